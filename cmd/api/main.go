@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"log"
 	"net/http"
@@ -9,14 +10,24 @@ import (
 	"os/signal"
 	"postgresDB/config"
 	"postgresDB/internal/delivery/handler"
+	"postgresDB/internal/delivery/middleware"
 	"postgresDB/internal/delivery/routers"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/infrastruktur/alert"
+	"postgresDB/internal/infrastruktur/broker"
 	"postgresDB/internal/infrastruktur/cache"
 	"postgresDB/internal/infrastruktur/database"
+	"postgresDB/internal/infrastruktur/mail"
+	"postgresDB/internal/infrastruktur/payment"
+	"postgresDB/internal/infrastruktur/storage"
+	"postgresDB/internal/notification"
 	"postgresDB/internal/repository/postgres"
 	"postgresDB/internal/repository/redis"
 	"postgresDB/internal/service"
 	"postgresDB/pkg/jwt"
 	"postgresDB/pkg/logger"
+	"postgresDB/pkg/metrics"
+	"postgresDB/pkg/utils"
 	"syscall"
 	"time"
 
@@ -47,6 +58,13 @@ func main() {
 	defer dbPool.Close()
 	log.Println("koneksi ke database berhasil")
 
+	// read replicas, reads of GET requests fall back to the primary without them
+	replicaPools := database.NewReplicaConnections(ctx, cfg.DB)
+	for _, pool := range replicaPools {
+		defer pool.Close()
+	}
+	replicas := postgres.NewReplicas(replicaPools...)
+
 	// initial Redis
 	redisClient, err := cache.NewRedisClient(ctx, cfg.Redis)
 	if err != nil {
@@ -55,37 +73,485 @@ func main() {
 	defer redisClient.Close()
 	log.Println("koneksi ke Redis berhasil")
 
+	// background workers stop when main returns
+	backgroundCtx, stopBackground := context.WithCancel(ctx)
+	defer stopBackground()
+
+	// warn when the database pool runs out of connections
+	go database.MonitorPool(backgroundCtx, dbPool, cfg.DB.PoolCheckInterval)
+
+	// monitor Redis health in the background
+	redisMonitor := cache.NewRedisHealthMonitor(redisClient, cfg.Redis.HealthCheckInterval, cfg.Redis.MaxReconnectBackoff)
+	go redisMonitor.Start(backgroundCtx)
+
 	// initial repository
-	userRepo := postgres.NewUserRepository(dbPool)
-	productRepo := postgres.NewProductRepository(dbPool)
-	orderRepo := postgres.NewOrderRepository(dbPool)
+	userRepo := postgres.NewUserRepository(dbPool, replicas)
+	var productRepo repository.ProductRepository = postgres.NewProductRepository(dbPool, replicas)
+	if cfg.Redis.ProductCacheTTL > 0 {
+		// every service shares the cached repository so all product writes invalidate it
+		productRepo = redis.NewProductCache(productRepo, redisClient, cfg.Redis.ProductCacheTTL, cfg.Redis.ProductCountCacheTTL)
+	}
+	var reservationRepo repository.StockReservationRepository = postgres.NewStockReservationRepository(dbPool)
+	if cfg.Redis.ProductCacheTTL > 0 {
+		reservationRepo = redis.NewReservationCache(reservationRepo, redisClient)
+	}
+	// stock writes are published for the live inventory stream
+	eventBus := redis.NewEventBus(redisClient)
+	productRepo = redis.NewProductStockEvents(productRepo, eventBus)
+	reservationRepo = redis.NewReservationStockEvents(reservationRepo, eventBus)
+	categoryRepo := postgres.NewCategoryRepository(dbPool)
+	productImageRepo := postgres.NewProductImageRepository(dbPool)
+	attributeRepo := postgres.NewAttributeRepository(dbPool)
+	tagRepo := postgres.NewTagRepository(dbPool)
+	var orderRepo repository.OrderRepository = postgres.NewOrderRepository(dbPool, replicas)
+	if cfg.Redis.OrderStatsCacheTTL > 0 {
+		orderRepo = redis.NewOrderStatsCache(orderRepo, redisClient, cfg.Redis.OrderStatsCacheTTL)
+	}
+	snapshotRepo := postgres.NewCatalogSnapshotRepository(dbPool)
+	trackingRepo := postgres.NewTrackingTokenRepository(dbPool)
+	shipmentRepo := postgres.NewShipmentRepository(dbPool)
+	captureRepo := postgres.NewPaymentCaptureRepository(dbPool)
+	paymentRepo := postgres.NewPaymentRepository(dbPool)
+	refundRepo := postgres.NewRefundRepository(dbPool)
+	addressRepo := postgres.NewAddressRepository(dbPool)
+	consentRepo := postgres.NewConsentRepository(dbPool)
+	notificationPreferenceRepo := postgres.NewNotificationPreferenceRepository(dbPool)
+	webhookRepo := postgres.NewWebhookRepository(dbPool)
+	outboxRepo := postgres.NewOutboxRepository(dbPool)
+	reconRepo := postgres.NewReconciliationRepository(dbPool)
+	emailTemplateRepo := postgres.NewEmailTemplateRepository(dbPool)
+	accessLogRepo := postgres.NewAccessLogRepository(dbPool)
+	apiClientRepo := postgres.NewAPIClientRepository(dbPool)
+	stockSubscriptionRepo := postgres.NewStockSubscriptionRepository(dbPool)
+	warehouseRepo := postgres.NewWarehouseRepository(dbPool)
+	stockTransferRepo := postgres.NewStockTransferRepository(dbPool)
+	stockCountRepo := postgres.NewStockCountRepository(dbPool)
+	authStateRepo := postgres.NewAuthStateBackupRepository(dbPool)
+	fieldUsageRepo := postgres.NewFieldUsageRepository(dbPool)
+	txManager := postgres.NewTxManager(dbPool)
 	tokenRepo := redis.NewTokenRepository(redisClient)
+	authCodeRepo := redis.NewAuthorizationCodeRepository(redisClient)
+	rateLimitRepo := redis.NewRateLimitRepository(redisClient)
+	nonceRepo := redis.NewNonceRepository(redisClient)
+	idempotencyRepo := redis.NewIdempotencyRepository(redisClient)
+	responseCacheRepo := redis.NewResponseCacheRepository(redisClient)
+	jobRepo := redis.NewJobRepository(redisClient, cfg.Jobs.Retention)
+	lockRepo := redis.NewLockRepository(redisClient)
 
 	// initial JWT service with token repository
 	jwtService, err := jwt.NewService(&cfg.JWT, tokenRepo)
 	if err != nil {
 		log.Fatalf("Failed to initialize jwt service: %v", err)
 	}
+	jwtService.SetTokenStoreHealth(redisMonitor.Available)
 	log.Println("JWT service initialized")
 
+	// initial storage for uploaded files
+	fileStorage, err := storage.NewLocalStorage(cfg.Storage.LocalDir, cfg.Storage.BaseURL)
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+	// access log exports are only downloaded through the API, never served as static files
+	exportStorage, err := storage.NewLocalStorage(cfg.Logs.ExportDir, "")
+	if err != nil {
+		log.Fatalf("Failed to initialize export storage: %v", err)
+	}
+	// backups go to a private local dir or an S3-compatible bucket
+	var backupStorage storage.Storage
+	if cfg.Backup.Storage == "s3" {
+		backupStorage, err = storage.NewS3Storage(storage.S3Config{
+			Endpoint:        cfg.Backup.S3.Endpoint,
+			Region:          cfg.Backup.S3.Region,
+			Bucket:          cfg.Backup.S3.Bucket,
+			AccessKeyID:     cfg.Backup.S3.AccessKeyID,
+			SecretAccessKey: cfg.Backup.S3.SecretAccessKey,
+			PathStyle:       cfg.Backup.S3.PathStyle,
+		})
+	} else {
+		backupStorage, err = storage.NewLocalStorage(cfg.Backup.LocalDir, "")
+	}
+	if err != nil {
+		log.Fatalf("Failed to initialize backup storage: %v", err)
+	}
+
+	// initial mailer, email stays disabled without an SMTP host
+	var mailer mail.Mailer
+	if cfg.Mail.SMTPHost != "" {
+		mailer, err = mail.NewSMTPMailer(mail.SMTPConfig{
+			Host:     cfg.Mail.SMTPHost,
+			Port:     cfg.Mail.SMTPPort,
+			Username: cfg.Mail.SMTPUsername,
+			Password: cfg.Mail.SMTPPassword,
+			From:     cfg.Mail.From,
+		})
+		if err != nil {
+			log.Fatalf("Failed to initialize mailer: %v", err)
+		}
+	}
+
+	// initial password hasher
+	passwordHasher, err := utils.NewPasswordHasher(utils.HasherConfig{
+		Algorithm:         cfg.Password.Algorithm,
+		BcryptCost:        cfg.Password.BcryptCost,
+		Argon2Memory:      uint32(cfg.Password.Argon2Memory),
+		Argon2Time:        uint32(cfg.Password.Argon2Time),
+		Argon2Parallelism: uint8(cfg.Password.Argon2Parallelism),
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize password hasher: %v", err)
+	}
+
 	// initialize service
-	authService := service.NewAuthService(userRepo, jwtService)
-	userService := service.NewUserService(userRepo)
-	productService := service.NewProductService(productRepo)
-	orderService := service.NewOrderService(orderRepo, productRepo)
+	jobService := service.NewJobService(jobRepo, service.JobWorkerConfig{
+		Queues:       cfg.Jobs.Queues,
+		Workers:      cfg.Jobs.Workers,
+		PollInterval: cfg.Jobs.PollInterval,
+		MaxAttempts:  cfg.Jobs.MaxAttempts,
+		BaseBackoff:  cfg.Jobs.BaseBackoff,
+		MaxBackoff:   cfg.Jobs.MaxBackoff,
+		Timeout:      cfg.Jobs.Timeout,
+		StaleAfter:   cfg.Jobs.StaleAfter,
+	})
+	// restocks enqueue back-in-stock notifications, every service below shares this repository
+	productRepo = service.NewBackInStockHook(productRepo, stockSubscriptionRepo, jobService)
+	authService := service.NewAuthService(userRepo, jwtService, passwordHasher)
+	userService := service.NewUserService(userRepo, passwordHasher)
+	productService := service.NewProductService(productRepo, categoryRepo, productImageRepo, attributeRepo, tagRepo, reservationRepo, responseCacheRepo, fileStorage)
+	productImageService := service.NewProductImageService(productRepo, productImageRepo, fileStorage, responseCacheRepo, cfg.Storage.MaxUploadSize)
+	categoryService := service.NewCategoryService(categoryRepo, responseCacheRepo)
+	attributeService := service.NewAttributeService(attributeRepo, categoryRepo, responseCacheRepo)
+	tagService := service.NewTagService(tagRepo, responseCacheRepo)
+	shippingService, err := service.NewShippingService(cfg.Shipping.Methods)
+	if err != nil {
+		log.Fatalf("Failed to configure shipping methods: %v", err)
+	}
+	taxService, err := service.NewTaxService(cfg.Tax.Rates)
+	if err != nil {
+		log.Fatalf("Failed to configure tax rates: %v", err)
+	}
+	addressService := service.NewAddressService(addressRepo)
+	// order webhooks for external systems, delivered from the job queue
+	webhookService := service.NewWebhookService(webhookRepo, jobService)
+	jobService.Register(service.JobTypeWebhook, webhookService.Deliver)
+	emailTemplateService := service.NewEmailTemplateService(emailTemplateRepo, mailer, cfg.Mail.DefaultLocale)
+	// customer notifications, sent from the job queue on every configured channel
+	var notificationChannels []notification.Channel
+	if mailer != nil {
+		notificationChannels = append(notificationChannels, notification.NewEmailChannel(mailer))
+	}
+	if cfg.Notify.WebhookURL != "" {
+		notificationChannels = append(notificationChannels, notification.NewWebhookChannel(cfg.Notify.WebhookURL, cfg.Notify.WebhookSecret))
+	}
+	notificationService := service.NewNotificationService(notificationPreferenceRepo, userRepo, emailTemplateService, jobService, notificationChannels...)
+	jobService.Register(service.JobTypeNotification, notificationService.Deliver)
+	// order confirmations and status updates to customers
+	orderNotifier, err := service.NewOrderNotifier(notificationService, productRepo, cfg.Orders.StatusNotifications)
+	if err != nil {
+		log.Fatalf("Failed to configure order status notifications: %v", err)
+	}
+	// order lifecycle hooks of this deployment, e.g. custom validation or ERP updates;
+	// each implements any of the order hook interfaces of the domain services
+	orderHooks, err := service.NewOrderHooks(webhookService, orderNotifier)
+	if err != nil {
+		log.Fatalf("Failed to register order hooks: %v", err)
+	}
+	if cfg.Orders.MaxItems <= 0 || cfg.Orders.MaxItemQuantity <= 0 {
+		log.Fatalf("ORDER_MAX_ITEMS and ORDER_MAX_ITEM_QUANTITY must be positive")
+	}
+	orderService := service.NewOrderService(orderRepo, productRepo, userRepo, reservationRepo, refundRepo, addressRepo, shipmentRepo, txManager, jobService, shippingService, taxService, orderHooks, service.ReservationConfig{
+		TTL: cfg.Orders.ReservationTTL,
+	}, service.OrderLimits{
+		MaxItems:        cfg.Orders.MaxItems,
+		MaxItemQuantity: cfg.Orders.MaxItemQuantity,
+	})
+
+	// periodic maintenance, each task runs on one instance at a time
+	scheduler := service.NewScheduler(lockRepo)
+	scheduler.Register("reservation-sweep", cfg.Orders.ReservationSweepInterval, orderService.SweepExpiredReservations)
+	scheduler.Register("related-products-refresh", cfg.Products.RelatedRefreshInterval, productService.RefreshRelated)
+	scheduler.Register("session-cleanup", cfg.JWT.SessionCleanupInterval, authService.PruneSessions)
+	if cfg.Products.ArchiveRetention > 0 {
+		scheduler.Register("archived-products-purge", cfg.Products.ArchivePurgeInterval, func(ctx context.Context) error {
+			return productService.PurgeArchived(ctx, cfg.Products.ArchiveRetention)
+		})
+	}
+	go scheduler.Run(backgroundCtx)
+
+	// low-stock alerts are sent from the job queue
+	lowStockNotifier := service.NewLowStockNotifier(productRepo, userRepo, emailTemplateService, mailer, cfg.Alerts.LowStockWebhookURL)
+	jobService.Register(service.JobTypeLowStockAlert, lowStockNotifier.Handle)
+	// consents gate marketing notifications and analytics
+	consentService := service.NewConsentService(consentRepo, userRepo)
+	// back-in-stock notifications to subscribed users
+	stockSubscriptionService := service.NewStockSubscriptionService(stockSubscriptionRepo, productRepo, consentService)
+	backInStockNotifier := service.NewBackInStockNotifier(productRepo, stockSubscriptionRepo, emailTemplateService, consentService, mailer)
+	jobService.Register(service.JobTypeBackInStock, backInStockNotifier.Handle)
+
+	// public order tracking links
+	trackingSecret := []byte(cfg.Tracking.Secret)
+	if len(trackingSecret) == 0 {
+		log.Println("TRACKING_TOKEN_SECRET kosong, memakai secret acak, link tracking tidak berlaku setelah restart")
+		trackingSecret = make([]byte, 32)
+		if _, err := rand.Read(trackingSecret); err != nil {
+			log.Fatalf("Failed to generate tracking secret: %v", err)
+		}
+	}
+	trackingService := service.NewOrderTrackingService(orderRepo, trackingRepo, userRepo, emailTemplateService, mailer, trackingSecret, cfg.Tracking.BaseURL)
+	// guests get the tracking link of their order by email
+	jobService.Register(service.JobTypeGuestOrderLink, trackingService.SendGuestLink)
+
+	// checkout payments at the payment provider, refused while none is configured
+	paymentProvider, err := payment.NewProvider(cfg.Payment.Provider, payment.StripeConfig{
+		APIURL:        cfg.Payment.StripeAPIURL,
+		SecretKey:     cfg.Payment.StripeSecretKey,
+		WebhookSecret: cfg.Payment.StripeWebhookSecret,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize payment provider: %v", err)
+	}
+	paymentService := service.NewPaymentService(paymentRepo, orderRepo, paymentProvider, orderHooks, cfg.Payment.Currency)
+	refundService := service.NewRefundService(refundRepo, orderRepo, paymentRepo, paymentProvider, orderHooks)
+	orderHistoryService := service.NewOrderHistoryService(orderRepo, paymentRepo, refundRepo)
+
+	// payment capture retries at the payment provider; without a provider that can capture,
+	// captures are refused and the retries don't run
+	captureService := service.NewPaymentCaptureService(captureRepo, orderRepo, payment.Gateway(paymentProvider), orderHooks, service.CaptureRetryConfig{
+		Interval:       cfg.Payment.CaptureRetryInterval,
+		BaseBackoff:    cfg.Payment.CaptureBaseBackoff,
+		MaxBackoff:     cfg.Payment.CaptureMaxBackoff,
+		MaxAttempts:    cfg.Payment.CaptureMaxAttempts,
+		BatchSize:      cfg.Payment.CaptureBatchSize,
+		AttemptTimeout: cfg.Payment.CaptureTimeout,
+	})
+	go captureService.RunRetries(backgroundCtx)
+
+	// payment reconciliation; without a provider API settlement reports are uploaded by
+	// admins and the daily schedule stays idle until a SettlementSource is passed in here
+	reconAt, err := time.Parse("15:04", cfg.Recon.DailyAt)
+	if err != nil {
+		log.Fatalf("RECONCILIATION_TIME tidak valid: %v", err)
+	}
+	reconciliationService := service.NewReconciliationService(reconRepo, captureRepo, nil,
+		time.Duration(reconAt.Hour())*time.Hour+time.Duration(reconAt.Minute())*time.Minute)
+	go reconciliationService.RunSchedule(backgroundCtx)
+
+	// nightly catalog snapshots
+	snapshotAt, err := time.Parse("15:04", cfg.Snapshot.DailyAt)
+	if err != nil {
+		log.Fatalf("CATALOG_SNAPSHOT_TIME tidak valid: %v", err)
+	}
+	snapshotService := service.NewCatalogSnapshotService(snapshotRepo, productRepo,
+		time.Duration(snapshotAt.Hour())*time.Hour+time.Duration(snapshotAt.Minute())*time.Minute,
+		cfg.Snapshot.Retention)
+	go snapshotService.RunSchedule(backgroundCtx)
+
+	// operational alerts are logged and delivered to the configured sinks from the job queue
+	var alertSinks []alert.Sink
+	if cfg.Alerts.WebhookURL != "" {
+		alertSinks = append(alertSinks, alert.NewWebhookSink(cfg.Alerts.WebhookURL))
+	}
+	alerter := service.NewAlerter(jobService, alertSinks...)
+	jobService.Register(service.JobTypeAlert, alerter.Handle)
+
+	// checkout failure spike detection
+	anomaly := cfg.Alerts.CheckoutAnomaly
+	checkoutDetector := service.NewCheckoutAnomalyDetector(service.CheckoutAnomalyConfig{
+		Interval:       anomaly.Interval,
+		Window:         anomaly.Window,
+		Baseline:       anomaly.Baseline,
+		Sensitivity:    anomaly.Sensitivity,
+		MinAttempts:    anomaly.MinAttempts,
+		MinFailureRate: anomaly.MinFailureRate,
+		Cooldown:       anomaly.Cooldown,
+	}, alerter)
+	go checkoutDetector.Run(backgroundCtx)
+
+	// service level objectives, their error budgets are burned from this instance's metrics
+	if cfg.SLO.CheckoutSuccess >= 100 || cfg.SLO.Latency >= 100 {
+		log.Fatalf("SLO_CHECKOUT_SUCCESS dan SLO_LATENCY harus di bawah 100, objective 100%% tidak punya error budget")
+	}
+	var slos []service.SLO
+	if cfg.SLO.CheckoutSuccess > 0 {
+		slos = append(slos, service.SLO{
+			Name:        "checkout_success",
+			Description: fmt.Sprintf("%g%% of checkout attempts succeed", cfg.SLO.CheckoutSuccess),
+			Objective:   cfg.SLO.CheckoutSuccess / 100,
+			SLI:         service.CheckoutSLI,
+		})
+	}
+	if cfg.SLO.Latency > 0 {
+		slos = append(slos, service.SLO{
+			Name:        "latency",
+			Description: fmt.Sprintf("%g%% of requests are served within %s", cfg.SLO.Latency, cfg.SLO.LatencyThreshold),
+			Objective:   cfg.SLO.Latency / 100,
+			SLI:         middleware.LatencySLI(cfg.SLO.LatencyThreshold),
+		})
+	}
+	sloService := service.NewSLOService(service.SLOConfig{
+		Interval:       cfg.SLO.Interval,
+		Period:         cfg.SLO.Period,
+		FastBurnRate:   cfg.SLO.FastBurnRate,
+		FastBurnWindow: cfg.SLO.FastBurnWindow,
+		SlowBurnRate:   cfg.SLO.SlowBurnRate,
+		SlowBurnWindow: cfg.SLO.SlowBurnWindow,
+		MinEvents:      cfg.SLO.MinEvents,
+	}, slos, alerter)
+	go sloService.Run(backgroundCtx)
+
+	// outbox events, saved with the order and user changes, published to the message broker
+	eventPublisher, err := broker.NewPublisher(cfg.Outbox.Broker, broker.Config{
+		Prefix:            cfg.Outbox.Prefix,
+		NATS:              broker.NATSConfig{URL: cfg.Outbox.NATSURL, JetStream: cfg.Outbox.NATSJetStream},
+		RedisStreamMaxLen: cfg.Outbox.RedisStreamMaxLen,
+	}, redisClient)
+	if err != nil {
+		log.Fatalf("Failed to initialize outbox broker: %v", err)
+	}
+	defer eventPublisher.Close()
+	if cfg.Outbox.PollInterval <= 0 || cfg.Outbox.BatchSize <= 0 {
+		log.Fatalf("OUTBOX_POLL_INTERVAL and OUTBOX_BATCH_SIZE must be positive")
+	}
+	outboxRelay := service.NewOutboxRelay(outboxRepo, txManager, eventPublisher, service.OutboxConfig{
+		PollInterval:   cfg.Outbox.PollInterval,
+		BatchSize:      cfg.Outbox.BatchSize,
+		PublishTimeout: cfg.Outbox.PublishTimeout,
+		BaseBackoff:    cfg.Outbox.BaseBackoff,
+		MaxBackoff:     cfg.Outbox.MaxBackoff,
+		Retention:      cfg.Outbox.Retention,
+	})
+	go outboxRelay.Run(backgroundCtx)
+
+	// backups of the Redis sessions and token blacklist
+	authStateService := service.NewAuthStateBackupService(tokenRepo, authStateRepo, backupStorage, alerter, service.AuthStateBackupConfig{
+		Interval:  cfg.Backup.AuthStateInterval,
+		Retention: cfg.Backup.AuthStateRetention,
+	})
+	go authStateService.RunSchedule(backgroundCtx)
+
+	// live inventory stream for warehouse dashboards
+	inventoryStreamService := service.NewInventoryStreamService(eventBus, productRepo)
+	go inventoryStreamService.Run(backgroundCtx)
+
+	// per-client access logs and their exports
+	accessLogService := service.NewAccessLogService(accessLogRepo, jobService, exportStorage, service.AccessLogConfig{
+		BufferSize:      cfg.Logs.BufferSize,
+		BatchSize:       cfg.Logs.BatchSize,
+		FlushInterval:   cfg.Logs.FlushInterval,
+		Retention:       cfg.Logs.Retention,
+		ExportRetention: cfg.Logs.ExportRetention,
+	})
+	jobService.Register(service.JobTypeAccessLogExport, accessLogService.HandleExport)
+	go accessLogService.Run(backgroundCtx)
+	// sampled field usage per client, to tell when a legacy field can be removed
+	fieldUsageService := service.NewFieldUsageService(fieldUsageRepo, cfg.Fields.FlushInterval)
+	go fieldUsageService.Run(backgroundCtx)
+
+	// self-service API clients, their usage is read from the access logs
+	apiClientService := service.NewAPIClientService(apiClientRepo, accessLogRepo)
+	// OAuth / OpenID Connect provider for the registered API clients
+	oauthService := service.NewOAuthService(apiClientRepo, userRepo, authCodeRepo, jwtService, service.OAuthConfig{
+		IssuerURL:        cfg.OAuth.IssuerURL,
+		AuthorizationURL: cfg.OAuth.AuthorizationURL,
+		CodeTTL:          cfg.OAuth.CodeTTL,
+	})
+
+	// warehouse stock, transfers between warehouses and stock counts, posted through the stock ledger
+	warehouseService := service.NewWarehouseService(warehouseRepo, productRepo)
+	stockTransferService := service.NewStockTransferService(stockTransferRepo, warehouseRepo, productRepo)
+	stockCountService := service.NewStockCountService(stockCountRepo, warehouseRepo, categoryRepo, productRepo)
+
+	// background job workers, every job handler must be registered above this line
+	jobsDone := make(chan struct{})
+	go func() {
+		defer close(jobsDone)
+		jobService.Run(backgroundCtx)
+	}()
 
 	// initialize handler
 	authHandler := handler.NewAuthHandler(authService, cfg.JWT.RefreshTokenTTL)
 	userHandler := handler.NewUserHandler(userService)
 	productHandler := handler.NewProductHandler(productService)
-	orderHandler := handler.NewOrderHandler(orderService)
+	productV2Handler := handler.NewProductV2Handler(productService, cfg.Payment.Currency)
+	categoryHandler := handler.NewCategoryHandler(categoryService)
+	attributeHandler := handler.NewAttributeHandler(attributeService)
+	tagHandler := handler.NewTagHandler(tagService)
+	productImageHandler := handler.NewProductImageHandler(productImageService, cfg.Storage.MaxUploadSize)
+	orderHandler := handler.NewOrderHandler(orderService, paymentService)
+	snapshotHandler := handler.NewCatalogSnapshotHandler(snapshotService)
+	trackingHandler := handler.NewOrderTrackingHandler(trackingService)
+	captureHandler := handler.NewPaymentCaptureHandler(captureService)
+	reconHandler := handler.NewReconciliationHandler(reconciliationService)
+	emailHandler := handler.NewEmailTemplateHandler(emailTemplateService)
+	jobHandler := handler.NewJobHandler(jobService)
+	inventoryHandler := handler.NewInventoryHandler(inventoryStreamService)
+	metricsHandler := handler.NewMetricsHandler(metrics.Default, cfg.Metrics.Token)
+	redisPinger := handler.PingFunc(func(ctx context.Context) error { return redisClient.Ping(ctx).Err() })
+	healthHandler := handler.NewHealthHandler(dbPool, redisPinger, cfg.JWT.RedisFailurePolicy, cfg.Server.HealthCheckTimeout)
+	accessLogHandler := handler.NewAccessLogHandler(accessLogService)
+	authStateHandler := handler.NewAuthStateHandler(authStateService)
+	apiClientHandler := handler.NewAPIClientHandler(apiClientService)
+	oauthHandler := handler.NewOAuthHandler(oauthService)
+	stockSubscriptionHandler := handler.NewStockSubscriptionHandler(stockSubscriptionService)
+	warehouseHandler := handler.NewWarehouseHandler(warehouseService)
+	stockTransferHandler := handler.NewStockTransferHandler(stockTransferService)
+	stockCountHandler := handler.NewStockCountHandler(stockCountService)
+	fieldUsageHandler := handler.NewFieldUsageHandler(fieldUsageService)
+	paymentHandler := handler.NewPaymentHandler(paymentService)
+	dashboardHandler := handler.NewDashboardHandler(orderService, jobService, metrics.Default, dbPool, redisMonitor)
+	refundHandler := handler.NewRefundHandler(refundService)
+	orderHistoryHandler := handler.NewOrderHistoryHandler(orderHistoryService)
+	shippingHandler := handler.NewShippingHandler(addressService, shippingService)
+	consentHandler := handler.NewConsentHandler(consentService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	sloHandler := handler.NewSLOHandler(sloService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	outboxHandler := handler.NewOutboxHandler(outboxRelay)
 
 	// initialize router
 	r := routers.NewRouter(
 		authHandler,
 		userHandler,
 		productHandler,
+		productV2Handler,
+		categoryHandler,
+		attributeHandler,
+		tagHandler,
+		productImageHandler,
 		orderHandler,
+		snapshotHandler,
+		trackingHandler,
+		captureHandler,
+		reconHandler,
+		emailHandler,
+		jobHandler,
+		inventoryHandler,
+		metricsHandler,
+		healthHandler,
+		accessLogHandler,
+		authStateHandler,
+		apiClientHandler,
+		oauthHandler,
+		stockSubscriptionHandler,
+		warehouseHandler,
+		stockTransferHandler,
+		stockCountHandler,
+		fieldUsageHandler,
+		paymentHandler,
+		dashboardHandler,
+		refundHandler,
+		orderHistoryHandler,
+		shippingHandler,
+		consentHandler,
+		notificationHandler,
+		sloHandler,
+		webhookHandler,
+		outboxHandler,
+		accessLogService,
+		fieldUsageService,
+		rateLimitRepo,
+		nonceRepo,
+		idempotencyRepo,
+		responseCacheRepo,
 		jwtService,
 		cfg,
 	)
@@ -110,6 +576,10 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
+	// fail readiness first and keep serving while load balancers take the instance out
+	healthHandler.Drain()
+	log.Printf("Draining for %s before shutting down...", cfg.Server.ShutdownDrainDelay)
+	time.Sleep(cfg.Server.ShutdownDrainDelay)
 	log.Println("Shutting down server...")
 
 	shutdownCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
@@ -119,5 +589,14 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	// stop taking jobs and let the running ones finish, a job cut short is queued again
+	// after JOB_STALE_AFTER
+	stopBackground()
+	select {
+	case <-jobsDone:
+	case <-time.After(cfg.Jobs.Timeout):
+		log.Println("Background jobs still running after JOB_TIMEOUT, exiting")
+	}
+
 	log.Println("Server exited gracefully")
 }
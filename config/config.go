@@ -3,14 +3,43 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
-	Server ServerConfig
-	DB     DBConfig
-	JWT    JWTConfig
-	Redis  RedisConfig
+	Server      ServerConfig
+	DB          DBConfig
+	JWT         JWTConfig
+	Redis       RedisConfig
+	Storage     StorageConfig
+	Password    PasswordConfig
+	Snapshot    SnapshotConfig
+	Tracking    TrackingConfig
+	Payment     PaymentConfig
+	Recon       ReconciliationConfig
+	Mail        MailConfig
+	Jobs        JobConfig
+	Alerts      AlertConfig
+	Notify      NotificationConfig
+	Metrics     MetricsConfig
+	SLO         SLOConfig
+	Outbox      OutboxConfig
+	Orders      OrderConfig
+	Shipping    ShippingConfig
+	Tax         TaxConfig
+	Products    ProductConfig
+	Backup      BackupConfig
+	Logs        AccessLogConfig
+	OAuth       OAuthConfig
+	Replay      ReplayConfig
+	Fields      FieldUsageConfig
+	Idempotency IdempotencyConfig
+	LoadShed    LoadShedConfig
+	RateLimit   RateLimitConfig
+	CORS        CORSConfig
+	Network     NetworkConfig
+	API         APIConfig
 }
 
 type ServerConfig struct {
@@ -18,6 +47,26 @@ type ServerConfig struct {
 	Host         string
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+	// RequestTimeout bounds the context of every request, 0 disables it. Keep it below
+	// WriteTimeout so clients get the timeout error
+	RequestTimeout time.Duration
+	// TimeoutRoutes overrides the request timeout of route patterns, "<pattern>=<duration>"
+	// with 0 for none
+	TimeoutRoutes []string
+	// MaxBodySize is the most bytes of a request body, multipart uploads excluded; 0 for no limit
+	MaxBodySize int64
+	// CompressionLevel is the gzip/deflate level of list responses, 1 to 9; 0 disables
+	// compression
+	CompressionLevel int
+	// CompressionMinSize is the smallest response body in bytes that is compressed
+	CompressionMinSize int
+	// DisabledMiddleware names global middlewares left out of the stack, e.g. access_log
+	DisabledMiddleware []string
+	// HealthCheckTimeout bounds the ping of each dependency by the readiness check
+	HealthCheckTimeout time.Duration
+	// ShutdownDrainDelay is how long the server keeps serving with readiness failing
+	// before it shuts down, so load balancers stop sending traffic first
+	ShutdownDrainDelay time.Duration
 }
 
 type DBConfig struct {
@@ -28,6 +77,20 @@ type DBConfig struct {
 	User          string
 	Password      string
 	SSLMode       string
+	// StatementTimeout bounds every statement on the server, 0 disables it. Queries of a
+	// request are also cancelled with its context
+	StatementTimeout time.Duration
+	// ReplicaURLs are the connection URLs of read replicas, reads of GET requests are
+	// spread over them. Without any everything goes to the primary
+	ReplicaURLs []string
+	// connection pool of the primary and of each replica
+	MaxConns          int
+	MinConns          int
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+	// PoolCheckInterval is how often the primary pool is checked for exhaustion
+	PoolCheckInterval time.Duration
 }
 
 type JWTConfig struct {
@@ -37,22 +100,366 @@ type JWTConfig struct {
 	RefreshTokenTTL time.Duration
 	Issuer          string
 	Audience        string
+	// RedisFailurePolicy decides how access tokens are validated while Redis is down:
+	// "fail_closed" rejects requests, "fail_open" skips the blacklist check
+	RedisFailurePolicy string
+	// MaxSessions is the number of login sessions a user may have at once, 0 for no limit
+	MaxSessions int
+	// SessionLimitPolicy decides what a login beyond MaxSessions does: "reject" refuses
+	// it, "evict_oldest" ends the oldest session
+	SessionLimitPolicy string
+	// SessionCleanupInterval is how often expired sessions are dropped from Redis, 0 disables it
+	SessionCleanupInterval time.Duration
 }
 
 type RedisConfig struct {
-	Host     string
-	Port     string
-	Password string
-	DB       int
+	Host                string
+	Port                string
+	Password            string
+	DB                  int
+	HealthCheckInterval time.Duration
+	MaxReconnectBackoff time.Duration
+	// ProductCacheTTL is how long catalog reads are cached, 0 disables the cache
+	ProductCacheTTL time.Duration
+	// ProductCountCacheTTL is how long the estimated product totals served with
+	// exact_count=false are cached, product writes don't reset them
+	ProductCountCacheTTL time.Duration
+	// ResponseCacheTTL is how long the rendered responses of public catalog routes are
+	// cached, writes through the product service reset them; 0 disables the cache
+	ResponseCacheTTL time.Duration
+	// OrderStatsCacheTTL is how long customer order statistics are cached, order changes
+	// show up once it runs out; 0 disables the cache
+	OrderStatsCacheTTL time.Duration
+}
+
+type StorageConfig struct {
+	LocalDir      string
+	BaseURL       string
+	MaxUploadSize int64
+}
+
+type PasswordConfig struct {
+	Algorithm         string
+	BcryptCost        int
+	Argon2Memory      int
+	Argon2Time        int
+	Argon2Parallelism int
+}
+
+type SnapshotConfig struct {
+	// DailyAt is the local time of the nightly catalog snapshot, formatted as HH:MM
+	DailyAt   string
+	Retention time.Duration
+}
+
+type TrackingConfig struct {
+	// Secret signs public tracking links, a random secret is used when empty
+	Secret     string
+	BaseURL    string
+	RateLimit  int
+	RateWindow time.Duration
+}
+
+type PaymentConfig struct {
+	// CaptureRetryInterval is how often due payment captures are retried
+	CaptureRetryInterval time.Duration
+	CaptureBaseBackoff   time.Duration
+	CaptureMaxBackoff    time.Duration
+	CaptureMaxAttempts   int
+	CaptureBatchSize     int
+	CaptureTimeout       time.Duration
+	// Provider selects the payment provider of checkout payments, only stripe is
+	// supported; empty disables payment intents and webhooks
+	Provider            string
+	StripeSecretKey     string
+	StripeWebhookSecret string
+	StripeAPIURL        string
+	// Currency is the ISO currency code payments are created in
+	Currency string
+}
+
+type MailConfig struct {
+	// SMTPHost is left empty to disable sending email
+	SMTPHost      string
+	SMTPPort      int
+	SMTPUsername  string
+	SMTPPassword  string
+	From          string
+	DefaultLocale string
+}
+
+// NotificationConfig configures the channels customer notifications are sent on; email is
+// one whenever an SMTP host is set
+type NotificationConfig struct {
+	// WebhookURL receives every notification as JSON, e.g. for an SMS gateway; empty disables it
+	WebhookURL string
+	// WebhookSecret signs the webhook body, empty leaves it unsigned
+	WebhookSecret string
+}
+
+type AlertConfig struct {
+	// LowStockWebhookURL receives low-stock alerts as JSON, empty disables the webhook
+	LowStockWebhookURL string
+	// WebhookURL receives operational alerts like checkout failure spikes, empty only logs them
+	WebhookURL string
+	// CheckoutAnomaly tunes the checkout failure rate detection
+	CheckoutAnomaly CheckoutAnomalyConfig
+}
+
+type CheckoutAnomalyConfig struct {
+	// Interval is how often the checkout failure rate is evaluated
+	Interval time.Duration
+	// Window is the recent period checked, Baseline the period before it compared with
+	Window   time.Duration
+	Baseline time.Duration
+	// Sensitivity is how many times the baseline failure rate raises an alert
+	Sensitivity    float64
+	MinAttempts    int
+	MinFailureRate float64
+	Cooldown       time.Duration
+}
+
+type BackupConfig struct {
+	// AuthStateInterval is how often the Redis sessions and token blacklist are backed up,
+	// 0 disables scheduled backups
+	AuthStateInterval  time.Duration
+	AuthStateRetention time.Duration
+	// Storage is "local" or "s3"
+	Storage  string
+	LocalDir string
+	S3       S3Config
+}
+
+type S3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PathStyle       bool
+}
+
+type ProductConfig struct {
+	// RelatedRefreshInterval is how often the co-purchase counts of related products are recomputed
+	RelatedRefreshInterval time.Duration
+	// ArchiveRetention is how long archived products no order refers to are kept before
+	// they are deleted for good, 0 keeps them forever
+	ArchiveRetention time.Duration
+	// ArchivePurgeInterval is how often archived products past the retention are deleted
+	ArchivePurgeInterval time.Duration
+}
+
+type OrderConfig struct {
+	// ReservationTTL is how long a pending order holds its stock before it's cancelled
+	ReservationTTL time.Duration
+	// ReservationSweepInterval is how often expired reservations are released
+	ReservationSweepInterval time.Duration
+	// MaxItems is the most distinct products in an order, MaxItemQuantity the most units
+	// of one product
+	MaxItems        int
+	MaxItemQuantity int
+	// StatusNotifications are the order statuses customers are notified about, none when empty
+	StatusNotifications []string
+	// GuestCheckout allows placing orders without an account, rate limited per client IP
+	GuestCheckout   bool
+	GuestRateLimit  int
+	GuestRateWindow time.Duration
+}
+
+type ShippingConfig struct {
+	// Methods are the comma separated shipping methods offered at checkout, in order of
+	// preference: "<code>=flat:<cost>" or "<code>=weight:<base cost>:<cost per kg>" with
+	// costs in units. Empty turns shipping off, orders then need no address
+	Methods []string
+}
+
+type TaxConfig struct {
+	// Rates are the comma separated tax rates, "<region>[/<category>]=<percent>" where
+	// region is the country code of the shipping address, * for any. The most specific
+	// rate applies to each item; empty leaves orders untaxed
+	Rates []string
+}
+
+type AccessLogConfig struct {
+	BufferSize    int
+	BatchSize     int
+	FlushInterval time.Duration
+	// Retention is how long access logs are kept, 0 keeps them forever
+	Retention time.Duration
+	// ExportDir holds export files; it must not be served publicly
+	ExportDir       string
+	ExportRetention time.Duration
+}
+
+type OAuthConfig struct {
+	// IssuerURL is the public base URL of the API, it is the issuer of ID tokens and
+	// prefixes the endpoints in the discovery document
+	IssuerURL string
+	// AuthorizationURL is the consent page of the frontend, which calls the authorize
+	// endpoint for the signed-in user. The API endpoint itself is used when empty
+	AuthorizationURL string
+	CodeTTL          time.Duration
+	// TokenRateLimit is the number of token requests allowed per IP in TokenRateWindow
+	TokenRateLimit  int
+	TokenRateWindow time.Duration
+}
+
+type ReplayConfig struct {
+	// Mode is "off", "optional" to check only signed requests, or "required" to reject
+	// unsigned requests to high-value mutations
+	Mode string
+	// Window is how far the request timestamp may be from the server time
+	Window time.Duration
+}
+
+type IdempotencyConfig struct {
+	// TTL is how long the response of a request with an Idempotency-Key is replayed
+	TTL time.Duration
+}
+
+type RateLimitConfig struct {
+	// PerMinute and Burst make the default token bucket of every client: Burst requests
+	// at once, refilled at PerMinute a minute. A PerMinute of 0 disables the rate limit
+	PerMinute int
+	Burst     int
+	// AuthPerMinute and AuthBurst are the stricter bucket of sign-in, registration and
+	// token routes, 0 leaves them on the default bucket
+	AuthPerMinute int
+	AuthBurst     int
+	// CatalogPerMinute and CatalogBurst are the looser bucket of catalog reads, 0 leaves
+	// them on the default bucket
+	CatalogPerMinute int
+	CatalogBurst     int
+	// Routes overrides the tier of route patterns, "<pattern>=<tier>" with tier exempt,
+	// default, auth or catalog
+	Routes []string
+}
+
+type APIConfig struct {
+	// DeprecatedVersions are the API versions being retired, "<version>=<sunset date>"
+	// such as v1=2027-06-30, the date is optional. Their responses carry Deprecation,
+	// Sunset and successor Link headers
+	DeprecatedVersions []string
+}
+
+type CORSConfig struct {
+	// AllowedOrigins are the browser origins allowed to call the API, "*" for any; none
+	// disables CORS
+	AllowedOrigins []string
+	// AllowCredentials lets browsers send cookies with cross-origin requests
+	AllowCredentials bool
+	// MaxAge is how long browsers cache the answer to a preflight request
+	MaxAge time.Duration
+}
+
+type NetworkConfig struct {
+	// TrustedProxies are the IPs and CIDR ranges of the proxies in front of the API, whose
+	// X-Forwarded-For is honored to find the client IP; none ignores the header
+	TrustedProxies []string
+	// DenyList are client IPs and CIDR ranges rejected on every route
+	DenyList []string
+	// AdminAllowList are the only client IPs and CIDR ranges let through to the admin
+	// routes, none allows any
+	AdminAllowList []string
+}
+
+type LoadShedConfig struct {
+	// MaxInFlight is the number of requests served at once before traffic is shed, 0
+	// disables load shedding. Checkout routes may use all of it
+	MaxInFlight int
+	// BrowseShare and ReportsShare are the fractions of MaxInFlight that browse and
+	// report routes may fill, the rest is kept for higher tiers
+	BrowseShare  float64
+	ReportsShare float64
+	// RetryAfter is sent to shed clients
+	RetryAfter time.Duration
+	// Routes overrides the tier of route patterns, "<pattern>=<tier>" with tier exempt,
+	// checkout, browse or reports
+	Routes []string
+}
+
+type FieldUsageConfig struct {
+	// SampleRate is the share of requests whose fields are counted, 0 turns it off
+	SampleRate    float64
+	FlushInterval time.Duration
+}
+
+type MetricsConfig struct {
+	// Token is the bearer token Prometheus scrapes /metrics with, empty leaves it open
+	Token string
+}
+
+type SLOConfig struct {
+	// CheckoutSuccess is the promised percentage of successful checkout attempts, 0 disables it
+	CheckoutSuccess float64
+	// Latency is the promised percentage of requests served within LatencyThreshold, 0
+	// disables it; 95 promises a p95 latency of LatencyThreshold
+	Latency          float64
+	LatencyThreshold time.Duration
+	// Interval is how often the objectives are evaluated, Period is their error budget period
+	Interval time.Duration
+	Period   time.Duration
+	// Burn rates alerted over their windows, critical for the fast and warning for the slow one
+	FastBurnRate   float64
+	FastBurnWindow time.Duration
+	SlowBurnRate   float64
+	SlowBurnWindow time.Duration
+	// MinEvents is the fewest events in a window for its burn rate to alert
+	MinEvents int
+}
+
+type OutboxConfig struct {
+	// Broker is where events are published: log, redis or nats
+	Broker string
+	// Prefix starts every subject and stream name
+	Prefix            string
+	NATSURL           string
+	NATSJetStream     bool
+	RedisStreamMaxLen int64
+	PollInterval      time.Duration
+	BatchSize         int
+	PublishTimeout    time.Duration
+	BaseBackoff       time.Duration
+	MaxBackoff        time.Duration
+	// Retention is how long published events are kept, 0 keeps them forever
+	Retention time.Duration
+}
+
+type JobConfig struct {
+	// Queues are the comma separated queues the workers take jobs from, in priority order
+	Queues       []string
+	Workers      int
+	PollInterval time.Duration
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	Timeout      time.Duration
+	StaleAfter   time.Duration
+	// Retention is how long finished jobs are kept for inspection
+	Retention time.Duration
+}
+
+type ReconciliationConfig struct {
+	// DailyAt is the local time of the daily settlement reconciliation, formatted as HH:MM
+	DailyAt string
 }
 
 func LoadConfig() (*Config, error) {
 	return &Config{
 		Server: ServerConfig{
-			Port:         getEnv("SERVER_PORT", "8080"),
-			Host:         getEnv("SERVER_HOST", "localhost"),
-			ReadTimeout:  getEnvAsDuration("SERVER_READ_TIMEOUT", 15*time.Second),
-			WriteTimeout: getEnvAsDuration("SERVER_WRITE_TIMEOUT", 15*time.Second),
+			Port:           getEnv("SERVER_PORT", "8080"),
+			Host:           getEnv("SERVER_HOST", "localhost"),
+			ReadTimeout:    getEnvAsDuration("SERVER_READ_TIMEOUT", 15*time.Second),
+			WriteTimeout:   getEnvAsDuration("SERVER_WRITE_TIMEOUT", 15*time.Second),
+			RequestTimeout: getEnvAsDuration("REQUEST_TIMEOUT", 10*time.Second),
+			TimeoutRoutes:  getEnvAsList("REQUEST_TIMEOUT_ROUTES", nil),
+			MaxBodySize:    int64(getEnvAsInt("REQUEST_MAX_BODY_SIZE", 1<<20)),
+			// Response compression of product and order lists
+			CompressionLevel:   getEnvAsInt("COMPRESSION_LEVEL", 5),
+			CompressionMinSize: getEnvAsInt("COMPRESSION_MIN_SIZE", 1024),
+			DisabledMiddleware: getEnvAsList("MIDDLEWARE_DISABLED", nil),
+			HealthCheckTimeout: getEnvAsDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+			ShutdownDrainDelay: getEnvAsDuration("SHUTDOWN_DRAIN_DELAY", 5*time.Second),
 		},
 		// Database configuration
 		DB: DBConfig{
@@ -62,22 +469,261 @@ func LoadConfig() (*Config, error) {
 			User:     getEnv("DB_USER", "postgres"),
 			Password: getEnv("DB_PASSWORD", "postgres"),
 			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+
+			StatementTimeout: getEnvAsDuration("DB_STATEMENT_TIMEOUT", 30*time.Second),
+			ReplicaURLs:      getEnvAsList("DB_REPLICA_URLS", nil),
+
+			MaxConns:          getEnvAsInt("DB_MAX_CONNS", 10),
+			MinConns:          getEnvAsInt("DB_MIN_CONNS", 2),
+			MaxConnLifetime:   getEnvAsDuration("DB_MAX_CONN_LIFETIME", time.Hour),
+			MaxConnIdleTime:   getEnvAsDuration("DB_MAX_CONN_IDLE_TIME", 30*time.Minute),
+			HealthCheckPeriod: getEnvAsDuration("DB_HEALTH_CHECK_PERIOD", time.Minute),
+			PoolCheckInterval: getEnvAsDuration("DB_POOL_CHECK_INTERVAL", 30*time.Second),
 		},
 		// JWT configuration
 		JWT: JWTConfig{
-			PrivateKeyPath:  getEnv("JWT_PRIVATE_KEY_PATH", "keys/private.pem"),
-			PublicKeyPath:   getEnv("JWT_PUBLIC_KEY_PATH", "keys/public.pem"),
-			AccessTokenTTL:  getEnvAsDuration("JWT_ACCESS_TOKEN_TTL", 15*time.Minute),
-			RefreshTokenTTL: getEnvAsDuration("JWT_REFRESH_TOKEN_TTL", 7*24*time.Hour),
-			Issuer:          getEnv("ISSUER", "myapp"),
-			Audience:        getEnv("AUDIENCE", "user-myapp"),
+			PrivateKeyPath:         getEnv("JWT_PRIVATE_KEY_PATH", "keys/private.pem"),
+			PublicKeyPath:          getEnv("JWT_PUBLIC_KEY_PATH", "keys/public.pem"),
+			AccessTokenTTL:         getEnvAsDuration("JWT_ACCESS_TOKEN_TTL", 15*time.Minute),
+			RefreshTokenTTL:        getEnvAsDuration("JWT_REFRESH_TOKEN_TTL", 7*24*time.Hour),
+			Issuer:                 getEnv("ISSUER", "myapp"),
+			Audience:               getEnv("AUDIENCE", "user-myapp"),
+			RedisFailurePolicy:     getEnv("AUTH_REDIS_FAILURE_POLICY", "fail_closed"),
+			MaxSessions:            getEnvAsInt("AUTH_MAX_SESSIONS", 0),
+			SessionLimitPolicy:     getEnv("AUTH_SESSION_LIMIT_POLICY", "reject"),
+			SessionCleanupInterval: getEnvAsDuration("AUTH_SESSION_CLEANUP_INTERVAL", time.Hour),
 		},
 		// Reis configuration
 		Redis: RedisConfig{
-			Host:     getEnv("REDIS_HOST", "localhost"),
-			Port:     getEnv("REDIS_PORT", "6379"),
-			Password: getEnv("REDIS_PASSWORD", ""),
-			DB:       getEnvAsInt("REDIS_DB", 0),
+			Host:                 getEnv("REDIS_HOST", "localhost"),
+			Port:                 getEnv("REDIS_PORT", "6379"),
+			Password:             getEnv("REDIS_PASSWORD", ""),
+			DB:                   getEnvAsInt("REDIS_DB", 0),
+			HealthCheckInterval:  getEnvAsDuration("REDIS_HEALTH_CHECK_INTERVAL", 5*time.Second),
+			MaxReconnectBackoff:  getEnvAsDuration("REDIS_MAX_RECONNECT_BACKOFF", time.Minute),
+			ProductCacheTTL:      getEnvAsDuration("PRODUCT_CACHE_TTL", 30*time.Second),
+			ProductCountCacheTTL: getEnvAsDuration("PRODUCT_COUNT_CACHE_TTL", 5*time.Minute),
+			ResponseCacheTTL:     getEnvAsDuration("RESPONSE_CACHE_TTL", 10*time.Second),
+			OrderStatsCacheTTL:   getEnvAsDuration("ORDER_STATS_CACHE_TTL", 5*time.Minute),
+		},
+		// Storage configuration
+		Storage: StorageConfig{
+			LocalDir:      getEnv("STORAGE_LOCAL_DIR", "uploads"),
+			BaseURL:       getEnv("STORAGE_BASE_URL", "/uploads"),
+			MaxUploadSize: int64(getEnvAsInt("STORAGE_MAX_UPLOAD_SIZE", 5<<20)),
+		},
+		// Password hashing configuration
+		Password: PasswordConfig{
+			Algorithm:         getEnv("PASSWORD_HASH_ALGORITHM", "argon2id"),
+			BcryptCost:        getEnvAsInt("BCRYPT_COST", 10),
+			Argon2Memory:      getEnvAsInt("ARGON2_MEMORY_KIB", 64*1024),
+			Argon2Time:        getEnvAsInt("ARGON2_TIME", 3),
+			Argon2Parallelism: getEnvAsInt("ARGON2_PARALLELISM", 2),
+		},
+		// Catalog snapshot configuration
+		Snapshot: SnapshotConfig{
+			DailyAt:   getEnv("CATALOG_SNAPSHOT_TIME", "02:00"),
+			Retention: getEnvAsDuration("CATALOG_SNAPSHOT_RETENTION", 30*24*time.Hour),
+		},
+		// Public order tracking configuration
+		Tracking: TrackingConfig{
+			Secret:     getEnv("TRACKING_TOKEN_SECRET", ""),
+			BaseURL:    getEnv("TRACKING_BASE_URL", "/api/v1/track"),
+			RateLimit:  getEnvAsInt("TRACKING_RATE_LIMIT", 30),
+			RateWindow: getEnvAsDuration("TRACKING_RATE_WINDOW", time.Minute),
+		},
+		// Payment capture retry configuration
+		Payment: PaymentConfig{
+			CaptureRetryInterval: getEnvAsDuration("PAYMENT_CAPTURE_RETRY_INTERVAL", 30*time.Second),
+			CaptureBaseBackoff:   getEnvAsDuration("PAYMENT_CAPTURE_BASE_BACKOFF", time.Minute),
+			CaptureMaxBackoff:    getEnvAsDuration("PAYMENT_CAPTURE_MAX_BACKOFF", time.Hour),
+			CaptureMaxAttempts:   getEnvAsInt("PAYMENT_CAPTURE_MAX_ATTEMPTS", 10),
+			CaptureBatchSize:     getEnvAsInt("PAYMENT_CAPTURE_BATCH_SIZE", 50),
+			CaptureTimeout:       getEnvAsDuration("PAYMENT_CAPTURE_TIMEOUT", 15*time.Second),
+			Provider:             getEnv("PAYMENT_PROVIDER", ""),
+			StripeSecretKey:      getEnv("STRIPE_SECRET_KEY", ""),
+			StripeWebhookSecret:  getEnv("STRIPE_WEBHOOK_SECRET", ""),
+			StripeAPIURL:         getEnv("STRIPE_API_URL", "https://api.stripe.com"),
+			Currency:             getEnv("PAYMENT_CURRENCY", "idr"),
+		},
+		// Email configuration
+		Mail: MailConfig{
+			SMTPHost:      getEnv("SMTP_HOST", ""),
+			SMTPPort:      getEnvAsInt("SMTP_PORT", 587),
+			SMTPUsername:  getEnv("SMTP_USERNAME", ""),
+			SMTPPassword:  getEnv("SMTP_PASSWORD", ""),
+			From:          getEnv("MAIL_FROM", "noreply@localhost"),
+			DefaultLocale: getEnv("EMAIL_DEFAULT_LOCALE", "id"),
+		},
+		// Admin alert configuration
+		Notify: NotificationConfig{
+			WebhookURL:    getEnv("NOTIFICATION_WEBHOOK_URL", ""),
+			WebhookSecret: getEnv("NOTIFICATION_WEBHOOK_SECRET", ""),
+		},
+		Alerts: AlertConfig{
+			LowStockWebhookURL: getEnv("LOW_STOCK_WEBHOOK_URL", ""),
+			WebhookURL:         getEnv("ALERT_WEBHOOK_URL", ""),
+			CheckoutAnomaly: CheckoutAnomalyConfig{
+				Interval:       getEnvAsDuration("CHECKOUT_ANOMALY_INTERVAL", 15*time.Second),
+				Window:         getEnvAsDuration("CHECKOUT_ANOMALY_WINDOW", 5*time.Minute),
+				Baseline:       getEnvAsDuration("CHECKOUT_ANOMALY_BASELINE", time.Hour),
+				Sensitivity:    getEnvAsFloat("CHECKOUT_ANOMALY_SENSITIVITY", 3),
+				MinAttempts:    getEnvAsInt("CHECKOUT_ANOMALY_MIN_ATTEMPTS", 20),
+				MinFailureRate: getEnvAsFloat("CHECKOUT_ANOMALY_MIN_FAILURE_RATE", 0.2),
+				Cooldown:       getEnvAsDuration("CHECKOUT_ANOMALY_COOLDOWN", 15*time.Minute),
+			},
+		},
+		// Order stock reservation configuration
+		Orders: OrderConfig{
+			ReservationTTL:           getEnvAsDuration("ORDER_RESERVATION_TTL", 30*time.Minute),
+			ReservationSweepInterval: getEnvAsDuration("ORDER_RESERVATION_SWEEP_INTERVAL", time.Minute),
+			MaxItems:                 getEnvAsInt("ORDER_MAX_ITEMS", 50),
+			MaxItemQuantity:          getEnvAsInt("ORDER_MAX_ITEM_QUANTITY", 100),
+			StatusNotifications:      getEnvAsList("ORDER_STATUS_NOTIFICATIONS", nil),
+			GuestCheckout:            getEnv("GUEST_CHECKOUT_ENABLED", "false") == "true",
+			GuestRateLimit:           getEnvAsInt("GUEST_CHECKOUT_RATE_LIMIT", 10),
+			GuestRateWindow:          getEnvAsDuration("GUEST_CHECKOUT_RATE_WINDOW", time.Hour),
+		},
+		// Shipping configuration
+		Shipping: ShippingConfig{
+			Methods: getEnvAsList("SHIPPING_METHODS", nil),
+		},
+		// Tax configuration
+		Tax: TaxConfig{
+			Rates: getEnvAsList("TAX_RATES", nil),
+		},
+		// Product configuration
+		Products: ProductConfig{
+			RelatedRefreshInterval: getEnvAsDuration("RELATED_PRODUCTS_REFRESH_INTERVAL", time.Hour),
+			ArchiveRetention:       getEnvAsDuration("PRODUCT_ARCHIVE_RETENTION", 0),
+			ArchivePurgeInterval:   getEnvAsDuration("PRODUCT_ARCHIVE_PURGE_INTERVAL", 24*time.Hour),
+		},
+		// Backup configuration
+		Backup: BackupConfig{
+			AuthStateInterval:  getEnvAsDuration("AUTH_STATE_BACKUP_INTERVAL", time.Hour),
+			AuthStateRetention: getEnvAsDuration("AUTH_STATE_BACKUP_RETENTION", 7*24*time.Hour),
+			Storage:            getEnv("BACKUP_STORAGE", "local"),
+			LocalDir:           getEnv("BACKUP_LOCAL_DIR", "backups"),
+			S3: S3Config{
+				Endpoint:        getEnv("BACKUP_S3_ENDPOINT", ""),
+				Region:          getEnv("BACKUP_S3_REGION", "us-east-1"),
+				Bucket:          getEnv("BACKUP_S3_BUCKET", ""),
+				AccessKeyID:     getEnv("BACKUP_S3_ACCESS_KEY_ID", ""),
+				SecretAccessKey: getEnv("BACKUP_S3_SECRET_ACCESS_KEY", ""),
+				PathStyle:       getEnv("BACKUP_S3_PATH_STYLE", "true") == "true",
+			},
+		},
+		// Access log configuration
+		Logs: AccessLogConfig{
+			BufferSize:      getEnvAsInt("ACCESS_LOG_BUFFER_SIZE", 4096),
+			BatchSize:       getEnvAsInt("ACCESS_LOG_BATCH_SIZE", 500),
+			FlushInterval:   getEnvAsDuration("ACCESS_LOG_FLUSH_INTERVAL", time.Second),
+			Retention:       getEnvAsDuration("ACCESS_LOG_RETENTION", 90*24*time.Hour),
+			ExportDir:       getEnv("ACCESS_LOG_EXPORT_DIR", "exports"),
+			ExportRetention: getEnvAsDuration("ACCESS_LOG_EXPORT_RETENTION", 7*24*time.Hour),
+		},
+		// OAuth provider configuration
+		OAuth: OAuthConfig{
+			IssuerURL:        strings.TrimSuffix(getEnv("OAUTH_ISSUER_URL", "http://localhost:8080"), "/"),
+			AuthorizationURL: getEnv("OAUTH_AUTHORIZATION_URL", ""),
+			CodeTTL:          getEnvAsDuration("OAUTH_CODE_TTL", 5*time.Minute),
+			TokenRateLimit:   getEnvAsInt("OAUTH_TOKEN_RATE_LIMIT", 30),
+			TokenRateWindow:  getEnvAsDuration("OAUTH_TOKEN_RATE_WINDOW", time.Minute),
+		},
+		// Replay protection of high-value mutations
+		Replay: ReplayConfig{
+			Mode:   getEnv("REPLAY_PROTECTION_MODE", "optional"),
+			Window: getEnvAsDuration("REPLAY_PROTECTION_WINDOW", 5*time.Minute),
+		},
+		// Idempotency keys
+		Idempotency: IdempotencyConfig{
+			TTL: getEnvAsDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		},
+		// Load shedding
+		LoadShed: LoadShedConfig{
+			MaxInFlight:  getEnvAsInt("LOAD_SHED_MAX_IN_FLIGHT", 0),
+			BrowseShare:  getEnvAsFloat("LOAD_SHED_BROWSE_SHARE", 0.8),
+			ReportsShare: getEnvAsFloat("LOAD_SHED_REPORTS_SHARE", 0.5),
+			RetryAfter:   getEnvAsDuration("LOAD_SHED_RETRY_AFTER", 2*time.Second),
+			Routes:       getEnvAsList("LOAD_SHED_ROUTES", nil),
+		},
+		// Token bucket rate limit
+		RateLimit: RateLimitConfig{
+			PerMinute:        getEnvAsInt("RATE_LIMIT_PER_MINUTE", 600),
+			Burst:            getEnvAsInt("RATE_LIMIT_BURST", 100),
+			AuthPerMinute:    getEnvAsInt("RATE_LIMIT_AUTH_PER_MINUTE", 20),
+			AuthBurst:        getEnvAsInt("RATE_LIMIT_AUTH_BURST", 10),
+			CatalogPerMinute: getEnvAsInt("RATE_LIMIT_CATALOG_PER_MINUTE", 1800),
+			CatalogBurst:     getEnvAsInt("RATE_LIMIT_CATALOG_BURST", 300),
+			Routes:           getEnvAsList("RATE_LIMIT_ROUTES", nil),
+		},
+		// API versions
+		API: APIConfig{
+			DeprecatedVersions: getEnvAsList("API_DEPRECATED_VERSIONS", nil),
+		},
+		// Cross-origin requests from browsers
+		CORS: CORSConfig{
+			AllowedOrigins:   getEnvAsList("CORS_ALLOWED_ORIGINS", nil),
+			AllowCredentials: getEnv("CORS_ALLOW_CREDENTIALS", "false") == "true",
+			MaxAge:           getEnvAsDuration("CORS_MAX_AGE", 10*time.Minute),
+		},
+		// Client IPs behind proxies and IP filtering
+		Network: NetworkConfig{
+			TrustedProxies: getEnvAsList("TRUSTED_PROXIES", nil),
+			DenyList:       getEnvAsList("IP_DENYLIST", nil),
+			AdminAllowList: getEnvAsList("ADMIN_IP_ALLOWLIST", nil),
+		},
+		// Field usage analytics
+		Fields: FieldUsageConfig{
+			SampleRate:    getEnvAsFloat("FIELD_USAGE_SAMPLE_RATE", 0.1),
+			FlushInterval: getEnvAsDuration("FIELD_USAGE_FLUSH_INTERVAL", time.Minute),
+		},
+		// Metrics configuration
+		Metrics: MetricsConfig{
+			Token: getEnv("METRICS_TOKEN", ""),
+		},
+		// Service level objective configuration
+		SLO: SLOConfig{
+			CheckoutSuccess:  getEnvAsFloat("SLO_CHECKOUT_SUCCESS", 99),
+			Latency:          getEnvAsFloat("SLO_LATENCY", 95),
+			LatencyThreshold: getEnvAsDuration("SLO_LATENCY_THRESHOLD", 500*time.Millisecond),
+			Interval:         getEnvAsDuration("SLO_INTERVAL", time.Minute),
+			Period:           getEnvAsDuration("SLO_PERIOD", 30*24*time.Hour),
+			FastBurnRate:     getEnvAsFloat("SLO_FAST_BURN_RATE", 14.4),
+			FastBurnWindow:   getEnvAsDuration("SLO_FAST_BURN_WINDOW", time.Hour),
+			SlowBurnRate:     getEnvAsFloat("SLO_SLOW_BURN_RATE", 6),
+			SlowBurnWindow:   getEnvAsDuration("SLO_SLOW_BURN_WINDOW", 6*time.Hour),
+			MinEvents:        getEnvAsInt("SLO_MIN_EVENTS", 20),
+		},
+		// Outbox configuration
+		Outbox: OutboxConfig{
+			Broker:            getEnv("OUTBOX_BROKER", "log"),
+			Prefix:            getEnv("OUTBOX_PREFIX", "shop"),
+			NATSURL:           getEnv("OUTBOX_NATS_URL", "nats://localhost:4222"),
+			NATSJetStream:     getEnv("OUTBOX_NATS_JETSTREAM", "false") == "true",
+			RedisStreamMaxLen: int64(getEnvAsInt("OUTBOX_REDIS_STREAM_MAX_LEN", 100000)),
+			PollInterval:      getEnvAsDuration("OUTBOX_POLL_INTERVAL", time.Second),
+			BatchSize:         getEnvAsInt("OUTBOX_BATCH_SIZE", 100),
+			PublishTimeout:    getEnvAsDuration("OUTBOX_PUBLISH_TIMEOUT", 5*time.Second),
+			BaseBackoff:       getEnvAsDuration("OUTBOX_BASE_BACKOFF", time.Second),
+			MaxBackoff:        getEnvAsDuration("OUTBOX_MAX_BACKOFF", 5*time.Minute),
+			Retention:         getEnvAsDuration("OUTBOX_RETENTION", 7*24*time.Hour),
+		},
+		// Background job configuration
+		Jobs: JobConfig{
+			Queues:       getEnvAsList("JOB_QUEUES", []string{"default"}),
+			Workers:      getEnvAsInt("JOB_WORKERS", 4),
+			PollInterval: getEnvAsDuration("JOB_POLL_INTERVAL", time.Second),
+			MaxAttempts:  getEnvAsInt("JOB_MAX_ATTEMPTS", 5),
+			BaseBackoff:  getEnvAsDuration("JOB_BASE_BACKOFF", 10*time.Second),
+			MaxBackoff:   getEnvAsDuration("JOB_MAX_BACKOFF", 30*time.Minute),
+			Timeout:      getEnvAsDuration("JOB_TIMEOUT", time.Minute),
+			StaleAfter:   getEnvAsDuration("JOB_STALE_AFTER", 10*time.Minute),
+			Retention:    getEnvAsDuration("JOB_RETENTION", 24*time.Hour),
+		},
+		// Payment reconciliation configuration
+		Recon: ReconciliationConfig{
+			DailyAt: getEnv("RECONCILIATION_TIME", "03:00"),
 		},
 	}, nil
 }
@@ -100,6 +746,15 @@ func getEnvAsInt(key string, defaultVal int) int {
 	return defaultVal
 }
 
+func getEnvAsFloat(key string, defaultVal float64) float64 {
+	if valueStr, exists := os.LookupEnv(key); exists {
+		if value, err := strconv.ParseFloat(valueStr, 64); err == nil {
+			return value
+		}
+	}
+	return defaultVal
+}
+
 func getEnvAsDuration(key string, defaultVal time.Duration) time.Duration {
 	if value, exists := os.LookupEnv(key); exists {
 		if duration, err := time.ParseDuration(value); err == nil {
@@ -108,3 +763,18 @@ func getEnvAsDuration(key string, defaultVal time.Duration) time.Duration {
 	}
 	return defaultVal
 }
+
+func getEnvAsList(key string, defaultVal []string) []string {
+	if value, exists := os.LookupEnv(key); exists {
+		var list []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		if len(list) > 0 {
+			return list
+		}
+	}
+	return defaultVal
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"postgresDB/internal/delivery/middleware"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/logger"
+	"postgresDB/pkg/validator"
+
+	"github.com/google/uuid"
+)
+
+type AccessLogHandler struct {
+	accessLogService service.AccessLogService
+}
+
+func NewAccessLogHandler(accessLogService service.AccessLogService) *AccessLogHandler {
+	return &AccessLogHandler{
+		accessLogService: accessLogService,
+	}
+}
+
+// CreateExport handles requesting an access log export; the file is written in the background
+func (h *AccessLogHandler) CreateExport(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+	userRole, err := middleware.GetUserRole(r.Context())
+	if err != nil {
+		response.BadRequest(w, "Role tidak ditemukan")
+		return
+	}
+
+	var req dto.CreateAccessLogExportRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	export, err := h.accessLogService.RequestExport(r.Context(), userID, userRole, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, export)
+}
+
+// ListExports handles listing access log exports
+func (h *AccessLogHandler) ListExports(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+	userRole, err := middleware.GetUserRole(r.Context())
+	if err != nil {
+		response.BadRequest(w, "Role tidak ditemukan")
+		return
+	}
+
+	var page dto.PageRequest
+	if err := bindQuery(r, &page); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	exports, meta, err := h.accessLogService.ListExports(r.Context(), userID, userRole, page.Page, page.Limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.SuccessWithMeta(w, exports, meta)
+}
+
+// GetExport handles retrieving the status of an access log export
+func (h *AccessLogHandler) GetExport(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID export tidak valid")
+		return
+	}
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+	userRole, err := middleware.GetUserRole(r.Context())
+	if err != nil {
+		response.BadRequest(w, "Role tidak ditemukan")
+		return
+	}
+
+	export, err := h.accessLogService.GetExport(r.Context(), id, userID, userRole)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, export)
+}
+
+// DownloadExport handles downloading the file of a completed access log export
+func (h *AccessLogHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID export tidak valid")
+		return
+	}
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+	userRole, err := middleware.GetUserRole(r.Context())
+	if err != nil {
+		response.BadRequest(w, "Role tidak ditemukan")
+		return
+	}
+
+	file, err := h.accessLogService.OpenExport(r.Context(), id, userID, userRole)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	defer file.Body.Close()
+
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, file.Name))
+	w.Header().Set("Content-Length", strconv.FormatInt(file.Size, 10))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, file.Body); err != nil {
+		logger.WarnContext(r.Context(), "sending access log export failed", "export_id", id, "error", err)
+	}
+}
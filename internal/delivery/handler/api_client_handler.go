@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"postgresDB/internal/delivery/middleware"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/validator"
+
+	"github.com/google/uuid"
+)
+
+type APIClientHandler struct {
+	clientService service.APIClientService
+}
+
+func NewAPIClientHandler(clientService service.APIClientService) *APIClientHandler {
+	return &APIClientHandler{
+		clientService: clientService,
+	}
+}
+
+// Create handles registering an API client; the secret is only returned here
+func (h *APIClientHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	var req dto.CreateAPIClientRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	client, err := h.clientService.Create(r.Context(), userID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, client)
+}
+
+// List handles listing the API clients of the user, or all clients for admins
+func (h *APIClientHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, userRole, ok := requester(w, r)
+	if !ok {
+		return
+	}
+
+	var page dto.PageRequest
+	if err := bindQuery(r, &page); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	clients, meta, err := h.clientService.List(r.Context(), userID, userRole, page.Page, page.Limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.SuccessWithMeta(w, clients, meta)
+}
+
+// Get handles retrieving an API client
+func (h *APIClientHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID API client tidak valid")
+		return
+	}
+	userID, userRole, ok := requester(w, r)
+	if !ok {
+		return
+	}
+
+	client, err := h.clientService.Get(r.Context(), id, userID, userRole)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, client)
+}
+
+// Update handles changing the name, redirect URIs or scopes of an API client
+func (h *APIClientHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID API client tidak valid")
+		return
+	}
+	userID, userRole, ok := requester(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateAPIClientRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	client, err := h.clientService.Update(r.Context(), id, userID, userRole, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, client)
+}
+
+// RotateSecret handles issuing a new secret for an API client
+func (h *APIClientHandler) RotateSecret(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID API client tidak valid")
+		return
+	}
+	userID, userRole, ok := requester(w, r)
+	if !ok {
+		return
+	}
+
+	client, err := h.clientService.RotateSecret(r.Context(), id, userID, userRole)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, client)
+}
+
+// Delete handles removing an API client
+func (h *APIClientHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID API client tidak valid")
+		return
+	}
+	userID, userRole, ok := requester(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.clientService.Delete(r.Context(), id, userID, userRole); err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, map[string]string{"message": "API client berhasil dihapus"})
+}
+
+// Usage handles the daily traffic of an API client over ?from and ?to (RFC 3339)
+func (h *APIClientHandler) Usage(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID API client tidak valid")
+		return
+	}
+	userID, userRole, ok := requester(w, r)
+	if !ok {
+		return
+	}
+
+	var from, to time.Time
+	if v := r.URL.Query().Get("from"); v != "" {
+		if from, err = time.Parse(time.RFC3339, v); err != nil {
+			response.BadRequest(w, "from harus berformat RFC3339")
+			return
+		}
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		if to, err = time.Parse(time.RFC3339, v); err != nil {
+			response.BadRequest(w, "to harus berformat RFC3339")
+			return
+		}
+	}
+
+	usage, err := h.clientService.Usage(r.Context(), id, userID, userRole, from, to)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, usage)
+}
+
+// requester reads the authenticated user and role, writing a bad request when missing
+func requester(w http.ResponseWriter, r *http.Request) (uuid.UUID, entities.Role, bool) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return uuid.Nil, "", false
+	}
+	userRole, err := middleware.GetUserRole(r.Context())
+	if err != nil {
+		response.BadRequest(w, "Role tidak ditemukan")
+		return uuid.Nil, "", false
+	}
+	return userID, userRole, true
+}
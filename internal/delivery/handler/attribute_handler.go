@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/validator"
+
+	"github.com/google/uuid"
+)
+
+type AttributeHandler struct {
+	attributeService service.AttributeService
+}
+
+func NewAttributeHandler(attributeService service.AttributeService) *AttributeHandler {
+	return &AttributeHandler{
+		attributeService: attributeService,
+	}
+}
+
+// Create handles defining an attribute for a category
+func (h *AttributeHandler) Create(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID kategori tidak valid")
+		return
+	}
+
+	var req dto.CreateAttributeRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	attribute, err := h.attributeService.Create(r.Context(), categoryID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, attribute)
+}
+
+// ListByCategory handles listing the attributes of a category
+func (h *AttributeHandler) ListByCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID kategori tidak valid")
+		return
+	}
+
+	attributes, err := h.attributeService.ListByCategory(r.Context(), categoryID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, attributes)
+}
+
+// Update handles updating an attribute definition
+func (h *AttributeHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID atribut tidak valid")
+		return
+	}
+
+	var req dto.UpdateAttributeRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	attribute, err := h.attributeService.Update(r.Context(), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, attribute)
+}
+
+// Delete handles deleting an attribute definition
+func (h *AttributeHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID atribut tidak valid")
+		return
+	}
+
+	if err := h.attributeService.Delete(r.Context(), id); err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.NoContent(w)
+}
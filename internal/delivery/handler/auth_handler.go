@@ -1,11 +1,11 @@
 package handler
 
 import (
-	"encoding/json"
 	"net/http"
 	"postgresDB/internal/delivery/middleware"
 	"postgresDB/internal/delivery/response"
 	"postgresDB/internal/domain/dto"
+	apperror "postgresDB/internal/domain/errors"
 	"postgresDB/internal/domain/service"
 	"postgresDB/pkg/validator"
 	"time"
@@ -24,32 +24,22 @@ func NewAuthHandler(authService service.AuthService, refreshTTL time.Duration) *
 }
 
 // Register handles user registration
-func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
-	// method POST check
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) error {
 	// parse request body
 	var req dto.RegisterRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		//response.BadRequest(w,"Invalit rekuest body")
-		return
+	if err := bindJSON(r, &req); err != nil {
+		return err
 	}
 
 	// validate request
 	if err := validator.ValidateStruct(&req); err != nil {
-		response.Error(w, err)
-		return
+		return err
 	}
 
 	// call service
 	res, err := h.authService.Register(r.Context(), req)
 	if err != nil {
-		response.Error(w, err)
-		return
+		return err
 	}
 
 	// set refresh token in http-only cookie
@@ -62,32 +52,24 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	})
 
 	response.Created(w, res)
+	return nil
 }
 
 // Login handles user login
-func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
-	// method POST check
-	if r.Method != http.MethodPost {
-		response.BadRequest(w, "Method not allowed")
-		return
-	}
-
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) error {
 	// parse request body
 	var req dto.LoginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
+	if err := bindJSON(r, &req); err != nil {
+		return err
 	}
 
 	if err := validator.ValidateStruct(&req); err != nil {
-		response.Error(w, err)
-		return
+		return err
 	}
 	// call service
-	res, err := h.authService.Login(r.Context(), req)
+	res, err := h.authService.Login(r.Context(), req, r.UserAgent(), middleware.ClientIP(r))
 	if err != nil {
-		response.Error(w, err)
-		return
+		return err
 	}
 	// set refresh token in http-only cookie
 	http.SetCookie(w, &http.Cookie{
@@ -99,36 +81,28 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	})
 
 	response.Success(w, res)
+	return nil
 }
 
 // Logout handles user logout
-func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
-	// method POST check
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) error {
 	// Get access token JTI and exp from context (set by auth middleware)
 	jti, _ := middleware.GetTokenJTIFromContext(r.Context())
 
 	exp, ok := r.Context().Value(middleware.TokenExpKey).(time.Time)
 	if !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
+		return apperror.ErrUnauthorized
 	}
 	// Get refresh token from cookie
 	cookie, err := r.Cookie("refresh_token")
 	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
+		return apperror.ErrUnauthorized
 	}
 	refreshToken := cookie.Value
 
 	// Call service to blacklist the token
 	if err := h.authService.Logout(r.Context(), jti, exp, refreshToken); err != nil {
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
+		return err
 	}
 
 	// clear refresh token cookie
@@ -141,21 +115,16 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	})
 
 	response.Success(w, map[string]string{"message": "logout berhasil"})
+	return nil
 }
 
 // RefreshToken handles token refresh
 
-func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
-	// method POST check
-	if r.Method != http.MethodPost {
-		response.BadRequest(w, "Method not allowed")
-		return
-	}
+func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) error {
 	// get refresh token from cookie
 	cookie, err := r.Cookie("refresh_token")
 	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
+		return apperror.ErrUnauthorized
 	}
 	refreshToken := cookie.Value
 	// call service
@@ -169,8 +138,7 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 			HttpOnly: true,
 			Path:     "/",
 		})
-		response.Error(w, err)
-		return
+		return err
 	}
 
 	// set new refresh token in http-only cookie
@@ -183,27 +151,60 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	})
 
 	response.Success(w, res)
+	return nil
 }
 
 // RevokeAllSessions handles revoking all user sessions
-func (h *AuthHandler) RevokeAllSessions(w http.ResponseWriter, r *http.Request) {
-	// method POST check
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
+func (h *AuthHandler) RevokeAllSessions(w http.ResponseWriter, r *http.Request) error {
 	// Get user ID from context (set by auth middleware)
 	userID, err := middleware.GetUserID(r.Context())
 	if err != nil {
-		response.BadRequest(w, "user tidak ditemukan")
-		return
+		return apperror.ErrUnauthorized
 	}
 
 	// Call service to revoke all sessions
 	if err := h.authService.RevokeAllSessions(r.Context(), userID); err != nil {
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
+		return err
 	}
 	response.Success(w, map[string]string{"message": "All sessions revoked successfully"})
+	return nil
+}
+
+// ListSessions handles listing the sessions of the signed in user, with the devices
+// whose session was evicted by the session limit
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) error {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	res, err := h.authService.ListSessions(r.Context(), userID)
+	if err != nil {
+		return err
+	}
+	response.Success(w, res)
+	return nil
+}
+
+// RevokeTokens handles revoking tokens in bulk by issue time, role or user
+func (h *AuthHandler) RevokeTokens(w http.ResponseWriter, r *http.Request) error {
+	adminID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var req dto.RevokeTokensRequest
+	if err := bindJSON(r, &req); err != nil {
+		return err
+	}
+	if err := validator.ValidateStruct(&req); err != nil {
+		return err
+	}
+
+	res, err := h.authService.RevokeTokens(r.Context(), adminID, req)
+	if err != nil {
+		return err
+	}
+	response.Success(w, res)
+	return nil
 }
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+
+	"postgresDB/internal/delivery/middleware"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+type AuthStateHandler struct {
+	backupService service.AuthStateBackupService
+}
+
+func NewAuthStateHandler(backupService service.AuthStateBackupService) *AuthStateHandler {
+	return &AuthStateHandler{
+		backupService: backupService,
+	}
+}
+
+// CreateBackup handles taking a manual backup of the Redis auth state
+func (h *AuthStateHandler) CreateBackup(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	backup, err := h.backupService.Backup(r.Context(), entities.AuthStateTriggerManual, &userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, backup)
+}
+
+// ListBackups handles listing auth state backups
+func (h *AuthStateHandler) ListBackups(w http.ResponseWriter, r *http.Request) {
+	var page dto.PageRequest
+	if err := bindQuery(r, &page); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	backups, meta, err := h.backupService.ListBackups(r.Context(), page.Page, page.Limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.SuccessWithMeta(w, backups, meta)
+}
+
+// Restore handles restoring the Redis auth state from a backup
+func (h *AuthStateHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID backup tidak valid")
+		return
+	}
+
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	restore, err := h.backupService.Restore(r.Context(), id, userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, restore)
+}
+
+// ListRestores handles listing restores of auth state backups
+func (h *AuthStateHandler) ListRestores(w http.ResponseWriter, r *http.Request) {
+	var page dto.PageRequest
+	if err := bindQuery(r, &page); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	restores, meta, err := h.backupService.ListRestores(r.Context(), page.Page, page.Limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.SuccessWithMeta(w, restores, meta)
+}
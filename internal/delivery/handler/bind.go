@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/pkg/query"
+	"postgresDB/pkg/validator"
+)
+
+// bindJSON decodes the JSON body of r into dst. Bodies of another Content-Type get 415,
+// and unknown fields, values of the wrong type, malformed JSON and data after the value
+// get a validation error naming the field and the line and column in the body
+func bindJSON(r *http.Request, dst any) error {
+	return decodeJSON(r, dst, false)
+}
+
+// bindOptionalJSON is bindJSON for routes whose body may be left out, an empty body
+// leaves dst as it is
+func bindOptionalJSON(r *http.Request, dst any) error {
+	return decodeJSON(r, dst, true)
+}
+
+func decodeJSON(r *http.Request, dst any, optional bool) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return apperror.ErrRequestTooLarge
+		}
+		return apperror.NewAppError(apperror.CodeBadRequest, "Body request tidak dapat dibaca", http.StatusBadRequest).WithError(err)
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		if optional {
+			return nil
+		}
+		return apperror.NewValidationError([]apperror.ValidationError{{Message: "Body request wajib diisi"}})
+	}
+	if !isJSON(r.Header.Get("Content-Type")) {
+		return apperror.ErrUnsupportedMediaType
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		return decodeError(body, dec.InputOffset(), err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return decodeError(body, dec.InputOffset(), errTrailingData)
+	}
+	return nil
+}
+
+// bindQuery binds the query parameters of r into dst, see query.Bind, and validates it.
+// Malformed parameters are reported instead of falling back to their defaults
+func bindQuery(r *http.Request, dst any) error {
+	if err := query.Bind(r.URL.Query(), dst); err != nil {
+		return err
+	}
+	return validator.ValidateStruct(dst)
+}
+
+// errTrailingData is reported for a body with more than one JSON value
+var errTrailingData = errors.New("trailing data")
+
+// isJSON reports whether contentType is application/json or a +json media type
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+// decodeError turns an error of decoding body into a validation error. offset is where
+// the decoder stopped, used when the error doesn't carry a position of its own
+func decodeError(body []byte, offset int64, err error) error {
+	detail := apperror.ValidationError{Message: "Format JSON tidak valid"}
+
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+		detail.Field = typeErr.Field
+		detail.Message = typeMessage(typeErr.Type.String())
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		offset = int64(len(body))
+		detail.Message = "JSON tidak lengkap"
+	case errors.Is(err, errTrailingData):
+		detail.Message = "Body request hanya boleh berisi satu nilai JSON"
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		detail.Field = strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		detail.Message = "Field tidak dikenal"
+		// the decoder reports it once the whole value is read, point at the key instead
+		if i := keyOffset(body, detail.Field); i >= 0 {
+			offset = int64(i)
+		}
+	default:
+		var invalid *json.InvalidUnmarshalError
+		if errors.As(err, &invalid) {
+			return apperror.WrapInternal(err)
+		}
+	}
+
+	detail.Line, detail.Column = position(body, offset)
+	return apperror.NewValidationError([]apperror.ValidationError{detail})
+}
+
+// keyOffset returns where the first object key named key starts in body, -1 when there's none
+func keyOffset(body []byte, key string) int {
+	quoted, _ := json.Marshal(key)
+	for from := 0; ; {
+		i := bytes.Index(body[from:], quoted)
+		if i < 0 {
+			return -1
+		}
+		i += from
+		rest := bytes.TrimLeft(body[i+len(quoted):], " \t\r\n")
+		if len(rest) > 0 && rest[0] == ':' {
+			return i
+		}
+		from = i + len(quoted)
+	}
+}
+
+// typeMessage tells a client the JSON kind a value of a Go type must be
+func typeMessage(goType string) string {
+	goType = strings.TrimLeft(goType, "*")
+	switch {
+	case goType == "string", goType == "uuid.UUID", goType == "time.Time":
+		return "Harus berupa string"
+	case goType == "bool":
+		return "Harus berupa boolean"
+	case strings.HasPrefix(goType, "int"), strings.HasPrefix(goType, "uint"):
+		return "Harus berupa bilangan bulat"
+	case strings.HasPrefix(goType, "float"):
+		return "Harus berupa angka"
+	case strings.HasPrefix(goType, "[]"):
+		return "Harus berupa array"
+	default:
+		return "Harus berupa objek"
+	}
+}
+
+// position returns the line and column of offset in body, both counted from 1
+func position(body []byte, offset int64) (int, int) {
+	if offset > int64(len(body)) {
+		offset = int64(len(body))
+	}
+	before := body[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/validator"
+
+	"github.com/google/uuid"
+)
+
+type CatalogSnapshotHandler struct {
+	snapshotService service.CatalogSnapshotService
+}
+
+func NewCatalogSnapshotHandler(snapshotService service.CatalogSnapshotService) *CatalogSnapshotHandler {
+	return &CatalogSnapshotHandler{
+		snapshotService: snapshotService,
+	}
+}
+
+// Create handles taking a manual catalog snapshot
+func (h *CatalogSnapshotHandler) Create(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := h.snapshotService.Capture(r.Context(), entities.SnapshotTriggerManual)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, snapshot)
+}
+
+// List handles listing catalog snapshots
+func (h *CatalogSnapshotHandler) List(w http.ResponseWriter, r *http.Request) {
+	var page dto.PageRequest
+	if err := bindQuery(r, &page); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	snapshots, meta, err := h.snapshotService.List(r.Context(), page.Page, page.Limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.SuccessWithMeta(w, snapshots, meta)
+}
+
+// Diff handles comparing two snapshots, or a snapshot with the current catalog when to is omitted
+func (h *CatalogSnapshotHandler) Diff(w http.ResponseWriter, r *http.Request) {
+	from, err := uuid.Parse(r.URL.Query().Get("from"))
+	if err != nil {
+		response.BadRequest(w, "Parameter from tidak valid")
+		return
+	}
+
+	var to *uuid.UUID
+	if raw := r.URL.Query().Get("to"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			response.BadRequest(w, "Parameter to tidak valid")
+			return
+		}
+		to = &id
+	}
+
+	diff, err := h.snapshotService.Diff(r.Context(), from, to)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, diff)
+}
+
+// Rollback handles restoring selected products from a snapshot
+func (h *CatalogSnapshotHandler) Rollback(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID snapshot tidak valid")
+		return
+	}
+
+	var req dto.SnapshotRollbackRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	result, err := h.snapshotService.Rollback(r.Context(), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, result)
+}
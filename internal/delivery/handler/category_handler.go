@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"net/http"
+
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/validator"
+
+	"github.com/google/uuid"
+)
+
+type CategoryHandler struct {
+	categoryService service.CategoryService
+}
+
+func NewCategoryHandler(categoryService service.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+	}
+}
+
+// Create handles creating a category
+func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateCategoryRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	category, err := h.categoryService.Create(r.Context(), &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, category)
+}
+
+// GetByID handles retrieving a category
+func (h *CategoryHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID kategori tidak valid")
+		return
+	}
+
+	category, err := h.categoryService.GetByID(r.Context(), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, category)
+}
+
+// List handles listing all categories
+func (h *CategoryHandler) List(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.categoryService.List(r.Context())
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, categories)
+}
+
+// Tree handles retrieving the category tree
+func (h *CategoryHandler) Tree(w http.ResponseWriter, r *http.Request) {
+	tree, err := h.categoryService.Tree(r.Context())
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, tree)
+}
+
+// Update handles updating a category
+func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID kategori tidak valid")
+		return
+	}
+
+	var req dto.UpdateCategoryRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	category, err := h.categoryService.Update(r.Context(), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, category)
+}
+
+// Delete handles deleting a category
+func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID kategori tidak valid")
+		return
+	}
+
+	if err := h.categoryService.Delete(r.Context(), id); err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.NoContent(w)
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"postgresDB/internal/delivery/middleware"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/service"
+
+	"github.com/google/uuid"
+)
+
+// memoryResponseCache keeps cached responses in memory like the Redis store does
+type memoryResponseCache struct {
+	mu        sync.Mutex
+	versions  map[string]int
+	responses map[string]*entities.CachedResponse
+}
+
+func (c *memoryResponseCache) Version(ctx context.Context, scope string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return strconv.Itoa(c.versions[scope]), nil
+}
+
+func (c *memoryResponseCache) Get(ctx context.Context, key string) (*entities.CachedResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.responses[key], nil
+}
+
+func (c *memoryResponseCache) Set(ctx context.Context, key string, response *entities.CachedResponse, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.responses[key] = response
+	return nil
+}
+
+func (c *memoryResponseCache) Invalidate(ctx context.Context, scope string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.versions[scope]++
+	return nil
+}
+
+// memoryCategoryRepo implements the category reads and updates the test makes
+type memoryCategoryRepo struct {
+	repository.CategoryRepository
+	categories map[uuid.UUID]entities.Category
+}
+
+func (r *memoryCategoryRepo) GetByID(ctx context.Context, id uuid.UUID) (*entities.Category, error) {
+	category := r.categories[id]
+	return &category, nil
+}
+
+func (r *memoryCategoryRepo) Update(ctx context.Context, category *entities.Category) error {
+	r.categories[category.ID] = *category
+	return nil
+}
+
+func TestCategoryUpdateVisibleOnNextGet(t *testing.T) {
+	id := uuid.New()
+	repo := &memoryCategoryRepo{categories: map[uuid.UUID]entities.Category{
+		id: {ID: id, Name: "Shoes", Slug: "shoes"},
+	}}
+	cache := &memoryResponseCache{versions: map[string]int{}, responses: map[string]*entities.CachedResponse{}}
+	h := NewCategoryHandler(service.NewCategoryService(repo, cache))
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /categories/{id}", middleware.ResponseCache(cache, entities.CacheScopeCatalog, time.Minute)(http.HandlerFunc(h.GetByID)))
+	mux.HandleFunc("PUT /categories/{id}", h.Update)
+
+	get := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/categories/"+id.String(), nil))
+		return rec
+	}
+
+	if rec := get(); !strings.Contains(rec.Body.String(), "Shoes") {
+		t.Fatalf("first GET = %d %s, want the category", rec.Code, rec.Body)
+	}
+	if rec := get(); rec.Header().Get("X-Cache") != "HIT" {
+		t.Fatalf("second GET X-Cache = %q, want HIT", rec.Header().Get("X-Cache"))
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/categories/"+id.String(), strings.NewReader(`{"name":"Sneakers"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT = %d %s", rec.Code, rec.Body)
+	}
+
+	if rec := get(); !strings.Contains(rec.Body.String(), "Sneakers") {
+		t.Errorf("GET after the update = %s (X-Cache %s), want the new name", rec.Body, rec.Header().Get("X-Cache"))
+	}
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+
+	"postgresDB/internal/delivery/middleware"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+type ConsentHandler struct {
+	consentService service.ConsentService
+}
+
+func NewConsentHandler(consentService service.ConsentService) *ConsentHandler {
+	return &ConsentHandler{
+		consentService: consentService,
+	}
+}
+
+// List handles reading the consents of the user
+func (h *ConsentHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	consents, err := h.consentService.List(r.Context(), userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, consents)
+}
+
+// Update handles changing the consents of the user, recording where the choice was made
+func (h *ConsentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	var req dto.UpdateConsentsRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	consents, err := h.consentService.Update(r.Context(), userID, &req, middleware.ClientIP(r), r.UserAgent())
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, consents)
+}
+
+// History handles listing the consent records of the user
+func (h *ConsentHandler) History(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+	h.history(w, r, userID)
+}
+
+// UserHistory handles listing the consent records of any user, for compliance requests
+func (h *ConsentHandler) UserHistory(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID user tidak valid")
+		return
+	}
+	h.history(w, r, userID)
+}
+
+// history writes a page of the consent records of a user
+func (h *ConsentHandler) history(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	var page dto.PageRequest
+	if err := bindQuery(r, &page); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	records, meta, err := h.consentService.History(r.Context(), userID, page.Page, page.Limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.SuccessWithMeta(w, records, meta)
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"postgresDB/internal/delivery/middleware"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/service"
+	"postgresDB/internal/infrastruktur/cache"
+	"postgresDB/pkg/metrics"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// dashboardRecentOrders is how many of the latest orders the dashboard lists
+	dashboardRecentOrders = 10
+	// dashboardQueueWindow is the window of the queue throughput on the dashboard
+	dashboardQueueWindow = time.Hour
+	// dashboardRefresh is how often the dashboard page reloads itself, in seconds
+	dashboardRefresh = 30
+)
+
+//go:embed templates/dashboard.html
+var dashboardHTML string
+
+var dashboardTemplate = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"percent": func(v float64) string { return fmt.Sprintf("%.2f%%", v*100) },
+}).Parse(dashboardHTML))
+
+// PoolStater is implemented by connection pools that report their usage (e.g. *pgxpool.Pool)
+type PoolStater interface {
+	Stat() *pgxpool.Stat
+}
+
+type DashboardHandler struct {
+	orderService service.OrderService
+	jobService   service.JobService
+	registry     *metrics.Registry
+	pool         PoolStater
+	redisMonitor *cache.RedisHealthMonitor
+}
+
+// NewDashboardHandler creates the handler of the admin dashboard, a small built-in
+// overview for deployments without Grafana
+func NewDashboardHandler(orderService service.OrderService, jobService service.JobService, registry *metrics.Registry, pool PoolStater, redisMonitor *cache.RedisHealthMonitor) *DashboardHandler {
+	return &DashboardHandler{
+		orderService: orderService,
+		jobService:   jobService,
+		registry:     registry,
+		pool:         pool,
+		redisMonitor: redisMonitor,
+	}
+}
+
+// DashboardResponse represents the figures shown on the admin dashboard. Request and
+// order counters are totals since the server started
+type DashboardResponse struct {
+	GeneratedAt  string                   `json:"generated_at"`
+	Requests     DashboardRequests        `json:"requests"`
+	Checkout     DashboardCheckout        `json:"checkout"`
+	Database     DashboardPool            `json:"database"`
+	Redis        cache.RedisHealthStatus  `json:"redis"`
+	Queues       []dto.QueueStatsResponse `json:"queues"`
+	RecentOrders []dto.OrderResponse      `json:"recent_orders"`
+}
+
+// DashboardRequests represents the HTTP traffic and its error rates
+type DashboardRequests struct {
+	Total           int64   `json:"total"`
+	ClientErrors    int64   `json:"client_errors"`
+	ServerErrors    int64   `json:"server_errors"`
+	ClientErrorRate float64 `json:"client_error_rate"`
+	ServerErrorRate float64 `json:"server_error_rate"`
+	InFlight        int64   `json:"in_flight"`
+	Shed            int64   `json:"shed"`
+}
+
+// DashboardCheckout represents the order and checkout figures
+type DashboardCheckout struct {
+	OrdersCreated int64 `json:"orders_created"`
+	Stockouts     int64 `json:"stockouts"`
+	// FailureRate is the checkout failure rate over the anomaly detection window
+	FailureRate float64 `json:"failure_rate"`
+}
+
+// DashboardPool represents the usage of the database connection pool
+type DashboardPool struct {
+	MaxConns      int32 `json:"max_conns"`
+	TotalConns    int32 `json:"total_conns"`
+	AcquiredConns int32 `json:"acquired_conns"`
+	IdleConns     int32 `json:"idle_conns"`
+	AcquireCount  int64 `json:"acquire_count"`
+	// EmptyAcquireCount counts acquires that had to wait for a connection
+	EmptyAcquireCount int64   `json:"empty_acquire_count"`
+	AvgAcquireMs      float64 `json:"avg_acquire_ms"`
+}
+
+// Dashboard handles rendering the admin dashboard as HTML, or as JSON with format=json
+func (h *DashboardHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
+	adminID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	orders, _, err := h.orderService.ListAll(r.Context(), adminID, entities.RoleAdmin, dto.OrderListRequest{Page: 1, Limit: dashboardRecentOrders})
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	queues, err := h.jobService.Stats(r.Context(), dashboardQueueWindow)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	snapshot := h.registry.Snapshot()
+	requests := DashboardRequests{
+		Total:        int64(sumSeries(snapshot, "http_requests_total")),
+		ClientErrors: int64(snapshot[`http_requests_total{class="4xx"}`]),
+		ServerErrors: int64(snapshot[`http_requests_total{class="5xx"}`]),
+		InFlight:     int64(snapshot["http_requests_in_flight"]),
+		Shed:         int64(sumSeries(snapshot, "http_requests_shed_total")),
+	}
+	if requests.Total > 0 {
+		requests.ClientErrorRate = float64(requests.ClientErrors) / float64(requests.Total)
+		requests.ServerErrorRate = float64(requests.ServerErrors) / float64(requests.Total)
+	}
+
+	stat := h.pool.Stat()
+	database := DashboardPool{
+		MaxConns:          stat.MaxConns(),
+		TotalConns:        stat.TotalConns(),
+		AcquiredConns:     stat.AcquiredConns(),
+		IdleConns:         stat.IdleConns(),
+		AcquireCount:      stat.AcquireCount(),
+		EmptyAcquireCount: stat.EmptyAcquireCount(),
+	}
+	if n := stat.AcquireCount(); n > 0 {
+		database.AvgAcquireMs = float64(stat.AcquireDuration().Microseconds()) / float64(n) / 1000
+	}
+
+	dashboard := DashboardResponse{
+		GeneratedAt: time.Now().Format(time.RFC3339),
+		Requests:    requests,
+		Checkout: DashboardCheckout{
+			OrdersCreated: int64(snapshot["business_orders_created_total"]),
+			Stockouts:     int64(snapshot["business_stockouts_total"]),
+			FailureRate:   snapshot["business_checkout_failure_rate"],
+		},
+		Database:     database,
+		Redis:        h.redisMonitor.Status(),
+		Queues:       queues,
+		RecentOrders: orders,
+	}
+
+	if r.URL.Query().Get("format") == "json" {
+		response.Success(w, dashboard)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := dashboardTemplate.Execute(&buf, struct {
+		DashboardResponse
+		Refresh int
+	}{dashboard, dashboardRefresh}); err != nil {
+		response.Error(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// sumSeries adds up every series of a metric in a registry snapshot
+func sumSeries(snapshot map[string]float64, name string) float64 {
+	var total float64
+	for key, value := range snapshot {
+		if key == name || strings.HasPrefix(key, name+"{") {
+			total += value
+		}
+	}
+	return total
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"net/http"
+
+	"postgresDB/internal/delivery/middleware"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/validator"
+
+	"github.com/google/uuid"
+)
+
+type EmailTemplateHandler struct {
+	templateService service.EmailTemplateService
+}
+
+func NewEmailTemplateHandler(templateService service.EmailTemplateService) *EmailTemplateHandler {
+	return &EmailTemplateHandler{
+		templateService: templateService,
+	}
+}
+
+// Create handles adding a new draft version of an email template
+func (h *EmailTemplateHandler) Create(w http.ResponseWriter, r *http.Request) {
+	adminID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	var req dto.CreateEmailTemplateRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	template, err := h.templateService.Create(r.Context(), adminID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, template)
+}
+
+// List handles listing template versions, filtered by ?key= and ?locale=
+func (h *EmailTemplateHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var page dto.PageRequest
+	if err := bindQuery(r, &page); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	templates, meta, err := h.templateService.List(r.Context(), query.Get("key"), query.Get("locale"),
+		page.Page, page.Limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.SuccessWithMeta(w, templates, meta)
+}
+
+// Get handles retrieving a template version
+func (h *EmailTemplateHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID template tidak valid")
+		return
+	}
+
+	template, err := h.templateService.Get(r.Context(), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, template)
+}
+
+// Publish handles making a template version live
+func (h *EmailTemplateHandler) Publish(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID template tidak valid")
+		return
+	}
+
+	template, err := h.templateService.Publish(r.Context(), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, template)
+}
+
+// Preview handles rendering a template version with sample data, the body is optional
+func (h *EmailTemplateHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID template tidak valid")
+		return
+	}
+
+	var req dto.PreviewEmailTemplateRequest
+	if err := bindOptionalJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	rendered, err := h.templateService.Preview(r.Context(), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, rendered)
+}
+
+// SendTest handles mailing a template version to a test address
+func (h *EmailTemplateHandler) SendTest(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID template tidak valid")
+		return
+	}
+
+	var req dto.SendTestEmailRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := h.templateService.SendTest(r.Context(), id, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, map[string]string{"message": "Email percobaan terkirim"})
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/service"
+)
+
+type FieldUsageHandler struct {
+	fieldUsageService service.FieldUsageService
+}
+
+func NewFieldUsageHandler(fieldUsageService service.FieldUsageService) *FieldUsageHandler {
+	return &FieldUsageHandler{
+		fieldUsageService: fieldUsageService,
+	}
+}
+
+// Report handles the field usage report, filtered by ?route, ?field, ?client_id and
+// ?since (RFC 3339)
+func (h *FieldUsageHandler) Report(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := entities.FieldUsageFilter{
+		Route: query.Get("route"),
+		Field: query.Get("field"),
+	}
+	if query.Has("client_id") {
+		clientID := query.Get("client_id")
+		filter.ClientID = &clientID
+	}
+	if v := query.Get("since"); v != "" {
+		since, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			response.BadRequest(w, "since harus berformat RFC3339")
+			return
+		}
+		filter.Since = &since
+	}
+
+	report, err := h.fieldUsageService.Report(r.Context(), filter)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, report)
+}
@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"net/http"
+
+	"postgresDB/internal/delivery/response"
+)
+
+// Func is a handler returning its error instead of writing it. The error is written by
+// response.Error, so an AppError keeps its status and code and any other error is logged
+// and answered with 500, always in the JSON envelope
+type Func func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP calls f and writes the error it returns
+func (f Func) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f(w, r); err != nil {
+		response.Error(w, err)
+	}
+}
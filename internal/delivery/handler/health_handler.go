@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"postgresDB/internal/delivery/response"
+	"postgresDB/pkg/jwt"
+)
+
+// Pinger is implemented by dependencies that can be health checked (e.g. *pgxpool.Pool)
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PingFunc adapts a function to Pinger, e.g. the Ping of a Redis client
+type PingFunc func(ctx context.Context) error
+
+func (f PingFunc) Ping(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Readiness statuses
+const (
+	StatusReady    = "ready"
+	StatusDegraded = "degraded"
+	StatusNotReady = "not_ready"
+	StatusDraining = "draining"
+)
+
+type HealthHandler struct {
+	db                 Pinger
+	redis              Pinger
+	redisFailurePolicy string
+	timeout            time.Duration
+	draining           atomic.Bool
+}
+
+// NewHealthHandler creates a HealthHandler pinging every dependency for at most timeout
+func NewHealthHandler(db, redis Pinger, redisFailurePolicy string, timeout time.Duration) *HealthHandler {
+	return &HealthHandler{
+		db:                 db,
+		redis:              redis,
+		redisFailurePolicy: redisFailurePolicy,
+		timeout:            timeout,
+	}
+}
+
+// LivenessResponse represents the liveness check result
+type LivenessResponse struct {
+	Status string `json:"status"`
+}
+
+// DependencyCheck is the result of pinging a dependency
+type DependencyCheck struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// ReadinessResponse represents the readiness check result
+type ReadinessResponse struct {
+	Status             string                     `json:"status"`
+	Checks             map[string]DependencyCheck `json:"checks"`
+	RedisFailurePolicy string                     `json:"auth_redis_failure_policy"`
+}
+
+// Drain makes the readiness check fail from now on, so load balancers stop sending
+// traffic before the server shuts down
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
+
+// Live reports that the process is running, it checks no dependency so an outage of
+// one doesn't get the process restarted
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	response.JSON(w, http.StatusOK, LivenessResponse{Status: "alive"})
+}
+
+// Ready pings PostgreSQL and Redis and reports whether the service can handle traffic,
+// 503 when it can't. With Redis down the service is "degraded" when the auth failure
+// policy is fail_open, otherwise not ready. While draining for shutdown it's never ready
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	checks := h.check(r.Context())
+
+	status := StatusReady
+	if checks["redis"].Status != "up" {
+		if h.redisFailurePolicy == jwt.PolicyFailOpen {
+			status = StatusDegraded
+		} else {
+			status = StatusNotReady
+		}
+	}
+	if checks["postgres"].Status != "up" {
+		status = StatusNotReady
+	}
+	if h.draining.Load() {
+		status = StatusDraining
+	}
+
+	code := http.StatusOK
+	if status == StatusNotReady || status == StatusDraining {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	response.JSON(w, code, ReadinessResponse{Status: status, Checks: checks, RedisFailurePolicy: h.redisFailurePolicy})
+}
+
+// check pings the dependencies at once, each within the timeout
+func (h *HealthHandler) check(ctx context.Context) map[string]DependencyCheck {
+	dependencies := map[string]Pinger{"postgres": h.db, "redis": h.redis}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		checks = make(map[string]DependencyCheck, len(dependencies))
+	)
+	for name, dependency := range dependencies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := ping(ctx, dependency, h.timeout)
+			mu.Lock()
+			checks[name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return checks
+}
+
+// ping pings a dependency and measures how long it took
+func ping(ctx context.Context, dependency Pinger, timeout time.Duration) DependencyCheck {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := dependency.Ping(ctx)
+	check := DependencyCheck{
+		Status:    "up",
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		check.Status = "down"
+		check.Error = err.Error()
+	}
+	return check
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// streamHeartbeat keeps idle streams from being closed by proxies
+const streamHeartbeat = 25 * time.Second
+
+type InventoryHandler struct {
+	streamService service.InventoryStreamService
+}
+
+func NewInventoryHandler(streamService service.InventoryStreamService) *InventoryHandler {
+	return &InventoryHandler{
+		streamService: streamService,
+	}
+}
+
+// Stream handles streaming stock changes of ?product_id= as Server-Sent Events. The
+// stream opens with a "snapshot" event of the current stock, followed by a "stock"
+// event per change. A client that falls behind is disconnected and should reconnect
+func (h *InventoryHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	var productIDs []uuid.UUID
+	for _, raw := range parseListQuery(r, "product_id") {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			response.BadRequest(w, "product_id tidak valid")
+			return
+		}
+		productIDs = append(productIDs, id)
+	}
+
+	snapshot, events, err := h.streamService.Watch(r.Context(), productIDs)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	// the stream outlives the server write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		logger.WarnContext(r.Context(), "clearing stream write deadline failed", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if err := writeEvent(w, "snapshot", snapshot); err != nil || rc.Flush() != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, "stock", event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeEvent writes data as a JSON encoded Server-Sent Event
+func writeEvent(w io.Writer, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
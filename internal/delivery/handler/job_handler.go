@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+type JobHandler struct {
+	jobService service.JobService
+}
+
+func NewJobHandler(jobService service.JobService) *JobHandler {
+	return &JobHandler{
+		jobService: jobService,
+	}
+}
+
+// ListFailed handles listing the failed jobs of ?queue= with their payloads
+func (h *JobHandler) ListFailed(w http.ResponseWriter, r *http.Request) {
+	var page dto.PageRequest
+	if err := bindQuery(r, &page); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	jobs, meta, err := h.jobService.ListFailed(r.Context(), r.URL.Query().Get("queue"),
+		page.Page, page.Limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.SuccessWithMeta(w, jobs, meta)
+}
+
+// Get handles inspecting a job
+func (h *JobHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID job tidak valid")
+		return
+	}
+
+	job, err := h.jobService.Get(r.Context(), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, job)
+}
+
+// Retry handles putting a failed job back in its queue
+func (h *JobHandler) Retry(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID job tidak valid")
+		return
+	}
+
+	job, err := h.jobService.Retry(r.Context(), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, job)
+}
+
+// Cancel handles dropping a queued or failed job
+func (h *JobHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID job tidak valid")
+		return
+	}
+
+	job, err := h.jobService.Cancel(r.Context(), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, job)
+}
+
+// Stats handles the per-queue backlog and throughput over ?window= (Go duration, default 1h)
+func (h *JobHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	window := time.Hour
+	if v := r.URL.Query().Get("window"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			response.BadRequest(w, "window tidak valid")
+			return
+		}
+		window = d
+	}
+
+	stats, err := h.jobService.Stats(r.Context(), window)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, stats)
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"postgresDB/internal/delivery/response"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/pkg/logger"
+	"postgresDB/pkg/metrics"
+)
+
+type MetricsHandler struct {
+	registry *metrics.Registry
+	token    string
+}
+
+// NewMetricsHandler creates a handler exposing registry to Prometheus. When token is set
+// scrapers must send it as a bearer token, business metrics aren't meant to be public
+func NewMetricsHandler(registry *metrics.Registry, token string) *MetricsHandler {
+	return &MetricsHandler{
+		registry: registry,
+		token:    token,
+	}
+}
+
+// Metrics handles a scrape in the Prometheus text exposition format, or in OpenMetrics
+// when the scraper accepts it
+func (h *MetricsHandler) Metrics(w http.ResponseWriter, r *http.Request) {
+	if h.token != "" {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			response.Error(w, apperror.ErrUnauthorized)
+			return
+		}
+	}
+
+	// Prometheus asks for OpenMetrics first when it supports it
+	if strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text") {
+		w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+		if err := h.registry.WriteOpenMetrics(w); err != nil {
+			logger.WarnContext(r.Context(), "writing metrics failed", "error", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := h.registry.WriteText(w); err != nil {
+		logger.WarnContext(r.Context(), "writing metrics failed", "error", err)
+	}
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+
+	"postgresDB/internal/delivery/middleware"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/validator"
+)
+
+type NotificationHandler struct {
+	notificationService service.NotificationService
+}
+
+func NewNotificationHandler(notificationService service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// Preferences handles reading the notification preferences of the user
+func (h *NotificationHandler) Preferences(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	preferences, err := h.notificationService.Preferences(r.Context(), userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, preferences)
+}
+
+// UpdatePreferences handles turning notifications of the user on or off
+func (h *NotificationHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	var req dto.UpdateNotificationPreferencesRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	preferences, err := h.notificationService.UpdatePreferences(r.Context(), userID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, preferences)
+}
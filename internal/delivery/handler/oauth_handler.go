@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"postgresDB/internal/delivery/middleware"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/logger"
+	"postgresDB/pkg/validator"
+)
+
+type OAuthHandler struct {
+	oauthService service.OAuthService
+}
+
+func NewOAuthHandler(oauthService service.OAuthService) *OAuthHandler {
+	return &OAuthHandler{
+		oauthService: oauthService,
+	}
+}
+
+// Consent handles checking an authorization request sent as query parameters, the
+// consent page shows the client and scopes before the user approves
+func (h *OAuthHandler) Consent(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req := dto.AuthorizeRequest{
+		ResponseType:        q.Get("response_type"),
+		ClientID:            q.Get("client_id"),
+		RedirectURI:         q.Get("redirect_uri"),
+		Scope:               q.Get("scope"),
+		State:               q.Get("state"),
+		CodeChallenge:       q.Get("code_challenge"),
+		CodeChallengeMethod: q.Get("code_challenge_method"),
+		Nonce:               q.Get("nonce"),
+	}
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	consent, err := h.oauthService.Consent(r.Context(), &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, consent)
+}
+
+// Authorize handles the approval of an authorization request by the signed-in user,
+// the client is sent to the returned redirect URI
+func (h *OAuthHandler) Authorize(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	var req dto.AuthorizeRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	res, err := h.oauthService.Authorize(r.Context(), userID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, res)
+}
+
+// Token handles the OAuth token endpoint. The request is form encoded and the client
+// authenticates with HTTP basic auth or client_id and client_secret in the form
+func (h *OAuthHandler) Token(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, apperror.NewOAuthError(apperror.OAuthInvalidRequest, "form tidak valid"))
+		return
+	}
+
+	req := dto.OAuthTokenRequest{
+		GrantType:    r.PostForm.Get("grant_type"),
+		Code:         r.PostForm.Get("code"),
+		RedirectURI:  r.PostForm.Get("redirect_uri"),
+		CodeVerifier: r.PostForm.Get("code_verifier"),
+		RefreshToken: r.PostForm.Get("refresh_token"),
+		ClientID:     r.PostForm.Get("client_id"),
+		ClientSecret: r.PostForm.Get("client_secret"),
+	}
+	if username, password, ok := r.BasicAuth(); ok {
+		if req.ClientSecret != "" {
+			writeOAuthError(w, apperror.NewOAuthError(apperror.OAuthInvalidRequest, "gunakan satu metode autentikasi client"))
+			return
+		}
+		// basic auth credentials are form encoded (RFC 6749 section 2.3.1)
+		clientID, errID := url.QueryUnescape(username)
+		secret, errSecret := url.QueryUnescape(password)
+		if errID != nil || errSecret != nil || (req.ClientID != "" && req.ClientID != clientID) {
+			writeOAuthError(w, apperror.NewOAuthError(apperror.OAuthInvalidClient, "autentikasi client gagal"))
+			return
+		}
+		req.ClientID, req.ClientSecret = clientID, secret
+	}
+
+	res, err := h.oauthService.Token(r.Context(), &req)
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+	response.JSON(w, http.StatusOK, res)
+}
+
+// UserInfo handles the OpenID Connect userinfo endpoint, authenticated with an access
+// token issued to an OAuth client
+func (h *OAuthHandler) UserInfo(w http.ResponseWriter, r *http.Request) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		w.Header().Set("WWW-Authenticate", `Bearer`)
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	claims, err := h.oauthService.UserInfo(r.Context(), token)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrInvalidToken):
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		case errors.Is(err, apperror.ErrForbidden):
+			w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="openid"`)
+		}
+		response.Error(w, err)
+		return
+	}
+	response.JSON(w, http.StatusOK, claims)
+}
+
+// Discovery handles the OpenID Connect discovery document
+func (h *OAuthHandler) Discovery(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	response.JSON(w, http.StatusOK, h.oauthService.Discovery())
+}
+
+// JWKS handles the JSON Web Key Set ID tokens are verified with
+func (h *OAuthHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	response.JSON(w, http.StatusOK, h.oauthService.JWKS())
+}
+
+// writeOAuthError writes an error in the OAuth format of the token endpoint
+func writeOAuthError(w http.ResponseWriter, err error) {
+	oauthErr := apperror.AsOAuthError(err)
+	if oauthErr.Code == apperror.OAuthServerError {
+		logger.Error("OAuth token request failed", "error", err.Error(), "request_id", w.Header().Get(response.HeaderRequestID))
+	}
+	if oauthErr.Code == apperror.OAuthInvalidClient {
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+	}
+	response.JSON(w, oauthErr.HTTPStatus, oauthErr)
+}
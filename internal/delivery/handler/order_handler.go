@@ -1,47 +1,67 @@
 package handler
 
 import (
-	"encoding/json"
+	"bytes"
+	_ "embed"
+	"errors"
+	"html/template"
 	"net/http"
 	"postgresDB/internal/delivery/middleware"
 	"postgresDB/internal/delivery/response"
 	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
 	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/logger"
 	"postgresDB/pkg/validator"
+	"slices"
 
 	"github.com/google/uuid"
 )
 
+// maxPackingSlips limits how many orders can be printed in one batch
+const maxPackingSlips = 50
+
+//go:embed templates/packing_slip.html
+var packingSlipHTML string
+
+var packingSlipTemplate = template.Must(template.New("packing_slip").Parse(packingSlipHTML))
+
 type OrderHandler struct {
-	orderService service.OrderService
+	orderService   service.OrderService
+	paymentService service.PaymentService
 }
 
-func NewOrderHandler(orderService service.OrderService) *OrderHandler {
+func NewOrderHandler(orderService service.OrderService, paymentService service.PaymentService) *OrderHandler {
 	return &OrderHandler{
-		orderService: orderService,
+		orderService:   orderService,
+		paymentService: paymentService,
 	}
 }
 
 func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
-	// method check
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	userID, err := middleware.GetUserID(r.Context())
 	if err != nil {
 		response.BadRequest(w, "User tidak ditemukan")
 		return
 	}
 
-	var req dto.CreateOrderRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.BadRequest(w, "Invalid request body")
+	var body dto.CreateOrderRequestV1
+	if err := bindJSON(r, &body); err != nil {
+		response.Error(w, err)
+		return
+	}
+	if body.CustomerID != nil {
+		w.Header().Set("Warning", `299 - "customer_id is deprecated and ignored, the order is placed for the signed-in user"`)
+		logger.WarnContext(r.Context(), "deprecated customer_id sent on order creation", "user_id", userID, "client_id", r.Header.Get("X-Client-ID"))
+	}
+	req, err := body.ForCustomer(userID)
+	if err != nil {
+		response.Error(w, err)
 		return
 	}
 	if err := validator.ValidateStruct(&req); err != nil {
-		response.BadRequest(w, err.Error())
+		response.Error(w, err)
 		return
 	}
 
@@ -50,15 +70,77 @@ func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 		response.Error(w, err)
 		return
 	}
+	// the order stands without a payment, the customer can start it again later
+	payment, err := h.paymentService.CreateIntent(r.Context(), order.ID, userID, entities.RoleUser)
+	switch {
+	case err == nil:
+		order.Payment = payment
+	case !errors.Is(err, apperror.ErrPaymentUnavailable):
+		logger.WarnContext(r.Context(), "payment intent not created at checkout", "order_id", order.ID, "error", err)
+	}
 	response.Success(w, order)
 }
 
-func (h *OrderHandler) GetOrderByID(w http.ResponseWriter, r *http.Request) {
-	// method check
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// CreateGuestOrder handles placing an order without an account, no login required
+func (h *OrderHandler) CreateGuestOrder(w http.ResponseWriter, r *http.Request) {
+	var req dto.GuestOrderRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	order, err := h.orderService.CreateAsGuest(r.Context(), req)
+	if err != nil {
+		response.Error(w, err)
 		return
 	}
+	// the guest pays right away, the tracking link lets them pay or follow the order later
+	payment, err := h.paymentService.CreateIntent(r.Context(), order.ID, order.CustomerID, entities.RoleUser)
+	switch {
+	case err == nil:
+		order.Payment = payment
+	case !errors.Is(err, apperror.ErrPaymentUnavailable):
+		logger.WarnContext(r.Context(), "payment intent not created at guest checkout", "order_id", order.ID, "error", err)
+	}
+	response.Success(w, order)
+}
+
+// AddShipment handles recording a shipment of an order (admin)
+func (h *OrderHandler) AddShipment(w http.ResponseWriter, r *http.Request) {
+	adminID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID tidak valid")
+		return
+	}
+
+	var req dto.CreateShipmentRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	shipment, err := h.orderService.AddShipment(r.Context(), id, adminID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, shipment)
+}
+
+func (h *OrderHandler) GetOrderByID(w http.ResponseWriter, r *http.Request) {
 	UserID, err := middleware.GetUserID(r.Context())
 	if err != nil {
 		response.BadRequest(w, "User tidak ditemukan")
@@ -88,15 +170,44 @@ func (h *OrderHandler) GetOrderByID(w http.ResponseWriter, r *http.Request) {
 		response.Error(w, err)
 		return
 	}
+	payment, err := h.paymentService.GetByOrder(r.Context(), id)
+	if err != nil && !errors.Is(err, apperror.ErrPaymentNotFound) {
+		response.Error(w, err)
+		return
+	}
+	order.Payment = payment
 	response.Success(w, order)
 }
 
-func (h *OrderHandler) ListOrders(w http.ResponseWriter, r *http.Request) {
-	// method check
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// CustomerStats returns the order statistics of the customer in the path, to the
+// customer or an admin
+func (h *OrderHandler) CustomerStats(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+	userRole, err := middleware.GetUserRole(r.Context())
+	if err != nil {
+		response.BadRequest(w, "Role tidak ditemukan")
+		return
+	}
+
+	customerID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID user tidak valid")
 		return
 	}
+
+	stats, err := h.orderService.CustomerStats(r.Context(), customerID, userID, userRole)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, stats)
+}
+
+func (h *OrderHandler) ListOrders(w http.ResponseWriter, r *http.Request) {
 	//
 	userID, err := middleware.GetUserID(r.Context())
 	if err != nil {
@@ -110,14 +221,19 @@ func (h *OrderHandler) ListOrders(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	req := dto.OrderListRequest{
-		Page:   parseIntQuery(r, "page", 1),
-		Limit:  parseIntQuery(r, "limit", 10),
-		Status: r.URL.Query().Get("status"),
+	var req dto.OrderListRequest
+	if err := bindQuery(r, &req); err != nil {
+		response.Error(w, err)
+		return
 	}
 
-	if err := validator.ValidateStruct(&req); err != nil {
-		response.BadRequest(w, err.Error())
+	if req.Cursor != nil {
+		orders, meta, err := h.orderService.ListByCursor(r.Context(), userID, userRole, req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		response.SuccessWithMeta(w, orders, meta)
 		return
 	}
 
@@ -130,11 +246,6 @@ func (h *OrderHandler) ListOrders(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *OrderHandler) UpdateOrderStatus(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPatch {
-		response.BadRequest(w, "Method not allowed")
-		return
-	}
-
 	userID, err := middleware.GetUserID(r.Context())
 	if err != nil {
 		response.BadRequest(w, "User tidak ditemukan")
@@ -161,8 +272,8 @@ func (h *OrderHandler) UpdateOrderStatus(w http.ResponseWriter, r *http.Request)
 	}
 
 	var req dto.UpdateOrderRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.BadRequest(w, "Format JSON tidak valid")
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
 		return
 	}
 
@@ -180,15 +291,59 @@ func (h *OrderHandler) UpdateOrderStatus(w http.ResponseWriter, r *http.Request)
 	response.Success(w, order)
 }
 
-// parseOrderIntQuery parses an integer query parameter with a default value
-// func parseOrderIntQuery(r *http.Request, key string, defaultValue int) int {
-// 	val := r.URL.Query().Get(key)
-// 	if val == "" {
-// 		return defaultValue
-// 	}
-// 	intVal, err := strconv.Atoi(val)
-// 	if err != nil {
-// 		return defaultValue
-// 	}
-// 	return intVal
-// }
+// PackingSlip handles rendering the packing slip of a single order
+func (h *OrderHandler) PackingSlip(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID tidak valid")
+		return
+	}
+
+	h.writePackingSlips(w, r, []uuid.UUID{id})
+}
+
+// PackingSlips handles rendering the packing slips of several orders, ids=a,b,c
+func (h *OrderHandler) PackingSlips(w http.ResponseWriter, r *http.Request) {
+	raw := parseListQuery(r, "ids")
+	if len(raw) == 0 || len(raw) > maxPackingSlips {
+		response.BadRequest(w, "Parameter ids wajib diisi, maksimal %d order", maxPackingSlips)
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(raw))
+	for _, v := range raw {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			response.BadRequest(w, "ID tidak valid: %s", v)
+			return
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	h.writePackingSlips(w, r, ids)
+}
+
+// writePackingSlips renders packing slips as printable HTML, or as JSON with format=json
+func (h *OrderHandler) writePackingSlips(w http.ResponseWriter, r *http.Request, ids []uuid.UUID) {
+	slips, err := h.orderService.PackingSlips(r.Context(), ids)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "json" {
+		response.Success(w, slips)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := packingSlipTemplate.Execute(&buf, slips); err != nil {
+		response.Error(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
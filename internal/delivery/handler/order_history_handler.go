@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+type OrderHistoryHandler struct {
+	historyService service.OrderHistoryService
+}
+
+func NewOrderHistoryHandler(historyService service.OrderHistoryService) *OrderHistoryHandler {
+	return &OrderHistoryHandler{
+		historyService: historyService,
+	}
+}
+
+// AsOf handles showing an order as it was at time=, for settling disputes about what
+// the order said earlier
+func (h *OrderHistoryHandler) AsOf(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID order tidak valid")
+		return
+	}
+
+	var req dto.OrderAsOfRequest
+	if err := bindQuery(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	order, err := h.historyService.AsOf(r.Context(), id, req.Time)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, order)
+}
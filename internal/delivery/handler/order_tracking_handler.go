@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"net/http"
+
+	"postgresDB/internal/delivery/middleware"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/validator"
+
+	"github.com/google/uuid"
+)
+
+type OrderTrackingHandler struct {
+	trackingService service.OrderTrackingService
+}
+
+func NewOrderTrackingHandler(trackingService service.OrderTrackingService) *OrderTrackingHandler {
+	return &OrderTrackingHandler{
+		trackingService: trackingService,
+	}
+}
+
+// CreateLink handles creating a shareable tracking link for an order
+func (h *OrderTrackingHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+	userRole, err := middleware.GetUserRole(r.Context())
+	if err != nil {
+		response.BadRequest(w, "Role tidak ditemukan")
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID tidak valid")
+		return
+	}
+
+	link, err := h.trackingService.CreateLink(r.Context(), id, userID, userRole)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, link)
+}
+
+// RevokeLinks handles revoking all tracking links of an order
+func (h *OrderTrackingHandler) RevokeLinks(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+	userRole, err := middleware.GetUserRole(r.Context())
+	if err != nil {
+		response.BadRequest(w, "Role tidak ditemukan")
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID tidak valid")
+		return
+	}
+
+	if err := h.trackingService.RevokeLinks(r.Context(), id, userID, userRole); err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.NoContent(w)
+}
+
+// TrackOrder handles the tracking view of an order for its customer
+func (h *OrderTrackingHandler) TrackOrder(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+	userRole, err := middleware.GetUserRole(r.Context())
+	if err != nil {
+		response.BadRequest(w, "Role tidak ditemukan")
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID tidak valid")
+		return
+	}
+
+	tracking, err := h.trackingService.TrackOrder(r.Context(), id, userID, userRole)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, tracking)
+}
+
+// Track handles the public tracking page, no login required
+func (h *OrderTrackingHandler) Track(w http.ResponseWriter, r *http.Request) {
+	tracking, err := h.trackingService.Track(r.Context(), r.PathValue("token"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	response.Success(w, tracking)
+}
+
+// ClaimGuestOrders handles moving guest orders to the signed-in user with the token of
+// the tracking link emailed to the guest
+func (h *OrderTrackingHandler) ClaimGuestOrders(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	var req dto.ClaimGuestOrdersRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	claimed, err := h.trackingService.ClaimGuestOrders(r.Context(), userID, req.Token)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, claimed)
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net/http"
+
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/service"
+)
+
+type OutboxHandler struct {
+	outboxRelay service.OutboxRelay
+}
+
+func NewOutboxHandler(outboxRelay service.OutboxRelay) *OutboxHandler {
+	return &OutboxHandler{
+		outboxRelay: outboxRelay,
+	}
+}
+
+// Status handles showing the backlog of the outbox and the last publishing error
+func (h *OutboxHandler) Status(w http.ResponseWriter, r *http.Request) {
+	status, err := h.outboxRelay.Status(r.Context())
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, status)
+}
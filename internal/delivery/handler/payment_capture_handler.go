@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+type PaymentCaptureHandler struct {
+	captureService service.PaymentCaptureService
+}
+
+func NewPaymentCaptureHandler(captureService service.PaymentCaptureService) *PaymentCaptureHandler {
+	return &PaymentCaptureHandler{
+		captureService: captureService,
+	}
+}
+
+// Start handles starting the payment capture of a pending order
+func (h *PaymentCaptureHandler) Start(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID order tidak valid")
+		return
+	}
+
+	capture, err := h.captureService.Start(r.Context(), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, capture)
+}
+
+// Get handles getting the payment capture state of an order
+func (h *PaymentCaptureHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID order tidak valid")
+		return
+	}
+
+	capture, err := h.captureService.Get(r.Context(), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, capture)
+}
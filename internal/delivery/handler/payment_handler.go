@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"postgresDB/internal/delivery/middleware"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+// maxWebhookBody limits the size of a payment provider webhook
+const maxWebhookBody = 1 << 20
+
+type PaymentHandler struct {
+	paymentService service.PaymentService
+}
+
+func NewPaymentHandler(paymentService service.PaymentService) *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: paymentService,
+	}
+}
+
+// CreateIntent handles starting the payment of a pending order, returning the client
+// secret the customer completes the payment with
+func (h *PaymentHandler) CreateIntent(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+	role, err := middleware.GetUserRole(r.Context())
+	if err != nil {
+		response.BadRequest(w, "Role tidak ditemukan")
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID order tidak valid")
+		return
+	}
+
+	payment, err := h.paymentService.CreateIntent(r.Context(), id, userID, role)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, payment)
+}
+
+// Webhook handles payment provider notifications. The provider signs the raw body, so
+// it is read as is and handed to the provider to verify
+func (h *PaymentHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.BadRequest(w, "Payload webhook terlalu besar")
+			return
+		}
+		response.BadRequest(w, "Payload webhook tidak dapat dibaca")
+		return
+	}
+
+	if err := h.paymentService.HandleWebhook(r.Context(), payload, r.Header); err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, map[string]bool{"received": true})
+}
@@ -1,13 +1,16 @@
 package handler
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
-	"strconv"
+	"path/filepath"
+	"strings"
 
 	"postgresDB/internal/delivery/middleware"
 	"postgresDB/internal/delivery/response"
 	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
 	"postgresDB/internal/domain/service"
 
 	"postgresDB/pkg/validator"
@@ -15,6 +18,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// maxImportFileSize is the largest accepted product import file
+const maxImportFileSize = 10 << 20
+
 type ProductHandler struct {
 	productService service.ProductService
 }
@@ -26,21 +32,15 @@ func NewProductHandler(productService service.ProductService) *ProductHandler {
 }
 
 func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
-	// method check
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	var req dto.CreateProductRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.BadRequest(w, "Format JSON tidak valid")
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
 		return
 	}
 
 	// validation struct
 	if err := validator.ValidateStruct(&req); err != nil {
-		response.BadRequest(w, err.Error())
+		response.Error(w, err)
 		return
 	}
 
@@ -54,11 +54,6 @@ func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
-	// check mthod
-	if r.Method != http.MethodGet {
-		response.BadRequest(w, "Method not allowed")
-	}
-
 	// Extract ID from url path
 	idStr := r.PathValue("id")
 	if idStr == "" {
@@ -80,19 +75,43 @@ func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, product)
 }
 
+// GetByIDs handles looking up several products at once, for carts and checkout
+func (h *ProductHandler) GetByIDs(w http.ResponseWriter, r *http.Request) {
+	var req dto.BatchProductRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	result, err := h.productService.GetByIDs(r.Context(), req.IDs)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, result)
+}
+
 // List handles listing products
 func (h *ProductHandler) List(w http.ResponseWriter, r *http.Request) {
-	// check method
-	if r.Method != http.MethodGet {
-		response.BadRequest(w, "Method not allowed")
+	req := dto.ProductListRequest{Attributes: parseAttributeQuery(r)}
+	if err := bindQuery(r, &req); err != nil {
+		response.Error(w, err)
 		return
 	}
 
-	req := dto.ProductListRequest{
-		Page:     parseIntQuery(r, "page", 1),
-		Limit:    parseIntQuery(r, "limit", 10),
-		Search:   r.URL.Query().Get("search"),
-		Category: r.URL.Query().Get("category"),
+	if req.Cursor != nil {
+		products, meta, err := h.productService.ListByCursor(r.Context(), req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		response.SuccessWithMeta(w, products, meta)
+		return
 	}
 
 	products, meta, err := h.productService.List(r.Context(), req)
@@ -104,12 +123,6 @@ func (h *ProductHandler) List(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
-	// check method
-	if r.Method != http.MethodPut && r.Method != http.MethodPatch {
-		response.BadRequest(w, "Method not allowed")
-		return
-	}
-
 	// extract id from url path
 	idStr := r.PathValue("id")
 	if idStr == "" {
@@ -127,13 +140,13 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var req dto.UpdateProductRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.BadRequest(w, "Format JSON tidak valid")
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
 		return
 	}
 
 	if err := validator.ValidateStruct(&req); err != nil {
-		response.BadRequest(w, err.Error())
+		response.Error(w, err)
 		return
 	}
 
@@ -148,11 +161,6 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 // Delete handles deleting a product
 func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		response.BadRequest(w, "Method not allowed")
-		return
-	}
-
 	// Extract ID from URL path
 	idStr := r.PathValue("id")
 	if idStr == "" {
@@ -172,23 +180,231 @@ func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.productService.Delete(r.Context(), id, userRole); err != nil {
+	policy := entities.ProductDeletePolicy(r.URL.Query().Get("policy"))
+	if policy == "" {
+		policy = entities.ProductDeleteBlock
+	}
+	if !policy.IsValid() {
+		response.BadRequest(w, "Policy hapus produk tidak valid, gunakan block, archive atau cleanup")
+		return
+	}
+
+	report, err := h.productService.Delete(r.Context(), id, userRole, policy)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, report)
+}
+
+// ListArchived handles listing archived products
+func (h *ProductHandler) ListArchived(w http.ResponseWriter, r *http.Request) {
+	var page dto.PageRequest
+	if err := bindQuery(r, &page); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	products, meta, err := h.productService.ListArchived(r.Context(), page.Page, page.Limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.SuccessWithMeta(w, products, meta)
+}
+
+// ListLowStock handles listing products at or below their reorder threshold
+func (h *ProductHandler) ListLowStock(w http.ResponseWriter, r *http.Request) {
+	var page dto.PageRequest
+	if err := bindQuery(r, &page); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	products, meta, err := h.productService.ListLowStock(r.Context(), page.Page, page.Limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.SuccessWithMeta(w, products, meta)
+}
+
+// Stats handles the catalog statistics and the top sellers over ?from and ?to (RFC 3339
+// or YYYY-MM-DD, a date-only to includes that day)
+func (h *ProductHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	var req dto.ProductStatsRequest
+	if err := bindQuery(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	stats, err := h.productService.Stats(r.Context(), req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, stats)
+}
+
+// Forecast handles the demand forecast of ?product_id over ?weeks, averaging the latest
+// ?window weeks of sales
+func (h *ProductHandler) Forecast(w http.ResponseWriter, r *http.Request) {
+	var req dto.DemandForecastRequest
+	if err := bindQuery(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	forecast, err := h.productService.Forecast(r.Context(), req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, forecast)
+}
+
+// References handles previewing what deleting a product would affect
+func (h *ProductHandler) References(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID produk tidak valid")
+		return
+	}
+
+	refs, err := h.productService.References(r.Context(), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, refs)
+}
+
+// ListRelated handles listing the products related to a product
+func (h *ProductHandler) ListRelated(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID produk tidak valid")
+		return
+	}
+
+	var req dto.RelatedProductsRequest
+	if err := bindQuery(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	products, err := h.productService.ListRelated(r.Context(), id, req.Limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, products)
+}
+
+// Restore handles restoring an archived product
+func (h *ProductHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID produk tidak valid")
+		return
+	}
+
+	product, err := h.productService.Restore(r.Context(), id)
+	if err != nil {
 		response.Error(w, err)
 		return
 	}
+	response.Success(w, product)
+}
 
-	response.NoContent(w)
+// BulkUpdatePrices handles rule based price updates, with preview support
+func (h *ProductHandler) BulkUpdatePrices(w http.ResponseWriter, r *http.Request) {
+	var req dto.BulkPriceUpdateRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	result, err := h.productService.BulkUpdatePrices(r.Context(), &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, result)
 }
 
-// parseIntQuery parses an integer query parameter with a default value
-func parseIntQuery(r *http.Request, key string, defaultValue int) int {
-	val := r.URL.Query().Get(key)
-	if val == "" {
-		return defaultValue
+// Import handles bulk product import; the CSV or JSON file is sent in the "file" field
+func (h *ProductHandler) Import(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSize+1<<20)
+	if err := r.ParseMultipartForm(maxImportFileSize); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.Error(w, apperror.ErrFileTooLarge)
+			return
+		}
+		response.BadRequest(w, "Format multipart tidak valid")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		response.BadRequest(w, "File import wajib diisi")
+		return
+	}
+	defer file.Close()
+
+	// format defaults to the file extension, e.g. products.csv
+	format := strings.ToLower(r.FormValue("format"))
+	if format == "" {
+		format = strings.ToLower(strings.TrimPrefix(filepath.Ext(header.Filename), "."))
 	}
-	intVal, err := strconv.Atoi(val)
+
+	report, err := h.productService.Import(r.Context(), dto.ImportProductsRequest{
+		File:   file,
+		Format: format,
+	})
 	if err != nil {
-		return defaultValue
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, report)
+}
+
+// parseListQuery parses a comma separated query parameter, e.g. category=a,b
+func parseListQuery(r *http.Request, key string) []string {
+	values := make([]string, 0)
+	for _, raw := range r.URL.Query()[key] {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// parseAttributeQuery collects attribute filters sent as attr.<code>=<value> or
+// attr[<code>]=<value>
+func parseAttributeQuery(r *http.Request) map[string]string {
+	var attributes map[string]string
+	for key, values := range r.URL.Query() {
+		code, ok := strings.CutPrefix(key, "attr.")
+		if inner, bracketed := strings.CutPrefix(key, "attr["); !ok && bracketed {
+			code, ok = strings.CutSuffix(inner, "]")
+		}
+		if !ok || code == "" || len(values) == 0 {
+			continue
+		}
+		if attributes == nil {
+			attributes = make(map[string]string)
+		}
+		attributes[code] = values[0]
 	}
-	return intVal
+	return attributes
 }
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/validator"
+
+	"github.com/google/uuid"
+)
+
+type ProductImageHandler struct {
+	imageService  service.ProductImageService
+	maxUploadSize int64
+}
+
+func NewProductImageHandler(imageService service.ProductImageService, maxUploadSize int64) *ProductImageHandler {
+	return &ProductImageHandler{
+		imageService:  imageService,
+		maxUploadSize: maxUploadSize,
+	}
+}
+
+// Upload handles multipart image upload, the file is sent in the "image" field
+func (h *ProductImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID produk tidak valid")
+		return
+	}
+
+	// Leave some room for the multipart envelope and other form fields
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxUploadSize+1<<20)
+	if err := r.ParseMultipartForm(h.maxUploadSize); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.Error(w, apperror.ErrFileTooLarge)
+			return
+		}
+		response.BadRequest(w, "Format multipart tidak valid")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		response.BadRequest(w, "File image wajib diisi")
+		return
+	}
+	defer file.Close()
+
+	isPrimary, _ := strconv.ParseBool(r.FormValue("is_primary"))
+
+	image, err := h.imageService.Upload(r.Context(), productID, dto.UploadProductImageRequest{
+		File:      file,
+		Size:      header.Size,
+		IsPrimary: isPrimary,
+	})
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, image)
+}
+
+// List handles listing the gallery of a product
+func (h *ProductImageHandler) List(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID produk tidak valid")
+		return
+	}
+
+	images, err := h.imageService.List(r.Context(), productID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, images)
+}
+
+// SetPrimary handles marking an image as the primary image
+func (h *ProductImageHandler) SetPrimary(w http.ResponseWriter, r *http.Request) {
+	productID, imageID, ok := parseProductImageIDs(w, r)
+	if !ok {
+		return
+	}
+
+	images, err := h.imageService.SetPrimary(r.Context(), productID, imageID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, images)
+}
+
+// Reorder handles changing the order of the gallery
+func (h *ProductImageHandler) Reorder(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID produk tidak valid")
+		return
+	}
+
+	var req dto.ReorderProductImagesRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	images, err := h.imageService.Reorder(r.Context(), productID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, images)
+}
+
+// Delete handles deleting an image from the gallery
+func (h *ProductImageHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	productID, imageID, ok := parseProductImageIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.imageService.Delete(r.Context(), productID, imageID); err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.NoContent(w)
+}
+
+// parseProductImageIDs extracts the product and image IDs from the URL path
+func parseProductImageIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	productID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID produk tidak valid")
+		return uuid.Nil, uuid.Nil, false
+	}
+	imageID, err := uuid.Parse(r.PathValue("imageId"))
+	if err != nil {
+		response.BadRequest(w, "ID gambar tidak valid")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return productID, imageID, true
+}
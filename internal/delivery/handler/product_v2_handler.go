@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	dtov2 "postgresDB/internal/domain/dto/v2"
+	"postgresDB/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+// ProductV2Handler serves the product routes of /api/v2 with the same service as v1
+type ProductV2Handler struct {
+	productService service.ProductService
+	currency       string
+}
+
+// NewProductV2Handler creates a ProductV2Handler, prices are reported in currency
+func NewProductV2Handler(productService service.ProductService, currency string) *ProductV2Handler {
+	return &ProductV2Handler{
+		productService: productService,
+		currency:       currency,
+	}
+}
+
+// GetByID handles getting a product
+func (h *ProductV2Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID produk tidak valid")
+		return
+	}
+
+	product, err := h.productService.GetByID(r.Context(), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, dtov2.ToProductResponse(*product, h.currency))
+}
+
+// List handles listing products, with the filters, sorting and pagination of v1
+func (h *ProductV2Handler) List(w http.ResponseWriter, r *http.Request) {
+	req := dto.ProductListRequest{Attributes: parseAttributeQuery(r)}
+	if err := bindQuery(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if req.Cursor != nil {
+		products, meta, err := h.productService.ListByCursor(r.Context(), req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		response.SuccessWithMeta(w, dtov2.ToProductResponses(products, h.currency), meta)
+		return
+	}
+
+	products, meta, err := h.productService.List(r.Context(), req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.SuccessWithMeta(w, dtov2.ToProductResponses(products, h.currency), meta)
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"postgresDB/internal/delivery/middleware"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/validator"
+
+	"github.com/google/uuid"
+)
+
+// maxSettlementReportSize is the largest accepted settlement report upload
+const maxSettlementReportSize = 20 << 20
+
+type ReconciliationHandler struct {
+	reconciliationService service.ReconciliationService
+}
+
+func NewReconciliationHandler(reconciliationService service.ReconciliationService) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		reconciliationService: reconciliationService,
+	}
+}
+
+// UploadReport handles reconciling an uploaded settlement report. The CSV is sent in the
+// "file" field, the covered period in period_start and period_end (YYYY-MM-DD, end exclusive)
+func (h *ReconciliationHandler) UploadReport(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxSettlementReportSize+1<<20)
+	if err := r.ParseMultipartForm(maxSettlementReportSize); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.Error(w, apperror.ErrFileTooLarge)
+			return
+		}
+		response.BadRequest(w, "Format multipart tidak valid")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	periodStart, err := time.ParseInLocation(time.DateOnly, r.FormValue("period_start"), time.Local)
+	if err != nil {
+		response.BadRequest(w, "period_start tidak valid")
+		return
+	}
+	periodEnd, err := time.ParseInLocation(time.DateOnly, r.FormValue("period_end"), time.Local)
+	if err != nil {
+		response.BadRequest(w, "period_end tidak valid")
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		response.BadRequest(w, "File laporan wajib diisi")
+		return
+	}
+	defer file.Close()
+
+	run, err := h.reconciliationService.ImportReport(r.Context(), dto.UploadSettlementReportRequest{
+		File:        file,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+	})
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, run)
+}
+
+// ListRuns handles listing reconciliation runs
+func (h *ReconciliationHandler) ListRuns(w http.ResponseWriter, r *http.Request) {
+	var page dto.PageRequest
+	if err := bindQuery(r, &page); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	runs, meta, err := h.reconciliationService.ListRuns(r.Context(), page.Page, page.Limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.SuccessWithMeta(w, runs, meta)
+}
+
+// ListIssues handles listing the review queue, open issues by default
+func (h *ReconciliationHandler) ListIssues(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if !r.URL.Query().Has("status") {
+		status = "open"
+	} else if status == "all" {
+		status = ""
+	}
+
+	var page dto.PageRequest
+	if err := bindQuery(r, &page); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	issues, meta, err := h.reconciliationService.ListIssues(r.Context(), status, page.Page, page.Limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.SuccessWithMeta(w, issues, meta)
+}
+
+// ResolveIssue handles closing a review queue entry
+func (h *ReconciliationHandler) ResolveIssue(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID tidak valid")
+		return
+	}
+
+	adminID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	var req dto.ResolveReconciliationIssueRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	issue, err := h.reconciliationService.ResolveIssue(r.Context(), id, adminID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, issue)
+}
+
+// Summary handles the reconciliation summary: latest run and open issues per kind
+func (h *ReconciliationHandler) Summary(w http.ResponseWriter, r *http.Request) {
+	summary, err := h.reconciliationService.Summary(r.Context())
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, summary)
+}
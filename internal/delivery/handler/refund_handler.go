@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+
+	"postgresDB/internal/delivery/middleware"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/validator"
+
+	"github.com/google/uuid"
+)
+
+type RefundHandler struct {
+	refundService service.RefundService
+}
+
+func NewRefundHandler(refundService service.RefundService) *RefundHandler {
+	return &RefundHandler{
+		refundService: refundService,
+	}
+}
+
+// Refund handles refunding an order, an empty body refunds the rest of the order
+func (h *RefundHandler) Refund(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID order tidak valid")
+		return
+	}
+
+	adminID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	var req dto.RefundOrderRequest
+	if err := bindOptionalJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	refund, err := h.refundService.Refund(r.Context(), id, adminID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, refund)
+}
+
+// List handles listing the refunds of an order
+func (h *RefundHandler) List(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID order tidak valid")
+		return
+	}
+
+	refunds, err := h.refundService.List(r.Context(), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, refunds)
+}
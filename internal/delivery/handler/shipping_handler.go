@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"net/http"
+
+	"postgresDB/internal/delivery/middleware"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/validator"
+
+	"github.com/google/uuid"
+)
+
+type ShippingHandler struct {
+	addressService  service.AddressService
+	shippingService service.ShippingService
+}
+
+func NewShippingHandler(addressService service.AddressService, shippingService service.ShippingService) *ShippingHandler {
+	return &ShippingHandler{
+		addressService:  addressService,
+		shippingService: shippingService,
+	}
+}
+
+// Methods handles listing the shipping methods offered at checkout
+func (h *ShippingHandler) Methods(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, h.shippingService.Methods())
+}
+
+// ListAddresses handles listing the address book of the user
+func (h *ShippingHandler) ListAddresses(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	addresses, err := h.addressService.List(r.Context(), userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, addresses)
+}
+
+// CreateAddress handles adding an address to the address book of the user
+func (h *ShippingHandler) CreateAddress(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	var req dto.CreateAddressRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	address, err := h.addressService.Create(r.Context(), userID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, address)
+}
+
+// UpdateAddress handles changing an address of the user
+func (h *ShippingHandler) UpdateAddress(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID alamat tidak valid")
+		return
+	}
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	var req dto.UpdateAddressRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	address, err := h.addressService.Update(r.Context(), id, userID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, address)
+}
+
+// DeleteAddress handles removing an address of the user
+func (h *ShippingHandler) DeleteAddress(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID alamat tidak valid")
+		return
+	}
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	if err := h.addressService.Delete(r.Context(), id, userID); err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, map[string]string{"message": "Alamat berhasil dihapus"})
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"net/http"
+
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/service"
+)
+
+type SLOHandler struct {
+	sloService service.SLOService
+}
+
+func NewSLOHandler(sloService service.SLOService) *SLOHandler {
+	return &SLOHandler{
+		sloService: sloService,
+	}
+}
+
+// Report handles listing the service level objectives with their error budgets and burn rates
+func (h *SLOHandler) Report(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, h.sloService.Report())
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"postgresDB/internal/delivery/middleware"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/validator"
+
+	"github.com/google/uuid"
+)
+
+type StockCountHandler struct {
+	countService service.StockCountService
+}
+
+func NewStockCountHandler(countService service.StockCountService) *StockCountHandler {
+	return &StockCountHandler{
+		countService: countService,
+	}
+}
+
+// Create handles opening a stock count session
+func (h *StockCountHandler) Create(w http.ResponseWriter, r *http.Request) {
+	adminID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	var req dto.CreateStockCountRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	count, err := h.countService.Create(r.Context(), adminID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, count)
+}
+
+// List handles listing stock count sessions, optionally by status
+func (h *StockCountHandler) List(w http.ResponseWriter, r *http.Request) {
+	var req dto.StockCountListRequest
+	if err := bindQuery(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	counts, meta, err := h.countService.List(r.Context(), req.Status, req.Page, req.Limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.SuccessWithMeta(w, counts, meta)
+}
+
+// Get handles getting a stock count session with its variances
+func (h *StockCountHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID stock opname tidak valid")
+		return
+	}
+
+	count, err := h.countService.Get(r.Context(), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, count)
+}
+
+// RecordCounts handles saving counted quantities
+func (h *StockCountHandler) RecordCounts(w http.ResponseWriter, r *http.Request) {
+	id, adminID, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.RecordStockCountRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	count, err := h.countService.RecordCounts(r.Context(), id, adminID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, count)
+}
+
+// Submit handles sending a counted session for approval
+func (h *StockCountHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	id, adminID, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	count, err := h.countService.Submit(r.Context(), id, adminID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, count)
+}
+
+// Approve handles approving a submitted session, posting its variances
+func (h *StockCountHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, h.countService.Approve)
+}
+
+// Reject handles rejecting a session without changing the stock
+func (h *StockCountHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, h.countService.Reject)
+}
+
+// review runs an approval decision with an optional note in the body
+func (h *StockCountHandler) review(w http.ResponseWriter, r *http.Request, decide func(ctx context.Context, id, adminID uuid.UUID, req *dto.ReviewStockCountRequest) (*dto.StockCountResponse, error)) {
+	id, adminID, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.ReviewStockCountRequest
+	if err := bindOptionalJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	count, err := decide(r.Context(), id, adminID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, count)
+}
+
+// parseRequest returns the session ID in the path and the signed-in admin, writing the
+// error response when either is missing
+func (h *StockCountHandler) parseRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID stock opname tidak valid")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	adminID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return id, adminID, true
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+
+	"postgresDB/internal/delivery/middleware"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+type StockSubscriptionHandler struct {
+	subscriptionService service.StockSubscriptionService
+}
+
+func NewStockSubscriptionHandler(subscriptionService service.StockSubscriptionService) *StockSubscriptionHandler {
+	return &StockSubscriptionHandler{
+		subscriptionService: subscriptionService,
+	}
+}
+
+// Subscribe handles subscribing to an out-of-stock product
+func (h *StockSubscriptionHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	productID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID produk tidak valid")
+		return
+	}
+
+	subscription, err := h.subscriptionService.Subscribe(r.Context(), userID, productID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, subscription)
+}
+
+// Unsubscribe handles removing a back-in-stock subscription
+func (h *StockSubscriptionHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	productID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID produk tidak valid")
+		return
+	}
+
+	if err := h.subscriptionService.Unsubscribe(r.Context(), userID, productID); err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.NoContent(w)
+}
+
+// List handles listing the back-in-stock subscriptions of the user
+func (h *StockSubscriptionHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	var page dto.PageRequest
+	if err := bindQuery(r, &page); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	subscriptions, meta, err := h.subscriptionService.List(r.Context(), userID, page.Page, page.Limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.SuccessWithMeta(w, subscriptions, meta)
+}
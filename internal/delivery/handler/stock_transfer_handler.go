@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"postgresDB/internal/delivery/middleware"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/validator"
+
+	"github.com/google/uuid"
+)
+
+type StockTransferHandler struct {
+	transferService service.StockTransferService
+}
+
+func NewStockTransferHandler(transferService service.StockTransferService) *StockTransferHandler {
+	return &StockTransferHandler{
+		transferService: transferService,
+	}
+}
+
+// Create handles creating a draft stock transfer
+func (h *StockTransferHandler) Create(w http.ResponseWriter, r *http.Request) {
+	adminID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	var req dto.CreateStockTransferRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	transfer, err := h.transferService.Create(r.Context(), adminID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, transfer)
+}
+
+// List handles listing stock transfers, open ones by default
+func (h *StockTransferHandler) List(w http.ResponseWriter, r *http.Request) {
+	var req dto.StockTransferListRequest
+	if err := bindQuery(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+	if req.Status == "all" {
+		req.Status = ""
+	}
+
+	transfers, meta, err := h.transferService.List(r.Context(), req.Status, req.Page, req.Limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.SuccessWithMeta(w, transfers, meta)
+}
+
+// Get handles getting a stock transfer with its items
+func (h *StockTransferHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID transfer tidak valid")
+		return
+	}
+
+	transfer, err := h.transferService.Get(r.Context(), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, transfer)
+}
+
+// Ship handles shipping a draft transfer out of the source warehouse
+func (h *StockTransferHandler) Ship(w http.ResponseWriter, r *http.Request) {
+	h.advance(w, r, h.transferService.Ship)
+}
+
+// Receive handles receiving a transfer in transit at the destination warehouse
+func (h *StockTransferHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	h.advance(w, r, h.transferService.Receive)
+}
+
+// Cancel handles cancelling a draft transfer
+func (h *StockTransferHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	h.advance(w, r, func(ctx context.Context, id, _ uuid.UUID) (*dto.StockTransferResponse, error) {
+		return h.transferService.Cancel(ctx, id)
+	})
+}
+
+// advance runs a status change of the transfer in the path on behalf of the admin
+func (h *StockTransferHandler) advance(w http.ResponseWriter, r *http.Request, action func(ctx context.Context, id, adminID uuid.UUID) (*dto.StockTransferResponse, error)) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID transfer tidak valid")
+		return
+	}
+
+	adminID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	transfer, err := action(r.Context(), id, adminID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, transfer)
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"net/http"
+
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/validator"
+
+	"github.com/google/uuid"
+)
+
+type TagHandler struct {
+	tagService service.TagService
+}
+
+func NewTagHandler(tagService service.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+// Create handles creating a tag
+func (h *TagHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateTagRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	tag, err := h.tagService.Create(r.Context(), &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, tag)
+}
+
+// List handles listing all tags
+func (h *TagHandler) List(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.tagService.List(r.Context())
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, tags)
+}
+
+// Update handles updating a tag
+func (h *TagHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID tag tidak valid")
+		return
+	}
+
+	var req dto.UpdateTagRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	tag, err := h.tagService.Update(r.Context(), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, tag)
+}
+
+// Delete handles deleting a tag
+func (h *TagHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID tag tidak valid")
+		return
+	}
+
+	if err := h.tagService.Delete(r.Context(), id); err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.NoContent(w)
+}
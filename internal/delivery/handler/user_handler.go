@@ -1,11 +1,11 @@
 package handler
 
 import (
-	"encoding/json"
 	"net/http"
 	"postgresDB/internal/delivery/middleware"
 	"postgresDB/internal/delivery/response"
 	"postgresDB/internal/domain/dto"
+	apperror "postgresDB/internal/domain/errors"
 	"postgresDB/internal/domain/service"
 	"postgresDB/pkg/validator"
 
@@ -22,20 +22,20 @@ func NewUserHandler(userService service.UserService) *UserHandler {
 	}
 }
 
-func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
-	// check method
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+// userID parses the id path value
+func userID(r *http.Request) (uuid.UUID, error) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		return uuid.Nil, apperror.NewAppError(apperror.CodeBadRequest, "ID user tidak valid", http.StatusBadRequest)
 	}
+	return id, nil
+}
 
-	// get user id from context
-	idStr := r.PathValue("id")
-	id, err := uuid.Parse(idStr)
+func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) error {
+	// get user id from path
+	id, err := userID(r)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
-		response.BadRequest(w, "ID user tidak valid")
-		return
+		return err
 	}
 
 	// get requester id and role from context
@@ -45,40 +45,28 @@ func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	// call service
 	user, err := h.userService.GetUser(r.Context(), id, requesterID, requesterRole)
 	if err != nil {
-		// More specific error handling could be added here
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		//response.Error(w, err)
-		return
+		return err
 	}
 	response.Success(w, user)
+	return nil
 }
 
-func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
-	// check method
-	if r.Method != http.MethodPut && r.Method != http.MethodPatch {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
+func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) error {
 	// get user id from path
-	idStr := r.PathValue("id")
-	id, err := uuid.Parse(idStr)
+	id, err := userID(r)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
-		return
+		return err
 	}
 
 	// parse request body
 	var req dto.UpdateUserRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
+	if err := bindJSON(r, &req); err != nil {
+		return err
 	}
 
 	// validate request
 	if err := validator.ValidateStruct(&req); err != nil {
-		response.Error(w, err)
-		return
+		return err
 	}
 
 	// get requester id and role from context
@@ -88,74 +76,68 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	// call service
 	updatedUser, err := h.userService.Update(r.Context(), id, requesterID, requesterRole, req)
 	if err != nil {
-		response.Error(w, err)
-		return
+		return err
 	}
 	response.Success(w, updatedUser)
+	return nil
 }
 
-func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
-	// check method
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) error {
 	// get user id from path
-	idStr := r.PathValue("id")
-	id, err := uuid.Parse(idStr)
+	id, err := userID(r)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
-		return
+		return err
 	}
 	// parse request body
 	var req dto.ChangePasswordRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
+	if err := bindJSON(r, &req); err != nil {
+		return err
 	}
 
 	if err := validator.ValidateStruct(&req); err != nil {
-		response.Error(w, err)
-		return
+		return err
 	}
 
 	// retrieve requester ID from context
 	requesterID, _ := middleware.GetUserID(r.Context())
 
 	// call service
-	err = h.userService.ChangePassword(r.Context(), id, requesterID, req)
-	if err != nil {
-		response.Error(w, err)
-		return
+	if err := h.userService.ChangePassword(r.Context(), id, requesterID, req); err != nil {
+		return err
 	}
 	response.Success(w, map[string]string{"message": "Password changed successfully"})
+	return nil
 }
 
-func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
-	// check method
-	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-	idStr := r.PathValue("id")
-	id, err := uuid.Parse(idStr)
+func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) error {
+	id, err := userID(r)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
-		return
+		return err
 	}
 
 	// retrieve requester ID from context
 	requesterID, _ := middleware.GetUserID(r.Context())
 	requesterRole, _ := middleware.GetUserRole(r.Context())
 
-	// ensure requester is deleting their own account
-
 	// call service
-	err = h.userService.Delete(r.Context(), id, requesterID, requesterRole)
-	if err != nil {
-		response.Error(w, err)
-		return
+	if err := h.userService.Delete(r.Context(), id, requesterID, requesterRole); err != nil {
+		return err
 	}
 	response.NoContent(w)
+	return nil
+}
+
+// RestoreUser handles restoring a deleted user
+func (h *UserHandler) RestoreUser(w http.ResponseWriter, r *http.Request) error {
+	id, err := userID(r)
+	if err != nil {
+		return err
+	}
+
+	user, err := h.userService.Restore(r.Context(), id)
+	if err != nil {
+		return err
+	}
+	response.Success(w, user)
+	return nil
 }
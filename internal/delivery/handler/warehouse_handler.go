@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"net/http"
+
+	"postgresDB/internal/delivery/middleware"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/validator"
+
+	"github.com/google/uuid"
+)
+
+type WarehouseHandler struct {
+	warehouseService service.WarehouseService
+}
+
+func NewWarehouseHandler(warehouseService service.WarehouseService) *WarehouseHandler {
+	return &WarehouseHandler{
+		warehouseService: warehouseService,
+	}
+}
+
+// Create handles creating a warehouse
+func (h *WarehouseHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateWarehouseRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	warehouse, err := h.warehouseService.Create(r.Context(), &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, warehouse)
+}
+
+// List handles listing all warehouses
+func (h *WarehouseHandler) List(w http.ResponseWriter, r *http.Request) {
+	warehouses, err := h.warehouseService.List(r.Context())
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Success(w, warehouses)
+}
+
+// ListStock handles listing the stock of a warehouse per product
+func (h *WarehouseHandler) ListStock(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID gudang tidak valid")
+		return
+	}
+
+	var page dto.PageRequest
+	if err := bindQuery(r, &page); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	stock, meta, err := h.warehouseService.ListStock(r.Context(), id, page.Page, page.Limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.SuccessWithMeta(w, stock, meta)
+}
+
+// Adjust handles correcting the stock of a warehouse
+func (h *WarehouseHandler) Adjust(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.BadRequest(w, "ID gudang tidak valid")
+		return
+	}
+
+	adminID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		response.BadRequest(w, "User tidak ditemukan")
+		return
+	}
+
+	var req dto.StockAdjustmentRequest
+	if err := bindJSON(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	entries, err := h.warehouseService.Adjust(r.Context(), id, adminID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, entries)
+}
+
+// ListLedger handles listing stock ledger entries, filtered by warehouse_id, product_id,
+// reference_id and the from/to posting dates
+func (h *WarehouseHandler) ListLedger(w http.ResponseWriter, r *http.Request) {
+	var req dto.StockLedgerListRequest
+	if err := bindQuery(r, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	entries, meta, err := h.warehouseService.ListLedger(r.Context(), req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.SuccessWithMeta(w, entries, meta)
+}
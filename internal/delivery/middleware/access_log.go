@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/service"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// accessLogKey holds the access log entry of the request, so Auth can fill in the user
+const accessLogKey contextKey = "access_log"
+
+// maxClientIDLength is the longest X-Client-ID kept, longer values are cut
+const maxClientIDLength = 100
+
+// AccessLog records every request with the recorder, tagged with the X-Client-ID header
+// and the authenticated user for per-client exports
+func AccessLog(recorder service.AccessLogRecorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			clientID := r.Header.Get("X-Client-ID")
+			if len(clientID) > maxClientIDLength {
+				clientID = clientID[:maxClientIDLength]
+			}
+			entry := &entities.AccessLog{
+				At:        start,
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				IP:        ClientIP(r),
+				ClientID:  clientID,
+				UserAgent: r.UserAgent(),
+			}
+
+			rw := &responseWriter{
+				ResponseWriter: w,
+				status:         http.StatusOK,
+			}
+			next.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), accessLogKey, entry)))
+
+			entry.Status = rw.status
+			entry.Size = int64(rw.size)
+			entry.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+			recorder.Record(*entry)
+		})
+	}
+}
+
+// setAccessLogUser attributes the access log entry of the request to the user
+func setAccessLogUser(ctx context.Context, userID uuid.UUID) {
+	if entry, ok := ctx.Value(accessLogKey).(*entities.AccessLog); ok {
+		entry.UserID = &userID
+	}
+}
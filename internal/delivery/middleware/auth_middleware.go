@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"postgresDB/internal/delivery/response"
 	"postgresDB/internal/domain/entities"
@@ -21,6 +22,8 @@ const (
 	UserRoleKey contextKey = "user_role"
 	TokenJTIKey contextKey = "token_jti"
 	TokenExpKey contextKey = "token_exp"
+	// TokenFamilyKey holds the session the access token belongs to
+	TokenFamilyKey contextKey = "token_family"
 )
 
 // Auth Middleware validates authentication and authorization JWT tokens
@@ -44,6 +47,10 @@ func Auth(jwtService *jwt.JWTService) func(http.Handler) http.Handler {
 			// Validate token
 			claims, err := jwtService.ValidateAccessToken(r.Context(), tokenString)
 			if err != nil {
+				if errors.Is(err, jwt.ErrTokenStoreUnavailable) {
+					response.Error(w, apperror.ErrServiceUnavailable)
+					return
+				}
 				response.Error(w, apperror.ErrInvalidToken)
 				return
 			}
@@ -54,6 +61,8 @@ func Auth(jwtService *jwt.JWTService) func(http.Handler) http.Handler {
 			ctx = context.WithValue(ctx, UserRoleKey, claims.Role)
 			ctx = context.WithValue(ctx, TokenJTIKey, claims.ID)
 			ctx = context.WithValue(ctx, TokenExpKey, claims.ExpiresAt.Time)
+			ctx = context.WithValue(ctx, TokenFamilyKey, claims.TokenFamily)
+			setAccessLogUser(ctx, claims.UserID)
 
 			// Call next handler with updated context
 			next.ServeHTTP(w, r.WithContext(ctx))
@@ -113,6 +122,15 @@ func GetTokenJTIFromContext(ctx context.Context) (string, error) {
 	return jti, nil
 }
 
+// GetTokenFamily retrieves the session of the access token from context
+func GetTokenFamily(ctx context.Context) (string, error) {
+	family, ok := ctx.Value(TokenFamilyKey).(string)
+	if !ok || family == "" {
+		return "", apperror.ErrUnauthorized
+	}
+	return family, nil
+}
+
 // GetTokenExpFromContext retrieves token expiration time from context
 func GetTokenExpFromContext(ctx context.Context) (time.Time, error) {
 	exp, ok := ctx.Value(TokenExpKey).(time.Time)
@@ -0,0 +1,36 @@
+package middleware
+
+import "net/http"
+
+// Middleware wraps a handler with behaviour of its own
+type Middleware func(http.Handler) http.Handler
+
+// Stack is an ordered list of named middlewares. The first one added is the outermost: it
+// sees the request first and the response last
+type Stack struct {
+	names       []string
+	middlewares []Middleware
+}
+
+// Use appends mw to the stack under name, a nil mw is left out
+func (s *Stack) Use(name string, mw Middleware) *Stack {
+	if mw == nil {
+		return s
+	}
+	s.names = append(s.names, name)
+	s.middlewares = append(s.middlewares, mw)
+	return s
+}
+
+// Names returns the names of the middlewares in the stack, outermost first
+func (s *Stack) Names() []string {
+	return append([]string(nil), s.names...)
+}
+
+// Then wraps h with every middleware of the stack
+func (s *Stack) Then(h http.Handler) http.Handler {
+	for i := len(s.middlewares) - 1; i >= 0; i-- {
+		h = s.middlewares[i](h)
+	}
+	return h
+}
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"postgresDB/internal/delivery/response"
+	apperror "postgresDB/internal/domain/errors"
+	"strings"
+)
+
+// clientIPKey holds the client IP resolved by RealIP
+const clientIPKey contextKey = "client_ip"
+
+// RealIP middleware resolves the IP of the client behind trusted proxies. X-Forwarded-For
+// is only read when the direct peer is in trusted, and then from the right: the first
+// address that isn't a trusted proxy is the client. Anyone can send the header, so the
+// addresses left of that one are ignored
+func RealIP(trusted []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := resolveClientIP(r, trusted)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientIPKey, ip)))
+		})
+	}
+}
+
+// resolveClientIP walks X-Forwarded-For from the peer towards the client while the hops
+// are trusted proxies
+func resolveClientIP(r *http.Request, trusted []netip.Prefix) string {
+	peer := peerIP(r)
+	client, err := netip.ParseAddr(peer)
+	if err != nil || !containsAddr(trusted, client.Unmap()) {
+		return peer
+	}
+
+	var hops []string
+	for _, value := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(value, ",")...)
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// a malformed hop can't be followed, the last proxy is the closest known client
+			break
+		}
+		client = hop.Unmap()
+		if !containsAddr(trusted, client) {
+			break
+		}
+	}
+	return client.String()
+}
+
+// ClientIP returns the IP of the client as resolved by RealIP, or the IP of the direct
+// peer, without the port, when RealIP didn't run
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey).(string); ok {
+		return ip
+	}
+	return peerIP(r)
+}
+
+// peerIP returns the IP of the direct peer, without the port
+func peerIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// IPFilter middleware rejects requests from clients in deny with 403, and requests to
+// restricted routes from clients outside allow. It must run after RealIP
+func IPFilter(deny, allow []netip.Prefix, restricted func(*http.Request) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := ClientIP(r)
+			addr, err := netip.ParseAddr(ip)
+			if err == nil {
+				addr = addr.Unmap()
+			}
+			denied := err == nil && containsAddr(deny, addr)
+			if !denied && restricted(r) {
+				denied = err != nil || !containsAddr(allow, addr)
+			}
+			if denied {
+				slog.WarnContext(r.Context(), "request rejected by IP filter",
+					slog.String("ip", ip),
+					slog.String("path", r.URL.Path),
+				)
+				response.Error(w, apperror.ErrIPForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ParsePrefixes parses IP addresses and CIDR ranges such as 10.0.0.0/8, returning the
+// entries that are neither
+func ParsePrefixes(entries []string) ([]netip.Prefix, []string) {
+	var prefixes []netip.Prefix
+	var invalid []string
+	for _, entry := range entries {
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(entry); err == nil {
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+		} else {
+			invalid = append(invalid, entry)
+		}
+	}
+	return prefixes, invalid
+}
+
+// containsAddr reports whether any of prefixes contains addr
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,239 @@
+package middleware
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// encoder is a pooled gzip or zlib writer
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// compressedTypes are media types already compressed, compressing them again only costs CPU
+var compressedTypes = map[string]bool{
+	"application/gzip":             true,
+	"application/x-gzip":           true,
+	"application/zip":              true,
+	"application/zstd":             true,
+	"application/x-7z-compressed":  true,
+	"application/x-rar-compressed": true,
+	"application/pdf":              true,
+}
+
+// Compress middleware compresses successful responses with gzip or deflate, whichever the
+// Accept-Encoding of the client prefers. Bodies under minSize bytes, bodies with a
+// Content-Encoding of their own and already compressed media types, such as images, are
+// sent as they are. level is a compress/flate level, out of range levels use the default
+func Compress(level, minSize int) func(http.Handler) http.Handler {
+	if level < flate.HuffmanOnly || level > flate.BestCompression {
+		level = flate.DefaultCompression
+	}
+	pools := map[string]*sync.Pool{
+		"gzip": {New: func() any {
+			zw, _ := gzip.NewWriterLevel(io.Discard, level)
+			return zw
+		}},
+		// the deflate coding is the zlib format, not raw deflate
+		"deflate": {New: func() any {
+			zw, _ := zlib.NewWriterLevel(io.Discard, level)
+			return zw
+		}},
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{
+				ResponseWriter: w,
+				encoding:       encoding,
+				pool:           pools[encoding],
+				minSize:        minSize,
+				status:         http.StatusOK,
+			}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding returns gzip or deflate, whichever the Accept-Encoding header gives
+// the higher quality, gzip on a tie; "" when the client accepts neither
+func negotiateEncoding(header string) string {
+	quality := map[string]float64{}
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		switch coding {
+		case "gzip", "x-gzip":
+			quality["gzip"] = q
+		case "deflate":
+			quality["deflate"] = q
+		case "*":
+			for _, name := range []string{"gzip", "deflate"} {
+				if _, ok := quality[name]; !ok {
+					quality[name] = q
+				}
+			}
+		}
+	}
+
+	best, bestQ := "", 0.0
+	for _, name := range []string{"gzip", "deflate"} {
+		if q := quality[name]; q > bestQ {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+// compressWriter holds the body back until minSize bytes are written, or the handler
+// returns or flushes, and then decides whether to compress it
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	pool     *sync.Pool
+	minSize  int
+
+	status      int
+	wroteHeader bool
+	started     bool
+	buf         []byte
+	enc         encoder
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = code
+	if !cw.compressible() {
+		cw.start(false)
+	}
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.started {
+		if cw.enc != nil {
+			return cw.enc.Write(b)
+		}
+		return cw.ResponseWriter.Write(b)
+	}
+
+	cw.buf = append(cw.buf, b...)
+	if len(cw.buf) >= cw.minSize {
+		if err := cw.start(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// Flush sends what's held back, compressed since a flushing handler is streaming more
+func (cw *compressWriter) Flush() {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if !cw.started {
+		cw.start(true)
+	}
+	if cw.enc != nil {
+		cw.enc.Flush()
+	}
+	http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying ResponseWriter (used by http.ResponseController)
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// compressible reports whether the status and headers of the response allow compressing it
+func (cw *compressWriter) compressible() bool {
+	if cw.status < http.StatusOK || cw.status >= http.StatusMultipleChoices ||
+		cw.status == http.StatusNoContent || cw.status == http.StatusPartialContent {
+		return false
+	}
+	h := cw.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(h.Get("Content-Type"))
+	return !compressedTypes[mediaType] && !strings.HasPrefix(mediaType, "video/") &&
+		!strings.HasPrefix(mediaType, "audio/") &&
+		(!strings.HasPrefix(mediaType, "image/") || mediaType == "image/svg+xml")
+}
+
+// start writes the header, compressed when compress is set and the response allows it,
+// and the body held back so far
+func (cw *compressWriter) start(compress bool) error {
+	cw.started = true
+	h := cw.Header()
+	if h.Get("Content-Type") == "" && len(cw.buf) > 0 {
+		// sniffed here, net/http would sniff the compressed bytes
+		h.Set("Content-Type", http.DetectContentType(cw.buf))
+	}
+	if compress && cw.compressible() {
+		h.Set("Content-Encoding", cw.encoding)
+		h.Del("Content-Length")
+		cw.enc = cw.pool.Get().(encoder)
+		cw.enc.Reset(cw.ResponseWriter)
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if cw.enc != nil {
+		_, err := cw.enc.Write(buf)
+		return err
+	}
+	_, err := cw.ResponseWriter.Write(buf)
+	return err
+}
+
+// close sends the body held back by a response under minSize, or ends the compressed
+// stream, once the handler returned
+func (cw *compressWriter) close() {
+	if !cw.started {
+		if !cw.wroteHeader {
+			return
+		}
+		cw.start(false)
+	}
+	if cw.enc != nil {
+		cw.enc.Close()
+		cw.enc.Reset(io.Discard)
+		cw.pool.Put(cw.enc)
+		cw.enc = nil
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// corsAllowedHeaders are the request headers browsers may send cross-origin
+var corsAllowedHeaders = []string{
+	"Accept", "Authorization", "Content-Type", HeaderIdempotencyKey,
+	"X-Client-ID", "X-Fields-Read", "X-Request-ID", "X-Response-Style",
+	"X-Request-Nonce", "X-Request-Signature", "X-Request-Timestamp",
+}
+
+// corsExposedHeaders are the response headers scripts of another origin may read
+var corsExposedHeaders = []string{
+	"X-Request-ID", "X-Page", "X-Limit", "X-Total-Count", "X-Total-Pages", "X-Total-Estimated",
+	"X-Next-Cursor", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After",
+	"Deprecation", "Sunset", "Link", "Idempotent-Replayed",
+}
+
+// corsAllowedMethods are the methods of the API
+var corsAllowedMethods = []string{
+	http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+}
+
+// CORSPolicy sets which browser origins may call the API
+type CORSPolicy struct {
+	// AllowedOrigins are the origins allowed, such as https://shop.example.com; "*" allows
+	// any origin
+	AllowedOrigins []string
+	// AllowCredentials lets browsers send cookies and read responses to them, the origin
+	// is then echoed rather than "*"
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache the answer to a preflight request
+	MaxAge time.Duration
+}
+
+// CORS middleware adds the CORS headers to the responses to an allowed origin and answers
+// its preflight requests. Requests of other origins pass through without the headers, so
+// the browser keeps the response from the page
+func CORS(policy CORSPolicy) func(http.Handler) http.Handler {
+	anyOrigin := slices.Contains(policy.AllowedOrigins, "*")
+	methods := strings.Join(corsAllowedMethods, ", ")
+	headers := strings.Join(corsAllowedHeaders, ", ")
+	exposed := strings.Join(corsExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(policy.MaxAge.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Add("Vary", "Origin")
+			if !anyOrigin && !slices.Contains(policy.AllowedOrigins, origin) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			if anyOrigin && !policy.AllowCredentials {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+			if policy.AllowCredentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				h.Add("Vary", "Access-Control-Request-Method")
+				h.Add("Vary", "Access-Control-Request-Headers")
+				h.Set("Access-Control-Allow-Methods", methods)
+				h.Set("Access-Control-Allow-Headers", headers)
+				if policy.MaxAge > 0 {
+					h.Set("Access-Control-Max-Age", maxAge)
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			h.Set("Access-Control-Expose-Headers", exposed)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// DeprecationInfo describes a deprecated endpoint
+type DeprecationInfo struct {
+	// Since is the time the endpoint was deprecated
+	Since time.Time
+	// Sunset is the time the endpoint will be removed (optional)
+	Sunset time.Time
+	// Successor is the path of the replacement endpoint (optional)
+	Successor string
+}
+
+// Deprecated middleware adds Deprecation, Sunset and Link headers to the response
+// and logs usage of the endpoint by client so it can be retired safely
+func Deprecated(info DeprecationInfo) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			setDeprecationHeaders(w, info)
+
+			slog.WarnContext(r.Context(), "Deprecated endpoint called",
+				slog.String("method", r.Method),
+				slog.String("pattern", r.Pattern),
+				slog.String("path", r.URL.Path),
+				slog.String("client_id", r.Header.Get("X-Client-ID")),
+				slog.String("user_agent", r.UserAgent()),
+				slog.String("ip", ClientIP(r)),
+			)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// DeprecatedWhen middleware adds the headers of Deprecated to the responses of the
+// requests info reports deprecated, for retiring a whole group of routes such as an API
+// version. It doesn't log every call, access log exports show the clients still calling
+func DeprecatedWhen(info func(*http.Request) (DeprecationInfo, bool)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if deprecation, ok := info(r); ok {
+				setDeprecationHeaders(w, deprecation)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// setDeprecationHeaders sets the Deprecation, Sunset and Link headers of info
+func setDeprecationHeaders(w http.ResponseWriter, info DeprecationInfo) {
+	// Deprecation header uses structured date format (RFC 9745)
+	if info.Since.IsZero() {
+		w.Header().Set("Deprecation", "true")
+	} else {
+		w.Header().Set("Deprecation", fmt.Sprintf("@%d", info.Since.Unix()))
+	}
+	// Sunset header uses HTTP-date format (RFC 8594)
+	if !info.Sunset.IsZero() {
+		w.Header().Set("Sunset", info.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if info.Successor != "" {
+		w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, info.Successor))
+	}
+}
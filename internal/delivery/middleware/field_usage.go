@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/service"
+	"sort"
+	"strings"
+)
+
+const (
+	// maxFieldUsageBody is the largest request body inspected, larger bodies are passed on
+	// without being counted
+	maxFieldUsageBody = 1 << 20
+	// maxFieldPaths caps the fields counted per request and direction
+	maxFieldPaths = 200
+	// maxFieldDepth is the deepest nesting of request fields counted
+	maxFieldDepth = 8
+	// maxFieldPathLength is the longest field path counted, longer ones are skipped
+	maxFieldPathLength = 200
+)
+
+// FieldUsage counts the fields of a sample of requests per client (X-Client-ID): the
+// fields of JSON request bodies, and the response fields a client reports reading as a
+// comma separated X-Fields-Read header. route returns the pattern of the route the
+// request matches, requests matching none aren't counted
+func FieldUsage(recorder service.FieldUsageRecorder, sampleRate float64, route func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if sampleRate <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if sampleRate < 1 && rand.Float64() >= sampleRate {
+				next.ServeHTTP(w, r)
+				return
+			}
+			pattern := route(r)
+			if pattern == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			clientID := r.Header.Get("X-Client-ID")
+			if len(clientID) > maxClientIDLength {
+				clientID = clientID[:maxClientIDLength]
+			}
+			if fields := requestFields(r); len(fields) > 0 {
+				recorder.Record(clientID, pattern, entities.FieldSent, fields)
+			}
+			if fields := readFields(r.Header.Get("X-Fields-Read")); len(fields) > 0 {
+				recorder.Record(clientID, pattern, entities.FieldRead, fields)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requestFields returns the field paths of a JSON request body, leaving the body
+// readable for the handler
+func requestFields(r *http.Request) []string {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxFieldUsageBody+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+	if err != nil || len(body) > maxFieldUsageBody {
+		return nil
+	}
+
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil
+	}
+	seen := make(map[string]struct{})
+	collectFieldPaths(doc, "", 0, seen)
+
+	fields := make([]string, 0, len(seen))
+	for field := range seen {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// collectFieldPaths adds the path of every object key in v to seen, nested keys are
+// joined with "." and array elements marked with "[]"
+func collectFieldPaths(v any, prefix string, depth int, seen map[string]struct{}) {
+	if depth >= maxFieldDepth {
+		return
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if len(seen) >= maxFieldPaths {
+				return
+			}
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			if len(path) > maxFieldPathLength {
+				continue
+			}
+			seen[path] = struct{}{}
+			collectFieldPaths(child, path, depth+1, seen)
+		}
+	case []any:
+		for _, child := range v {
+			collectFieldPaths(child, prefix+"[]", depth+1, seen)
+		}
+	}
+}
+
+// readFields parses the X-Fields-Read header
+func readFields(header string) []string {
+	if header == "" {
+		return nil
+	}
+	fields := make([]string, 0)
+	for _, field := range strings.Split(header, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || len(field) > maxFieldPathLength {
+			continue
+		}
+		fields = append(fields, field)
+		if len(fields) == maxFieldPaths {
+			break
+		}
+	}
+	return fields
+}
@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/repository"
+	"time"
+)
+
+// HeaderIdempotencyKey is the header a client sets to make retries of a mutation safe
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+const (
+	// maxIdempotencyKeyLength is the longest Idempotency-Key accepted
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodySize limits the body read to fingerprint a request
+	maxIdempotentBodySize = 1 << 20
+)
+
+// Idempotency middleware makes a mutation safe to retry. The response of a request with
+// an Idempotency-Key is stored per user for ttl and sent again, with Idempotent-Replayed,
+// to retries with the same key. A retry with another method, URI or body is rejected,
+// and so is one sent while the first request is still running. Server errors aren't
+// stored so the request can be retried. Requests without the header are passed on. It
+// must run after Auth
+func Idempotency(store repository.IdempotencyRepository, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(HeaderIdempotencyKey)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				response.Error(w, apperror.ErrIdempotencyKeyInvalid)
+				return
+			}
+
+			userID, err := GetUserID(r.Context())
+			if err != nil {
+				response.Error(w, err)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodySize))
+			if err != nil {
+				response.BadRequest(w, "Body request terlalu besar atau tidak terbaca")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			storeKey := userID.String() + ":" + key
+			fingerprint := requestFingerprint(r.Method, r.URL.RequestURI(), body)
+			stored, claimed, err := store.Claim(r.Context(), storeKey, fingerprint, ttl)
+			if err != nil {
+				slog.WarnContext(r.Context(), "idempotency store unavailable, rejecting request",
+					slog.String("path", r.URL.Path),
+					slog.String("error", err.Error()),
+				)
+				response.Error(w, apperror.ErrServiceUnavailable)
+				return
+			}
+			if !claimed {
+				switch {
+				case stored.Fingerprint != fingerprint:
+					response.Error(w, apperror.ErrIdempotencyKeyReused)
+				case !stored.Completed():
+					response.Error(w, apperror.ErrIdempotencyInProgress)
+				default:
+					replayResponse(w, stored)
+				}
+				return
+			}
+
+			rec := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			// the request is done, store its outcome even if the client went away
+			ctx := context.WithoutCancel(r.Context())
+			if rec.status >= http.StatusInternalServerError {
+				if err := store.Release(ctx, storeKey); err != nil {
+					slog.WarnContext(ctx, "releasing idempotency key failed", slog.String("error", err.Error()))
+				}
+				return
+			}
+			completed := &entities.IdempotentRequest{
+				Fingerprint: fingerprint,
+				Status:      rec.status,
+				ContentType: rec.Header().Get("Content-Type"),
+				Location:    rec.Header().Get("Location"),
+				Body:        rec.body.Bytes(),
+			}
+			if err := store.Complete(ctx, storeKey, completed, ttl); err != nil {
+				slog.WarnContext(ctx, "storing idempotent response failed", slog.String("error", err.Error()))
+			}
+		})
+	}
+}
+
+// requestFingerprint hashes what identifies a request
+func requestFingerprint(method, uri string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + "\n" + uri + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// replayResponse writes a stored response again
+func replayResponse(w http.ResponseWriter, stored *entities.IdempotentRequest) {
+	if stored.ContentType != "" {
+		w.Header().Set("Content-Type", stored.ContentType)
+	}
+	if stored.Location != "" {
+		w.Header().Set("Location", stored.Location)
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(stored.Status)
+	w.Write(stored.Body)
+}
+
+// recordingWriter passes the response on while keeping a copy of it
+type recordingWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rw *recordingWriter) WriteHeader(code int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.status = code
+	rw.wroteHeader = true
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rw *recordingWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"postgresDB/internal/delivery/response"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/pkg/metrics"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Priority is the tier of a route when the server is saturated, lower tiers are shed first
+type Priority int
+
+const (
+	// PriorityExempt routes are never shed nor counted, e.g. health checks and streams
+	PriorityExempt Priority = iota
+	// PriorityCheckout routes keep the shop selling: placing and paying orders, signing in
+	PriorityCheckout
+	// PriorityBrowse routes serve the catalog and customer pages
+	PriorityBrowse
+	// PriorityReports routes serve back office reads and reports
+	PriorityReports
+)
+
+var priorityNames = map[Priority]string{
+	PriorityExempt:   "exempt",
+	PriorityCheckout: "checkout",
+	PriorityBrowse:   "browse",
+	PriorityReports:  "reports",
+}
+
+// String returns the name of the priority as used in settings and metrics
+func (p Priority) String() string {
+	return priorityNames[p]
+}
+
+// ParsePriority returns the priority named name
+func ParsePriority(name string) (Priority, bool) {
+	for p, n := range priorityNames {
+		if n == name {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+var (
+	requestsInFlight = metrics.NewGauge("http_requests_in_flight", "Number of requests being served, exempt routes excluded")
+	requestsShed     = metrics.NewCounterVec("http_requests_shed_total", "Number of requests rejected by load shedding", "priority")
+)
+
+// LoadShed rejects requests with 503 once the server is busy. Each priority is admitted
+// while fewer than limits[priority] requests are in flight, so with a higher limit for
+// checkout than for reports, reports are turned away first while checkout keeps the
+// remaining capacity. Priorities without a limit are exempt
+func LoadShed(limits map[Priority]int, retryAfter time.Duration, classify func(*http.Request) Priority) func(http.Handler) http.Handler {
+	var inFlight atomic.Int64
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			priority := classify(r)
+			limit, ok := limits[priority]
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if n := inFlight.Add(1); n > int64(limit) {
+				inFlight.Add(-1)
+				requestsShed.With(priority.String()).Inc()
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				response.Error(w, apperror.ErrServerOverloaded)
+				return
+			}
+			requestsInFlight.Add(1)
+			defer func() {
+				inFlight.Add(-1)
+				requestsInFlight.Add(-1)
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -3,9 +3,27 @@ package middleware
 import (
 	"log/slog"
 	"net/http"
+	"postgresDB/pkg/metrics"
+	"strconv"
 	"time"
 )
 
+var (
+	httpRequests = metrics.NewCounterVec("http_requests_total",
+		"Number of requests served by status class (2xx, 3xx, 4xx, 5xx)", "class")
+	httpRequestDuration = metrics.NewHistogram("http_request_duration_seconds",
+		"Time taken to serve requests, event streams excluded",
+		[]float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10})
+)
+
+// LatencySLI reads the requests served within threshold, rounded down to the buckets of
+// http_request_duration_seconds, and all requests served. Both count since the start
+func LatencySLI(threshold time.Duration) func() (good, total float64) {
+	return func() (float64, float64) {
+		return httpRequestDuration.CountAtOrBelow(threshold.Seconds()), httpRequestDuration.Count()
+	}
+}
+
 // responseWriter is a wrapper for http.ResponseWriter to capture status code
 type responseWriter struct {
 	http.ResponseWriter
@@ -32,6 +50,11 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap returns the underlying ResponseWriter (used by http.ResponseController)
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // Logger middleware logs details of each HTTP request
 func Logger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -48,13 +71,18 @@ func Logger(next http.Handler) http.Handler {
 
 		// Log request details
 		duration := time.Since(start)
+		httpRequests.With(strconv.Itoa(rw.status/100) + "xx").Inc()
+		// streams stay open for as long as the client listens
+		if rw.Header().Get("Content-Type") != "text/event-stream" {
+			httpRequestDuration.Observe(duration.Seconds())
+		}
 
-		slog.Info("HTTP Request",
+		slog.InfoContext(r.Context(), "HTTP Request",
 			slog.String("method", r.Method),
 			slog.String("path", r.URL.Path),
 			slog.Int("status", rw.status),
 			slog.String("duration", duration.String()),
-			slog.String("ip", r.RemoteAddr),
+			slog.String("ip", ClientIP(r)),
 		)
 	})
 }
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"postgresDB/pkg/metrics"
+	"strconv"
+	"time"
+)
+
+// routeSeries caps the series of the route metrics, above the number of routes times the
+// status codes they answer with
+const routeSeries = 2000
+
+// unmatchedRoute labels requests no route matches, so unknown paths share one series
+const unmatchedRoute = "unmatched"
+
+var (
+	routeRequests = metrics.NewCounterVec("http_route_requests_total",
+		"Number of requests served by route pattern and status code", "route", "status").Limit(routeSeries)
+	routeRequestDuration = metrics.NewHistogramVec("http_route_request_duration_seconds",
+		"Time taken to serve requests by route pattern, event streams excluded",
+		[]float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}, "route").Limit(routeSeries)
+)
+
+// Metrics middleware counts requests by route pattern and status code and observes their
+// duration by route. route returns the pattern of the route a request matches, "" when
+// none does; the path itself is never a label
+func Metrics(route func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			pattern := route(r)
+			if pattern == "" {
+				pattern = unmatchedRoute
+			}
+
+			rw := &responseWriter{
+				ResponseWriter: w,
+				status:         http.StatusOK,
+			}
+			next.ServeHTTP(rw, r)
+
+			routeRequests.With(pattern, strconv.Itoa(rw.status)).Inc()
+			if rw.Header().Get("Content-Type") != "text/event-stream" {
+				routeRequestDuration.With(pattern).Observe(time.Since(start).Seconds())
+			}
+		})
+	}
+}
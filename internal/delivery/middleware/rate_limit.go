@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"postgresDB/internal/delivery/response"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/repository"
+	"postgresDB/pkg/metrics"
+	"strconv"
+	"time"
+)
+
+// RateTier is the class of a route for the token bucket rate limit, each tier has its
+// own bucket per client
+type RateTier int
+
+const (
+	// RateTierExempt routes aren't rate limited, e.g. health checks and metrics
+	RateTierExempt RateTier = iota
+	// RateTierDefault is every route not in another tier
+	RateTierDefault
+	// RateTierAuth routes sign in, register and issue tokens, with a stricter bucket
+	// against credential stuffing
+	RateTierAuth
+	// RateTierCatalog routes read the public catalog, with a looser bucket
+	RateTierCatalog
+)
+
+var rateTierNames = map[RateTier]string{
+	RateTierExempt:  "exempt",
+	RateTierDefault: "default",
+	RateTierAuth:    "auth",
+	RateTierCatalog: "catalog",
+}
+
+// String returns the name of the tier as used in settings, keys and metrics
+func (t RateTier) String() string {
+	return rateTierNames[t]
+}
+
+// ParseRateTier returns the tier named name
+func ParseRateTier(name string) (RateTier, bool) {
+	for t, n := range rateTierNames {
+		if n == name {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// TokenBucket is a rate limit policy: Burst requests at once, refilled at Rate a second
+type TokenBucket struct {
+	Rate  float64
+	Burst int
+}
+
+var requestsRateLimited = metrics.NewCounterVec("http_requests_rate_limited_total",
+	"Number of requests rejected by the token bucket rate limit", "tier")
+
+// TokenBucketRateLimit gives every client a token bucket per tier and rejects requests
+// with 429 once it's empty. Clients are told by identify, e.g. the user of the token and
+// the IP otherwise. Tiers without a bucket are exempt. Every limited response carries
+// the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers; like RateLimit,
+// requests are let through while the limiter store is unavailable
+func TokenBucketRateLimit(limiter repository.RateLimitRepository, buckets map[RateTier]TokenBucket, classify func(*http.Request) RateTier, identify func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tier := classify(r)
+			bucket, ok := buckets[tier]
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allowed, remaining, wait, err := limiter.Take(r.Context(), tier.String()+":"+identify(r), bucket.Rate, bucket.Burst)
+			if err != nil {
+				slog.WarnContext(r.Context(), "rate limiter unavailable, allowing request",
+					slog.String("tier", tier.String()),
+					slog.String("error", err.Error()),
+				)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			seconds := strconv.Itoa(int(math.Ceil(wait.Seconds())))
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(bucket.Burst))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(remaining))
+			w.Header().Set("RateLimit-Reset", seconds)
+			if !allowed {
+				requestsRateLimited.With(tier.String()).Inc()
+				w.Header().Set("Retry-After", seconds)
+				response.Error(w, apperror.ErrTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RateLimit middleware allows at most limit requests per client IP in each window for
+// the given scope. When the limiter store is unavailable requests are let through,
+// a rate limit outage shouldn't take the endpoint down with it
+func RateLimit(limiter repository.RateLimitRepository, scope string, limit int, window time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := scope + ":" + ClientIP(r)
+
+			allowed, retryAfter, err := limiter.Allow(r.Context(), key, limit, window)
+			if err != nil {
+				slog.WarnContext(r.Context(), "rate limiter unavailable, allowing request",
+					slog.String("scope", scope),
+					slog.String("error", err.Error()),
+				)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				response.Error(w, apperror.ErrTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"postgresDB/internal/delivery/response"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/pkg/metrics"
+	"runtime/debug"
+)
+
+var httpPanics = metrics.NewCounter("http_panics_total",
+	"Number of requests whose handler panicked")
+
+// Recover middleware answers a request whose handler panicked with 500 and logs the panic
+// with its stack, so one request can't take the server down. When the handler already
+// started its response the connection is aborted instead, the client sees it cut off
+// rather than a body with an error in the middle. http.ErrAbortHandler is passed on
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &responseWriter{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+		}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			httpPanics.Inc()
+			slog.ErrorContext(r.Context(), "panic serving request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Any("panic", v),
+				slog.String("stack", string(debug.Stack())),
+			)
+			if rw.wroteHeader {
+				panic(http.ErrAbortHandler)
+			}
+			response.Error(w, apperror.ErrInternal)
+		}()
+		next.ServeHTTP(rw, r)
+	})
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"postgresDB/internal/delivery/response"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/repository"
+	"strconv"
+	"time"
+)
+
+// Headers of a signed request
+const (
+	HeaderRequestTimestamp = "X-Request-Timestamp"
+	HeaderRequestNonce     = "X-Request-Nonce"
+	HeaderRequestSignature = "X-Request-Signature"
+)
+
+// Replay protection modes
+const (
+	// ReplayModeOff skips the check
+	ReplayModeOff = "off"
+	// ReplayModeOptional checks requests that are signed and lets unsigned ones through
+	ReplayModeOptional = "optional"
+	// ReplayModeRequired rejects unsigned requests
+	ReplayModeRequired = "required"
+)
+
+const (
+	// maxSignedBodySize limits the body read to verify a signature
+	maxSignedBodySize = 1 << 20
+	nonceMinLength    = 16
+	nonceMaxLength    = 128
+)
+
+// ReplayProtection middleware rejects replayed requests to high-value mutations. A signed
+// request carries a unix timestamp, a nonce and an HMAC-SHA256 signature over the method,
+// URI, timestamp, nonce and body hash, keyed with the signing key of the session, looked up
+// with signingKey from the token family. The key is never sent with a request, so a captured
+// request can't be signed again. Requests outside window from now are rejected, and a nonce
+// is accepted once per user, so a captured request can't be sent again even while its token
+// is valid. It must run after Auth
+func ReplayProtection(nonces repository.NonceRepository, signingKey func(family string) string, mode string, window time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if mode == ReplayModeOff {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timestamp := r.Header.Get(HeaderRequestTimestamp)
+			nonce := r.Header.Get(HeaderRequestNonce)
+			signature := r.Header.Get(HeaderRequestSignature)
+			if timestamp == "" && nonce == "" && signature == "" && mode == ReplayModeOptional {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if timestamp == "" || nonce == "" || signature == "" {
+				response.Error(w, apperror.ErrRequestSignatureRequired)
+				return
+			}
+
+			userID, err := GetUserID(r.Context())
+			if err != nil {
+				response.Error(w, err)
+				return
+			}
+			family, err := GetTokenFamily(r.Context())
+			if err != nil {
+				response.Error(w, apperror.ErrRequestSignatureInvalid)
+				return
+			}
+
+			unix, err := strconv.ParseInt(timestamp, 10, 64)
+			if err != nil || time.Since(time.Unix(unix, 0)).Abs() > window {
+				response.Error(w, apperror.ErrRequestSignatureInvalid)
+				return
+			}
+			if len(nonce) < nonceMinLength || len(nonce) > nonceMaxLength {
+				response.Error(w, apperror.ErrRequestSignatureInvalid)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSignedBodySize))
+			if err != nil {
+				response.BadRequest(w, "Body request terlalu besar atau tidak terbaca")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			if !validRequestSignature(signingKey(family), r.Method, r.URL.RequestURI(), timestamp, nonce, body, signature) {
+				response.Error(w, apperror.ErrRequestSignatureInvalid)
+				return
+			}
+
+			// the nonce is kept for two windows, a timestamp up to window in the future
+			// stays acceptable that long
+			fresh, err := nonces.Use(r.Context(), userID.String()+":"+nonce, 2*window)
+			if err != nil {
+				slog.WarnContext(r.Context(), "nonce store unavailable, rejecting signed request",
+					slog.String("path", r.URL.Path),
+					slog.String("error", err.Error()),
+				)
+				response.Error(w, apperror.ErrServiceUnavailable)
+				return
+			}
+			if !fresh {
+				slog.WarnContext(r.Context(), "replayed request rejected",
+					slog.String("user_id", userID.String()),
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+				)
+				response.Error(w, apperror.ErrRequestReplayed)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// validRequestSignature checks the hex HMAC-SHA256 of
+// "METHOD\nURI\nTIMESTAMP\nNONCE\nhex(sha256(body))" keyed with the session signing key
+func validRequestSignature(key, method, uri, timestamp, nonce string, body []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(method + "\n" + uri + "\n" + timestamp + "\n" + nonce + "\n" + hex.EncodeToString(bodyHash[:])))
+	return hmac.Equal(mac.Sum(nil), expected)
+}
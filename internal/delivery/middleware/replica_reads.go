@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"net/http"
+	"postgresDB/internal/domain/repository"
+)
+
+// ReplicaReads middleware lets the reads of GET and HEAD requests go to a read replica.
+// Other requests read from the primary, so what they write and read back is consistent
+func ReplicaReads(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			r = r.WithContext(repository.AllowStaleReads(r.Context()))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
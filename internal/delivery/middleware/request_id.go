@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// maxRequestIDLength bounds a request ID sent by the client
+const maxRequestIDLength = 128
+
+// RequestID middleware gives every request an ID, the X-Request-ID sent by the client or
+// proxy when it's usable, a new UUID otherwise. The ID is returned in the X-Request-ID
+// header and in error bodies, and is added to every line logged with the request context
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(response.HeaderRequestID)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		w.Header().Set(response.HeaderRequestID, id)
+		next.ServeHTTP(w, r.WithContext(logger.WithRequestID(r.Context(), id)))
+	})
+}
+
+// validRequestID accepts short IDs of letters, digits and -_.:, so a client can't inject
+// anything into logs or headers
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"postgresDB/internal/delivery/response"
+	apperror "postgresDB/internal/domain/errors"
+	"time"
+)
+
+// Timeout middleware bounds the context of a request to timeout(r), no bound when it's 0.
+// Handlers see the deadline through r.Context(); a server error answered after it passed
+// is replaced by 408. A handler ignoring its context isn't stopped, the server write
+// timeout still closes the connection
+func Timeout(timeout func(*http.Request) time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			d := timeout(r)
+			if d <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			rw := &replacingWriter{ResponseWriter: w, replace: func(status int) *apperror.AppError {
+				if status >= http.StatusInternalServerError && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return apperror.ErrRequestTimeout
+				}
+				return nil
+			}}
+			next.ServeHTTP(rw, r.WithContext(ctx))
+		})
+	}
+}
+
+// MaxBodySize middleware rejects request bodies over limit bytes with 413. Multipart
+// uploads are left to their handlers, which read them with limits of their own. A body
+// sent without Content-Length is cut at the limit, and the 400 the handler answers for
+// the truncated body is replaced by 413
+func MaxBodySize(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if limit <= 0 || r.Body == nil || r.Body == http.NoBody || isMultipart(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if r.ContentLength > limit {
+				response.Error(w, apperror.ErrRequestTooLarge)
+				return
+			}
+
+			body := &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, limit)}
+			r.Body = body
+			rw := &replacingWriter{ResponseWriter: w, replace: func(status int) *apperror.AppError {
+				if status == http.StatusBadRequest && body.exceeded {
+					return apperror.ErrRequestTooLarge
+				}
+				return nil
+			}}
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// isMultipart reports whether the request body is a multipart form
+func isMultipart(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "multipart/form-data"
+}
+
+// limitedBody remembers whether reading stopped at the size limit
+type limitedBody struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		b.exceeded = true
+	}
+	return n, err
+}
+
+// replacingWriter swaps the response of a handler for an error when replace returns one
+// for the status the handler writes, the handler's own body is dropped
+type replacingWriter struct {
+	http.ResponseWriter
+	replace     func(status int) *apperror.AppError
+	replaced    bool
+	wroteHeader bool
+}
+
+func (rw *replacingWriter) WriteHeader(code int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+	if appErr := rw.replace(code); appErr != nil {
+		rw.replaced = true
+		response.Error(rw.ResponseWriter, appErr)
+		return
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *replacingWriter) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	if rw.replaced {
+		return len(b), nil
+	}
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter (used by http.ResponseController)
+func (rw *replacingWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
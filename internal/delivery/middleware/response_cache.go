@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"postgresDB/internal/delivery/response"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/repository"
+	"postgresDB/pkg/i18n"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// HeaderCache tells whether a response came from the response cache
+const HeaderCache = "X-Cache"
+
+// ResponseCache middleware caches the 200 responses of a public GET route in store for
+// ttl, keyed by the normalized URL, the auth state of the caller and the negotiated
+// style and language. Concurrent misses of a key run the handler once and share its
+// response. Entries belong to scope and are invalidated with it. When the store is
+// unavailable requests are served uncached
+func ResponseCache(store repository.ResponseCacheRepository, scope string, ttl time.Duration) func(http.Handler) http.Handler {
+	var group singleflight.Group
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			version, err := store.Version(r.Context(), scope)
+			if err != nil {
+				slog.WarnContext(r.Context(), "response cache unavailable",
+					slog.String("path", r.URL.Path),
+					slog.String("error", err.Error()),
+				)
+				next.ServeHTTP(w, r)
+				return
+			}
+			key := scope + ":v" + version + ":" + responseCacheKey(r)
+
+			cached, err := store.Get(r.Context(), key)
+			if err != nil {
+				slog.WarnContext(r.Context(), "response cache read failed", slog.String("error", err.Error()))
+			}
+			if cached != nil {
+				writeCachedResponse(w, cached, "HIT")
+				return
+			}
+
+			// only the request running the handler has its response in rec
+			leader := false
+			result, _, _ := group.Do(key, func() (any, error) {
+				leader = true
+				rec := &bufferingWriter{ResponseWriter: w, header: make(http.Header), status: http.StatusOK}
+				next.ServeHTTP(rec, r)
+				return rec, nil
+			})
+			rec := result.(*bufferingWriter)
+			if rec.status != http.StatusOK {
+				if leader {
+					rec.flush(w)
+					return
+				}
+				// a failure may be the leader's own, like its client going away
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			entry := &entities.CachedResponse{Header: rec.header, Body: rec.body.Bytes()}
+			if leader {
+				// the request is done, cache it even if the client went away
+				ctx := context.WithoutCancel(r.Context())
+				if err := store.Set(ctx, key, entry, ttl); err != nil {
+					slog.WarnContext(ctx, "response cache write failed", slog.String("error", err.Error()))
+				}
+			}
+			writeCachedResponse(w, entry, "MISS")
+		})
+	}
+}
+
+// responseCacheKey hashes what a public response depends on. Query parameters are sorted
+// so their order doesn't split the cache
+func responseCacheKey(r *http.Request) string {
+	h := sha256.New()
+	h.Write([]byte(strings.Join([]string{
+		r.URL.Path,
+		r.URL.Query().Encode(),
+		authState(r),
+		r.Header.Get("Accept"),
+		r.Header.Get(response.HeaderResponseStyle),
+		i18n.Negotiate(r.Header.Get("Accept-Language")),
+	}, "\n")))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// authState tells apart the callers a route may answer differently: the role of an
+// authenticated caller, or whether an unauthenticated request carries credentials
+func authState(r *http.Request) string {
+	if role, err := GetUserRole(r.Context()); err == nil {
+		return "role:" + string(role)
+	}
+	if r.Header.Get("Authorization") != "" {
+		return "credentials"
+	}
+	return "anonymous"
+}
+
+// writeCachedResponse writes a cached response with its X-Cache status
+func writeCachedResponse(w http.ResponseWriter, cached *entities.CachedResponse, status string) {
+	for name, values := range cached.Header {
+		// concurrent requests share the entry, each gets its own values
+		w.Header()[name] = slices.Clone(values)
+	}
+	w.Header().Set(HeaderCache, status)
+	w.WriteHeader(http.StatusOK)
+	w.Write(cached.Body)
+}
+
+// bufferingWriter holds a response back so it can be cached before it is written. It
+// keeps the underlying writer for the response helpers looking up the negotiated style
+type bufferingWriter struct {
+	http.ResponseWriter
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (bw *bufferingWriter) Header() http.Header {
+	return bw.header
+}
+
+func (bw *bufferingWriter) WriteHeader(code int) {
+	if bw.wroteHeader {
+		return
+	}
+	bw.status = code
+	bw.wroteHeader = true
+}
+
+func (bw *bufferingWriter) Write(b []byte) (int, error) {
+	if !bw.wroteHeader {
+		bw.WriteHeader(http.StatusOK)
+	}
+	return bw.body.Write(b)
+}
+
+// flush writes the held response to w
+func (bw *bufferingWriter) flush(w http.ResponseWriter) {
+	for name, values := range bw.header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(bw.status)
+	w.Write(bw.body.Bytes())
+}
+
+// Unwrap exposes the underlying writer to the response helpers
+func (bw *bufferingWriter) Unwrap() http.ResponseWriter {
+	return bw.ResponseWriter
+}
@@ -0,0 +1,41 @@
+package response
+
+import (
+	"net/http"
+
+	"postgresDB/pkg/i18n"
+)
+
+// localizedWriter carries the negotiated language down to the response helpers
+type localizedWriter struct {
+	http.ResponseWriter
+	lang string
+}
+
+// Unwrap returns the underlying ResponseWriter (used by http.ResponseController)
+func (lw *localizedWriter) Unwrap() http.ResponseWriter {
+	return lw.ResponseWriter
+}
+
+// Localize middleware picks the language of error messages from the Accept-Language
+// header and makes it available to all response helpers. Error codes don't change with it
+func Localize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(&localizedWriter{ResponseWriter: w, lang: i18n.Negotiate(r.Header.Get("Accept-Language"))}, r)
+	})
+}
+
+// languageOf returns the negotiated language for the writer
+func languageOf(w http.ResponseWriter) string {
+	for {
+		switch t := w.(type) {
+		case *localizedWriter:
+			return t.lang
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = t.Unwrap()
+		default:
+			return i18n.Default
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package response
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"postgresDB/internal/domain/dto"
+)
+
+// Style represents the response body style negotiated with the client
+type Style int
+
+const (
+	// StyleEnvelope wraps resources in {success, data, meta, error} (default)
+	StyleEnvelope Style = iota
+	// StylePlain writes raw resource JSON without the envelope
+	StylePlain
+)
+
+const (
+	// HeaderResponseStyle lets clients select the response style explicitly
+	HeaderResponseStyle = "X-Response-Style"
+	// MediaTypePlain is the vendored media type for raw resource bodies
+	MediaTypePlain = "application/vnd.postgresdb.plain+json"
+	// MediaTypeProblem is used for error bodies in plain style (RFC 9457)
+	MediaTypeProblem = "application/problem+json"
+	// HeaderRequestID carries the ID of a request, see middleware.RequestID
+	HeaderRequestID = "X-Request-ID"
+)
+
+// styledWriter carries the negotiated style down to the response helpers
+type styledWriter struct {
+	http.ResponseWriter
+	style Style
+}
+
+// Unwrap returns the underlying ResponseWriter (used by http.ResponseController)
+func (sw *styledWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
+
+// Negotiate middleware determines the response style from the
+// X-Response-Style header or the Accept header and makes it available
+// to all response helpers
+func Negotiate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		w.Header().Add("Vary", HeaderResponseStyle)
+		next.ServeHTTP(&styledWriter{ResponseWriter: w, style: negotiateStyle(r)}, r)
+	})
+}
+
+// negotiateStyle picks the response style for a request
+func negotiateStyle(r *http.Request) Style {
+	switch strings.ToLower(strings.TrimSpace(r.Header.Get(HeaderResponseStyle))) {
+	case "plain", "raw":
+		return StylePlain
+	case "envelope":
+		return StyleEnvelope
+	}
+
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err == nil && mediaType == MediaTypePlain {
+			return StylePlain
+		}
+	}
+	return StyleEnvelope
+}
+
+// styleOf returns the negotiated style for the writer
+func styleOf(w http.ResponseWriter) Style {
+	for {
+		switch t := w.(type) {
+		case *styledWriter:
+			return t.style
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = t.Unwrap()
+		default:
+			return StyleEnvelope
+		}
+	}
+}
+
+// ProblemDetails represents an error body in plain style (RFC 9457)
+type ProblemDetails struct {
+	Type    string                `json:"type"`
+	Title   string                `json:"title"`
+	Status  int                   `json:"status"`
+	Detail  string                `json:"detail"`
+	Code    string                `json:"code"`
+	Details []dto.ValidationError `json:"errors,omitempty"`
+	// RequestID identifies the request in the server logs
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// writePlain writes a raw resource body
+func writePlain(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", MediaTypePlain)
+	writeBody(w, status, data)
+}
+
+// writePlainMeta exposes pagination metadata as headers since the body has no envelope
+func writePlainMeta(w http.ResponseWriter, meta interface{}) {
+	var p *dto.PaginationMeta
+	switch m := meta.(type) {
+	case *dto.PaginationMeta:
+		p = m
+	case dto.PaginationMeta:
+		p = &m
+	case *dto.CursorMeta:
+		writePlainCursorMeta(w, m)
+	case dto.CursorMeta:
+		writePlainCursorMeta(w, &m)
+	}
+	if p == nil {
+		return
+	}
+	w.Header().Set("X-Page", strconv.Itoa(p.Page))
+	w.Header().Set("X-Limit", strconv.Itoa(p.Limit))
+	w.Header().Set("X-Total-Count", strconv.FormatInt(p.Total, 10))
+	w.Header().Set("X-Total-Pages", strconv.Itoa(p.TotalPages))
+	if p.TotalEstimated {
+		w.Header().Set("X-Total-Estimated", "true")
+	}
+}
+
+// writePlainCursorMeta exposes keyset pagination metadata as headers
+func writePlainCursorMeta(w http.ResponseWriter, c *dto.CursorMeta) {
+	w.Header().Set("X-Limit", strconv.Itoa(c.Limit))
+	if c.NextCursor != "" {
+		w.Header().Set("X-Next-Cursor", c.NextCursor)
+	}
+}
+
+// writeProblem writes an error body in plain style
+func writeProblem(w http.ResponseWriter, status int, code, message string, details []dto.ValidationError) {
+	w.Header().Set("Content-Type", MediaTypeProblem)
+	writeBody(w, status, ProblemDetails{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    message,
+		Code:      code,
+		Details:   details,
+		RequestID: w.Header().Get(HeaderRequestID),
+	})
+}
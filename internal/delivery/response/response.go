@@ -6,34 +6,54 @@ import (
 
 	"postgresDB/internal/domain/dto"
 	apperrors "postgresDB/internal/domain/errors"
+	"postgresDB/pkg/i18n"
 	"postgresDB/pkg/logger"
 )
 
 // JSON writes a JSON response
 func JSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
+	writeBody(w, status, data)
+}
+
+// writeBody writes the status code and encodes data as JSON
+func writeBody(w http.ResponseWriter, status int, data interface{}) {
 	w.WriteHeader(status)
 	if data != nil {
 		if err := json.NewEncoder(w).Encode(data); err != nil {
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			// the status is already sent, all that's left is to log it
+			logger.Error("encoding response failed", "error", err)
 		}
 	}
 }
 
 // Success writes a success response
 func Success(w http.ResponseWriter, data interface{}) {
+	if styleOf(w) == StylePlain {
+		writePlain(w, http.StatusOK, data)
+		return
+	}
 	resp := dto.NewSuccessResponse(data)
 	JSON(w, http.StatusOK, resp)
 }
 
 // SuccessWithMeta writes a success response with metadata
 func SuccessWithMeta(w http.ResponseWriter, data interface{}, meta interface{}) {
+	if styleOf(w) == StylePlain {
+		writePlainMeta(w, meta)
+		writePlain(w, http.StatusOK, data)
+		return
+	}
 	resp := dto.NewSuccessResponseWithMeta(data, meta)
 	JSON(w, http.StatusOK, resp)
 }
 
 // Created writes a created response
 func Created(w http.ResponseWriter, data interface{}) {
+	if styleOf(w) == StylePlain {
+		writePlain(w, http.StatusCreated, data)
+		return
+	}
 	resp := dto.NewSuccessResponse(data)
 	JSON(w, http.StatusCreated, resp)
 }
@@ -47,37 +67,58 @@ func NoContent(w http.ResponseWriter) {
 func Error(w http.ResponseWriter, err error) {
 	appErr, ok := apperrors.AsAppError(err)
 	if !ok {
-		appErr = apperrors.ErrInternal
+		// logged below, unlike the bare ErrInternal
+		appErr = apperrors.WrapInternal(err)
 	}
 
+	lang := languageOf(w)
 	var details []dto.ValidationError
 	if len(appErr.Details) > 0 {
 		details = make([]dto.ValidationError, len(appErr.Details))
 		for i, d := range appErr.Details {
 			details[i] = dto.ValidationError{
 				Field:   d.Field,
-				Message: d.Message,
+				Message: d.Localize(lang),
+				Line:    d.Line,
+				Column:  d.Column,
 			}
 		}
 	}
 
-	resp := dto.NewErrorResponse(string(appErr.Code), appErr.Message, details)
-
 	// Log internal errors for debugging
 	if appErr.Code == apperrors.CodeInternal {
 		if appErr.Err != nil {
 			logger.Error("Internal server error occurred",
 				"error", appErr.Err.Error(),
 				"http_status", appErr.HTTPStatus,
+				"request_id", w.Header().Get(HeaderRequestID),
 			)
 		}
 	}
+	if appErr.Code == apperrors.CodeQueryTimeout {
+		logger.Warn("Database query timed out",
+			"error", appErr.Err.Error(),
+			"request_id", w.Header().Get(HeaderRequestID),
+		)
+	}
 
-	JSON(w, appErr.HTTPStatus, resp)
+	writeError(w, appErr.HTTPStatus, string(appErr.Code), appErr.Localize(lang), details)
 }
 
-// BadRequest writes a bad request error
-func BadRequest(w http.ResponseWriter, message string) {
-	resp := dto.NewErrorResponse(string(apperrors.CodeBadRequest), message, nil)
-	JSON(w, http.StatusBadRequest, resp)
+// BadRequest writes a bad request error, message is translated like the one of an
+// AppError and args fill in its verbs
+func BadRequest(w http.ResponseWriter, message string, args ...any) {
+	writeError(w, http.StatusBadRequest, string(apperrors.CodeBadRequest), i18n.T(languageOf(w), message, args...), nil)
+}
+
+// writeError writes an error body, already translated, in the negotiated style
+func writeError(w http.ResponseWriter, status int, code, message string, details []dto.ValidationError) {
+	w.Header().Set("Content-Language", languageOf(w))
+	if styleOf(w) == StylePlain {
+		writeProblem(w, status, code, message, details)
+		return
+	}
+	resp := dto.NewErrorResponse(code, message, details)
+	resp.Error.RequestID = w.Header().Get(HeaderRequestID)
+	JSON(w, status, resp)
 }
@@ -2,23 +2,120 @@ package routers
 
 import (
 	"net/http"
+	"net/netip"
 	"postgresDB/config"
 	"postgresDB/internal/delivery/handler"
 	"postgresDB/internal/delivery/middleware"
+	"postgresDB/internal/delivery/response"
 	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/service"
+	"postgresDB/internal/repository"
 	"postgresDB/pkg/jwt"
+	"postgresDB/pkg/logger"
+	"strings"
+	"time"
 )
 
+// routePriorities sets the load shedding tier of routes that differ from the default:
+// admin routes are reports, everything else is browse. LOAD_SHED_ROUTES overrides them
+var routePriorities = map[string]middleware.Priority{
+	"GET /healthz":                                  middleware.PriorityExempt,
+	"GET /readyz":                                   middleware.PriorityExempt,
+	"GET /api/v1/health":                            middleware.PriorityExempt,
+	"GET /api/v1/health/ready":                      middleware.PriorityExempt,
+	"GET /metrics":                                  middleware.PriorityExempt,
+	"GET /api/v1/admin/inventory/stream":            middleware.PriorityExempt,
+	"POST /api/v1/auth/login":                       middleware.PriorityCheckout,
+	"POST /api/v1/auth/refresh":                     middleware.PriorityCheckout,
+	"POST /api/v1/oauth/token":                      middleware.PriorityCheckout,
+	"POST /api/v1/orders":                           middleware.PriorityCheckout,
+	"PATCH /api/v1/orders/{id}/status":              middleware.PriorityCheckout,
+	"POST /api/v1/admin/orders/{id}/capture":        middleware.PriorityCheckout,
+	"POST /api/v1/orders/{id}/payment":              middleware.PriorityCheckout,
+	"POST /api/v1/admin/orders/{id}/refund":         middleware.PriorityCheckout,
+	"POST /api/v1/payments/webhook":                 middleware.PriorityCheckout,
+	"POST /api/v1/access-logs/exports":              middleware.PriorityReports,
+	"GET /api/v1/access-logs/exports/{id}/download": middleware.PriorityReports,
+	"GET /api/v1/clients/{id}/usage":                middleware.PriorityReports,
+}
+
+// routeRateTiers sets the rate limit tier of routes that differ from the default: catalog
+// reads are catalog, everything else is default. RATE_LIMIT_ROUTES overrides them
+var routeRateTiers = map[string]middleware.RateTier{
+	"GET /healthz":                            middleware.RateTierExempt,
+	"GET /readyz":                             middleware.RateTierExempt,
+	"GET /api/v1/health":                      middleware.RateTierExempt,
+	"GET /api/v1/health/ready":                middleware.RateTierExempt,
+	"GET /metrics":                            middleware.RateTierExempt,
+	"GET /api/v1/admin/inventory/stream":      middleware.RateTierExempt,
+	"POST /api/v1/payments/webhook":           middleware.RateTierExempt,
+	"POST /api/v1/auth/register":              middleware.RateTierAuth,
+	"POST /api/v1/auth/login":                 middleware.RateTierAuth,
+	"POST /api/v1/auth/refresh":               middleware.RateTierAuth,
+	"POST /api/v1/oauth/token":                middleware.RateTierAuth,
+	"POST /api/v1/users/{id}/change-password": middleware.RateTierAuth,
+}
+
+// routeTimeouts sets the request timeout of routes that differ from REQUEST_TIMEOUT, 0 for
+// none. REQUEST_TIMEOUT_ROUTES overrides them
+var routeTimeouts = map[string]time.Duration{
+	"GET /api/v1/admin/inventory/stream": 0,
+}
+
+// catalogPaths start the paths of catalog reads
+var catalogPaths = []string{"/api/v1/products", "/api/v1/categories", "/api/v1/tags", "/api/v2/products"}
+
 // Router sets up all routes for the application
 type Router struct {
 	// Define router fields here
-	mux            *http.ServeMux
-	authHandler    *handler.AuthHandler
-	userHandler    *handler.UserHandler
-	productHandler *handler.ProductHandler
-	orderHandler   *handler.OrderHandler
-	jwtService     *jwt.JWTService
-	cfg            *config.Config
+	mux              *http.ServeMux
+	authHandler      *handler.AuthHandler
+	userHandler      *handler.UserHandler
+	productHandler   *handler.ProductHandler
+	productV2Handler *handler.ProductV2Handler
+	categoryHandler  *handler.CategoryHandler
+	attrHandler      *handler.AttributeHandler
+	tagHandler       *handler.TagHandler
+	imageHandler     *handler.ProductImageHandler
+	orderHandler     *handler.OrderHandler
+	snapshotHandler  *handler.CatalogSnapshotHandler
+	trackingHandler  *handler.OrderTrackingHandler
+	captureHandler   *handler.PaymentCaptureHandler
+	reconHandler     *handler.ReconciliationHandler
+	emailHandler     *handler.EmailTemplateHandler
+	jobHandler       *handler.JobHandler
+	streamHandler    *handler.InventoryHandler
+	metricsHandler   *handler.MetricsHandler
+	healthHandler    *handler.HealthHandler
+	accessHandler    *handler.AccessLogHandler
+	authStateHandler *handler.AuthStateHandler
+	clientHandler    *handler.APIClientHandler
+	oauthHandler     *handler.OAuthHandler
+	stockSubHandler  *handler.StockSubscriptionHandler
+	warehouseHandler *handler.WarehouseHandler
+	transferHandler  *handler.StockTransferHandler
+	countHandler     *handler.StockCountHandler
+	fieldHandler     *handler.FieldUsageHandler
+	paymentHandler   *handler.PaymentHandler
+	dashboardHandler *handler.DashboardHandler
+	refundHandler    *handler.RefundHandler
+	historyHandler   *handler.OrderHistoryHandler
+	shippingHandler  *handler.ShippingHandler
+	consentHandler   *handler.ConsentHandler
+	notifyHandler    *handler.NotificationHandler
+	sloHandler       *handler.SLOHandler
+	webhookHandler   *handler.WebhookHandler
+	outboxHandler    *handler.OutboxHandler
+	accessLogs       service.AccessLogRecorder
+	fieldUsage       service.FieldUsageRecorder
+	rateLimiter      repository.RateLimitRepository
+	nonces           repository.NonceRepository
+	idempotency      repository.IdempotencyRepository
+	responseCache    repository.ResponseCacheRepository
+	jwtService       *jwt.JWTService
+	cfg              *config.Config
+	// versioned holds the patterns registered through a version group
+	versioned map[string]bool
 }
 
 // NewRouter creates a new Router instance
@@ -26,63 +123,602 @@ func NewRouter(
 	authHandler *handler.AuthHandler,
 	userHandler *handler.UserHandler,
 	productHandler *handler.ProductHandler,
+	productV2Handler *handler.ProductV2Handler,
+	categoryHandler *handler.CategoryHandler,
+	attrHandler *handler.AttributeHandler,
+	tagHandler *handler.TagHandler,
+	imageHandler *handler.ProductImageHandler,
 	orderHandler *handler.OrderHandler,
+	snapshotHandler *handler.CatalogSnapshotHandler,
+	trackingHandler *handler.OrderTrackingHandler,
+	captureHandler *handler.PaymentCaptureHandler,
+	reconHandler *handler.ReconciliationHandler,
+	emailHandler *handler.EmailTemplateHandler,
+	jobHandler *handler.JobHandler,
+	streamHandler *handler.InventoryHandler,
+	metricsHandler *handler.MetricsHandler,
+	healthHandler *handler.HealthHandler,
+	accessHandler *handler.AccessLogHandler,
+	authStateHandler *handler.AuthStateHandler,
+	clientHandler *handler.APIClientHandler,
+	oauthHandler *handler.OAuthHandler,
+	stockSubHandler *handler.StockSubscriptionHandler,
+	warehouseHandler *handler.WarehouseHandler,
+	transferHandler *handler.StockTransferHandler,
+	countHandler *handler.StockCountHandler,
+	fieldHandler *handler.FieldUsageHandler,
+	paymentHandler *handler.PaymentHandler,
+	dashboardHandler *handler.DashboardHandler,
+	refundHandler *handler.RefundHandler,
+	historyHandler *handler.OrderHistoryHandler,
+	shippingHandler *handler.ShippingHandler,
+	consentHandler *handler.ConsentHandler,
+	notifyHandler *handler.NotificationHandler,
+	sloHandler *handler.SLOHandler,
+	webhookHandler *handler.WebhookHandler,
+	outboxHandler *handler.OutboxHandler,
+	accessLogs service.AccessLogRecorder,
+	fieldUsage service.FieldUsageRecorder,
+	rateLimiter repository.RateLimitRepository,
+	nonces repository.NonceRepository,
+	idempotency repository.IdempotencyRepository,
+	responseCache repository.ResponseCacheRepository,
 	jwtService *jwt.JWTService,
 	cfg *config.Config,
 ) *Router {
 	return &Router{
-		mux:            http.NewServeMux(),
-		authHandler:    authHandler,
-		userHandler:    userHandler,
-		productHandler: productHandler,
-		orderHandler:   orderHandler,
-		jwtService:     jwtService,
-		cfg:            cfg,
+		mux:              http.NewServeMux(),
+		authHandler:      authHandler,
+		userHandler:      userHandler,
+		productHandler:   productHandler,
+		productV2Handler: productV2Handler,
+		categoryHandler:  categoryHandler,
+		attrHandler:      attrHandler,
+		tagHandler:       tagHandler,
+		imageHandler:     imageHandler,
+		orderHandler:     orderHandler,
+		snapshotHandler:  snapshotHandler,
+		trackingHandler:  trackingHandler,
+		captureHandler:   captureHandler,
+		reconHandler:     reconHandler,
+		emailHandler:     emailHandler,
+		jobHandler:       jobHandler,
+		streamHandler:    streamHandler,
+		metricsHandler:   metricsHandler,
+		healthHandler:    healthHandler,
+		accessHandler:    accessHandler,
+		authStateHandler: authStateHandler,
+		clientHandler:    clientHandler,
+		oauthHandler:     oauthHandler,
+		stockSubHandler:  stockSubHandler,
+		warehouseHandler: warehouseHandler,
+		transferHandler:  transferHandler,
+		countHandler:     countHandler,
+		fieldHandler:     fieldHandler,
+		paymentHandler:   paymentHandler,
+		dashboardHandler: dashboardHandler,
+		refundHandler:    refundHandler,
+		historyHandler:   historyHandler,
+		shippingHandler:  shippingHandler,
+		consentHandler:   consentHandler,
+		notifyHandler:    notifyHandler,
+		sloHandler:       sloHandler,
+		webhookHandler:   webhookHandler,
+		outboxHandler:    outboxHandler,
+		accessLogs:       accessLogs,
+		fieldUsage:       fieldUsage,
+		rateLimiter:      rateLimiter,
+		nonces:           nonces,
+		idempotency:      idempotency,
+		responseCache:    responseCache,
+		jwtService:       jwtService,
+		cfg:              cfg,
+		versioned:        make(map[string]bool),
 	}
 }
 
-// setupRoutes configures all application routes
+// SetupRoutes registers all application routes and returns the mux wrapped in the global
+// middleware stack
 func (r *Router) SetupRoutes() http.Handler {
-	// Health check route
-	r.mux.HandleFunc("GET /api/v1/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
+	// Health check routes: liveness for restarts, readiness for load balancers
+	r.mux.HandleFunc("GET /healthz", r.healthHandler.Live)
+	r.mux.HandleFunc("GET /readyz", r.healthHandler.Ready)
+	// Prometheus scrape endpoint
+	r.mux.HandleFunc("GET /metrics", r.metricsHandler.Metrics)
 	// Auth routes (public)
-	r.mux.HandleFunc("POST /api/v1/auth/register", r.authHandler.Register)
-	r.mux.HandleFunc("POST /api/v1/auth/login", r.authHandler.Login)
-	r.mux.HandleFunc("POST /api/v1/auth/refresh", r.authHandler.RefreshToken)
+	r.mux.Handle("POST /api/v1/auth/register", handler.Func(r.authHandler.Register))
+	r.mux.Handle("POST /api/v1/auth/login", handler.Func(r.authHandler.Login))
+	r.mux.Handle("POST /api/v1/auth/refresh", handler.Func(r.authHandler.RefreshToken))
 	// Auth routes (protected)
-	r.mux.Handle("POST /api/v1/auth/logout", r.withAuth(http.HandlerFunc(r.authHandler.Logout)))
-	r.mux.Handle("POST /api/v1/auth/revoke", r.withAuth(http.HandlerFunc(r.authHandler.RevokeAllSessions)))
+	r.mux.Handle("POST /api/v1/auth/logout", r.withAuth(handler.Func(r.authHandler.Logout)))
+	r.mux.Handle("POST /api/v1/auth/revoke", r.withAuth(handler.Func(r.authHandler.RevokeAllSessions)))
+	r.mux.Handle("GET /api/v1/auth/sessions", r.withAuth(handler.Func(r.authHandler.ListSessions)))
 
 	// User routes (protected)
-	r.mux.Handle("GET /api/v1/users", r.withAuth(http.HandlerFunc(r.userHandler.GetProfile)))                           // GET all users (admin only)
-	r.mux.Handle("GET /api/v1/users/{id}", r.withAuth(http.HandlerFunc(r.userHandler.GetProfile)))                      // GET user by ID
-	r.mux.Handle("PUT /api/v1/users/{id}", r.withAuth(http.HandlerFunc(r.userHandler.UpdateUser)))                      // PUT/PATCH update user
-	r.mux.Handle("POST /api/v1/users/{id}/change-password", r.withAuth(http.HandlerFunc(r.userHandler.ChangePassword))) // POST change password
-	r.mux.Handle("DELETE /api/v1/users/{id}", r.withAuth(http.HandlerFunc(r.userHandler.DeleteUser)))                   // DELETE user
+	r.mux.Handle("GET /api/v1/users", r.withAuth(handler.Func(r.userHandler.GetProfile)))                           // GET all users (admin only)
+	r.mux.Handle("GET /api/v1/users/{id}", r.withAuth(handler.Func(r.userHandler.GetProfile)))                      // GET user by ID
+	r.mux.Handle("PUT /api/v1/users/{id}", r.withAuth(r.signed(handler.Func(r.userHandler.UpdateUser))))            // PUT/PATCH update user, may change the role
+	r.mux.Handle("POST /api/v1/users/{id}/change-password", r.withAuth(handler.Func(r.userHandler.ChangePassword))) // POST change password
+	r.mux.Handle("DELETE /api/v1/users/{id}", r.withAuth(r.signed(handler.Func(r.userHandler.DeleteUser))))         // DELETE user
+	r.mux.Handle("GET /api/v1/users/{id}/order-stats", r.withAuth(http.HandlerFunc(r.orderHandler.CustomerStats)))  // GET order statistics (self or admin)
+	r.mux.Handle("POST /api/v1/admin/users/{id}/restore", r.withAuthAndRole(handler.Func(r.userHandler.RestoreUser), entities.RoleAdmin))
 
 	// Admin management user (protected)
-	//r.mux.Handle("PUT /api/v1/users/{id}", r.withAuthAndRole(http.HandlerFunc(r.userHandler.UpdateUser), entities.RoleAdmin))
+	//r.mux.Handle("PUT /api/v1/users/{id}", r.withAuthAndRole(handler.Func(r.userHandler.UpdateUser), entities.RoleAdmin))
 
 	// Product routes (public)
-	r.mux.HandleFunc("GET /api/v1/products", http.HandlerFunc(r.productHandler.List))
-	r.mux.HandleFunc("GET /api/v1/products/{id}", http.HandlerFunc(r.productHandler.GetByID))
+	r.mux.Handle("GET /api/v1/products", r.compressed(r.cached(http.HandlerFunc(r.productHandler.List))))
+	r.mux.Handle("GET /api/v1/products/{id}", r.cached(http.HandlerFunc(r.productHandler.GetByID)))
+	r.mux.HandleFunc("POST /api/v1/products/batch", r.productHandler.GetByIDs)
+	r.mux.Handle("GET /api/v1/products/{id}/related", r.compressed(r.cached(http.HandlerFunc(r.productHandler.ListRelated))))
+
+	// Product routes, v2: same filters and services, prices with their currency
+	v2 := r.version("v2")
+	v2.handle("GET /products", r.compressed(r.cached(http.HandlerFunc(r.productV2Handler.List))))
+	v2.handle("GET /products/{id}", r.cached(http.HandlerFunc(r.productV2Handler.GetByID)))
 
 	// Product routes (protected)
-	r.mux.Handle("POST /api/v1/products", r.withAuthAndRole(http.HandlerFunc(r.productHandler.CreateProduct), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/products", r.withAuthAndRole(r.idempotent(http.HandlerFunc(r.productHandler.CreateProduct)), entities.RoleAdmin))
 	r.mux.Handle("PUT /api/v1/products/{id}", r.withAuthAndRole(http.HandlerFunc(r.productHandler.Update), entities.RoleAdmin))
-	r.mux.Handle("PATCH /api/products/{id}", r.withAuthAndRole(http.HandlerFunc(r.productHandler.Update), entities.RoleAdmin))
+	r.mux.Handle("PATCH /api/v1/products/{id}", r.withAuthAndRole(http.HandlerFunc(r.productHandler.Update), entities.RoleAdmin))
 	r.mux.Handle("DELETE /api/v1/products/{id}", r.withAuthAndRole(http.HandlerFunc(r.productHandler.Delete), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/products/import", r.withAuthAndRole(http.HandlerFunc(r.productHandler.Import), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/products/price-update", r.withAuthAndRole(r.signed(http.HandlerFunc(r.productHandler.BulkUpdatePrices)), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/products/archived", r.withAuthAndRole(r.compressed(http.HandlerFunc(r.productHandler.ListArchived)), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/products/low-stock", r.withAuthAndRole(r.compressed(http.HandlerFunc(r.productHandler.ListLowStock)), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/products/stats", r.withAuthAndRole(http.HandlerFunc(r.productHandler.Stats), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/reports/forecast", r.withAuthAndRole(http.HandlerFunc(r.productHandler.Forecast), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/products/{id}/references", r.withAuthAndRole(http.HandlerFunc(r.productHandler.References), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/products/{id}/restore", r.withAuthAndRole(http.HandlerFunc(r.productHandler.Restore), entities.RoleAdmin))
+
+	// Back-in-stock subscriptions (protected)
+	r.mux.Handle("POST /api/v1/products/{id}/subscription", r.withAuth(http.HandlerFunc(r.stockSubHandler.Subscribe)))
+	r.mux.Handle("DELETE /api/v1/products/{id}/subscription", r.withAuth(http.HandlerFunc(r.stockSubHandler.Unsubscribe)))
+	r.mux.Handle("GET /api/v1/stock-subscriptions", r.withAuth(http.HandlerFunc(r.stockSubHandler.List)))
+
+	// Product image routes
+	r.mux.Handle("GET /api/v1/products/{id}/images", r.cached(http.HandlerFunc(r.imageHandler.List)))
+	r.mux.Handle("POST /api/v1/products/{id}/images", r.withAuthAndRole(http.HandlerFunc(r.imageHandler.Upload), entities.RoleAdmin))
+	r.mux.Handle("PUT /api/v1/products/{id}/images/order", r.withAuthAndRole(http.HandlerFunc(r.imageHandler.Reorder), entities.RoleAdmin))
+	r.mux.Handle("PATCH /api/v1/products/{id}/images/{imageId}/primary", r.withAuthAndRole(http.HandlerFunc(r.imageHandler.SetPrimary), entities.RoleAdmin))
+	r.mux.Handle("DELETE /api/v1/products/{id}/images/{imageId}", r.withAuthAndRole(http.HandlerFunc(r.imageHandler.Delete), entities.RoleAdmin))
+
+	// Catalog snapshot routes (admin)
+	r.mux.Handle("GET /api/v1/admin/catalog-snapshots", r.withAuthAndRole(http.HandlerFunc(r.snapshotHandler.List), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/catalog-snapshots", r.withAuthAndRole(http.HandlerFunc(r.snapshotHandler.Create), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/catalog-snapshots/diff", r.withAuthAndRole(http.HandlerFunc(r.snapshotHandler.Diff), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/catalog-snapshots/{id}/rollback", r.withAuthAndRole(http.HandlerFunc(r.snapshotHandler.Rollback), entities.RoleAdmin))
+
+	// Warehouse and stock transfer routes (admin)
+	r.mux.Handle("GET /api/v1/admin/warehouses", r.withAuthAndRole(http.HandlerFunc(r.warehouseHandler.List), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/warehouses", r.withAuthAndRole(http.HandlerFunc(r.warehouseHandler.Create), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/warehouses/{id}/stock", r.withAuthAndRole(http.HandlerFunc(r.warehouseHandler.ListStock), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/warehouses/{id}/adjustments", r.withAuthAndRole(r.idempotent(http.HandlerFunc(r.warehouseHandler.Adjust)), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/stock-ledger", r.withAuthAndRole(http.HandlerFunc(r.warehouseHandler.ListLedger), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/stock-transfers", r.withAuthAndRole(http.HandlerFunc(r.transferHandler.List), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/stock-transfers", r.withAuthAndRole(r.idempotent(http.HandlerFunc(r.transferHandler.Create)), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/stock-transfers/{id}", r.withAuthAndRole(http.HandlerFunc(r.transferHandler.Get), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/stock-transfers/{id}/ship", r.withAuthAndRole(http.HandlerFunc(r.transferHandler.Ship), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/stock-transfers/{id}/receive", r.withAuthAndRole(http.HandlerFunc(r.transferHandler.Receive), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/stock-transfers/{id}/cancel", r.withAuthAndRole(http.HandlerFunc(r.transferHandler.Cancel), entities.RoleAdmin))
+
+	// Stock count routes (admin)
+	r.mux.Handle("GET /api/v1/admin/stock-counts", r.withAuthAndRole(http.HandlerFunc(r.countHandler.List), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/stock-counts", r.withAuthAndRole(r.idempotent(http.HandlerFunc(r.countHandler.Create)), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/stock-counts/{id}", r.withAuthAndRole(http.HandlerFunc(r.countHandler.Get), entities.RoleAdmin))
+	r.mux.Handle("PUT /api/v1/admin/stock-counts/{id}/items", r.withAuthAndRole(http.HandlerFunc(r.countHandler.RecordCounts), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/stock-counts/{id}/submit", r.withAuthAndRole(http.HandlerFunc(r.countHandler.Submit), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/stock-counts/{id}/approve", r.withAuthAndRole(http.HandlerFunc(r.countHandler.Approve), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/stock-counts/{id}/reject", r.withAuthAndRole(http.HandlerFunc(r.countHandler.Reject), entities.RoleAdmin))
+
+	// Uploaded files (local storage)
+	r.mux.Handle("GET "+r.cfg.Storage.BaseURL+"/", http.StripPrefix(r.cfg.Storage.BaseURL, http.FileServer(http.Dir(r.cfg.Storage.LocalDir))))
+
+	// Category routes (public)
+	r.mux.Handle("GET /api/v1/categories", r.cached(http.HandlerFunc(r.categoryHandler.List)))
+	r.mux.Handle("GET /api/v1/categories/tree", r.cached(http.HandlerFunc(r.categoryHandler.Tree)))
+	r.mux.Handle("GET /api/v1/categories/{id}", r.cached(http.HandlerFunc(r.categoryHandler.GetByID)))
+
+	// Category routes (protected)
+	r.mux.Handle("POST /api/v1/categories", r.withAuthAndRole(http.HandlerFunc(r.categoryHandler.Create), entities.RoleAdmin))
+	r.mux.Handle("PUT /api/v1/categories/{id}", r.withAuthAndRole(http.HandlerFunc(r.categoryHandler.Update), entities.RoleAdmin))
+	r.mux.Handle("DELETE /api/v1/categories/{id}", r.withAuthAndRole(http.HandlerFunc(r.categoryHandler.Delete), entities.RoleAdmin))
+
+	// Category attribute routes
+	r.mux.Handle("GET /api/v1/categories/{id}/attributes", r.cached(http.HandlerFunc(r.attrHandler.ListByCategory)))
+	r.mux.Handle("POST /api/v1/categories/{id}/attributes", r.withAuthAndRole(http.HandlerFunc(r.attrHandler.Create), entities.RoleAdmin))
+	r.mux.Handle("PUT /api/v1/attributes/{id}", r.withAuthAndRole(http.HandlerFunc(r.attrHandler.Update), entities.RoleAdmin))
+	r.mux.Handle("DELETE /api/v1/attributes/{id}", r.withAuthAndRole(http.HandlerFunc(r.attrHandler.Delete), entities.RoleAdmin))
+
+	// Tag routes
+	r.mux.Handle("GET /api/v1/tags", r.cached(http.HandlerFunc(r.tagHandler.List)))
+	r.mux.Handle("POST /api/v1/tags", r.withAuthAndRole(http.HandlerFunc(r.tagHandler.Create), entities.RoleAdmin))
+	r.mux.Handle("PUT /api/v1/tags/{id}", r.withAuthAndRole(http.HandlerFunc(r.tagHandler.Update), entities.RoleAdmin))
+	r.mux.Handle("DELETE /api/v1/tags/{id}", r.withAuthAndRole(http.HandlerFunc(r.tagHandler.Delete), entities.RoleAdmin))
+
+	// Deprecated routes
+	// The health checks moved to /healthz and /readyz, where probes and load balancers look
+	r.mux.Handle("GET /api/v1/health", r.deprecated(
+		http.HandlerFunc(r.healthHandler.Live),
+		middleware.DeprecationInfo{
+			Since:     time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC),
+			Sunset:    time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC),
+			Successor: "/healthz",
+		},
+	))
+	r.mux.Handle("GET /api/v1/health/ready", r.deprecated(
+		http.HandlerFunc(r.healthHandler.Ready),
+		middleware.DeprecationInfo{
+			Since:     time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC),
+			Sunset:    time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC),
+			Successor: "/readyz",
+		},
+	))
+	// PATCH /api/products/{id} was registered without the version prefix, use PATCH /api/v1/products/{id}
+	r.mux.Handle("PATCH /api/products/{id}", r.deprecated(
+		r.withAuthAndRole(http.HandlerFunc(r.productHandler.Update), entities.RoleAdmin),
+		middleware.DeprecationInfo{
+			Since:     time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+			Sunset:    time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC),
+			Successor: "/api/v1/products/{id}",
+		},
+	))
 
 	// Order routes (protected)
-	r.mux.Handle("GET /api/v1/orders", r.withAuth(http.HandlerFunc(r.orderHandler.ListOrders)))
+	r.mux.Handle("GET /api/v1/orders", r.withAuth(r.compressed(http.HandlerFunc(r.orderHandler.ListOrders))))
 	r.mux.Handle("GET /api/v1/orders/{id}", r.withAuth(http.HandlerFunc(r.orderHandler.GetOrderByID)))
-	r.mux.Handle("POST /api/v1/orders", r.withAuthAndRole(http.HandlerFunc(r.orderHandler.CreateOrder), entities.RoleUser))
-	r.mux.Handle("PATCH /api/v1/orders/{id}/status", r.withAuthAndRole(http.HandlerFunc(r.orderHandler.UpdateOrderStatus), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/orders", r.withAuthAndRole(r.idempotent(http.HandlerFunc(r.orderHandler.CreateOrder)), entities.RoleUser))
+	r.mux.Handle("PATCH /api/v1/orders/{id}/status", r.withAuth(r.signed(http.HandlerFunc(r.orderHandler.UpdateOrderStatus)))) // admins, or customers cancelling or confirming delivery
+
+	// Shipping routes: the methods offered at checkout are public, users manage their
+	// own address book
+	r.mux.HandleFunc("GET /api/v1/shipping/methods", r.shippingHandler.Methods)
+	r.mux.Handle("GET /api/v1/addresses", r.withAuth(http.HandlerFunc(r.shippingHandler.ListAddresses)))
+	r.mux.Handle("POST /api/v1/addresses", r.withAuth(http.HandlerFunc(r.shippingHandler.CreateAddress)))
+	r.mux.Handle("PATCH /api/v1/addresses/{id}", r.withAuth(http.HandlerFunc(r.shippingHandler.UpdateAddress)))
+	r.mux.Handle("DELETE /api/v1/addresses/{id}", r.withAuth(http.HandlerFunc(r.shippingHandler.DeleteAddress)))
 
-	return middleware.Logger(r.mux)
+	// Consent routes: users manage their own consents, admins read the records of any
+	// user for compliance requests
+	r.mux.Handle("GET /api/v1/consents", r.withAuth(http.HandlerFunc(r.consentHandler.List)))
+	r.mux.Handle("PUT /api/v1/consents", r.withAuth(http.HandlerFunc(r.consentHandler.Update)))
+	r.mux.Handle("GET /api/v1/consents/history", r.withAuth(http.HandlerFunc(r.consentHandler.History)))
+	r.mux.Handle("GET /api/v1/admin/users/{id}/consents/history", r.withAuthAndRole(http.HandlerFunc(r.consentHandler.UserHistory), entities.RoleAdmin))
+
+	// Notification preferences of the signed-in user
+	r.mux.Handle("GET /api/v1/notification-preferences", r.withAuth(http.HandlerFunc(r.notifyHandler.Preferences)))
+	r.mux.Handle("PUT /api/v1/notification-preferences", r.withAuth(http.HandlerFunc(r.notifyHandler.UpdatePreferences)))
+
+	// Guest checkout, public and rate limited per client IP while enabled. Guests claim
+	// their orders after registering the email, with the emailed tracking link
+	if r.cfg.Orders.GuestCheckout {
+		r.mux.Handle("POST /api/v1/guest/orders", middleware.RateLimit(r.rateLimiter, "guest_checkout", r.cfg.Orders.GuestRateLimit, r.cfg.Orders.GuestRateWindow)(
+			http.HandlerFunc(r.orderHandler.CreateGuestOrder),
+		))
+	}
+	r.mux.Handle("POST /api/v1/orders/claim", r.withAuth(http.HandlerFunc(r.trackingHandler.ClaimGuestOrders)))
+
+	// Order tracking with the shipments of the order (protected)
+	r.mux.Handle("GET /api/v1/orders/{id}/tracking", r.withAuth(http.HandlerFunc(r.trackingHandler.TrackOrder)))
+
+	// Order tracking link routes (protected)
+	r.mux.Handle("POST /api/v1/orders/{id}/tracking-link", r.withAuth(http.HandlerFunc(r.trackingHandler.CreateLink)))
+	r.mux.Handle("DELETE /api/v1/orders/{id}/tracking-link", r.withAuth(http.HandlerFunc(r.trackingHandler.RevokeLinks)))
+
+	// Public order tracking page, rate limited per client IP
+	r.mux.Handle("GET /api/v1/track/{token}", middleware.RateLimit(r.rateLimiter, "tracking", r.cfg.Tracking.RateLimit, r.cfg.Tracking.RateWindow)(
+		http.HandlerFunc(r.trackingHandler.Track),
+	))
+
+	// Order fulfilment routes (admin)
+	r.mux.Handle("GET /api/v1/admin/orders/{id}/packing-slip", r.withAuthAndRole(http.HandlerFunc(r.orderHandler.PackingSlip), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/orders/packing-slips", r.withAuthAndRole(r.compressed(http.HandlerFunc(r.orderHandler.PackingSlips)), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/orders/{id}/shipments", r.withAuthAndRole(http.HandlerFunc(r.orderHandler.AddShipment), entities.RoleAdmin))
+	// Order state at a point in time, for support disputes (admin)
+	r.mux.Handle("GET /api/v1/admin/orders/{id}/as-of", r.withAuthAndRole(http.HandlerFunc(r.historyHandler.AsOf), entities.RoleAdmin))
+
+	// Payment routes: customers start the payment of their order, the provider reports
+	// the outcome to the public webhook, which verifies the provider's signature
+	r.mux.Handle("POST /api/v1/orders/{id}/payment", r.withAuth(r.idempotent(http.HandlerFunc(r.paymentHandler.CreateIntent))))
+	r.mux.Handle("POST /api/v1/payments/webhook", http.HandlerFunc(r.paymentHandler.Webhook))
+
+	// Payment capture routes (admin)
+	r.mux.Handle("POST /api/v1/admin/orders/{id}/capture", r.withAuthAndRole(r.idempotent(r.signed(http.HandlerFunc(r.captureHandler.Start))), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/orders/{id}/capture", r.withAuthAndRole(http.HandlerFunc(r.captureHandler.Get), entities.RoleAdmin))
+	// Refund routes (admin)
+	r.mux.Handle("POST /api/v1/admin/orders/{id}/refund", r.withAuthAndRole(r.idempotent(r.signed(http.HandlerFunc(r.refundHandler.Refund))), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/orders/{id}/refunds", r.withAuthAndRole(http.HandlerFunc(r.refundHandler.List), entities.RoleAdmin))
+	// Payment reconciliation routes (admin)
+	r.mux.Handle("POST /api/v1/admin/reconciliation/reports", r.withAuthAndRole(http.HandlerFunc(r.reconHandler.UploadReport), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/reconciliation/runs", r.withAuthAndRole(http.HandlerFunc(r.reconHandler.ListRuns), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/reconciliation/issues", r.withAuthAndRole(http.HandlerFunc(r.reconHandler.ListIssues), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/reconciliation/issues/{id}/resolve", r.withAuthAndRole(http.HandlerFunc(r.reconHandler.ResolveIssue), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/reconciliation/summary", r.withAuthAndRole(http.HandlerFunc(r.reconHandler.Summary), entities.RoleAdmin))
+	// Email template routes (admin)
+	r.mux.Handle("POST /api/v1/admin/email-templates", r.withAuthAndRole(http.HandlerFunc(r.emailHandler.Create), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/email-templates", r.withAuthAndRole(http.HandlerFunc(r.emailHandler.List), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/email-templates/{id}", r.withAuthAndRole(http.HandlerFunc(r.emailHandler.Get), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/email-templates/{id}/publish", r.withAuthAndRole(http.HandlerFunc(r.emailHandler.Publish), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/email-templates/{id}/preview", r.withAuthAndRole(http.HandlerFunc(r.emailHandler.Preview), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/email-templates/{id}/send-test", r.withAuthAndRole(http.HandlerFunc(r.emailHandler.SendTest), entities.RoleAdmin))
+	// Background job routes (admin)
+	r.mux.Handle("GET /api/v1/admin/jobs/failed", r.withAuthAndRole(http.HandlerFunc(r.jobHandler.ListFailed), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/jobs/stats", r.withAuthAndRole(http.HandlerFunc(r.jobHandler.Stats), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/jobs/{id}", r.withAuthAndRole(http.HandlerFunc(r.jobHandler.Get), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/jobs/{id}/retry", r.withAuthAndRole(http.HandlerFunc(r.jobHandler.Retry), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/jobs/{id}/cancel", r.withAuthAndRole(http.HandlerFunc(r.jobHandler.Cancel), entities.RoleAdmin))
+	// Metrics dashboard for deployments without Grafana (admin)
+	r.mux.Handle("GET /api/v1/admin/dashboard", r.withAuthAndRole(http.HandlerFunc(r.dashboardHandler.Dashboard), entities.RoleAdmin))
+	// Service level objectives and their error budgets (admin)
+	r.mux.Handle("GET /api/v1/admin/slo", r.withAuthAndRole(http.HandlerFunc(r.sloHandler.Report), entities.RoleAdmin))
+	// Order webhooks for external systems and their delivery log (admin)
+	r.mux.Handle("POST /api/v1/admin/webhooks", r.withAuthAndRole(http.HandlerFunc(r.webhookHandler.Create), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/webhooks", r.withAuthAndRole(http.HandlerFunc(r.webhookHandler.List), entities.RoleAdmin))
+	r.mux.Handle("PATCH /api/v1/admin/webhooks/{id}", r.withAuthAndRole(http.HandlerFunc(r.webhookHandler.Update), entities.RoleAdmin))
+	r.mux.Handle("DELETE /api/v1/admin/webhooks/{id}", r.withAuthAndRole(http.HandlerFunc(r.webhookHandler.Delete), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/webhooks/{id}/deliveries", r.withAuthAndRole(http.HandlerFunc(r.webhookHandler.Deliveries), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/outbox", r.withAuthAndRole(http.HandlerFunc(r.outboxHandler.Status), entities.RoleAdmin))
+	// Inventory stream routes (admin)
+	r.mux.Handle("GET /api/v1/admin/inventory/stream", r.withAuthAndRole(http.HandlerFunc(r.streamHandler.Stream), entities.RoleAdmin))
+	// Bulk token revocation routes (admin)
+	r.mux.Handle("POST /api/v1/admin/tokens/revoke", r.withAuthAndRole(r.signed(handler.Func(r.authHandler.RevokeTokens)), entities.RoleAdmin))
+	// Auth state backup routes (admin)
+	r.mux.Handle("POST /api/v1/admin/auth-state/backups", r.withAuthAndRole(http.HandlerFunc(r.authStateHandler.CreateBackup), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/auth-state/backups", r.withAuthAndRole(http.HandlerFunc(r.authStateHandler.ListBackups), entities.RoleAdmin))
+	r.mux.Handle("POST /api/v1/admin/auth-state/backups/{id}/restore", r.withAuthAndRole(r.signed(http.HandlerFunc(r.authStateHandler.Restore)), entities.RoleAdmin))
+	r.mux.Handle("GET /api/v1/admin/auth-state/restores", r.withAuthAndRole(http.HandlerFunc(r.authStateHandler.ListRestores), entities.RoleAdmin))
+	// Field usage report (admin), which clients still send or read a field
+	r.mux.Handle("GET /api/v1/admin/field-usage", r.withAuthAndRole(http.HandlerFunc(r.fieldHandler.Report), entities.RoleAdmin))
+	// Access log export routes (protected), users export their own traffic
+	r.mux.Handle("POST /api/v1/access-logs/exports", r.withAuth(r.idempotent(http.HandlerFunc(r.accessHandler.CreateExport))))
+	r.mux.Handle("GET /api/v1/access-logs/exports", r.withAuth(http.HandlerFunc(r.accessHandler.ListExports)))
+	r.mux.Handle("GET /api/v1/access-logs/exports/{id}", r.withAuth(http.HandlerFunc(r.accessHandler.GetExport)))
+	r.mux.Handle("GET /api/v1/access-logs/exports/{id}/download", r.withAuth(http.HandlerFunc(r.accessHandler.DownloadExport)))
+	// API client routes (protected), users manage their own clients
+	r.mux.Handle("POST /api/v1/clients", r.withAuth(http.HandlerFunc(r.clientHandler.Create)))
+	r.mux.Handle("GET /api/v1/clients", r.withAuth(http.HandlerFunc(r.clientHandler.List)))
+	r.mux.Handle("GET /api/v1/clients/{id}", r.withAuth(http.HandlerFunc(r.clientHandler.Get)))
+	r.mux.Handle("PATCH /api/v1/clients/{id}", r.withAuth(http.HandlerFunc(r.clientHandler.Update)))
+	r.mux.Handle("DELETE /api/v1/clients/{id}", r.withAuth(http.HandlerFunc(r.clientHandler.Delete)))
+	r.mux.Handle("POST /api/v1/clients/{id}/rotate-secret", r.withAuth(http.HandlerFunc(r.clientHandler.RotateSecret)))
+	r.mux.Handle("GET /api/v1/clients/{id}/usage", r.withAuth(http.HandlerFunc(r.clientHandler.Usage)))
+	// OAuth / OpenID Connect provider routes, the signed-in user approves requests of
+	// API clients, which then redeem the code at the token endpoint
+	r.mux.HandleFunc("GET /.well-known/openid-configuration", r.oauthHandler.Discovery)
+	r.mux.HandleFunc("GET /.well-known/jwks.json", r.oauthHandler.JWKS)
+	r.mux.Handle("GET /api/v1/oauth/authorize", r.withAuth(http.HandlerFunc(r.oauthHandler.Consent)))
+	r.mux.Handle("POST /api/v1/oauth/authorize", r.withAuth(http.HandlerFunc(r.oauthHandler.Authorize)))
+	r.mux.Handle("POST /api/v1/oauth/token", middleware.RateLimit(r.rateLimiter, "oauth_token", r.cfg.OAuth.TokenRateLimit, r.cfg.OAuth.TokenRateWindow)(
+		http.HandlerFunc(r.oauthHandler.Token),
+	))
+	r.mux.HandleFunc("GET /api/v1/oauth/userinfo", r.oauthHandler.UserInfo)
+	r.mux.HandleFunc("POST /api/v1/oauth/userinfo", r.oauthHandler.UserInfo)
+
+	stack := r.middleware()
+	logger.Info("middleware stack", "order", strings.Join(stack.Names(), ","))
+	return stack.Then(http.HandlerFunc(r.serve))
+}
+
+// middleware returns the global middleware stack, outermost first, without the ones
+// MIDDLEWARE_DISABLED names. The request ID comes first so every log line carries it, and
+// requests are logged before anything may reject them
+func (r *Router) middleware() *middleware.Stack {
+	disabled := make(map[string]bool, len(r.cfg.Server.DisabledMiddleware))
+	for _, name := range r.cfg.Server.DisabledMiddleware {
+		disabled[name] = true
+	}
+
+	stack := &middleware.Stack{}
+	use := func(name string, mw middleware.Middleware) {
+		if disabled[name] {
+			delete(disabled, name)
+			return
+		}
+		stack.Use(name, mw)
+	}
+	use("request_id", middleware.RequestID)
+	use("real_ip", middleware.RealIP(r.prefixes("TRUSTED_PROXIES", r.cfg.Network.TrustedProxies)))
+	use("logger", middleware.Logger)
+	use("metrics", middleware.Metrics(r.route))
+	use("access_log", middleware.AccessLog(r.accessLogs))
+	use("recover", middleware.Recover)
+	use("ip_filter", r.ipFilter())
+	use("cors", r.cors())
+	use("field_usage", middleware.FieldUsage(r.fieldUsage, r.cfg.Fields.SampleRate, r.route))
+	use("api_version", r.versionDeprecation())
+	use("negotiate", response.Negotiate)
+	use("language", response.Localize)
+	use("rate_limit", r.rateLimit())
+	use("load_shed", r.loadShed())
+	use("timeout", r.timeout())
+	use("max_body_size", middleware.MaxBodySize(r.cfg.Server.MaxBodySize))
+	use("replica_reads", r.replicaReads())
+
+	for name := range disabled {
+		logger.Warn("ignoring unknown middleware in MIDDLEWARE_DISABLED", "name", name)
+	}
+	return stack
+}
+
+// ipFilter returns the IP filter middleware, nil when IP_DENYLIST and ADMIN_IP_ALLOWLIST
+// are empty. The allowlist covers the /api/v1/admin/ routes; when none of its entries is
+// valid they are closed to everyone rather than left open
+func (r *Router) ipFilter() middleware.Middleware {
+	cfg := r.cfg.Network
+	if len(cfg.DenyList) == 0 && len(cfg.AdminAllowList) == 0 {
+		return nil
+	}
+	deny := r.prefixes("IP_DENYLIST", cfg.DenyList)
+	allow := r.prefixes("ADMIN_IP_ALLOWLIST", cfg.AdminAllowList)
+	restrictAdmin := len(cfg.AdminAllowList) > 0
+	return middleware.IPFilter(deny, allow, func(req *http.Request) bool {
+		return restrictAdmin && strings.HasPrefix(req.URL.Path, "/api/v1/admin/")
+	})
+}
+
+// prefixes parses the IPs and CIDR ranges of the setting name, skipping invalid entries
+func (r *Router) prefixes(name string, entries []string) []netip.Prefix {
+	prefixes, invalid := middleware.ParsePrefixes(entries)
+	for _, entry := range invalid {
+		logger.Warn("ignoring invalid IP range", "setting", name, "entry", entry)
+	}
+	return prefixes
+}
+
+// cors returns the CORS middleware, nil when CORS_ALLOWED_ORIGINS is empty
+func (r *Router) cors() middleware.Middleware {
+	cfg := r.cfg.CORS
+	if len(cfg.AllowedOrigins) == 0 {
+		return nil
+	}
+	return middleware.CORS(middleware.CORSPolicy{
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	})
+}
+
+// replicaReads returns the middleware sending the reads of GET requests to the read
+// replicas, nil when DB_REPLICA_URLS is empty
+func (r *Router) replicaReads() middleware.Middleware {
+	if len(r.cfg.DB.ReplicaURLs) == 0 {
+		return nil
+	}
+	return middleware.ReplicaReads
+}
+
+// timeout returns the request timeout middleware, REQUEST_TIMEOUT unless the route has
+// its own
+func (r *Router) timeout() middleware.Middleware {
+	overrides := make(map[string]time.Duration, len(r.cfg.Server.TimeoutRoutes))
+	for _, entry := range r.cfg.Server.TimeoutRoutes {
+		pattern, value, _ := strings.Cut(entry, "=")
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			logger.Warn("ignoring request timeout route with an invalid duration", "entry", entry)
+			continue
+		}
+		overrides[strings.TrimSpace(pattern)] = d
+	}
+
+	return middleware.Timeout(func(req *http.Request) time.Duration {
+		pattern := r.route(req)
+		if d, ok := overrides[pattern]; ok {
+			return d
+		}
+		if d, ok := routeTimeouts[pattern]; ok {
+			return d
+		}
+		return r.cfg.Server.RequestTimeout
+	})
+}
+
+// rateLimit returns the token bucket rate limit middleware, nil when
+// RATE_LIMIT_PER_MINUTE is 0
+func (r *Router) rateLimit() middleware.Middleware {
+	cfg := r.cfg.RateLimit
+	if cfg.PerMinute <= 0 {
+		return nil
+	}
+
+	overrides := make(map[string]middleware.RateTier, len(cfg.Routes))
+	for _, entry := range cfg.Routes {
+		pattern, name, _ := strings.Cut(entry, "=")
+		tier, ok := middleware.ParseRateTier(strings.TrimSpace(name))
+		if !ok {
+			logger.Warn("ignoring rate limit route with an unknown tier", "entry", entry)
+			continue
+		}
+		overrides[strings.TrimSpace(pattern)] = tier
+	}
+
+	bucket := func(perMinute, burst int) middleware.TokenBucket {
+		return middleware.TokenBucket{Rate: float64(perMinute) / 60, Burst: max(1, burst)}
+	}
+	buckets := map[middleware.RateTier]middleware.TokenBucket{
+		middleware.RateTierDefault: bucket(cfg.PerMinute, cfg.Burst),
+		middleware.RateTierAuth:    bucket(cfg.PerMinute, cfg.Burst),
+		middleware.RateTierCatalog: bucket(cfg.PerMinute, cfg.Burst),
+	}
+	if cfg.AuthPerMinute > 0 {
+		buckets[middleware.RateTierAuth] = bucket(cfg.AuthPerMinute, cfg.AuthBurst)
+	}
+	if cfg.CatalogPerMinute > 0 {
+		buckets[middleware.RateTierCatalog] = bucket(cfg.CatalogPerMinute, cfg.CatalogBurst)
+	}
+
+	return middleware.TokenBucketRateLimit(r.rateLimiter, buckets, func(req *http.Request) middleware.RateTier {
+		pattern := r.route(req)
+		if tier, ok := overrides[pattern]; ok {
+			return tier
+		}
+		if tier, ok := routeRateTiers[pattern]; ok {
+			return tier
+		}
+		if method, path, _ := strings.Cut(pattern, " "); method == http.MethodGet {
+			for _, prefix := range catalogPaths {
+				if strings.HasPrefix(path, prefix) {
+					return middleware.RateTierCatalog
+				}
+			}
+		}
+		return middleware.RateTierDefault
+	}, r.rateLimitClient)
+}
+
+// rateLimitClient keys the rate limit of a request by the user of a valid bearer token,
+// so users behind one NAT don't share a bucket, and by client IP otherwise
+func (r *Router) rateLimitClient(req *http.Request) string {
+	if token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); ok {
+		if claims, err := r.jwtService.ValidateToken(token); err == nil {
+			return "user:" + claims.UserID.String()
+		}
+	}
+	return "ip:" + middleware.ClientIP(req)
+}
+
+// loadShed returns the load shedding middleware, nil when LOAD_SHED_MAX_IN_FLIGHT is 0
+func (r *Router) loadShed() middleware.Middleware {
+	cfg := r.cfg.LoadShed
+	if cfg.MaxInFlight <= 0 {
+		return nil
+	}
+
+	overrides := make(map[string]middleware.Priority, len(cfg.Routes))
+	for _, entry := range cfg.Routes {
+		pattern, name, _ := strings.Cut(entry, "=")
+		priority, ok := middleware.ParsePriority(strings.TrimSpace(name))
+		if !ok {
+			logger.Warn("ignoring load shedding route with an unknown tier", "entry", entry)
+			continue
+		}
+		overrides[strings.TrimSpace(pattern)] = priority
+	}
+
+	limits := map[middleware.Priority]int{
+		middleware.PriorityCheckout: cfg.MaxInFlight,
+		middleware.PriorityBrowse:   max(1, int(float64(cfg.MaxInFlight)*cfg.BrowseShare)),
+		middleware.PriorityReports:  max(1, int(float64(cfg.MaxInFlight)*cfg.ReportsShare)),
+	}
+	return middleware.LoadShed(limits, cfg.RetryAfter, func(req *http.Request) middleware.Priority {
+		pattern := r.route(req)
+		if priority, ok := overrides[pattern]; ok {
+			return priority
+		}
+		if priority, ok := routePriorities[pattern]; ok {
+			return priority
+		}
+		if _, path, _ := strings.Cut(pattern, " "); strings.HasPrefix(path, "/api/v1/admin/") {
+			return middleware.PriorityReports
+		}
+		return middleware.PriorityBrowse
+	})
+}
+
+// route returns the pattern of the route matching req, "" when none does
+func (r *Router) route(req *http.Request) string {
+	_, pattern := r.mux.Handler(req)
+	return pattern
 }
 
 // withAuthMiddleware applies authentication middleware to protected routes
@@ -96,3 +732,35 @@ func (r *Router) withAuthAndRole(h http.Handler, roles ...entities.Role) http.Ha
 		middleware.RequireRole(roles...)(h),
 	)
 }
+
+// signed applies replay protection to a high-value mutation, inside withAuth
+func (r *Router) signed(h http.Handler) http.Handler {
+	return middleware.ReplayProtection(r.nonces, r.jwtService.RequestSigningKey, r.cfg.Replay.Mode, r.cfg.Replay.Window)(h)
+}
+
+// idempotent lets clients retry a mutation safely with an Idempotency-Key, inside withAuth
+func (r *Router) idempotent(h http.Handler) http.Handler {
+	return middleware.Idempotency(r.idempotency, r.cfg.Idempotency.TTL)(h)
+}
+
+// compressed compresses the large JSON pages of a list route, unless COMPRESSION_LEVEL is 0
+func (r *Router) compressed(h http.Handler) http.Handler {
+	if r.cfg.Server.CompressionLevel == 0 {
+		return h
+	}
+	return middleware.Compress(r.cfg.Server.CompressionLevel, r.cfg.Server.CompressionMinSize)(h)
+}
+
+// cached serves a public catalog read from the response cache, inside compressed, unless
+// RESPONSE_CACHE_TTL is 0
+func (r *Router) cached(h http.Handler) http.Handler {
+	if r.cfg.Redis.ResponseCacheTTL == 0 {
+		return h
+	}
+	return middleware.ResponseCache(r.responseCache, entities.CacheScopeCatalog, r.cfg.Redis.ResponseCacheTTL)(h)
+}
+
+// deprecated marks a route as deprecated, emitting Deprecation/Sunset/Link headers
+func (r *Router) deprecated(h http.Handler, info middleware.DeprecationInfo) http.Handler {
+	return middleware.Deprecated(info)(h)
+}
@@ -0,0 +1,52 @@
+package routers
+
+import (
+	"net/http"
+	"postgresDB/internal/delivery/response"
+	apperror "postgresDB/internal/domain/errors"
+)
+
+// serve dispatches req to its route. The mux answers requests matching no route, or only
+// routes of other methods, with a plain text 404 or 405; those are answered with the
+// error envelope instead, keeping the Allow header of a 405
+func (r *Router) serve(w http.ResponseWriter, req *http.Request) {
+	if r.route(req) != "" {
+		r.mux.ServeHTTP(w, req)
+		return
+	}
+	r.mux.ServeHTTP(&unmatchedWriter{ResponseWriter: w}, req)
+}
+
+// unmatchedWriter replaces the plain text 404 and 405 of the mux with the error envelope
+type unmatchedWriter struct {
+	http.ResponseWriter
+	replaced bool
+}
+
+func (w *unmatchedWriter) WriteHeader(code int) {
+	var err error
+	switch code {
+	case http.StatusNotFound:
+		err = apperror.ErrRouteNotFound
+	case http.StatusMethodNotAllowed:
+		err = apperror.ErrMethodNotAllowed
+	default:
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.replaced = true
+	response.Error(w.ResponseWriter, err)
+}
+
+func (w *unmatchedWriter) Write(b []byte) (int, error) {
+	if w.replaced {
+		// the plain text body of the mux
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter (used by http.ResponseController)
+func (w *unmatchedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,95 @@
+package routers
+
+import (
+	"net/http"
+	"postgresDB/internal/delivery/middleware"
+	"postgresDB/pkg/logger"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiVersion is the route group of one version of the API, its routes are under
+// /api/<name>. Versions share the services, each maps them to its own DTOs
+type apiVersion struct {
+	router *Router
+	name   string
+}
+
+// version returns the route group of version name, e.g. v2
+func (r *Router) version(name string) *apiVersion {
+	return &apiVersion{router: r, name: name}
+}
+
+// handle registers h for pattern, "<method> <path>" with the path below the version
+func (v *apiVersion) handle(pattern string, h http.Handler) {
+	method, path, _ := strings.Cut(pattern, " ")
+	full := method + " /api/" + v.name + path
+	v.router.mux.Handle(full, h)
+	v.router.versioned[full] = true
+}
+
+// handleFunc registers f for pattern, see handle
+func (v *apiVersion) handleFunc(pattern string, f http.HandlerFunc) {
+	v.handle(pattern, f)
+}
+
+// versionDeprecation returns the middleware marking every route of the versions
+// API_DEPRECATED_VERSIONS names deprecated, linking to the same route of the next version
+// when it has one; nil while no version is deprecated
+func (r *Router) versionDeprecation() middleware.Middleware {
+	deprecated := make(map[int]middleware.DeprecationInfo, len(r.cfg.API.DeprecatedVersions))
+	for _, entry := range r.cfg.API.DeprecatedVersions {
+		name, sunset, _ := strings.Cut(entry, "=")
+		version, ok := parseVersion(strings.TrimSpace(name))
+		if !ok {
+			logger.Warn("ignoring deprecated API version with an invalid name", "entry", entry)
+			continue
+		}
+		var info middleware.DeprecationInfo
+		if sunset = strings.TrimSpace(sunset); sunset != "" {
+			t, err := time.Parse(time.DateOnly, sunset)
+			if err != nil {
+				logger.Warn("ignoring deprecated API version with an invalid sunset date", "entry", entry)
+				continue
+			}
+			info.Sunset = t
+		}
+		deprecated[version] = info
+	}
+	if len(deprecated) == 0 {
+		return nil
+	}
+
+	return middleware.DeprecatedWhen(func(req *http.Request) (middleware.DeprecationInfo, bool) {
+		rest, ok := strings.CutPrefix(req.URL.Path, "/api/")
+		if !ok {
+			return middleware.DeprecationInfo{}, false
+		}
+		name, _, _ := strings.Cut(rest, "/")
+		version, ok := parseVersion(name)
+		if !ok {
+			return middleware.DeprecationInfo{}, false
+		}
+		info, ok := deprecated[version]
+		if !ok {
+			return info, false
+		}
+
+		current, next := "/api/"+name+"/", "/api/v"+strconv.Itoa(version+1)+"/"
+		if pattern := r.route(req); pattern != "" && r.versioned[strings.Replace(pattern, current, next, 1)] {
+			info.Successor = strings.Replace(req.URL.Path, current, next, 1)
+		}
+		return info, true
+	})
+}
+
+// parseVersion returns the number of a version name such as v1
+func parseVersion(name string) (int, bool) {
+	digits, ok := strings.CutPrefix(name, "v")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(digits)
+	return n, err == nil && n > 0
+}
@@ -0,0 +1,124 @@
+package dto
+
+import (
+	"io"
+	"postgresDB/internal/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateAccessLogExportRequest represents the payload for exporting access logs of [from, to).
+// Users export their own requests; admins may pick any user_id or client_id, or neither
+// to export all traffic
+type CreateAccessLogExportRequest struct {
+	ClientID string     `json:"client_id" validate:"omitempty,max=100"`
+	UserID   *uuid.UUID `json:"user_id" validate:"omitempty"`
+	From     time.Time  `json:"from" validate:"required"`
+	To       time.Time  `json:"to" validate:"required,gtfield=From"`
+	// Format is "csv" (default) or "ndjson"
+	Format string `json:"format" validate:"omitempty,oneof=csv ndjson"`
+}
+
+// AccessLogExportResponse represents an access log export returned in responses
+type AccessLogExportResponse struct {
+	ID       string  `json:"id"`
+	ClientID string  `json:"client_id,omitempty"`
+	UserID   *string `json:"user_id"`
+	From     string  `json:"from"`
+	To       string  `json:"to"`
+	Format   string  `json:"format"`
+	Status   string  `json:"status"`
+	RowCount int64   `json:"row_count"`
+	Size     int64   `json:"size"`
+	Error    string  `json:"error,omitempty"`
+	// DownloadURL is set once the export is completed
+	DownloadURL string  `json:"download_url,omitempty"`
+	CreatedAt   string  `json:"created_at"`
+	CompletedAt *string `json:"completed_at"`
+}
+
+// AccessLogRecord is one access log line of an export file; CSV exports use the JSON
+// names as header
+type AccessLogRecord struct {
+	At         string  `json:"at"`
+	Method     string  `json:"method"`
+	Path       string  `json:"path"`
+	Status     int     `json:"status"`
+	DurationMs float64 `json:"duration_ms"`
+	Size       int64   `json:"size"`
+	IP         string  `json:"ip"`
+	ClientID   string  `json:"client_id"`
+	UserID     string  `json:"user_id"`
+	UserAgent  string  `json:"user_agent"`
+}
+
+// AccessLogExportFile is the downloadable file of a completed export
+type AccessLogExportFile struct {
+	Name        string
+	ContentType string
+	Size        int64
+	Body        io.ReadCloser
+}
+
+// ToAccessLogExportResponse converts an AccessLogExport entity to AccessLogExportResponse DTO
+func ToAccessLogExportResponse(e *entities.AccessLogExport) AccessLogExportResponse {
+	var userID *string
+	if e.UserID != nil {
+		id := e.UserID.String()
+		userID = &id
+	}
+	var completedAt *string
+	if e.CompletedAt != nil {
+		t := e.CompletedAt.Format(time.RFC3339)
+		completedAt = &t
+	}
+	var downloadURL string
+	if e.Status == entities.AccessLogExportCompleted {
+		downloadURL = "/api/v1/access-logs/exports/" + e.ID.String() + "/download"
+	}
+	return AccessLogExportResponse{
+		ID:          e.ID.String(),
+		ClientID:    e.ClientID,
+		UserID:      userID,
+		From:        e.From.Format(time.RFC3339),
+		To:          e.To.Format(time.RFC3339),
+		Format:      e.Format,
+		Status:      string(e.Status),
+		RowCount:    e.RowCount,
+		Size:        e.Size,
+		Error:       e.Error,
+		DownloadURL: downloadURL,
+		CreatedAt:   e.CreatedAt.Format(time.RFC3339),
+		CompletedAt: completedAt,
+	}
+}
+
+// ToAccessLogExportResponseList converts a slice of AccessLogExport entities to responses
+func ToAccessLogExportResponseList(exports []*entities.AccessLogExport) []AccessLogExportResponse {
+	responses := make([]AccessLogExportResponse, len(exports))
+	for i, e := range exports {
+		responses[i] = ToAccessLogExportResponse(e)
+	}
+	return responses
+}
+
+// ToAccessLogRecord converts an AccessLog entity to an export line
+func ToAccessLogRecord(l *entities.AccessLog) AccessLogRecord {
+	var userID string
+	if l.UserID != nil {
+		userID = l.UserID.String()
+	}
+	return AccessLogRecord{
+		At:         l.At.UTC().Format(time.RFC3339Nano),
+		Method:     l.Method,
+		Path:       l.Path,
+		Status:     l.Status,
+		DurationMs: l.DurationMs,
+		Size:       l.Size,
+		IP:         l.IP,
+		ClientID:   l.ClientID,
+		UserID:     userID,
+		UserAgent:  l.UserAgent,
+	}
+}
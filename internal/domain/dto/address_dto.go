@@ -0,0 +1,128 @@
+package dto
+
+import (
+	"postgresDB/internal/domain/entities"
+	"time"
+)
+
+// CreateAddressRequest represents the payload for adding an address to the address book
+type CreateAddressRequest struct {
+	Label         string `json:"label" validate:"omitempty,max=50"`
+	RecipientName string `json:"recipient_name" validate:"required,max=100"`
+	Phone         string `json:"phone" validate:"required,max=30"`
+	Street        string `json:"street" validate:"required,max=255"`
+	City          string `json:"city" validate:"required,max=100"`
+	Province      string `json:"province" validate:"omitempty,max=100"`
+	PostalCode    string `json:"postal_code" validate:"required,max=20"`
+	// Country is an ISO 3166-1 alpha-2 code, ID when omitted
+	Country   string `json:"country" validate:"omitempty,len=2,alpha"`
+	IsDefault bool   `json:"is_default"`
+}
+
+// UpdateAddressRequest represents the payload for updating an address, omitted fields
+// are kept
+type UpdateAddressRequest struct {
+	Label         *string `json:"label" validate:"omitempty,max=50"`
+	RecipientName *string `json:"recipient_name" validate:"omitempty,min=1,max=100"`
+	Phone         *string `json:"phone" validate:"omitempty,min=1,max=30"`
+	Street        *string `json:"street" validate:"omitempty,min=1,max=255"`
+	City          *string `json:"city" validate:"omitempty,min=1,max=100"`
+	Province      *string `json:"province" validate:"omitempty,max=100"`
+	PostalCode    *string `json:"postal_code" validate:"omitempty,min=1,max=20"`
+	Country       *string `json:"country" validate:"omitempty,len=2,alpha"`
+	// IsDefault true makes this the default address, false is ignored
+	IsDefault *bool `json:"is_default"`
+}
+
+// ShippingAddressRequest represents the address a guest order ships to, guests have no
+// address book
+type ShippingAddressRequest struct {
+	RecipientName string `json:"recipient_name" validate:"required,max=100"`
+	Phone         string `json:"phone" validate:"required,max=30"`
+	Street        string `json:"street" validate:"required,max=255"`
+	City          string `json:"city" validate:"required,max=100"`
+	Province      string `json:"province" validate:"omitempty,max=100"`
+	PostalCode    string `json:"postal_code" validate:"required,max=20"`
+	// Country is an ISO 3166-1 alpha-2 code, ID when omitted
+	Country string `json:"country" validate:"omitempty,len=2,alpha"`
+}
+
+// AddressResponse represents an address book entry returned in responses
+type AddressResponse struct {
+	ID            string `json:"id"`
+	Label         string `json:"label,omitempty"`
+	RecipientName string `json:"recipient_name"`
+	Phone         string `json:"phone"`
+	Street        string `json:"street"`
+	City          string `json:"city"`
+	Province      string `json:"province,omitempty"`
+	PostalCode    string `json:"postal_code"`
+	Country       string `json:"country"`
+	IsDefault     bool   `json:"is_default"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+// ShippingAddressResponse represents the address an order ships to
+type ShippingAddressResponse struct {
+	RecipientName string `json:"recipient_name"`
+	Phone         string `json:"phone"`
+	Street        string `json:"street"`
+	City          string `json:"city"`
+	Province      string `json:"province,omitempty"`
+	PostalCode    string `json:"postal_code"`
+	Country       string `json:"country"`
+}
+
+// ShippingMethodResponse represents a shipping method offered at checkout
+type ShippingMethodResponse struct {
+	Code string `json:"code"`
+	// Calculator is flat or weight
+	Calculator string `json:"calculator"`
+	BaseCost   int64  `json:"base_cost"`
+	// CostPerKg is added for every started kilogram by weight-based methods
+	CostPerKg int64 `json:"cost_per_kg,omitempty"`
+}
+
+// ToAddressResponse converts an Address entity to AddressResponse DTO
+func ToAddressResponse(a *entities.Address) AddressResponse {
+	return AddressResponse{
+		ID:            a.ID.String(),
+		Label:         a.Label,
+		RecipientName: a.RecipientName,
+		Phone:         a.Phone,
+		Street:        a.Street,
+		City:          a.City,
+		Province:      a.Province,
+		PostalCode:    a.PostalCode,
+		Country:       a.Country,
+		IsDefault:     a.IsDefault,
+		CreatedAt:     a.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     a.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToAddressResponseList converts a slice of Address entities to responses
+func ToAddressResponseList(addresses []*entities.Address) []AddressResponse {
+	responses := make([]AddressResponse, len(addresses))
+	for i, a := range addresses {
+		responses[i] = ToAddressResponse(a)
+	}
+	return responses
+}
+
+// ToShippingAddressResponse converts the address of an order, nil when it has none
+func ToShippingAddressResponse(a *entities.ShippingAddress) *ShippingAddressResponse {
+	if a == nil {
+		return nil
+	}
+	return &ShippingAddressResponse{
+		RecipientName: a.RecipientName,
+		Phone:         a.Phone,
+		Street:        a.Street,
+		City:          a.City,
+		Province:      a.Province,
+		PostalCode:    a.PostalCode,
+		Country:       a.Country,
+	}
+}
@@ -0,0 +1,91 @@
+package dto
+
+import (
+	"postgresDB/internal/domain/entities"
+	"time"
+)
+
+// CreateAPIClientRequest represents the payload for registering an API client
+type CreateAPIClientRequest struct {
+	Name         string   `json:"name" validate:"required,max=100"`
+	RedirectURIs []string `json:"redirect_uris" validate:"required,min=1,max=10,dive,required,max=2000"`
+	Scopes       []string `json:"scopes" validate:"required,min=1,max=10,dive,required"`
+}
+
+// UpdateAPIClientRequest represents the payload for updating an API client, omitted
+// fields are kept
+type UpdateAPIClientRequest struct {
+	Name         *string  `json:"name" validate:"omitempty,max=100"`
+	RedirectURIs []string `json:"redirect_uris" validate:"omitempty,min=1,max=10,dive,required,max=2000"`
+	Scopes       []string `json:"scopes" validate:"omitempty,min=1,max=10,dive,required"`
+}
+
+// APIClientResponse represents an API client returned in responses
+type APIClientResponse struct {
+	ID           string   `json:"id"`
+	ClientID     string   `json:"client_id"`
+	Name         string   `json:"name"`
+	RedirectURIs []string `json:"redirect_uris"`
+	Scopes       []string `json:"scopes"`
+	// ClientSecret is only returned when the secret is issued
+	ClientSecret    string `json:"client_secret,omitempty"`
+	SecretRotatedAt string `json:"secret_rotated_at"`
+	CreatedAt       string `json:"created_at"`
+	UpdatedAt       string `json:"updated_at"`
+}
+
+// APIClientUsageDay represents the traffic of an API client on a single day
+type APIClientUsageDay struct {
+	Date     string `json:"date"`
+	Requests int64  `json:"requests"`
+	Errors   int64  `json:"errors"`
+}
+
+// APIClientUsageResponse represents the traffic of an API client over [from, to)
+type APIClientUsageResponse struct {
+	ClientID string              `json:"client_id"`
+	From     string              `json:"from"`
+	To       string              `json:"to"`
+	Requests int64               `json:"requests"`
+	Errors   int64               `json:"errors"`
+	Days     []APIClientUsageDay `json:"days"`
+}
+
+// ToAPIClientResponse converts an APIClient entity to APIClientResponse DTO
+func ToAPIClientResponse(c *entities.APIClient) APIClientResponse {
+	return APIClientResponse{
+		ID:              c.ID.String(),
+		ClientID:        c.ClientID,
+		Name:            c.Name,
+		RedirectURIs:    c.RedirectURIs,
+		Scopes:          c.Scopes,
+		SecretRotatedAt: c.SecretRotatedAt.Format(time.RFC3339),
+		CreatedAt:       c.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       c.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToAPIClientResponseList converts a slice of APIClient entities to responses
+func ToAPIClientResponseList(clients []*entities.APIClient) []APIClientResponse {
+	responses := make([]APIClientResponse, len(clients))
+	for i, c := range clients {
+		responses[i] = ToAPIClientResponse(c)
+	}
+	return responses
+}
+
+// ToAPIClientUsageResponse converts the daily usage of a client to APIClientUsageResponse DTO
+func ToAPIClientUsageResponse(clientID string, from, to time.Time, usage []entities.APIClientUsage) APIClientUsageResponse {
+	response := APIClientUsageResponse{
+		ClientID: clientID,
+		From:     from.Format(time.RFC3339),
+		To:       to.Format(time.RFC3339),
+		Days:     make([]APIClientUsageDay, len(usage)),
+	}
+	for i, u := range usage {
+		response.Requests += u.Requests
+		response.Errors += u.Errors
+		response.Days[i] = APIClientUsageDay{Date: u.Day.Format("2006-01-02"), Requests: u.Requests, Errors: u.Errors}
+	}
+	return response
+}
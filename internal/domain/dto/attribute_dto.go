@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"postgresDB/internal/domain/entities"
+	"time"
+)
+
+// CreateAttributeRequest represents the payload for defining a category attribute
+type CreateAttributeRequest struct {
+	Code       string `json:"code" validate:"required,slug,max=100"`
+	Name       string `json:"name" validate:"required,max=100"`
+	Type       string `json:"type" validate:"required,oneof=string number boolean"`
+	Unit       string `json:"unit" validate:"omitempty,max=20"`
+	Filterable bool   `json:"filterable"`
+}
+
+// UpdateAttributeRequest represents the payload for updating an attribute definition.
+// The type can't be changed since existing values were validated against it.
+type UpdateAttributeRequest struct {
+	Code       *string `json:"code" validate:"omitempty,slug,max=100"`
+	Name       *string `json:"name" validate:"omitempty,max=100"`
+	Unit       *string `json:"unit" validate:"omitempty,max=20"`
+	Filterable *bool   `json:"filterable"`
+}
+
+// AttributeResponse represents an attribute definition returned in responses
+type AttributeResponse struct {
+	ID         string `json:"id"`
+	CategoryID string `json:"category_id"`
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Unit       string `json:"unit,omitempty"`
+	Filterable bool   `json:"filterable"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// ProductAttributeResponse represents an attribute value of a product
+type ProductAttributeResponse struct {
+	Code  string `json:"code"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Unit  string `json:"unit,omitempty"`
+	Value string `json:"value"`
+}
+
+// ToAttributeResponse converts an AttributeDefinition entity to AttributeResponse DTO
+func ToAttributeResponse(d *entities.AttributeDefinition) AttributeResponse {
+	return AttributeResponse{
+		ID:         d.ID.String(),
+		CategoryID: d.CategoryID.String(),
+		Code:       d.Code,
+		Name:       d.Name,
+		Type:       string(d.Type),
+		Unit:       d.Unit,
+		Filterable: d.Filterable,
+		CreatedAt:  d.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  d.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToAttributeResponseList converts a slice of AttributeDefinition entities to responses
+func ToAttributeResponseList(defs []*entities.AttributeDefinition) []AttributeResponse {
+	responses := make([]AttributeResponse, len(defs))
+	for i, d := range defs {
+		responses[i] = ToAttributeResponse(d)
+	}
+	return responses
+}
+
+// ToProductAttributeResponseList converts product attribute values to responses
+func ToProductAttributeResponseList(attrs []entities.ProductAttribute) []ProductAttributeResponse {
+	responses := make([]ProductAttributeResponse, len(attrs))
+	for i, a := range attrs {
+		responses[i] = ProductAttributeResponse{
+			Code:  a.Code,
+			Name:  a.Name,
+			Type:  string(a.Type),
+			Unit:  a.Unit,
+			Value: a.Value,
+		}
+	}
+	return responses
+}
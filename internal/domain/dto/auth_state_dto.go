@@ -0,0 +1,94 @@
+package dto
+
+import (
+	"postgresDB/internal/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuthStateBackupResponse represents an auth state backup returned in responses
+type AuthStateBackupResponse struct {
+	ID          string  `json:"id"`
+	Trigger     string  `json:"trigger"`
+	RequestedBy *string `json:"requested_by"`
+	Status      string  `json:"status"`
+	Blacklisted int     `json:"blacklisted"`
+	Families    int     `json:"families"`
+	Sessions    int     `json:"sessions"`
+	Size        int64   `json:"size"`
+	Error       string  `json:"error,omitempty"`
+	CreatedAt   string  `json:"created_at"`
+}
+
+// AuthStateRestoreResponse represents a restore of a backup returned in responses
+type AuthStateRestoreResponse struct {
+	ID         string  `json:"id"`
+	BackupID   string  `json:"backup_id"`
+	RestoredBy *string `json:"restored_by"`
+	Restored   int     `json:"restored"`
+	Skipped    int     `json:"skipped"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+// AuthStateBackupFile is the content of a backup file
+type AuthStateBackupFile struct {
+	Version   int                       `json:"version"`
+	CreatedAt time.Time                 `json:"created_at"`
+	Entries   []entities.AuthStateEntry `json:"entries"`
+}
+
+// ToAuthStateBackupResponse converts an AuthStateBackup entity to AuthStateBackupResponse DTO
+func ToAuthStateBackupResponse(b *entities.AuthStateBackup) AuthStateBackupResponse {
+	return AuthStateBackupResponse{
+		ID:          b.ID.String(),
+		Trigger:     b.Trigger,
+		RequestedBy: uuidString(b.RequestedBy),
+		Status:      string(b.Status),
+		Blacklisted: b.Blacklisted,
+		Families:    b.Families,
+		Sessions:    b.Sessions,
+		Size:        b.Size,
+		Error:       b.Error,
+		CreatedAt:   b.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToAuthStateBackupResponseList converts a slice of AuthStateBackup entities to responses
+func ToAuthStateBackupResponseList(backups []*entities.AuthStateBackup) []AuthStateBackupResponse {
+	responses := make([]AuthStateBackupResponse, len(backups))
+	for i, b := range backups {
+		responses[i] = ToAuthStateBackupResponse(b)
+	}
+	return responses
+}
+
+// ToAuthStateRestoreResponse converts an AuthStateRestore entity to AuthStateRestoreResponse DTO
+func ToAuthStateRestoreResponse(r *entities.AuthStateRestore) AuthStateRestoreResponse {
+	return AuthStateRestoreResponse{
+		ID:         r.ID.String(),
+		BackupID:   r.BackupID.String(),
+		RestoredBy: uuidString(r.RestoredBy),
+		Restored:   r.Restored,
+		Skipped:    r.Skipped,
+		CreatedAt:  r.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToAuthStateRestoreResponseList converts a slice of AuthStateRestore entities to responses
+func ToAuthStateRestoreResponseList(restores []*entities.AuthStateRestore) []AuthStateRestoreResponse {
+	responses := make([]AuthStateRestoreResponse, len(restores))
+	for i, r := range restores {
+		responses[i] = ToAuthStateRestoreResponse(r)
+	}
+	return responses
+}
+
+// uuidString formats an optional id, nil stays nil
+func uuidString(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	s := id.String()
+	return &s
+}
@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"postgresDB/internal/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CatalogSnapshotResponse represents a catalog snapshot without its items
+type CatalogSnapshotResponse struct {
+	ID           string `json:"id"`
+	Trigger      string `json:"trigger"`
+	ProductCount int    `json:"product_count"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// SnapshotStateResponse represents the state of a product in a snapshot
+type SnapshotStateResponse struct {
+	Name  string `json:"name"`
+	Price int64  `json:"price"`
+	Stock int    `json:"stock"`
+}
+
+// SnapshotDiffResponse represents the change of a single product between two snapshots
+type SnapshotDiffResponse struct {
+	ProductID string                 `json:"product_id"`
+	Name      string                 `json:"name"`
+	Change    string                 `json:"change"`
+	Before    *SnapshotStateResponse `json:"before"`
+	After     *SnapshotStateResponse `json:"after"`
+}
+
+// CatalogDiffResponse represents the diff between two snapshots. To is empty when
+// the snapshot was compared against the current catalog
+type CatalogDiffResponse struct {
+	From    string                 `json:"from"`
+	To      string                 `json:"to,omitempty"`
+	Total   int                    `json:"total"`
+	Changes []SnapshotDiffResponse `json:"changes"`
+}
+
+// SnapshotRollbackRequest represents the payload for restoring products from a snapshot
+type SnapshotRollbackRequest struct {
+	ProductIDs []uuid.UUID `json:"product_ids" validate:"required,min=1,max=1000"`
+	// Fields lists what to restore, price and stock when empty
+	Fields []string `json:"fields" validate:"omitempty,dive,oneof=price stock"`
+}
+
+// SnapshotRollbackResponse represents the result of a snapshot rollback
+type SnapshotRollbackResponse struct {
+	Restored int `json:"restored"`
+	// BackupSnapshotID is the snapshot taken right before the rollback, to undo it
+	BackupSnapshotID string `json:"backup_snapshot_id"`
+}
+
+// ToCatalogSnapshotResponse converts a CatalogSnapshot entity to CatalogSnapshotResponse DTO
+func ToCatalogSnapshotResponse(s *entities.CatalogSnapshot) CatalogSnapshotResponse {
+	return CatalogSnapshotResponse{
+		ID:           s.ID.String(),
+		Trigger:      s.Trigger,
+		ProductCount: s.ProductCount,
+		CreatedAt:    s.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToCatalogSnapshotResponseList converts a slice of CatalogSnapshot entities to responses
+func ToCatalogSnapshotResponseList(snapshots []*entities.CatalogSnapshot) []CatalogSnapshotResponse {
+	responses := make([]CatalogSnapshotResponse, len(snapshots))
+	for i, s := range snapshots {
+		responses[i] = ToCatalogSnapshotResponse(s)
+	}
+	return responses
+}
+
+// ToSnapshotDiffResponseList converts snapshot diffs to responses
+func ToSnapshotDiffResponseList(diffs []entities.SnapshotDiff) []SnapshotDiffResponse {
+	responses := make([]SnapshotDiffResponse, len(diffs))
+	for i, d := range diffs {
+		responses[i] = SnapshotDiffResponse{
+			ProductID: d.ProductID.String(),
+			Name:      d.Name,
+			Change:    d.Change,
+			Before:    toSnapshotState(d.Before),
+			After:     toSnapshotState(d.After),
+		}
+	}
+	return responses
+}
+
+func toSnapshotState(item *entities.SnapshotItem) *SnapshotStateResponse {
+	if item == nil {
+		return nil
+	}
+	return &SnapshotStateResponse{Name: item.Name, Price: item.Price, Stock: item.Stock}
+}
@@ -0,0 +1,100 @@
+package dto
+
+import (
+	"postgresDB/internal/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateCategoryRequest represents the payload for creating a new category
+type CreateCategoryRequest struct {
+	Name        string     `json:"name" validate:"required,max=100"`
+	Slug        string     `json:"slug" validate:"omitempty,slug,max=100"`
+	Description string     `json:"description" validate:"omitempty"`
+	ParentID    *uuid.UUID `json:"parent_id" validate:"omitempty"`
+}
+
+// UpdateCategoryRequest represents the payload for updating an existing category
+type UpdateCategoryRequest struct {
+	Name        *string    `json:"name" validate:"omitempty,max=100"`
+	Slug        *string    `json:"slug" validate:"omitempty,slug,max=100"`
+	Description *string    `json:"description" validate:"omitempty"`
+	ParentID    *uuid.UUID `json:"parent_id" validate:"omitempty"`
+	// RemoveParent moves the category to the root of the tree
+	RemoveParent bool `json:"remove_parent"`
+}
+
+// CategoryResponse represents the category data returned in responses
+type CategoryResponse struct {
+	ID          string  `json:"id"`
+	ParentID    *string `json:"parent_id"`
+	Name        string  `json:"name"`
+	Slug        string  `json:"slug"`
+	Description string  `json:"description"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+// CategoryTreeResponse represents a category with its nested children
+type CategoryTreeResponse struct {
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name"`
+	Slug     string                 `json:"slug"`
+	Children []CategoryTreeResponse `json:"children"`
+}
+
+// ToCategoryResponse converts a Category entity to CategoryResponse DTO
+func ToCategoryResponse(c *entities.Category) CategoryResponse {
+	var parentID *string
+	if c.ParentID != nil {
+		id := c.ParentID.String()
+		parentID = &id
+	}
+	return CategoryResponse{
+		ID:          c.ID.String(),
+		ParentID:    parentID,
+		Name:        c.Name,
+		Slug:        c.Slug,
+		Description: c.Description,
+		CreatedAt:   c.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   c.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToCategoryResponseList converts a slice of Category entities to a slice of CategoryResponse DTOs
+func ToCategoryResponseList(categories []*entities.Category) []CategoryResponse {
+	responses := make([]CategoryResponse, len(categories))
+	for i, c := range categories {
+		responses[i] = ToCategoryResponse(c)
+	}
+	return responses
+}
+
+// ToCategoryTree builds a category tree from a flat list of categories
+func ToCategoryTree(categories []*entities.Category) []CategoryTreeResponse {
+	children := make(map[uuid.UUID][]*entities.Category)
+	roots := make([]*entities.Category, 0)
+	for _, c := range categories {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+			continue
+		}
+		children[*c.ParentID] = append(children[*c.ParentID], c)
+	}
+
+	var build func(nodes []*entities.Category) []CategoryTreeResponse
+	build = func(nodes []*entities.Category) []CategoryTreeResponse {
+		tree := make([]CategoryTreeResponse, len(nodes))
+		for i, n := range nodes {
+			tree[i] = CategoryTreeResponse{
+				ID:       n.ID.String(),
+				Name:     n.Name,
+				Slug:     n.Slug,
+				Children: build(children[n.ID]),
+			}
+		}
+		return tree
+	}
+	return build(roots)
+}
@@ -6,6 +6,14 @@ type PaginationMeta struct {
 	Limit      int   `json:"limit"`
 	Total      int64 `json:"total"`
 	TotalPages int   `json:"total_pages"`
+	// TotalEstimated is set when Total is an estimate, see exact_count on product lists
+	TotalEstimated bool `json:"total_estimated,omitempty"`
+}
+
+// PageRequest represents the offset pagination query parameters, embedded in list requests
+type PageRequest struct {
+	Page  int `json:"page" query:"page" default:"1" validate:"omitempty,min=1"`
+	Limit int `json:"limit" query:"limit" default:"10" validate:"omitempty,min=1,max=100"`
 }
 
 // APIResponse represents a standard API response
@@ -21,12 +29,16 @@ type ErrorInfo struct {
 	Code    string            `json:"code"`
 	Message string            `json:"message"`
 	Details []ValidationError `json:"details,omitempty"`
+	// RequestID identifies the request in the server logs, quote it when reporting an error
+	RequestID string `json:"request_id,omitempty"`
 }
 
 // ValidationError represents a validation error detail
 type ValidationError struct {
 	Field   string `json:"field"`
 	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
 }
 
 // NewSuccessResponse creates a success response
@@ -69,3 +81,10 @@ func CalculateTotalPages(total int64, limit int) int {
 	}
 	return pages
 }
+
+// CursorMeta represents keyset pagination metadata
+type CursorMeta struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"postgresDB/internal/domain/entities"
+	"time"
+)
+
+// UpdateConsentsRequest represents the payload for changing consents, purposes left out
+// are kept as they are
+type UpdateConsentsRequest struct {
+	MarketingEmail *bool `json:"marketing_email"`
+	MarketingSMS   *bool `json:"marketing_sms"`
+	Analytics      *bool `json:"analytics"`
+}
+
+// Choices returns the purposes set in the request with their consent
+func (r *UpdateConsentsRequest) Choices() map[entities.ConsentPurpose]bool {
+	choices := make(map[entities.ConsentPurpose]bool)
+	for purpose, granted := range map[entities.ConsentPurpose]*bool{
+		entities.ConsentMarketingEmail: r.MarketingEmail,
+		entities.ConsentMarketingSMS:   r.MarketingSMS,
+		entities.ConsentAnalytics:      r.Analytics,
+	} {
+		if granted != nil {
+			choices[purpose] = *granted
+		}
+	}
+	return choices
+}
+
+// ConsentResponse represents the current consent of a user for a purpose
+type ConsentResponse struct {
+	Purpose string `json:"purpose"`
+	Granted bool   `json:"granted"`
+	// UpdatedAt is empty while the user never chose and Granted is the default
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// ConsentRecordResponse represents a consent choice from the consent records
+type ConsentRecordResponse struct {
+	ID        string `json:"id"`
+	Purpose   string `json:"purpose"`
+	Granted   bool   `json:"granted"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ToConsentResponse converts a Consent entity to ConsentResponse DTO
+func ToConsentResponse(c *entities.Consent) ConsentResponse {
+	response := ConsentResponse{
+		Purpose: string(c.Purpose),
+		Granted: c.Granted,
+	}
+	if c.UpdatedAt != nil {
+		response.UpdatedAt = c.UpdatedAt.Format(time.RFC3339)
+	}
+	return response
+}
+
+// ToConsentResponseList converts Consent entities to ConsentResponse DTOs
+func ToConsentResponseList(consents []*entities.Consent) []ConsentResponse {
+	responses := make([]ConsentResponse, len(consents))
+	for i, c := range consents {
+		responses[i] = ToConsentResponse(c)
+	}
+	return responses
+}
+
+// ToConsentRecordResponse converts a ConsentRecord entity to ConsentRecordResponse DTO
+func ToConsentRecordResponse(c *entities.ConsentRecord) ConsentRecordResponse {
+	return ConsentRecordResponse{
+		ID:        c.ID.String(),
+		Purpose:   string(c.Purpose),
+		Granted:   c.Granted,
+		IP:        c.IP,
+		UserAgent: c.UserAgent,
+		CreatedAt: c.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToConsentRecordResponseList converts ConsentRecord entities to ConsentRecordResponse DTOs
+func ToConsentRecordResponseList(records []*entities.ConsentRecord) []ConsentRecordResponse {
+	responses := make([]ConsentRecordResponse, len(records))
+	for i, c := range records {
+		responses[i] = ToConsentRecordResponse(c)
+	}
+	return responses
+}
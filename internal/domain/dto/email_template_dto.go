@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"postgresDB/internal/domain/entities"
+	"time"
+)
+
+// CreateEmailTemplateRequest represents the payload for a new email template version.
+// Subject and text_body use text/template syntax, html_body uses html/template
+type CreateEmailTemplateRequest struct {
+	Key      string `json:"key" validate:"required,max=100,slug"`
+	Locale   string `json:"locale" validate:"required,max=35,bcp47_language_tag"`
+	Subject  string `json:"subject" validate:"required,max=255"`
+	HTMLBody string `json:"html_body" validate:"required"`
+	TextBody string `json:"text_body" validate:"omitempty"`
+}
+
+// PreviewEmailTemplateRequest represents the sample data a template is rendered with
+type PreviewEmailTemplateRequest struct {
+	Data map[string]any `json:"data"`
+}
+
+// SendTestEmailRequest represents the payload for sending a template to a test address
+type SendTestEmailRequest struct {
+	To   string         `json:"to" validate:"required,customEmail"`
+	Data map[string]any `json:"data"`
+}
+
+// EmailTemplateResponse represents an email template version returned in responses
+type EmailTemplateResponse struct {
+	ID          string  `json:"id"`
+	Key         string  `json:"key"`
+	Locale      string  `json:"locale"`
+	Version     int     `json:"version"`
+	Subject     string  `json:"subject"`
+	HTMLBody    string  `json:"html_body"`
+	TextBody    string  `json:"text_body"`
+	Status      string  `json:"status"`
+	CreatedBy   *string `json:"created_by,omitempty"`
+	PublishedAt *string `json:"published_at,omitempty"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+// RenderedEmailResponse represents a template rendered with data
+type RenderedEmailResponse struct {
+	TemplateID string `json:"template_id"`
+	Locale     string `json:"locale"`
+	Version    int    `json:"version"`
+	Subject    string `json:"subject"`
+	HTML       string `json:"html"`
+	Text       string `json:"text"`
+}
+
+// ToEmailTemplateResponse converts an EmailTemplate entity to EmailTemplateResponse DTO
+func ToEmailTemplateResponse(template *entities.EmailTemplate) EmailTemplateResponse {
+	response := EmailTemplateResponse{
+		ID:        template.ID.String(),
+		Key:       template.Key,
+		Locale:    template.Locale,
+		Version:   template.Version,
+		Subject:   template.Subject,
+		HTMLBody:  template.HTMLBody,
+		TextBody:  template.TextBody,
+		Status:    string(template.Status),
+		CreatedAt: template.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: template.UpdatedAt.Format(time.RFC3339),
+	}
+	if template.CreatedBy != nil {
+		by := template.CreatedBy.String()
+		response.CreatedBy = &by
+	}
+	if template.PublishedAt != nil {
+		at := template.PublishedAt.Format(time.RFC3339)
+		response.PublishedAt = &at
+	}
+	return response
+}
+
+// ToEmailTemplateResponseList converts email templates to responses
+func ToEmailTemplateResponseList(templates []*entities.EmailTemplate) []EmailTemplateResponse {
+	responses := make([]EmailTemplateResponse, len(templates))
+	for i, template := range templates {
+		responses[i] = ToEmailTemplateResponse(template)
+	}
+	return responses
+}
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"postgresDB/internal/domain/entities"
+	"time"
+)
+
+// FieldUsageResponse represents how many sampled requests used a field of a route, and
+// which clients did
+type FieldUsageResponse struct {
+	Route     string                     `json:"route"`
+	Direction string                     `json:"direction"`
+	Field     string                     `json:"field"`
+	Count     int64                      `json:"count"`
+	LastSeen  string                     `json:"last_seen"`
+	Clients   []FieldClientUsageResponse `json:"clients"`
+}
+
+// FieldClientUsageResponse represents the usage of a field by one client, client_id is
+// empty for requests without X-Client-ID
+type FieldClientUsageResponse struct {
+	ClientID  string `json:"client_id"`
+	Count     int64  `json:"count"`
+	FirstSeen string `json:"first_seen"`
+	LastSeen  string `json:"last_seen"`
+}
+
+// ToFieldUsageResponseList groups the per client usage, sorted by route, direction and
+// field, into one FieldUsageResponse per field
+func ToFieldUsageResponseList(usage []entities.FieldUsage) []FieldUsageResponse {
+	responses := make([]FieldUsageResponse, 0)
+	var lastSeen time.Time
+	for _, u := range usage {
+		n := len(responses)
+		if n == 0 || responses[n-1].Route != u.Route || responses[n-1].Direction != string(u.Direction) || responses[n-1].Field != u.Field {
+			responses = append(responses, FieldUsageResponse{
+				Route:     u.Route,
+				Direction: string(u.Direction),
+				Field:     u.Field,
+				Clients:   make([]FieldClientUsageResponse, 0, 1),
+			})
+			n++
+			lastSeen = time.Time{}
+		}
+		field := &responses[n-1]
+		field.Count += u.Count
+		if u.LastSeen.After(lastSeen) {
+			lastSeen = u.LastSeen
+			field.LastSeen = lastSeen.Format(time.RFC3339)
+		}
+		field.Clients = append(field.Clients, FieldClientUsageResponse{
+			ClientID:  u.ClientID,
+			Count:     u.Count,
+			FirstSeen: u.FirstSeen.Format(time.RFC3339),
+			LastSeen:  u.LastSeen.Format(time.RFC3339),
+		})
+	}
+	return responses
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"postgresDB/internal/domain/entities"
+	"time"
+)
+
+// StockLevelResponse represents the current stock of a watched product
+type StockLevelResponse struct {
+	ProductID        string `json:"product_id"`
+	SKU              string `json:"sku,omitempty"`
+	Name             string `json:"name"`
+	Stock            int    `json:"stock"`
+	ReorderThreshold *int   `json:"reorder_threshold,omitempty"`
+}
+
+// StockEventResponse represents a stock change pushed to inventory stream clients
+type StockEventResponse struct {
+	ProductID  string `json:"product_id"`
+	Stock      int    `json:"stock"`
+	Previous   *int   `json:"previous,omitempty"`
+	OccurredAt string `json:"occurred_at"`
+}
+
+// ToStockLevelResponse converts a Product entity to StockLevelResponse DTO
+func ToStockLevelResponse(product *entities.Product) StockLevelResponse {
+	return StockLevelResponse{
+		ProductID:        product.ID.String(),
+		SKU:              product.SKU,
+		Name:             product.Name,
+		Stock:            product.Stock,
+		ReorderThreshold: product.ReorderThreshold,
+	}
+}
+
+// ToStockEventResponse converts a StockEvent entity to StockEventResponse DTO
+func ToStockEventResponse(event *entities.StockEvent) StockEventResponse {
+	return StockEventResponse{
+		ProductID:  event.ProductID.String(),
+		Stock:      event.Stock,
+		Previous:   event.Previous,
+		OccurredAt: event.OccurredAt.Format(time.RFC3339Nano),
+	}
+}
@@ -0,0 +1,99 @@
+package dto
+
+import (
+	"encoding/json"
+	"postgresDB/internal/domain/entities"
+	"time"
+)
+
+// JobResponse represents a background job returned in responses
+type JobResponse struct {
+	ID          string          `json:"id"`
+	Queue       string          `json:"queue"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+	RunAt       string          `json:"run_at"`
+	CreatedAt   string          `json:"created_at"`
+	UpdatedAt   string          `json:"updated_at"`
+	StartedAt   *string         `json:"started_at,omitempty"`
+	FinishedAt  *string         `json:"finished_at,omitempty"`
+}
+
+// QueueStatsResponse represents the backlog of a queue and its throughput over a window
+type QueueStatsResponse struct {
+	Queue               string  `json:"queue"`
+	Ready               int64   `json:"ready"`
+	Scheduled           int64   `json:"scheduled"`
+	Running             int64   `json:"running"`
+	Failed              int64   `json:"failed"`
+	Window              string  `json:"window"`
+	Succeeded           int64   `json:"succeeded"`
+	Errored             int64   `json:"errored"`
+	ThroughputPerMinute float64 `json:"throughput_per_minute"`
+	AvgWaitMs           float64 `json:"avg_wait_ms"`
+	AvgRunMs            float64 `json:"avg_run_ms"`
+}
+
+// ToJobResponse converts a Job entity to JobResponse DTO
+func ToJobResponse(job *entities.Job) JobResponse {
+	payload := job.Payload
+	if len(payload) == 0 {
+		payload = json.RawMessage("null")
+	}
+	response := JobResponse{
+		ID:          job.ID.String(),
+		Queue:       job.Queue,
+		Type:        job.Type,
+		Payload:     payload,
+		Status:      string(job.Status),
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		LastError:   job.LastError,
+		RunAt:       job.RunAt.Format(time.RFC3339),
+		CreatedAt:   job.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   job.UpdatedAt.Format(time.RFC3339),
+	}
+	if job.StartedAt != nil {
+		at := job.StartedAt.Format(time.RFC3339)
+		response.StartedAt = &at
+	}
+	if job.FinishedAt != nil {
+		at := job.FinishedAt.Format(time.RFC3339)
+		response.FinishedAt = &at
+	}
+	return response
+}
+
+// ToJobResponseList converts jobs to responses
+func ToJobResponseList(jobs []*entities.Job) []JobResponse {
+	responses := make([]JobResponse, len(jobs))
+	for i, job := range jobs {
+		responses[i] = ToJobResponse(job)
+	}
+	return responses
+}
+
+// ToQueueStatsResponse converts QueueStats to QueueStatsResponse DTO, averaging over
+// every finished attempt in the window
+func ToQueueStatsResponse(stats *entities.QueueStats) QueueStatsResponse {
+	response := QueueStatsResponse{
+		Queue:               stats.Queue,
+		Ready:               stats.Ready,
+		Scheduled:           stats.Scheduled,
+		Running:             stats.Running,
+		Failed:              stats.Failed,
+		Window:              stats.Window.String(),
+		Succeeded:           stats.Succeeded,
+		Errored:             stats.Errored,
+		ThroughputPerMinute: float64(stats.Succeeded+stats.Errored) / stats.Window.Minutes(),
+	}
+	if attempts := stats.Succeeded + stats.Errored; attempts > 0 {
+		response.AvgWaitMs = float64(stats.TotalWait.Milliseconds()) / float64(attempts)
+		response.AvgRunMs = float64(stats.TotalRun.Milliseconds()) / float64(attempts)
+	}
+	return response
+}
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"postgresDB/internal/domain/entities"
+	"time"
+)
+
+// UpdateNotificationPreferencesRequest represents the payload for changing notification
+// preferences, kinds and channels left out are kept as they are
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceRequest `json:"preferences" validate:"required,min=1,max=50,dive"`
+}
+
+// NotificationPreferenceRequest turns a kind of notification on or off on a channel
+type NotificationPreferenceRequest struct {
+	Kind    string `json:"kind" validate:"required"`
+	Channel string `json:"channel" validate:"required"`
+	Enabled *bool  `json:"enabled" validate:"required"`
+}
+
+// NotificationPreferenceResponse represents whether a user receives a kind of
+// notification on a channel
+type NotificationPreferenceResponse struct {
+	Kind    string `json:"kind"`
+	Channel string `json:"channel"`
+	Enabled bool   `json:"enabled"`
+	// Required notifications are sent whatever the preference
+	Required bool `json:"required"`
+	// UpdatedAt is empty while the user never chose and the notification is on
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// ToNotificationPreferenceResponse converts a NotificationPreference entity to NotificationPreferenceResponse DTO
+func ToNotificationPreferenceResponse(p *entities.NotificationPreference) NotificationPreferenceResponse {
+	response := NotificationPreferenceResponse{
+		Kind:     string(p.Kind),
+		Channel:  p.Channel,
+		Enabled:  p.Enabled || p.Kind.Required(),
+		Required: p.Kind.Required(),
+	}
+	if p.UpdatedAt != nil {
+		response.UpdatedAt = p.UpdatedAt.Format(time.RFC3339)
+	}
+	return response
+}
+
+// ToNotificationPreferenceResponseList converts NotificationPreference entities to NotificationPreferenceResponse DTOs
+func ToNotificationPreferenceResponseList(preferences []*entities.NotificationPreference) []NotificationPreferenceResponse {
+	responses := make([]NotificationPreferenceResponse, len(preferences))
+	for i, p := range preferences {
+		responses[i] = ToNotificationPreferenceResponse(p)
+	}
+	return responses
+}
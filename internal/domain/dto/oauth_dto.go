@@ -0,0 +1,89 @@
+package dto
+
+// AuthorizeRequest represents an OAuth authorization request of the signed-in user.
+// Scope is space separated, only the S256 code challenge method is supported
+type AuthorizeRequest struct {
+	ResponseType        string `json:"response_type" validate:"required,eq=code"`
+	ClientID            string `json:"client_id" validate:"required,max=100"`
+	RedirectURI         string `json:"redirect_uri" validate:"required,max=2000"`
+	Scope               string `json:"scope" validate:"required,max=500"`
+	State               string `json:"state" validate:"omitempty,max=500"`
+	CodeChallenge       string `json:"code_challenge" validate:"required,min=43,max=128"`
+	CodeChallengeMethod string `json:"code_challenge_method" validate:"required,eq=S256"`
+	Nonce               string `json:"nonce" validate:"omitempty,max=500"`
+}
+
+// AuthorizeConsentResponse describes an authorization request for the consent page
+type AuthorizeConsentResponse struct {
+	ClientID    string   `json:"client_id"`
+	ClientName  string   `json:"client_name"`
+	RedirectURI string   `json:"redirect_uri"`
+	Scopes      []string `json:"scopes"`
+}
+
+// AuthorizeResponse holds the redirect URI with the authorization code and state
+type AuthorizeResponse struct {
+	RedirectTo string `json:"redirect_to"`
+	ExpiresIn  int64  `json:"expires_in"`
+}
+
+// OAuthTokenRequest represents the form posted to the token endpoint. The client
+// credentials come from HTTP basic auth or the form
+type OAuthTokenRequest struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	RefreshToken string
+	ClientID     string
+	ClientSecret string
+}
+
+// OAuthTokenResponse represents the tokens issued to an OAuth client
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	IDToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope"`
+}
+
+// UserInfoResponse represents the OpenID Connect claims of the user, by granted scope
+type UserInfoResponse struct {
+	Subject           string `json:"sub"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Email             string `json:"email,omitempty"`
+}
+
+// OpenIDConfiguration represents the OpenID Connect discovery document
+type OpenIDConfiguration struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+// JWK represents a public signing key in JSON Web Key format
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+// JWKSet represents the JSON Web Key Set of the keys ID tokens are signed with
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
@@ -2,14 +2,51 @@ package dto
 
 import (
 	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// CreateOrderRequest represents the payload for placing an order, the customer is the
+// signed-in user
 type CreateOrderRequest struct {
-	Items []OrderItemRequest `json:"items" validate:"required,dive,required"`
+	// Items listing the same product more than once are merged into one line
+	Items []OrderItemRequest `json:"items" validate:"required,min=1,dive,required"`
+	// AddressID picks the address book entry to ship to, the default address when omitted
+	AddressID *uuid.UUID `json:"address_id" validate:"omitempty"`
+	// ShippingMethod is the code of a configured shipping method, the first one when omitted
+	ShippingMethod string `json:"shipping_method" validate:"omitempty,max=50"`
 }
+
+// CreateOrderRequestV1 is the v1 payload, which may still carry the customer. It is
+// checked against the signed-in user and otherwise ignored; v2 drops it
+type CreateOrderRequestV1 struct {
+	CreateOrderRequest
+	// Deprecated: CustomerID is the signed-in user, orders can't be placed for others
+	CustomerID *uuid.UUID `json:"customer_id,omitempty"`
+}
+
+// ForCustomer returns the current payload for the signed-in user, rejecting a customer_id
+// of someone else
+func (r CreateOrderRequestV1) ForCustomer(userID uuid.UUID) (CreateOrderRequest, error) {
+	if r.CustomerID != nil && *r.CustomerID != userID {
+		return CreateOrderRequest{}, apperror.ErrCustomerMismatch
+	}
+	return r.CreateOrderRequest, nil
+}
+
+// GuestOrderRequest represents the payload for placing an order without an account
+type GuestOrderRequest struct {
+	// Email receives the tracking link, registering it later allows claiming the order
+	Email string `json:"email" validate:"required,email,max=255"`
+	// Items listing the same product more than once are merged into one line
+	Items           []OrderItemRequest      `json:"items" validate:"required,min=1,dive,required"`
+	ShippingAddress *ShippingAddressRequest `json:"shipping_address" validate:"required"`
+	// ShippingMethod is the code of a configured shipping method, the first one when omitted
+	ShippingMethod string `json:"shipping_method" validate:"omitempty,max=50"`
+}
+
 type OrderItemRequest struct {
 	ProductID uuid.UUID `json:"product_id" validate:"required,uuid4"`
 	Quantity  int       `json:"quantity" validate:"required,min=1"`
@@ -17,33 +54,70 @@ type OrderItemRequest struct {
 
 // UpdateOrderRequest represents the payload for updating an existing order
 type UpdateOrderRequest struct {
-	Status string `json:"status" validate:"omitempty,oneof=pending completed cancelled"`
+	Status string `json:"status" validate:"omitempty,oneof=pending paid processing shipped delivered completed cancelled refunded"`
+	// Carrier and TrackingNumber record a shipment, usually sent along with status shipped.
+	// Either is completed from the order's latest shipment
+	Carrier        *string `json:"carrier" validate:"omitempty,max=50"`
+	TrackingNumber *string `json:"tracking_number" validate:"omitempty,max=100"`
 }
 
 type OrderResponse struct {
-	ID          uuid.UUID           `json:"id"`
-	CustomerID  uuid.UUID           `json:"customer_id"`
-	Status      string              `json:"status"`
-	TotalAmount float64             `json:"total_amount"`
-	Items       []OrderItemResponse `json:"items"`
-	CreatedAt   time.Time           `json:"created_at"`
-	UpdatedAt   time.Time           `json:"updated_at"`
+	ID              uuid.UUID                `json:"id"`
+	CustomerID      uuid.UUID                `json:"customer_id"`
+	Status          string                   `json:"status"`
+	TotalAmount     int64                    `json:"total_amount"`
+	Items           []OrderItemResponse      `json:"items"`
+	Carrier         string                   `json:"carrier,omitempty"`
+	TrackingNumber  string                   `json:"tracking_number,omitempty"`
+	Shipments       []ShipmentResponse       `json:"shipments,omitempty"`
+	ShippingAddress *ShippingAddressResponse `json:"shipping_address,omitempty"`
+	ShippingMethod  string                   `json:"shipping_method,omitempty"`
+	// Subtotal, TaxAmount and ShippingCost add up to TotalAmount
+	Subtotal     int64             `json:"subtotal"`
+	TaxAmount    int64             `json:"tax_amount"`
+	TaxLines     []TaxLineResponse `json:"tax_lines"`
+	ShippingCost int64             `json:"shipping_cost"`
+	// Payment is the latest payment of the order at the payment provider
+	Payment   *PaymentResponse `json:"payment,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
 }
 
 type OrderItemResponse struct {
 	ID        uuid.UUID `json:"id"`
 	ProductID uuid.UUID `json:"product_id"`
 	Quantity  int       `json:"quantity"`
-	UnitPrice float64   `json:"unit_price"`
-	SubTotal  float64   `json:"sub_total"`
+	UnitPrice int64     `json:"unit_price"`
+	SubTotal  int64     `json:"sub_total"`
+	TaxAmount int64     `json:"tax_amount"`
 	CreatedAt string    `json:"created_at"`
 }
 
-// OrderListRequest represents the query parameters for listing orders
+// TaxLineResponse represents the tax charged at one rate, Rate is in basis points
+type TaxLineResponse struct {
+	Name          string `json:"name"`
+	Rate          int    `json:"rate"`
+	TaxableAmount int64  `json:"taxable_amount"`
+	Amount        int64  `json:"amount"`
+}
+
+// OrderListRequest represents the query parameters for listing orders, created in
+// [from, to). The customer filters are only applied for admins
 type OrderListRequest struct {
-	Status string `json:"status" validate:"omitempty,oneof=pending completed cancelled"`
-	Limit  int    `json:"limit" validate:"omitempty,min=1,max=100"`
-	Page   int    `json:"page" validate:"omitempty,min=1"`
+	// Status matches orders in any of the statuses, e.g. status=paid,processing
+	Status        []string   `json:"status" query:"status" validate:"omitempty,max=9,dive,oneof=pending pending_capture paid processing shipped delivered completed cancelled refunded"`
+	CustomerID    *uuid.UUID `json:"customer_id" query:"customer_id"`
+	CustomerEmail string     `json:"customer_email" query:"customer_email" validate:"omitempty,max=255"`
+	From          time.Time  `json:"from" query:"from"`
+	To            time.Time  `json:"to" query:"to,end"`
+	MinTotal      *int64     `json:"min_total" query:"min_total" validate:"omitempty,min=0"`
+	MaxTotal      *int64     `json:"max_total" query:"max_total" validate:"omitempty,min=0"`
+	// IncludeItems returns the items of every listed order, left empty otherwise
+	IncludeItems bool `json:"include_items" query:"include_items"`
+	Limit        int  `json:"limit" query:"limit" default:"10" validate:"omitempty,min=1,max=100"`
+	Page         int  `json:"page" query:"page" default:"1" validate:"omitempty,min=1"`
+	// Cursor switches to keyset pagination when set; empty requests the first page
+	Cursor *string `json:"cursor" query:"cursor"`
 }
 
 // ToOrderResponse converts an Order entity to OrderResponse DTO
@@ -56,17 +130,36 @@ func ToOrderResponse(o *entities.Order) OrderResponse {
 			Quantity:  item.Quantity,
 			UnitPrice: item.UnitPrice,
 			SubTotal:  item.SubTotal,
+			TaxAmount: item.TaxAmount,
+		}
+	}
+	taxLines := make([]TaxLineResponse, len(o.TaxLines))
+	for i, line := range o.TaxLines {
+		taxLines[i] = TaxLineResponse{
+			Name:          line.Name,
+			Rate:          line.Rate,
+			TaxableAmount: line.TaxableAmount,
+			Amount:        line.Amount,
 		}
 	}
 
 	return OrderResponse{
-		ID:          o.ID,
-		CustomerID:  o.CustomerID,
-		Status:      o.Status.String(),
-		TotalAmount: o.TotalAmount,
-		Items:       items,
-		CreatedAt:   o.CreatedAt,
-		UpdatedAt:   o.UpdatedAt,
+		ID:              o.ID,
+		CustomerID:      o.CustomerID,
+		Status:          o.Status.String(),
+		TotalAmount:     o.TotalAmount,
+		Items:           items,
+		Carrier:         o.Carrier,
+		TrackingNumber:  o.TrackingNumber,
+		Shipments:       ToShipmentResponseList(o.Shipments),
+		ShippingAddress: ToShippingAddressResponse(o.ShippingAddress),
+		ShippingMethod:  o.ShippingMethod,
+		Subtotal:        o.Subtotal,
+		TaxAmount:       o.TaxAmount,
+		TaxLines:        taxLines,
+		ShippingCost:    o.ShippingCost,
+		CreatedAt:       o.CreatedAt,
+		UpdatedAt:       o.UpdatedAt,
 	}
 }
 
@@ -78,3 +171,259 @@ func ToOrderResponseList(orders []*entities.Order) []OrderResponse {
 	}
 	return responses
 }
+
+// PackingSlip represents the printable packing document of an order. It lists what to
+// pick and pack, and deliberately leaves out prices
+type PackingSlip struct {
+	OrderID       string `json:"order_id"`
+	OrderDate     string `json:"order_date"`
+	Status        string `json:"status"`
+	CustomerName  string `json:"customer_name"`
+	CustomerEmail string `json:"customer_email"`
+	// ShipTo is the address the parcel goes to, nil for orders placed without one
+	ShipTo         *ShippingAddressResponse `json:"ship_to,omitempty"`
+	ShippingMethod string                   `json:"shipping_method,omitempty"`
+	Items          []PackingSlipItem        `json:"items"`
+	TotalQuantity  int                      `json:"total_quantity"`
+}
+
+// PackingSlipItem represents a line of a packing slip
+type PackingSlipItem struct {
+	ProductID string `json:"product_id"`
+	Name      string `json:"name"`
+	Category  string `json:"category"`
+	Quantity  int    `json:"quantity"`
+}
+
+// PaymentCaptureResponse represents the state of an order's payment capture
+type PaymentCaptureResponse struct {
+	OrderID       string `json:"order_id"`
+	Status        string `json:"status"`
+	Attempts      int    `json:"attempts"`
+	NextAttemptAt string `json:"next_attempt_at,omitempty"`
+	LastError     string `json:"last_error,omitempty"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+// ToPaymentCaptureResponse converts a PaymentCapture entity to PaymentCaptureResponse DTO
+func ToPaymentCaptureResponse(c *entities.PaymentCapture) PaymentCaptureResponse {
+	response := PaymentCaptureResponse{
+		OrderID:   c.OrderID.String(),
+		Status:    string(c.Status),
+		Attempts:  c.Attempts,
+		LastError: c.LastError,
+		CreatedAt: c.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: c.UpdatedAt.Format(time.RFC3339),
+	}
+	if c.Status == entities.CaptureStatusPending {
+		response.NextAttemptAt = c.NextAttemptAt.Format(time.RFC3339)
+	}
+	return response
+}
+
+// PaymentResponse represents a payment of an order at the payment provider. The client
+// secret lets the customer's client complete a pending payment
+type PaymentResponse struct {
+	ID            string `json:"id"`
+	Provider      string `json:"provider"`
+	Status        string `json:"status"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+	ClientSecret  string `json:"client_secret,omitempty"`
+	FailureReason string `json:"failure_reason,omitempty"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+// ToPaymentResponse converts a Payment entity to PaymentResponse DTO
+func ToPaymentResponse(p *entities.Payment) PaymentResponse {
+	response := PaymentResponse{
+		ID:            p.ID.String(),
+		Provider:      p.Provider,
+		Status:        string(p.Status),
+		Amount:        p.Amount,
+		Currency:      p.Currency,
+		FailureReason: p.FailureReason,
+		CreatedAt:     p.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     p.UpdatedAt.Format(time.RFC3339),
+	}
+	if p.Status == entities.PaymentStatusPending {
+		response.ClientSecret = p.ClientSecret
+	}
+	return response
+}
+
+// TrackingLinkResponse represents a shareable public tracking link
+type TrackingLinkResponse struct {
+	URL   string `json:"url"`
+	Token string `json:"token"`
+}
+
+// CreateShipmentRequest represents the payload for recording a shipment of an order
+type CreateShipmentRequest struct {
+	Carrier        string `json:"carrier" validate:"required,max=50"`
+	TrackingNumber string `json:"tracking_number" validate:"required,max=100"`
+	// ShippedAt is when the parcel was handed to the carrier, now when omitted
+	ShippedAt *time.Time `json:"shipped_at"`
+}
+
+// ShipmentResponse represents a shipment of an order, the carrier and tracking number of
+// the order are the latest shipment's
+type ShipmentResponse struct {
+	ID             string `json:"id"`
+	Carrier        string `json:"carrier"`
+	TrackingNumber string `json:"tracking_number"`
+	ShippedAt      string `json:"shipped_at"`
+}
+
+// ToShipmentResponse converts a Shipment entity to ShipmentResponse DTO
+func ToShipmentResponse(s *entities.Shipment) ShipmentResponse {
+	return ShipmentResponse{
+		ID:             s.ID.String(),
+		Carrier:        s.Carrier,
+		TrackingNumber: s.TrackingNumber,
+		ShippedAt:      s.ShippedAt.Format(time.RFC3339),
+	}
+}
+
+// ToShipmentResponseList converts the shipments of an order, nil when it has none
+func ToShipmentResponseList(shipments []entities.Shipment) []ShipmentResponse {
+	if len(shipments) == 0 {
+		return nil
+	}
+	responses := make([]ShipmentResponse, len(shipments))
+	for i := range shipments {
+		responses[i] = ToShipmentResponse(&shipments[i])
+	}
+	return responses
+}
+
+// CustomerOrderStatsResponse represents the order statistics of a customer, over their
+// paid orders
+type CustomerOrderStatsResponse struct {
+	CustomerID  string `json:"customer_id"`
+	OrderCount  int64  `json:"order_count"`
+	TotalSpent  int64  `json:"total_spent"`
+	LastOrderAt string `json:"last_order_at,omitempty"`
+	// FavoriteCategories are the categories the customer bought the most units of
+	FavoriteCategories []CategoryPurchaseResponse `json:"favorite_categories"`
+}
+
+// CategoryPurchaseResponse represents what a customer bought of a category
+type CategoryPurchaseResponse struct {
+	CategoryID string `json:"category_id,omitempty"`
+	Name       string `json:"name"`
+	Quantity   int64  `json:"quantity"`
+	Spent      int64  `json:"spent"`
+}
+
+// ToCustomerOrderStatsResponse converts the CustomerOrderStats of a customer to CustomerOrderStatsResponse DTO
+func ToCustomerOrderStatsResponse(customerID uuid.UUID, s *entities.CustomerOrderStats) CustomerOrderStatsResponse {
+	response := CustomerOrderStatsResponse{
+		CustomerID:         customerID.String(),
+		OrderCount:         s.Orders,
+		TotalSpent:         s.TotalSpent,
+		FavoriteCategories: make([]CategoryPurchaseResponse, len(s.FavoriteCategories)),
+	}
+	if s.LastOrderAt != nil {
+		response.LastOrderAt = s.LastOrderAt.Format(time.RFC3339)
+	}
+	for i, c := range s.FavoriteCategories {
+		response.FavoriteCategories[i] = CategoryPurchaseResponse{
+			Name:     c.Category,
+			Quantity: c.Quantity,
+			Spent:    c.Spent,
+		}
+		if c.CategoryID != nil {
+			response.FavoriteCategories[i].CategoryID = c.CategoryID.String()
+		}
+	}
+	return response
+}
+
+// ClaimGuestOrdersRequest represents the payload for claiming guest orders, with the
+// token of the tracking link emailed to the guest
+type ClaimGuestOrdersRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ClaimGuestOrdersResponse reports the guest orders moved to the account
+type ClaimGuestOrdersResponse struct {
+	Claimed int64 `json:"claimed"`
+}
+
+// OrderStatusEventResponse represents a status change of an order
+type OrderStatusEventResponse struct {
+	Status string `json:"status"`
+	Note   string `json:"note,omitempty"`
+	At     string `json:"at"`
+}
+
+// OrderAsOfRequest represents the query parameters of an order's state at a point in time
+type OrderAsOfRequest struct {
+	Time time.Time `json:"time" query:"time" validate:"required"`
+}
+
+// OrderAsOfResponse represents an order as it was at a point in time, rebuilt from its
+// status history, payments and refunds. Shipment details have no history and are left out
+type OrderAsOfResponse struct {
+	OrderID     string              `json:"order_id"`
+	AsOf        string              `json:"as_of"`
+	CustomerID  string              `json:"customer_id"`
+	Status      string              `json:"status"`
+	TotalAmount int64               `json:"total_amount"`
+	Items       []OrderItemResponse `json:"items"`
+	// History lists the status changes up to the point in time
+	History []OrderStatusEventResponse `json:"history"`
+	// Payment is the latest payment started by then, with the status it had
+	Payment *PaymentResponse `json:"payment,omitempty"`
+	// Refunds are the refunds requested by then, with the status they had
+	Refunds        []RefundResponse `json:"refunds"`
+	RefundedAmount int64            `json:"refunded_amount"`
+	PlacedAt       string           `json:"placed_at"`
+	// CurrentStatus is the status now, to compare with Status
+	CurrentStatus string `json:"current_status"`
+}
+
+// OrderTrackingResponse represents the public tracking view of an order. It leaves out
+// customer and price details since the link can be shared with anyone
+type OrderTrackingResponse struct {
+	OrderID        string                     `json:"order_id"`
+	Status         string                     `json:"status"`
+	PlacedAt       string                     `json:"placed_at"`
+	ItemCount      int                        `json:"item_count"`
+	Carrier        string                     `json:"carrier,omitempty"`
+	TrackingNumber string                     `json:"tracking_number,omitempty"`
+	Shipments      []ShipmentResponse         `json:"shipments"`
+	History        []OrderStatusEventResponse `json:"history"`
+}
+
+// ToOrderTrackingResponse converts an order and its status history to OrderTrackingResponse DTO
+func ToOrderTrackingResponse(o *entities.Order, history []entities.OrderStatusEvent) OrderTrackingResponse {
+	events := make([]OrderStatusEventResponse, len(history))
+	for i, e := range history {
+		events[i] = OrderStatusEventResponse{Status: e.Status.String(), Note: e.Note, At: e.CreatedAt.Format(time.RFC3339)}
+	}
+
+	shipments := ToShipmentResponseList(o.Shipments)
+	if shipments == nil {
+		shipments = []ShipmentResponse{}
+	}
+
+	itemCount := 0
+	for _, item := range o.Items {
+		itemCount += item.Quantity
+	}
+
+	return OrderTrackingResponse{
+		OrderID:        o.ID.String(),
+		Status:         o.Status.String(),
+		PlacedAt:       o.CreatedAt.Format(time.RFC3339),
+		ItemCount:      itemCount,
+		Carrier:        o.Carrier,
+		TrackingNumber: o.TrackingNumber,
+		Shipments:      shipments,
+		History:        events,
+	}
+}
@@ -0,0 +1,13 @@
+package dto
+
+// OutboxStatusResponse represents the backlog of the outbox relay
+type OutboxStatusResponse struct {
+	Broker string `json:"broker"`
+	// Pending is the number of events waiting to be published
+	Pending int64 `json:"pending"`
+	// OldestPendingAt is when the oldest waiting event was created, omitted without backlog
+	OldestPendingAt string `json:"oldest_pending_at,omitempty"`
+	LastPublishedAt string `json:"last_published_at,omitempty"`
+	// LastError is the last publishing error, cleared once an event is published again
+	LastError string `json:"last_error,omitempty"`
+}
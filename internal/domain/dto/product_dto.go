@@ -1,59 +1,156 @@
 package dto
 
 import (
+	"io"
+	"math"
 	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/pkg/query"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // CreateProductRequest represents the payload for creating a new product
 type CreateProductRequest struct {
-	Name        string  `json:"name" validate:"required"`
-	Description string  `json:"description" validate:"required"`
-	Price       float64 `json:"price" validate:"required,min=0"`
-	Stock       int     `json:"stock" validate:"required,min=0"`
-	Category    string  `json:"category" validate:"required"`
+	SKU         string `json:"sku" validate:"omitempty,max=64"`
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description" validate:"required"`
+	// Price is in minor units, e.g. cents
+	Price      int64             `json:"price" validate:"required,min=0"`
+	Stock      int               `json:"stock" validate:"required,min=0"`
+	Category   string            `json:"category" validate:"required_without=CategoryID"`
+	CategoryID *uuid.UUID        `json:"category_id" validate:"omitempty"`
+	Attributes map[string]string `json:"attributes" validate:"omitempty"`
+	// Tags holds the slugs of existing tags
+	Tags []string `json:"tags" validate:"omitempty,max=20,dive,required"`
+	// ReorderThreshold alerts admins once the stock drops to it, omit to disable alerts
+	ReorderThreshold *int `json:"reorder_threshold" validate:"omitempty,min=0"`
+	// WeightGrams is the shipping weight of one unit
+	WeightGrams int `json:"weight_grams" validate:"omitempty,min=0"`
 }
 
 // UpdateProductRequest represents the payload for updating an existing product
 type UpdateProductRequest struct {
-	Name        *string  `json:"name" validate:"omitempty"`
-	Description *string  `json:"description" validate:"omitempty"`
-	Price       *float64 `json:"price" validate:"omitempty,min=0"`
-	Stock       *int     `json:"stock" validate:"omitempty,min=0"`
-	Category    *string  `json:"category" validate:"omitempty"`
+	SKU         *string    `json:"sku" validate:"omitempty,max=64"`
+	Name        *string    `json:"name" validate:"omitempty"`
+	Description *string    `json:"description" validate:"omitempty"`
+	Price       *int64     `json:"price" validate:"omitempty,min=0"`
+	Stock       *int       `json:"stock" validate:"omitempty,min=0"`
+	Category    *string    `json:"category" validate:"omitempty"`
+	CategoryID  *uuid.UUID `json:"category_id" validate:"omitempty"`
+	// Attributes replaces all attribute values when set, an empty object clears them
+	Attributes map[string]string `json:"attributes" validate:"omitempty"`
+	// Tags replaces all tags when set, an empty array clears them
+	Tags []string `json:"tags" validate:"omitempty,max=20,dive,required"`
+	// ReorderThreshold sets the low-stock alert level
+	ReorderThreshold *int `json:"reorder_threshold" validate:"omitempty,min=0"`
+	WeightGrams      *int `json:"weight_grams" validate:"omitempty,min=0"`
+	// Version is the version the change is based on, a stale one is refused with 409
+	Version *int `json:"version" validate:"omitempty,min=1"`
 }
 
 // ProductResponse represents the product data returned in responses
 type ProductResponse struct {
-	ID          string  `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Stock       int     `json:"stock"`
-	Category    string  `json:"category"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
+	ID              string                     `json:"id"`
+	SKU             string                     `json:"sku,omitempty"`
+	Name            string                     `json:"name"`
+	Description     string                     `json:"description"`
+	Price           int64                      `json:"price"`
+	Stock           int                        `json:"stock"`
+	Category        string                     `json:"category"`
+	CategoryID      *string                    `json:"category_id"`
+	PrimaryImageURL string                     `json:"primary_image_url,omitempty"`
+	Images          []ProductImageResponse     `json:"images"`
+	Attributes      []ProductAttributeResponse `json:"attributes"`
+	Tags            []ProductTagResponse       `json:"tags"`
+	CreatedAt       string                     `json:"created_at"`
+	UpdatedAt       string                     `json:"updated_at"`
+	ArchivedAt      *string                    `json:"archived_at,omitempty"`
+	// ReorderThreshold is the low-stock alert level, null when alerts are off
+	ReorderThreshold *int `json:"reorder_threshold"`
+	// AvailableStock is the stock not held by pending orders
+	AvailableStock int `json:"available_stock"`
+	WeightGrams    int `json:"weight_grams"`
+	// SuggestedReorderQuantity is set on the low-stock report, it covers the forecast
+	// demand of the coming weeks on top of the reorder threshold
+	SuggestedReorderQuantity *int `json:"suggested_reorder_quantity,omitempty"`
+	// Version changes on every update, send it back with the next update
+	Version int `json:"version"`
+}
+
+// BatchProductRequest represents the payload for looking up several products at once
+type BatchProductRequest struct {
+	IDs []uuid.UUID `json:"ids" validate:"required,min=1,max=100"`
+}
+
+// BatchProductResponse holds the products found in the order they were asked for, and
+// the IDs of those that don't exist or are archived
+type BatchProductResponse struct {
+	Products   []ProductResponse `json:"products"`
+	MissingIDs []string          `json:"missing_ids"`
 }
 
 // ProductListRequest represents the query parameters for listing products
 type ProductListRequest struct {
-	Category string `json:"category" validate:"omitempty"`
-	Limit    int    `json:"limit" validate:"omitempty,min=1,max=100"`
-	Page     int    `json:"page" validate:"omitempty,min=1"`
-	Search   string `json:"search" validate:"omitempty"`
+	Categories []string `json:"category" query:"category" validate:"omitempty,max=20,dive,required"`
+	Limit      int      `json:"limit" query:"limit" default:"10" validate:"omitempty,min=1,max=100"`
+	Page       int      `json:"page" query:"page" default:"1" validate:"omitempty,min=1"`
+	Search     string   `json:"search" query:"search" validate:"omitempty"`
+	MinPrice   *int64   `json:"min_price" query:"min_price" validate:"omitempty,min=0"`
+	MaxPrice   *int64   `json:"max_price" query:"max_price" validate:"omitempty,min=0"`
+	InStock    bool     `json:"in_stock" query:"in_stock"`
+	// Attributes filters by attribute code and value, from ?attr.<code>=<value> or ?attr[<code>]=<value>
+	Attributes map[string]string `json:"attributes" validate:"omitempty,max=10"`
+	// Tags filters by tag slug, a product matches when it has any of them
+	Tags []string `json:"tag" query:"tag" validate:"omitempty,max=20,dive,required"`
+	// ExactCount counts the matching products exactly; false allows an estimated total,
+	// which stays fast on large catalogs
+	ExactCount bool `json:"exact_count" query:"exact_count" default:"true"`
+	// Sort orders the offset listing, newest first when empty
+	Sort query.Sort `json:"sort" query:"sort" allowed:"name,price,stock,created_at" validate:"omitempty,max=3"`
+	// Cursor switches to keyset pagination when set; empty requests the first page
+	Cursor *string `json:"cursor" query:"cursor"`
 }
 
 // ToProductResponse converts a Product entity to ProductResponse DTO
 func ToProductResponse(p *entities.Product) ProductResponse {
+	var categoryID *string
+	if p.CategoryID != nil {
+		id := p.CategoryID.String()
+		categoryID = &id
+	}
+	var primaryImageURL string
+	for _, img := range p.Images {
+		if img.IsPrimary {
+			primaryImageURL = img.URL
+		}
+	}
+	var archivedAt *string
+	if p.DeletedAt != nil {
+		t := p.DeletedAt.Format(time.RFC3339)
+		archivedAt = &t
+	}
 	return ProductResponse{
-		ID:          p.ID.String(),
-		Name:        p.Name,
-		Description: p.Description,
-		Price:       p.Price,
-		Stock:       p.Stock,
-		Category:    p.Category,
-		CreatedAt:   p.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   p.UpdatedAt.Format(time.RFC3339),
+		ID:               p.ID.String(),
+		SKU:              p.SKU,
+		Name:             p.Name,
+		Description:      p.Description,
+		Price:            p.Price,
+		Stock:            p.Stock,
+		Category:         p.Category,
+		CategoryID:       categoryID,
+		PrimaryImageURL:  primaryImageURL,
+		Images:           ToProductImageResponseList(p.Images),
+		Attributes:       ToProductAttributeResponseList(p.Attributes),
+		Tags:             ToProductTagResponseList(p.Tags),
+		CreatedAt:        p.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        p.UpdatedAt.Format(time.RFC3339),
+		ArchivedAt:       archivedAt,
+		ReorderThreshold: p.ReorderThreshold,
+		AvailableStock:   max(p.Stock-p.ReservedStock, 0),
+		WeightGrams:      p.WeightGrams,
+		Version:          p.Version,
 	}
 }
 
@@ -65,3 +162,363 @@ func ToProductResponseList(products []*entities.Product) []ProductResponse {
 	}
 	return responses
 }
+
+// UploadProductImageRequest represents an uploaded product image
+type UploadProductImageRequest struct {
+	File      io.Reader
+	Size      int64
+	IsPrimary bool
+}
+
+// ReorderProductImagesRequest represents the payload for reordering a product gallery
+type ReorderProductImagesRequest struct {
+	ImageIDs []uuid.UUID `json:"image_ids" validate:"required,min=1,dive,required"`
+}
+
+// ProductImageResponse represents a product image returned in responses
+type ProductImageResponse struct {
+	ID          string `json:"id"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Position    int    `json:"position"`
+	IsPrimary   bool   `json:"is_primary"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// ToProductImageResponse converts a ProductImage entity to ProductImageResponse DTO
+func ToProductImageResponse(img *entities.ProductImage) ProductImageResponse {
+	return ProductImageResponse{
+		ID:          img.ID.String(),
+		URL:         img.URL,
+		ContentType: img.ContentType,
+		Size:        img.Size,
+		Position:    img.Position,
+		IsPrimary:   img.IsPrimary,
+		CreatedAt:   img.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToProductImageResponseList converts a slice of ProductImage entities to responses
+func ToProductImageResponseList(images []entities.ProductImage) []ProductImageResponse {
+	responses := make([]ProductImageResponse, len(images))
+	for i := range images {
+		responses[i] = ToProductImageResponse(&images[i])
+	}
+	return responses
+}
+
+// ImportProductsRequest represents an uploaded product import file
+type ImportProductsRequest struct {
+	File io.Reader
+	// Format is "csv" or "json"
+	Format string
+}
+
+// ProductImportRow represents a single product of an import file, matched by SKU.
+// CSV files use the JSON names as header, e.g. sku,name,description,price,stock,category.
+// JSON prices are in minor units, CSV prices are written in units, e.g. 12500.50
+type ProductImportRow struct {
+	SKU         string     `json:"sku" validate:"required,max=64"`
+	Name        string     `json:"name" validate:"required"`
+	Description string     `json:"description" validate:"required"`
+	Price       int64      `json:"price" validate:"min=0"`
+	Stock       int        `json:"stock" validate:"min=0"`
+	Category    string     `json:"category" validate:"required_without=CategoryID"`
+	CategoryID  *uuid.UUID `json:"category_id" validate:"omitempty"`
+}
+
+// ProductImportRowResult represents the outcome of one import row
+type ProductImportRowResult struct {
+	// Row is the 1-based data row, the CSV header isn't counted
+	Row       int                        `json:"row"`
+	SKU       string                     `json:"sku"`
+	Status    string                     `json:"status"`
+	ProductID string                     `json:"product_id,omitempty"`
+	Errors    []apperror.ValidationError `json:"errors,omitempty"`
+}
+
+// ProductImportReport represents the per-row result of a product import
+type ProductImportReport struct {
+	Total   int                      `json:"total"`
+	Created int                      `json:"created"`
+	Updated int                      `json:"updated"`
+	Failed  int                      `json:"failed"`
+	Rows    []ProductImportRowResult `json:"rows"`
+}
+
+// PriceRuleRequest represents a single rule of a bulk price update. A rule applies to
+// the products in its category or product list, or to all products when neither is set
+type PriceRuleRequest struct {
+	CategoryID *uuid.UUID  `json:"category_id" validate:"omitempty"`
+	Category   string      `json:"category" validate:"omitempty"`
+	ProductIDs []uuid.UUID `json:"product_ids" validate:"omitempty,max=500"`
+	Adjustment string      `json:"adjustment" validate:"required,oneof=percent amount set"`
+	// Value is a percentage for percent rules and an amount in minor units otherwise
+	Value   float64 `json:"value"`
+	RoundTo int64   `json:"round_to" validate:"omitempty,gt=0"`
+	Ending  int64   `json:"ending" validate:"omitempty,min=0,ltfield=RoundTo"`
+}
+
+// BulkPriceUpdateRequest represents the payload for a rule based bulk price update.
+// Rules are applied in order, so a product matched by several rules gets all of them
+type BulkPriceUpdateRequest struct {
+	Rules   []PriceRuleRequest `json:"rules" validate:"required,min=1,max=50,dive"`
+	Preview bool               `json:"preview"`
+}
+
+// PriceChangeResponse represents the price change of a product
+type PriceChangeResponse struct {
+	ProductID string `json:"product_id"`
+	Name      string `json:"name"`
+	OldPrice  int64  `json:"old_price"`
+	NewPrice  int64  `json:"new_price"`
+}
+
+// BulkPriceUpdateResponse represents the result of a bulk price update
+type BulkPriceUpdateResponse struct {
+	Preview bool                  `json:"preview"`
+	Applied bool                  `json:"applied"`
+	Total   int                   `json:"total"`
+	Changes []PriceChangeResponse `json:"changes"`
+}
+
+// ToBulkPriceUpdateResponse converts computed price changes to BulkPriceUpdateResponse DTO
+func ToBulkPriceUpdateResponse(changes []entities.PriceChange, preview bool) BulkPriceUpdateResponse {
+	responses := make([]PriceChangeResponse, len(changes))
+	for i, c := range changes {
+		responses[i] = PriceChangeResponse{
+			ProductID: c.ProductID.String(),
+			Name:      c.Name,
+			OldPrice:  c.OldPrice,
+			NewPrice:  c.NewPrice,
+		}
+	}
+	return BulkPriceUpdateResponse{
+		Preview: preview,
+		Applied: !preview && len(changes) > 0,
+		Total:   len(changes),
+		Changes: responses,
+	}
+}
+
+// ProductReferencesResponse lists what still refers to a product
+type ProductReferencesResponse struct {
+	// OpenOrders counts the orders that aren't completed or cancelled per status
+	OpenOrders map[string]int `json:"open_orders"`
+	// OpenOrderIDs holds up to 100 of the most recent open orders
+	OpenOrderIDs       []string `json:"open_order_ids"`
+	ClosedOrders       int      `json:"closed_orders"`
+	ActiveReservations int      `json:"active_reservations"`
+	ReservedQuantity   int      `json:"reserved_quantity"`
+	Images             int      `json:"images"`
+	Attributes         int      `json:"attributes"`
+	Tags               int      `json:"tags"`
+	// Blocking is true when the block policy refuses to delete the product
+	Blocking bool `json:"blocking"`
+}
+
+// ProductDeleteReport describes what deleting a product did
+type ProductDeleteReport struct {
+	ProductID  string                    `json:"product_id"`
+	Policy     string                    `json:"policy"`
+	Archived   bool                      `json:"archived"`
+	References ProductReferencesResponse `json:"references"`
+	// CancelledOrders are the unpaid orders cancelled by the cleanup policy
+	CancelledOrders []string `json:"cancelled_orders"`
+}
+
+// ToProductReferencesResponse converts ProductReferences to ProductReferencesResponse DTO
+func ToProductReferencesResponse(refs *entities.ProductReferences) ProductReferencesResponse {
+	openOrders := make(map[string]int, len(refs.OpenOrders))
+	for status, n := range refs.OpenOrders {
+		openOrders[string(status)] = n
+	}
+	orderIDs := make([]string, len(refs.OpenOrderIDs))
+	for i, id := range refs.OpenOrderIDs {
+		orderIDs[i] = id.String()
+	}
+	return ProductReferencesResponse{
+		OpenOrders:         openOrders,
+		OpenOrderIDs:       orderIDs,
+		ClosedOrders:       refs.ClosedOrders,
+		ActiveReservations: refs.ActiveReservations,
+		ReservedQuantity:   refs.ReservedQuantity,
+		Images:             refs.Images,
+		Attributes:         refs.Attributes,
+		Tags:               refs.Tags,
+		Blocking:           refs.Blocking(),
+	}
+}
+
+// RelatedProductsRequest represents the query parameters of the related products
+type RelatedProductsRequest struct {
+	Limit int `json:"limit" query:"limit" default:"10" validate:"omitempty,min=1,max=50"`
+}
+
+// ProductStatsRequest represents the query parameters of the product statistics, top
+// sellers are counted over [from, to)
+type ProductStatsRequest struct {
+	From time.Time `json:"from" query:"from"`
+	To   time.Time `json:"to" query:"to,end"`
+	// TopLimit is the number of top sellers, 10 by default
+	TopLimit int `json:"top" query:"top" default:"10"`
+}
+
+// ProductStatsResponse represents the catalog and sales statistics for admins, amounts
+// are in minor units
+type ProductStatsResponse struct {
+	Products       int                     `json:"products"`
+	ArchivedCount  int                     `json:"archived"`
+	Stock          int64                   `json:"stock"`
+	ReservedStock  int64                   `json:"reserved_stock"`
+	InventoryValue int64                   `json:"inventory_value"`
+	OutOfStock     int                     `json:"out_of_stock"`
+	LowStock       int                     `json:"low_stock"`
+	Categories     []CategoryStatsResponse `json:"categories"`
+	TopSellers     TopSellersResponse      `json:"top_sellers"`
+}
+
+// CategoryStatsResponse represents the product statistics of a category
+type CategoryStatsResponse struct {
+	CategoryID     *string `json:"category_id"`
+	Category       string  `json:"category"`
+	Products       int     `json:"products"`
+	Stock          int64   `json:"stock"`
+	InventoryValue int64   `json:"inventory_value"`
+	OutOfStock     int     `json:"out_of_stock"`
+}
+
+// TopSellersResponse represents the best selling products of a period
+type TopSellersResponse struct {
+	From     string                 `json:"from"`
+	To       string                 `json:"to"`
+	Products []ProductSalesResponse `json:"products"`
+}
+
+// ProductSalesResponse represents what a product sold over a period
+type ProductSalesResponse struct {
+	ProductID string `json:"product_id"`
+	Name      string `json:"name"`
+	Quantity  int64  `json:"quantity"`
+	Orders    int    `json:"orders"`
+	Revenue   int64  `json:"revenue"`
+}
+
+// ToProductStatsResponse converts the product statistics to ProductStatsResponse DTO
+func ToProductStatsResponse(summary *entities.InventorySummary, categories []entities.CategoryStats, sales []entities.ProductSales, from, to time.Time) ProductStatsResponse {
+	response := ProductStatsResponse{
+		Products:       summary.Products,
+		ArchivedCount:  summary.Archived,
+		Stock:          summary.Stock,
+		ReservedStock:  summary.ReservedStock,
+		InventoryValue: summary.InventoryValue,
+		OutOfStock:     summary.OutOfStock,
+		LowStock:       summary.LowStock,
+		Categories:     make([]CategoryStatsResponse, len(categories)),
+		TopSellers: TopSellersResponse{
+			From:     from.Format(time.RFC3339),
+			To:       to.Format(time.RFC3339),
+			Products: make([]ProductSalesResponse, len(sales)),
+		},
+	}
+	for i, c := range categories {
+		var categoryID *string
+		if c.CategoryID != nil {
+			id := c.CategoryID.String()
+			categoryID = &id
+		}
+		response.Categories[i] = CategoryStatsResponse{
+			CategoryID:     categoryID,
+			Category:       c.Category,
+			Products:       c.Products,
+			Stock:          c.Stock,
+			InventoryValue: c.InventoryValue,
+			OutOfStock:     c.OutOfStock,
+		}
+	}
+	for i, s := range sales {
+		response.TopSellers.Products[i] = ProductSalesResponse{
+			ProductID: s.ProductID.String(),
+			Name:      s.Name,
+			Quantity:  s.Quantity,
+			Orders:    s.Orders,
+			Revenue:   s.Revenue,
+		}
+	}
+	return response
+}
+
+// DemandForecastRequest represents the query parameters of a product demand forecast
+type DemandForecastRequest struct {
+	ProductID uuid.UUID `json:"product_id" query:"product_id" validate:"required"`
+	// Weeks is the number of weeks forecast, 4 by default
+	Weeks int `json:"weeks" query:"weeks"`
+	// Window is the number of latest weeks averaged, 4 by default
+	Window int `json:"window" query:"window"`
+}
+
+// DemandForecastResponse represents the expected demand of a product and the quantity
+// to reorder to cover it
+type DemandForecastResponse struct {
+	ProductID string `json:"product_id"`
+	Name      string `json:"name"`
+	// Method is moving_average, or seasonal when last year's sales weighted the average
+	Method                   string                 `json:"method"`
+	Window                   int                    `json:"window"`
+	WeeklyAverage            float64                `json:"weekly_average"`
+	History                  []WeeklyDemandResponse `json:"history"`
+	Forecast                 []WeeklyDemandResponse `json:"forecast"`
+	ForecastTotal            float64                `json:"forecast_total"`
+	AvailableStock           int                    `json:"available_stock"`
+	ReorderThreshold         *int                   `json:"reorder_threshold"`
+	SuggestedReorderQuantity int                    `json:"suggested_reorder_quantity"`
+}
+
+// WeeklyDemandResponse represents the quantity sold, or expected, in the week starting
+// on WeekStart
+type WeeklyDemandResponse struct {
+	WeekStart string  `json:"week_start"`
+	Quantity  float64 `json:"quantity"`
+}
+
+// ToDemandForecastResponse converts a DemandForecast of product to DemandForecastResponse DTO
+func ToDemandForecastResponse(product *entities.Product, forecast *entities.DemandForecast, suggested int) DemandForecastResponse {
+	method := "moving_average"
+	if forecast.Seasonal {
+		method = "seasonal"
+	}
+	response := DemandForecastResponse{
+		ProductID:                product.ID.String(),
+		Name:                     product.Name,
+		Method:                   method,
+		Window:                   forecast.Window,
+		WeeklyAverage:            roundQuantity(forecast.Baseline),
+		History:                  make([]WeeklyDemandResponse, len(forecast.History)),
+		Forecast:                 make([]WeeklyDemandResponse, len(forecast.Weekly)),
+		ForecastTotal:            roundQuantity(forecast.Total),
+		AvailableStock:           max(product.Stock-product.ReservedStock, 0),
+		ReorderThreshold:         product.ReorderThreshold,
+		SuggestedReorderQuantity: suggested,
+	}
+	week := 7 * 24 * time.Hour
+	for i, quantity := range forecast.History {
+		response.History[i] = WeeklyDemandResponse{
+			WeekStart: forecast.HistoryStart.Add(time.Duration(i) * week).Format(time.DateOnly),
+			Quantity:  float64(quantity),
+		}
+	}
+	forecastStart := forecast.HistoryStart.Add(time.Duration(len(forecast.History)) * week)
+	for i, quantity := range forecast.Weekly {
+		response.Forecast[i] = WeeklyDemandResponse{
+			WeekStart: forecastStart.Add(time.Duration(i) * week).Format(time.DateOnly),
+			Quantity:  roundQuantity(quantity),
+		}
+	}
+	return response
+}
+
+// roundQuantity rounds a forecast quantity to one decimal
+func roundQuantity(q float64) float64 {
+	return math.Round(q*10) / 10
+}
@@ -0,0 +1,118 @@
+package dto
+
+import (
+	"io"
+	"postgresDB/internal/domain/entities"
+	"time"
+)
+
+// UploadSettlementReportRequest represents an uploaded provider settlement report. The
+// CSV has the columns order_id,amount,settled_at,reference and covers [PeriodStart, PeriodEnd)
+type UploadSettlementReportRequest struct {
+	File        io.Reader
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+}
+
+// ResolveReconciliationIssueRequest represents the payload for closing a review queue entry
+type ResolveReconciliationIssueRequest struct {
+	Note string `json:"note" validate:"required,max=500"`
+}
+
+// ReconciliationRunResponse represents the result of a reconciliation run
+type ReconciliationRunResponse struct {
+	ID            string `json:"id"`
+	Source        string `json:"source"`
+	PeriodStart   string `json:"period_start"`
+	PeriodEnd     string `json:"period_end"`
+	ReportedCount int    `json:"reported_count"`
+	RecordedCount int    `json:"recorded_count"`
+	MatchedCount  int    `json:"matched_count"`
+	IssueCount    int    `json:"issue_count"`
+	ReportedTotal int64  `json:"reported_total"`
+	RecordedTotal int64  `json:"recorded_total"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// ReconciliationIssueResponse represents an entry of the reconciliation review queue
+type ReconciliationIssueResponse struct {
+	ID             string  `json:"id"`
+	RunID          string  `json:"run_id"`
+	OrderID        string  `json:"order_id"`
+	Kind           string  `json:"kind"`
+	Reference      string  `json:"reference,omitempty"`
+	ExpectedAmount *int64  `json:"expected_amount"`
+	ReportedAmount *int64  `json:"reported_amount"`
+	Status         string  `json:"status"`
+	Note           string  `json:"note,omitempty"`
+	ResolvedBy     *string `json:"resolved_by,omitempty"`
+	ResolvedAt     *string `json:"resolved_at,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+}
+
+// ReconciliationSummaryResponse represents the reconciliation state at a glance
+type ReconciliationSummaryResponse struct {
+	LatestRun        *ReconciliationRunResponse `json:"latest_run"`
+	OpenIssues       int                        `json:"open_issues"`
+	OpenIssuesByKind map[string]int             `json:"open_issues_by_kind"`
+}
+
+// ToReconciliationRunResponse converts a ReconciliationRun entity to ReconciliationRunResponse DTO
+func ToReconciliationRunResponse(run *entities.ReconciliationRun) ReconciliationRunResponse {
+	return ReconciliationRunResponse{
+		ID:            run.ID.String(),
+		Source:        run.Source,
+		PeriodStart:   run.PeriodStart.Format(time.RFC3339),
+		PeriodEnd:     run.PeriodEnd.Format(time.RFC3339),
+		ReportedCount: run.ReportedCount,
+		RecordedCount: run.RecordedCount,
+		MatchedCount:  run.MatchedCount,
+		IssueCount:    run.IssueCount,
+		ReportedTotal: run.ReportedTotal,
+		RecordedTotal: run.RecordedTotal,
+		CreatedAt:     run.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToReconciliationRunResponseList converts reconciliation runs to responses
+func ToReconciliationRunResponseList(runs []*entities.ReconciliationRun) []ReconciliationRunResponse {
+	responses := make([]ReconciliationRunResponse, len(runs))
+	for i, run := range runs {
+		responses[i] = ToReconciliationRunResponse(run)
+	}
+	return responses
+}
+
+// ToReconciliationIssueResponse converts a ReconciliationIssue entity to ReconciliationIssueResponse DTO
+func ToReconciliationIssueResponse(issue *entities.ReconciliationIssue) ReconciliationIssueResponse {
+	response := ReconciliationIssueResponse{
+		ID:             issue.ID.String(),
+		RunID:          issue.RunID.String(),
+		OrderID:        issue.OrderID.String(),
+		Kind:           string(issue.Kind),
+		Reference:      issue.Reference,
+		ExpectedAmount: issue.ExpectedAmount,
+		ReportedAmount: issue.ReportedAmount,
+		Status:         issue.Status,
+		Note:           issue.Note,
+		CreatedAt:      issue.CreatedAt.Format(time.RFC3339),
+	}
+	if issue.ResolvedBy != nil {
+		by := issue.ResolvedBy.String()
+		response.ResolvedBy = &by
+	}
+	if issue.ResolvedAt != nil {
+		at := issue.ResolvedAt.Format(time.RFC3339)
+		response.ResolvedAt = &at
+	}
+	return response
+}
+
+// ToReconciliationIssueResponseList converts reconciliation issues to responses
+func ToReconciliationIssueResponseList(issues []*entities.ReconciliationIssue) []ReconciliationIssueResponse {
+	responses := make([]ReconciliationIssueResponse, len(issues))
+	for i, issue := range issues {
+		responses[i] = ToReconciliationIssueResponse(issue)
+	}
+	return responses
+}
@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"postgresDB/internal/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefundOrderRequest represents the payload of a refund. Without items and amount the
+// rest of the order is refunded; items are the returned items, refunded at their price
+// unless amount is given, and go back into stock
+type RefundOrderRequest struct {
+	Items  []RefundItemRequest `json:"items" validate:"omitempty,dive"`
+	Amount *int64              `json:"amount" validate:"omitempty,min=1"`
+	Reason string              `json:"reason" validate:"max=500"`
+}
+
+// RefundItemRequest represents a returned quantity of an order item
+type RefundItemRequest struct {
+	OrderItemID uuid.UUID `json:"order_item_id" validate:"required"`
+	Quantity    int       `json:"quantity" validate:"required,min=1"`
+}
+
+// RefundResponse represents a refund of an order
+type RefundResponse struct {
+	ID            string               `json:"id"`
+	OrderID       string               `json:"order_id"`
+	Provider      string               `json:"provider"`
+	ProviderRef   string               `json:"provider_ref,omitempty"`
+	Status        string               `json:"status"`
+	Amount        int64                `json:"amount"`
+	Reason        string               `json:"reason,omitempty"`
+	FailureReason string               `json:"failure_reason,omitempty"`
+	Items         []RefundItemResponse `json:"items"`
+	// OrderStatus is the status of the order after the refund
+	OrderStatus string `json:"order_status,omitempty"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// RefundItemResponse represents a returned quantity of an order item
+type RefundItemResponse struct {
+	OrderItemID string `json:"order_item_id"`
+	ProductID   string `json:"product_id"`
+	Quantity    int    `json:"quantity"`
+}
+
+// ToRefundResponse converts a Refund entity to RefundResponse DTO
+func ToRefundResponse(r *entities.Refund) RefundResponse {
+	items := make([]RefundItemResponse, len(r.Items))
+	for i, item := range r.Items {
+		items[i] = RefundItemResponse{
+			OrderItemID: item.OrderItemID.String(),
+			ProductID:   item.ProductID.String(),
+			Quantity:    item.Quantity,
+		}
+	}
+	return RefundResponse{
+		ID:            r.ID.String(),
+		OrderID:       r.OrderID.String(),
+		Provider:      r.Provider,
+		ProviderRef:   r.ProviderRef,
+		Status:        string(r.Status),
+		Amount:        r.Amount,
+		Reason:        r.Reason,
+		FailureReason: r.FailureReason,
+		Items:         items,
+		CreatedAt:     r.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     r.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToRefundResponseList converts refunds to responses
+func ToRefundResponseList(refunds []*entities.Refund) []RefundResponse {
+	responses := make([]RefundResponse, len(refunds))
+	for i, r := range refunds {
+		responses[i] = ToRefundResponse(r)
+	}
+	return responses
+}
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"postgresDB/internal/domain/entities"
+	"time"
+)
+
+// SessionResponse represents a login session and the device it was started from
+type SessionResponse struct {
+	ID         string     `json:"id"`
+	Device     string     `json:"device"`
+	IP         string     `json:"ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	EvictedAt  *time.Time `json:"evicted_at,omitempty"`
+}
+
+// SessionListResponse represents the active sessions of a user and the sessions
+// recently evicted to make room for a new login
+type SessionListResponse struct {
+	// MaxSessions is the number of sessions allowed at once, 0 for no limit
+	MaxSessions int               `json:"max_sessions"`
+	Sessions    []SessionResponse `json:"sessions"`
+	Evicted     []SessionResponse `json:"evicted"`
+}
+
+func ToSessionResponse(s *entities.Session) SessionResponse {
+	return SessionResponse{
+		ID:         s.Family,
+		Device:     s.Device,
+		IP:         s.IP,
+		CreatedAt:  s.CreatedAt,
+		LastUsedAt: s.LastUsedAt,
+		ExpiresAt:  s.ExpiresAt,
+		EvictedAt:  s.EvictedAt,
+	}
+}
+
+func ToSessionResponseList(sessions []*entities.Session) []SessionResponse {
+	responses := make([]SessionResponse, len(sessions))
+	for i, s := range sessions {
+		responses[i] = ToSessionResponse(s)
+	}
+	return responses
+}
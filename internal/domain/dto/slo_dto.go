@@ -0,0 +1,29 @@
+package dto
+
+// SLOResponse represents a service level objective, how much of its error budget is left
+// and how fast it burns
+type SLOResponse struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Objective   float64 `json:"objective"`
+	// Period is the error budget period, or the time since the server started when shorter
+	Period      string `json:"period"`
+	GoodEvents  int64  `json:"good_events"`
+	TotalEvents int64  `json:"total_events"`
+	// SLI is the share of good events over the period, 1 without events
+	SLI float64 `json:"sli"`
+	// ErrorBudgetRemaining is the share of the period's error budget left, negative once spent
+	ErrorBudgetRemaining float64               `json:"error_budget_remaining"`
+	BurnRates            []SLOBurnRateResponse `json:"burn_rates"`
+	// Alert is "fast_burn" or "slow_burn" while the burn rate is alerted, empty otherwise
+	Alert string `json:"alert,omitempty"`
+}
+
+// SLOBurnRateResponse represents how many times faster than sustainable the error budget
+// burned over a window
+type SLOBurnRateResponse struct {
+	Window string  `json:"window"`
+	Rate   float64 `json:"rate"`
+	// Threshold is the rate from which the window alerts
+	Threshold float64 `json:"threshold"`
+}
@@ -0,0 +1,145 @@
+package dto
+
+import (
+	"postgresDB/internal/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateStockCountRequest represents the payload for opening a stock count
+type CreateStockCountRequest struct {
+	WarehouseID uuid.UUID `json:"warehouse_id" validate:"required"`
+	// CategoryID limits the count to the products of one category
+	CategoryID *uuid.UUID `json:"category_id"`
+	Note       string     `json:"note" validate:"omitempty,max=500"`
+}
+
+// RecordStockCountRequest represents counted quantities, a product can be counted again
+// until the count is submitted
+type RecordStockCountRequest struct {
+	Items []StockCountEntryRequest `json:"items" validate:"required,min=1,max=500,dive"`
+}
+
+// StockCountEntryRequest is the counted quantity of a product
+type StockCountEntryRequest struct {
+	ProductID       uuid.UUID `json:"product_id" validate:"required"`
+	CountedQuantity *int      `json:"counted_quantity" validate:"required,min=0"`
+}
+
+// ReviewStockCountRequest represents the payload for approving or rejecting a stock count
+type ReviewStockCountRequest struct {
+	Note string `json:"note" validate:"omitempty,max=500"`
+}
+
+// StockCountListRequest represents the query parameters for listing stock counts
+type StockCountListRequest struct {
+	Status string `json:"status" query:"status" validate:"omitempty,oneof=counting submitted approved rejected"`
+	PageRequest
+}
+
+// StockCountResponse represents a stock count returned in responses. Items and Summary
+// are left out of listings
+type StockCountResponse struct {
+	ID          string                   `json:"id"`
+	WarehouseID string                   `json:"warehouse_id"`
+	CategoryID  *string                  `json:"category_id,omitempty"`
+	Status      string                   `json:"status"`
+	Note        string                   `json:"note,omitempty"`
+	Summary     *StockCountSummary       `json:"summary,omitempty"`
+	Items       []StockCountItemResponse `json:"items,omitempty"`
+	CreatedBy   *string                  `json:"created_by,omitempty"`
+	SubmittedBy *string                  `json:"submitted_by,omitempty"`
+	SubmittedAt *string                  `json:"submitted_at,omitempty"`
+	ReviewedBy  *string                  `json:"reviewed_by,omitempty"`
+	ReviewedAt  *string                  `json:"reviewed_at,omitempty"`
+	ReviewNote  string                   `json:"review_note,omitempty"`
+	CreatedAt   string                   `json:"created_at"`
+	UpdatedAt   string                   `json:"updated_at"`
+}
+
+// StockCountSummary totals the progress and variances of a stock count
+type StockCountSummary struct {
+	Products     int `json:"products"`
+	Counted      int `json:"counted"`
+	WithVariance int `json:"with_variance"`
+	// NetVariance is the sum of all variances, negative when stock is missing
+	NetVariance int `json:"net_variance"`
+}
+
+// StockCountItemResponse represents a product of a stock count with its variance
+type StockCountItemResponse struct {
+	ProductID        string  `json:"product_id"`
+	ProductName      string  `json:"product_name"`
+	ExpectedQuantity int     `json:"expected_quantity"`
+	CountedQuantity  *int    `json:"counted_quantity"`
+	Variance         *int    `json:"variance"`
+	CountedBy        *string `json:"counted_by,omitempty"`
+	CountedAt        *string `json:"counted_at,omitempty"`
+}
+
+// ToStockCountResponse converts a StockCount entity to StockCountResponse DTO, with its
+// items and summary when they were loaded
+func ToStockCountResponse(c *entities.StockCount) StockCountResponse {
+	response := StockCountResponse{
+		ID:          c.ID.String(),
+		WarehouseID: c.WarehouseID.String(),
+		CategoryID:  uuidString(c.CategoryID),
+		Status:      string(c.Status),
+		Note:        c.Note,
+		CreatedBy:   uuidString(c.CreatedBy),
+		SubmittedBy: uuidString(c.SubmittedBy),
+		ReviewedBy:  uuidString(c.ReviewedBy),
+		ReviewNote:  c.ReviewNote,
+		CreatedAt:   c.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   c.UpdatedAt.Format(time.RFC3339),
+	}
+	if c.SubmittedAt != nil {
+		at := c.SubmittedAt.Format(time.RFC3339)
+		response.SubmittedAt = &at
+	}
+	if c.ReviewedAt != nil {
+		at := c.ReviewedAt.Format(time.RFC3339)
+		response.ReviewedAt = &at
+	}
+	if c.Items == nil {
+		return response
+	}
+
+	summary := StockCountSummary{Products: len(c.Items)}
+	response.Items = make([]StockCountItemResponse, len(c.Items))
+	for i, item := range c.Items {
+		itemResponse := StockCountItemResponse{
+			ProductID:        item.ProductID.String(),
+			ProductName:      item.ProductName,
+			ExpectedQuantity: item.Expected,
+			CountedQuantity:  item.Counted,
+			CountedBy:        uuidString(item.CountedBy),
+		}
+		if item.Counted != nil {
+			variance := item.Variance()
+			itemResponse.Variance = &variance
+			summary.Counted++
+			summary.NetVariance += variance
+			if variance != 0 {
+				summary.WithVariance++
+			}
+		}
+		if item.CountedAt != nil {
+			at := item.CountedAt.Format(time.RFC3339)
+			itemResponse.CountedAt = &at
+		}
+		response.Items[i] = itemResponse
+	}
+	response.Summary = &summary
+	return response
+}
+
+// ToStockCountResponseList converts StockCount entities to StockCountResponse DTOs
+func ToStockCountResponseList(counts []*entities.StockCount) []StockCountResponse {
+	responses := make([]StockCountResponse, len(counts))
+	for i, c := range counts {
+		responses[i] = ToStockCountResponse(c)
+	}
+	return responses
+}
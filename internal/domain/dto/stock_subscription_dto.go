@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"postgresDB/internal/domain/entities"
+	"time"
+)
+
+// StockSubscriptionResponse represents a back-in-stock subscription returned in responses
+type StockSubscriptionResponse struct {
+	ProductID   string `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Stock       int    `json:"stock"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// ToStockSubscriptionResponse converts a StockSubscription entity to StockSubscriptionResponse DTO
+func ToStockSubscriptionResponse(s *entities.StockSubscription) StockSubscriptionResponse {
+	return StockSubscriptionResponse{
+		ProductID:   s.ProductID.String(),
+		ProductName: s.ProductName,
+		Stock:       s.Stock,
+		CreatedAt:   s.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToStockSubscriptionResponseList converts StockSubscription entities to StockSubscriptionResponse DTOs
+func ToStockSubscriptionResponseList(subscriptions []*entities.StockSubscription) []StockSubscriptionResponse {
+	responses := make([]StockSubscriptionResponse, len(subscriptions))
+	for i, s := range subscriptions {
+		responses[i] = ToStockSubscriptionResponse(s)
+	}
+	return responses
+}
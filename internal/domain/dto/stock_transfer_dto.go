@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"postgresDB/internal/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateStockTransferRequest represents the payload for creating a stock transfer
+type CreateStockTransferRequest struct {
+	SourceWarehouseID      uuid.UUID                  `json:"source_warehouse_id" validate:"required"`
+	DestinationWarehouseID uuid.UUID                  `json:"destination_warehouse_id" validate:"required"`
+	Items                  []StockTransferItemRequest `json:"items" validate:"required,min=1,max=100,dive"`
+	Note                   string                     `json:"note" validate:"omitempty,max=500"`
+}
+
+// StockTransferItemRequest is the quantity of a product to transfer
+type StockTransferItemRequest struct {
+	ProductID uuid.UUID `json:"product_id" validate:"required"`
+	Quantity  int       `json:"quantity" validate:"required,min=1"`
+}
+
+// StockTransferListRequest represents the query parameters for listing stock transfers,
+// the open ones by default
+type StockTransferListRequest struct {
+	Status string `json:"status" query:"status" default:"open" validate:"omitempty,oneof=open all draft in_transit received cancelled"`
+	PageRequest
+}
+
+// StockTransferResponse represents a stock transfer returned in responses
+type StockTransferResponse struct {
+	ID                     string                      `json:"id"`
+	SourceWarehouseID      string                      `json:"source_warehouse_id"`
+	DestinationWarehouseID string                      `json:"destination_warehouse_id"`
+	Status                 string                      `json:"status"`
+	Note                   string                      `json:"note,omitempty"`
+	Items                  []StockTransferItemResponse `json:"items"`
+	CreatedBy              *string                     `json:"created_by,omitempty"`
+	ShippedBy              *string                     `json:"shipped_by,omitempty"`
+	ShippedAt              *string                     `json:"shipped_at,omitempty"`
+	ReceivedBy             *string                     `json:"received_by,omitempty"`
+	ReceivedAt             *string                     `json:"received_at,omitempty"`
+	CreatedAt              string                      `json:"created_at"`
+	UpdatedAt              string                      `json:"updated_at"`
+}
+
+// StockTransferItemResponse represents an item of a stock transfer
+type StockTransferItemResponse struct {
+	ProductID   string `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Quantity    int    `json:"quantity"`
+}
+
+// ToStockTransferResponse converts a StockTransfer entity to StockTransferResponse DTO
+func ToStockTransferResponse(t *entities.StockTransfer) StockTransferResponse {
+	items := make([]StockTransferItemResponse, len(t.Items))
+	for i, item := range t.Items {
+		items[i] = StockTransferItemResponse{
+			ProductID:   item.ProductID.String(),
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+		}
+	}
+
+	response := StockTransferResponse{
+		ID:                     t.ID.String(),
+		SourceWarehouseID:      t.SourceWarehouseID.String(),
+		DestinationWarehouseID: t.DestinationWarehouseID.String(),
+		Status:                 string(t.Status),
+		Note:                   t.Note,
+		Items:                  items,
+		CreatedBy:              uuidString(t.CreatedBy),
+		ShippedBy:              uuidString(t.ShippedBy),
+		ReceivedBy:             uuidString(t.ReceivedBy),
+		CreatedAt:              t.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:              t.UpdatedAt.Format(time.RFC3339),
+	}
+	if t.ShippedAt != nil {
+		at := t.ShippedAt.Format(time.RFC3339)
+		response.ShippedAt = &at
+	}
+	if t.ReceivedAt != nil {
+		at := t.ReceivedAt.Format(time.RFC3339)
+		response.ReceivedAt = &at
+	}
+	return response
+}
+
+// ToStockTransferResponseList converts StockTransfer entities to StockTransferResponse DTOs
+func ToStockTransferResponseList(transfers []*entities.StockTransfer) []StockTransferResponse {
+	responses := make([]StockTransferResponse, len(transfers))
+	for i, t := range transfers {
+		responses[i] = ToStockTransferResponse(t)
+	}
+	return responses
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"postgresDB/internal/domain/entities"
+	"time"
+)
+
+// CreateTagRequest represents the payload for creating a tag
+type CreateTagRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	Slug string `json:"slug" validate:"omitempty,slug,max=100"`
+}
+
+// UpdateTagRequest represents the payload for updating a tag
+type UpdateTagRequest struct {
+	Name *string `json:"name" validate:"omitempty,max=100"`
+	Slug *string `json:"slug" validate:"omitempty,slug,max=100"`
+}
+
+// TagResponse represents the tag data returned in responses
+type TagResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// ProductTagResponse represents a tag of a product
+type ProductTagResponse struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// ToTagResponse converts a Tag entity to TagResponse DTO
+func ToTagResponse(t *entities.Tag) TagResponse {
+	return TagResponse{
+		ID:        t.ID.String(),
+		Name:      t.Name,
+		Slug:      t.Slug,
+		CreatedAt: t.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: t.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToTagResponseList converts a slice of Tag entities to a slice of TagResponse DTOs
+func ToTagResponseList(tags []*entities.Tag) []TagResponse {
+	responses := make([]TagResponse, len(tags))
+	for i, t := range tags {
+		responses[i] = ToTagResponse(t)
+	}
+	return responses
+}
+
+// ToProductTagResponseList converts the tags of a product to responses
+func ToProductTagResponseList(tags []entities.Tag) []ProductTagResponse {
+	responses := make([]ProductTagResponse, len(tags))
+	for i, t := range tags {
+		responses[i] = ProductTagResponse{Name: t.Name, Slug: t.Slug}
+	}
+	return responses
+}
@@ -3,6 +3,8 @@ package dto
 import (
 	"postgresDB/internal/domain/entities"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // LoginRequest represents the payload for user login
@@ -24,6 +26,8 @@ type UpdateUserRequest struct {
 	Username *string `json:"username" validate:"omitempty,username"`
 	Email    *string `json:"email" validate:"omitempty,customEmail"`
 	IsActive *bool   `json:"is_active" validate:"omitempty"`
+	// Version is the version the change is based on, a stale one is refused with 409
+	Version *int `json:"version" validate:"omitempty,min=1"`
 }
 
 // ChangePasswordRequest represents the payload for changing user password
@@ -33,20 +37,42 @@ type ChangePasswordRequest struct {
 }
 
 // Response represents the user data returned in responses
+// RevokeTokensRequest selects the tokens to revoke in bulk. Without role and user_ids
+// every token is revoked and issued_before is required
+type RevokeTokensRequest struct {
+	// IssuedBefore defaults to now
+	IssuedBefore *time.Time  `json:"issued_before" validate:"omitempty"`
+	Role         string      `json:"role" validate:"omitempty,oneof=admin customer"`
+	UserIDs      []uuid.UUID `json:"user_ids" validate:"omitempty,max=1000"`
+}
+
+type RevokeTokensResponse struct {
+	Scope        string    `json:"scope"`
+	Role         string    `json:"role,omitempty"`
+	Users        int       `json:"users,omitempty"`
+	IssuedBefore time.Time `json:"issued_before"`
+}
+
 type UserResponse struct {
-	ID        string `json:"id"`
-	Username  string `json:"username"`
-	Email     string `json:"email"`
-	Role      string `json:"role"`
-	IsActive  bool   `json:"is_active"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID        string  `json:"id"`
+	Username  string  `json:"username"`
+	Email     string  `json:"email"`
+	Role      string  `json:"role"`
+	IsActive  bool    `json:"is_active"`
+	IsGuest   bool    `json:"is_guest,omitempty"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt string  `json:"updated_at"`
+	DeletedAt *string `json:"deleted_at,omitempty"`
+	Version   int     `json:"version"`
 }
 
 type AuthResponse struct {
-	AccessToken  string       `json:"token"`
-	RefreshToken string       `json:"refresh_token,omitempty"`
-	User         UserResponse `json:"user"`
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// SigningKey signs the requests of the session for replay protection, it stays the
+	// same across refreshes and must not be sent with a request
+	SigningKey string       `json:"signing_key,omitempty"`
+	User       UserResponse `json:"user"`
 }
 
 type RegisterResponse struct {
@@ -58,15 +84,22 @@ type RegisterResponse struct {
 
 // Func Response creates a new Response instance
 func ToUserResponse(u *entities.User) UserResponse {
-	return UserResponse{
+	resp := UserResponse{
 		ID:        u.ID.String(),
 		Username:  u.Username,
 		Email:     u.Email,
 		Role:      string(u.Role),
 		IsActive:  u.IsActive,
+		IsGuest:   u.IsGuest,
 		CreatedAt: u.CreatedAt.Format(time.RFC3339),
 		UpdatedAt: u.UpdatedAt.Format(time.RFC3339),
+		Version:   u.Version,
+	}
+	if u.DeletedAt != nil {
+		t := u.DeletedAt.Format(time.RFC3339)
+		resp.DeletedAt = &t
 	}
+	return resp
 }
 
 func ToAuthResponse(access string, refresh string, u *entities.User) AuthResponse {
@@ -0,0 +1,79 @@
+// Package v2 holds the DTOs of the /api/v2 routes. The services return v1 DTOs, the
+// mappers here reshape them, so both versions share the services
+package v2
+
+import (
+	"postgresDB/internal/domain/dto"
+	"strings"
+)
+
+// Money is an amount in the smallest unit of its currency
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// Stock is the stock of a product, Available leaves out what pending orders hold
+type Stock struct {
+	OnHand    int `json:"on_hand"`
+	Available int `json:"available"`
+}
+
+// CategoryRef is the category a product belongs to
+type CategoryRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ProductResponse is a product of v2: the price carries its currency, the stock is one
+// object and the category is null rather than an empty name
+type ProductResponse struct {
+	ID          string                         `json:"id"`
+	SKU         string                         `json:"sku,omitempty"`
+	Name        string                         `json:"name"`
+	Description string                         `json:"description"`
+	Price       Money                          `json:"price"`
+	Stock       Stock                          `json:"stock"`
+	Category    *CategoryRef                   `json:"category"`
+	WeightGrams int                            `json:"weight_grams"`
+	Images      []dto.ProductImageResponse     `json:"images"`
+	Attributes  []dto.ProductAttributeResponse `json:"attributes"`
+	Tags        []dto.ProductTagResponse       `json:"tags"`
+	CreatedAt   string                         `json:"created_at"`
+	UpdatedAt   string                         `json:"updated_at"`
+	ArchivedAt  *string                        `json:"archived_at,omitempty"`
+	Version     int                            `json:"version"`
+}
+
+// ToProductResponse maps a v1 product to v2, prices are in currency
+func ToProductResponse(p dto.ProductResponse, currency string) ProductResponse {
+	res := ProductResponse{
+		ID:          p.ID,
+		SKU:         p.SKU,
+		Name:        p.Name,
+		Description: p.Description,
+		Price:       Money{Amount: p.Price, Currency: strings.ToUpper(currency)},
+		Stock:       Stock{OnHand: p.Stock, Available: p.AvailableStock},
+		WeightGrams: p.WeightGrams,
+		Images:      p.Images,
+		Attributes:  p.Attributes,
+		Tags:        p.Tags,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+		ArchivedAt:  p.ArchivedAt,
+		Version:     p.Version,
+	}
+	if p.CategoryID != nil {
+		res.Category = &CategoryRef{ID: *p.CategoryID, Name: p.Category}
+	}
+	return res
+}
+
+// ToProductResponses maps a page of v1 products to v2
+func ToProductResponses(products []dto.ProductResponse, currency string) []ProductResponse {
+	res := make([]ProductResponse, len(products))
+	for i, p := range products {
+		res[i] = ToProductResponse(p, currency)
+	}
+	return res
+}
@@ -0,0 +1,132 @@
+package dto
+
+import (
+	"postgresDB/internal/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateWarehouseRequest represents the payload for creating a warehouse
+type CreateWarehouseRequest struct {
+	Code    string `json:"code" validate:"required,slug,max=50"`
+	Name    string `json:"name" validate:"required,max=255"`
+	Address string `json:"address" validate:"omitempty,max=500"`
+}
+
+// StockAdjustmentRequest represents the payload for correcting the stock of a warehouse
+type StockAdjustmentRequest struct {
+	Items []StockAdjustmentItem `json:"items" validate:"required,min=1,max=100,dive"`
+	Note  string                `json:"note" validate:"required,max=500"`
+}
+
+// StockAdjustmentItem is the change of a product's stock, negative to take stock out
+type StockAdjustmentItem struct {
+	ProductID uuid.UUID `json:"product_id" validate:"required"`
+	Quantity  int       `json:"quantity" validate:"required"`
+}
+
+// StockLedgerListRequest represents the query parameters for listing stock ledger entries,
+// entries are posted in [from, to)
+type StockLedgerListRequest struct {
+	WarehouseID *uuid.UUID `json:"warehouse_id" query:"warehouse_id"`
+	ProductID   *uuid.UUID `json:"product_id" query:"product_id"`
+	ReferenceID *uuid.UUID `json:"reference_id" query:"reference_id"`
+	From        time.Time  `json:"from" query:"from"`
+	To          time.Time  `json:"to" query:"to,end"`
+	PageRequest
+}
+
+// WarehouseResponse represents the warehouse data returned in responses
+type WarehouseResponse struct {
+	ID        string `json:"id"`
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	Address   string `json:"address"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// WarehouseStockResponse represents the stock of a product in a warehouse
+type WarehouseStockResponse struct {
+	ProductID   string `json:"product_id"`
+	ProductName string `json:"product_name"`
+	SKU         string `json:"sku,omitempty"`
+	Quantity    int    `json:"quantity"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// StockLedgerEntryResponse represents a warehouse stock movement
+type StockLedgerEntryResponse struct {
+	ID          string  `json:"id"`
+	WarehouseID string  `json:"warehouse_id"`
+	ProductID   string  `json:"product_id"`
+	Quantity    int     `json:"quantity"`
+	Balance     int     `json:"balance"`
+	Reason      string  `json:"reason"`
+	ReferenceID *string `json:"reference_id,omitempty"`
+	Note        string  `json:"note,omitempty"`
+	CreatedBy   *string `json:"created_by,omitempty"`
+	CreatedAt   string  `json:"created_at"`
+}
+
+// ToWarehouseResponse converts a Warehouse entity to WarehouseResponse DTO
+func ToWarehouseResponse(w *entities.Warehouse) WarehouseResponse {
+	return WarehouseResponse{
+		ID:        w.ID.String(),
+		Code:      w.Code,
+		Name:      w.Name,
+		Address:   w.Address,
+		CreatedAt: w.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: w.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToWarehouseResponseList converts Warehouse entities to WarehouseResponse DTOs
+func ToWarehouseResponseList(warehouses []*entities.Warehouse) []WarehouseResponse {
+	responses := make([]WarehouseResponse, len(warehouses))
+	for i, w := range warehouses {
+		responses[i] = ToWarehouseResponse(w)
+	}
+	return responses
+}
+
+// ToWarehouseStockResponseList converts WarehouseStock entities to WarehouseStockResponse DTOs
+func ToWarehouseStockResponseList(stock []*entities.WarehouseStock) []WarehouseStockResponse {
+	responses := make([]WarehouseStockResponse, len(stock))
+	for i, s := range stock {
+		responses[i] = WarehouseStockResponse{
+			ProductID:   s.ProductID.String(),
+			ProductName: s.ProductName,
+			SKU:         s.SKU,
+			Quantity:    s.Quantity,
+			UpdatedAt:   s.UpdatedAt.Format(time.RFC3339),
+		}
+	}
+	return responses
+}
+
+// ToStockLedgerEntryResponse converts a StockLedgerEntry entity to StockLedgerEntryResponse DTO
+func ToStockLedgerEntryResponse(e *entities.StockLedgerEntry) StockLedgerEntryResponse {
+	return StockLedgerEntryResponse{
+		ID:          e.ID.String(),
+		WarehouseID: e.WarehouseID.String(),
+		ProductID:   e.ProductID.String(),
+		Quantity:    e.Quantity,
+		Balance:     e.Balance,
+		Reason:      string(e.Reason),
+		ReferenceID: uuidString(e.ReferenceID),
+		Note:        e.Note,
+		CreatedBy:   uuidString(e.CreatedBy),
+		CreatedAt:   e.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToStockLedgerEntryResponseList converts StockLedgerEntry entities to StockLedgerEntryResponse DTOs
+func ToStockLedgerEntryResponseList(entries []*entities.StockLedgerEntry) []StockLedgerEntryResponse {
+	responses := make([]StockLedgerEntryResponse, len(entries))
+	for i, e := range entries {
+		responses[i] = ToStockLedgerEntryResponse(e)
+	}
+	return responses
+}
@@ -0,0 +1,116 @@
+package dto
+
+import (
+	"encoding/json"
+	"postgresDB/internal/domain/entities"
+	"time"
+)
+
+// CreateWebhookRequest represents the payload for registering a webhook endpoint
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" validate:"required,max=2000"`
+	Description string   `json:"description" validate:"max=255"`
+	Events      []string `json:"events" validate:"required,min=1,max=10,dive,required"`
+}
+
+// UpdateWebhookRequest represents the payload for updating a webhook endpoint, omitted
+// fields are kept
+type UpdateWebhookRequest struct {
+	URL         *string  `json:"url" validate:"omitempty,max=2000"`
+	Description *string  `json:"description" validate:"omitempty,max=255"`
+	Events      []string `json:"events" validate:"omitempty,min=1,max=10,dive,required"`
+	Active      *bool    `json:"active"`
+}
+
+// WebhookResponse represents a webhook endpoint returned in responses
+type WebhookResponse struct {
+	ID          string   `json:"id"`
+	URL         string   `json:"url"`
+	Description string   `json:"description"`
+	Events      []string `json:"events"`
+	Active      bool     `json:"active"`
+	// Secret is only returned when the endpoint is registered
+	Secret    string `json:"secret,omitempty"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// WebhookDeliveryResponse represents a delivery attempt in the delivery log
+type WebhookDeliveryResponse struct {
+	ID        string          `json:"id"`
+	EventID   string          `json:"event_id"`
+	EventType string          `json:"event_type"`
+	Attempt   int             `json:"attempt"`
+	Payload   json.RawMessage `json:"payload"`
+	// StatusCode is omitted when the endpoint didn't answer
+	StatusCode   *int   `json:"status_code,omitempty"`
+	ResponseBody string `json:"response_body,omitempty"`
+	Error        string `json:"error,omitempty"`
+	DurationMs   int    `json:"duration_ms"`
+	Succeeded    bool   `json:"succeeded"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// WebhookEvent represents the body posted to webhook endpoints
+type WebhookEvent struct {
+	// ID is the same for every attempt of the event, receivers use it to skip duplicates
+	ID        string           `json:"id"`
+	Type      string           `json:"type"`
+	CreatedAt string           `json:"created_at"`
+	Data      WebhookOrderData `json:"data"`
+}
+
+// WebhookOrderData represents the order an event is about
+type WebhookOrderData struct {
+	Order OrderResponse `json:"order"`
+	// PreviousStatus is set on order.status_changed events
+	PreviousStatus string `json:"previous_status,omitempty"`
+}
+
+// ToWebhookResponse converts a WebhookEndpoint entity to WebhookResponse DTO
+func ToWebhookResponse(e *entities.WebhookEndpoint) WebhookResponse {
+	return WebhookResponse{
+		ID:          e.ID.String(),
+		URL:         e.URL,
+		Description: e.Description,
+		Events:      e.Events,
+		Active:      e.Active,
+		CreatedAt:   e.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   e.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToWebhookResponseList converts WebhookEndpoint entities to WebhookResponse DTOs
+func ToWebhookResponseList(endpoints []*entities.WebhookEndpoint) []WebhookResponse {
+	responses := make([]WebhookResponse, len(endpoints))
+	for i, e := range endpoints {
+		responses[i] = ToWebhookResponse(e)
+	}
+	return responses
+}
+
+// ToWebhookDeliveryResponse converts a WebhookDelivery entity to WebhookDeliveryResponse DTO
+func ToWebhookDeliveryResponse(d *entities.WebhookDelivery) WebhookDeliveryResponse {
+	return WebhookDeliveryResponse{
+		ID:           d.ID.String(),
+		EventID:      d.EventID.String(),
+		EventType:    d.EventType,
+		Attempt:      d.Attempt,
+		Payload:      json.RawMessage(d.Payload),
+		StatusCode:   d.StatusCode,
+		ResponseBody: d.ResponseBody,
+		Error:        d.Error,
+		DurationMs:   d.DurationMs,
+		Succeeded:    d.Succeeded,
+		CreatedAt:    d.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToWebhookDeliveryResponseList converts WebhookDelivery entities to WebhookDeliveryResponse DTOs
+func ToWebhookDeliveryResponseList(deliveries []*entities.WebhookDelivery) []WebhookDeliveryResponse {
+	responses := make([]WebhookDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		responses[i] = ToWebhookDeliveryResponse(d)
+	}
+	return responses
+}
@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccessLog is a recorded API request
+type AccessLog struct {
+	ID         int64
+	At         time.Time
+	Method     string
+	Path       string
+	Status     int
+	DurationMs float64
+	Size       int64
+	IP         string
+	// ClientID is the X-Client-ID header sent by API integrations
+	ClientID string
+	// UserID is set for authenticated requests
+	UserID    *uuid.UUID
+	UserAgent string
+}
+
+// AccessLogExportStatus is the state of an access log export
+type AccessLogExportStatus string
+
+const (
+	AccessLogExportPending   AccessLogExportStatus = "pending"
+	AccessLogExportRunning   AccessLogExportStatus = "running"
+	AccessLogExportCompleted AccessLogExportStatus = "completed"
+	AccessLogExportFailed    AccessLogExportStatus = "failed"
+)
+
+// AccessLogExport is a requested export of the access logs of a client and/or user
+// over a time range, written to a file by a background job
+type AccessLogExport struct {
+	ID          uuid.UUID
+	RequestedBy uuid.UUID
+	// ClientID and UserID narrow the export, empty/nil matches any
+	ClientID    string
+	UserID      *uuid.UUID
+	From        time.Time
+	To          time.Time
+	Format      string
+	Status      AccessLogExportStatus
+	RowCount    int64
+	Size        int64
+	StorageKey  string
+	Error       string
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+}
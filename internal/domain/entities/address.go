@@ -0,0 +1,67 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Address is an entry of a user's address book
+type Address struct {
+	ID            uuid.UUID `db:"id"`
+	UserID        uuid.UUID `db:"user_id"`
+	Label         string    `db:"label"`
+	RecipientName string    `db:"recipient_name"`
+	Phone         string    `db:"phone"`
+	Street        string    `db:"street"`
+	City          string    `db:"city"`
+	Province      string    `db:"province"`
+	PostalCode    string    `db:"postal_code"`
+	Country       string    `db:"country"`
+	// IsDefault marks the address orders ship to when none is chosen
+	IsDefault bool      `db:"is_default"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// ShippingAddress is the copy of an address kept on an order, so editing or removing
+// the address later doesn't change where placed orders go
+type ShippingAddress struct {
+	RecipientName string `json:"recipient_name"`
+	Phone         string `json:"phone"`
+	Street        string `json:"street"`
+	City          string `json:"city"`
+	Province      string `json:"province"`
+	PostalCode    string `json:"postal_code"`
+	Country       string `json:"country"`
+}
+
+// Snapshot copies the address for an order
+func (a *Address) Snapshot() *ShippingAddress {
+	return &ShippingAddress{
+		RecipientName: a.RecipientName,
+		Phone:         a.Phone,
+		Street:        a.Street,
+		City:          a.City,
+		Province:      a.Province,
+		PostalCode:    a.PostalCode,
+		Country:       a.Country,
+	}
+}
+
+// Shipping cost calculators
+const (
+	// ShippingCalculatorFlat charges the same cost for every order
+	ShippingCalculatorFlat = "flat"
+	// ShippingCalculatorWeight charges a base cost plus a cost per started kilogram
+	ShippingCalculatorWeight = "weight"
+)
+
+// ShippingMethod is a way of shipping orders offered at checkout
+type ShippingMethod struct {
+	Code       string
+	Calculator string
+	// BaseCost is the flat cost, or the cost before weight of weight-based methods
+	BaseCost  int64
+	CostPerKg int64
+}
@@ -0,0 +1,55 @@
+package entities
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scopes an API client can be granted
+const (
+	// ScopeOpenID asks for an ID token when signing in with this API
+	ScopeOpenID       = "openid"
+	ScopeProfile      = "profile"
+	ScopeEmail        = "email"
+	ScopeProductsRead = "products:read"
+	ScopeOrdersRead   = "orders:read"
+	ScopeOrdersWrite  = "orders:write"
+)
+
+var apiClientScopes = []string{ScopeOpenID, ScopeProfile, ScopeEmail, ScopeProductsRead, ScopeOrdersRead, ScopeOrdersWrite}
+
+// IsValidScope checks if scope is a known API client scope
+func IsValidScope(scope string) bool {
+	return slices.Contains(apiClientScopes, scope)
+}
+
+// APIClientScopes returns every scope an API client can be granted
+func APIClientScopes() []string {
+	return slices.Clone(apiClientScopes)
+}
+
+// APIClient is an integration registered by a user. ClientID is public and is sent as
+// the X-Client-ID header, the secret is only kept as a hash
+type APIClient struct {
+	ID           uuid.UUID `db:"id"`
+	OwnerID      uuid.UUID `db:"owner_id"`
+	ClientID     string    `db:"client_id"`
+	Name         string    `db:"name"`
+	SecretHash   string    `db:"secret_hash"`
+	RedirectURIs []string  `db:"redirect_uris"`
+	Scopes       []string  `db:"scopes"`
+	// SecretRotatedAt is when the current secret was issued
+	SecretRotatedAt time.Time `db:"secret_rotated_at"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+// APIClientUsage is the traffic of an API client on a single day
+type APIClientUsage struct {
+	Day      time.Time
+	Requests int64
+	// Errors counts responses with a 4xx or 5xx status
+	Errors int64
+}
@@ -0,0 +1,76 @@
+package entities
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AttributeType defines the value type of a product attribute
+type AttributeType string
+
+const (
+	AttributeTypeString  AttributeType = "string"
+	AttributeTypeNumber  AttributeType = "number"
+	AttributeTypeBoolean AttributeType = "boolean"
+)
+
+// IsValid checks if the attribute type is valid
+func (t AttributeType) IsValid() bool {
+	switch t {
+	case AttributeTypeString, AttributeTypeNumber, AttributeTypeBoolean:
+		return true
+	default:
+		return false
+	}
+}
+
+// Normalize validates a raw value for the type and returns its canonical form,
+// so equal values compare equal when filtering (e.g. "10.0" and "10")
+func (t AttributeType) Normalize(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	switch t {
+	case AttributeTypeNumber:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", false
+		}
+		return strconv.FormatFloat(f, 'f', -1, 64), true
+	case AttributeTypeBoolean:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", false
+		}
+		return strconv.FormatBool(b), true
+	case AttributeTypeString:
+		return value, value != ""
+	default:
+		return "", false
+	}
+}
+
+// AttributeDefinition describes a specification attribute available to products of a category
+type AttributeDefinition struct {
+	ID         uuid.UUID     `db:"id"`
+	CategoryID uuid.UUID     `db:"category_id"`
+	Code       string        `db:"code"`
+	Name       string        `db:"name"`
+	Type       AttributeType `db:"type"`
+	Unit       string        `db:"unit"`
+	Filterable bool          `db:"filterable"`
+	CreatedAt  time.Time     `db:"created_at"`
+	UpdatedAt  time.Time     `db:"updated_at"`
+}
+
+// ProductAttribute is the value of an attribute for a product
+type ProductAttribute struct {
+	ProductID   uuid.UUID     `db:"product_id"`
+	AttributeID uuid.UUID     `db:"attribute_id"`
+	Code        string        `db:"code"`
+	Name        string        `db:"name"`
+	Type        AttributeType `db:"type"`
+	Unit        string        `db:"unit"`
+	Value       string        `db:"value"`
+}
@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of auth state kept in Redis
+const (
+	// AuthStateBlacklist is a revoked access token, ID is its JTI
+	AuthStateBlacklist = "blacklist"
+	// AuthStateFamily is the current JTI of a refresh token family, ID is <user id>:<family>
+	AuthStateFamily = "family"
+	// AuthStateSessions is the set of token families of a user, ID is the user id
+	AuthStateSessions = "sessions"
+	// AuthStateRevokedBefore is the unix time before which tokens of a scope are revoked,
+	// ID is the scope
+	AuthStateRevokedBefore = "revoked_before"
+)
+
+// AuthStateEntry is a single Redis auth state key, as written to a backup
+type AuthStateEntry struct {
+	Kind  string `json:"kind"`
+	ID    string `json:"id"`
+	Value string `json:"value,omitempty"`
+	// Sessions maps token families to the unix time their session expires
+	Sessions map[string]int64 `json:"sessions,omitempty"`
+	// ExpiresAt is nil for keys without a TTL
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Auth state backup triggers
+const (
+	AuthStateTriggerManual    = "manual"
+	AuthStateTriggerScheduled = "scheduled"
+)
+
+// AuthStateBackupStatus is the state of an auth state backup
+type AuthStateBackupStatus string
+
+const (
+	AuthStateBackupCompleted AuthStateBackupStatus = "completed"
+	AuthStateBackupFailed    AuthStateBackupStatus = "failed"
+	// AuthStateBackupExpired backups are past retention, their file is removed
+	AuthStateBackupExpired AuthStateBackupStatus = "expired"
+)
+
+// AuthStateBackup records a backup of the Redis auth state to storage
+type AuthStateBackup struct {
+	ID      uuid.UUID
+	Trigger string
+	// RequestedBy is nil for scheduled backups
+	RequestedBy *uuid.UUID
+	Status      AuthStateBackupStatus
+	Blacklisted int
+	Families    int
+	Sessions    int
+	Size        int64
+	StorageKey  string
+	Error       string
+	CreatedAt   time.Time
+}
+
+// AuthStateRestore records a restore of the Redis auth state from a backup
+type AuthStateRestore struct {
+	ID       uuid.UUID
+	BackupID uuid.UUID
+	// RestoredBy is nil once the admin is deleted
+	RestoredBy *uuid.UUID
+	Restored   int
+	// Skipped counts entries that expired since the backup or were already newer in Redis
+	Skipped   int
+	CreatedAt time.Time
+}
@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Snapshot triggers
+const (
+	SnapshotTriggerScheduled   = "scheduled"
+	SnapshotTriggerManual      = "manual"
+	SnapshotTriggerPreRollback = "pre_rollback"
+)
+
+// CatalogSnapshot is a point-in-time copy of the catalog prices and stock
+type CatalogSnapshot struct {
+	ID           uuid.UUID      `db:"id"`
+	Trigger      string         `db:"trigger"`
+	ProductCount int            `db:"product_count"`
+	Items        []SnapshotItem `db:"data"`
+	CreatedAt    time.Time      `db:"created_at"`
+}
+
+// SnapshotItem is the state of a single product in a snapshot. The short JSON
+// keys keep the stored snapshot small.
+type SnapshotItem struct {
+	ProductID uuid.UUID `json:"i"`
+	Name      string    `json:"n"`
+	// Price is in minor units
+	Price int64 `json:"m"`
+	Stock int   `json:"s"`
+}
+
+// UnmarshalJSON also reads snapshots taken before prices were kept in minor units,
+// which hold a decimal price under "p"
+func (s *SnapshotItem) UnmarshalJSON(data []byte) error {
+	type item SnapshotItem
+	var v struct {
+		item
+		LegacyPrice *float64 `json:"p"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = SnapshotItem(v.item)
+	if v.LegacyPrice != nil {
+		s.Price = int64(math.Round(*v.LegacyPrice * MinorUnits))
+	}
+	return nil
+}
+
+// Snapshot diff change types
+const (
+	SnapshotChangeAdded   = "added"
+	SnapshotChangeRemoved = "removed"
+	SnapshotChangeUpdated = "updated"
+)
+
+// SnapshotDiff describes how a product differs between two snapshots
+type SnapshotDiff struct {
+	ProductID uuid.UUID
+	Name      string
+	Change    string
+	Before    *SnapshotItem
+	After     *SnapshotItem
+}
+
+// DiffSnapshotItems compares two sets of snapshot items, ordered by product name
+func DiffSnapshotItems(from, to []SnapshotItem) []SnapshotDiff {
+	before := make(map[uuid.UUID]SnapshotItem, len(from))
+	for _, item := range from {
+		before[item.ProductID] = item
+	}
+
+	diffs := make([]SnapshotDiff, 0)
+	seen := make(map[uuid.UUID]bool, len(to))
+	for _, item := range to {
+		after := item
+		seen[item.ProductID] = true
+		old, ok := before[item.ProductID]
+		switch {
+		case !ok:
+			diffs = append(diffs, SnapshotDiff{ProductID: item.ProductID, Name: item.Name, Change: SnapshotChangeAdded, After: &after})
+		case old.Price != item.Price || old.Stock != item.Stock || old.Name != item.Name:
+			diffs = append(diffs, SnapshotDiff{ProductID: item.ProductID, Name: item.Name, Change: SnapshotChangeUpdated, Before: &old, After: &after})
+		}
+	}
+	for _, item := range from {
+		if !seen[item.ProductID] {
+			old := item
+			diffs = append(diffs, SnapshotDiff{ProductID: item.ProductID, Name: item.Name, Change: SnapshotChangeRemoved, Before: &old})
+		}
+	}
+
+	sort.SliceStable(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+	return diffs
+}
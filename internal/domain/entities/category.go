@@ -0,0 +1,18 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Category represents a product category; categories form a tree via ParentID
+type Category struct {
+	ID          uuid.UUID  `db:"id"`
+	ParentID    *uuid.UUID `db:"parent_id"`
+	Name        string     `db:"name"`
+	Slug        string     `db:"slug"`
+	Description string     `db:"description"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+}
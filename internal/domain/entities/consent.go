@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConsentPurpose is what a user can agree to their data being used for
+type ConsentPurpose string
+
+const (
+	ConsentMarketingEmail ConsentPurpose = "marketing_email"
+	ConsentMarketingSMS   ConsentPurpose = "marketing_sms"
+	// ConsentAnalytics covers using the order history of a user in statistics such as
+	// the co-purchase recommendations
+	ConsentAnalytics ConsentPurpose = "analytics"
+)
+
+// ConsentPurposes lists every purpose, in the order they are shown
+var ConsentPurposes = []ConsentPurpose{ConsentMarketingEmail, ConsentMarketingSMS, ConsentAnalytics}
+
+// IsValid checks if the consent purpose is known
+func (p ConsentPurpose) IsValid() bool {
+	for _, purpose := range ConsentPurposes {
+		if p == purpose {
+			return true
+		}
+	}
+	return false
+}
+
+// Default is the consent of a user who never chose: marketing is opt-in, analytics opt-out
+func (p ConsentPurpose) Default() bool {
+	return p == ConsentAnalytics
+}
+
+// Consent is the current choice of a user for a purpose
+type Consent struct {
+	UserID  uuid.UUID      `db:"user_id"`
+	Purpose ConsentPurpose `db:"purpose"`
+	Granted bool           `db:"granted"`
+	// UpdatedAt is nil while the user never chose and Granted is the default
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+// ConsentRecord is a consent choice as it was made, kept for compliance
+type ConsentRecord struct {
+	ID        uuid.UUID      `db:"id"`
+	UserID    uuid.UUID      `db:"user_id"`
+	Purpose   ConsentPurpose `db:"purpose"`
+	Granted   bool           `db:"granted"`
+	IP        string         `db:"ip"`
+	UserAgent string         `db:"user_agent"`
+	CreatedAt time.Time      `db:"created_at"`
+}
@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailTemplateStatus is the publication state of an email template version
+type EmailTemplateStatus string
+
+const (
+	// EmailTemplateDraft is a version that can be previewed but is not sent yet
+	EmailTemplateDraft EmailTemplateStatus = "draft"
+	// EmailTemplateActive is the version sent for its key and locale
+	EmailTemplateActive EmailTemplateStatus = "active"
+	// EmailTemplateArchived is a version replaced by a newer one
+	EmailTemplateArchived EmailTemplateStatus = "archived"
+)
+
+// EmailTemplate is one version of a notification email in one locale. Subject and
+// TextBody are text/template sources, HTMLBody is an html/template source
+type EmailTemplate struct {
+	ID          uuid.UUID           `db:"id"`
+	Key         string              `db:"key"`
+	Locale      string              `db:"locale"`
+	Version     int                 `db:"version"`
+	Subject     string              `db:"subject"`
+	HTMLBody    string              `db:"html_body"`
+	TextBody    string              `db:"text_body"`
+	Status      EmailTemplateStatus `db:"status"`
+	CreatedBy   *uuid.UUID          `db:"created_by"`
+	PublishedAt *time.Time          `db:"published_at"`
+	CreatedAt   time.Time           `db:"created_at"`
+	UpdatedAt   time.Time           `db:"updated_at"`
+}
@@ -0,0 +1,37 @@
+package entities
+
+import "time"
+
+// FieldDirection tells whether a field is sent by clients or read by them
+type FieldDirection string
+
+const (
+	// FieldSent is a field of a request body
+	FieldSent FieldDirection = "request"
+	// FieldRead is a response field a client reports reading with X-Fields-Read
+	FieldRead FieldDirection = "response"
+)
+
+// FieldUsage counts the sampled requests of a client using a field of a route
+type FieldUsage struct {
+	// Route is the pattern of the route, e.g. "POST /api/v1/orders"
+	Route     string
+	Direction FieldDirection
+	// Field is the path of the field, nested objects are joined with "." and array
+	// elements marked with "[]", e.g. "items[].product_id"
+	Field     string
+	ClientID  string
+	Count     int64
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// FieldUsageFilter selects field usage; empty values match everything
+type FieldUsageFilter struct {
+	Route string
+	// Field matches the field and the fields nested in it
+	Field    string
+	ClientID *string
+	// Since keeps the usage seen at or after it
+	Since *time.Time
+}
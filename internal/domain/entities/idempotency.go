@@ -0,0 +1,18 @@
+package entities
+
+// IdempotentRequest is a request sent with an Idempotency-Key and, once it completed,
+// its response to replay to retries
+type IdempotentRequest struct {
+	// Fingerprint hashes the method, URI and body so a key can't be reused for another request
+	Fingerprint string `json:"fingerprint"`
+	// Status is 0 while the request is running
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	Location    string `json:"location,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Completed reports whether the response of the request is stored
+func (r *IdempotentRequest) Completed() bool {
+	return r.Status != 0
+}
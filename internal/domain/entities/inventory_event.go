@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockEventTopic is the event bus topic stock changes are published on
+const StockEventTopic = "inventory.stock"
+
+// StockEvent is a change of a product's stock level
+type StockEvent struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Stock     int       `json:"stock"`
+	// Previous is nil when the write didn't read the stock it replaced, like admin edits
+	Previous   *int      `json:"previous,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
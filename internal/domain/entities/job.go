@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobStatus is the state of a background job
+type JobStatus string
+
+const (
+	// JobStatusQueued is waiting in its queue, possibly delayed until RunAt
+	JobStatusQueued JobStatus = "queued"
+	// JobStatusRunning is being handled by a worker
+	JobStatusRunning JobStatus = "running"
+	// JobStatusSucceeded finished without error
+	JobStatusSucceeded JobStatus = "succeeded"
+	// JobStatusFailed used up its attempts and waits for an admin to retry or cancel it
+	JobStatusFailed JobStatus = "failed"
+	// JobStatusCancelled was cancelled by an admin and won't run
+	JobStatusCancelled JobStatus = "cancelled"
+)
+
+// Job is a unit of background work handled by the handler registered for its Type
+type Job struct {
+	ID          uuid.UUID
+	Queue       string
+	Type        string
+	Payload     json.RawMessage
+	Status      JobStatus
+	Attempts    int
+	MaxAttempts int
+	LastError   string
+	RunAt       time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+}
+
+// QueueStats summarizes a queue: its current backlog and the jobs finished in the window
+type QueueStats struct {
+	Queue     string
+	Ready     int64
+	Scheduled int64
+	Running   int64
+	Failed    int64
+	Window    time.Duration
+	Succeeded int64
+	// Errored counts failed attempts, including those that were retried
+	Errored int64
+	// TotalWait is the summed time from RunAt until a worker picked the job up
+	TotalWait time.Duration
+	// TotalRun is the summed handler time
+	TotalRun time.Duration
+}
@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Amounts of money (prices, order totals, payments) are int64 minor units, e.g. cents,
+// so sums and comparisons are exact
+
+// MinorUnits is the number of minor units in one unit of the currency
+const MinorUnits = 100
+
+// minorUnitDigits is the number of decimals of an amount written in units
+const minorUnitDigits = 2
+
+// ErrInvalidAmount is returned for an amount that is not a decimal number with at
+// most two decimals
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// ParseAmount parses an amount written in units, such as "12500" or "12500.50", into
+// minor units without going through floating point
+func ParseAmount(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" || len(frac) > minorUnitDigits || strings.ContainsAny(whole+frac, "+-") {
+		return 0, ErrInvalidAmount
+	}
+	frac += strings.Repeat("0", minorUnitDigits-len(frac))
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/MinorUnits-1 {
+		return 0, ErrInvalidAmount
+	}
+	cents, err := strconv.ParseInt(frac, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidAmount
+	}
+
+	amount := units*MinorUnits + cents
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// FormatAmount writes an amount in minor units as units with two decimals
+func FormatAmount(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	cents := strconv.FormatInt(amount%MinorUnits, 10)
+	if len(cents) < minorUnitDigits {
+		cents = "0" + cents
+	}
+	return sign + strconv.FormatInt(amount/MinorUnits, 10) + "." + cents
+}
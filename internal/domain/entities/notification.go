@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationKind is what a notification is about
+type NotificationKind string
+
+const (
+	NotificationOrderConfirmation NotificationKind = "order_confirmation"
+	NotificationOrderStatus       NotificationKind = "order_status"
+	// NotificationPasswordReset carries a password reset link, it can't be turned off
+	NotificationPasswordReset NotificationKind = "password_reset"
+)
+
+// NotificationKinds lists every kind, in the order they are shown
+var NotificationKinds = []NotificationKind{NotificationOrderConfirmation, NotificationOrderStatus, NotificationPasswordReset}
+
+// IsValid checks if the notification kind is known
+func (k NotificationKind) IsValid() bool {
+	return slices.Contains(NotificationKinds, k)
+}
+
+// Required reports whether users receive the kind whatever their preferences, because
+// they asked for it themselves
+func (k NotificationKind) Required() bool {
+	return k == NotificationPasswordReset
+}
+
+// NotificationPreference is whether a user receives a kind of notification on a channel.
+// Notifications are on until the user turns them off
+type NotificationPreference struct {
+	UserID  uuid.UUID        `db:"user_id"`
+	Kind    NotificationKind `db:"kind"`
+	Channel string           `db:"channel"`
+	Enabled bool             `db:"enabled"`
+	// UpdatedAt is nil while the user never chose and the notification is on
+	UpdatedAt *time.Time `db:"updated_at"`
+}
@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuthorizationCode is the grant behind an OAuth authorization code, kept until the
+// client redeems the code at the token endpoint. Only the hash of the code is stored
+type AuthorizationCode struct {
+	ClientID    string    `json:"client_id"`
+	UserID      uuid.UUID `json:"user_id"`
+	RedirectURI string    `json:"redirect_uri"`
+	Scopes      []string  `json:"scopes"`
+	// CodeChallenge is the S256 PKCE challenge the code verifier must match
+	CodeChallenge string    `json:"code_challenge"`
+	Nonce         string    `json:"nonce,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
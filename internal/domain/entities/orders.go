@@ -1,6 +1,7 @@
 package entities
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,21 +10,57 @@ import (
 type OrderStatus string
 
 const (
-	OrderStatusPending   OrderStatus = "pending"
-	OrderStatusPaid      OrderStatus = "paid"
-	OrderStatusShipped   OrderStatus = "shipped"
+	OrderStatusPending OrderStatus = "pending"
+	// OrderStatusPendingCapture waits for an asynchronous payment provider to settle
+	OrderStatusPendingCapture OrderStatus = "pending_capture"
+	OrderStatusPaid           OrderStatus = "paid"
+	// OrderStatusProcessing is a paid order being picked and packed
+	OrderStatusProcessing OrderStatus = "processing"
+	OrderStatusShipped    OrderStatus = "shipped"
+	// OrderStatusDelivered is a shipped order the customer received
+	OrderStatusDelivered OrderStatus = "delivered"
 	OrderStatusCompleted OrderStatus = "completed"
 	OrderStatusCancelled OrderStatus = "cancelled"
+	// OrderStatusRefunded is a paid order whose payment was returned
+	OrderStatusRefunded OrderStatus = "refunded"
 )
 
+var (
+	// OpenOrderStatuses are the statuses an order can still move on from
+	OpenOrderStatuses = []OrderStatus{OrderStatusPending, OrderStatusPendingCapture, OrderStatusPaid, OrderStatusProcessing, OrderStatusShipped, OrderStatusDelivered}
+	// SoldOrderStatuses are the statuses of orders counted as sales
+	SoldOrderStatuses = []OrderStatus{OrderStatusPaid, OrderStatusProcessing, OrderStatusShipped, OrderStatusDelivered, OrderStatusCompleted}
+)
+
+// orderTransitions is the order lifecycle, the statuses each status can move to
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:        {OrderStatusPendingCapture, OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPendingCapture: {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:           {OrderStatusProcessing, OrderStatusShipped, OrderStatusCancelled, OrderStatusRefunded},
+	OrderStatusProcessing:     {OrderStatusShipped, OrderStatusCancelled, OrderStatusRefunded},
+	OrderStatusShipped:        {OrderStatusDelivered, OrderStatusCompleted, OrderStatusRefunded},
+	OrderStatusDelivered:      {OrderStatusCompleted, OrderStatusRefunded},
+	OrderStatusCompleted:      {OrderStatusRefunded},
+	OrderStatusCancelled:      {},
+	OrderStatusRefunded:       {},
+}
+
+// customerTransitions are the changes customers may make to their own orders: cancelling
+// an unpaid order and confirming delivery. Every other change is made by admins
+var customerTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending: {OrderStatusCancelled},
+	OrderStatusShipped: {OrderStatusDelivered},
+}
+
 // IsValid checks if the order status is valid
 func (s OrderStatus) IsValid() bool {
-	switch s {
-	case OrderStatusPending, OrderStatusPaid, OrderStatusShipped, OrderStatusCompleted, OrderStatusCancelled:
-		return true
-	default:
-		return false
-	}
+	_, ok := orderTransitions[s]
+	return ok
+}
+
+// IsClosed reports whether the order can't move on anymore, except to a refund
+func (s OrderStatus) IsClosed() bool {
+	return !slices.Contains(OpenOrderStatuses, s)
 }
 
 // String returns the string representation of OrderStatus
@@ -33,35 +70,63 @@ func (s OrderStatus) String() string {
 
 // CanTransitionTo checks if status can transition to the target status
 func (s OrderStatus) CanTransitionTo(target OrderStatus) bool {
-	transitions := map[OrderStatus][]OrderStatus{
-		OrderStatusPending:   {OrderStatusPaid, OrderStatusCancelled},
-		OrderStatusPaid:      {OrderStatusShipped, OrderStatusCancelled},
-		OrderStatusShipped:   {OrderStatusCompleted},
-		OrderStatusCompleted: {},
-		OrderStatusCancelled: {},
-	}
+	return slices.Contains(orderTransitions[s], target)
+}
 
-	allowedTransitions, exists := transitions[s]
-	if !exists {
+// CanTransitionAs checks if a user with role may move an order from status to target;
+// customers only on their own orders
+func (s OrderStatus) CanTransitionAs(target OrderStatus, role Role) bool {
+	if !s.CanTransitionTo(target) {
 		return false
 	}
-
-	for _, allowed := range allowedTransitions {
-		if allowed == target {
-			return true
-		}
-	}
-	return false
+	return role == RoleAdmin || slices.Contains(customerTransitions[s], target)
 }
 
 type Order struct {
-	ID          uuid.UUID   `db:"id"`
-	CustomerID  uuid.UUID   `db:"customer_id"`
-	Status      OrderStatus `db:"status"`
-	TotalAmount float64     `db:"total_amount"`
-	Items       []OrderItem `db:"items"`
-	CreatedAt   time.Time   `db:"created_at"`
-	UpdatedAt   time.Time   `db:"updated_at"`
+	ID         uuid.UUID   `db:"id"`
+	CustomerID uuid.UUID   `db:"customer_id"`
+	Status     OrderStatus `db:"status"`
+	// TotalAmount is Subtotal + TaxAmount + ShippingCost
+	TotalAmount int64 `db:"total_amount"`
+	// Subtotal is the sum of the items before tax
+	Subtotal  int64       `db:"subtotal"`
+	TaxAmount int64       `db:"tax_amount"`
+	TaxLines  []TaxLine   `db:"tax_lines"`
+	Items     []OrderItem `db:"items"`
+	// Carrier and TrackingNumber are the latest shipment's, set once the order is shipped
+	Carrier        string `db:"carrier"`
+	TrackingNumber string `db:"tracking_number"`
+	// Shipments are loaded along with the items, oldest first
+	Shipments []Shipment `db:"shipments"`
+	// ShippingAddress is a copy of the address the order ships to, nil for orders
+	// placed without one
+	ShippingAddress *ShippingAddress `db:"shipping_address"`
+	ShippingMethod  string           `db:"shipping_method"`
+	// ShippingCost is part of TotalAmount
+	ShippingCost int64     `db:"shipping_cost"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+// CustomerOrderStats aggregates the sold orders of a customer, see SoldOrderStatuses
+type CustomerOrderStats struct {
+	Orders int64
+	// TotalSpent is the sum of the order totals, in minor units
+	TotalSpent int64
+	// LastOrderAt is nil for customers without a sold order
+	LastOrderAt *time.Time
+	// FavoriteCategories are the categories the customer bought the most units of
+	FavoriteCategories []CategoryPurchases
+}
+
+// CategoryPurchases is what a customer bought of a category
+type CategoryPurchases struct {
+	// CategoryID is nil for products with only a free-text category
+	CategoryID *uuid.UUID
+	Category   string
+	Quantity   int64
+	// Spent is the sum of the order item subtotals, in minor units
+	Spent int64
 }
 
 type OrderItem struct {
@@ -69,7 +134,27 @@ type OrderItem struct {
 	OrderID   uuid.UUID `db:"order_id"`
 	ProductID uuid.UUID `db:"product_id"`
 	Quantity  int       `db:"quantity"`
-	UnitPrice float64   `db:"unit_price"`
-	SubTotal  float64   `db:"subtotal"`
+	UnitPrice int64     `db:"unit_price"`
+	SubTotal  int64     `db:"subtotal"`
+	// TaxAmount is the tax charged on SubTotal
+	TaxAmount int64     `db:"tax_amount"`
 	CreatedAt time.Time `db:"created_at"`
 }
+
+// OrderStatusEvent records a status the order moved into
+type OrderStatusEvent struct {
+	ID      uuid.UUID   `db:"id"`
+	OrderID uuid.UUID   `db:"order_id"`
+	Status  OrderStatus `db:"status"`
+	// Note explains the event, e.g. a payment capture retry
+	Note      string    `db:"note"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// TrackingToken grants public, read-only access to an order's tracking page
+type TrackingToken struct {
+	ID        uuid.UUID  `db:"id"`
+	OrderID   uuid.UUID  `db:"order_id"`
+	CreatedAt time.Time  `db:"created_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+}
@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Aggregates outbox events are about
+const (
+	OutboxAggregateOrder = "order"
+	OutboxAggregateUser  = "user"
+)
+
+// Outbox event types
+const (
+	OutboxEventOrderCreated       = "order.created"
+	OutboxEventOrderStatusChanged = "order.status_changed"
+	OutboxEventUserRegistered     = "user.registered"
+	OutboxEventUserUpdated        = "user.updated"
+	OutboxEventUserDeleted        = "user.deleted"
+)
+
+// OutboxEvent is a change to publish to the message broker. It's saved in the same
+// transaction as the change, so an event exists if and only if the change was committed
+type OutboxEvent struct {
+	ID uuid.UUID `db:"id"`
+	// Position orders the events, they are published in this order
+	Position      int64     `db:"position"`
+	AggregateType string    `db:"aggregate_type"`
+	AggregateID   uuid.UUID `db:"aggregate_id"`
+	EventType     string    `db:"event_type"`
+	// Payload is the JSON encoded event data, one of the *Event types below
+	Payload     []byte     `db:"payload"`
+	CreatedAt   time.Time  `db:"created_at"`
+	PublishedAt *time.Time `db:"published_at"`
+	// Attempts and LastError are the failed attempts to publish the event
+	Attempts  int    `db:"attempts"`
+	LastError string `db:"last_error"`
+}
+
+// OrderCreatedEvent is the data of an order.created event
+type OrderCreatedEvent struct {
+	OrderID      uuid.UUID              `json:"order_id"`
+	CustomerID   uuid.UUID              `json:"customer_id"`
+	Status       OrderStatus            `json:"status"`
+	Subtotal     int64                  `json:"subtotal"`
+	TaxAmount    int64                  `json:"tax_amount"`
+	ShippingCost int64                  `json:"shipping_cost"`
+	TotalAmount  int64                  `json:"total_amount"`
+	Items        []OrderCreatedItemData `json:"items"`
+	CreatedAt    time.Time              `json:"created_at"`
+}
+
+// OrderCreatedItemData is an item of an order.created event
+type OrderCreatedItemData struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Quantity  int       `json:"quantity"`
+	UnitPrice int64     `json:"unit_price"`
+	Subtotal  int64     `json:"subtotal"`
+}
+
+// OrderStatusChangedEvent is the data of an order.status_changed event
+type OrderStatusChangedEvent struct {
+	OrderID        uuid.UUID   `json:"order_id"`
+	CustomerID     uuid.UUID   `json:"customer_id"`
+	Status         OrderStatus `json:"status"`
+	PreviousStatus OrderStatus `json:"previous_status"`
+	Note           string      `json:"note,omitempty"`
+}
+
+// UserEvent is the data of the user events, only UserID is set on user.deleted
+type UserEvent struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username,omitempty"`
+	Email    string    `json:"email,omitempty"`
+	Role     Role      `json:"role,omitempty"`
+	IsActive *bool     `json:"is_active,omitempty"`
+}
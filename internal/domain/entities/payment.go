@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CaptureStatus is the state of a payment capture
+type CaptureStatus string
+
+const (
+	CaptureStatusPending  CaptureStatus = "pending"
+	CaptureStatusCaptured CaptureStatus = "captured"
+	CaptureStatusFailed   CaptureStatus = "failed"
+)
+
+// CaptureResult is the answer of a payment provider to a capture attempt
+type CaptureResult string
+
+const (
+	// CaptureResultCaptured means the payment is settled
+	CaptureResultCaptured CaptureResult = "captured"
+	// CaptureResultPending means the provider hasn't settled yet, the attempt is retried
+	CaptureResultPending CaptureResult = "pending"
+	// CaptureResultDeclined means the payment won't be settled, the order is cancelled
+	CaptureResultDeclined CaptureResult = "declined"
+)
+
+// PaymentCapture tracks the capture of an order's payment at an asynchronous provider
+type PaymentCapture struct {
+	OrderID       uuid.UUID     `db:"order_id"`
+	Status        CaptureStatus `db:"status"`
+	Attempts      int           `db:"attempts"`
+	NextAttemptAt time.Time     `db:"next_attempt_at"`
+	LastError     string        `db:"last_error"`
+	CreatedAt     time.Time     `db:"created_at"`
+	UpdatedAt     time.Time     `db:"updated_at"`
+}
+
+// PaymentStatus is the state of a payment at the payment provider
+type PaymentStatus string
+
+const (
+	// PaymentStatusPending waits for the customer to pay
+	PaymentStatusPending   PaymentStatus = "pending"
+	PaymentStatusSucceeded PaymentStatus = "succeeded"
+	PaymentStatusFailed    PaymentStatus = "failed"
+	PaymentStatusCancelled PaymentStatus = "cancelled"
+)
+
+// Payment is a payment intent created at a payment provider for an order, the customer
+// completes it with the provider's client using ClientSecret
+type Payment struct {
+	ID      uuid.UUID `db:"id"`
+	OrderID uuid.UUID `db:"order_id"`
+	// Provider names the payment provider, e.g. stripe
+	Provider string `db:"provider"`
+	// ProviderRef is the ID of the payment at the provider
+	ProviderRef   string        `db:"provider_ref"`
+	Status        PaymentStatus `db:"status"`
+	Amount        int64         `db:"amount"`
+	Currency      string        `db:"currency"`
+	ClientSecret  string        `db:"client_secret"`
+	FailureReason string        `db:"failure_reason"`
+	CreatedAt     time.Time     `db:"created_at"`
+	UpdatedAt     time.Time     `db:"updated_at"`
+}
+
+// PaymentIntent is the answer of a payment provider to the creation of a payment, which
+// stays pending until the provider's webhook reports its outcome
+type PaymentIntent struct {
+	ProviderRef  string
+	ClientSecret string
+}
+
+// PaymentEvent is a verified webhook notification of a payment provider
+type PaymentEvent struct {
+	// ID identifies the event at the provider, redeliveries carry the same ID
+	ID          string
+	ProviderRef string
+	Status      PaymentStatus
+	// Amount is the amount paid in minor units
+	Amount        int64
+	FailureReason string
+}
@@ -0,0 +1,63 @@
+package entities
+
+import (
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// PriceAdjustment defines how a price rule changes a price
+type PriceAdjustment string
+
+const (
+	// PriceAdjustmentPercent changes the price by a percentage, e.g. 10 for +10%
+	PriceAdjustmentPercent PriceAdjustment = "percent"
+	// PriceAdjustmentAmount adds a fixed amount, negative to lower the price
+	PriceAdjustmentAmount PriceAdjustment = "amount"
+	// PriceAdjustmentSet replaces the price
+	PriceAdjustmentSet PriceAdjustment = "set"
+)
+
+// PriceRule describes a price change for the products it applies to. Amounts are in
+// minor units
+type PriceRule struct {
+	Adjustment PriceAdjustment
+	// Percent is the change of a percent rule, e.g. -15 for 15% off
+	Percent float64
+	// Amount is added by an amount rule or set by a set rule
+	Amount int64
+	// RoundTo rounds the result to the nearest multiple of RoundTo plus Ending,
+	// e.g. RoundTo 1000 with Ending 999 turns 12345 into 11999
+	RoundTo int64
+	Ending  int64
+}
+
+// Apply returns the price after the rule. Percent changes are rounded to the nearest
+// minor unit
+func (r PriceRule) Apply(price int64) int64 {
+	switch r.Adjustment {
+	case PriceAdjustmentPercent:
+		price = int64(math.Round(float64(price) * (100 + r.Percent) / 100))
+	case PriceAdjustmentAmount:
+		price += r.Amount
+	case PriceAdjustmentSet:
+		price = r.Amount
+	}
+
+	if r.RoundTo > 0 {
+		var steps int64
+		if offset := price - r.Ending; offset > 0 {
+			steps = (offset + r.RoundTo/2) / r.RoundTo
+		}
+		price = steps*r.RoundTo + r.Ending
+	}
+	return price
+}
+
+// PriceChange is the computed price change of a single product
+type PriceChange struct {
+	ProductID uuid.UUID
+	Name      string
+	OldPrice  int64
+	NewPrice  int64
+}
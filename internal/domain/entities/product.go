@@ -9,12 +9,153 @@ import (
 // Product represents a product entity in the system
 
 type Product struct {
+	ID          uuid.UUID          `db:"id"`
+	SKU         string             `db:"sku"`
+	Name        string             `db:"name"`
+	Description string             `db:"description"`
+	Price       int64              `db:"price"`
+	Stock       int                `db:"stock"`
+	Category    string             `db:"category"`
+	CategoryID  *uuid.UUID         `db:"category_id"`
+	Images      []ProductImage     `db:"images"`
+	Attributes  []ProductAttribute `db:"attributes"`
+	Tags        []Tag              `db:"tags"`
+	CreatedAt   time.Time          `db:"created_at"`
+	UpdatedAt   time.Time          `db:"updated_at"`
+	// DeletedAt is set when the product is archived
+	DeletedAt *time.Time `db:"deleted_at"`
+	// ReorderThreshold is the stock level at which admins are alerted, nil disables alerts
+	ReorderThreshold *int `db:"reorder_threshold"`
+	// ReservedStock is held by pending orders and can't be sold
+	ReservedStock int `db:"reserved_stock"`
+	// WeightGrams is the shipping weight of one unit
+	WeightGrams int `db:"weight_grams"`
+	// Version is raised by every edit through the repository, an update made from an
+	// older version is refused
+	Version int `db:"version"`
+}
+
+// StockLevel is the stock of a product before and after a stock update
+type StockLevel struct {
+	ProductID        uuid.UUID
+	Previous         int
+	Current          int
+	ReorderThreshold *int
+}
+
+// DroppedToReorderLevel reports whether the update took the stock from above the reorder
+// threshold to at or below it, so every drop is alerted once rather than on every sale
+func (l *StockLevel) DroppedToReorderLevel() bool {
+	return l.ReorderThreshold != nil && l.Previous > *l.ReorderThreshold && l.Current <= *l.ReorderThreshold
+}
+
+// ProductImage represents an image in a product gallery
+type ProductImage struct {
 	ID          uuid.UUID `db:"id"`
-	Name        string    `db:"name"`
-	Description string    `db:"description"`
-	Price       float64   `db:"price"`
-	Stock       int       `db:"stock"`
-	Category    string    `db:"category"`
+	ProductID   uuid.UUID `db:"product_id"`
+	URL         string    `db:"url"`
+	StorageKey  string    `db:"storage_key"`
+	ContentType string    `db:"content_type"`
+	Size        int64     `db:"size"`
+	Position    int       `db:"position"`
+	IsPrimary   bool      `db:"is_primary"`
 	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// ProductDeletePolicy decides how deleting a product treats its open orders
+type ProductDeletePolicy string
+
+const (
+	// ProductDeleteBlock refuses to delete a product with open orders or stock reservations
+	ProductDeleteBlock ProductDeletePolicy = "block"
+	// ProductDeleteArchive archives the product and lets its open orders complete
+	ProductDeleteArchive ProductDeletePolicy = "archive"
+	// ProductDeleteCleanup cancels the unpaid orders of the product, releasing their
+	// reservations, then archives it; paid orders are still fulfilled
+	ProductDeleteCleanup ProductDeletePolicy = "cleanup"
+)
+
+// IsValid checks if the delete policy is known
+func (p ProductDeletePolicy) IsValid() bool {
+	switch p {
+	case ProductDeleteBlock, ProductDeleteArchive, ProductDeleteCleanup:
+		return true
+	}
+	return false
+}
+
+// ProductReferences is what still refers to a product
+type ProductReferences struct {
+	// OpenOrders counts the orders that aren't completed or cancelled per status
+	OpenOrders map[OrderStatus]int
+	// OpenOrderIDs holds the most recent open orders
+	OpenOrderIDs []uuid.UUID
+	// ClosedOrders counts completed and cancelled orders, they keep the archived product
+	ClosedOrders       int
+	ActiveReservations int
+	ReservedQuantity   int
+	Images             int
+	Attributes         int
+	Tags               int
+}
+
+// Blocking reports whether open orders or reservations still depend on the product
+func (r *ProductReferences) Blocking() bool {
+	open := 0
+	for _, n := range r.OpenOrders {
+		open += n
+	}
+	return open > 0 || r.ActiveReservations > 0
+}
+
+// InventorySummary aggregates the stock of all active products, values are in minor units
+type InventorySummary struct {
+	Products      int
+	Stock         int64
+	ReservedStock int64
+	// InventoryValue is the sum of price times stock
+	InventoryValue int64
+	OutOfStock     int
+	// LowStock counts products at or below their reorder threshold, out of stock included
+	LowStock int
+	Archived int
+}
+
+// CategoryStats aggregates the active products of a category
+type CategoryStats struct {
+	// CategoryID is nil for products with only a free-text category
+	CategoryID     *uuid.UUID
+	Category       string
+	Products       int
+	Stock          int64
+	InventoryValue int64
+	OutOfStock     int
+}
+
+// DemandForecast is the expected weekly demand of a product, derived from what it sold
+// in sold orders, see SoldOrderStatuses
+type DemandForecast struct {
+	ProductID uuid.UUID
+	// HistoryStart is the first week of History, weeks start on Monday (UTC)
+	HistoryStart time.Time
+	// History is the quantity sold per week, oldest first, up to the last complete week
+	History []int64
+	// Window is the number of latest weeks averaged into Baseline
+	Window   int
+	Baseline float64
+	// Seasonal is set when the weeks a year before the forecast weighted it
+	Seasonal bool
+	// Weekly is the expected quantity per week starting with the current one
+	Weekly []float64
+	Total  float64
+}
+
+// ProductSales is what a product sold over a period, in orders with SoldOrderStatuses
+type ProductSales struct {
+	ProductID uuid.UUID
+	Name      string
+	Quantity  int64
+	Orders    int
+	// Revenue is the sum of the order item subtotals, in minor units
+	Revenue int64
 }
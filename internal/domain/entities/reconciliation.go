@@ -0,0 +1,150 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Settlement report sources
+const (
+	SettlementSourceUpload = "upload"
+	SettlementSourceAPI    = "api"
+)
+
+// ReconciliationIssueKind is the kind of mismatch between the provider and our records
+type ReconciliationIssueKind string
+
+const (
+	// IssueMissingInReport is a captured payment the provider didn't settle
+	IssueMissingInReport ReconciliationIssueKind = "missing_in_report"
+	// IssueMissingPayment is a settlement without a captured payment on our side
+	IssueMissingPayment ReconciliationIssueKind = "missing_payment"
+	// IssueAmountMismatch is a settlement whose amount differs from the order total
+	IssueAmountMismatch ReconciliationIssueKind = "amount_mismatch"
+	// IssueDuplicateSettlement is a second settlement of the same order
+	IssueDuplicateSettlement ReconciliationIssueKind = "duplicate_settlement"
+)
+
+// Review states of a reconciliation issue
+const (
+	IssueStatusOpen     = "open"
+	IssueStatusResolved = "resolved"
+)
+
+// SettlementLine is a single settled payment of a provider report
+type SettlementLine struct {
+	OrderID   uuid.UUID
+	Reference string
+	Amount    int64
+	SettledAt time.Time
+}
+
+// SettlementReport is the list of payments a provider settled in a period
+type SettlementReport struct {
+	Source      string
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	Lines       []SettlementLine
+}
+
+// RecordedPayment is a payment we captured, as seen by reconciliation
+type RecordedPayment struct {
+	OrderID    uuid.UUID
+	Amount     int64
+	CapturedAt time.Time
+}
+
+// ReconciliationRun is the result of checking one settlement report
+type ReconciliationRun struct {
+	ID            uuid.UUID `db:"id"`
+	Source        string    `db:"source"`
+	PeriodStart   time.Time `db:"period_start"`
+	PeriodEnd     time.Time `db:"period_end"`
+	ReportedCount int       `db:"reported_count"`
+	RecordedCount int       `db:"recorded_count"`
+	MatchedCount  int       `db:"matched_count"`
+	IssueCount    int       `db:"issue_count"`
+	ReportedTotal int64     `db:"reported_total"`
+	RecordedTotal int64     `db:"recorded_total"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// ReconciliationIssue is a mismatch waiting in the admin review queue
+type ReconciliationIssue struct {
+	ID             uuid.UUID               `db:"id"`
+	RunID          uuid.UUID               `db:"run_id"`
+	OrderID        uuid.UUID               `db:"order_id"`
+	Kind           ReconciliationIssueKind `db:"kind"`
+	Reference      string                  `db:"reference"`
+	ExpectedAmount *int64                  `db:"expected_amount"`
+	ReportedAmount *int64                  `db:"reported_amount"`
+	Status         string                  `db:"status"`
+	Note           string                  `db:"note"`
+	ResolvedBy     *uuid.UUID              `db:"resolved_by"`
+	ResolvedAt     *time.Time              `db:"resolved_at"`
+	CreatedAt      time.Time               `db:"created_at"`
+}
+
+// Reconcile matches the settlement report against our payments. payments holds the
+// payments captured in the report period plus any other payment of an order in the
+// report, so settlements that cross the period boundary still match. The run and
+// issues are returned without IDs
+func Reconcile(report *SettlementReport, payments []RecordedPayment) (*ReconciliationRun, []ReconciliationIssue) {
+	run := &ReconciliationRun{
+		Source:        report.Source,
+		PeriodStart:   report.PeriodStart,
+		PeriodEnd:     report.PeriodEnd,
+		ReportedCount: len(report.Lines),
+	}
+
+	byOrder := make(map[uuid.UUID]RecordedPayment, len(payments))
+	for _, p := range payments {
+		byOrder[p.OrderID] = p
+		if inPeriod(p.CapturedAt, report.PeriodStart, report.PeriodEnd) {
+			run.RecordedCount++
+			run.RecordedTotal += p.Amount
+		}
+	}
+
+	issues := make([]ReconciliationIssue, 0)
+	settled := make(map[uuid.UUID]bool, len(report.Lines))
+	for _, line := range report.Lines {
+		reported := line.Amount
+		run.ReportedTotal += reported
+		issue := ReconciliationIssue{OrderID: line.OrderID, Reference: line.Reference, ReportedAmount: &reported, Status: IssueStatusOpen}
+
+		payment, ok := byOrder[line.OrderID]
+		switch {
+		case settled[line.OrderID]:
+			issue.Kind = IssueDuplicateSettlement
+		case !ok:
+			issue.Kind = IssueMissingPayment
+		case payment.Amount != line.Amount:
+			expected := payment.Amount
+			issue.Kind = IssueAmountMismatch
+			issue.ExpectedAmount = &expected
+		default:
+			run.MatchedCount++
+		}
+		settled[line.OrderID] = true
+		if issue.Kind != "" {
+			issues = append(issues, issue)
+		}
+	}
+
+	for _, p := range payments {
+		if !settled[p.OrderID] && inPeriod(p.CapturedAt, report.PeriodStart, report.PeriodEnd) {
+			expected := p.Amount
+			issues = append(issues, ReconciliationIssue{OrderID: p.OrderID, Kind: IssueMissingInReport, ExpectedAmount: &expected, Status: IssueStatusOpen})
+		}
+	}
+
+	run.IssueCount = len(issues)
+	return run, issues
+}
+
+// inPeriod reports whether t is within [start, end)
+func inPeriod(t, start, end time.Time) bool {
+	return !t.Before(start) && t.Before(end)
+}
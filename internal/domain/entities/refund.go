@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefundStatus is the state of a refund at the payment provider
+type RefundStatus string
+
+const (
+	// RefundStatusPending is recorded before the payment provider is called
+	RefundStatusPending   RefundStatus = "pending"
+	RefundStatusSucceeded RefundStatus = "succeeded"
+	RefundStatusFailed    RefundStatus = "failed"
+)
+
+// RefundProviderManual marks refunds of orders not paid through a payment provider, the
+// money is returned outside the shop
+const RefundProviderManual = "manual"
+
+// Refund is money returned for an order, in full or in part
+type Refund struct {
+	ID      uuid.UUID `db:"id"`
+	OrderID uuid.UUID `db:"order_id"`
+	// PaymentID is the refunded payment, nil for manual refunds
+	PaymentID     *uuid.UUID   `db:"payment_id"`
+	Provider      string       `db:"provider"`
+	ProviderRef   string       `db:"provider_ref"`
+	Status        RefundStatus `db:"status"`
+	Amount        int64        `db:"amount"`
+	Reason        string       `db:"reason"`
+	FailureReason string       `db:"failure_reason"`
+	CreatedBy     *uuid.UUID   `db:"created_by"`
+	// Items are the returned items, which go back into stock
+	Items     []RefundItem `db:"items"`
+	CreatedAt time.Time    `db:"created_at"`
+	UpdatedAt time.Time    `db:"updated_at"`
+}
+
+// RefundItem is a returned quantity of an order item
+type RefundItem struct {
+	OrderItemID uuid.UUID `db:"order_item_id"`
+	ProductID   uuid.UUID `db:"product_id"`
+	Quantity    int       `db:"quantity"`
+}
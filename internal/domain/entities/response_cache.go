@@ -0,0 +1,13 @@
+package entities
+
+// CacheScopeCatalog groups the cached responses of the public catalog routes, product
+// writes invalidate it
+const CacheScopeCatalog = "catalog"
+
+// CachedResponse is a rendered response of a public GET route, served again to requests
+// with the same key
+type CachedResponse struct {
+	// Header holds the headers set by the handler
+	Header map[string][]string `json:"header"`
+	Body   []byte              `json:"body"`
+}
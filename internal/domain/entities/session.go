@@ -0,0 +1,25 @@
+package entities
+
+import "time"
+
+// Session limit policies, applied at login when a user reaches the session quota
+const (
+	// SessionLimitReject refuses the new login
+	SessionLimitReject = "reject"
+	// SessionLimitEvictOldest ends the oldest session to make room for the new one
+	SessionLimitEvictOldest = "evict_oldest"
+)
+
+// Session is a login session, i.e. a refresh token family, with the device it was
+// started from
+type Session struct {
+	Family string `json:"family"`
+	// Device is the User-Agent of the login request
+	Device     string    `json:"device"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// EvictedAt is set on sessions ended by the session quota
+	EvictedAt *time.Time `json:"evicted_at,omitempty"`
+}
@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Shipment is a parcel of an order handed to a carrier, an order may ship in several
+type Shipment struct {
+	ID             uuid.UUID `db:"id"`
+	OrderID        uuid.UUID `db:"order_id"`
+	Carrier        string    `db:"carrier"`
+	TrackingNumber string    `db:"tracking_number"`
+	ShippedAt      time.Time `db:"shipped_at"`
+	// CreatedBy is the admin who recorded the shipment
+	CreatedBy *uuid.UUID `db:"created_by"`
+	CreatedAt time.Time  `db:"created_at"`
+}
@@ -0,0 +1,67 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockCountStatus is the state of a stock count session
+type StockCountStatus string
+
+const (
+	// StockCountCounting accepts counted quantities
+	StockCountCounting StockCountStatus = "counting"
+	// StockCountSubmitted has every product counted and waits for approval
+	StockCountSubmitted StockCountStatus = "submitted"
+	// StockCountApproved had its variances posted to the stock ledger
+	StockCountApproved StockCountStatus = "approved"
+	// StockCountRejected was dropped without changing the stock
+	StockCountRejected StockCountStatus = "rejected"
+)
+
+// IsValid checks if the stock count status is known
+func (s StockCountStatus) IsValid() bool {
+	switch s {
+	case StockCountCounting, StockCountSubmitted, StockCountApproved, StockCountRejected:
+		return true
+	}
+	return false
+}
+
+// StockCount is a physical count of the stock of a warehouse, or of one category in it
+type StockCount struct {
+	ID          uuid.UUID        `db:"id"`
+	WarehouseID uuid.UUID        `db:"warehouse_id"`
+	CategoryID  *uuid.UUID       `db:"category_id"`
+	Status      StockCountStatus `db:"status"`
+	Note        string           `db:"note"`
+	Items       []StockCountItem `db:"items"`
+	CreatedBy   *uuid.UUID       `db:"created_by"`
+	SubmittedBy *uuid.UUID       `db:"submitted_by"`
+	SubmittedAt *time.Time       `db:"submitted_at"`
+	ReviewedBy  *uuid.UUID       `db:"reviewed_by"`
+	ReviewedAt  *time.Time       `db:"reviewed_at"`
+	ReviewNote  string           `db:"review_note"`
+	CreatedAt   time.Time        `db:"created_at"`
+	UpdatedAt   time.Time        `db:"updated_at"`
+}
+
+// StockCountItem is a product of a stock count. Expected is the system stock when the
+// count was opened, Counted stays nil until the product has been counted
+type StockCountItem struct {
+	ProductID   uuid.UUID  `db:"product_id"`
+	ProductName string     `db:"product_name"`
+	Expected    int        `db:"expected_quantity"`
+	Counted     *int       `db:"counted_quantity"`
+	CountedBy   *uuid.UUID `db:"counted_by"`
+	CountedAt   *time.Time `db:"counted_at"`
+}
+
+// Variance is the counted quantity minus the expected one, 0 while not counted
+func (i *StockCountItem) Variance() int {
+	if i.Counted == nil {
+		return 0
+	}
+	return *i.Counted - i.Expected
+}
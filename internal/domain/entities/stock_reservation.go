@@ -0,0 +1,33 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReservationStatus is the state of a stock reservation
+type ReservationStatus string
+
+const (
+	// ReservationActive holds stock for a pending order
+	ReservationActive ReservationStatus = "active"
+	// ReservationConverted was deducted from the stock once the order was paid
+	ReservationConverted ReservationStatus = "converted"
+	// ReservationReleased was given back because the order was cancelled
+	ReservationReleased ReservationStatus = "released"
+	// ReservationExpired was given back because the order wasn't paid in time
+	ReservationExpired ReservationStatus = "expired"
+)
+
+// StockReservation holds stock of a product for an order until it's paid or expires
+type StockReservation struct {
+	ID        uuid.UUID         `db:"id"`
+	OrderID   uuid.UUID         `db:"order_id"`
+	ProductID uuid.UUID         `db:"product_id"`
+	Quantity  int               `db:"quantity"`
+	Status    ReservationStatus `db:"status"`
+	ExpiresAt time.Time         `db:"expires_at"`
+	CreatedAt time.Time         `db:"created_at"`
+	UpdatedAt time.Time         `db:"updated_at"`
+}
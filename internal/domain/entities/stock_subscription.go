@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockSubscription is a user waiting to be emailed when a product is back in stock
+type StockSubscription struct {
+	UserID      uuid.UUID `db:"user_id"`
+	ProductID   uuid.UUID `db:"product_id"`
+	ProductName string    `db:"product_name"`
+	// Stock is the current stock of the product
+	Stock     int       `db:"stock"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// StockSubscriber is an active user subscribed to a product, with what is needed to email them
+type StockSubscriber struct {
+	UserID   uuid.UUID `db:"user_id"`
+	Username string    `db:"username"`
+	Email    string    `db:"email"`
+}
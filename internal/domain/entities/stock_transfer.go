@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransferStatus is the state of a stock transfer
+type TransferStatus string
+
+const (
+	// TransferDraft is a transfer that hasn't moved any stock yet
+	TransferDraft TransferStatus = "draft"
+	// TransferInTransit has left the source warehouse and not yet arrived
+	TransferInTransit TransferStatus = "in_transit"
+	// TransferReceived was booked into the destination warehouse
+	TransferReceived TransferStatus = "received"
+	// TransferCancelled was dropped before it was shipped
+	TransferCancelled TransferStatus = "cancelled"
+)
+
+// OpenTransferStatuses are the states of a transfer that still needs handling
+var OpenTransferStatuses = []TransferStatus{TransferDraft, TransferInTransit}
+
+// IsValid checks if the transfer status is known
+func (s TransferStatus) IsValid() bool {
+	switch s {
+	case TransferDraft, TransferInTransit, TransferReceived, TransferCancelled:
+		return true
+	}
+	return false
+}
+
+// StockTransfer moves stock from one warehouse to another. Shipping takes the items out
+// of the source warehouse and receiving books them into the destination, in between the
+// quantities are in transit
+type StockTransfer struct {
+	ID                     uuid.UUID           `db:"id"`
+	SourceWarehouseID      uuid.UUID           `db:"source_warehouse_id"`
+	DestinationWarehouseID uuid.UUID           `db:"destination_warehouse_id"`
+	Status                 TransferStatus      `db:"status"`
+	Note                   string              `db:"note"`
+	Items                  []StockTransferItem `db:"items"`
+	CreatedBy              *uuid.UUID          `db:"created_by"`
+	ShippedBy              *uuid.UUID          `db:"shipped_by"`
+	ShippedAt              *time.Time          `db:"shipped_at"`
+	ReceivedBy             *uuid.UUID          `db:"received_by"`
+	ReceivedAt             *time.Time          `db:"received_at"`
+	CreatedAt              time.Time           `db:"created_at"`
+	UpdatedAt              time.Time           `db:"updated_at"`
+}
+
+// StockTransferItem is the quantity of a product on a stock transfer
+type StockTransferItem struct {
+	ProductID   uuid.UUID `db:"product_id"`
+	ProductName string    `db:"product_name"`
+	Quantity    int       `db:"quantity"`
+}
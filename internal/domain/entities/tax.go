@@ -0,0 +1,40 @@
+package entities
+
+// BasisPoints is the number of basis points in 100%, tax rates are in basis points so
+// taxes are computed without floating point
+const BasisPoints = 10000
+
+// TaxRate is a configured tax rate. Region is the country code of the shipping address
+// and Category the product category, either is empty for a rate applying to all
+type TaxRate struct {
+	Region   string
+	Category string
+	// Rate is in basis points, e.g. 1100 for 11%
+	Rate int
+}
+
+// Name identifies the rate as configured, "<region>/<category>" with * for any
+func (r *TaxRate) Name() string {
+	region, category := r.Region, r.Category
+	if region == "" {
+		region = "*"
+	}
+	if category == "" {
+		return region
+	}
+	return region + "/" + category
+}
+
+// Tax returns the tax on amount at the rate, rounded half up to the minor unit
+func (r *TaxRate) Tax(amount int64) int64 {
+	return (amount*int64(r.Rate) + BasisPoints/2) / BasisPoints
+}
+
+// TaxLine is the tax charged on the items of an order taxed at the same rate
+type TaxLine struct {
+	Name string `json:"name"`
+	// Rate is in basis points
+	Rate          int   `json:"rate"`
+	TaxableAmount int64 `json:"taxable_amount"`
+	Amount        int64 `json:"amount"`
+}
@@ -16,12 +16,18 @@ const (
 
 // User represents a user entity in the system
 type User struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	Username  string    `json:"username" db:"username"`
-	Email     string    `json:"email" db:"email"`
-	Password  string    `json:"-" db:"password"`
-	Role      Role      `json:"role" db:"role"`
-	IsActive  bool      `json:"is_active" db:"is_active"`
+	ID       uuid.UUID `json:"id" db:"id"`
+	Username string    `json:"username" db:"username"`
+	Email    string    `json:"email" db:"email"`
+	Password string    `json:"-" db:"password"`
+	Role     Role      `json:"role" db:"role"`
+	IsActive bool      `json:"is_active" db:"is_active"`
+	// IsGuest marks a customer who ordered without an account, a guest can't sign in
+	IsGuest   bool      `json:"is_guest" db:"is_guest"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	// Version is raised by every update, an update made from an older version is refused
+	Version int `json:"version" db:"version"`
+	// DeletedAt is set once the user is soft deleted
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
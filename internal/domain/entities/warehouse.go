@@ -0,0 +1,67 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Warehouse is a location that holds stock
+type Warehouse struct {
+	ID        uuid.UUID `db:"id"`
+	Code      string    `db:"code"`
+	Name      string    `db:"name"`
+	Address   string    `db:"address"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// WarehouseStock is the on-hand quantity of a product in a warehouse
+type WarehouseStock struct {
+	WarehouseID uuid.UUID `db:"warehouse_id"`
+	ProductID   uuid.UUID `db:"product_id"`
+	ProductName string    `db:"product_name"`
+	SKU         string    `db:"sku"`
+	Quantity    int       `db:"quantity"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// LedgerReason is why a warehouse stock movement was posted
+type LedgerReason string
+
+const (
+	// LedgerAdjustment is a manual correction of the warehouse stock
+	LedgerAdjustment LedgerReason = "adjustment"
+	// LedgerTransferOut is stock shipped to another warehouse
+	LedgerTransferOut LedgerReason = "transfer_out"
+	// LedgerTransferIn is stock received from another warehouse
+	LedgerTransferIn LedgerReason = "transfer_in"
+	// LedgerStockCount is the variance of an approved stock count
+	LedgerStockCount LedgerReason = "stock_count"
+)
+
+// StockLedgerEntry is a movement of warehouse stock. Quantity is positive for stock
+// coming in and negative for stock going out, Balance is the stock after the movement
+type StockLedgerEntry struct {
+	ID          uuid.UUID    `db:"id"`
+	WarehouseID uuid.UUID    `db:"warehouse_id"`
+	ProductID   uuid.UUID    `db:"product_id"`
+	Quantity    int          `db:"quantity"`
+	Balance     int          `db:"balance"`
+	Reason      LedgerReason `db:"reason"`
+	// ReferenceID is the document that caused the movement, such as a stock transfer
+	ReferenceID *uuid.UUID `db:"reference_id"`
+	Note        string     `db:"note"`
+	CreatedBy   *uuid.UUID `db:"created_by"`
+	CreatedAt   time.Time  `db:"created_at"`
+}
+
+// StockLedgerFilter narrows a stock ledger listing, nil and zero fields match everything
+type StockLedgerFilter struct {
+	WarehouseID *uuid.UUID
+	ProductID   *uuid.UUID
+	ReferenceID *uuid.UUID
+	// From and To bound the posting time to [From, To)
+	From time.Time
+	To   time.Time
+}
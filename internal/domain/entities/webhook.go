@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Order events delivered to webhook endpoints
+const (
+	WebhookEventOrderCreated       = "order.created"
+	WebhookEventOrderStatusChanged = "order.status_changed"
+)
+
+var webhookEvents = []string{WebhookEventOrderCreated, WebhookEventOrderStatusChanged}
+
+// IsValidWebhookEvent checks if event is a known webhook event type
+func IsValidWebhookEvent(event string) bool {
+	return slices.Contains(webhookEvents, event)
+}
+
+// WebhookEvents returns every event type an endpoint can subscribe to
+func WebhookEvents() []string {
+	return slices.Clone(webhookEvents)
+}
+
+// WebhookEndpoint is a URL of an external system receiving the events it subscribed to.
+// Deliveries are signed with Secret
+type WebhookEndpoint struct {
+	ID          uuid.UUID  `db:"id"`
+	URL         string     `db:"url"`
+	Description string     `db:"description"`
+	Secret      string     `db:"secret"`
+	Events      []string   `db:"events"`
+	Active      bool       `db:"active"`
+	CreatedBy   *uuid.UUID `db:"created_by"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+}
+
+// WebhookDelivery is one attempt to deliver an event to an endpoint
+type WebhookDelivery struct {
+	ID         uuid.UUID `db:"id"`
+	EndpointID uuid.UUID `db:"endpoint_id"`
+	EventID    uuid.UUID `db:"event_id"`
+	EventType  string    `db:"event_type"`
+	// Attempt counts the attempts of this event to this endpoint, from 1
+	Attempt int    `db:"attempt"`
+	Payload []byte `db:"payload"`
+	// StatusCode is nil when no response was received
+	StatusCode   *int      `db:"status_code"`
+	ResponseBody string    `db:"response_body"`
+	Error        string    `db:"error"`
+	DurationMs   int       `db:"duration_ms"`
+	Succeeded    bool      `db:"succeeded"`
+	CreatedAt    time.Time `db:"created_at"`
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+
+	"postgresDB/pkg/i18n"
 )
 
 // ErrorCode represents the error code type
@@ -18,29 +20,56 @@ const (
 	CodeInternal     ErrorCode = "INTERNAL_ERROR"
 	CodeBadRequest   ErrorCode = "BAD_REQUEST"
 	CodeTooMany      ErrorCode = "TOO_MANY_REQUESTS"
+	CodeUnavailable  ErrorCode = "SERVICE_UNAVAILABLE"
+	CodeTimeout      ErrorCode = "REQUEST_TIMEOUT"
+	CodeTooLarge     ErrorCode = "PAYLOAD_TOO_LARGE"
+	CodeMediaType    ErrorCode = "UNSUPPORTED_MEDIA_TYPE"
+	CodeMethod       ErrorCode = "METHOD_NOT_ALLOWED"
+	CodeQueryTimeout ErrorCode = "QUERY_TIMEOUT"
+	CodeCanceled     ErrorCode = "REQUEST_CANCELED"
 )
 
-// AppError represents a custom application error
+// StatusClientClosedRequest is the non-standard status of a request its client gave up on
+const StatusClientClosedRequest = 499
+
+// AppError represents a custom application error. Message is in the default language of
+// i18n, translated per request when written; with Args it's a format they fill in
 type AppError struct {
 	Code       ErrorCode         `json:"code"`
 	Message    string            `json:"message"`
+	Args       []any             `json:"-"`
 	Details    []ValidationError `json:"details,omitempty"`
 	HTTPStatus int               `json:"-"`
 	Err        error             `json:"-"`
 }
 
-// ValidationError represents a field validation error
+// ValidationError represents a field validation error. Line and Column point into the
+// request body for errors found while decoding it. Message is translated like the one
+// of AppError
 type ValidationError struct {
 	Field   string `json:"field"`
 	Message string `json:"message"`
+	Args    []any  `json:"-"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
 }
 
 // Error implements the error interface
 func (e *AppError) Error() string {
 	if e.Err != nil {
-		return fmt.Sprintf("%s: %s (%v)", e.Code, e.Message, e.Err)
+		return fmt.Sprintf("%s: %s (%v)", e.Code, e.Localize(i18n.Default), e.Err)
 	}
-	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+	return fmt.Sprintf("%s: %s", e.Code, e.Localize(i18n.Default))
+}
+
+// Localize returns the message in lang
+func (e *AppError) Localize(lang string) string {
+	return i18n.T(lang, e.Message, e.Args...)
+}
+
+// Localize returns the message in lang
+func (v ValidationError) Localize(lang string) string {
+	return i18n.T(lang, v.Message, v.Args...)
 }
 
 // Unwrap returns the wrapped error
@@ -113,6 +142,13 @@ var (
 		HTTPStatus: http.StatusForbidden,
 	}
 
+	// ErrIPForbidden rejects a client IP that is denied, or not allowed on the route
+	ErrIPForbidden = &AppError{
+		Code:       CodeForbidden,
+		Message:    "Akses dari alamat IP ini tidak diizinkan",
+		HTTPStatus: http.StatusForbidden,
+	}
+
 	ErrUserNotFound = &AppError{
 		Code:       CodeNotFound,
 		Message:    "User tidak ditemukan",
@@ -149,18 +185,420 @@ var (
 		HTTPStatus: http.StatusNotFound,
 	}
 
+	ErrSKUExists = &AppError{
+		Code:       CodeConflict,
+		Message:    "SKU sudah digunakan produk lain",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrSnapshotNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Snapshot katalog tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrVersionConflict = &AppError{
+		Code:       CodeConflict,
+		Message:    "Data sudah diubah oleh permintaan lain, muat ulang lalu coba lagi",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrPriceChanged = &AppError{
+		Code:       CodeConflict,
+		Message:    "Harga produk berubah selama pembaruan, silakan coba lagi",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrProductImageNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Gambar produk tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrInvalidImage = &AppError{
+		Code:       CodeBadRequest,
+		Message:    "File gambar tidak valid, gunakan JPEG, PNG, WEBP atau GIF",
+		HTTPStatus: http.StatusBadRequest,
+	}
+
+	ErrFileTooLarge = &AppError{
+		Code:       CodeBadRequest,
+		Message:    "Ukuran file terlalu besar",
+		HTTPStatus: http.StatusRequestEntityTooLarge,
+	}
+
+	ErrCategoryNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Kategori tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrCategoryExists = &AppError{
+		Code:       CodeConflict,
+		Message:    "Slug kategori sudah digunakan",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrCategoryInUse = &AppError{
+		Code:       CodeConflict,
+		Message:    "Kategori masih memiliki sub-kategori",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrInvalidCategoryParent = &AppError{
+		Code:       CodeBadRequest,
+		Message:    "Parent kategori tidak valid",
+		HTTPStatus: http.StatusBadRequest,
+	}
+
+	ErrAttributeNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Atribut tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrAttributeExists = &AppError{
+		Code:       CodeConflict,
+		Message:    "Kode atribut sudah digunakan pada kategori ini",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrTagNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Tag tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrTagExists = &AppError{
+		Code:       CodeConflict,
+		Message:    "Slug tag sudah digunakan",
+		HTTPStatus: http.StatusConflict,
+	}
+
 	ErrOrderNotFound = &AppError{
 		Code:       CodeNotFound,
 		Message:    "Order tidak ditemukan",
 		HTTPStatus: http.StatusNotFound,
 	}
 
+	ErrPaymentCaptureNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Capture pembayaran tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrPaymentUnavailable = &AppError{
+		Code:       CodeUnavailable,
+		Message:    "Penyedia pembayaran belum dikonfigurasi",
+		HTTPStatus: http.StatusServiceUnavailable,
+	}
+
+	ErrReconciliationIssueNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Selisih rekonsiliasi tidak ditemukan atau sudah ditinjau",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrEmailTemplateNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Template email tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrMailUnavailable = &AppError{
+		Code:       CodeUnavailable,
+		Message:    "Pengiriman email belum dikonfigurasi",
+		HTTPStatus: http.StatusServiceUnavailable,
+	}
+
+	ErrJobNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Job tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrJobStateConflict = &AppError{
+		Code:       CodeConflict,
+		Message:    "Job tidak dapat diubah pada status saat ini",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrAccessLogExportNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Export access log tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrAccessLogExportNotReady = &AppError{
+		Code:       CodeConflict,
+		Message:    "Export access log belum selesai",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrProductReferenced = &AppError{
+		Code:       CodeConflict,
+		Message:    "Produk masih dipakai order aktif",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrAuthStateBackupNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Backup auth state tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrAuthStateBackupUnavailable = &AppError{
+		Code:       CodeConflict,
+		Message:    "Backup auth state tidak dapat dipulihkan",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrAPIClientNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "API client tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrAPIClientLimit = &AppError{
+		Code:       CodeConflict,
+		Message:    "Batas jumlah API client tercapai",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrStockSubscriptionNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Langganan stok tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrProductInStock = &AppError{
+		Code:       CodeConflict,
+		Message:    "Produk masih tersedia, langganan hanya untuk produk yang stoknya habis",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrWarehouseNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Gudang tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrWarehouseCodeExists = &AppError{
+		Code:       CodeConflict,
+		Message:    "Kode gudang sudah digunakan",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrInsufficientWarehouseStock = &AppError{
+		Code:       CodeConflict,
+		Message:    "Stok gudang tidak mencukupi",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrStockTransferNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Transfer stok tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrStockTransferStatus = &AppError{
+		Code:       CodeConflict,
+		Message:    "Status transfer stok tidak memungkinkan aksi ini",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrStockCountNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Stock opname tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrStockCountStatus = &AppError{
+		Code:       CodeConflict,
+		Message:    "Status stock opname tidak memungkinkan aksi ini",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrStockCountInProgress = &AppError{
+		Code:       CodeConflict,
+		Message:    "Masih ada stock opname yang berjalan di gudang ini",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrStockCountIncomplete = &AppError{
+		Code:       CodeConflict,
+		Message:    "Masih ada produk yang belum dihitung",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrCustomerMismatch = &AppError{
+		Code:       CodeForbidden,
+		Message:    "customer_id harus sama dengan user yang login",
+		HTTPStatus: http.StatusForbidden,
+	}
+
+	ErrIdempotencyKeyInvalid = &AppError{
+		Code:       CodeBadRequest,
+		Message:    "Header Idempotency-Key harus 1 sampai 255 karakter",
+		HTTPStatus: http.StatusBadRequest,
+	}
+
+	ErrIdempotencyKeyReused = &AppError{
+		Code:       CodeValidation,
+		Message:    "Idempotency-Key sudah dipakai untuk request yang berbeda",
+		HTTPStatus: http.StatusUnprocessableEntity,
+	}
+
+	ErrIdempotencyInProgress = &AppError{
+		Code:       CodeConflict,
+		Message:    "Request dengan Idempotency-Key ini masih diproses",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrPaymentNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Pembayaran tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrOrderNotPayable = &AppError{
+		Code:       CodeConflict,
+		Message:    "Order tidak sedang menunggu pembayaran",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrPaymentProviderFailed = &AppError{
+		Code:       CodeUnavailable,
+		Message:    "Penyedia pembayaran gagal memproses permintaan, coba lagi nanti",
+		HTTPStatus: http.StatusBadGateway,
+	}
+
+	ErrWebhookSignatureInvalid = &AppError{
+		Code:       CodeBadRequest,
+		Message:    "Tanda tangan webhook tidak valid",
+		HTTPStatus: http.StatusBadRequest,
+	}
+
+	ErrPaymentAmountMismatch = &AppError{
+		Code:       CodeValidation,
+		Message:    "Jumlah pembayaran tidak sesuai dengan total order",
+		HTTPStatus: http.StatusUnprocessableEntity,
+	}
+
+	ErrOrderNotRefundable = &AppError{
+		Code:       CodeConflict,
+		Message:    "Order belum dibayar atau sudah direfund",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrRefundExceedsOrder = &AppError{
+		Code:       CodeValidation,
+		Message:    "Refund melebihi jumlah atau item order yang belum direfund",
+		HTTPStatus: http.StatusUnprocessableEntity,
+	}
+
+	ErrOrderItemNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Item order tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrOrderNotYetPlaced = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Order belum dibuat pada waktu tersebut",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrAddressNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Alamat tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrShippingAddressRequired = &AppError{
+		Code:       CodeValidation,
+		Message:    "Alamat pengiriman wajib diisi",
+		HTTPStatus: http.StatusUnprocessableEntity,
+	}
+
+	ErrShippingMethodNotFound = &AppError{
+		Code:       CodeValidation,
+		Message:    "Metode pengiriman tidak tersedia",
+		HTTPStatus: http.StatusUnprocessableEntity,
+	}
+
+	ErrSessionLimitReached = &AppError{
+		Code:       CodeConflict,
+		Message:    "Jumlah sesi aktif sudah mencapai batas, keluar dari perangkat lain terlebih dahulu",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrConsentNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Persetujuan belum pernah dipilih",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrConsentRequired = &AppError{
+		Code:       CodeForbidden,
+		Message:    "Persetujuan email pemasaran diperlukan, aktifkan di pengaturan persetujuan",
+		HTTPStatus: http.StatusForbidden,
+	}
+
+	ErrNotificationPreferenceNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Preferensi notifikasi belum pernah dipilih",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrWebhookNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Webhook tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrServerOverloaded = &AppError{
+		Code:       CodeUnavailable,
+		Message:    "Server sedang sibuk, coba lagi sebentar lagi",
+		HTTPStatus: http.StatusServiceUnavailable,
+	}
+
+	ErrTrackingLinkInvalid = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Link tracking tidak valid atau sudah dicabut",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrShipmentNotAllowed = &AppError{
+		Code:       CodeBadRequest,
+		Message:    "Pengiriman hanya bisa dicatat untuk pesanan paid, processing atau shipped",
+		HTTPStatus: http.StatusBadRequest,
+	}
+
+	ErrGuestOrderNotClaimable = &AppError{
+		Code:       CodeConflict,
+		Message:    "Pesanan ini bukan pesanan guest atau sudah diklaim",
+		HTTPStatus: http.StatusConflict,
+	}
+
+	ErrGuestEmailMismatch = &AppError{
+		Code:       CodeForbidden,
+		Message:    "Email akun harus sama dengan email pesanan guest",
+		HTTPStatus: http.StatusForbidden,
+	}
+
 	ErrEmailExists = &AppError{
 		Code:       CodeConflict,
 		Message:    "Email sudah terdaftar",
 		HTTPStatus: http.StatusConflict,
 	}
 
+	ErrUsernameExists = &AppError{
+		Code:       CodeConflict,
+		Message:    "Username sudah digunakan",
+		HTTPStatus: http.StatusConflict,
+	}
+
 	ErrInsufficientStock = &AppError{
 		Code:       CodeBadRequest,
 		Message:    "Stok tidak mencukupi",
@@ -179,11 +617,65 @@ var (
 		HTTPStatus: http.StatusInternalServerError,
 	}
 
+	ErrServiceUnavailable = &AppError{
+		Code:       CodeUnavailable,
+		Message:    "Layanan sedang tidak tersedia, coba lagi nanti",
+		HTTPStatus: http.StatusServiceUnavailable,
+	}
+
+	ErrRequestSignatureRequired = &AppError{
+		Code:       CodeBadRequest,
+		Message:    "Header X-Request-Timestamp, X-Request-Nonce dan X-Request-Signature wajib diisi",
+		HTTPStatus: http.StatusBadRequest,
+	}
+
+	ErrRequestSignatureInvalid = &AppError{
+		Code:       CodeUnauthorized,
+		Message:    "Tanda tangan request tidak valid atau kedaluwarsa",
+		HTTPStatus: http.StatusUnauthorized,
+	}
+
+	ErrRequestReplayed = &AppError{
+		Code:       CodeConflict,
+		Message:    "Request dengan nonce ini sudah pernah diterima",
+		HTTPStatus: http.StatusConflict,
+	}
+
 	ErrTooManyRequests = &AppError{
 		Code:       CodeTooMany,
 		Message:    "Terlalu banyak permintaan, coba lagi nanti",
 		HTTPStatus: http.StatusTooManyRequests,
 	}
+
+	ErrRequestTimeout = &AppError{
+		Code:       CodeTimeout,
+		Message:    "Permintaan melebihi batas waktu, coba lagi",
+		HTTPStatus: http.StatusRequestTimeout,
+	}
+
+	ErrRequestTooLarge = &AppError{
+		Code:       CodeTooLarge,
+		Message:    "Body request terlalu besar",
+		HTTPStatus: http.StatusRequestEntityTooLarge,
+	}
+
+	ErrUnsupportedMediaType = &AppError{
+		Code:       CodeMediaType,
+		Message:    "Content-Type harus application/json",
+		HTTPStatus: http.StatusUnsupportedMediaType,
+	}
+
+	ErrRouteNotFound = &AppError{
+		Code:       CodeNotFound,
+		Message:    "Endpoint tidak ditemukan",
+		HTTPStatus: http.StatusNotFound,
+	}
+
+	ErrMethodNotAllowed = &AppError{
+		Code:       CodeMethod,
+		Message:    "Method tidak diizinkan untuk endpoint ini",
+		HTTPStatus: http.StatusMethodNotAllowed,
+	}
 )
 
 // IsAppError checks if the error is an AppError
@@ -211,6 +703,27 @@ func WrapInternal(err error) *AppError {
 	}
 }
 
+// WrapQueryTimeout wraps a database query stopped by its deadline or the statement timeout
+func WrapQueryTimeout(err error) *AppError {
+	return &AppError{
+		Code:       CodeQueryTimeout,
+		Message:    "Query database melebihi batas waktu, coba lagi",
+		HTTPStatus: http.StatusGatewayTimeout,
+		Err:        err,
+	}
+}
+
+// WrapCanceled wraps work stopped because its context was cancelled, usually because the
+// client disconnected. It isn't a timeout and no one is left to read the response
+func WrapCanceled(err error) *AppError {
+	return &AppError{
+		Code:       CodeCanceled,
+		Message:    "Permintaan dibatalkan",
+		HTTPStatus: StatusClientClosedRequest,
+		Err:        err,
+	}
+}
+
 // NewValidationError creates a validation error with details
 func NewValidationError(details []ValidationError) *AppError {
 	return &AppError{
@@ -225,7 +738,8 @@ func NewValidationError(details []ValidationError) *AppError {
 func NewNotFoundError(resource string) *AppError {
 	return &AppError{
 		Code:       CodeNotFound,
-		Message:    fmt.Sprintf("%s tidak ditemukan", resource),
+		Message:    "%s tidak ditemukan",
+		Args:       []any{resource},
 		HTTPStatus: http.StatusNotFound,
 	}
 }
@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+)
+
+// OAuth error codes returned by the token endpoint (RFC 6749 section 5.2)
+const (
+	OAuthInvalidRequest       = "invalid_request"
+	OAuthInvalidClient        = "invalid_client"
+	OAuthInvalidGrant         = "invalid_grant"
+	OAuthUnsupportedGrantType = "unsupported_grant_type"
+	OAuthServerError          = "server_error"
+)
+
+// OAuthError is an error of the OAuth token endpoint, written as
+// {"error": ..., "error_description": ...} instead of the API error format
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+	HTTPStatus  int    `json:"-"`
+	Err         error  `json:"-"`
+}
+
+// Error implements the error interface
+func (e *OAuthError) Error() string {
+	if e.Err != nil {
+		return e.Code + ": " + e.Description + " (" + e.Err.Error() + ")"
+	}
+	return e.Code + ": " + e.Description
+}
+
+// Unwrap returns the wrapped error
+func (e *OAuthError) Unwrap() error {
+	return e.Err
+}
+
+// NewOAuthError creates an OAuth error, invalid_client is answered with 401 and
+// server_error with 500
+func NewOAuthError(code, description string) *OAuthError {
+	status := http.StatusBadRequest
+	switch code {
+	case OAuthInvalidClient:
+		status = http.StatusUnauthorized
+	case OAuthServerError:
+		status = http.StatusInternalServerError
+	}
+	return &OAuthError{Code: code, Description: description, HTTPStatus: status}
+}
+
+// AsOAuthError converts an error to an OAuthError, other errors become server_error
+func AsOAuthError(err error) *OAuthError {
+	var oauthErr *OAuthError
+	if errors.As(err, &oauthErr) {
+		return oauthErr
+	}
+	serverErr := NewOAuthError(OAuthServerError, "")
+	serverErr.Err = err
+	return serverErr
+}
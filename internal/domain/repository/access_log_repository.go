@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccessLogFilter selects the access logs of an export; the range is [From, To)
+type AccessLogFilter struct {
+	ClientID string
+	UserID   *uuid.UUID
+	From     time.Time
+	To       time.Time
+}
+
+// AccessLogRepository defines the interface for access log and export operations
+type AccessLogRepository interface {
+	// InsertBatch stores the access logs in a single round trip
+	InsertBatch(ctx context.Context, logs []entities.AccessLog) error
+	// Each calls fn for every access log matching the filter, oldest first, stopping at
+	// the first error
+	Each(ctx context.Context, filter AccessLogFilter, fn func(*entities.AccessLog) error) error
+	// DailyUsage counts the requests and errors of a client per UTC day over [from, to),
+	// days without traffic are left out
+	DailyUsage(ctx context.Context, clientID string, from, to time.Time) ([]entities.APIClientUsage, error)
+	// DeleteBefore removes access logs recorded before t and returns how many were removed
+	DeleteBefore(ctx context.Context, t time.Time) (int64, error)
+
+	CreateExport(ctx context.Context, export *entities.AccessLogExport) error
+	GetExport(ctx context.Context, id uuid.UUID) (*entities.AccessLogExport, error)
+	// ListExports returns exports newest first, only those requested by requestedBy when set
+	ListExports(ctx context.Context, requestedBy *uuid.UUID, limit, offset int) ([]*entities.AccessLogExport, int64, error)
+	UpdateExport(ctx context.Context, export *entities.AccessLogExport) error
+	// DeleteExportsBefore removes exports created before t and returns them so their
+	// files can be removed
+	DeleteExportsBefore(ctx context.Context, t time.Time) ([]*entities.AccessLogExport, error)
+}
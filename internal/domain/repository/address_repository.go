@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// AddressRepository defines the interface for address book operations
+type AddressRepository interface {
+	// Create saves an address; a default address takes over from the user's previous one
+	Create(ctx context.Context, address *entities.Address) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Address, error)
+	// GetDefault returns the user's default address, ErrAddressNotFound when there is none
+	GetDefault(ctx context.Context, userID uuid.UUID) (*entities.Address, error)
+	// ListByUserID returns the user's addresses, the default one first
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Address, error)
+	// Update saves the address; a default address takes over from the user's previous one
+	Update(ctx context.Context, address *entities.Address) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
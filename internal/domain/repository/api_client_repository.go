@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// APIClientRepository defines the interface for API client operations
+type APIClientRepository interface {
+	Create(ctx context.Context, client *entities.APIClient) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.APIClient, error)
+	// GetByClientID retrieves a client by its public client ID
+	GetByClientID(ctx context.Context, clientID string) (*entities.APIClient, error)
+	// List returns clients newest first, only those of ownerID when set
+	List(ctx context.Context, ownerID *uuid.UUID, limit, offset int) ([]*entities.APIClient, int64, error)
+	CountByOwner(ctx context.Context, ownerID uuid.UUID) (int, error)
+	// Update saves the name, redirect URIs and scopes
+	Update(ctx context.Context, client *entities.APIClient) error
+	// UpdateSecret replaces the secret hash and its rotation time
+	UpdateSecret(ctx context.Context, client *entities.APIClient) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
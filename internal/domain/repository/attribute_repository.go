@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// AttributeRepository defines the interface for attribute definition and value operations
+type AttributeRepository interface {
+	CreateDefinition(ctx context.Context, def *entities.AttributeDefinition) error
+	GetDefinitionByID(ctx context.Context, id uuid.UUID) (*entities.AttributeDefinition, error)
+	ListDefinitionsByCategory(ctx context.Context, categoryID uuid.UUID) ([]*entities.AttributeDefinition, error)
+	UpdateDefinition(ctx context.Context, def *entities.AttributeDefinition) error
+	DeleteDefinition(ctx context.Context, id uuid.UUID) error
+	// SetProductValues replaces all attribute values of a product
+	SetProductValues(ctx context.Context, productID uuid.UUID, values []entities.ProductAttribute) error
+	ListValuesByProductIDs(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]entities.ProductAttribute, error)
+}
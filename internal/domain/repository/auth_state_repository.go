@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuthStateBackupRepository defines the interface for the audit trail of auth state
+// backups and restores
+type AuthStateBackupRepository interface {
+	CreateBackup(ctx context.Context, backup *entities.AuthStateBackup) error
+	GetBackup(ctx context.Context, id uuid.UUID) (*entities.AuthStateBackup, error)
+	// LatestBackup returns the most recent completed backup, nil when there is none
+	LatestBackup(ctx context.Context) (*entities.AuthStateBackup, error)
+	// ListBackups returns backups newest first
+	ListBackups(ctx context.Context, limit, offset int) ([]*entities.AuthStateBackup, int64, error)
+	// ExpireBackupsBefore marks completed backups created before t as expired and returns
+	// their storage keys so the files can be removed
+	ExpireBackupsBefore(ctx context.Context, t time.Time) ([]string, error)
+
+	CreateRestore(ctx context.Context, restore *entities.AuthStateRestore) error
+	// ListRestores returns restores newest first
+	ListRestores(ctx context.Context, limit, offset int) ([]*entities.AuthStateRestore, int64, error)
+}
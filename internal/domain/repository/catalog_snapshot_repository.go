@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CatalogSnapshotRepository defines the interface for catalog snapshot storage
+type CatalogSnapshotRepository interface {
+	Create(ctx context.Context, snapshot *entities.CatalogSnapshot) error
+	// GetByID returns the snapshot including its items
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.CatalogSnapshot, error)
+	// List returns snapshots newest first, without items
+	List(ctx context.Context, limit, offset int) ([]*entities.CatalogSnapshot, int64, error)
+	DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// CategoryRepository defines the interface for category data operations
+type CategoryRepository interface {
+	Create(ctx context.Context, category *entities.Category) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Category, error)
+	GetBySlug(ctx context.Context, slug string) (*entities.Category, error)
+	List(ctx context.Context) ([]*entities.Category, error)
+	Update(ctx context.Context, category *entities.Category) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// ConsentRepository defines the interface for user consents and their records
+type ConsentRepository interface {
+	// ListByUserID returns the consents the user chose, purposes never chosen are missing
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Consent, error)
+	// Get returns the consent the user chose for purpose, ErrConsentNotFound when never chosen
+	Get(ctx context.Context, userID uuid.UUID, purpose entities.ConsentPurpose) (*entities.Consent, error)
+	// Record stores the choices as the current consents of the user and appends them to
+	// the consent records, in one transaction
+	Record(ctx context.Context, records []*entities.ConsentRecord) error
+	// ListRecords returns the consent records of a user, newest first
+	ListRecords(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.ConsentRecord, int64, error)
+}
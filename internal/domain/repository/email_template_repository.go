@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// EmailTemplateRepository defines the interface for versioned email template storage
+type EmailTemplateRepository interface {
+	// Create stores the template as the next version of its key and locale
+	Create(ctx context.Context, template *entities.EmailTemplate) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.EmailTemplate, error)
+	// List returns versions filtered by key and locale when set, newest first
+	List(ctx context.Context, key, locale string, limit, offset int) ([]*entities.EmailTemplate, int64, error)
+	// GetActive returns the active version for the first locale that has one
+	GetActive(ctx context.Context, key string, locales []string) (*entities.EmailTemplate, error)
+	// Publish activates the version and archives the previously active one
+	Publish(ctx context.Context, id uuid.UUID) (*entities.EmailTemplate, error)
+}
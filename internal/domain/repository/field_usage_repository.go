@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+)
+
+// FieldUsageRepository defines the interface for field usage operations
+type FieldUsageRepository interface {
+	// Add adds the counts of usage to the stored ones, widening their first and last seen
+	Add(ctx context.Context, usage []entities.FieldUsage) error
+	// List returns the usage matching the filter by route, direction and field, most
+	// used clients first
+	List(ctx context.Context, filter entities.FieldUsageFilter) ([]entities.FieldUsage, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// NotificationPreferenceRepository defines the interface for the notification preferences of users
+type NotificationPreferenceRepository interface {
+	// ListByUserID returns the preferences the user chose, kinds and channels never chosen are missing
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.NotificationPreference, error)
+	// Get returns the preference the user chose for a kind on a channel,
+	// ErrNotificationPreferenceNotFound when never chosen
+	Get(ctx context.Context, userID uuid.UUID, kind entities.NotificationKind, channel string) (*entities.NotificationPreference, error)
+	// Save stores the preferences, replacing earlier choices, in one transaction
+	Save(ctx context.Context, preferences []*entities.NotificationPreference) error
+}
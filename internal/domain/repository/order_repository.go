@@ -3,18 +3,49 @@ package repository
 import (
 	"context"
 	"postgresDB/internal/domain/entities"
+	"postgresDB/pkg/pagination"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// OrderFilter holds the optional filters for listing orders, nil and zero fields match
+// every order
+type OrderFilter struct {
+	// Statuses matches orders in any of them
+	Statuses   []string
+	CustomerID *uuid.UUID
+	// CustomerEmail matches customers whose email starts with it, ignoring case
+	CustomerEmail string
+	// From and To bound the creation time to [From, To)
+	From     time.Time
+	To       time.Time
+	MinTotal *int64
+	MaxTotal *int64
+	// WithItems loads the items of the listed orders too, in one extra query
+	WithItems bool
+}
+
 // OrderRepository defines the interface for order data operations
 type OrderRepository interface {
 	Create(ctx context.Context, order *entities.Order) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Order, error)
+	// GetByIDForUpdate retrieves an order with its items, locked until the transaction ends
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entities.Order, error)
 	GetByIDWithItems(ctx context.Context, id uuid.UUID) (*entities.Order, error)
-	GetByCustomerID(ctx context.Context, customerID uuid.UUID, limit, offset int, status string) ([]*entities.Order, int64, error)
+	GetByIDsWithItems(ctx context.Context, ids []uuid.UUID) ([]*entities.Order, error)
+	// GetByCustomerID lists the orders of a customer matching the filter, newest first
+	GetByCustomerID(ctx context.Context, customerID uuid.UUID, limit, offset int, filter OrderFilter) ([]*entities.Order, int64, error)
+	// UpdateStatus changes the status and appends it to the status history
 	UpdateStatus(ctx context.Context, id uuid.UUID, newStatus entities.OrderStatus) error
+	GetStatusHistory(ctx context.Context, orderID uuid.UUID) ([]entities.OrderStatusEvent, error)
 	CreateOrderItem(ctx context.Context, item *entities.OrderItem) error
 	GetOrderItemsByOrderID(ctx context.Context, orderID uuid.UUID) ([]entities.OrderItem, error)
-	ListAll(ctx context.Context, limit, offset int, status string) ([]*entities.Order, int64, error)
+	// ListAll lists the orders matching the filter, newest first
+	ListAll(ctx context.Context, limit, offset int, filter OrderFilter) ([]*entities.Order, int64, error)
+	// ListAfter returns up to limit orders matching the filter ordered after the cursor
+	ListAfter(ctx context.Context, limit int, after *pagination.Cursor, filter OrderFilter) ([]*entities.Order, error)
+	// CustomerStats aggregates the sold orders of a customer with up to categories of their
+	// favorite categories
+	CustomerStats(ctx context.Context, customerID uuid.UUID, categories int) (*entities.CustomerOrderStats, error)
 }
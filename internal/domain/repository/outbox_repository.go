@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxRepository defines the interface for relaying outbox events. Events are added by
+// the order and user repositories, in the transaction of the change they describe
+type OutboxRepository interface {
+	// ClaimPending returns up to limit unpublished events in publishing order. It must run
+	// in a transaction of TxManager, which holds the relay lock until it ends; nil is
+	// returned while another instance holds it
+	ClaimPending(ctx context.Context, limit int) ([]*entities.OutboxEvent, error)
+	// MarkPublished sets the events published at at
+	MarkPublished(ctx context.Context, ids []uuid.UUID, at time.Time) error
+	// MarkFailed counts a failed attempt to publish an event
+	MarkFailed(ctx context.Context, id uuid.UUID, reason string) error
+	// Backlog returns how many events wait to be published and when the oldest was created
+	Backlog(ctx context.Context) (int64, *time.Time, error)
+	// DeletePublishedBefore removes events published before cutoff
+	DeletePublishedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentCaptureRepository defines the interface for payment capture data operations
+type PaymentCaptureRepository interface {
+	// Start moves a pending order to pending_capture and schedules its first capture attempt
+	Start(ctx context.Context, capture *entities.PaymentCapture, note string) error
+	GetByOrderID(ctx context.Context, orderID uuid.UUID) (*entities.PaymentCapture, error)
+	// ListDue returns up to limit pending captures whose next attempt is due
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.PaymentCapture, error)
+	// Reschedule saves a failed attempt and adds the note to the order status history
+	Reschedule(ctx context.Context, capture *entities.PaymentCapture, note string) error
+	// ListCaptured returns the captured payments of the period [from, to) and of the
+	// given orders, with the order total as amount
+	ListCaptured(ctx context.Context, from, to time.Time, orderIDs []uuid.UUID) ([]entities.RecordedPayment, error)
+	// Finish closes the capture and moves the order to orderStatus; in the same transaction
+	// a paid order's stock reservations are deducted and a cancelled order's released
+	Finish(ctx context.Context, capture *entities.PaymentCapture, orderStatus entities.OrderStatus, note string) error
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// PaymentRepository defines the interface for payment data operations
+type PaymentRepository interface {
+	Create(ctx context.Context, payment *entities.Payment) error
+	// GetLatestByOrderID returns the most recent payment of an order
+	GetLatestByOrderID(ctx context.Context, orderID uuid.UUID) (*entities.Payment, error)
+	// ListByOrderID returns the payments of an order, oldest first
+	ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]*entities.Payment, error)
+	// ApplyEvent records a webhook event of provider once and moves its pending payment to
+	// the event status. In the same transaction a succeeded payment moves its pending order
+	// to paid, deducting the reserved stock, and reports it in orderPaid. payment is nil for
+	// an event seen before and for one that doesn't change the payment
+	ApplyEvent(ctx context.Context, provider string, event *entities.PaymentEvent, note string) (payment *entities.Payment, orderPaid bool, err error)
+}
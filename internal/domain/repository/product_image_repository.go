@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// ProductImageRepository defines the interface for product image data operations
+type ProductImageRepository interface {
+	Create(ctx context.Context, image *entities.ProductImage) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.ProductImage, error)
+	ListByProductID(ctx context.Context, productID uuid.UUID) ([]entities.ProductImage, error)
+	ListByProductIDs(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]entities.ProductImage, error)
+	SetPrimary(ctx context.Context, productID, imageID uuid.UUID) error
+	Reorder(ctx context.Context, productID uuid.UUID, imageIDs []uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -3,16 +3,108 @@ package repository
 import (
 	"context"
 	"postgresDB/internal/domain/entities"
+	"postgresDB/pkg/pagination"
+	"postgresDB/pkg/query"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// ArchivedScope selects how archived (soft deleted) products are treated by a filter
+type ArchivedScope = DeletedScope
+
+const (
+	// ArchivedExclude hides archived products, the default
+	ArchivedExclude = DeletedExclude
+	// ArchivedInclude returns archived and active products, e.g. for order history
+	ArchivedInclude = DeletedInclude
+	// ArchivedOnly returns archived products only
+	ArchivedOnly = DeletedOnly
+)
+
+// ProductFilter holds the optional filters for listing products
+type ProductFilter struct {
+	IDs         []uuid.UUID
+	CategoryIDs []uuid.UUID
+	Search      string
+	Categories  []string
+	MinPrice    *int64
+	MaxPrice    *int64
+	InStock     bool
+	// Attributes maps a filterable attribute code to the accepted values
+	Attributes map[string][]string
+	// Tags holds tag slugs, a product matches when it has any of them
+	Tags     []string
+	Archived ArchivedScope
+	// Sort orders offset listings, newest first when empty; keyset listings ignore it
+	Sort query.Sort
+}
+
 // ProductRepository defines the interface for product data operations
 type ProductRepository interface {
 	Create(ctx context.Context, product *entities.Product) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Product, error)
+	// GetCurrent reads the product from the primary, never from a cache or a replica, so
+	// an update based on it starts from the latest version
+	GetCurrent(ctx context.Context, id uuid.UUID) (*entities.Product, error)
+	// GetByIDs returns the active products among ids in one query, in no particular order
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Product, error)
+	// GetByIDsForUpdate returns the active products among ids locked until the end of the
+	// transaction of ctx, see TxManager. Rows are locked in ID order
+	GetByIDsForUpdate(ctx context.Context, ids []uuid.UUID) ([]*entities.Product, error)
 	Update(ctx context.Context, product *entities.Product) error
+	// Delete archives the product, it's kept for order history
 	Delete(ctx context.Context, id uuid.UUID) error
-	List(ctx context.Context, limit, offset int, search, category string) ([]*entities.Product, int64, error)
-	UpdateStock(ctx context.Context, id uuid.UUID, newStock int) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	// PurgeArchived deletes up to limit products archived before the cutoff that no order
+	// refers to, with their details. It returns the number deleted and the storage keys of
+	// their images, whose files are left to the caller
+	PurgeArchived(ctx context.Context, before time.Time, limit int) (int, []string, error)
+	List(ctx context.Context, limit, offset int, filter ProductFilter) ([]*entities.Product, int64, error)
+	// ListPage returns the same page as List without counting the matching products
+	ListPage(ctx context.Context, limit, offset int, filter ProductFilter) ([]*entities.Product, error)
+	// EstimateCount returns an estimate of the products matching the filter, cheap on
+	// large catalogs. exact reports whether the count was actually taken
+	EstimateCount(ctx context.Context, filter ProductFilter) (count int64, exact bool, err error)
+	// ListAfter returns up to limit products ordered after the cursor, or from the start when nil
+	ListAfter(ctx context.Context, limit int, after *pagination.Cursor, filter ProductFilter) ([]*entities.Product, error)
+	// UpdateStock adds delta, negative to take stock out, to the stock in one statement and
+	// returns the level before and after. It fails with ErrInsufficientStock when the stock
+	// would go below zero
+	UpdateStock(ctx context.Context, id uuid.UUID, delta int) (*entities.StockLevel, error)
+	// DecrementStock takes qty out of the stock in one conditional statement and returns
+	// the stock left. It fails with ErrInsufficientStock, changing nothing, when less than
+	// qty is available: stock held by reservations can't be taken. Reserved stock is taken
+	// by StockReservationRepository.Convert
+	DecrementStock(ctx context.Context, id uuid.UUID, qty int) (int, error)
+	// ListLowStock returns active products at or below their reorder threshold
+	ListLowStock(ctx context.Context, limit, offset int) ([]*entities.Product, int64, error)
+	// FindAll returns every product matching the filter, without pagination
+	FindAll(ctx context.Context, filter ProductFilter) ([]*entities.Product, error)
+	// UpdatePrices applies all price changes atomically
+	UpdatePrices(ctx context.Context, changes []entities.PriceChange) error
+	// UpsertBySKU inserts or updates the products by SKU atomically, sending batchSize rows
+	// per round trip. It reports per product whether it was created
+	UpsertBySKU(ctx context.Context, products []*entities.Product, batchSize int) ([]bool, error)
+	// RestoreFromSnapshot resets price and/or stock to the snapshot values, returning
+	// the number of products restored
+	RestoreFromSnapshot(ctx context.Context, items []entities.SnapshotItem, restorePrice, restoreStock bool) (int, error)
+	// ListRelated returns up to limit active products of the same category as id, the ones
+	// most often bought together with it first
+	ListRelated(ctx context.Context, id uuid.UUID, limit int) ([]*entities.Product, error)
+	// RefreshRelated recomputes the co-purchase counts used by ListRelated
+	RefreshRelated(ctx context.Context) error
+	// References scans the orders, reservations and details referring to the product
+	References(ctx context.Context, id uuid.UUID) (*entities.ProductReferences, error)
+	// InventorySummary aggregates the stock and value of all active products
+	InventorySummary(ctx context.Context) (*entities.InventorySummary, error)
+	// CategoryStats aggregates the active products per category, largest first
+	CategoryStats(ctx context.Context) ([]entities.CategoryStats, error)
+	// TopSellers returns up to limit products by quantity sold in orders placed in
+	// [from, to), cancelled and unpaid orders excluded
+	TopSellers(ctx context.Context, from, to time.Time, limit int) ([]entities.ProductSales, error)
+	// WeeklySales returns the quantity of each product sold per week in orders placed in the
+	// weeks weeks since from, oldest first, counted like TopSellers. Every product of ids
+	// gets a series, zero when it sold nothing
+	WeeklySales(ctx context.Context, ids []uuid.UUID, from time.Time, weeks int) (map[uuid.UUID][]int64, error)
 }
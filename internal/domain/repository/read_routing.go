@@ -0,0 +1,19 @@
+package repository
+
+import "context"
+
+// staleReadsKey is the context key marking calls that may read from a replica
+type staleReadsKey struct{}
+
+// AllowStaleReads marks ctx as fine with reads from a read replica, which may lag behind
+// the primary by a moment. Without the mark repositories read from the primary, so a
+// caller reading back what it just wrote sees it
+func AllowStaleReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, staleReadsKey{}, true)
+}
+
+// StaleReadsAllowed reports whether ctx was marked with AllowStaleReads
+func StaleReadsAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(staleReadsKey{}).(bool)
+	return allowed
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// ReconciliationRepository defines the interface for reconciliation runs and the review queue
+type ReconciliationRepository interface {
+	// CreateRun saves a run together with its issues
+	CreateRun(ctx context.Context, run *entities.ReconciliationRun, issues []entities.ReconciliationIssue) error
+	ListRuns(ctx context.Context, limit, offset int) ([]*entities.ReconciliationRun, int64, error)
+	// LatestRun returns the most recent run, or nil when there is none
+	LatestRun(ctx context.Context) (*entities.ReconciliationRun, error)
+	// ListIssues lists issues with the given status, all issues when status is empty
+	ListIssues(ctx context.Context, status string, limit, offset int) ([]*entities.ReconciliationIssue, int64, error)
+	// CountOpenIssues counts the open issues per kind
+	CountOpenIssues(ctx context.Context) (map[entities.ReconciliationIssueKind]int, error)
+	// ResolveIssue closes an open issue
+	ResolveIssue(ctx context.Context, id, resolvedBy uuid.UUID, note string) (*entities.ReconciliationIssue, error)
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// RefundRepository defines the interface for refund data operations
+type RefundRepository interface {
+	// Create records a pending refund, filling in the product of each item. It locks the
+	// order and refuses with ErrRefundExceedsOrder an amount or item quantity beyond what
+	// pending and succeeded refunds left, and with ErrOrderNotRefundable an order that
+	// can't be refunded
+	Create(ctx context.Context, refund *entities.Refund) error
+	// Complete marks a pending refund succeeded and puts its items back into stock. In the
+	// same transaction an order refunded in full moves to refunded, reported in orderRefunded
+	Complete(ctx context.Context, refund *entities.Refund, note string) (orderRefunded bool, err error)
+	// Fail marks a pending refund failed, its amount and items can be refunded again
+	Fail(ctx context.Context, refund *entities.Refund) error
+	// RefundedQuantities returns the quantity of each order item, by order item ID, that
+	// succeeded refunds of the order already put back into stock
+	RefundedQuantities(ctx context.Context, orderID uuid.UUID) (map[uuid.UUID]int, error)
+	// ListByOrderID returns the refunds of an order with their items, oldest first
+	ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]*entities.Refund, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// ShipmentRepository defines the interface for the shipments of orders
+type ShipmentRepository interface {
+	// Create records a shipment, which becomes the order's carrier and tracking number
+	// unless a later one was recorded
+	Create(ctx context.Context, shipment *entities.Shipment) error
+	// ListByOrderID returns the shipments of an order, oldest first
+	ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]entities.Shipment, error)
+}
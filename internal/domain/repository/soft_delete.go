@@ -0,0 +1,14 @@
+package repository
+
+// DeletedScope selects how soft deleted rows, those with a deleted_at, are treated by a
+// query. Repositories exclude them unless asked otherwise
+type DeletedScope int
+
+const (
+	// DeletedExclude hides soft deleted rows, the default
+	DeletedExclude DeletedScope = iota
+	// DeletedInclude returns soft deleted and live rows, e.g. for admins reading history
+	DeletedInclude
+	// DeletedOnly returns soft deleted rows only
+	DeletedOnly
+)
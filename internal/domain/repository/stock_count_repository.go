@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// StockCountRepository defines the interface for stock count operations
+type StockCountRepository interface {
+	// Create opens a count session with the products stocked in the warehouse, narrowed
+	// to the category when set, expecting their current stock
+	Create(ctx context.Context, count *entities.StockCount) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.StockCount, error)
+	// List returns the count sessions with status, all of them for "", newest first, and
+	// their total count. Items are not loaded
+	List(ctx context.Context, status entities.StockCountStatus, limit, offset int) ([]*entities.StockCount, int64, error)
+	// RecordCounts saves counted quantities of a session that is counting. A product that
+	// wasn't part of the session is added expecting its current stock
+	RecordCounts(ctx context.Context, id, userID uuid.UUID, items []entities.StockCountItem) error
+	// Submit sends a session with every product counted for approval
+	Submit(ctx context.Context, id, userID uuid.UUID) error
+	// Approve posts the variances of a submitted session to the stock ledger
+	Approve(ctx context.Context, id, userID uuid.UUID, note string) error
+	// Reject drops a session that is counting or submitted
+	Reject(ctx context.Context, id, userID uuid.UUID, note string) error
+}
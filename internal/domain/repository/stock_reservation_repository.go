@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockReservationRepository defines the interface for stock reservation data operations
+type StockReservationRepository interface {
+	// Reserve holds the stock of all reservations or, when one product lacks available
+	// stock, none of them. The returned levels are the available stock before and after
+	Reserve(ctx context.Context, reservations []entities.StockReservation) ([]*entities.StockLevel, error)
+	// Convert deducts the active reservations of an order from the stock and the reserved
+	// stock. Every way an order gets paid converts its reservations like this, stock an
+	// admin lowered below the reservation in the meantime bottoms out at zero
+	Convert(ctx context.Context, orderID uuid.UUID) ([]*entities.StockLevel, error)
+	// Release gives back the active reservations of an order and returns how many there were
+	Release(ctx context.Context, orderID uuid.UUID) (int, error)
+	// ReleaseExpired gives back the orders of up to limit reservations that expired before
+	// now and are still pending, cancels those orders and returns their IDs
+	ReleaseExpired(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error)
+	// CancelPendingWithProduct cancels the pending orders containing the product, gives
+	// back their reservations and returns the cancelled order IDs
+	CancelPendingWithProduct(ctx context.Context, productID uuid.UUID) ([]uuid.UUID, error)
+}
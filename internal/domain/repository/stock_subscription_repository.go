@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// StockSubscriptionRepository defines the interface for back-in-stock subscriptions
+type StockSubscriptionRepository interface {
+	// Subscribe stores the subscription, reporting false when it already existed
+	Subscribe(ctx context.Context, userID, productID uuid.UUID) (bool, error)
+	Unsubscribe(ctx context.Context, userID, productID uuid.UUID) error
+	// ListByUser returns the subscriptions of a user with their product, newest first
+	ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.StockSubscription, int64, error)
+	// ListSubscribers returns up to limit active users subscribed to the product, oldest
+	// subscription first
+	ListSubscribers(ctx context.Context, productID uuid.UUID, limit int) ([]entities.StockSubscriber, error)
+	// Remove deletes the subscriptions of the users to the product
+	Remove(ctx context.Context, productID uuid.UUID, userIDs []uuid.UUID) error
+	// ProductsWithSubscribers returns the products among ids that have a subscriber
+	ProductsWithSubscribers(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// StockTransferRepository defines the interface for stock transfer operations
+type StockTransferRepository interface {
+	// Create saves a draft transfer with its items
+	Create(ctx context.Context, transfer *entities.StockTransfer) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.StockTransfer, error)
+	// List returns the transfers in one of statuses, oldest first, and their total count
+	List(ctx context.Context, statuses []entities.TransferStatus, limit, offset int) ([]*entities.StockTransfer, int64, error)
+	// Ship takes the items of a draft transfer out of the source warehouse through the
+	// stock ledger and puts the transfer in transit
+	Ship(ctx context.Context, id, userID uuid.UUID) (*entities.StockTransfer, error)
+	// Receive books the items of a transfer in transit into the destination warehouse
+	// through the stock ledger
+	Receive(ctx context.Context, id, userID uuid.UUID) (*entities.StockTransfer, error)
+	// Cancel drops a draft transfer
+	Cancel(ctx context.Context, id uuid.UUID) (*entities.StockTransfer, error)
+}
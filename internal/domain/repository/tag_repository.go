@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// TagRepository defines the interface for tag and product tag operations
+type TagRepository interface {
+	Create(ctx context.Context, tag *entities.Tag) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Tag, error)
+	// GetBySlugs returns the tags with the given slugs, unknown slugs are left out
+	GetBySlugs(ctx context.Context, slugs []string) ([]*entities.Tag, error)
+	List(ctx context.Context) ([]*entities.Tag, error)
+	Update(ctx context.Context, tag *entities.Tag) error
+	// Delete removes the tag from all products
+	Delete(ctx context.Context, id uuid.UUID) error
+	// SetProductTags replaces all tags of a product
+	SetProductTags(ctx context.Context, productID uuid.UUID, tagIDs []uuid.UUID) error
+	ListByProductIDs(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]entities.Tag, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// TrackingTokenRepository defines the interface for public order tracking tokens
+type TrackingTokenRepository interface {
+	Create(ctx context.Context, token *entities.TrackingToken) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.TrackingToken, error)
+	// RevokeByOrderID revokes every active token of an order, returning how many were revoked
+	RevokeByOrderID(ctx context.Context, orderID uuid.UUID) (int64, error)
+}
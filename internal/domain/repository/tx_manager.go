@@ -0,0 +1,15 @@
+package repository
+
+import "context"
+
+// TxManager runs several repository calls as one unit of work
+type TxManager interface {
+	// WithinTx runs fn in a transaction, committed when fn returns nil and rolled back
+	// otherwise. Repository calls made with the context passed to fn join the
+	// transaction; a nested WithinTx joins the outer one. A transaction failing with a
+	// transient error, such as a serialization failure, runs fn again from the start
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	// WithinTxNoRetry runs fn in a transaction like WithinTx, but never runs fn again after a
+	// transient failure. Use it when fn calls hooks or publishes outside the database
+	WithinTxNoRetry(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -10,12 +10,28 @@ import (
 type UserRepository interface {
 	// Define repository methods here
 	Create(ctx context.Context, user *entities.User) error
+	// GetByID returns the user unless it's deleted
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error)
+	// GetByIDScoped returns the user among the users of scope, admins use it to read
+	// deleted users
+	GetByIDScoped(ctx context.Context, id uuid.UUID, scope DeletedScope) (*entities.User, error)
 	GetByEmail(ctx context.Context, email string) (*entities.User, error)
 	GetByUsername(ctx context.Context, username string) (*entities.User, error)
 	GetByEmailOrUsername(ctx context.Context, loginID string) (*entities.User, error)
+	// ListActiveByRole returns the active users with the given role
+	ListActiveByRole(ctx context.Context, role entities.Role) ([]*entities.User, error)
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
 	ExistsByUsername(ctx context.Context, username string) (bool, error)
 	Update(ctx context.Context, user *entities.User) error
+	UpdatePassword(ctx context.Context, id uuid.UUID, hashedPassword string) error
+	// Delete soft deletes the user, queries skip it from then on
 	Delete(ctx context.Context, id uuid.UUID) error
+	// Restore brings a deleted user back
+	Restore(ctx context.Context, id uuid.UUID) error
+	// GetOrCreateGuest returns the guest customer with the email, creating it on the first
+	// guest order
+	GetOrCreateGuest(ctx context.Context, email string) (*entities.User, error)
+	// ClaimGuest moves the orders of a guest to a registered user and removes the guest,
+	// returning the number of orders moved
+	ClaimGuest(ctx context.Context, guestID, userID uuid.UUID) (int64, error)
 }
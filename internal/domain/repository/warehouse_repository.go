@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// WarehouseRepository defines the interface for warehouse and stock ledger operations
+type WarehouseRepository interface {
+	Create(ctx context.Context, warehouse *entities.Warehouse) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Warehouse, error)
+	List(ctx context.Context) ([]*entities.Warehouse, error)
+	// ListStock returns the products stocked in a warehouse by name and their total count
+	ListStock(ctx context.Context, warehouseID uuid.UUID, limit, offset int) ([]*entities.WarehouseStock, int64, error)
+	// GetStock returns the on-hand quantity of the products in a warehouse, products
+	// never stocked there are left out
+	GetStock(ctx context.Context, warehouseID uuid.UUID, productIDs []uuid.UUID) (map[uuid.UUID]int, error)
+	// Post applies the movements to the warehouse stock and records them in the ledger,
+	// all or none of them. A movement that would leave a negative stock fails the post
+	Post(ctx context.Context, entries []entities.StockLedgerEntry) ([]*entities.StockLedgerEntry, error)
+	// ListLedger returns the ledger entries matching the filter, newest first, and their total count
+	ListLedger(ctx context.Context, filter entities.StockLedgerFilter, limit, offset int) ([]*entities.StockLedgerEntry, int64, error)
+}
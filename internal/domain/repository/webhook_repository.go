@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// WebhookRepository defines the interface for webhook endpoint and delivery operations
+type WebhookRepository interface {
+	Create(ctx context.Context, endpoint *entities.WebhookEndpoint) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.WebhookEndpoint, error)
+	// List returns the endpoints newest first
+	List(ctx context.Context, limit, offset int) ([]*entities.WebhookEndpoint, int64, error)
+	// ListSubscribed returns the active endpoints subscribed to event
+	ListSubscribed(ctx context.Context, event string) ([]*entities.WebhookEndpoint, error)
+	// Update saves the URL, description, events and active flag
+	Update(ctx context.Context, endpoint *entities.WebhookEndpoint) error
+	// Delete removes an endpoint with its delivery log
+	Delete(ctx context.Context, id uuid.UUID) error
+	// RecordDelivery saves a delivery attempt and sets its attempt number
+	RecordDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error
+	// ListDeliveries returns the delivery attempts of an endpoint, newest first
+	ListDeliveries(ctx context.Context, endpointID uuid.UUID, limit, offset int) ([]*entities.WebhookDelivery, int64, error)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// AccessLogRecorder receives the access log of every API request
+type AccessLogRecorder interface {
+	// Record queues the access log for storage without blocking the request
+	Record(log entities.AccessLog)
+}
+
+// AccessLogService defines the interface for storing access logs and exporting them
+type AccessLogService interface {
+	AccessLogRecorder
+	// Run stores the recorded access logs in batches and removes expired logs and
+	// exports until ctx is done
+	Run(ctx context.Context)
+	// HandleExport is the job handler writing the file of an export
+	HandleExport(ctx context.Context, payload json.RawMessage) error
+	RequestExport(ctx context.Context, requester uuid.UUID, role entities.Role, req *dto.CreateAccessLogExportRequest) (*dto.AccessLogExportResponse, error)
+	GetExport(ctx context.Context, id, requester uuid.UUID, role entities.Role) (*dto.AccessLogExportResponse, error)
+	ListExports(ctx context.Context, requester uuid.UUID, role entities.Role, page, limit int) ([]dto.AccessLogExportResponse, *dto.PaginationMeta, error)
+	// OpenExport returns the file of a completed export
+	OpenExport(ctx context.Context, id, requester uuid.UUID, role entities.Role) (*dto.AccessLogExportFile, error)
+}
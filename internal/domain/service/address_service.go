@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+// AddressService defines the interface for the address book of the signed-in user
+type AddressService interface {
+	// Create adds an address; the first address of a user becomes the default
+	Create(ctx context.Context, userID uuid.UUID, req *dto.CreateAddressRequest) (*dto.AddressResponse, error)
+	List(ctx context.Context, userID uuid.UUID) ([]dto.AddressResponse, error)
+	Update(ctx context.Context, id, userID uuid.UUID, req *dto.UpdateAddressRequest) (*dto.AddressResponse, error)
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIClientService defines the interface for self-service API client registration
+type APIClientService interface {
+	// Create registers a client, the response holds the only copy of its secret
+	Create(ctx context.Context, ownerID uuid.UUID, req *dto.CreateAPIClientRequest) (*dto.APIClientResponse, error)
+	Get(ctx context.Context, id, requester uuid.UUID, role entities.Role) (*dto.APIClientResponse, error)
+	List(ctx context.Context, requester uuid.UUID, role entities.Role, page, limit int) ([]dto.APIClientResponse, *dto.PaginationMeta, error)
+	Update(ctx context.Context, id, requester uuid.UUID, role entities.Role, req *dto.UpdateAPIClientRequest) (*dto.APIClientResponse, error)
+	// RotateSecret replaces the secret, the old one stops working right away
+	RotateSecret(ctx context.Context, id, requester uuid.UUID, role entities.Role) (*dto.APIClientResponse, error)
+	Delete(ctx context.Context, id, requester uuid.UUID, role entities.Role) error
+	// Usage reports the daily traffic sent with the client ID over [from, to)
+	Usage(ctx context.Context, id, requester uuid.UUID, role entities.Role, from, to time.Time) (*dto.APIClientUsageResponse, error)
+}
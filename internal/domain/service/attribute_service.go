@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+// AttributeService defines the interface for managing category attribute definitions
+type AttributeService interface {
+	Create(ctx context.Context, categoryID uuid.UUID, req *dto.CreateAttributeRequest) (*dto.AttributeResponse, error)
+	ListByCategory(ctx context.Context, categoryID uuid.UUID) ([]dto.AttributeResponse, error)
+	Update(ctx context.Context, id uuid.UUID, req *dto.UpdateAttributeRequest) (*dto.AttributeResponse, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+// AuthStateBackupService defines the interface for backing up and restoring the Redis
+// session and token blacklist state
+type AuthStateBackupService interface {
+	// Backup writes the current auth state to storage; requestedBy is nil for scheduled backups
+	Backup(ctx context.Context, trigger string, requestedBy *uuid.UUID) (*dto.AuthStateBackupResponse, error)
+	ListBackups(ctx context.Context, page, limit int) ([]dto.AuthStateBackupResponse, *dto.PaginationMeta, error)
+	// Restore writes the entries of a completed backup back to Redis
+	Restore(ctx context.Context, id, restoredBy uuid.UUID) (*dto.AuthStateRestoreResponse, error)
+	ListRestores(ctx context.Context, page, limit int) ([]dto.AuthStateRestoreResponse, *dto.PaginationMeta, error)
+	// RunSchedule takes a backup every interval and expires old backups until ctx is done
+	RunSchedule(ctx context.Context)
+}
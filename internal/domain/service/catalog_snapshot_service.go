@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+// CatalogSnapshotService defines the interface for catalog snapshots and rollback
+type CatalogSnapshotService interface {
+	Capture(ctx context.Context, trigger string) (*dto.CatalogSnapshotResponse, error)
+	List(ctx context.Context, page, limit int) ([]dto.CatalogSnapshotResponse, *dto.PaginationMeta, error)
+	// Diff compares two snapshots, or a snapshot against the current catalog when to is nil
+	Diff(ctx context.Context, from uuid.UUID, to *uuid.UUID) (*dto.CatalogDiffResponse, error)
+	Rollback(ctx context.Context, id uuid.UUID, req *dto.SnapshotRollbackRequest) (*dto.SnapshotRollbackResponse, error)
+	// RunSchedule takes a snapshot every day at the configured time until ctx is done
+	RunSchedule(ctx context.Context)
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+type CategoryService interface {
+	Create(ctx context.Context, req *dto.CreateCategoryRequest) (*dto.CategoryResponse, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*dto.CategoryResponse, error)
+	List(ctx context.Context) ([]dto.CategoryResponse, error)
+	Tree(ctx context.Context) ([]dto.CategoryTreeResponse, error)
+	Update(ctx context.Context, id uuid.UUID, req *dto.UpdateCategoryRequest) (*dto.CategoryResponse, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// ConsentChecker tells whether a user agreed to a purpose. Modules that email, text or
+// analyse users check it before doing so
+type ConsentChecker interface {
+	HasConsent(ctx context.Context, userID uuid.UUID, purpose entities.ConsentPurpose) (bool, error)
+}
+
+// ConsentService defines the interface for the consents of the signed-in user
+type ConsentService interface {
+	ConsentChecker
+	// List returns the consent for every purpose, defaults included
+	List(ctx context.Context, userID uuid.UUID) ([]dto.ConsentResponse, error)
+	// Update records the choices of the request made from ip with userAgent
+	Update(ctx context.Context, userID uuid.UUID, req *dto.UpdateConsentsRequest, ip, userAgent string) ([]dto.ConsentResponse, error)
+	// History returns the consent records of a user, newest first
+	History(ctx context.Context, userID uuid.UUID, page, limit int) ([]dto.ConsentRecordResponse, *dto.PaginationMeta, error)
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+// EmailTemplateService defines the interface for managing and rendering email templates
+type EmailTemplateService interface {
+	// Create stores a new draft version, the template must parse
+	Create(ctx context.Context, adminID uuid.UUID, req *dto.CreateEmailTemplateRequest) (*dto.EmailTemplateResponse, error)
+	Get(ctx context.Context, id uuid.UUID) (*dto.EmailTemplateResponse, error)
+	List(ctx context.Context, key, locale string, page, limit int) ([]dto.EmailTemplateResponse, *dto.PaginationMeta, error)
+	// Publish makes the version the one sent for its key and locale
+	Publish(ctx context.Context, id uuid.UUID) (*dto.EmailTemplateResponse, error)
+	// Preview renders any version, drafts included, with sample data
+	Preview(ctx context.Context, id uuid.UUID, req *dto.PreviewEmailTemplateRequest) (*dto.RenderedEmailResponse, error)
+	// SendTest renders a version and mails it to a test address
+	SendTest(ctx context.Context, id uuid.UUID, req *dto.SendTestEmailRequest) error
+	// Render renders the active version of key, falling back from the locale to its
+	// base language and then to the default locale
+	Render(ctx context.Context, key, locale string, data any) (*dto.RenderedEmailResponse, error)
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+)
+
+// FieldUsageRecorder receives the fields used by sampled API requests
+type FieldUsageRecorder interface {
+	// Record counts one use of each of fields without blocking the request
+	Record(clientID, route string, direction entities.FieldDirection, fields []string)
+}
+
+// FieldUsageService defines the interface for field usage analytics, telling whether a
+// legacy field is still used before removing it
+type FieldUsageService interface {
+	FieldUsageRecorder
+	// Run stores the recorded usage every flush interval until ctx is done
+	Run(ctx context.Context)
+	// Report returns the usage matching the filter per field, with the clients using it
+	Report(ctx context.Context, filter entities.FieldUsageFilter) ([]dto.FieldUsageResponse, error)
+}
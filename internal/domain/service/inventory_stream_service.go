@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+// InventoryStreamService defines the interface for streaming stock changes of watched products
+type InventoryStreamService interface {
+	// Run relays stock events from the event bus to the watchers until ctx is done
+	Run(ctx context.Context)
+	// Watch returns the current stock of productIDs and a channel of their later changes.
+	// The channel is closed when ctx is done or the watcher falls behind, clients then
+	// reconnect for a fresh snapshot
+	Watch(ctx context.Context, productIDs []uuid.UUID) ([]dto.StockLevelResponse, <-chan dto.StockEventResponse, error)
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"postgresDB/internal/domain/dto"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobHandler handles the payload of one job type. A returned error retries the job
+type JobHandler func(ctx context.Context, payload json.RawMessage) error
+
+// JobQueue enqueues work to be run by the background workers
+type JobQueue interface {
+	// Enqueue stores a job of jobType on queue, payload is encoded as JSON
+	Enqueue(ctx context.Context, queue, jobType string, payload any) (uuid.UUID, error)
+}
+
+// JobService defines the interface for running and administering background jobs
+type JobService interface {
+	JobQueue
+	// Register sets the handler of a job type, it must be called before Run
+	Register(jobType string, handler JobHandler)
+	// Run processes jobs of the configured queues until ctx is done
+	Run(ctx context.Context)
+	Get(ctx context.Context, id uuid.UUID) (*dto.JobResponse, error)
+	ListFailed(ctx context.Context, queue string, page, limit int) ([]dto.JobResponse, *dto.PaginationMeta, error)
+	// Retry puts a failed job back in its queue with fresh attempts
+	Retry(ctx context.Context, id uuid.UUID) (*dto.JobResponse, error)
+	// Cancel drops a queued or failed job
+	Cancel(ctx context.Context, id uuid.UUID) (*dto.JobResponse, error)
+	// Stats returns the backlog and throughput of every queue over the window
+	Stats(ctx context.Context, window time.Duration) ([]dto.QueueStatsResponse, error)
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// Notifier sends a user a notification on every channel they didn't turn it off on. Modules
+// that tell users about their orders or account call it instead of a mailer
+type Notifier interface {
+	// Notify queues the notification, data is what its template is rendered with
+	Notify(ctx context.Context, userID uuid.UUID, kind entities.NotificationKind, data map[string]any) error
+}
+
+// NotificationService defines the interface for notifications and the preferences of the
+// signed-in user
+type NotificationService interface {
+	Notifier
+	// Preferences returns the preference for every kind on every channel, defaults included
+	Preferences(ctx context.Context, userID uuid.UUID) ([]dto.NotificationPreferenceResponse, error)
+	// UpdatePreferences saves the preferences of the request
+	UpdatePreferences(ctx context.Context, userID uuid.UUID, req *dto.UpdateNotificationPreferencesRequest) ([]dto.NotificationPreferenceResponse, error)
+	// Deliver is the JobHandler sending one notification on one channel, failures are retried
+	Deliver(ctx context.Context, payload json.RawMessage) error
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+// OAuthService defines the interface for the OAuth 2.0 / OpenID Connect provider,
+// registered API clients sign users in with the authorization code flow and PKCE
+type OAuthService interface {
+	// Consent validates an authorization request and describes it for the consent page
+	Consent(ctx context.Context, req *dto.AuthorizeRequest) (*dto.AuthorizeConsentResponse, error)
+	// Authorize issues an authorization code to the client for the user
+	Authorize(ctx context.Context, userID uuid.UUID, req *dto.AuthorizeRequest) (*dto.AuthorizeResponse, error)
+	// Token redeems an authorization code or refresh token, errors are *apperror.OAuthError
+	Token(ctx context.Context, req *dto.OAuthTokenRequest) (*dto.OAuthTokenResponse, error)
+	// UserInfo returns the claims of the user an OAuth access token was issued for
+	UserInfo(ctx context.Context, accessToken string) (*dto.UserInfoResponse, error)
+	Discovery() dto.OpenIDConfiguration
+	JWKS() dto.JWKSet
+}
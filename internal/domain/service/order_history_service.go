@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderHistoryService defines the interface for looking up past states of orders
+type OrderHistoryService interface {
+	// AsOf rebuilds the order as it was at the given time
+	AsOf(ctx context.Context, orderID uuid.UUID, at time.Time) (*dto.OrderAsOfResponse, error)
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+)
+
+// Order hooks let a deployment extend the order lifecycle, e.g. with custom validation or
+// by pushing orders to an ERP, without changing the order services. A hook implements any
+// of the interfaces below and is registered when the services are composed.
+
+// BeforeOrderCreateHook runs before an order is saved, with its items and totals set. The
+// ordered products are locked meanwhile, so it should be quick. An error refuses the
+// order; return an *apperror.AppError to choose the response
+type BeforeOrderCreateHook interface {
+	BeforeOrderCreate(ctx context.Context, order *entities.Order) error
+}
+
+// AfterOrderCreateHook runs after an order is placed. The order stands, errors are only logged
+type AfterOrderCreateHook interface {
+	AfterOrderCreate(ctx context.Context, order *entities.Order) error
+}
+
+// BeforeOrderPaymentHook runs before a payment of an order is started at the payment
+// provider. An error refuses the payment
+type BeforeOrderPaymentHook interface {
+	BeforeOrderPayment(ctx context.Context, order *entities.Order, payment *entities.Payment) error
+}
+
+// AfterOrderPaymentHook runs after the payment provider reported a payment succeeded or
+// failed. Errors are only logged
+type AfterOrderPaymentHook interface {
+	AfterOrderPayment(ctx context.Context, payment *entities.Payment) error
+}
+
+// BeforeOrderStatusChangeHook runs before a user moves an order to another status. An error
+// refuses the change. Changes made by payments, refunds and expired reservations can't be refused
+type BeforeOrderStatusChangeHook interface {
+	BeforeOrderStatusChange(ctx context.Context, order *entities.Order, status entities.OrderStatus) error
+}
+
+// AfterOrderStatusChangeHook runs after an order moved from one status to its current one,
+// whatever moved it. Errors are only logged
+type AfterOrderStatusChangeHook interface {
+	AfterOrderStatusChange(ctx context.Context, order *entities.Order, from entities.OrderStatus) error
+}
@@ -10,8 +10,19 @@ import (
 
 type OrderService interface {
 	Create(ctx context.Context, customerID uuid.UUID, req dto.CreateOrderRequest) (*dto.OrderResponse, error)
+	// CreateAsGuest places an order without an account and emails the guest a tracking link
+	CreateAsGuest(ctx context.Context, req dto.GuestOrderRequest) (*dto.OrderResponse, error)
 	GetByID(ctx context.Context, id uuid.UUID, requesterID uuid.UUID, requesterRole entities.Role) (*dto.OrderResponse, error)
 	//GetByCustomerID(ctx context.Context, customerID uuid.UUID, requesterID uuid.UUID, requesterRole entities.Role, req dto.OrderListRequest) ([]dto.OrderResponse, *dto.PaginationMeta, error)
 	UpdateStatus(ctx context.Context, id uuid.UUID, requesterID uuid.UUID, requesterRole entities.Role, req *dto.UpdateOrderRequest) (*dto.OrderResponse, error)
+	// AddShipment records a parcel of a paid order handed to a carrier
+	AddShipment(ctx context.Context, orderID, adminID uuid.UUID, req dto.CreateShipmentRequest) (*dto.ShipmentResponse, error)
 	ListAll(ctx context.Context, UserID uuid.UUID, requesterRole entities.Role, req dto.OrderListRequest) ([]dto.OrderResponse, *dto.PaginationMeta, error)
+	// PackingSlips builds the packing slips of the given orders, in order date order
+	PackingSlips(ctx context.Context, ids []uuid.UUID) ([]dto.PackingSlip, error)
+	// CustomerStats aggregates the paid orders of a customer, for the customer or an admin
+	CustomerStats(ctx context.Context, customerID, requesterID uuid.UUID, requesterRole entities.Role) (*dto.CustomerOrderStatsResponse, error)
+	ListByCursor(ctx context.Context, userID uuid.UUID, requesterRole entities.Role, req dto.OrderListRequest) ([]dto.OrderResponse, *dto.CursorMeta, error)
+	// SweepExpiredReservations releases expired stock reservations and cancels their unpaid orders
+	SweepExpiredReservations(ctx context.Context) error
 }
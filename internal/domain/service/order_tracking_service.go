@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// OrderTrackingService defines the interface for public order tracking links
+type OrderTrackingService interface {
+	CreateLink(ctx context.Context, orderID, requesterID uuid.UUID, requesterRole entities.Role) (*dto.TrackingLinkResponse, error)
+	// RevokeLinks revokes every tracking link of the order
+	RevokeLinks(ctx context.Context, orderID, requesterID uuid.UUID, requesterRole entities.Role) error
+	Track(ctx context.Context, token string) (*dto.OrderTrackingResponse, error)
+	// TrackOrder returns the tracking view of an order owned by the requester
+	TrackOrder(ctx context.Context, orderID, requesterID uuid.UUID, requesterRole entities.Role) (*dto.OrderTrackingResponse, error)
+	// SendGuestLink is the JobHandler emailing the tracking link of a guest order
+	SendGuestLink(ctx context.Context, payload json.RawMessage) error
+	// ClaimGuestOrders moves the orders of the guest behind a tracking link to the user,
+	// whose email must be the guest's
+	ClaimGuestOrders(ctx context.Context, userID uuid.UUID, token string) (*dto.ClaimGuestOrdersResponse, error)
+}
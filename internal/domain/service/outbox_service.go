@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+)
+
+// EventPublisher publishes outbox events to a message broker
+type EventPublisher interface {
+	// Name identifies the broker, e.g. nats
+	Name() string
+	// Publish returns once the broker accepted the event. An event can be published more
+	// than once, consumers skip duplicates by its ID
+	Publish(ctx context.Context, event *entities.OutboxEvent) error
+	Close() error
+}
+
+// OutboxRelay defines the interface for publishing the outbox to the message broker
+type OutboxRelay interface {
+	// Status returns the backlog of the outbox and the last publishing error
+	Status(ctx context.Context) (*dto.OutboxStatusResponse, error)
+	// Run publishes new events until ctx is done
+	Run(ctx context.Context)
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// PaymentGateway captures order payments at an asynchronous payment provider
+type PaymentGateway interface {
+	// Capture requests or verifies the capture of the order's payment. An error or
+	// CaptureResultPending schedules another attempt
+	Capture(ctx context.Context, order *entities.Order) (entities.CaptureResult, error)
+}
+
+// PaymentCaptureService defines the interface for capturing payments with retries
+type PaymentCaptureService interface {
+	// Start moves a pending order to pending_capture, the capture runs in the background
+	Start(ctx context.Context, orderID uuid.UUID) (*dto.PaymentCaptureResponse, error)
+	Get(ctx context.Context, orderID uuid.UUID) (*dto.PaymentCaptureResponse, error)
+	// RunRetries attempts due captures on every tick until ctx is done
+	RunRetries(ctx context.Context)
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// PaymentProvider creates payments at a payment provider and reads its webhooks
+type PaymentProvider interface {
+	// Name identifies the provider in stored payments, e.g. stripe
+	Name() string
+	// CreateIntent creates a payment for payment.Amount, payment.ID makes retries idempotent
+	CreateIntent(ctx context.Context, payment *entities.Payment) (*entities.PaymentIntent, error)
+	// Refund returns refund.Amount of a succeeded payment and returns the provider's ID of
+	// the refund; refund.ID makes retries idempotent
+	Refund(ctx context.Context, payment *entities.Payment, refund *entities.Refund) (string, error)
+	// ParseWebhook verifies the signature of a webhook and returns its event, or nil for
+	// event types that don't change a payment
+	ParseWebhook(payload []byte, header http.Header) (*entities.PaymentEvent, error)
+}
+
+// PaymentService defines the interface for paying orders at the payment provider
+type PaymentService interface {
+	// CreateIntent starts the payment of a pending order, a pending payment of the same
+	// amount is returned instead of creating another one
+	CreateIntent(ctx context.Context, orderID uuid.UUID, requesterID uuid.UUID, requesterRole entities.Role) (*dto.PaymentResponse, error)
+	// GetByOrder returns the latest payment of an order
+	GetByOrder(ctx context.Context, orderID uuid.UUID) (*dto.PaymentResponse, error)
+	// HandleWebhook applies a provider notification, marking the order paid once the
+	// payment succeeds. Redelivered events are ignored
+	HandleWebhook(ctx context.Context, payload []byte, header http.Header) error
+}
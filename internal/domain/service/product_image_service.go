@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+type ProductImageService interface {
+	Upload(ctx context.Context, productID uuid.UUID, req dto.UploadProductImageRequest) (*dto.ProductImageResponse, error)
+	List(ctx context.Context, productID uuid.UUID) ([]dto.ProductImageResponse, error)
+	SetPrimary(ctx context.Context, productID, imageID uuid.UUID) ([]dto.ProductImageResponse, error)
+	Reorder(ctx context.Context, productID uuid.UUID, req dto.ReorderProductImagesRequest) ([]dto.ProductImageResponse, error)
+	Delete(ctx context.Context, productID, imageID uuid.UUID) error
+}
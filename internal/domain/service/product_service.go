@@ -4,6 +4,7 @@ import (
 	"context"
 	"postgresDB/internal/domain/dto"
 	"postgresDB/internal/domain/entities"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -11,7 +12,32 @@ import (
 type ProductService interface {
 	Create(ctx context.Context, req *dto.CreateProductRequest) (*dto.ProductResponse, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*dto.ProductResponse, error)
+	// GetByIDs looks up several products in one query, reporting the missing ones
+	GetByIDs(ctx context.Context, ids []uuid.UUID) (*dto.BatchProductResponse, error)
 	List(ctx context.Context, req dto.ProductListRequest) ([]dto.ProductResponse, *dto.PaginationMeta, error)
+	ListByCursor(ctx context.Context, req dto.ProductListRequest) ([]dto.ProductResponse, *dto.CursorMeta, error)
 	Update(ctx context.Context, id uuid.UUID, req *dto.UpdateProductRequest, userRole entities.Role) (*dto.ProductResponse, error)
-	Delete(ctx context.Context, id uuid.UUID, userRole entities.Role) error
+	// Delete archives the product, treating its open orders according to policy
+	Delete(ctx context.Context, id uuid.UUID, userRole entities.Role, policy entities.ProductDeletePolicy) (*dto.ProductDeleteReport, error)
+	// References lists what still refers to the product, to preview a delete
+	References(ctx context.Context, id uuid.UUID) (*dto.ProductReferencesResponse, error)
+	ListArchived(ctx context.Context, page, limit int) ([]dto.ProductResponse, *dto.PaginationMeta, error)
+	Restore(ctx context.Context, id uuid.UUID) (*dto.ProductResponse, error)
+	// ListLowStock returns the products at or below their reorder threshold
+	ListLowStock(ctx context.Context, page, limit int) ([]dto.ProductResponse, *dto.PaginationMeta, error)
+	// Stats aggregates the catalog per category and the top sellers of a period
+	Stats(ctx context.Context, req dto.ProductStatsRequest) (*dto.ProductStatsResponse, error)
+	// Forecast estimates the weekly demand of a product from its sales and the quantity to
+	// reorder to cover it
+	Forecast(ctx context.Context, req dto.DemandForecastRequest) (*dto.DemandForecastResponse, error)
+	// Import upserts the products of a CSV or JSON file by SKU and reports the result per row
+	Import(ctx context.Context, req dto.ImportProductsRequest) (*dto.ProductImportReport, error)
+	BulkUpdatePrices(ctx context.Context, req *dto.BulkPriceUpdateRequest) (*dto.BulkPriceUpdateResponse, error)
+	// ListRelated returns products of the same category, the ones most often bought together first
+	ListRelated(ctx context.Context, id uuid.UUID, limit int) ([]dto.ProductResponse, error)
+	// RefreshRelated recomputes the co-purchase counts used by ListRelated
+	RefreshRelated(ctx context.Context) error
+	// PurgeArchived permanently deletes the products archived for longer than retention
+	// that no order refers to
+	PurgeArchived(ctx context.Context, retention time.Duration) error
 }
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SettlementSource fetches settlement reports from a payment provider's API
+type SettlementSource interface {
+	// FetchReport returns the payments the provider settled in [from, to)
+	FetchReport(ctx context.Context, from, to time.Time) (*entities.SettlementReport, error)
+}
+
+// ReconciliationService defines the interface for reconciling payments against provider reports
+type ReconciliationService interface {
+	// ImportReport reconciles an uploaded CSV settlement report
+	ImportReport(ctx context.Context, req dto.UploadSettlementReportRequest) (*dto.ReconciliationRunResponse, error)
+	ListRuns(ctx context.Context, page, limit int) ([]dto.ReconciliationRunResponse, *dto.PaginationMeta, error)
+	ListIssues(ctx context.Context, status string, page, limit int) ([]dto.ReconciliationIssueResponse, *dto.PaginationMeta, error)
+	ResolveIssue(ctx context.Context, id, adminID uuid.UUID, req *dto.ResolveReconciliationIssueRequest) (*dto.ReconciliationIssueResponse, error)
+	Summary(ctx context.Context) (*dto.ReconciliationSummaryResponse, error)
+	// RunSchedule reconciles the previous day every day at the configured time until ctx is done
+	RunSchedule(ctx context.Context)
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+// RefundService defines the interface for refunding paid orders
+type RefundService interface {
+	// Refund returns money of an order through its payment provider, in full or in part,
+	// and puts returned items back into stock. An order refunded in full moves to refunded
+	Refund(ctx context.Context, orderID uuid.UUID, adminID uuid.UUID, req *dto.RefundOrderRequest) (*dto.RefundResponse, error)
+	// List returns the refunds of an order, oldest first
+	List(ctx context.Context, orderID uuid.UUID) ([]dto.RefundResponse, error)
+}
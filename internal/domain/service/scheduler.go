@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// ScheduledTask is one run of a periodic task, a returned error is logged and the task
+// runs again at its next interval
+type ScheduledTask func(ctx context.Context) error
+
+// Scheduler runs periodic maintenance tasks. A task runs at most once per interval across
+// all API instances, whichever instance gets to it first
+type Scheduler interface {
+	// Register adds a task run every interval, it must be called before Run. A
+	// non-positive interval disables the task
+	Register(name string, interval time.Duration, task ScheduledTask)
+	// Run runs the registered tasks until ctx is done
+	Run(ctx context.Context)
+}
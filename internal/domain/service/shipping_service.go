@@ -0,0 +1,20 @@
+package service
+
+import (
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+)
+
+// ShippingCalculator prices the shipment of an order by its weight
+type ShippingCalculator interface {
+	Cost(weightGrams int) int64
+}
+
+// ShippingService defines the interface for the shipping methods offered at checkout
+type ShippingService interface {
+	// Methods lists the configured methods, none when shipping isn't set up
+	Methods() []dto.ShippingMethodResponse
+	// Quote prices shipping weightGrams with a method, the first one when code is empty.
+	// Unknown codes return ErrShippingMethodNotFound
+	Quote(code string, weightGrams int) (*entities.ShippingMethod, int64, error)
+}
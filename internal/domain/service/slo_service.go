@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+)
+
+// SLOService defines the interface for tracking service level objectives and their error budgets
+type SLOService interface {
+	// Report returns the state of every objective as of the last evaluation
+	Report() []dto.SLOResponse
+	// Run evaluates the objectives on every tick until ctx is done
+	Run(ctx context.Context)
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+// StockCountService defines the interface for the stock count workflow
+type StockCountService interface {
+	// Create opens a count session for a warehouse or one category in it
+	Create(ctx context.Context, adminID uuid.UUID, req *dto.CreateStockCountRequest) (*dto.StockCountResponse, error)
+	Get(ctx context.Context, id uuid.UUID) (*dto.StockCountResponse, error)
+	List(ctx context.Context, status string, page, limit int) ([]dto.StockCountResponse, *dto.PaginationMeta, error)
+	RecordCounts(ctx context.Context, id, adminID uuid.UUID, req *dto.RecordStockCountRequest) (*dto.StockCountResponse, error)
+	// Submit sends a fully counted session for approval
+	Submit(ctx context.Context, id, adminID uuid.UUID) (*dto.StockCountResponse, error)
+	// Approve posts the variances to the stock ledger
+	Approve(ctx context.Context, id, adminID uuid.UUID, req *dto.ReviewStockCountRequest) (*dto.StockCountResponse, error)
+	Reject(ctx context.Context, id, adminID uuid.UUID, req *dto.ReviewStockCountRequest) (*dto.StockCountResponse, error)
+}
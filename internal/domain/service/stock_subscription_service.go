@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+// StockSubscriptionService defines the interface for back-in-stock subscriptions
+type StockSubscriptionService interface {
+	// Subscribe asks to be emailed when an out-of-stock product is back in stock
+	Subscribe(ctx context.Context, userID, productID uuid.UUID) (*dto.StockSubscriptionResponse, error)
+	Unsubscribe(ctx context.Context, userID, productID uuid.UUID) error
+	List(ctx context.Context, userID uuid.UUID, page, limit int) ([]dto.StockSubscriptionResponse, *dto.PaginationMeta, error)
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+// StockTransferService defines the interface for moving stock between warehouses
+type StockTransferService interface {
+	Create(ctx context.Context, adminID uuid.UUID, req *dto.CreateStockTransferRequest) (*dto.StockTransferResponse, error)
+	Get(ctx context.Context, id uuid.UUID) (*dto.StockTransferResponse, error)
+	// List returns the transfers with status, "open" for drafts and transfers in transit
+	// and "" for all of them
+	List(ctx context.Context, status string, page, limit int) ([]dto.StockTransferResponse, *dto.PaginationMeta, error)
+	Ship(ctx context.Context, id, adminID uuid.UUID) (*dto.StockTransferResponse, error)
+	Receive(ctx context.Context, id, adminID uuid.UUID) (*dto.StockTransferResponse, error)
+	Cancel(ctx context.Context, id uuid.UUID) (*dto.StockTransferResponse, error)
+}
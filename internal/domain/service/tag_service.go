@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+// TagService defines the interface for managing product tags
+type TagService interface {
+	Create(ctx context.Context, req *dto.CreateTagRequest) (*dto.TagResponse, error)
+	List(ctx context.Context) ([]dto.TagResponse, error)
+	Update(ctx context.Context, id uuid.UUID, req *dto.UpdateTagRequest) (*dto.TagResponse, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,11 @@
+package service
+
+import "postgresDB/internal/domain/entities"
+
+// TaxService defines the interface for the tax rates applied at checkout
+type TaxService interface {
+	// Rate returns the most specific rate for a product category shipped to region: the
+	// rate of both, then of the category, of the region and the default. It returns nil
+	// when no rate applies, the item is then untaxed
+	Rate(region, category string) *entities.TaxRate
+}
@@ -16,12 +16,19 @@ type UserService interface {
 	Update(ctx context.Context, id uuid.UUID, requesterID uuid.UUID, requesterRole entities.Role, req dto.UpdateUserRequest) (*dto.UserResponse, error)
 	ChangePassword(ctx context.Context, id uuid.UUID, requesterID uuid.UUID, req dto.ChangePasswordRequest) error
 	Delete(ctx context.Context, id uuid.UUID, requesterID uuid.UUID, requesterRole entities.Role) error
+	// Restore brings a deleted user back, for admins
+	Restore(ctx context.Context, id uuid.UUID) (*dto.UserResponse, error)
 }
 
 type AuthService interface {
 	Register(ctx context.Context, req dto.RegisterRequest) (*dto.RegisterResponse, error)
-	Login(ctx context.Context, req dto.LoginRequest) (*dto.AuthResponse, error)
+	// Login starts a session from device, the User-Agent of the request, and ip
+	Login(ctx context.Context, req dto.LoginRequest, device, ip string) (*dto.AuthResponse, error)
 	Logout(ctx context.Context, accessJTI string, accessExp time.Time, refreshToken string) error
 	RefreshToken(ctx context.Context, refreshToken string) (*dto.AuthResponse, error)
 	RevokeAllSessions(ctx context.Context, userID uuid.UUID) error
+	ListSessions(ctx context.Context, userID uuid.UUID) (*dto.SessionListResponse, error)
+	RevokeTokens(ctx context.Context, adminID uuid.UUID, req dto.RevokeTokensRequest) (*dto.RevokeTokensResponse, error)
+	// PruneSessions drops the expired sessions of every user from Redis
+	PruneSessions(ctx context.Context) error
 }
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+// WarehouseService defines the interface for warehouse and stock ledger business logic
+type WarehouseService interface {
+	Create(ctx context.Context, req *dto.CreateWarehouseRequest) (*dto.WarehouseResponse, error)
+	List(ctx context.Context) ([]dto.WarehouseResponse, error)
+	ListStock(ctx context.Context, id uuid.UUID, page, limit int) ([]dto.WarehouseStockResponse, *dto.PaginationMeta, error)
+	// Adjust corrects the stock of a warehouse through the stock ledger
+	Adjust(ctx context.Context, id, adminID uuid.UUID, req *dto.StockAdjustmentRequest) ([]dto.StockLedgerEntryResponse, error)
+	ListLedger(ctx context.Context, req dto.StockLedgerListRequest) ([]dto.StockLedgerEntryResponse, *dto.PaginationMeta, error)
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"postgresDB/internal/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+// WebhookService defines the interface for order webhooks. It's an order hook turning
+// order events into signed deliveries to the subscribed endpoints
+type WebhookService interface {
+	AfterOrderCreateHook
+	AfterOrderStatusChangeHook
+	// Create registers an endpoint, the response holds the only copy of its signing secret
+	Create(ctx context.Context, adminID uuid.UUID, req *dto.CreateWebhookRequest) (*dto.WebhookResponse, error)
+	List(ctx context.Context, page, limit int) ([]dto.WebhookResponse, *dto.PaginationMeta, error)
+	Update(ctx context.Context, id uuid.UUID, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// Deliveries returns the delivery log of an endpoint, newest first
+	Deliveries(ctx context.Context, id uuid.UUID, page, limit int) ([]dto.WebhookDeliveryResponse, *dto.PaginationMeta, error)
+	// Deliver is the JobHandler posting one event to one endpoint, failures are retried
+	Deliver(ctx context.Context, payload json.RawMessage) error
+}
@@ -0,0 +1,81 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Alert statuses
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+// Alert severities
+const (
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Alert is an operational alert for the people running the shop
+type Alert struct {
+	// Name identifies the condition, e.g. checkout_failure_rate
+	Name     string         `json:"name"`
+	Status   string         `json:"status"`
+	Severity string         `json:"severity"`
+	Summary  string         `json:"summary"`
+	Details  map[string]any `json:"details,omitempty"`
+	At       time.Time      `json:"at"`
+}
+
+// Sink abstracts where alerts are delivered
+type Sink interface {
+	// Name identifies the sink in the job queue, it must be unique
+	Name() string
+	Send(ctx context.Context, a Alert) error
+}
+
+// webhookSink posts alerts as JSON
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink creates a Sink posting alerts as JSON to url; any non-2xx response is an error
+func NewWebhookSink(url string) Sink {
+	return &webhookSink{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *webhookSink) Name() string {
+	return "webhook"
+}
+
+func (s *webhookSink) Send(ctx context.Context, a Alert) error {
+	body, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("alert webhook: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("alert webhook: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
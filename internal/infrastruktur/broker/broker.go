@@ -0,0 +1,136 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/logger"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Config configures the message broker outbox events are published to
+type Config struct {
+	// Prefix starts every subject and stream name, e.g. shop publishes order.created
+	// as shop.order.created
+	Prefix string
+	NATS   NATSConfig
+	// RedisStreamMaxLen trims every stream to about this many events, 0 keeps them all
+	RedisStreamMaxLen int64
+}
+
+// NewPublisher creates the publisher of the broker named name: log, redis or nats.
+// Another broker, such as Kafka or RabbitMQ, plugs in as a service.EventPublisher
+func NewPublisher(name string, cfg Config, redisClient *redis.Client) (service.EventPublisher, error) {
+	switch name {
+	case "", "log":
+		return NewLogPublisher(), nil
+	case "redis":
+		return NewRedisPublisher(redisClient, cfg.Prefix, cfg.RedisStreamMaxLen), nil
+	case "nats":
+		return NewNATSPublisher(cfg.NATS, cfg.Prefix)
+	default:
+		return nil, fmt.Errorf("broker: unknown broker %q", name)
+	}
+}
+
+// Envelope is the message published for an outbox event
+type Envelope struct {
+	// ID is the same every time the event is published, consumers use it to skip duplicates
+	ID            uuid.UUID       `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uuid.UUID       `json:"aggregate_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// encode returns the envelope of event as JSON
+func encode(event *entities.OutboxEvent) ([]byte, error) {
+	return json.Marshal(Envelope{
+		ID:            event.ID,
+		Type:          event.EventType,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		CreatedAt:     event.CreatedAt,
+		Data:          event.Payload,
+	})
+}
+
+// subject names where an event is published, e.g. shop.order.created
+func subject(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// logPublisher writes events to the log, for development and for deployments without
+// consumers yet
+type logPublisher struct{}
+
+// NewLogPublisher creates a publisher writing events to the log
+func NewLogPublisher() service.EventPublisher {
+	return logPublisher{}
+}
+
+func (logPublisher) Name() string {
+	return "log"
+}
+
+func (logPublisher) Publish(ctx context.Context, event *entities.OutboxEvent) error {
+	logger.InfoContext(ctx, "outbox event", "event_id", event.ID, "type", event.EventType,
+		"aggregate_id", event.AggregateID, "data", string(event.Payload))
+	return nil
+}
+
+func (logPublisher) Close() error {
+	return nil
+}
+
+// redisPublisher appends events to Redis streams, one per aggregate type so the events of
+// an order or user keep their order, e.g. shop.order
+type redisPublisher struct {
+	client *redis.Client
+	prefix string
+	maxLen int64
+}
+
+// NewRedisPublisher creates a publisher appending events to Redis streams
+func NewRedisPublisher(client *redis.Client, prefix string, maxLen int64) service.EventPublisher {
+	return &redisPublisher{
+		client: client,
+		prefix: prefix,
+		maxLen: maxLen,
+	}
+}
+
+func (p *redisPublisher) Name() string {
+	return "redis"
+}
+
+func (p *redisPublisher) Publish(ctx context.Context, event *entities.OutboxEvent) error {
+	body, err := encode(event)
+	if err != nil {
+		return err
+	}
+	return p.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: subject(p.prefix, event.AggregateType),
+		MaxLen: p.maxLen,
+		Approx: p.maxLen > 0,
+		Values: map[string]any{
+			"id":   event.ID.String(),
+			"type": event.EventType,
+			"body": body,
+		},
+	}).Err()
+}
+
+// Close leaves the client open, it's shared with the rest of the application
+func (p *redisPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,158 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"postgresDB/pkg/logger"
+	"postgresDB/pkg/metrics"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	redisUp           = metrics.NewGauge("redis_up", "Whether Redis is reachable (1) or not (0)")
+	redisStateChanges = metrics.NewCounter("redis_state_changes_total", "Number of Redis availability state changes")
+	redisPingFailures = metrics.NewCounter("redis_ping_failures_total", "Number of failed Redis health pings")
+)
+
+// RedisHealthStatus is a snapshot of the Redis health state
+type RedisHealthStatus struct {
+	Available           bool      `json:"available"`
+	Since               time.Time `json:"since"`
+	LastCheck           time.Time `json:"last_check"`
+	LastError           string    `json:"last_error,omitempty"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+}
+
+// RedisHealthMonitor periodically pings Redis, records availability and
+// retries with exponential backoff while Redis is down
+type RedisHealthMonitor struct {
+	client     *redis.Client
+	interval   time.Duration
+	maxBackoff time.Duration
+	timeout    time.Duration
+
+	mu        sync.RWMutex
+	status    RedisHealthStatus
+	listeners []func(available bool)
+}
+
+// NewRedisHealthMonitor creates a health monitor; Redis is assumed available until the first check
+func NewRedisHealthMonitor(client *redis.Client, interval, maxBackoff time.Duration) *RedisHealthMonitor {
+	redisUp.Set(1)
+	return &RedisHealthMonitor{
+		client:     client,
+		interval:   interval,
+		maxBackoff: maxBackoff,
+		timeout:    2 * time.Second,
+		status: RedisHealthStatus{
+			Available: true,
+			Since:     time.Now(),
+		},
+	}
+}
+
+// OnStateChange registers a callback invoked when availability changes
+func (m *RedisHealthMonitor) OnStateChange(fn func(available bool)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, fn)
+}
+
+// Available reports whether Redis was reachable at the last check
+func (m *RedisHealthMonitor) Available() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.status.Available
+}
+
+// Status returns a snapshot of the health state
+func (m *RedisHealthMonitor) Status() RedisHealthStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.status
+}
+
+// Start runs the monitor until ctx is cancelled
+func (m *RedisHealthMonitor) Start(ctx context.Context) {
+	for {
+		wait := m.interval
+		if !m.check(ctx) {
+			wait = m.backoff()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// check pings Redis and updates the state; go-redis re-dials broken
+// connections on the next command so a successful ping means reconnected
+func (m *RedisHealthMonitor) check(ctx context.Context) bool {
+	pingCtx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+	err := m.client.Ping(pingCtx).Err()
+	if err != nil && ctx.Err() != nil {
+		return true // shutting down
+	}
+
+	m.mu.Lock()
+	previous := m.status.Available
+	m.status.LastCheck = time.Now()
+	if err != nil {
+		redisPingFailures.Inc()
+		m.status.Available = false
+		m.status.LastError = err.Error()
+		m.status.ConsecutiveFailures++
+	} else {
+		m.status.Available = true
+		m.status.LastError = ""
+		m.status.ConsecutiveFailures = 0
+	}
+	changed := previous != m.status.Available
+	if changed {
+		m.status.Since = m.status.LastCheck
+	}
+	status := m.status
+	listeners := append([]func(bool){}, m.listeners...)
+	m.mu.Unlock()
+
+	if changed {
+		m.notify(status, listeners)
+	}
+	return status.Available
+}
+
+// notify records and alerts on availability changes
+func (m *RedisHealthMonitor) notify(status RedisHealthStatus, listeners []func(bool)) {
+	redisStateChanges.Inc()
+	if status.Available {
+		redisUp.Set(1)
+		logger.Info("Redis connection restored")
+	} else {
+		redisUp.Set(0)
+		logger.Error("ALERT: Redis unavailable", "error", status.LastError)
+	}
+
+	for _, fn := range listeners {
+		fn(status.Available)
+	}
+}
+
+// backoff returns the wait before the next reconnection attempt
+func (m *RedisHealthMonitor) backoff() time.Duration {
+	m.mu.RLock()
+	failures := m.status.ConsecutiveFailures
+	m.mu.RUnlock()
+
+	wait := time.Second
+	for i := 1; i < failures && wait < m.maxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, m.maxBackoff)
+}
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"postgresDB/pkg/metrics"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// pipelineCommand labels pipelines and transactions, whatever commands they hold
+const pipelineCommand = "pipeline"
+
+var (
+	redisCommandDuration = metrics.NewHistogramVec("redis_command_duration_seconds",
+		"Time taken by Redis commands, pipelines counted as one",
+		[]float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}, "command")
+	redisCommandErrors = metrics.NewCounterVec("redis_command_errors_total",
+		"Number of failed Redis commands, a missing key isn't a failure", "command")
+)
+
+// metricsHook times every command of a client
+type metricsHook struct{}
+
+func (metricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (metricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		observeCommand(strings.ToLower(cmd.Name()), start, err)
+		return err
+	}
+}
+
+func (metricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		observeCommand(pipelineCommand, start, err)
+		return err
+	}
+}
+
+// observeCommand records the duration and the outcome of a command
+func observeCommand(command string, start time.Time, err error) {
+	redisCommandDuration.With(command).Observe(time.Since(start).Seconds())
+	if err != nil && !errors.Is(err, redis.Nil) {
+		redisCommandErrors.With(command).Inc()
+	}
+}
@@ -16,6 +16,8 @@ func NewRedisClient(ctx context.Context, cfg config.RedisConfig) (*redis.Client,
 		DB:       cfg.DB,
 	})
 
+	client.AddHook(metricsHook{})
+
 	if err := client.Ping(ctx).Err(); err != nil {
 		return nil, fmt.Errorf("failed to connect redis:%w", err)
 	}
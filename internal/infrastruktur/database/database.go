@@ -4,8 +4,12 @@ import (
 	"context"
 	"fmt"
 	"postgresDB/config"
+	"postgresDB/pkg/logger"
+	"strconv"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgconn/ctxwatch"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -22,16 +26,61 @@ func NewConnection(ctx context.Context, dbCfg config.DBConfig) (*pgxpool.Pool, e
 		)
 	}
 
+	pool, err := newPool(ctx, dsn, dbCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	registerPoolMetrics(pool)
+
+	fmt.Println("Connection Success")
+	return pool, nil
+
+}
+
+// NewReplicaConnections membuat connection pool untuk setiap read replica. Replica yang
+// gagal dihubungi dilewati, reads-nya kembali ke primary
+func NewReplicaConnections(ctx context.Context, dbCfg config.DBConfig) []*pgxpool.Pool {
+	var pools []*pgxpool.Pool
+	for i, dsn := range dbCfg.ReplicaURLs {
+		pool, err := newPool(ctx, dsn, dbCfg)
+		if err != nil {
+			// the URL may carry a password, name the replica by position
+			logger.Warn("skipping read replica", "replica", i, "error", err)
+			continue
+		}
+		pools = append(pools, pool)
+	}
+	return pools
+}
+
+// newPool creates a connection pool for dsn with the settings of dbCfg and checks it
+// answers
+func newPool(ctx context.Context, dsn string, dbCfg config.DBConfig) (*pgxpool.Pool, error) {
 	// Parse the connection string
 	pgxCfg, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
 		return nil, fmt.Errorf("invalid database URL: %v", err)
 	}
 	// configurasi connection pool
-	pgxCfg.MaxConns = 10
-	pgxCfg.MinConns = 2
-	pgxCfg.MaxConnLifetime = time.Hour
-	pgxCfg.MaxConnIdleTime = 30 * time.Minute
+	pgxCfg.MaxConns = int32(dbCfg.MaxConns)
+	pgxCfg.MinConns = int32(dbCfg.MinConns)
+	pgxCfg.MaxConnLifetime = dbCfg.MaxConnLifetime
+	pgxCfg.MaxConnIdleTime = dbCfg.MaxConnIdleTime
+	pgxCfg.HealthCheckPeriod = dbCfg.HealthCheckPeriod
+
+	// batas waktu setiap statement di server, juga untuk query di luar request
+	if dbCfg.StatementTimeout > 0 {
+		pgxCfg.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(dbCfg.StatementTimeout.Milliseconds(), 10)
+	}
+	// context yang selesai (client disconnect, deadline request) membatalkan query di
+	// server juga, bukan hanya memutus koneksinya
+	pgxCfg.ConnConfig.BuildContextWatcherHandler = func(pgConn *pgconn.PgConn) ctxwatch.Handler {
+		return &pgconn.CancelRequestContextWatcherHandler{
+			Conn:          pgConn,
+			DeadlineDelay: 5 * time.Second,
+		}
+	}
 
 	// Create a connection pool with configured settings
 	pool, err := pgxpool.NewWithConfig(ctx, pgxCfg)
@@ -40,13 +89,11 @@ func NewConnection(ctx context.Context, dbCfg config.DBConfig) (*pgxpool.Pool, e
 	}
 
 	// Test the connection with timeout
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
-	if err := pool.Ping(ctx); err != nil {
+	if err := pool.Ping(pingCtx); err != nil {
+		pool.Close()
 		return nil, fmt.Errorf("ping error to connect database: %v", err)
 	}
-
-	fmt.Println("Connection Success")
 	return pool, nil
-
 }
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"postgresDB/pkg/logger"
+	"postgresDB/pkg/metrics"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// registerPoolMetrics exposes the statistics of the connection pool, read at every scrape.
+// Only the primary pool is registered, registering a second one panics on the duplicate
+// names
+func registerPoolMetrics(pool *pgxpool.Pool) {
+	gauge := func(name, help string, value func(*pgxpool.Stat) int32) {
+		metrics.NewGaugeFunc(name, help, func() float64 { return float64(value(pool.Stat())) })
+	}
+	counter := func(name, help string, value func(*pgxpool.Stat) float64) {
+		metrics.NewCounterFunc(name, help, func() float64 { return value(pool.Stat()) })
+	}
+
+	gauge("db_pool_connections_max", "Most connections the pool opens", (*pgxpool.Stat).MaxConns)
+	gauge("db_pool_connections_total", "Number of open connections, in use, idle or being opened", (*pgxpool.Stat).TotalConns)
+	gauge("db_pool_connections_acquired", "Number of connections in use", (*pgxpool.Stat).AcquiredConns)
+	gauge("db_pool_connections_idle", "Number of idle connections", (*pgxpool.Stat).IdleConns)
+	gauge("db_pool_connections_constructing", "Number of connections being opened", (*pgxpool.Stat).ConstructingConns)
+	counter("db_pool_acquires_total", "Number of connections acquired from the pool",
+		func(s *pgxpool.Stat) float64 { return float64(s.AcquireCount()) })
+	counter("db_pool_empty_acquires_total", "Number of acquires that waited for a connection because none was idle",
+		func(s *pgxpool.Stat) float64 { return float64(s.EmptyAcquireCount()) })
+	counter("db_pool_canceled_acquires_total", "Number of acquires cancelled by their context",
+		func(s *pgxpool.Stat) float64 { return float64(s.CanceledAcquireCount()) })
+	counter("db_pool_acquire_wait_seconds_total", "Time spent waiting for a connection by acquires that found none idle",
+		func(s *pgxpool.Stat) float64 { return s.EmptyAcquireWaitTime().Seconds() })
+	counter("db_pool_new_connections_total", "Number of connections the pool opened",
+		func(s *pgxpool.Stat) float64 { return float64(s.NewConnsCount()) })
+	counter("db_pool_lifetime_closes_total", "Number of connections closed for reaching DB_MAX_CONN_LIFETIME",
+		func(s *pgxpool.Stat) float64 { return float64(s.MaxLifetimeDestroyCount()) })
+	counter("db_pool_idle_closes_total", "Number of connections closed for being idle longer than DB_MAX_CONN_IDLE_TIME",
+		func(s *pgxpool.Stat) float64 { return float64(s.MaxIdleDestroyCount()) })
+}
+
+// MonitorPool warns every interval in which acquires had to wait for a connection while
+// the pool was already at its size limit, the sign it's too small for the load. It runs
+// until ctx is cancelled
+func MonitorPool(ctx context.Context, pool *pgxpool.Pool, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := pool.Stat()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		stat := pool.Stat()
+		waits := stat.EmptyAcquireCount() - last.EmptyAcquireCount()
+		if waits > 0 && stat.TotalConns() >= stat.MaxConns() {
+			logger.Warn("database pool exhausted",
+				"waits", waits,
+				"wait_time", stat.EmptyAcquireWaitTime()-last.EmptyAcquireWaitTime(),
+				"canceled_acquires", stat.CanceledAcquireCount()-last.CanceledAcquireCount(),
+				"max_conns", stat.MaxConns(),
+			)
+		}
+		last = stat
+	}
+}
@@ -0,0 +1,26 @@
+package payment
+
+import (
+	"fmt"
+	"postgresDB/internal/domain/service"
+)
+
+// NewProvider creates the payment provider named name. An empty name means no provider
+// is configured and returns nil
+func NewProvider(name string, stripe StripeConfig) (service.PaymentProvider, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case "stripe":
+		return NewStripeProvider(stripe)
+	default:
+		return nil, fmt.Errorf("payment: unknown provider %q", name)
+	}
+}
+
+// Gateway returns the provider as a PaymentGateway, or nil when there is no provider or
+// it can't capture payments
+func Gateway(provider service.PaymentProvider) service.PaymentGateway {
+	gateway, _ := provider.(service.PaymentGateway)
+	return gateway
+}
@@ -0,0 +1,301 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/service"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultStripeAPIURL is the base URL of the Stripe API
+const DefaultStripeAPIURL = "https://api.stripe.com"
+
+// stripeWebhookTolerance is how old a signed webhook may be, older ones are replays
+const stripeWebhookTolerance = 5 * time.Minute
+
+// stripeProvider creates and captures Stripe PaymentIntents and verifies Stripe webhooks
+type stripeProvider struct {
+	apiURL        string
+	secretKey     string
+	webhookSecret string
+	client        *http.Client
+}
+
+// StripeConfig holds the settings of the Stripe provider
+type StripeConfig struct {
+	// APIURL may be empty for the public API
+	APIURL    string
+	SecretKey string
+	// WebhookSecret is the signing secret of the webhook endpoint
+	WebhookSecret string
+}
+
+// NewStripeProvider creates a PaymentProvider backed by Stripe PaymentIntents. It is a
+// service.PaymentGateway too, capturing the intents of orders waiting for capture
+func NewStripeProvider(cfg StripeConfig) (service.PaymentProvider, error) {
+	if cfg.SecretKey == "" || cfg.WebhookSecret == "" {
+		return nil, errors.New("stripe: secret key and webhook secret are required")
+	}
+	apiURL := cfg.APIURL
+	if apiURL == "" {
+		apiURL = DefaultStripeAPIURL
+	}
+	return &stripeProvider{
+		apiURL:        strings.TrimRight(apiURL, "/"),
+		secretKey:     cfg.SecretKey,
+		webhookSecret: cfg.WebhookSecret,
+		client:        &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+func (p *stripeProvider) Name() string {
+	return "stripe"
+}
+
+// stripeIntent is the part of a Stripe PaymentIntent the shop reads
+type stripeIntent struct {
+	ID               string `json:"id"`
+	Status           string `json:"status"`
+	Amount           int64  `json:"amount"`
+	AmountReceived   int64  `json:"amount_received"`
+	Created          int64  `json:"created"`
+	ClientSecret     string `json:"client_secret"`
+	LastPaymentError *struct {
+		Message string `json:"message"`
+	} `json:"last_payment_error"`
+}
+
+// CreateIntent creates a PaymentIntent, the payment ID is sent as idempotency key so a
+// retried request doesn't charge twice
+func (p *stripeProvider) CreateIntent(ctx context.Context, payment *entities.Payment) (*entities.PaymentIntent, error) {
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(payment.Amount, 10))
+	form.Set("currency", payment.Currency)
+	form.Set("automatic_payment_methods[enabled]", "true")
+	form.Set("metadata[order_id]", payment.OrderID.String())
+	form.Set("metadata[payment_id]", payment.ID.String())
+
+	body, err := p.post(ctx, "/v1/payment_intents", form, payment.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var intent stripeIntent
+	if err := json.Unmarshal(body, &intent); err != nil {
+		return nil, fmt.Errorf("stripe: decoding payment intent: %w", err)
+	}
+	return &entities.PaymentIntent{
+		ProviderRef:  intent.ID,
+		ClientSecret: intent.ClientSecret,
+	}, nil
+}
+
+// Refund creates a Stripe Refund of the payment's PaymentIntent
+func (p *stripeProvider) Refund(ctx context.Context, payment *entities.Payment, refund *entities.Refund) (string, error) {
+	form := url.Values{}
+	form.Set("payment_intent", payment.ProviderRef)
+	form.Set("amount", strconv.FormatInt(refund.Amount, 10))
+	form.Set("metadata[order_id]", refund.OrderID.String())
+	form.Set("metadata[refund_id]", refund.ID.String())
+
+	body, err := p.post(ctx, "/v1/refunds", form, refund.ID.String())
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("stripe: decoding refund: %w", err)
+	}
+	// pending refunds settle on their own, only outright failures are errors
+	if result.Status == "failed" || result.Status == "canceled" {
+		return "", fmt.Errorf("stripe: refund %s %s", result.ID, result.Status)
+	}
+	return result.ID, nil
+}
+
+// Capture settles the latest PaymentIntent of the order. An authorized intent is captured,
+// a succeeded one was captured already. Without an intent, or while the customer still has
+// to pay, the capture is pending; a cancelled intent declines it
+func (p *stripeProvider) Capture(ctx context.Context, order *entities.Order) (entities.CaptureResult, error) {
+	query := url.Values{}
+	query.Set("query", fmt.Sprintf("metadata['order_id']:'%s'", order.ID))
+	body, err := p.get(ctx, "/v1/payment_intents/search", query)
+	if err != nil {
+		return "", err
+	}
+	var found struct {
+		Data []stripeIntent `json:"data"`
+	}
+	if err := json.Unmarshal(body, &found); err != nil {
+		return "", fmt.Errorf("stripe: decoding payment intents: %w", err)
+	}
+	if len(found.Data) == 0 {
+		return entities.CaptureResultPending, nil
+	}
+	intent := found.Data[0]
+	for _, candidate := range found.Data[1:] {
+		if candidate.Created > intent.Created {
+			intent = candidate
+		}
+	}
+	if intent.Amount != order.TotalAmount {
+		return "", fmt.Errorf("stripe: payment intent %s is for %d, the order totals %d", intent.ID, intent.Amount, order.TotalAmount)
+	}
+
+	if intent.Status == "requires_capture" {
+		body, err := p.post(ctx, "/v1/payment_intents/"+url.PathEscape(intent.ID)+"/capture", url.Values{}, "capture-"+intent.ID)
+		if err != nil {
+			return "", err
+		}
+		if err := json.Unmarshal(body, &intent); err != nil {
+			return "", fmt.Errorf("stripe: decoding captured payment intent: %w", err)
+		}
+	}
+
+	switch intent.Status {
+	case "succeeded":
+		return entities.CaptureResultCaptured, nil
+	case "canceled":
+		return entities.CaptureResultDeclined, nil
+	default:
+		// requires_payment_method, requires_confirmation, requires_action and processing
+		// wait for the customer or the bank
+		return entities.CaptureResultPending, nil
+	}
+}
+
+// get sends an API request with the query and returns the body of a 2xx response
+func (p *stripeProvider) get(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.apiURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return p.do(req)
+}
+
+// post sends a form-encoded API request and returns the body of a 2xx response
+func (p *stripeProvider) post(ctx context.Context, path string, form url.Values, idempotencyKey string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.apiURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Idempotency-Key", idempotencyKey)
+	return p.do(req)
+}
+
+// do authenticates and sends an API request, returning the body of a 2xx response
+func (p *stripeProvider) do(req *http.Request) ([]byte, error) {
+	req.Header.Set("Authorization", "Bearer "+p.secretKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("stripe: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("stripe: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(body, &apiErr)
+		return nil, fmt.Errorf("stripe: unexpected status %d: %s", resp.StatusCode, apiErr.Error.Message)
+	}
+	return body, nil
+}
+
+// ParseWebhook verifies the Stripe-Signature header and reads payment_intent events
+func (p *stripeProvider) ParseWebhook(payload []byte, header http.Header) (*entities.PaymentEvent, error) {
+	if err := p.verify(payload, header.Get("Stripe-Signature"), time.Now()); err != nil {
+		return nil, err
+	}
+
+	var event struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Data struct {
+			Object stripeIntent `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("stripe: decoding event: %w", err)
+	}
+
+	intent := event.Data.Object
+	result := &entities.PaymentEvent{
+		ID:          event.ID,
+		ProviderRef: intent.ID,
+	}
+	switch event.Type {
+	case "payment_intent.succeeded":
+		result.Status = entities.PaymentStatusSucceeded
+		result.Amount = intent.AmountReceived
+	case "payment_intent.payment_failed":
+		result.Status = entities.PaymentStatusFailed
+		if intent.LastPaymentError != nil {
+			result.FailureReason = intent.LastPaymentError.Message
+		}
+	case "payment_intent.canceled":
+		result.Status = entities.PaymentStatusCancelled
+	default:
+		return nil, nil
+	}
+	return result, nil
+}
+
+// verify checks a Stripe-Signature header of the form t=<unix time>,v1=<signature>: an
+// HMAC-SHA256 of "<t>.<payload>" with the webhook secret. Stripe may send several v1
+// signatures while the secret is rolled, one match is enough
+func (p *stripeProvider) verify(payload []byte, signature string, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return errors.New("stripe: malformed signature header")
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > stripeWebhookTolerance || age < -stripeWebhookTolerance {
+		return errors.New("stripe: signature timestamp outside tolerance")
+	}
+
+	mac := hmac.New(sha256.New, []byte(p.webhookSecret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+	for _, s := range signatures {
+		if got, err := hex.DecodeString(s); err == nil && hmac.Equal(got, expected) {
+			return nil
+		}
+	}
+	return errors.New("stripe: signature mismatch")
+}
@@ -0,0 +1,36 @@
+package notification
+
+import (
+	"context"
+
+	"postgresDB/internal/infrastruktur/mail"
+)
+
+// ChannelEmail is the name of the email channel
+const ChannelEmail = "email"
+
+// emailChannel sends notifications as emails through the mailer, the SMTP server
+type emailChannel struct {
+	mailer mail.Mailer
+}
+
+// NewEmailChannel creates a Channel emailing notifications through mailer
+func NewEmailChannel(mailer mail.Mailer) Channel {
+	return &emailChannel{mailer: mailer}
+}
+
+func (c *emailChannel) Name() string {
+	return ChannelEmail
+}
+
+func (c *emailChannel) Send(ctx context.Context, to Recipient, msg Message) error {
+	if to.Email == "" {
+		return ErrUnreachable
+	}
+	return c.mailer.Send(ctx, mail.Message{
+		To:      to.Email,
+		Subject: msg.Subject,
+		HTML:    msg.HTML,
+		Text:    msg.Text,
+	})
+}
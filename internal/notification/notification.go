@@ -0,0 +1,38 @@
+package notification
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnreachable is returned by a channel that has no address for the recipient
+var ErrUnreachable = errors.New("notification: recipient can't be reached on this channel")
+
+// Recipient is the user a notification is sent to
+type Recipient struct {
+	UserID uuid.UUID `json:"user_id"`
+	// Name is empty for guests, templates greet them without one
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Message is a rendered notification
+type Message struct {
+	// Kind is the kind of notification, e.g. order_confirmation
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"`
+	HTML    string `json:"-"`
+	Text    string `json:"text"`
+	// Data is what the message was rendered from, for channels that format it themselves
+	Data map[string]any `json:"data,omitempty"`
+}
+
+// Channel delivers notifications through one medium, e.g. email or a webhook. An SMS
+// gateway plugs in as another Channel
+type Channel interface {
+	// Name identifies the channel in preferences and the job queue, it must be unique
+	Name() string
+	Send(ctx context.Context, to Recipient, msg Message) error
+}
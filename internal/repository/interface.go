@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"postgresDB/internal/domain/entities"
 	"time"
 
 	"github.com/google/uuid"
@@ -21,4 +22,124 @@ type TokenRepository interface {
 	TrackUserSession(ctx context.Context, userID uuid.UUID, family string, ttl time.Duration) error
 	// RevokeAllUserSessions revokes all sessions for a user
 	RevokeAllUserSessions(ctx context.Context, userID uuid.UUID) error
+	// SaveSession stores the device and start time of a login session for ttl
+	SaveSession(ctx context.Context, userID uuid.UUID, session *entities.Session, ttl time.Duration) error
+	// ListUserSessions returns the unexpired login sessions of a user, oldest first.
+	// Token families without stored session details, like OAuth grants, are left out
+	ListUserSessions(ctx context.Context, userID uuid.UUID) ([]*entities.Session, error)
+	// EvictUserSession ends a login session and keeps it in the evicted sessions of the
+	// user for ttl
+	EvictUserSession(ctx context.Context, userID uuid.UUID, session *entities.Session, ttl time.Duration) error
+	// RemoveUserSession deletes a token family with its session details
+	RemoveUserSession(ctx context.Context, userID uuid.UUID, family string) error
+	// ListEvictedSessions returns the recently evicted sessions of a user, latest first
+	ListEvictedSessions(ctx context.Context, userID uuid.UUID) ([]*entities.Session, error)
+	// RevokeIssuedBefore revokes the tokens of every scope issued before the cutoff, for
+	// ttl; a later cutoff already stored is kept. It returns the number of scopes raised
+	RevokeIssuedBefore(ctx context.Context, scopes []string, before time.Time, ttl time.Duration) (int, error)
+	// IsTokenRevoked reports whether the token is blacklisted or was issued before the
+	// cutoff of any of its scopes
+	IsTokenRevoked(ctx context.Context, jti string, issuedAt time.Time, scopes ...string) (bool, error)
+	// ExportState reads every blacklist, token family and session key for a backup
+	ExportState(ctx context.Context) ([]entities.AuthStateEntry, error)
+	// ImportState writes back backed up entries. Expired entries are skipped and newer
+	// token families in Redis are kept, so a restore never revives a rotated refresh token
+	ImportState(ctx context.Context, entries []entities.AuthStateEntry) (restored, skipped int, err error)
+	// PruneSessions drops the sessions that expired before now from the session sets of
+	// every user, returning the number dropped
+	PruneSessions(ctx context.Context, now time.Time) (int64, error)
+}
+
+// AuthorizationCodeRepository keeps OAuth authorization codes until they are redeemed (Redis)
+type AuthorizationCodeRepository interface {
+	// Save stores the grant of a code under the hash of the code for ttl
+	Save(ctx context.Context, codeHash string, code *entities.AuthorizationCode, ttl time.Duration) error
+	// Consume returns and deletes the grant in one step, so a code is redeemed at most
+	// once. It returns nil when the code is unknown or expired
+	Consume(ctx context.Context, codeHash string) (*entities.AuthorizationCode, error)
+}
+
+// RateLimitRepository counts requests per key in fixed time windows or token buckets (Redis)
+type RateLimitRepository interface {
+	// Allow records a hit for key and reports whether it's within limit for the
+	// current window, along with the time left until the window resets
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error)
+	// Take takes a token from the bucket of key, which holds up to burst tokens and refills
+	// at rate tokens per second. It reports whether a token was taken, the whole tokens
+	// left, and the wait until the bucket is full again or, when refused, until the next token
+	Take(ctx context.Context, key string, rate float64, burst int) (allowed bool, remaining int, wait time.Duration, err error)
+}
+
+// NonceRepository remembers request nonces to reject replayed requests (Redis)
+type NonceRepository interface {
+	// Use records the nonce for ttl and reports whether it was unused
+	Use(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// IdempotencyRepository stores the responses of requests sent with an Idempotency-Key (Redis)
+type IdempotencyRepository interface {
+	// Claim reserves key for a request with the fingerprint for ttl. When the key is
+	// already taken it returns the stored request instead, with no response while that
+	// request is still running
+	Claim(ctx context.Context, key, fingerprint string, ttl time.Duration) (*entities.IdempotentRequest, bool, error)
+	// Complete stores the response of a claimed key for ttl
+	Complete(ctx context.Context, key string, request *entities.IdempotentRequest, ttl time.Duration) error
+	// Release frees a claimed key so the request can be retried
+	Release(ctx context.Context, key string) error
+}
+
+// ResponseCacheRepository stores rendered responses of public GET routes (Redis). Keys are
+// built with the version of their scope, so bumping it invalidates every response of the
+// scope at once
+type ResponseCacheRepository interface {
+	// Version returns the current version of scope
+	Version(ctx context.Context, scope string) (string, error)
+	// Get returns the response cached under key, nil when there is none
+	Get(ctx context.Context, key string) (*entities.CachedResponse, error)
+	// Set caches the response under key for ttl
+	Set(ctx context.Context, key string, response *entities.CachedResponse, ttl time.Duration) error
+	// Invalidate bumps the version of scope
+	Invalidate(ctx context.Context, scope string) error
+}
+
+// JobRepository stores background jobs and their queues (Redis)
+type JobRepository interface {
+	// Enqueue stores the job and makes it ready at job.RunAt
+	Enqueue(ctx context.Context, job *entities.Job) error
+	// Dequeue claims the next ready job of queue, nil when there is none
+	Dequeue(ctx context.Context, queue string, now time.Time) (*entities.Job, error)
+	Get(ctx context.Context, id uuid.UUID) (*entities.Job, error)
+	// Complete records a successful run
+	Complete(ctx context.Context, job *entities.Job, wait, run time.Duration) error
+	// Reschedule puts a job whose attempt failed back in its queue at job.RunAt
+	Reschedule(ctx context.Context, job *entities.Job, wait, run time.Duration) error
+	// Fail moves a job that used up its attempts to the failed list of its queue
+	Fail(ctx context.Context, job *entities.Job, wait, run time.Duration) error
+	// RequeueStale puts running jobs claimed before cutoff back in their queue,
+	// recovering jobs of a worker that died mid-run
+	RequeueStale(ctx context.Context, queue string, cutoff time.Time) (int, error)
+	// ListFailed returns the failed jobs of queue, most recent first
+	ListFailed(ctx context.Context, queue string, limit, offset int) ([]*entities.Job, int64, error)
+	// Retry moves a failed job back to its queue with fresh attempts
+	Retry(ctx context.Context, id uuid.UUID) (*entities.Job, error)
+	// Cancel drops a queued or failed job
+	Cancel(ctx context.Context, id uuid.UUID) (*entities.Job, error)
+	// Queues returns the names of all queues that ever had a job
+	Queues(ctx context.Context) ([]string, error)
+	Stats(ctx context.Context, queue string, window time.Duration) (*entities.QueueStats, error)
+}
+
+// EventBus broadcasts events to every running API instance (Redis pub/sub). Delivery is
+// at most once, subscribers that aren't connected when an event is published miss it
+type EventBus interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+	// Subscribe delivers the payloads published on topic until ctx is done, then closes
+	// the channel
+	Subscribe(ctx context.Context, topic string) (<-chan []byte, error)
+}
+
+// LockRepository hands out named locks shared by every API instance (Redis)
+type LockRepository interface {
+	// TryLock takes the lock name for owner until ttl passes and reports whether it was free
+	TryLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
 }
@@ -0,0 +1,258 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// accessLogExportColumns is the column list scanned by scanAccessLogExport
+const accessLogExportColumns = `id, requested_by, COALESCE(client_id, ''), user_id, from_at, to_at, format, status, row_count, size, COALESCE(storage_key, ''), COALESCE(error, ''), created_at, completed_at`
+
+type accessLogRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewAccessLogRepository creates a new AccessLogRepository instance
+func NewAccessLogRepository(db *pgxpool.Pool) repository.AccessLogRepository {
+	return &accessLogRepository{
+		db: db,
+	}
+}
+
+// InsertBatch menyimpan access log sekaligus dengan COPY
+func (r *accessLogRepository) InsertBatch(ctx context.Context, logs []entities.AccessLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	rows := make([][]any, len(logs))
+	for i, l := range logs {
+		rows[i] = []any{l.At, l.Method, l.Path, l.Status, l.DurationMs, l.Size, nullIfEmpty(l.IP), nullIfEmpty(l.ClientID), l.UserID, nullIfEmpty(l.UserAgent)}
+	}
+
+	_, err := r.db.CopyFrom(ctx,
+		pgx.Identifier{"access_logs"},
+		[]string{"at", "method", "path", "status", "duration_ms", "size", "ip", "client_id", "user_id", "user_agent"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// Each membaca access log yang cocok dengan filter baris per baris, tanpa memuat semuanya ke memori
+func (r *accessLogRepository) Each(ctx context.Context, filter repository.AccessLogFilter, fn func(*entities.AccessLog) error) error {
+	query := `
+		SELECT id, at, method, path, status, duration_ms, size, COALESCE(ip, ''), COALESCE(client_id, ''), user_id, COALESCE(user_agent, '')
+		FROM access_logs
+		WHERE at >= $1 AND at < $2
+	`
+	args := []any{filter.From, filter.To}
+	if filter.ClientID != "" {
+		args = append(args, filter.ClientID)
+		query += fmt.Sprintf(" AND client_id = $%d", len(args))
+	}
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		query += fmt.Sprintf(" AND user_id = $%d", len(args))
+	}
+	query += " ORDER BY at, id"
+
+	rows, err := retrying(r.db).Query(ctx, query, args...)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l entities.AccessLog
+		if err := rows.Scan(&l.ID, &l.At, &l.Method, &l.Path, &l.Status, &l.DurationMs, &l.Size, &l.IP, &l.ClientID, &l.UserID, &l.UserAgent); err != nil {
+			return wrapError(err)
+		}
+		if err := fn(&l); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// DailyUsage menghitung request dan error sebuah client per hari (UTC)
+func (r *accessLogRepository) DailyUsage(ctx context.Context, clientID string, from, to time.Time) ([]entities.APIClientUsage, error) {
+	query := `
+		SELECT date_trunc('day', at AT TIME ZONE 'UTC') AS day, COUNT(*), COUNT(*) FILTER (WHERE status >= 400)
+		FROM access_logs
+		WHERE client_id = $1 AND at >= $2 AND at < $3
+		GROUP BY day
+		ORDER BY day
+	`
+	rows, err := retrying(r.db).Query(ctx, query, clientID, from, to)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	usage := make([]entities.APIClientUsage, 0)
+	for rows.Next() {
+		var u entities.APIClientUsage
+		if err := rows.Scan(&u.Day, &u.Requests, &u.Errors); err != nil {
+			return nil, wrapError(err)
+		}
+		usage = append(usage, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return usage, nil
+}
+
+// DeleteBefore menghapus access log yang lebih lama dari t
+func (r *accessLogRepository) DeleteBefore(ctx context.Context, t time.Time) (int64, error) {
+	res, err := retrying(r.db).Exec(ctx, `DELETE FROM access_logs WHERE at < $1`, t)
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	return res.RowsAffected(), nil
+}
+
+// CreateExport menyimpan permintaan export baru
+func (r *accessLogRepository) CreateExport(ctx context.Context, export *entities.AccessLogExport) error {
+	query := `
+		INSERT INTO access_log_exports (id, requested_by, client_id, user_id, from_at, to_at, format, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := retrying(r.db).Exec(ctx, query, export.ID, export.RequestedBy, nullIfEmpty(export.ClientID), export.UserID,
+		export.From, export.To, export.Format, export.Status, export.CreatedAt)
+	if err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// GetExport mengambil export berdasarkan ID
+func (r *accessLogRepository) GetExport(ctx context.Context, id uuid.UUID) (*entities.AccessLogExport, error) {
+	query := `SELECT ` + accessLogExportColumns + ` FROM access_log_exports WHERE id = $1`
+
+	export, err := scanAccessLogExport(retrying(r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrAccessLogExportNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return export, nil
+}
+
+// ListExports mengambil daftar export terbaru lebih dulu
+func (r *accessLogRepository) ListExports(ctx context.Context, requestedBy *uuid.UUID, limit, offset int) ([]*entities.AccessLogExport, int64, error) {
+	where := ` WHERE ($1::uuid IS NULL OR requested_by = $1)`
+
+	var total int64
+	if err := retrying(r.db).QueryRow(ctx, `SELECT COUNT(*) FROM access_log_exports`+where, requestedBy).Scan(&total); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	query := `SELECT ` + accessLogExportColumns + ` FROM access_log_exports` + where + ` ORDER BY created_at DESC LIMIT $2 OFFSET $3`
+	rows, err := retrying(r.db).Query(ctx, query, requestedBy, limit, offset)
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+	defer rows.Close()
+
+	exports, err := scanAccessLogExports(rows, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return exports, total, nil
+}
+
+// UpdateExport menyimpan status dan hasil export
+func (r *accessLogRepository) UpdateExport(ctx context.Context, export *entities.AccessLogExport) error {
+	query := `
+		UPDATE access_log_exports
+		SET status = $1, row_count = $2, size = $3, storage_key = $4, error = $5, completed_at = $6
+		WHERE id = $7
+	`
+	res, err := retrying(r.db).Exec(ctx, query, export.Status, export.RowCount, export.Size, nullIfEmpty(export.StorageKey),
+		nullIfEmpty(export.Error), export.CompletedAt, export.ID)
+	if err != nil {
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrAccessLogExportNotFound
+	}
+	return nil
+}
+
+// DeleteExportsBefore menghapus export yang dibuat sebelum t dan mengembalikannya
+func (r *accessLogRepository) DeleteExportsBefore(ctx context.Context, t time.Time) ([]*entities.AccessLogExport, error) {
+	query := `DELETE FROM access_log_exports WHERE created_at < $1 RETURNING ` + accessLogExportColumns
+	rows, err := retrying(r.db).Query(ctx, query, t)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	return scanAccessLogExports(rows, 0)
+}
+
+// scanAccessLogExports scans export rows selected with accessLogExportColumns
+func scanAccessLogExports(rows pgx.Rows, capacity int) ([]*entities.AccessLogExport, error) {
+	exports := make([]*entities.AccessLogExport, 0, capacity)
+	for rows.Next() {
+		export, err := scanAccessLogExport(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		exports = append(exports, export)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return exports, nil
+}
+
+// scanAccessLogExport scans a single export row selected with accessLogExportColumns
+func scanAccessLogExport(row pgx.Row) (*entities.AccessLogExport, error) {
+	var e entities.AccessLogExport
+	err := row.Scan(
+		&e.ID,
+		&e.RequestedBy,
+		&e.ClientID,
+		&e.UserID,
+		&e.From,
+		&e.To,
+		&e.Format,
+		&e.Status,
+		&e.RowCount,
+		&e.Size,
+		&e.StorageKey,
+		&e.Error,
+		&e.CreatedAt,
+		&e.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// nullIfEmpty maps an empty string to NULL
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
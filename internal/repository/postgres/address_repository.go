@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// addressColumns is the column list scanned by scanAddress
+const addressColumns = `id, user_id, label, recipient_name, phone, street, city, province, postal_code, country, is_default, created_at, updated_at`
+
+type addressRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewAddressRepository untuk membuat instance baru dari AddressRepository
+func NewAddressRepository(db *pgxpool.Pool) repository.AddressRepository {
+	return &addressRepository{
+		db: db,
+	}
+}
+
+// Create menyimpan alamat baru ke buku alamat user
+func (r *addressRepository) Create(ctx context.Context, address *entities.Address) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if address.IsDefault {
+		if err := clearDefaultAddress(ctx, tx, address.UserID); err != nil {
+			return err
+		}
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO addresses (id, user_id, label, recipient_name, phone, street, city, province, postal_code, country, is_default, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		address.ID, address.UserID, address.Label, address.RecipientName, address.Phone, address.Street, address.City,
+		address.Province, address.PostalCode, address.Country, address.IsDefault, address.CreatedAt, address.UpdatedAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// GetByID mengambil alamat berdasarkan ID
+func (r *addressRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Address, error) {
+	return r.get(ctx, `SELECT `+addressColumns+` FROM addresses WHERE id = $1`, id)
+}
+
+// GetDefault mengambil alamat utama user
+func (r *addressRepository) GetDefault(ctx context.Context, userID uuid.UUID) (*entities.Address, error) {
+	return r.get(ctx, `SELECT `+addressColumns+` FROM addresses WHERE user_id = $1 AND is_default`, userID)
+}
+
+func (r *addressRepository) get(ctx context.Context, query string, arg any) (*entities.Address, error) {
+	address, err := scanAddress(retrying(r.db).QueryRow(ctx, query, arg))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrAddressNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return address, nil
+}
+
+// ListByUserID mengambil buku alamat user, alamat utama lebih dulu
+func (r *addressRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Address, error) {
+	query := `SELECT ` + addressColumns + ` FROM addresses WHERE user_id = $1 ORDER BY is_default DESC, created_at, id`
+	rows, err := retrying(r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	addresses := make([]*entities.Address, 0)
+	for rows.Next() {
+		address, err := scanAddress(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		addresses = append(addresses, address)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return addresses, nil
+}
+
+// Update menyimpan perubahan alamat
+func (r *addressRepository) Update(ctx context.Context, address *entities.Address) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if address.IsDefault {
+		if err := clearDefaultAddress(ctx, tx, address.UserID); err != nil {
+			return err
+		}
+	}
+	res, err := tx.Exec(ctx, `
+		UPDATE addresses SET label = $1, recipient_name = $2, phone = $3, street = $4, city = $5, province = $6,
+			postal_code = $7, country = $8, is_default = $9, updated_at = $10
+		WHERE id = $11`,
+		address.Label, address.RecipientName, address.Phone, address.Street, address.City, address.Province,
+		address.PostalCode, address.Country, address.IsDefault, address.UpdatedAt, address.ID,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrAddressNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// Delete menghapus alamat dari buku alamat
+func (r *addressRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := retrying(r.db).Exec(ctx, `DELETE FROM addresses WHERE id = $1`, id)
+	if err != nil {
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrAddressNotFound
+	}
+	return nil
+}
+
+// clearDefaultAddress unsets the user's default address so another one can take over
+func clearDefaultAddress(ctx context.Context, tx pgx.Tx, userID uuid.UUID) error {
+	if _, err := tx.Exec(ctx, `UPDATE addresses SET is_default = FALSE WHERE user_id = $1 AND is_default`, userID); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// scanAddress scans a single address row selected with addressColumns
+func scanAddress(row pgx.Row) (*entities.Address, error) {
+	var a entities.Address
+	err := row.Scan(&a.ID, &a.UserID, &a.Label, &a.RecipientName, &a.Phone, &a.Street, &a.City, &a.Province,
+		&a.PostalCode, &a.Country, &a.IsDefault, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// apiClientColumns is the column list scanned by scanAPIClient
+const apiClientColumns = `id, owner_id, client_id, name, secret_hash, redirect_uris, scopes, secret_rotated_at, created_at, updated_at`
+
+type apiClientRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewAPIClientRepository creates a new APIClientRepository instance
+func NewAPIClientRepository(db *pgxpool.Pool) repository.APIClientRepository {
+	return &apiClientRepository{
+		db: db,
+	}
+}
+
+// Create menyimpan API client baru
+func (r *apiClientRepository) Create(ctx context.Context, client *entities.APIClient) error {
+	query := `
+		INSERT INTO api_clients (id, owner_id, client_id, name, secret_hash, redirect_uris, scopes, secret_rotated_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := retrying(r.db).Exec(ctx, query, client.ID, client.OwnerID, client.ClientID, client.Name, client.SecretHash,
+		client.RedirectURIs, client.Scopes, client.SecretRotatedAt, client.CreatedAt, client.UpdatedAt)
+	if err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// GetByID mengambil API client berdasarkan ID
+func (r *apiClientRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.APIClient, error) {
+	return r.get(ctx, `SELECT `+apiClientColumns+` FROM api_clients WHERE id = $1`, id)
+}
+
+// GetByClientID mengambil API client berdasarkan client ID publiknya
+func (r *apiClientRepository) GetByClientID(ctx context.Context, clientID string) (*entities.APIClient, error) {
+	return r.get(ctx, `SELECT `+apiClientColumns+` FROM api_clients WHERE client_id = $1`, clientID)
+}
+
+func (r *apiClientRepository) get(ctx context.Context, query string, arg any) (*entities.APIClient, error) {
+	client, err := scanAPIClient(retrying(r.db).QueryRow(ctx, query, arg))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrAPIClientNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return client, nil
+}
+
+// List mengambil daftar API client terbaru lebih dulu
+func (r *apiClientRepository) List(ctx context.Context, ownerID *uuid.UUID, limit, offset int) ([]*entities.APIClient, int64, error) {
+	where := ` WHERE ($1::uuid IS NULL OR owner_id = $1)`
+
+	var total int64
+	if err := retrying(r.db).QueryRow(ctx, `SELECT COUNT(*) FROM api_clients`+where, ownerID).Scan(&total); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	query := `SELECT ` + apiClientColumns + ` FROM api_clients` + where + ` ORDER BY created_at DESC LIMIT $2 OFFSET $3`
+	rows, err := retrying(r.db).Query(ctx, query, ownerID, limit, offset)
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+	defer rows.Close()
+
+	clients := make([]*entities.APIClient, 0, limit)
+	for rows.Next() {
+		client, err := scanAPIClient(rows)
+		if err != nil {
+			return nil, 0, wrapError(err)
+		}
+		clients = append(clients, client)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, wrapError(err)
+	}
+	return clients, total, nil
+}
+
+// CountByOwner menghitung API client milik seorang user
+func (r *apiClientRepository) CountByOwner(ctx context.Context, ownerID uuid.UUID) (int, error) {
+	var count int
+	if err := retrying(r.db).QueryRow(ctx, `SELECT COUNT(*) FROM api_clients WHERE owner_id = $1`, ownerID).Scan(&count); err != nil {
+		return 0, wrapError(err)
+	}
+	return count, nil
+}
+
+// Update menyimpan nama, redirect URI dan scope API client
+func (r *apiClientRepository) Update(ctx context.Context, client *entities.APIClient) error {
+	query := `UPDATE api_clients SET name = $1, redirect_uris = $2, scopes = $3, updated_at = $4 WHERE id = $5`
+	return r.exec(ctx, query, client.Name, client.RedirectURIs, client.Scopes, client.UpdatedAt, client.ID)
+}
+
+// UpdateSecret mengganti hash secret API client
+func (r *apiClientRepository) UpdateSecret(ctx context.Context, client *entities.APIClient) error {
+	query := `UPDATE api_clients SET secret_hash = $1, secret_rotated_at = $2, updated_at = $3 WHERE id = $4`
+	return r.exec(ctx, query, client.SecretHash, client.SecretRotatedAt, client.UpdatedAt, client.ID)
+}
+
+// Delete menghapus API client
+func (r *apiClientRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.exec(ctx, `DELETE FROM api_clients WHERE id = $1`, id)
+}
+
+// exec runs a statement affecting a single client
+func (r *apiClientRepository) exec(ctx context.Context, query string, args ...any) error {
+	res, err := retrying(r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrAPIClientNotFound
+	}
+	return nil
+}
+
+// scanAPIClient scans a single client row selected with apiClientColumns
+func scanAPIClient(row pgx.Row) (*entities.APIClient, error) {
+	var c entities.APIClient
+	err := row.Scan(&c.ID, &c.OwnerID, &c.ClientID, &c.Name, &c.SecretHash, &c.RedirectURIs, &c.Scopes,
+		&c.SecretRotatedAt, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,209 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type attributeRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewAttributeRepository creates a new AttributeRepository instance
+func NewAttributeRepository(db *pgxpool.Pool) repository.AttributeRepository {
+	return &attributeRepository{
+		db: db,
+	}
+}
+
+// CreateDefinition inserts a new attribute definition
+func (r *attributeRepository) CreateDefinition(ctx context.Context, def *entities.AttributeDefinition) error {
+	query := `
+		INSERT INTO attribute_definitions (id, category_id, code, name, type, unit, filterable, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+	`
+	_, err := retrying(r.db).Exec(ctx, query, def.ID, def.CategoryID, def.Code, def.Name, def.Type, def.Unit, def.Filterable)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return apperror.ErrAttributeExists
+		}
+		if isForeignKeyViolation(err) {
+			return apperror.ErrCategoryNotFound
+		}
+		return wrapError(err)
+	}
+	return nil
+}
+
+// GetDefinitionByID retrieves an attribute definition by its ID
+func (r *attributeRepository) GetDefinitionByID(ctx context.Context, id uuid.UUID) (*entities.AttributeDefinition, error) {
+	query := `SELECT id, category_id, code, name, type, unit, filterable, created_at, updated_at FROM attribute_definitions WHERE id = $1`
+
+	var def entities.AttributeDefinition
+	var unit *string
+	err := retrying(r.db).QueryRow(ctx, query, id).Scan(
+		&def.ID,
+		&def.CategoryID,
+		&def.Code,
+		&def.Name,
+		&def.Type,
+		&unit,
+		&def.Filterable,
+		&def.CreatedAt,
+		&def.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrAttributeNotFound
+		}
+		return nil, wrapError(err)
+	}
+	if unit != nil {
+		def.Unit = *unit
+	}
+	return &def, nil
+}
+
+// ListDefinitionsByCategory retrieves the attribute definitions of a category
+func (r *attributeRepository) ListDefinitionsByCategory(ctx context.Context, categoryID uuid.UUID) ([]*entities.AttributeDefinition, error) {
+	query := `
+		SELECT id, category_id, code, name, type, unit, filterable, created_at, updated_at
+		FROM attribute_definitions
+		WHERE category_id = $1
+		ORDER BY name
+	`
+	rows, err := retrying(r.db).Query(ctx, query, categoryID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	defs := make([]*entities.AttributeDefinition, 0)
+	for rows.Next() {
+		var def entities.AttributeDefinition
+		var unit *string
+		if err := rows.Scan(
+			&def.ID,
+			&def.CategoryID,
+			&def.Code,
+			&def.Name,
+			&def.Type,
+			&unit,
+			&def.Filterable,
+			&def.CreatedAt,
+			&def.UpdatedAt,
+		); err != nil {
+			return nil, wrapError(err)
+		}
+		if unit != nil {
+			def.Unit = *unit
+		}
+		defs = append(defs, &def)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return defs, nil
+}
+
+// UpdateDefinition updates an attribute definition
+func (r *attributeRepository) UpdateDefinition(ctx context.Context, def *entities.AttributeDefinition) error {
+	query := `UPDATE attribute_definitions SET code = $1, name = $2, unit = $3, filterable = $4, updated_at = NOW() WHERE id = $5`
+	res, err := retrying(r.db).Exec(ctx, query, def.Code, def.Name, def.Unit, def.Filterable, def.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return apperror.ErrAttributeExists
+		}
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrAttributeNotFound
+	}
+	return nil
+}
+
+// DeleteDefinition removes an attribute definition and its product values
+func (r *attributeRepository) DeleteDefinition(ctx context.Context, id uuid.UUID) error {
+	res, err := retrying(r.db).Exec(ctx, `DELETE FROM attribute_definitions WHERE id = $1`, id)
+	if err != nil {
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrAttributeNotFound
+	}
+	return nil
+}
+
+// SetProductValues replaces all attribute values of a product
+func (r *attributeRepository) SetProductValues(ctx context.Context, productID uuid.UUID, values []entities.ProductAttribute) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM product_attribute_values WHERE product_id = $1`, productID); err != nil {
+		return wrapError(err)
+	}
+
+	for _, v := range values {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO product_attribute_values (product_id, attribute_id, value) VALUES ($1, $2, $3)`,
+			productID, v.AttributeID, v.Value,
+		)
+		if err != nil {
+			return wrapError(err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// ListValuesByProductIDs retrieves the attribute values of several products in one query
+func (r *attributeRepository) ListValuesByProductIDs(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]entities.ProductAttribute, error) {
+	result := make(map[uuid.UUID][]entities.ProductAttribute, len(productIDs))
+	if len(productIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT v.product_id, v.attribute_id, d.code, d.name, d.type, d.unit, v.value
+		FROM product_attribute_values v
+		JOIN attribute_definitions d ON d.id = v.attribute_id
+		WHERE v.product_id = ANY($1)
+		ORDER BY v.product_id, d.name
+	`
+	rows, err := retrying(r.db).Query(ctx, query, productIDs)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a entities.ProductAttribute
+		var unit *string
+		if err := rows.Scan(&a.ProductID, &a.AttributeID, &a.Code, &a.Name, &a.Type, &unit, &a.Value); err != nil {
+			return nil, wrapError(err)
+		}
+		if unit != nil {
+			a.Unit = *unit
+		}
+		result[a.ProductID] = append(result[a.ProductID], a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return result, nil
+}
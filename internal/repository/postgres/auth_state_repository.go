@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// authStateBackupColumns is the column list scanned by scanAuthStateBackup
+const authStateBackupColumns = `id, trigger, requested_by, status, blacklisted, families, sessions, size, COALESCE(storage_key, ''), COALESCE(error, ''), created_at`
+
+type authStateBackupRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewAuthStateBackupRepository creates a new AuthStateBackupRepository instance
+func NewAuthStateBackupRepository(db *pgxpool.Pool) repository.AuthStateBackupRepository {
+	return &authStateBackupRepository{
+		db: db,
+	}
+}
+
+// CreateBackup mencatat backup auth state baru
+func (r *authStateBackupRepository) CreateBackup(ctx context.Context, backup *entities.AuthStateBackup) error {
+	query := `
+		INSERT INTO auth_state_backups (id, trigger, requested_by, status, blacklisted, families, sessions, size, storage_key, error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	_, err := retrying(r.db).Exec(ctx, query, backup.ID, backup.Trigger, backup.RequestedBy, backup.Status, backup.Blacklisted,
+		backup.Families, backup.Sessions, backup.Size, nullIfEmpty(backup.StorageKey), nullIfEmpty(backup.Error), backup.CreatedAt)
+	if err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// GetBackup mengambil backup berdasarkan ID
+func (r *authStateBackupRepository) GetBackup(ctx context.Context, id uuid.UUID) (*entities.AuthStateBackup, error) {
+	query := `SELECT ` + authStateBackupColumns + ` FROM auth_state_backups WHERE id = $1`
+
+	backup, err := scanAuthStateBackup(retrying(r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrAuthStateBackupNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return backup, nil
+}
+
+// LatestBackup mengambil backup terakhir yang berhasil
+func (r *authStateBackupRepository) LatestBackup(ctx context.Context) (*entities.AuthStateBackup, error) {
+	query := `SELECT ` + authStateBackupColumns + ` FROM auth_state_backups WHERE status = $1 ORDER BY created_at DESC LIMIT 1`
+
+	backup, err := scanAuthStateBackup(retrying(r.db).QueryRow(ctx, query, entities.AuthStateBackupCompleted))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, wrapError(err)
+	}
+	return backup, nil
+}
+
+// ListBackups mengambil daftar backup terbaru lebih dulu
+func (r *authStateBackupRepository) ListBackups(ctx context.Context, limit, offset int) ([]*entities.AuthStateBackup, int64, error) {
+	var total int64
+	if err := retrying(r.db).QueryRow(ctx, `SELECT COUNT(*) FROM auth_state_backups`).Scan(&total); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	query := `SELECT ` + authStateBackupColumns + ` FROM auth_state_backups ORDER BY created_at DESC LIMIT $1 OFFSET $2`
+	rows, err := retrying(r.db).Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+	defer rows.Close()
+
+	backups := make([]*entities.AuthStateBackup, 0, limit)
+	for rows.Next() {
+		backup, err := scanAuthStateBackup(rows)
+		if err != nil {
+			return nil, 0, wrapError(err)
+		}
+		backups = append(backups, backup)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, wrapError(err)
+	}
+	return backups, total, nil
+}
+
+// ExpireBackupsBefore menandai backup yang dibuat sebelum t sebagai expired dan
+// mengembalikan storage key lamanya
+func (r *authStateBackupRepository) ExpireBackupsBefore(ctx context.Context, t time.Time) ([]string, error) {
+	query := `
+		UPDATE auth_state_backups b
+		SET status = $1, storage_key = NULL
+		FROM (
+			SELECT id, storage_key FROM auth_state_backups
+			WHERE status = $2 AND created_at < $3
+			FOR UPDATE
+		) old
+		WHERE b.id = old.id
+		RETURNING COALESCE(old.storage_key, '')
+	`
+	rows, err := retrying(r.db).Query(ctx, query, entities.AuthStateBackupExpired, entities.AuthStateBackupCompleted, t)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	keys, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return keys, nil
+}
+
+// CreateRestore mencatat restore auth state dari sebuah backup
+func (r *authStateBackupRepository) CreateRestore(ctx context.Context, restore *entities.AuthStateRestore) error {
+	query := `
+		INSERT INTO auth_state_restores (id, backup_id, restored_by, restored, skipped, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := retrying(r.db).Exec(ctx, query, restore.ID, restore.BackupID, restore.RestoredBy, restore.Restored, restore.Skipped, restore.CreatedAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return apperror.ErrAuthStateBackupNotFound
+		}
+		return wrapError(err)
+	}
+	return nil
+}
+
+// ListRestores mengambil daftar restore terbaru lebih dulu
+func (r *authStateBackupRepository) ListRestores(ctx context.Context, limit, offset int) ([]*entities.AuthStateRestore, int64, error) {
+	var total int64
+	if err := retrying(r.db).QueryRow(ctx, `SELECT COUNT(*) FROM auth_state_restores`).Scan(&total); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	query := `
+		SELECT id, backup_id, restored_by, restored, skipped, created_at
+		FROM auth_state_restores
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2
+	`
+	rows, err := retrying(r.db).Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+	defer rows.Close()
+
+	restores := make([]*entities.AuthStateRestore, 0, limit)
+	for rows.Next() {
+		var re entities.AuthStateRestore
+		if err := rows.Scan(&re.ID, &re.BackupID, &re.RestoredBy, &re.Restored, &re.Skipped, &re.CreatedAt); err != nil {
+			return nil, 0, wrapError(err)
+		}
+		restores = append(restores, &re)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, wrapError(err)
+	}
+	return restores, total, nil
+}
+
+// scanAuthStateBackup scans a single backup row selected with authStateBackupColumns
+func scanAuthStateBackup(row pgx.Row) (*entities.AuthStateBackup, error) {
+	var b entities.AuthStateBackup
+	err := row.Scan(&b.ID, &b.Trigger, &b.RequestedBy, &b.Status, &b.Blacklisted, &b.Families, &b.Sessions,
+		&b.Size, &b.StorageKey, &b.Error, &b.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type catalogSnapshotRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewCatalogSnapshotRepository creates a new CatalogSnapshotRepository instance
+func NewCatalogSnapshotRepository(db *pgxpool.Pool) repository.CatalogSnapshotRepository {
+	return &catalogSnapshotRepository{
+		db: db,
+	}
+}
+
+// Create menyimpan snapshot katalog dalam bentuk JSON terkompresi gzip
+func (r *catalogSnapshotRepository) Create(ctx context.Context, snapshot *entities.CatalogSnapshot) error {
+	data, err := encodeSnapshotItems(snapshot.Items)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	query := `INSERT INTO catalog_snapshots (id, trigger, product_count, data, created_at) VALUES ($1, $2, $3, $4, $5)`
+	if _, err := retrying(r.db).Exec(ctx, query, snapshot.ID, snapshot.Trigger, snapshot.ProductCount, data, snapshot.CreatedAt); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// GetByID mengambil snapshot beserta isinya
+func (r *catalogSnapshotRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.CatalogSnapshot, error) {
+	query := `SELECT id, trigger, product_count, data, created_at FROM catalog_snapshots WHERE id = $1`
+
+	var snapshot entities.CatalogSnapshot
+	var data []byte
+	err := retrying(r.db).QueryRow(ctx, query, id).Scan(
+		&snapshot.ID,
+		&snapshot.Trigger,
+		&snapshot.ProductCount,
+		&data,
+		&snapshot.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrSnapshotNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	if snapshot.Items, err = decodeSnapshotItems(data); err != nil {
+		return nil, wrapError(err)
+	}
+	return &snapshot, nil
+}
+
+// List mengambil daftar snapshot terbaru tanpa isinya
+func (r *catalogSnapshotRepository) List(ctx context.Context, limit, offset int) ([]*entities.CatalogSnapshot, int64, error) {
+	var total int64
+	if err := retrying(r.db).QueryRow(ctx, `SELECT COUNT(*) FROM catalog_snapshots`).Scan(&total); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	query := `
+		SELECT id, trigger, product_count, created_at
+		FROM catalog_snapshots
+		ORDER BY created_at DESC LIMIT $1 OFFSET $2
+	`
+	rows, err := retrying(r.db).Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+	defer rows.Close()
+
+	snapshots := make([]*entities.CatalogSnapshot, 0, limit)
+	for rows.Next() {
+		var snapshot entities.CatalogSnapshot
+		if err := rows.Scan(&snapshot.ID, &snapshot.Trigger, &snapshot.ProductCount, &snapshot.CreatedAt); err != nil {
+			return nil, 0, wrapError(err)
+		}
+		snapshots = append(snapshots, &snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	return snapshots, total, nil
+}
+
+// DeleteOlderThan menghapus snapshot yang dibuat sebelum waktu tertentu
+func (r *catalogSnapshotRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	res, err := retrying(r.db).Exec(ctx, `DELETE FROM catalog_snapshots WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	return res.RowsAffected(), nil
+}
+
+// encodeSnapshotItems serializes snapshot items to gzip compressed JSON
+func encodeSnapshotItems(items []entities.SnapshotItem) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(items); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeSnapshotItems reverses encodeSnapshotItems
+func decodeSnapshotItems(data []byte) ([]entities.SnapshotItem, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]entities.SnapshotItem, 0)
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type categoryRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewCategoryRepository creates a new CategoryRepository instance
+func NewCategoryRepository(db *pgxpool.Pool) repository.CategoryRepository {
+	return &categoryRepository{
+		db: db,
+	}
+}
+
+// Create inserts a new category
+func (r *categoryRepository) Create(ctx context.Context, category *entities.Category) error {
+	query := `
+		INSERT INTO categories (id, parent_id, name, slug, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+	`
+	_, err := retrying(r.db).Exec(ctx, query, category.ID, category.ParentID, category.Name, category.Slug, category.Description)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return apperror.ErrCategoryExists
+		}
+		if isForeignKeyViolation(err) {
+			return apperror.ErrInvalidCategoryParent
+		}
+		return wrapError(err)
+	}
+	return nil
+}
+
+// GetByID retrieves a category by its ID
+func (r *categoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Category, error) {
+	query := `SELECT id, parent_id, name, slug, description, created_at, updated_at FROM categories WHERE id = $1`
+	return r.getOne(ctx, query, id)
+}
+
+// GetBySlug retrieves a category by its slug
+func (r *categoryRepository) GetBySlug(ctx context.Context, slug string) (*entities.Category, error) {
+	query := `SELECT id, parent_id, name, slug, description, created_at, updated_at FROM categories WHERE slug = $1`
+	return r.getOne(ctx, query, slug)
+}
+
+// getOne scans a single category row
+func (r *categoryRepository) getOne(ctx context.Context, query string, arg interface{}) (*entities.Category, error) {
+	var c entities.Category
+	var description *string
+	err := retrying(r.db).QueryRow(ctx, query, arg).Scan(
+		&c.ID,
+		&c.ParentID,
+		&c.Name,
+		&c.Slug,
+		&description,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrCategoryNotFound
+		}
+		return nil, wrapError(err)
+	}
+	if description != nil {
+		c.Description = *description
+	}
+	return &c, nil
+}
+
+// List retrieves all categories ordered by name
+func (r *categoryRepository) List(ctx context.Context) ([]*entities.Category, error) {
+	query := `SELECT id, parent_id, name, slug, description, created_at, updated_at FROM categories ORDER BY name`
+
+	rows, err := retrying(r.db).Query(ctx, query)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	categories := make([]*entities.Category, 0)
+	for rows.Next() {
+		var c entities.Category
+		var description *string
+		if err := rows.Scan(
+			&c.ID,
+			&c.ParentID,
+			&c.Name,
+			&c.Slug,
+			&description,
+			&c.CreatedAt,
+			&c.UpdatedAt,
+		); err != nil {
+			return nil, wrapError(err)
+		}
+		if description != nil {
+			c.Description = *description
+		}
+		categories = append(categories, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return categories, nil
+}
+
+// Update updates an existing category and keeps the denormalized product category slug in sync
+func (r *categoryRepository) Update(ctx context.Context, category *entities.Category) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE categories SET parent_id = $1, name = $2, slug = $3, description = $4, updated_at = NOW() WHERE id = $5`
+	res, err := tx.Exec(ctx, query, category.ParentID, category.Name, category.Slug, category.Description, category.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return apperror.ErrCategoryExists
+		}
+		if isForeignKeyViolation(err) {
+			return apperror.ErrInvalidCategoryParent
+		}
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrCategoryNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE products SET category = $1, version = version + 1 WHERE category_id = $2`, category.Slug, category.ID); err != nil {
+		return wrapError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// Delete removes a category; products in the category are left uncategorized
+func (r *categoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `UPDATE products SET category = NULL, version = version + 1 WHERE category_id = $1`, id); err != nil {
+		return wrapError(err)
+	}
+
+	res, err := tx.Exec(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return apperror.ErrCategoryInUse
+		}
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrCategoryNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
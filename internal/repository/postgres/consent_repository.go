@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type consentRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewConsentRepository untuk membuat instance baru dari ConsentRepository
+func NewConsentRepository(db *pgxpool.Pool) repository.ConsentRepository {
+	return &consentRepository{
+		db: db,
+	}
+}
+
+// ListByUserID mengambil persetujuan yang sudah dipilih seorang user
+func (r *consentRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Consent, error) {
+	rows, err := retrying(r.db).Query(ctx, `SELECT user_id, purpose, granted, updated_at FROM user_consents WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	consents := make([]*entities.Consent, 0)
+	for rows.Next() {
+		var c entities.Consent
+		if err := rows.Scan(&c.UserID, &c.Purpose, &c.Granted, &c.UpdatedAt); err != nil {
+			return nil, wrapError(err)
+		}
+		consents = append(consents, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return consents, nil
+}
+
+// Get mengambil persetujuan seorang user untuk satu tujuan
+func (r *consentRepository) Get(ctx context.Context, userID uuid.UUID, purpose entities.ConsentPurpose) (*entities.Consent, error) {
+	var c entities.Consent
+	err := retrying(r.db).QueryRow(ctx,
+		`SELECT user_id, purpose, granted, updated_at FROM user_consents WHERE user_id = $1 AND purpose = $2`,
+		userID, purpose,
+	).Scan(&c.UserID, &c.Purpose, &c.Granted, &c.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrConsentNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return &c, nil
+}
+
+// Record menyimpan pilihan persetujuan sebagai persetujuan terkini dan mencatatnya
+func (r *consentRepository) Record(ctx context.Context, records []*entities.ConsentRecord) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, record := range records {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO consent_records (id, user_id, purpose, granted, ip, user_agent, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, NOW())
+			RETURNING created_at`,
+			record.ID, record.UserID, record.Purpose, record.Granted, record.IP, record.UserAgent,
+		).Scan(&record.CreatedAt)
+		if err != nil {
+			return wrapError(err)
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO user_consents (user_id, purpose, granted, updated_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_id, purpose) DO UPDATE SET granted = EXCLUDED.granted, updated_at = EXCLUDED.updated_at`,
+			record.UserID, record.Purpose, record.Granted, record.CreatedAt,
+		)
+		if err != nil {
+			return wrapError(err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// ListRecords mengambil catatan persetujuan seorang user, terbaru lebih dulu
+func (r *consentRepository) ListRecords(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.ConsentRecord, int64, error) {
+	var total int64
+	if err := retrying(r.db).QueryRow(ctx, `SELECT COUNT(*) FROM consent_records WHERE user_id = $1`, userID).Scan(&total); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	query := `
+		SELECT id, user_id, purpose, granted, ip, user_agent, created_at
+		FROM consent_records
+		WHERE user_id = $1
+		ORDER BY created_at DESC, purpose
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := retrying(r.db).Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+	defer rows.Close()
+
+	records := make([]*entities.ConsentRecord, 0, limit)
+	for rows.Next() {
+		var c entities.ConsentRecord
+		if err := rows.Scan(&c.ID, &c.UserID, &c.Purpose, &c.Granted, &c.IP, &c.UserAgent, &c.CreatedAt); err != nil {
+			return nil, 0, wrapError(err)
+		}
+		records = append(records, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, wrapError(err)
+	}
+	return records, total, nil
+}
@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const emailTemplateColumns = `id, key, locale, version, subject, html_body, text_body, status, created_by, published_at, created_at, updated_at`
+
+type emailTemplateRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewEmailTemplateRepository untuk membuat instance baru dari EmailTemplateRepository
+func NewEmailTemplateRepository(db *pgxpool.Pool) repository.EmailTemplateRepository {
+	return &emailTemplateRepository{
+		db: db,
+	}
+}
+
+// Create menyimpan template sebagai versi berikutnya dari key dan locale yang sama
+func (r *emailTemplateRepository) Create(ctx context.Context, template *entities.EmailTemplate) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// versi dihitung dari versi terakhir, lock mencegah dua draft mendapat nomor yang sama
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1 || '/' || $2))`, template.Key, template.Locale); err != nil {
+		return wrapError(err)
+	}
+
+	query := `
+		INSERT INTO email_templates (id, key, locale, version, subject, html_body, text_body, status, created_by, created_at, updated_at)
+		SELECT $1, $2, $3, COALESCE(MAX(version), 0) + 1, $4, $5, $6, $7, $8, $9, $10
+		FROM email_templates WHERE key = $2 AND locale = $3
+		RETURNING version
+	`
+	err = tx.QueryRow(ctx, query,
+		template.ID, template.Key, template.Locale, template.Subject, template.HTMLBody, template.TextBody,
+		template.Status, template.CreatedBy, template.CreatedAt, template.UpdatedAt,
+	).Scan(&template.Version)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// GetByID mengambil satu versi template
+func (r *emailTemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.EmailTemplate, error) {
+	query := `SELECT ` + emailTemplateColumns + ` FROM email_templates WHERE id = $1`
+
+	template, err := scanEmailTemplate(retrying(r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmailTemplateNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return template, nil
+}
+
+// List mengambil daftar versi template, difilter berdasarkan key dan locale bila diisi
+func (r *emailTemplateRepository) List(ctx context.Context, key, locale string, limit, offset int) ([]*entities.EmailTemplate, int64, error) {
+	where := ` WHERE ($1 = '' OR key = $1) AND ($2 = '' OR locale = $2)`
+
+	var total int64
+	if err := retrying(r.db).QueryRow(ctx, `SELECT COUNT(*) FROM email_templates`+where, key, locale).Scan(&total); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	query := `SELECT ` + emailTemplateColumns + ` FROM email_templates` + where + ` ORDER BY key, locale, version DESC LIMIT $3 OFFSET $4`
+	rows, err := retrying(r.db).Query(ctx, query, key, locale, limit, offset)
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+	defer rows.Close()
+
+	templates := make([]*entities.EmailTemplate, 0, limit)
+	for rows.Next() {
+		template, err := scanEmailTemplate(rows)
+		if err != nil {
+			return nil, 0, wrapError(err)
+		}
+		templates = append(templates, template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, wrapError(err)
+	}
+	return templates, total, nil
+}
+
+// GetActive mengambil versi aktif untuk locale pertama yang memilikinya
+func (r *emailTemplateRepository) GetActive(ctx context.Context, key string, locales []string) (*entities.EmailTemplate, error) {
+	query := `
+		SELECT ` + emailTemplateColumns + `
+		FROM email_templates
+		WHERE key = $1 AND status = $2 AND locale = ANY($3)
+		ORDER BY array_position($3, locale::text)
+		LIMIT 1
+	`
+
+	template, err := scanEmailTemplate(retrying(r.db).QueryRow(ctx, query, key, entities.EmailTemplateActive, locales))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmailTemplateNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return template, nil
+}
+
+// Publish mengaktifkan sebuah versi dan mengarsipkan versi yang sebelumnya aktif
+func (r *emailTemplateRepository) Publish(ctx context.Context, id uuid.UUID) (*entities.EmailTemplate, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var key, locale string
+	if err := tx.QueryRow(ctx, `SELECT key, locale FROM email_templates WHERE id = $1 FOR UPDATE`, id).Scan(&key, &locale); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmailTemplateNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	archive := `
+		UPDATE email_templates SET status = $1, updated_at = NOW()
+		WHERE key = $2 AND locale = $3 AND status = $4 AND id <> $5
+	`
+	if _, err := tx.Exec(ctx, archive, entities.EmailTemplateArchived, key, locale, entities.EmailTemplateActive, id); err != nil {
+		return nil, wrapError(err)
+	}
+
+	query := `
+		UPDATE email_templates SET status = $1, published_at = NOW(), updated_at = NOW()
+		WHERE id = $2
+		RETURNING ` + emailTemplateColumns
+	template, err := scanEmailTemplate(tx.QueryRow(ctx, query, entities.EmailTemplateActive, id))
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, wrapError(err)
+	}
+	return template, nil
+}
+
+// scanEmailTemplate scans a row selected with emailTemplateColumns
+func scanEmailTemplate(row pgx.Row) (*entities.EmailTemplate, error) {
+	var template entities.EmailTemplate
+	var status string
+	if err := row.Scan(
+		&template.ID,
+		&template.Key,
+		&template.Locale,
+		&template.Version,
+		&template.Subject,
+		&template.HTMLBody,
+		&template.TextBody,
+		&status,
+		&template.CreatedBy,
+		&template.PublishedAt,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	template.Status = entities.EmailTemplateStatus(status)
+	return &template, nil
+}
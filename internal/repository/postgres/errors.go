@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	apperror "postgresDB/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SQLSTATE codes repositories map to domain errors
+const (
+	codeUniqueViolation     = "23505"
+	codeForeignKeyViolation = "23503"
+	// a statement stopped by statement_timeout or by a cancel request
+	codeQueryCanceled = "57014"
+	// transactions the server rolled back, which succeed when run again
+	codeSerializationFailure = "40001"
+	codeDeadlockDetected     = "40P01"
+)
+
+// pgErrorCode returns the SQLSTATE of a PostgreSQL error, "" for other errors. Unlike the
+// message, the code doesn't depend on the language of the server
+func pgErrorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
+
+// isUniqueViolation checks if the error is a unique constraint violation
+func isUniqueViolation(err error) bool {
+	return pgErrorCode(err) == codeUniqueViolation
+}
+
+// isForeignKeyViolation checks if the error is a foreign key constraint violation
+func isForeignKeyViolation(err error) bool {
+	return pgErrorCode(err) == codeForeignKeyViolation
+}
+
+// isRetryable reports whether running the failed statement or transaction again may
+// succeed: the server rolled it back over a conflict with another transaction, or the
+// connection failed before anything was sent
+func isRetryable(err error) bool {
+	switch pgErrorCode(err) {
+	case codeSerializationFailure, codeDeadlockDetected:
+		return true
+	}
+	return pgconn.SafeToRetry(err)
+}
+
+// uniqueConstraint returns the name of the unique constraint or index the error violated,
+// "" when it isn't a unique violation
+func uniqueConstraint(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == codeUniqueViolation {
+		return pgErr.ConstraintName
+	}
+	return ""
+}
+
+// wrapError wraps an error of a query as internal error, as a query timeout when the
+// statement_timeout or the deadline of the query stopped it, or as cancelled when its
+// context was cancelled. pgx cancels the statement on the server for both, so the
+// context is checked before the SQLSTATE
+func wrapError(err error) *apperror.AppError {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return apperror.WrapQueryTimeout(err)
+	case errors.Is(err, context.Canceled):
+		return apperror.WrapCanceled(err)
+	case pgErrorCode(err) == codeQueryCanceled:
+		return apperror.WrapQueryTimeout(err)
+	}
+	return apperror.WrapInternal(err)
+}
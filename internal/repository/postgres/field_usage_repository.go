@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/repository"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type fieldUsageRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewFieldUsageRepository untuk membuat instance baru dari FieldUsageRepository
+func NewFieldUsageRepository(db *pgxpool.Pool) repository.FieldUsageRepository {
+	return &fieldUsageRepository{
+		db: db,
+	}
+}
+
+// Add menambahkan jumlah pemakaian field dalam satu query
+func (r *fieldUsageRepository) Add(ctx context.Context, usage []entities.FieldUsage) error {
+	if len(usage) == 0 {
+		return nil
+	}
+
+	routes := make([]string, len(usage))
+	directions := make([]string, len(usage))
+	fields := make([]string, len(usage))
+	clients := make([]string, len(usage))
+	counts := make([]int64, len(usage))
+	firstSeen := make([]time.Time, len(usage))
+	lastSeen := make([]time.Time, len(usage))
+	for i, u := range usage {
+		routes[i] = u.Route
+		directions[i] = string(u.Direction)
+		fields[i] = u.Field
+		clients[i] = u.ClientID
+		counts[i] = u.Count
+		firstSeen[i] = u.FirstSeen
+		lastSeen[i] = u.LastSeen
+	}
+
+	query := `
+		INSERT INTO field_usage (route, direction, field, client_id, count, first_seen, last_seen)
+		SELECT * FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::bigint[], $6::timestamptz[], $7::timestamptz[])
+		ON CONFLICT (route, direction, field, client_id) DO UPDATE
+		SET count = field_usage.count + EXCLUDED.count,
+		    first_seen = LEAST(field_usage.first_seen, EXCLUDED.first_seen),
+		    last_seen = GREATEST(field_usage.last_seen, EXCLUDED.last_seen)
+	`
+	if _, err := retrying(r.db).Exec(ctx, query, routes, directions, fields, clients, counts, firstSeen, lastSeen); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// List mengambil pemakaian field sesuai filter
+func (r *fieldUsageRepository) List(ctx context.Context, filter entities.FieldUsageFilter) ([]entities.FieldUsage, error) {
+	query := `
+		SELECT route, direction, field, client_id, count, first_seen, last_seen
+		FROM field_usage
+		WHERE ($1 = '' OR route = $1)
+		  AND ($2 = '' OR field = $2 OR starts_with(field, $2 || '.') OR starts_with(field, $2 || '[]'))
+		  AND ($3::text IS NULL OR client_id = $3)
+		  AND ($4::timestamptz IS NULL OR last_seen >= $4)
+		ORDER BY route, direction, field, count DESC, client_id
+	`
+	rows, err := retrying(r.db).Query(ctx, query, filter.Route, filter.Field, filter.ClientID, filter.Since)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	usage := make([]entities.FieldUsage, 0)
+	for rows.Next() {
+		var u entities.FieldUsage
+		var direction string
+		if err := rows.Scan(&u.Route, &direction, &u.Field, &u.ClientID, &u.Count, &u.FirstSeen, &u.LastSeen); err != nil {
+			return nil, wrapError(err)
+		}
+		u.Direction = entities.FieldDirection(direction)
+		usage = append(usage, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return usage, nil
+}
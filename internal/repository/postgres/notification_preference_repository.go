@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type notificationPreferenceRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewNotificationPreferenceRepository untuk membuat instance baru dari NotificationPreferenceRepository
+func NewNotificationPreferenceRepository(db *pgxpool.Pool) repository.NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{
+		db: db,
+	}
+}
+
+// ListByUserID mengambil preferensi notifikasi yang sudah dipilih seorang user
+func (r *notificationPreferenceRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.NotificationPreference, error) {
+	rows, err := retrying(r.db).Query(ctx,
+		`SELECT user_id, kind, channel, enabled, updated_at FROM notification_preferences WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	preferences := make([]*entities.NotificationPreference, 0)
+	for rows.Next() {
+		var p entities.NotificationPreference
+		if err := rows.Scan(&p.UserID, &p.Kind, &p.Channel, &p.Enabled, &p.UpdatedAt); err != nil {
+			return nil, wrapError(err)
+		}
+		preferences = append(preferences, &p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return preferences, nil
+}
+
+// Get mengambil preferensi seorang user untuk satu jenis notifikasi pada satu channel
+func (r *notificationPreferenceRepository) Get(ctx context.Context, userID uuid.UUID, kind entities.NotificationKind, channel string) (*entities.NotificationPreference, error) {
+	var p entities.NotificationPreference
+	err := retrying(r.db).QueryRow(ctx, `
+		SELECT user_id, kind, channel, enabled, updated_at FROM notification_preferences
+		WHERE user_id = $1 AND kind = $2 AND channel = $3`,
+		userID, kind, channel,
+	).Scan(&p.UserID, &p.Kind, &p.Channel, &p.Enabled, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNotificationPreferenceNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return &p, nil
+}
+
+// Save menyimpan preferensi notifikasi, menggantikan pilihan sebelumnya
+func (r *notificationPreferenceRepository) Save(ctx context.Context, preferences []*entities.NotificationPreference) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO notification_preferences (user_id, kind, channel, enabled, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (user_id, kind, channel) DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = EXCLUDED.updated_at
+		RETURNING updated_at
+	`
+	for _, p := range preferences {
+		if err := tx.QueryRow(ctx, query, p.UserID, p.Kind, p.Channel, p.Enabled).Scan(&p.UpdatedAt); err != nil {
+			if isForeignKeyViolation(err) {
+				return apperror.ErrUserNotFound
+			}
+			return wrapError(err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
@@ -7,34 +7,42 @@ import (
 	"postgresDB/internal/domain/entities"
 	apperror "postgresDB/internal/domain/errors"
 	"postgresDB/internal/domain/repository"
+	"postgresDB/pkg/pagination"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// orderColumns is the column list scanned by scanOrder
+const orderColumns = `id, customer_id, status, total_amount, subtotal, tax_amount, tax_lines, COALESCE(carrier, ''), COALESCE(tracking_number, ''), shipping_address, COALESCE(shipping_method, ''), shipping_cost, created_at, updated_at`
+
 type orderRepository struct {
-	db *pgxpool.Pool
+	db       *pgxpool.Pool
+	replicas *Replicas
 }
 
-// NewOrderRepository creates a new OrderRepository instance
-func NewOrderRepository(db *pgxpool.Pool) repository.OrderRepository {
+// NewOrderRepository creates a new OrderRepository instance, reads allowed to be stale
+// go to replicas
+func NewOrderRepository(db *pgxpool.Pool, replicas *Replicas) repository.OrderRepository {
 	return &orderRepository{
-		db: db,
+		db:       db,
+		replicas: replicas,
 	}
 }
 
 func (r *orderRepository) Create(ctx context.Context, order *entities.Order) error {
 	// Start a transaction
-	tx, err := r.db.Begin(ctx)
+	tx, err := conn(ctx, r.db).Begin(ctx)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback(ctx)
 	// Insert order
 	orderQuery := `
-		INSERT INTO orders (id, customer_id, status, total_amount, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO orders (id, customer_id, status, total_amount, subtotal, tax_amount, tax_lines, shipping_address, shipping_method, shipping_cost, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10, $11, $12)
 	`
 
 	_, err = tx.Exec(ctx, orderQuery,
@@ -42,63 +50,80 @@ func (r *orderRepository) Create(ctx context.Context, order *entities.Order) err
 		order.CustomerID,
 		order.Status,
 		order.TotalAmount,
+		order.Subtotal,
+		order.TaxAmount,
+		order.TaxLines,
+		order.ShippingAddress,
+		order.ShippingMethod,
+		order.ShippingCost,
 		order.CreatedAt,
 		order.UpdatedAt,
 	)
 	if err != nil {
-		return apperror.WrapInternal(err)
+		return wrapError(err)
 	}
-	// Insert order items
-	itemQuery := `
-		INSERT INTO order_items (id, order_id, product_id, quantity, unit_price, subtotal, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-	`
-	for _, item := range order.Items {
-		_, err = tx.Exec(ctx, itemQuery,
-			item.ID,
-			item.OrderID,
-			item.ProductID,
-			item.Quantity,
-			item.UnitPrice,
-			item.SubTotal,
-			item.CreatedAt,
-		)
-		if err != nil {
-			return apperror.WrapInternal(err)
-		}
+	// Insert order items in one round trip, however large the order
+	rows := make([][]any, len(order.Items))
+	for i, item := range order.Items {
+		rows[i] = []any{item.ID, item.OrderID, item.ProductID, item.Quantity, item.UnitPrice, item.SubTotal, item.TaxAmount, item.CreatedAt}
+	}
+	if _, err := tx.CopyFrom(ctx,
+		pgx.Identifier{"order_items"},
+		[]string{"id", "order_id", "product_id", "quantity", "unit_price", "subtotal", "tax_amount", "created_at"},
+		pgx.CopyFromRows(rows),
+	); err != nil {
+		return wrapError(err)
+	}
+	// Start the status history
+	if err := insertStatusEvent(ctx, tx, order.ID, order.Status, ""); err != nil {
+		return err
+	}
+	if err := insertOutboxEvent(ctx, tx, entities.OutboxAggregateOrder, order.ID, entities.OutboxEventOrderCreated, orderCreatedEvent(order)); err != nil {
+		return err
 	}
 	// Commit the transaction
 	err = tx.Commit(ctx)
 	if err != nil {
-		return apperror.WrapInternal(err)
+		return wrapError(err)
 	}
 	return nil
 }
 
 // GetByID retrieves an order by its ID (without items)
 func (r *orderRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Order, error) {
-	query := `SELECT id, customer_id, status, total_amount, created_at, updated_at FROM orders WHERE id = $1`
-
-	var order entities.Order
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&order.ID,
-		&order.CustomerID,
-		&order.Status,
-		&order.TotalAmount,
-		&order.CreatedAt,
-		&order.UpdatedAt,
-	)
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE id = $1`
+
+	order, err := scanOrder(readConn(ctx, r.db, r.replicas).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrOrderNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	return order, nil
+}
+
+// GetByIDForUpdate retrieves an order with its items and locks it until the surrounding
+// transaction ends
+func (r *orderRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entities.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE id = $1 FOR UPDATE`
+
+	order, err := scanOrder(conn(ctx, r.db).QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperror.ErrOrderNotFound
 		}
-		return nil, apperror.WrapInternal(err)
+		return nil, wrapError(err)
 	}
 
-	return &order, nil
+	if order.Items, err = r.GetOrderItemsByOrderID(ctx, id); err != nil {
+		return nil, err
+	}
+	return order, nil
 }
 
-// GetByIDWithItems retrieves an order by its ID along with its items
+// GetByIDWithItems retrieves an order by its ID along with its items and shipments
 func (r *orderRepository) GetByIDWithItems(ctx context.Context, id uuid.UUID) (*entities.Order, error) {
 	order, err := r.GetByID(ctx, id)
 	if err != nil {
@@ -110,146 +135,392 @@ func (r *orderRepository) GetByIDWithItems(ctx context.Context, id uuid.UUID) (*
 		return nil, err
 	}
 	order.Items = items
+	if order.Shipments, err = listShipments(ctx, readConn(ctx, r.db, r.replicas), id); err != nil {
+		return nil, err
+	}
 
 	return order, nil
 }
 
-// GetByCustomerID terima customerID dan mengembalikan daftar pesanan yang terkait dengan customer tersebut
-func (r *orderRepository) GetByCustomerID(ctx context.Context, customerID uuid.UUID, limit, offset int, status string) ([]*entities.Order, int64, error) {
-	// Build count query
-	countQuery := `SELECT COUNT(*) FROM orders WHERE customer_id = $1`
-	args := []interface{}{customerID}
-	argsIndex := 2
+// GetByIDsWithItems retrieves several orders along with their items in two queries
+func (r *orderRepository) GetByIDsWithItems(ctx context.Context, ids []uuid.UUID) ([]*entities.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE id = ANY($1) ORDER BY created_at`
+	rows, err := readConn(ctx, r.db, r.replicas).Query(ctx, query, ids)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
 
-	// Add status filter if provided
-	if status != "" {
-		countQuery += fmt.Sprintf(" AND status = $%d", argsIndex)
-		args = append(args, status)
-		argsIndex++
+	orders := make([]*entities.Order, 0, len(ids))
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
 	}
 
-	var total int64
-	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
-		return nil, 0, apperror.WrapInternal(err)
+	if err := r.attachItems(ctx, orders); err != nil {
+		return nil, err
 	}
 
-	// Build main query
-	query := `SELECT id, customer_id, status, total_amount, created_at, updated_at FROM orders WHERE customer_id = $1`
-	args = []interface{}{customerID}
-	argsIndex = 2
+	return orders, nil
+}
 
-	// Add status filter if provided
-	if status != "" {
-		query += fmt.Sprintf(" AND status = $%d", argsIndex)
-		args = append(args, status)
-		argsIndex++
-	}
+// GetByCustomerID terima customerID dan mengembalikan daftar pesanan yang terkait dengan customer tersebut
+func (r *orderRepository) GetByCustomerID(ctx context.Context, customerID uuid.UUID, limit, offset int, filter repository.OrderFilter) ([]*entities.Order, int64, error) {
+	filter.CustomerID = &customerID
+	return r.ListAll(ctx, limit, offset, filter)
+}
 
-	// Add pagination
-	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argsIndex, argsIndex+1)
+// ListAll mengambil daftar order yang cocok dengan filter, terbaru lebih dulu. Total
+// dihitung dalam query yang sama dengan COUNT(*) OVER(), kecuali untuk halaman di luar
+// jangkauan yang tidak punya baris untuk membawanya
+func (r *orderRepository) ListAll(ctx context.Context, limit, offset int, filter repository.OrderFilter) ([]*entities.Order, int64, error) {
+	where, args, argIndex := orderFilterClause(filter)
+
+	query := `SELECT ` + orderColumns + `, COUNT(*) OVER() FROM orders` + where
+	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
 	args = append(args, limit, offset)
 
-	rows, err := r.db.Query(ctx, query, args...)
+	rows, err := readConn(ctx, r.db, r.replicas).Query(ctx, query, args...)
 	if err != nil {
-		return nil, 0, apperror.WrapInternal(err)
+		return nil, 0, wrapError(err)
 	}
 	defer rows.Close()
 
+	var total int64
 	orders := make([]*entities.Order, 0, limit)
 	for rows.Next() {
-		var order entities.Order
-		if err := rows.Scan(
-			&order.ID,
-			&order.CustomerID,
-			&order.Status,
-			&order.TotalAmount,
-			&order.CreatedAt,
-			&order.UpdatedAt,
-		); err != nil {
-			return nil, 0, apperror.WrapInternal(err)
+		order, err := scanOrder(rows, &total)
+		if err != nil {
+			return nil, 0, wrapError(err)
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, wrapError(err)
+	}
+	rows.Close()
+
+	if len(orders) == 0 && offset > 0 {
+		if err := readConn(ctx, r.db, r.replicas).QueryRow(ctx, `SELECT COUNT(*) FROM orders`+where, args[:argIndex-1]...).Scan(&total); err != nil {
+			return nil, 0, wrapError(err)
 		}
-		orders = append(orders, &order)
 	}
 
+	if filter.WithItems {
+		if err := r.attachItems(ctx, orders); err != nil {
+			return nil, 0, err
+		}
+	}
 	return orders, total, nil
 }
 
-// ListAll retrieves all orders with pagination and optional status filtering
-func (r *orderRepository) ListAll(ctx context.Context, limit, offset int, status string) ([]*entities.Order, int64, error) {
-	count := `SELECT COUNT(*) FROM orders WHERE 1=1`
-	args := make([]interface{}, 0)
-	argIndex := 1
+// ListAfter retrieves a page of orders matching the filter after the cursor (keyset
+// pagination); no total is counted
+func (r *orderRepository) ListAfter(ctx context.Context, limit int, after *pagination.Cursor, filter repository.OrderFilter) ([]*entities.Order, error) {
+	where, args, argIndex := orderFilterClause(filter)
+	query := `SELECT ` + orderColumns + ` FROM orders` + where
 
-	if status != "" {
-		count += fmt.Sprintf(" AND status = $%d", argIndex)
-		args = append(args, status)
-		argIndex++
+	if after != nil {
+		query += fmt.Sprintf(" AND (created_at, id) < ($%d, $%d)", argIndex, argIndex+1)
+		args = append(args, after.CreatedAt, after.ID)
+		argIndex += 2
 	}
 
-	var total int64
-	if err := r.db.QueryRow(ctx, count, args...).Scan(&total); err != nil {
-		return nil, 0, apperror.WrapInternal(err)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", argIndex)
+	args = append(args, limit)
+
+	rows, err := readConn(ctx, r.db, r.replicas).Query(ctx, query, args...)
+	if err != nil {
+		return nil, wrapError(err)
 	}
-	query := `SELECT id, customer_id, status, total_amount, created_at, updated_at FROM orders WHERE 1=1`
-	args = make([]interface{}, 0)
-	argIndex = 1
+	defer rows.Close()
 
-	if status != "" {
-		query += fmt.Sprintf(" AND status = $%d", argIndex)
-		args = append(args, status)
-		argIndex++
+	orders := make([]*entities.Order, 0, limit)
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		orders = append(orders, order)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	rows.Close()
 
-	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
-	args = append(args, limit, offset)
+	if filter.WithItems {
+		if err := r.attachItems(ctx, orders); err != nil {
+			return nil, err
+		}
+	}
+	return orders, nil
+}
+
+// CustomerStats menghitung jumlah, total belanja dan tanggal order terakhir dari order yang
+// sudah dibayar milik customer, beserta kategori yang paling banyak dibeli
+func (r *orderRepository) CustomerStats(ctx context.Context, customerID uuid.UUID, categories int) (*entities.CustomerOrderStats, error) {
+	sold := orderStatusNames(entities.SoldOrderStatuses)
+	stats := entities.CustomerOrderStats{FavoriteCategories: make([]entities.CategoryPurchases, 0, categories)}
+	err := readConn(ctx, r.db, r.replicas).QueryRow(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(total_amount), 0)::bigint, MAX(created_at)
+		FROM orders WHERE customer_id = $1 AND status = ANY($2)`, customerID, sold,
+	).Scan(&stats.Orders, &stats.TotalSpent, &stats.LastOrderAt)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if stats.Orders == 0 || categories <= 0 {
+		return &stats, nil
+	}
 
-	rows, err := r.db.Query(ctx, query, args...)
+	query := `
+		SELECT p.category_id, COALESCE(c.name, p.category), SUM(oi.quantity), SUM(oi.subtotal)::bigint
+		FROM order_items oi
+		JOIN orders o ON o.id = oi.order_id
+		JOIN products p ON p.id = oi.product_id
+		LEFT JOIN categories c ON c.id = p.category_id
+		WHERE o.customer_id = $1 AND o.status = ANY($2) AND COALESCE(c.name, p.category) <> ''
+		GROUP BY p.category_id, COALESCE(c.name, p.category)
+		ORDER BY SUM(oi.quantity) DESC, SUM(oi.subtotal) DESC, 2
+		LIMIT $3
+	`
+	rows, err := readConn(ctx, r.db, r.replicas).Query(ctx, query, customerID, sold, categories)
 	if err != nil {
-		return nil, 0, apperror.WrapInternal(err)
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c entities.CategoryPurchases
+		if err := rows.Scan(&c.CategoryID, &c.Category, &c.Quantity, &c.Spent); err != nil {
+			return nil, wrapError(err)
+		}
+		stats.FavoriteCategories = append(stats.FavoriteCategories, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return &stats, nil
+}
+
+// attachItems loads the items of all orders in one query and appends them to their order
+func (r *orderRepository) attachItems(ctx context.Context, orders []*entities.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(orders))
+	byID := make(map[uuid.UUID]*entities.Order, len(orders))
+	for i, order := range orders {
+		ids[i] = order.ID
+		byID[order.ID] = order
+	}
+
+	itemQuery := `SELECT id, order_id, product_id, quantity, unit_price, subtotal, tax_amount, created_at FROM order_items WHERE order_id = ANY($1) ORDER BY created_at`
+	rows, err := readConn(ctx, r.db, r.replicas).Query(ctx, itemQuery, ids)
+	if err != nil {
+		return wrapError(err)
 	}
 	defer rows.Close()
 
-	orders := make([]*entities.Order, 0, limit)
 	for rows.Next() {
-		var order entities.Order
+		var item entities.OrderItem
 		if err := rows.Scan(
-			&order.ID,
-			&order.CustomerID,
-			&order.Status,
-			&order.TotalAmount,
-			&order.CreatedAt,
-			&order.UpdatedAt,
+			&item.ID,
+			&item.OrderID,
+			&item.ProductID,
+			&item.Quantity,
+			&item.UnitPrice,
+			&item.SubTotal,
+			&item.TaxAmount,
+			&item.CreatedAt,
 		); err != nil {
-			return nil, 0, apperror.WrapInternal(err)
+			return wrapError(err)
+		}
+		if order, ok := byID[item.OrderID]; ok {
+			order.Items = append(order.Items, item)
 		}
-		orders = append(orders, &order)
 	}
+	if err := rows.Err(); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
 
-	return orders, total, nil
+// orderFilterClause builds the WHERE clause of an order listing and its arguments, and
+// returns the index of the next argument
+func orderFilterClause(filter repository.OrderFilter) (string, []any, int) {
+	where := ` WHERE 1=1`
+	args := make([]any, 0)
+	argIndex := 1
+
+	if filter.CustomerID != nil {
+		where += fmt.Sprintf(" AND customer_id = $%d", argIndex)
+		args = append(args, *filter.CustomerID)
+		argIndex++
+	}
+	if filter.CustomerEmail != "" {
+		where += fmt.Sprintf(" AND customer_id IN (SELECT id FROM users WHERE LOWER(email) LIKE $%d)", argIndex)
+		args = append(args, likeEscaper.Replace(strings.ToLower(filter.CustomerEmail))+"%")
+		argIndex++
+	}
+	if len(filter.Statuses) == 1 {
+		where += fmt.Sprintf(" AND status = $%d", argIndex)
+		args = append(args, filter.Statuses[0])
+		argIndex++
+	} else if len(filter.Statuses) > 1 {
+		where += fmt.Sprintf(" AND status = ANY($%d::order_status[])", argIndex)
+		args = append(args, filter.Statuses)
+		argIndex++
+	}
+	if !filter.From.IsZero() {
+		where += fmt.Sprintf(" AND created_at >= $%d", argIndex)
+		args = append(args, filter.From)
+		argIndex++
+	}
+	if !filter.To.IsZero() {
+		where += fmt.Sprintf(" AND created_at < $%d", argIndex)
+		args = append(args, filter.To)
+		argIndex++
+	}
+	if filter.MinTotal != nil {
+		where += fmt.Sprintf(" AND total_amount >= $%d", argIndex)
+		args = append(args, *filter.MinTotal)
+		argIndex++
+	}
+	if filter.MaxTotal != nil {
+		where += fmt.Sprintf(" AND total_amount <= $%d", argIndex)
+		args = append(args, *filter.MaxTotal)
+		argIndex++
+	}
+	return where, args, argIndex
 }
 
-// UpdateStatus updates the status of an order
+// likeEscaper escapes the LIKE wildcards of a search term, backslash is the default escape
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// UpdateStatus updates the status of an order and records it in the status history
 func (r *orderRepository) UpdateStatus(ctx context.Context, id uuid.UUID, newStatus entities.OrderStatus) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
 	query := `UPDATE orders SET status = $1, updated_at = NOW() WHERE id = $2`
 
-	res, err := r.db.Exec(ctx, query, newStatus, id)
+	res, err := tx.Exec(ctx, query, newStatus, id)
 	if err != nil {
-		return apperror.WrapInternal(err)
+		return wrapError(err)
 	}
 	if res.RowsAffected() == 0 {
 		return apperror.ErrOrderNotFound
 	}
+
+	if err := insertStatusEvent(ctx, tx, id, newStatus, ""); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
 	return nil
 }
 
+// GetStatusHistory retrieves the status changes of an order, oldest first
+func (r *orderRepository) GetStatusHistory(ctx context.Context, orderID uuid.UUID) ([]entities.OrderStatusEvent, error) {
+	query := `SELECT id, order_id, status, COALESCE(note, ''), created_at FROM order_status_history WHERE order_id = $1 ORDER BY created_at`
+
+	rows, err := readConn(ctx, r.db, r.replicas).Query(ctx, query, orderID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	events := make([]entities.OrderStatusEvent, 0)
+	for rows.Next() {
+		var event entities.OrderStatusEvent
+		if err := rows.Scan(&event.ID, &event.OrderID, &event.Status, &event.Note, &event.CreatedAt); err != nil {
+			return nil, wrapError(err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return events, nil
+}
+
+// insertStatusEvent appends a status to the order history within a transaction. A change
+// of status is added to the outbox too, the first status is part of order.created
+func insertStatusEvent(ctx context.Context, tx pgx.Tx, orderID uuid.UUID, status entities.OrderStatus, note string) error {
+	var (
+		customerID uuid.UUID
+		previous   *entities.OrderStatus
+	)
+	err := tx.QueryRow(ctx, `
+		SELECT o.customer_id,
+			(SELECT h.status FROM order_status_history h WHERE h.order_id = o.id ORDER BY h.created_at DESC LIMIT 1)
+		FROM orders o WHERE o.id = $1`, orderID,
+	).Scan(&customerID, &previous)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	query := `INSERT INTO order_status_history (id, order_id, status, note, created_at) VALUES ($1, $2, $3, NULLIF($4, ''), NOW())`
+	if _, err := tx.Exec(ctx, query, uuid.New(), orderID, status, note); err != nil {
+		return wrapError(err)
+	}
+
+	if previous == nil {
+		return nil
+	}
+	return insertOutboxEvent(ctx, tx, entities.OutboxAggregateOrder, orderID, entities.OutboxEventOrderStatusChanged, entities.OrderStatusChangedEvent{
+		OrderID:        orderID,
+		CustomerID:     customerID,
+		Status:         status,
+		PreviousStatus: *previous,
+		Note:           note,
+	})
+}
+
+// orderCreatedEvent is the outbox data of a new order
+func orderCreatedEvent(order *entities.Order) entities.OrderCreatedEvent {
+	items := make([]entities.OrderCreatedItemData, len(order.Items))
+	for i, item := range order.Items {
+		items[i] = entities.OrderCreatedItemData{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+			Subtotal:  item.SubTotal,
+		}
+	}
+	return entities.OrderCreatedEvent{
+		OrderID:      order.ID,
+		CustomerID:   order.CustomerID,
+		Status:       order.Status,
+		Subtotal:     order.Subtotal,
+		TaxAmount:    order.TaxAmount,
+		ShippingCost: order.ShippingCost,
+		TotalAmount:  order.TotalAmount,
+		Items:        items,
+		CreatedAt:    order.CreatedAt,
+	}
+}
+
 // CreateOrderItem buat item pesanan baru
 func (r *orderRepository) CreateOrderItem(ctx context.Context, item *entities.OrderItem) error {
 	query := `
-		INSERT INTO order_items (id, order_id, product_id, quantity, unit_price, subtotal, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO order_items (id, order_id, product_id, quantity, unit_price, subtotal, tax_amount, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
-	_, err := r.db.Exec(ctx,
+	_, err := conn(ctx, r.db).Exec(ctx,
 		query,
 		item.ID,
 		item.OrderID,
@@ -257,21 +528,22 @@ func (r *orderRepository) CreateOrderItem(ctx context.Context, item *entities.Or
 		item.Quantity,
 		item.UnitPrice,
 		item.SubTotal,
+		item.TaxAmount,
 		item.CreatedAt,
 	)
 	if err != nil {
-		return apperror.WrapInternal(err)
+		return wrapError(err)
 	}
 	return nil
 }
 
 // GetOrderItemsByOrderID mengambil item pesanan berdasarkan ID pesanan
 func (r *orderRepository) GetOrderItemsByOrderID(ctx context.Context, orderID uuid.UUID) ([]entities.OrderItem, error) {
-	query := `SELECT id, order_id, product_id, quantity, unit_price, subtotal, created_at FROM order_items WHERE order_id = $1 ORDER BY created_at`
+	query := `SELECT id, order_id, product_id, quantity, unit_price, subtotal, tax_amount, created_at FROM order_items WHERE order_id = $1 ORDER BY created_at`
 
-	rows, err := r.db.Query(ctx, query, orderID)
+	rows, err := readConn(ctx, r.db, r.replicas).Query(ctx, query, orderID)
 	if err != nil {
-		return nil, apperror.WrapInternal(err)
+		return nil, wrapError(err)
 	}
 	defer rows.Close()
 
@@ -285,12 +557,26 @@ func (r *orderRepository) GetOrderItemsByOrderID(ctx context.Context, orderID uu
 			&item.Quantity,
 			&item.UnitPrice,
 			&item.SubTotal,
+			&item.TaxAmount,
 			&item.CreatedAt,
 		); err != nil {
-			return nil, apperror.WrapInternal(err)
+			return nil, wrapError(err)
 		}
 		items = append(items, item)
 	}
 
 	return items, nil
 }
+
+// scanOrder scans a single order row selected with orderColumns, without its items.
+// Columns selected after them are scanned into extra
+func scanOrder(row pgx.Row, extra ...any) (*entities.Order, error) {
+	var o entities.Order
+	dest := []any{&o.ID, &o.CustomerID, &o.Status, &o.TotalAmount, &o.Subtotal, &o.TaxAmount, &o.TaxLines, &o.Carrier, &o.TrackingNumber,
+		&o.ShippingAddress, &o.ShippingMethod, &o.ShippingCost, &o.CreatedAt, &o.UpdatedAt}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
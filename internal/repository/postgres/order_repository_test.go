@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// BenchmarkOrderRepositoryCreate compares Create, which copies the items in one round
+// trip, with inserting the items one statement at a time
+func BenchmarkOrderRepositoryCreate(b *testing.B) {
+	pool := testPool(b)
+	ctx := context.Background()
+	repo := NewOrderRepository(pool, nil)
+	txManager := NewTxManager(pool)
+
+	customer := &entities.User{
+		ID:       uuid.New(),
+		Username: "bench-" + uuid.NewString()[:8],
+		Email:    "bench-" + uuid.NewString() + "@example.com",
+		Password: "x",
+		Role:     entities.RoleUser,
+		IsActive: true,
+	}
+	if err := NewUserRepository(pool, nil).Create(ctx, customer); err != nil {
+		b.Fatalf("creating the customer: %v", err)
+	}
+	product := &entities.Product{ID: uuid.New(), Name: "order benchmark", Price: 1000, Stock: 1}
+	if err := NewProductRepository(pool, nil).Create(ctx, product); err != nil {
+		b.Fatalf("creating the product: %v", err)
+	}
+	b.Cleanup(func() {
+		ctx := context.Background()
+		pool.Exec(ctx, `DELETE FROM outbox_events WHERE aggregate_id IN (SELECT id FROM orders WHERE customer_id = $1)`, customer.ID)
+		pool.Exec(ctx, `DELETE FROM users WHERE id = $1`, customer.ID)
+		pool.Exec(ctx, `DELETE FROM products WHERE id = $1`, product.ID)
+	})
+
+	for _, size := range []int{1, 10, 100} {
+		b.Run(fmt.Sprintf("items=%d/copy", size), func(b *testing.B) {
+			for b.Loop() {
+				if err := repo.Create(ctx, benchmarkOrder(customer.ID, product.ID, size)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("items=%d/insert-per-item", size), func(b *testing.B) {
+			for b.Loop() {
+				order := benchmarkOrder(customer.ID, product.ID, size)
+				items := order.Items
+				order.Items = nil
+				err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+					if err := repo.Create(ctx, order); err != nil {
+						return err
+					}
+					for i := range items {
+						if err := repo.CreateOrderItem(ctx, &items[i]); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func benchmarkOrder(customerID, productID uuid.UUID, size int) *entities.Order {
+	now := time.Now()
+	order := &entities.Order{
+		ID:         uuid.New(),
+		CustomerID: customerID,
+		Status:     entities.OrderStatusPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	for range size {
+		order.Items = append(order.Items, entities.OrderItem{
+			ID:        uuid.New(),
+			OrderID:   order.ID,
+			ProductID: productID,
+			Quantity:  1,
+			UnitPrice: 1000,
+			SubTotal:  1000,
+			CreatedAt: now,
+		})
+		order.Subtotal += 1000
+	}
+	order.TotalAmount = order.Subtotal
+	return order
+}
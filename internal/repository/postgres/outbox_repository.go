@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// outboxRelayLock is the advisory lock held by the instance relaying the outbox, so the
+// events are published in order
+const outboxRelayLock = "outbox_relay"
+
+type outboxRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewOutboxRepository untuk membuat instance baru dari OutboxRepository
+func NewOutboxRepository(db *pgxpool.Pool) repository.OutboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+// ClaimPending mengambil event outbox yang belum dipublikasikan sambil memegang lock relay
+func (r *outboxRepository) ClaimPending(ctx context.Context, limit int) ([]*entities.OutboxEvent, error) {
+	db := conn(ctx, r.db)
+	var locked bool
+	if err := db.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock(hashtext($1))`, outboxRelayLock).Scan(&locked); err != nil {
+		return nil, wrapError(err)
+	}
+	if !locked {
+		return nil, nil
+	}
+
+	rows, err := db.Query(ctx, `
+		SELECT id, position, aggregate_type, aggregate_id, event_type, payload, created_at, published_at, attempts, last_error
+		FROM outbox_events WHERE published_at IS NULL ORDER BY position LIMIT $1`, limit)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	events := make([]*entities.OutboxEvent, 0, limit)
+	for rows.Next() {
+		var e entities.OutboxEvent
+		if err := rows.Scan(&e.ID, &e.Position, &e.AggregateType, &e.AggregateID, &e.EventType, &e.Payload,
+			&e.CreatedAt, &e.PublishedAt, &e.Attempts, &e.LastError); err != nil {
+			return nil, wrapError(err)
+		}
+		events = append(events, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return events, nil
+}
+
+// MarkPublished menandai event outbox sudah dipublikasikan
+func (r *outboxRepository) MarkPublished(ctx context.Context, ids []uuid.UUID, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := conn(ctx, r.db).Exec(ctx, `UPDATE outbox_events SET published_at = $1 WHERE id = ANY($2)`, at, ids); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// MarkFailed mencatat percobaan publikasi yang gagal
+func (r *outboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `UPDATE outbox_events SET attempts = attempts + 1, last_error = $1 WHERE id = $2`, reason, id)
+	if err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// Backlog menghitung event yang belum dipublikasikan beserta waktu event tertua
+func (r *outboxRepository) Backlog(ctx context.Context) (int64, *time.Time, error) {
+	var (
+		count  int64
+		oldest *time.Time
+	)
+	err := retrying(r.db).QueryRow(ctx, `SELECT COUNT(*), MIN(created_at) FROM outbox_events WHERE published_at IS NULL`).Scan(&count, &oldest)
+	if err != nil {
+		return 0, nil, wrapError(err)
+	}
+	return count, oldest, nil
+}
+
+// DeletePublishedBefore menghapus event yang dipublikasikan sebelum cutoff
+func (r *outboxRepository) DeletePublishedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := retrying(r.db).Exec(ctx, `DELETE FROM outbox_events WHERE published_at < $1`, cutoff)
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	return res.RowsAffected(), nil
+}
+
+// insertOutboxEvent adds an event about a change within the transaction making it
+func insertOutboxEvent(ctx context.Context, tx pgx.Tx, aggregateType string, aggregateID uuid.UUID, eventType string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return wrapError(err)
+	}
+	query := `
+		INSERT INTO outbox_events (id, aggregate_type, aggregate_id, event_type, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+	`
+	if _, err := tx.Exec(ctx, query, uuid.New(), aggregateType, aggregateID, eventType, payload); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const paymentCaptureColumns = `order_id, status, attempts, next_attempt_at, COALESCE(last_error, ''), created_at, updated_at`
+
+type paymentCaptureRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPaymentCaptureRepository untuk membuat instance baru dari PaymentCaptureRepository
+func NewPaymentCaptureRepository(db *pgxpool.Pool) repository.PaymentCaptureRepository {
+	return &paymentCaptureRepository{
+		db: db,
+	}
+}
+
+// Start memindahkan order pending ke pending_capture dan menjadwalkan capture pertama
+func (r *paymentCaptureRepository) Start(ctx context.Context, capture *entities.PaymentCapture, note string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	res, err := tx.Exec(ctx,
+		`UPDATE orders SET status = $1, updated_at = NOW() WHERE id = $2 AND status = $3`,
+		entities.OrderStatusPendingCapture, capture.OrderID, entities.OrderStatusPending,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrInvalidStatusTransition
+	}
+
+	query := `
+		INSERT INTO payment_captures (order_id, status, attempts, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	if _, err := tx.Exec(ctx, query, capture.OrderID, capture.Status, capture.Attempts, capture.NextAttemptAt, capture.CreatedAt, capture.UpdatedAt); err != nil {
+		return wrapError(err)
+	}
+
+	if err := insertStatusEvent(ctx, tx, capture.OrderID, entities.OrderStatusPendingCapture, note); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// GetByOrderID mengambil capture pembayaran sebuah order
+func (r *paymentCaptureRepository) GetByOrderID(ctx context.Context, orderID uuid.UUID) (*entities.PaymentCapture, error) {
+	query := `SELECT ` + paymentCaptureColumns + ` FROM payment_captures WHERE order_id = $1`
+
+	capture, err := scanPaymentCapture(retrying(r.db).QueryRow(ctx, query, orderID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrPaymentCaptureNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return capture, nil
+}
+
+// ListDue mengambil capture pending yang jadwal percobaannya sudah tiba
+func (r *paymentCaptureRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.PaymentCapture, error) {
+	query := `
+		SELECT ` + paymentCaptureColumns + `
+		FROM payment_captures
+		WHERE status = $1 AND next_attempt_at <= $2
+		ORDER BY next_attempt_at
+		LIMIT $3
+	`
+	rows, err := retrying(r.db).Query(ctx, query, entities.CaptureStatusPending, now, limit)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	captures := make([]*entities.PaymentCapture, 0)
+	for rows.Next() {
+		capture, err := scanPaymentCapture(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		captures = append(captures, capture)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return captures, nil
+}
+
+// Reschedule menyimpan percobaan yang gagal beserta jadwal berikutnya
+func (r *paymentCaptureRepository) Reschedule(ctx context.Context, capture *entities.PaymentCapture, note string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE payment_captures
+		SET attempts = $1, next_attempt_at = $2, last_error = NULLIF($3, ''), updated_at = NOW()
+		WHERE order_id = $4 AND status = $5
+	`
+	res, err := tx.Exec(ctx, query, capture.Attempts, capture.NextAttemptAt, capture.LastError, capture.OrderID, entities.CaptureStatusPending)
+	if err != nil {
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrPaymentCaptureNotFound
+	}
+
+	if err := insertStatusEvent(ctx, tx, capture.OrderID, entities.OrderStatusPendingCapture, note); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// ListCaptured mengambil pembayaran yang sudah di-capture dalam periode [from, to) dan
+// pembayaran dari order yang diberikan, nominalnya diambil dari total order
+func (r *paymentCaptureRepository) ListCaptured(ctx context.Context, from, to time.Time, orderIDs []uuid.UUID) ([]entities.RecordedPayment, error) {
+	query := `
+		SELECT pc.order_id, o.total_amount, pc.updated_at
+		FROM payment_captures pc
+		JOIN orders o ON o.id = pc.order_id
+		WHERE pc.status = $1 AND ((pc.updated_at >= $2 AND pc.updated_at < $3) OR pc.order_id = ANY($4))
+	`
+	rows, err := retrying(r.db).Query(ctx, query, entities.CaptureStatusCaptured, from, to, orderIDs)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	payments := make([]entities.RecordedPayment, 0)
+	for rows.Next() {
+		var p entities.RecordedPayment
+		if err := rows.Scan(&p.OrderID, &p.Amount, &p.CapturedAt); err != nil {
+			return nil, wrapError(err)
+		}
+		payments = append(payments, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return payments, nil
+}
+
+// Finish menutup capture dan memindahkan status order; order yang dibatalkan
+// mengembalikan stok produknya dalam transaksi yang sama
+func (r *paymentCaptureRepository) Finish(ctx context.Context, capture *entities.PaymentCapture, orderStatus entities.OrderStatus, note string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE payment_captures
+		SET status = $1, attempts = $2, last_error = NULLIF($3, ''), updated_at = NOW()
+		WHERE order_id = $4 AND status = $5
+	`
+	res, err := tx.Exec(ctx, query, capture.Status, capture.Attempts, capture.LastError, capture.OrderID, entities.CaptureStatusPending)
+	if err != nil {
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrPaymentCaptureNotFound
+	}
+
+	res, err = tx.Exec(ctx,
+		`UPDATE orders SET status = $1, updated_at = NOW() WHERE id = $2 AND status = $3`,
+		orderStatus, capture.OrderID, entities.OrderStatusPendingCapture,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrInvalidStatusTransition
+	}
+
+	switch orderStatus {
+	case entities.OrderStatusPaid:
+		quantities, _, err := settleReservations(ctx, tx, []uuid.UUID{capture.OrderID}, entities.ReservationConverted)
+		if err != nil {
+			return err
+		}
+		if _, err := deductReserved(ctx, tx, quantities); err != nil {
+			return err
+		}
+	case entities.OrderStatusCancelled:
+		quantities, released, err := settleReservations(ctx, tx, []uuid.UUID{capture.OrderID}, entities.ReservationReleased)
+		if err != nil {
+			return err
+		}
+		if err := releaseReserved(ctx, tx, quantities); err != nil {
+			return err
+		}
+		// orders placed before stock reservations had their stock deducted right away
+		if released == 0 {
+			restock := `
+				UPDATE products p SET stock = p.stock + oi.quantity, version = p.version + 1, updated_at = NOW()
+				FROM order_items oi
+				WHERE oi.order_id = $1 AND oi.product_id = p.id
+			`
+			if _, err := tx.Exec(ctx, restock, capture.OrderID); err != nil {
+				return wrapError(err)
+			}
+		}
+	}
+
+	if err := insertStatusEvent(ctx, tx, capture.OrderID, orderStatus, note); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// scanPaymentCapture scans a row selected with paymentCaptureColumns
+func scanPaymentCapture(row pgx.Row) (*entities.PaymentCapture, error) {
+	var capture entities.PaymentCapture
+	if err := row.Scan(
+		&capture.OrderID,
+		&capture.Status,
+		&capture.Attempts,
+		&capture.NextAttemptAt,
+		&capture.LastError,
+		&capture.CreatedAt,
+		&capture.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &capture, nil
+}
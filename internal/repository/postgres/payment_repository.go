@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const paymentColumns = `id, order_id, provider, provider_ref, status, amount, currency, client_secret, failure_reason, created_at, updated_at`
+
+type paymentRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPaymentRepository untuk membuat instance baru dari PaymentRepository
+func NewPaymentRepository(db *pgxpool.Pool) repository.PaymentRepository {
+	return &paymentRepository{
+		db: db,
+	}
+}
+
+// Create menyimpan pembayaran yang baru dibuat di penyedia pembayaran
+func (r *paymentRepository) Create(ctx context.Context, payment *entities.Payment) error {
+	query := `
+		INSERT INTO payments (id, order_id, provider, provider_ref, status, amount, currency, client_secret, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+	err := retrying(r.db).QueryRow(ctx, query,
+		payment.ID, payment.OrderID, payment.Provider, payment.ProviderRef, payment.Status, payment.Amount, payment.Currency, payment.ClientSecret,
+	).Scan(&payment.CreatedAt, &payment.UpdatedAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return apperror.ErrOrderNotFound
+		}
+		return wrapError(err)
+	}
+	return nil
+}
+
+// GetLatestByOrderID mengambil pembayaran terakhir dari sebuah order
+func (r *paymentRepository) GetLatestByOrderID(ctx context.Context, orderID uuid.UUID) (*entities.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE order_id = $1 ORDER BY created_at DESC LIMIT 1`
+	payment, err := scanPayment(retrying(r.db).QueryRow(ctx, query, orderID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrPaymentNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return payment, nil
+}
+
+// ListByOrderID mengambil semua pembayaran sebuah order
+func (r *paymentRepository) ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]*entities.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE order_id = $1 ORDER BY created_at, id`
+	rows, err := retrying(r.db).Query(ctx, query, orderID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	payments := make([]*entities.Payment, 0)
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		payments = append(payments, payment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return payments, nil
+}
+
+// ApplyEvent mencatat event webhook sekali dan memindahkan pembayaran ke status event;
+// pembayaran yang berhasil memindahkan order pending ke paid dalam transaksi yang sama
+func (r *paymentRepository) ApplyEvent(ctx context.Context, provider string, event *entities.PaymentEvent, note string) (*entities.Payment, bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, false, wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	res, err := tx.Exec(ctx,
+		`INSERT INTO payment_events (provider, event_id, received_at) VALUES ($1, $2, NOW()) ON CONFLICT DO NOTHING`,
+		provider, event.ID,
+	)
+	if err != nil {
+		return nil, false, wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return nil, false, nil
+	}
+
+	payment, err := scanPayment(tx.QueryRow(ctx,
+		`SELECT `+paymentColumns+` FROM payments WHERE provider = $1 AND provider_ref = $2 FOR UPDATE`,
+		provider, event.ProviderRef,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, apperror.ErrPaymentNotFound
+		}
+		return nil, false, wrapError(err)
+	}
+
+	// a settled payment keeps its status, providers may deliver events out of order
+	if payment.Status != entities.PaymentStatusPending || event.Status == entities.PaymentStatusPending {
+		if err := tx.Commit(ctx); err != nil {
+			return nil, false, wrapError(err)
+		}
+		return nil, false, nil
+	}
+	if event.Status == entities.PaymentStatusSucceeded && event.Amount != payment.Amount {
+		return nil, false, apperror.ErrPaymentAmountMismatch
+	}
+
+	err = tx.QueryRow(ctx,
+		`UPDATE payments SET status = $1, failure_reason = $2, updated_at = NOW() WHERE id = $3 RETURNING updated_at`,
+		event.Status, event.FailureReason, payment.ID,
+	).Scan(&payment.UpdatedAt)
+	if err != nil {
+		return nil, false, wrapError(err)
+	}
+	payment.Status = event.Status
+	payment.FailureReason = event.FailureReason
+
+	orderPaid := false
+	if payment.Status == entities.PaymentStatusSucceeded {
+		if orderPaid, err = markOrderPaid(ctx, tx, payment.OrderID, note); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, false, wrapError(err)
+	}
+	return payment, orderPaid, nil
+}
+
+// markOrderPaid moves a pending order to paid and deducts its reserved stock. An order
+// that isn't pending anymore, e.g. cancelled while the customer paid, is left as it is
+func markOrderPaid(ctx context.Context, tx pgx.Tx, orderID uuid.UUID, note string) (bool, error) {
+	res, err := tx.Exec(ctx,
+		`UPDATE orders SET status = $1, updated_at = NOW() WHERE id = $2 AND status = $3`,
+		entities.OrderStatusPaid, orderID, entities.OrderStatusPending,
+	)
+	if err != nil {
+		return false, wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	quantities, _, err := settleReservations(ctx, tx, []uuid.UUID{orderID}, entities.ReservationConverted)
+	if err != nil {
+		return false, err
+	}
+	if _, err := deductReserved(ctx, tx, quantities); err != nil {
+		return false, err
+	}
+	if err := insertStatusEvent(ctx, tx, orderID, entities.OrderStatusPaid, note); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// scanPayment scans a row selected with paymentColumns
+func scanPayment(row pgx.Row) (*entities.Payment, error) {
+	var payment entities.Payment
+	if err := row.Scan(
+		&payment.ID,
+		&payment.OrderID,
+		&payment.Provider,
+		&payment.ProviderRef,
+		&payment.Status,
+		&payment.Amount,
+		&payment.Currency,
+		&payment.ClientSecret,
+		&payment.FailureReason,
+		&payment.CreatedAt,
+		&payment.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
@@ -0,0 +1,244 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type productImageRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewProductImageRepository creates a new ProductImageRepository instance
+func NewProductImageRepository(db *pgxpool.Pool) repository.ProductImageRepository {
+	return &productImageRepository{
+		db: db,
+	}
+}
+
+// Create inserts a new image at the end of the product gallery.
+// The first image of a product becomes the primary image.
+func (r *productImageRepository) Create(ctx context.Context, image *entities.ProductImage) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the product row so concurrent uploads get distinct positions
+	var position int
+	var hasPrimary bool
+	err = tx.QueryRow(ctx, `
+		SELECT
+			COALESCE((SELECT MAX(position) + 1 FROM product_images WHERE product_id = p.id), 0),
+			EXISTS(SELECT 1 FROM product_images WHERE product_id = p.id AND is_primary)
+		FROM products p WHERE p.id = $1 FOR UPDATE
+	`, image.ProductID).Scan(&position, &hasPrimary)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.ErrProductNotFound
+		}
+		return wrapError(err)
+	}
+
+	image.Position = position
+	if !hasPrimary {
+		image.IsPrimary = true
+	} else if image.IsPrimary {
+		if _, err := tx.Exec(ctx, `UPDATE product_images SET is_primary = FALSE WHERE product_id = $1`, image.ProductID); err != nil {
+			return wrapError(err)
+		}
+	}
+
+	query := `
+		INSERT INTO product_images (id, product_id, url, storage_key, content_type, size, position, is_primary, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err = tx.Exec(ctx, query,
+		image.ID,
+		image.ProductID,
+		image.URL,
+		image.StorageKey,
+		image.ContentType,
+		image.Size,
+		image.Position,
+		image.IsPrimary,
+		image.CreatedAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// GetByID retrieves an image by its ID
+func (r *productImageRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.ProductImage, error) {
+	query := `SELECT id, product_id, url, storage_key, content_type, size, position, is_primary, created_at FROM product_images WHERE id = $1`
+
+	var img entities.ProductImage
+	err := retrying(r.db).QueryRow(ctx, query, id).Scan(
+		&img.ID,
+		&img.ProductID,
+		&img.URL,
+		&img.StorageKey,
+		&img.ContentType,
+		&img.Size,
+		&img.Position,
+		&img.IsPrimary,
+		&img.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrProductImageNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return &img, nil
+}
+
+// ListByProductID retrieves the gallery of a product ordered by position
+func (r *productImageRepository) ListByProductID(ctx context.Context, productID uuid.UUID) ([]entities.ProductImage, error) {
+	images, err := r.ListByProductIDs(ctx, []uuid.UUID{productID})
+	if err != nil {
+		return nil, err
+	}
+	return images[productID], nil
+}
+
+// ListByProductIDs retrieves the galleries of several products in one query
+func (r *productImageRepository) ListByProductIDs(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]entities.ProductImage, error) {
+	result := make(map[uuid.UUID][]entities.ProductImage, len(productIDs))
+	if len(productIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT id, product_id, url, storage_key, content_type, size, position, is_primary, created_at
+		FROM product_images
+		WHERE product_id = ANY($1)
+		ORDER BY product_id, position
+	`
+	rows, err := retrying(r.db).Query(ctx, query, productIDs)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var img entities.ProductImage
+		if err := rows.Scan(
+			&img.ID,
+			&img.ProductID,
+			&img.URL,
+			&img.StorageKey,
+			&img.ContentType,
+			&img.Size,
+			&img.Position,
+			&img.IsPrimary,
+			&img.CreatedAt,
+		); err != nil {
+			return nil, wrapError(err)
+		}
+		result[img.ProductID] = append(result[img.ProductID], img)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return result, nil
+}
+
+// SetPrimary marks an image as the primary image of its product
+func (r *productImageRepository) SetPrimary(ctx context.Context, productID, imageID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `UPDATE product_images SET is_primary = FALSE WHERE product_id = $1 AND is_primary`, productID); err != nil {
+		return wrapError(err)
+	}
+
+	res, err := tx.Exec(ctx, `UPDATE product_images SET is_primary = TRUE WHERE id = $1 AND product_id = $2`, imageID, productID)
+	if err != nil {
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrProductImageNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// Reorder sets image positions following the order of imageIDs
+func (r *productImageRepository) Reorder(ctx context.Context, productID uuid.UUID, imageIDs []uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	for position, id := range imageIDs {
+		res, err := tx.Exec(ctx, `UPDATE product_images SET position = $1 WHERE id = $2 AND product_id = $3`, position, id, productID)
+		if err != nil {
+			return wrapError(err)
+		}
+		if res.RowsAffected() == 0 {
+			return apperror.ErrProductImageNotFound
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// Delete removes an image; if it was the primary image the next image becomes primary
+func (r *productImageRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var productID uuid.UUID
+	var wasPrimary bool
+	err = tx.QueryRow(ctx, `DELETE FROM product_images WHERE id = $1 RETURNING product_id, is_primary`, id).Scan(&productID, &wasPrimary)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.ErrProductImageNotFound
+		}
+		return wrapError(err)
+	}
+
+	if wasPrimary {
+		_, err := tx.Exec(ctx, `
+			UPDATE product_images SET is_primary = TRUE
+			WHERE id = (SELECT id FROM product_images WHERE product_id = $1 ORDER BY position LIMIT 1)
+		`, productID)
+		if err != nil {
+			return wrapError(err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
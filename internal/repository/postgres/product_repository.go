@@ -2,26 +2,44 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"postgresDB/internal/domain/entities"
 	apperror "postgresDB/internal/domain/errors"
 	"postgresDB/internal/domain/repository"
+	"postgresDB/pkg/pagination"
+	"postgresDB/pkg/query"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// maxReferencedOrderIDs is the most open order IDs listed by References
+const maxReferencedOrderIDs = 100
+
+// exactCountThreshold is the planner estimate below which EstimateCount counts exactly
+const exactCountThreshold = 10000
+
+// productColumns is the column list scanned by scanProducts
+const productColumns = `id, COALESCE(sku, ''), name, description, price, stock, category, category_id, created_at, updated_at, deleted_at, reorder_threshold, reserved_stock, weight_grams, version`
+
 type productRepository struct {
 	// db connection or other dependencies can be added here
-	db *pgxpool.Pool
+	db       *pgxpool.Pool
+	replicas *Replicas
 }
 
-// NewProductRepository untuk membuat instance baru dari ProductRepository
-func NewProductRepository(db *pgxpool.Pool) repository.ProductRepository {
+// NewProductRepository untuk membuat instance baru dari ProductRepository, reads yang
+// boleh stale dibaca dari replica
+func NewProductRepository(db *pgxpool.Pool, replicas *Replicas) repository.ProductRepository {
 	return &productRepository{
-		db: db,
+		db:       db,
+		replicas: replicas,
 	}
 }
 
@@ -29,40 +47,60 @@ func NewProductRepository(db *pgxpool.Pool) repository.ProductRepository {
 func (r *productRepository) Create(ctx context.Context, product *entities.Product) error {
 	// implementasi pembuatan produk di database
 	query := `
-		INSERT INTO products (id, name, description, price, stock, category, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		INSERT INTO products (id, sku, name, description, price, stock, category, category_id, reorder_threshold, weight_grams, created_at, updated_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
 	`
-	_, err := r.db.Exec(ctx, query, product.ID, product.Name, product.Description, product.Price, product.Stock, product.Category)
+	_, err := conn(ctx, r.db).Exec(ctx, query, product.ID, product.SKU, product.Name, product.Description, product.Price, product.Stock, product.Category, product.CategoryID, product.ReorderThreshold, product.WeightGrams)
 	if err != nil {
-		return apperror.WrapInternal(err)
+		if isUniqueViolation(err) {
+			return apperror.ErrSKUExists
+		}
+		return wrapError(err)
 	}
+	product.Version = 1
 	return nil
 }
 
 // GetByID mengambil produk berdasarkan ID
 func (r *productRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
+	return r.getByID(ctx, readConn(ctx, r.db, r.replicas), id)
+}
+
+// GetCurrent mengambil produk berdasarkan ID dari primary, tidak pernah dari replika
+func (r *productRepository) GetCurrent(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
+	return r.getByID(ctx, conn(ctx, r.db), id)
+}
+
+func (r *productRepository) getByID(ctx context.Context, db dbConn, id uuid.UUID) (*entities.Product, error) {
 	// implementasi pengambilan produk dari database berdasarkan ID
-	query := `SELECT id, name, description, price, stock, category, created_at, updated_at FROM products WHERE id = $1`
+	query := `SELECT ` + productColumns + ` FROM products WHERE id = $1 AND deleted_at IS NULL`
 
 	// Scan the result into a Product entity
 	var product entities.Product
 	var description, category *string
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	err := db.QueryRow(ctx, query, id).Scan(
 		&product.ID,
+		&product.SKU,
 		&product.Name,
 		&description,
 		&product.Price,
 		&product.Stock,
 		&category,
+		&product.CategoryID,
 		&product.CreatedAt,
 		&product.UpdatedAt,
+		&product.DeletedAt,
+		&product.ReorderThreshold,
+		&product.ReservedStock,
+		&product.WeightGrams,
+		&product.Version,
 	)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperror.ErrProductNotFound
 		}
-		return nil, apperror.WrapInternal(err)
+		return nil, wrapError(err)
 	}
 
 	// Assign nullable fields
@@ -76,77 +114,688 @@ func (r *productRepository) GetByID(ctx context.Context, id uuid.UUID) (*entitie
 	return &product, nil
 }
 
-// List mengambil daftar produk dengan pagination dan filter
-func (r *productRepository) List(ctx context.Context, limit, offset int, search, category string) ([]*entities.Product, int64, error) {
-	//build count query
-	countQuery := `SELECT COUNT(*) FROM products WHERE 1=1`
-	args := make([]interface{}, 0)
-	argIndex := 1
+// GetByIDs mengambil produk aktif berdasarkan beberapa ID dalam satu query; ID yang
+// tidak ditemukan atau sudah diarsipkan dilewati
+func (r *productRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products WHERE id = ANY($1) AND deleted_at IS NULL`
 
-	if search != "" {
-		countQuery += fmt.Sprintf(" AND (name ILIKE $%d OR description ILIKE $%d)", argIndex, argIndex)
-		args = append(args, "%"+search+"%")
-		argIndex++
+	rows, err := readConn(ctx, r.db, r.replicas).Query(ctx, query, ids)
+	if err != nil {
+		return nil, wrapError(err)
 	}
-	if category != "" {
-		countQuery += fmt.Sprintf(" AND category = $%d", argIndex)
-		args = append(args, category)
-		argIndex++
+	defer rows.Close()
+
+	return scanProducts(rows, len(ids))
+}
+
+// GetByIDsForUpdate mengambil produk aktif berdasarkan beberapa ID sekaligus mengunci
+// barisnya sampai transaksi selesai
+func (r *productRepository) GetByIDsForUpdate(ctx context.Context, ids []uuid.UUID) ([]*entities.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products WHERE id = ANY($1) AND deleted_at IS NULL ORDER BY id FOR UPDATE`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, ids)
+	if err != nil {
+		return nil, wrapError(err)
 	}
+	defer rows.Close()
+
+	return scanProducts(rows, len(ids))
+}
+
+// List mengambil daftar produk dengan pagination dan filter. Total dihitung dalam query
+// yang sama dengan COUNT(*) OVER(), kecuali untuk halaman di luar jangkauan yang tidak
+// punya baris untuk membawanya
+func (r *productRepository) List(ctx context.Context, limit, offset int, filter repository.ProductFilter) ([]*entities.Product, int64, error) {
+	where, args := buildProductFilter(filter)
+	argIndex := len(args) + 1
+
+	query := `
+		SELECT ` + productColumns + `, COUNT(*) OVER()
+		FROM products
+	` + where
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d OFFSET $%d", productOrder(filter.Sort), argIndex, argIndex+1)
+	args = append(args, limit, offset)
+
+	rows, err := readConn(ctx, r.db, r.replicas).Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+	defer rows.Close()
 
-	// End count query
 	var total int64
-	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
-		return nil, 0, apperror.WrapInternal(err)
+	products, err := scanProducts(rows, limit, &total)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(products) == 0 && offset > 0 {
+		if total, err = r.count(ctx, filter); err != nil {
+			return nil, 0, err
+		}
 	}
+	return products, total, nil
+}
+
+// ListPage mengambil satu halaman produk tanpa menghitung total
+func (r *productRepository) ListPage(ctx context.Context, limit, offset int, filter repository.ProductFilter) ([]*entities.Product, error) {
+	where, args := buildProductFilter(filter)
 
 	// Build main query
 	query := `
-		SELECT id, name, description, price, stock, category, created_at, updated_at
+		SELECT ` + productColumns + `
+		FROM products
+	` + where
+	argIndex := len(args) + 1
+
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d OFFSET $%d", productOrder(filter.Sort), argIndex, argIndex+1)
+	args = append(args, limit, offset)
+
+	rows, err := readConn(ctx, r.db, r.replicas).Query(ctx, query, args...)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	return scanProducts(rows, limit)
+}
+
+// EstimateCount memperkirakan jumlah produk yang cocok dengan filter dari rencana query
+// planner, yang dihitung dari pg_class.reltuples dan statistik kolom. Perkiraan di bawah
+// exactCountThreshold dihitung ulang dengan COUNT(*), karena murah dan perkiraan kecil
+// sering meleset jauh
+func (r *productRepository) EstimateCount(ctx context.Context, filter repository.ProductFilter) (int64, bool, error) {
+	where, args := buildProductFilter(filter)
+
+	var plan string
+	if err := readConn(ctx, r.db, r.replicas).QueryRow(ctx, `EXPLAIN (FORMAT JSON) SELECT 1 FROM products`+where, args...).Scan(&plan); err != nil {
+		return 0, false, wrapError(err)
+	}
+	var explained []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(plan), &explained); err != nil {
+		return 0, false, wrapError(fmt.Errorf("parse query plan: %w", err))
+	}
+
+	var estimate int64
+	if len(explained) > 0 {
+		estimate = int64(explained[0].Plan.Rows)
+	}
+	if estimate < exactCountThreshold {
+		total, err := r.count(ctx, filter)
+		return total, err == nil, err
+	}
+	return estimate, false, nil
+}
+
+// count counts the products matching the filter
+func (r *productRepository) count(ctx context.Context, filter repository.ProductFilter) (int64, error) {
+	where, args := buildProductFilter(filter)
+
+	var total int64
+	if err := readConn(ctx, r.db, r.replicas).QueryRow(ctx, `SELECT COUNT(*) FROM products`+where, args...).Scan(&total); err != nil {
+		return 0, wrapError(err)
+	}
+	return total, nil
+}
+
+// ListAfter mengambil halaman produk setelah cursor (keyset pagination), tanpa hitung total
+func (r *productRepository) ListAfter(ctx context.Context, limit int, after *pagination.Cursor, filter repository.ProductFilter) ([]*entities.Product, error) {
+	where, args := buildProductFilter(filter)
+	argIndex := len(args) + 1
+
+	if after != nil {
+		where += fmt.Sprintf(" AND (created_at, id) < ($%d, $%d)", argIndex, argIndex+1)
+		args = append(args, after.CreatedAt, after.ID)
+		argIndex += 2
+	}
+
+	query := `
+		SELECT ` + productColumns + `
 		FROM products
-		WHERE 1=1
+	` + where + fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", argIndex)
+	args = append(args, limit)
+
+	rows, err := readConn(ctx, r.db, r.replicas).Query(ctx, query, args...)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	return scanProducts(rows, limit)
+}
+
+// FindAll mengambil semua produk yang cocok dengan filter tanpa pagination
+func (r *productRepository) FindAll(ctx context.Context, filter repository.ProductFilter) ([]*entities.Product, error) {
+	where, args := buildProductFilter(filter)
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+	` + where + ` ORDER BY created_at DESC`
+
+	rows, err := readConn(ctx, r.db, r.replicas).Query(ctx, query, args...)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	return scanProducts(rows, 0)
+}
+
+// UpdatePrices menerapkan perubahan harga dalam satu transaksi; perubahan dibatalkan
+// bila harga salah satu produk sudah berubah sejak dihitung
+func (r *productRepository) UpdatePrices(ctx context.Context, changes []entities.PriceChange) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, c := range changes {
+		res, err := tx.Exec(ctx,
+			`UPDATE products SET price = $1, version = version + 1, updated_at = NOW() WHERE id = $2 AND price = $3 AND deleted_at IS NULL`,
+			c.NewPrice, c.ProductID, c.OldPrice,
+		)
+		if err != nil {
+			return wrapError(err)
+		}
+		if res.RowsAffected() == 0 {
+			return apperror.ErrPriceChanged
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// RestoreFromSnapshot mengembalikan harga dan/atau stok produk ke nilai snapshot dalam satu
+// transaksi; produk yang sudah dihapus dilewati. Mengembalikan jumlah produk yang dipulihkan
+func (r *productRepository) RestoreFromSnapshot(ctx context.Context, items []entities.SnapshotItem, restorePrice, restoreStock bool) (int, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	restored := 0
+	for _, item := range items {
+		query := `UPDATE products SET
+			price = CASE WHEN $2::boolean THEN $4::bigint ELSE price END,
+			stock = CASE WHEN $3::boolean THEN $5::integer ELSE stock END,
+			version = version + 1,
+			updated_at = NOW()
+			WHERE id = $1`
+		res, err := tx.Exec(ctx, query, item.ProductID, restorePrice, restoreStock, item.Price, item.Stock)
+		if err != nil {
+			return 0, wrapError(err)
+		}
+		restored += int(res.RowsAffected())
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, wrapError(err)
+	}
+	return restored, nil
+}
+
+// UpsertBySKU menyimpan produk berdasarkan SKU dalam satu transaksi, dikirim per batch
+// agar ribuan baris tidak butuh ribuan round trip. Produk dengan SKU yang sudah ada
+// diperbarui (termasuk yang diarsipkan) dan ID-nya diganti dengan ID yang tersimpan
+func (r *productRepository) UpsertBySKU(ctx context.Context, products []*entities.Product, batchSize int) ([]bool, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO products (id, sku, name, description, price, stock, category, category_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		ON CONFLICT (sku) DO UPDATE SET
+			name = EXCLUDED.name,
+			description = EXCLUDED.description,
+			price = EXCLUDED.price,
+			stock = EXCLUDED.stock,
+			category = EXCLUDED.category,
+			category_id = EXCLUDED.category_id,
+			version = products.version + 1,
+			updated_at = NOW()
+		RETURNING id, (xmax = 0) AS inserted
 	`
 
-	args = make([]interface{}, 0)
-	argIndex = 1
+	created := make([]bool, len(products))
+	for start := 0; start < len(products); start += batchSize {
+		end := min(start+batchSize, len(products))
 
-	if category != "" {
-		query += fmt.Sprintf(" AND category = $%d", argIndex)
-		args = append(args, category)
-		argIndex++
+		batch := &pgx.Batch{}
+		for _, p := range products[start:end] {
+			batch.Queue(query, p.ID, p.SKU, p.Name, p.Description, p.Price, p.Stock, p.Category, p.CategoryID)
+		}
+
+		results := tx.SendBatch(ctx, batch)
+		for i := start; i < end; i++ {
+			if err := results.QueryRow().Scan(&products[i].ID, &created[i]); err != nil {
+				results.Close()
+				return nil, wrapError(err)
+			}
+		}
+		if err := results.Close(); err != nil {
+			return nil, wrapError(err)
+		}
 	}
 
-	if search != "" {
-		query += fmt.Sprintf(" AND (name ILIKE $%d OR description ILIKE $%d)", argIndex, argIndex)
-		args = append(args, "%"+search+"%")
-		argIndex++
+	if err := tx.Commit(ctx); err != nil {
+		return nil, wrapError(err)
 	}
+	return created, nil
+}
 
-	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
-	args = append(args, limit, offset)
+// Update mengupdate data produk selama versinya masih sama dengan product.Version, lalu
+// menyimpan versi barunya ke product.Version
+func (r *productRepository) Update(ctx context.Context, product *entities.Product) error {
+	// implementasi update produk di database
+	query := `UPDATE products SET sku = NULLIF($1, ''), name = $2, description = $3, price = $4, stock = $5, category = $6, category_id = $7, reorder_threshold = $8, weight_grams = $9, version = version + 1, updated_at = NOW() WHERE id = $10 AND deleted_at IS NULL AND version = $11 RETURNING version`
+
+	// Execute the query
+	err := conn(ctx, r.db).QueryRow(ctx, query, product.SKU, product.Name, product.Description, product.Price, product.Stock, product.Category, product.CategoryID, product.ReorderThreshold, product.WeightGrams, product.ID, product.Version).Scan(&product.Version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return r.staleOrMissing(ctx, product.ID)
+		}
+		if isUniqueViolation(err) {
+			return apperror.ErrSKUExists
+		}
+		return wrapError(err)
+	}
+	return nil
+}
+
+// staleOrMissing tells apart an update refused for its version from one whose product is gone
+func (r *productRepository) staleOrMissing(ctx context.Context, id uuid.UUID) error {
+	var exists bool
+	if err := conn(ctx, r.db).QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM products WHERE id = $1 AND deleted_at IS NULL)`, id).Scan(&exists); err != nil {
+		return wrapError(err)
+	}
+	if exists {
+		return apperror.ErrVersionConflict
+	}
+	return apperror.ErrProductNotFound
+}
+
+// Delete mengarsipkan produk berdasarkan ID; baris tetap disimpan agar riwayat order utuh
+func (r *productRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE products SET deleted_at = NOW(), version = version + 1, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrProductNotFound
+	}
+	return nil
+}
+
+// Restore mengembalikan produk yang diarsipkan
+func (r *productRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE products SET deleted_at = NULL, version = version + 1, updated_at = NOW() WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrProductNotFound
+	}
+	return nil
+}
+
+// PurgeArchived menghapus permanen produk yang diarsipkan sebelum cutoff dan tidak dirujuk
+// order mana pun; gambar, atribut, tag dan stok gudangnya ikut terhapus
+func (r *productRepository) PurgeArchived(ctx context.Context, before time.Time, limit int) (int, []string, error) {
+	query := `
+		WITH purged AS (
+			DELETE FROM products WHERE id IN (
+				SELECT p.id FROM products p
+				WHERE p.deleted_at < $1
+				  AND NOT EXISTS (SELECT 1 FROM order_items i WHERE i.product_id = p.id)
+				ORDER BY p.deleted_at
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id
+		)
+		SELECT
+			(SELECT COUNT(*) FROM purged),
+			ARRAY(SELECT storage_key FROM product_images WHERE product_id IN (SELECT id FROM purged))
+	`
+	var (
+		count int
+		keys  []string
+	)
+	if err := conn(ctx, r.db).QueryRow(ctx, query, before, limit).Scan(&count, &keys); err != nil {
+		return 0, nil, wrapError(err)
+	}
+	return count, keys, nil
+}
+
+// UpdateStock menambahkan delta ke stok produk secara atomik dan mengembalikan stok sebelum dan sesudahnya
+func (r *productRepository) UpdateStock(ctx context.Context, id uuid.UUID, delta int) (*entities.StockLevel, error) {
+	// implementasi pembaruan stok produk di database; stok lama dibaca dari baris yang dikunci
+	query := `
+		WITH prev AS (SELECT id, stock FROM products WHERE id = $2 FOR UPDATE)
+		UPDATE products p SET stock = p.stock + $1, version = p.version + 1, updated_at = NOW()
+		FROM prev
+		WHERE p.id = prev.id AND p.stock + $1 >= 0
+		RETURNING prev.stock, p.stock, p.reorder_threshold
+	`
+
+	// a deadlock with another stock update rolls the statement back, outside a transaction
+	// conn runs it again
+	level := entities.StockLevel{ProductID: id}
+	err := conn(ctx, r.db).QueryRow(ctx, query, delta, id).Scan(&level.Previous, &level.Current, &level.ReorderThreshold)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrInsufficientStock
+		}
+		return nil, wrapError(err)
+	}
+	return &level, nil
+}
+
+// DecrementStock mengurangi stok produk sebesar qty hanya bila stok yang tidak ditahan
+// reservasi mencukupi dan mengembalikan sisa stoknya
+func (r *productRepository) DecrementStock(ctx context.Context, id uuid.UUID, qty int) (int, error) {
+	query := `
+		UPDATE products SET stock = stock - $2, version = version + 1, updated_at = NOW()
+		WHERE id = $1 AND stock - reserved_stock >= $2
+		RETURNING stock
+	`
+
+	var stock int
+	if err := conn(ctx, r.db).QueryRow(ctx, query, id, qty).Scan(&stock); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, apperror.ErrInsufficientStock
+		}
+		return 0, wrapError(err)
+	}
+	return stock, nil
+}
+
+// ListLowStock mengambil produk aktif yang stoknya berada pada atau di bawah ambang reorder,
+// yang paling kritis lebih dulu
+func (r *productRepository) ListLowStock(ctx context.Context, limit, offset int) ([]*entities.Product, int64, error) {
+	where := ` WHERE reorder_threshold IS NOT NULL AND stock <= reorder_threshold AND deleted_at IS NULL`
+
+	var total int64
+	if err := readConn(ctx, r.db, r.replicas).QueryRow(ctx, `SELECT COUNT(*) FROM products`+where).Scan(&total); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	query := `SELECT ` + productColumns + ` FROM products` + where + ` ORDER BY stock - reorder_threshold, stock, name LIMIT $1 OFFSET $2`
+	rows, err := readConn(ctx, r.db, r.replicas).Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+	defer rows.Close()
+
+	products, err := scanProducts(rows, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return products, total, nil
+}
+
+// InventorySummary menghitung jumlah, stok dan nilai inventaris seluruh produk aktif
+func (r *productRepository) InventorySummary(ctx context.Context) (*entities.InventorySummary, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE deleted_at IS NULL),
+			COALESCE(SUM(stock) FILTER (WHERE deleted_at IS NULL), 0),
+			COALESCE(SUM(reserved_stock) FILTER (WHERE deleted_at IS NULL), 0),
+			COALESCE(SUM(price * stock) FILTER (WHERE deleted_at IS NULL), 0)::bigint,
+			COUNT(*) FILTER (WHERE deleted_at IS NULL AND stock = 0),
+			COUNT(*) FILTER (WHERE deleted_at IS NULL AND reorder_threshold IS NOT NULL AND stock <= reorder_threshold),
+			COUNT(*) FILTER (WHERE deleted_at IS NOT NULL)
+		FROM products
+	`
+	var s entities.InventorySummary
+	err := readConn(ctx, r.db, r.replicas).QueryRow(ctx, query).Scan(&s.Products, &s.Stock, &s.ReservedStock, &s.InventoryValue, &s.OutOfStock, &s.LowStock, &s.Archived)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &s, nil
+}
+
+// CategoryStats menghitung produk aktif per kategori; produk tanpa category_id dikelompokkan
+// berdasarkan teks kategorinya
+func (r *productRepository) CategoryStats(ctx context.Context) ([]entities.CategoryStats, error) {
+	query := `
+		SELECT p.category_id, COALESCE(c.name, p.category), COUNT(*), COALESCE(SUM(p.stock), 0),
+			COALESCE(SUM(p.price * p.stock), 0)::bigint, COUNT(*) FILTER (WHERE p.stock = 0)
+		FROM products p
+		LEFT JOIN categories c ON c.id = p.category_id
+		WHERE p.deleted_at IS NULL
+		GROUP BY p.category_id, COALESCE(c.name, p.category)
+		ORDER BY COUNT(*) DESC, 2
+	`
+	rows, err := readConn(ctx, r.db, r.replicas).Query(ctx, query)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	stats := make([]entities.CategoryStats, 0)
+	for rows.Next() {
+		var s entities.CategoryStats
+		if err := rows.Scan(&s.CategoryID, &s.Category, &s.Products, &s.Stock, &s.InventoryValue, &s.OutOfStock); err != nil {
+			return nil, wrapError(err)
+		}
+		stats = append(stats, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return stats, nil
+}
+
+// TopSellers mengambil produk terlaris berdasarkan jumlah terjual pada order yang sudah
+// dibayar dalam rentang [from, to); produk yang diarsipkan tetap dihitung
+func (r *productRepository) TopSellers(ctx context.Context, from, to time.Time, limit int) ([]entities.ProductSales, error) {
+	query := `
+		SELECT oi.product_id, COALESCE(p.name, ''), SUM(oi.quantity), COUNT(DISTINCT oi.order_id), SUM(oi.subtotal)::bigint
+		FROM order_items oi
+		JOIN orders o ON o.id = oi.order_id
+		LEFT JOIN products p ON p.id = oi.product_id
+		WHERE o.status = ANY($1) AND o.created_at >= $2 AND o.created_at < $3
+		GROUP BY oi.product_id, p.name
+		ORDER BY SUM(oi.quantity) DESC, SUM(oi.subtotal) DESC
+		LIMIT $4
+	`
+	sold := orderStatusNames(entities.SoldOrderStatuses)
+	rows, err := readConn(ctx, r.db, r.replicas).Query(ctx, query, sold, from, to, limit)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
 
-	rows, err := r.db.Query(ctx, query, args...)
+	sales := make([]entities.ProductSales, 0, limit)
+	for rows.Next() {
+		var s entities.ProductSales
+		if err := rows.Scan(&s.ProductID, &s.Name, &s.Quantity, &s.Orders, &s.Revenue); err != nil {
+			return nil, wrapError(err)
+		}
+		sales = append(sales, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return sales, nil
+}
+
+// WeeklySales mengambil jumlah terjual per minggu untuk beberapa produk sejak from
+func (r *productRepository) WeeklySales(ctx context.Context, ids []uuid.UUID, from time.Time, weeks int) (map[uuid.UUID][]int64, error) {
+	query := `
+		SELECT oi.product_id, FLOOR(EXTRACT(EPOCH FROM o.created_at - $3) / 604800)::int AS week, SUM(oi.quantity)
+		FROM order_items oi
+		JOIN orders o ON o.id = oi.order_id
+		WHERE oi.product_id = ANY($1) AND o.status = ANY($2)
+		  AND o.created_at >= $3 AND o.created_at < $3 + make_interval(weeks => $4)
+		GROUP BY oi.product_id, week
+	`
+	sold := orderStatusNames(entities.SoldOrderStatuses)
+	rows, err := readConn(ctx, r.db, r.replicas).Query(ctx, query, ids, sold, from, weeks)
 	if err != nil {
-		return nil, 0, apperror.WrapInternal(err)
+		return nil, wrapError(err)
 	}
 	defer rows.Close()
 
-	products := make([]*entities.Product, 0, limit)
+	sales := make(map[uuid.UUID][]int64, len(ids))
+	for _, id := range ids {
+		sales[id] = make([]int64, weeks)
+	}
+	for rows.Next() {
+		var id uuid.UUID
+		var week int
+		var quantity int64
+		if err := rows.Scan(&id, &week, &quantity); err != nil {
+			return nil, wrapError(err)
+		}
+		if week >= 0 && week < weeks {
+			sales[id][week] = quantity
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return sales, nil
+}
+
+// ListRelated mengambil produk aktif lain dari kategori yang sama, yang paling sering dibeli
+// bersama produk id lebih dulu, sisanya produk terbaru
+func (r *productRepository) ListRelated(ctx context.Context, id uuid.UUID, limit int) ([]*entities.Product, error) {
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE id <> $1 AND deleted_at IS NULL
+		  AND category_id = (SELECT category_id FROM products WHERE id = $1)
+		ORDER BY COALESCE((
+			SELECT order_count FROM product_co_purchases
+			WHERE product_id = $1 AND related_id = products.id
+		), 0) DESC, created_at DESC
+		LIMIT $2
+	`
+	rows, err := readConn(ctx, r.db, r.replicas).Query(ctx, query, id, limit)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	return scanProducts(rows, limit)
+}
+
+// RefreshRelated menghitung ulang frekuensi pembelian bersama dari order_items
+// tanpa memblokir pembacaan
+func (r *productRepository) RefreshRelated(ctx context.Context) error {
+	// REFRESH ... CONCURRENTLY tidak bisa berjalan di dalam transaksi, selalu memakai pool
+	if _, err := retrying(r.db).Exec(ctx, `REFRESH MATERIALIZED VIEW CONCURRENTLY product_co_purchases`); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// References menghitung order, reservasi dan detail yang masih merujuk ke produk
+func (r *productRepository) References(ctx context.Context, id uuid.UUID) (*entities.ProductReferences, error) {
+	refs := &entities.ProductReferences{OpenOrders: make(map[entities.OrderStatus]int)}
+
+	rows, err := readConn(ctx, r.db, r.replicas).Query(ctx, `
+		SELECT o.status, COUNT(DISTINCT o.id)
+		FROM order_items i
+		JOIN orders o ON o.id = i.order_id
+		WHERE i.product_id = $1
+		GROUP BY o.status
+	`, id)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	for rows.Next() {
+		var status entities.OrderStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			rows.Close()
+			return nil, wrapError(err)
+		}
+		if status.IsClosed() {
+			refs.ClosedOrders += count
+		} else {
+			refs.OpenOrders[status] = count
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	rows, err = readConn(ctx, r.db, r.replicas).Query(ctx, `
+		SELECT id FROM orders
+		WHERE status = ANY($2)
+		  AND id IN (SELECT order_id FROM order_items WHERE product_id = $1)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`, id, orderStatusNames(entities.OpenOrderStatuses), maxReferencedOrderIDs)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	refs.OpenOrderIDs, err = pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	err = readConn(ctx, r.db, r.replicas).QueryRow(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM stock_reservations WHERE product_id = $1 AND status = $2),
+			(SELECT COALESCE(SUM(quantity), 0) FROM stock_reservations WHERE product_id = $1 AND status = $2),
+			(SELECT COUNT(*) FROM product_images WHERE product_id = $1),
+			(SELECT COUNT(*) FROM product_attribute_values WHERE product_id = $1),
+			(SELECT COUNT(*) FROM product_tags WHERE product_id = $1)
+	`, id, entities.ReservationActive).Scan(&refs.ActiveReservations, &refs.ReservedQuantity, &refs.Images, &refs.Attributes, &refs.Tags)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return refs, nil
+}
+
+// scanProducts scans product rows selected with the standard column list. Columns selected
+// after it are scanned into extra, which holds the values of the last row
+func scanProducts(rows pgx.Rows, capacity int, extra ...any) ([]*entities.Product, error) {
+	products := make([]*entities.Product, 0, capacity)
 	for rows.Next() {
 		var product entities.Product
 		var description, categoryVal *string
 
-		if err := rows.Scan(
+		dest := []any{
 			&product.ID,
+			&product.SKU,
 			&product.Name,
 			&description,
 			&product.Price,
 			&product.Stock,
 			&categoryVal,
+			&product.CategoryID,
 			&product.CreatedAt,
 			&product.UpdatedAt,
-		); err != nil {
-			return nil, 0, apperror.WrapInternal(err)
+			&product.DeletedAt,
+			&product.ReorderThreshold,
+			&product.ReservedStock,
+			&product.WeightGrams,
+			&product.Version,
+		}
+		if err := rows.Scan(append(dest, extra...)...); err != nil {
+			return nil, wrapError(err)
 		}
 
 		if description != nil {
@@ -158,52 +807,120 @@ func (r *productRepository) List(ctx context.Context, limit, offset int, search,
 
 		products = append(products, &product)
 	}
-
-	return products, total, nil
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return products, nil
 }
 
-// Update mengupdate data produk
-func (r *productRepository) Update(ctx context.Context, product *entities.Product) error {
-	// implementasi update produk di database
-	query := `UPDATE products SET name = $1, description = $2, price = $3, stock = $4, category = $5, updated_at = NOW() WHERE id = $6`
+// productSortColumns maps the sortable fields of a product listing to their columns
+var productSortColumns = map[string]string{
+	"name":       "name",
+	"price":      "price",
+	"stock":      "stock",
+	"created_at": "created_at",
+}
 
-	// Execute the query
-	res, err := r.db.Exec(ctx, query, product.Name, product.Description, product.Price, product.Stock, product.Category, product.ID)
-	if err != nil {
-		return apperror.WrapInternal(err)
+// productOrder builds the ORDER BY list of a product listing, the ID breaks ties so pages
+// don't overlap
+func productOrder(fields query.Sort) string {
+	if len(fields) == 0 {
+		return "created_at DESC, id"
 	}
-	if res.RowsAffected() == 0 {
-		return apperror.ErrProductNotFound
+	terms := make([]string, 0, len(fields)+1)
+	for _, f := range fields {
+		column, ok := productSortColumns[f.Field]
+		if !ok {
+			continue
+		}
+		if f.Desc {
+			column += " DESC"
+		}
+		terms = append(terms, column)
 	}
-	return nil
+	return strings.Join(append(terms, "id"), ", ")
 }
 
-// Delete menghapus produk berdasarkan ID
-func (r *productRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	// implementasi penghapusan produk di database
-	query := `DELETE FROM products WHERE id = $1`
+// buildProductFilter builds the WHERE clause shared by the count, estimate and list queries
+func buildProductFilter(filter repository.ProductFilter) (string, []interface{}) {
+	where := ` WHERE 1=1`
+	args := make([]interface{}, 0)
+	argIndex := 1
 
-	res, err := r.db.Exec(ctx, query, id)
-	if err != nil {
-		return apperror.WrapInternal(err)
+	if cond := deletedCondition(filter.Archived); cond != "" {
+		where += " AND " + cond
 	}
-	if res.RowsAffected() == 0 {
-		return apperror.ErrProductNotFound
-	}
-	return nil
-}
 
-// UpdateStock memperbarui stok produk
-func (r *productRepository) UpdateStock(ctx context.Context, id uuid.UUID, newStock int) error {
-	// implementasi pembaruan stok produk di database
-	query := `UPDATE products SET stock = stock + $1, updated_at = NOW() WHERE id = $2 AND stock + $1 >= 0`
+	if filter.Search != "" {
+		where += fmt.Sprintf(" AND (name ILIKE $%d OR description ILIKE $%d)", argIndex, argIndex)
+		args = append(args, "%"+filter.Search+"%")
+		argIndex++
+	}
+	if len(filter.IDs) > 0 {
+		where += fmt.Sprintf(" AND id = ANY($%d)", argIndex)
+		args = append(args, filter.IDs)
+		argIndex++
+	}
+	if len(filter.CategoryIDs) > 0 {
+		where += fmt.Sprintf(" AND category_id = ANY($%d)", argIndex)
+		args = append(args, filter.CategoryIDs)
+		argIndex++
+	}
+	if len(filter.Categories) == 1 {
+		where += fmt.Sprintf(" AND category = $%d", argIndex)
+		args = append(args, filter.Categories[0])
+		argIndex++
+	} else if len(filter.Categories) > 1 {
+		where += fmt.Sprintf(" AND category = ANY($%d)", argIndex)
+		args = append(args, filter.Categories)
+		argIndex++
+	}
+	if filter.MinPrice != nil {
+		where += fmt.Sprintf(" AND price >= $%d", argIndex)
+		args = append(args, *filter.MinPrice)
+		argIndex++
+	}
+	if filter.MaxPrice != nil {
+		where += fmt.Sprintf(" AND price <= $%d", argIndex)
+		args = append(args, *filter.MaxPrice)
+		argIndex++
+	}
+	if filter.InStock {
+		where += " AND stock > 0"
+	}
+	if len(filter.Tags) > 0 {
+		where += fmt.Sprintf(` AND EXISTS (
+			SELECT 1 FROM product_tags pt
+			JOIN tags t ON t.id = pt.tag_id
+			WHERE pt.product_id = products.id AND t.slug = ANY($%d))`, argIndex)
+		args = append(args, filter.Tags)
+		argIndex++
+	}
 
-	res, err := r.db.Exec(ctx, query, newStock, id)
-	if err != nil {
-		return apperror.WrapInternal(err)
+	// sorted so the same filter always produces the same query text
+	codes := make([]string, 0, len(filter.Attributes))
+	for code := range filter.Attributes {
+		codes = append(codes, code)
 	}
-	if res.RowsAffected() == 0 {
-		return apperror.ErrInsufficientStock
+	sort.Strings(codes)
+	for _, code := range codes {
+		where += fmt.Sprintf(` AND EXISTS (
+			SELECT 1 FROM product_attribute_values pav
+			JOIN attribute_definitions ad ON ad.id = pav.attribute_id
+			WHERE pav.product_id = products.id AND ad.filterable
+			AND ad.code = $%d AND pav.value = ANY($%d))`, argIndex, argIndex+1)
+		args = append(args, code, filter.Attributes[code])
+		argIndex += 2
 	}
-	return nil
+
+	return where, args
+}
+
+// orderStatusNames converts order statuses to query parameters
+func orderStatusNames(statuses []entities.OrderStatus) []string {
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+	return names
 }
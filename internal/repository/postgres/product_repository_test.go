@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+func TestProductRepositoryDecrementStock(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := NewProductRepository(pool, nil)
+
+	product := &entities.Product{ID: uuid.New(), Name: "decrement stock test", Price: 1000, Stock: 5}
+	if err := repo.Create(ctx, product); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DELETE FROM products WHERE id = $1`, product.ID)
+	})
+
+	stock, err := repo.DecrementStock(ctx, product.ID, 2)
+	if err != nil {
+		t.Fatalf("DecrementStock: %v", err)
+	}
+	if stock != 3 {
+		t.Errorf("stock = %d, want 3", stock)
+	}
+
+	if _, err := repo.DecrementStock(ctx, product.ID, 4); !errors.Is(err, apperror.ErrInsufficientStock) {
+		t.Fatalf("DecrementStock past the stock: err = %v, want ErrInsufficientStock", err)
+	}
+	// stock held by reservations isn't available
+	if _, err := pool.Exec(ctx, `UPDATE products SET reserved_stock = 2 WHERE id = $1`, product.ID); err != nil {
+		t.Fatalf("reserving stock: %v", err)
+	}
+	if _, err := repo.DecrementStock(ctx, product.ID, 2); !errors.Is(err, apperror.ErrInsufficientStock) {
+		t.Fatalf("DecrementStock of reserved stock: err = %v, want ErrInsufficientStock", err)
+	}
+	if _, err := pool.Exec(ctx, `UPDATE products SET reserved_stock = 0 WHERE id = $1`, product.ID); err != nil {
+		t.Fatalf("releasing stock: %v", err)
+	}
+	if _, err := repo.DecrementStock(ctx, uuid.New(), 1); !errors.Is(err, apperror.ErrInsufficientStock) {
+		t.Fatalf("DecrementStock of a missing product: err = %v, want ErrInsufficientStock", err)
+	}
+
+	// concurrent decrements never take the stock below zero
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.DecrementStock(ctx, product.ID, 1)
+			if err != nil && !errors.Is(err, apperror.ErrInsufficientStock) {
+				t.Errorf("concurrent DecrementStock: %v", err)
+				return
+			}
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if succeeded != 3 {
+		t.Errorf("%d concurrent decrements succeeded, want 3", succeeded)
+	}
+
+	current, err := repo.GetCurrent(ctx, product.ID)
+	if err != nil {
+		t.Fatalf("GetCurrent: %v", err)
+	}
+	if current.Stock != 0 {
+		t.Errorf("stock after the concurrent decrements = %d, want 0", current.Stock)
+	}
+	if current.Version != 5 {
+		t.Errorf("version = %d, want 5, raised by every decrement", current.Version)
+	}
+}
@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	reconciliationRunColumns   = `id, source, period_start, period_end, reported_count, recorded_count, matched_count, issue_count, reported_total, recorded_total, created_at`
+	reconciliationIssueColumns = `id, run_id, order_id, kind, COALESCE(reference, ''), expected_amount, reported_amount, status, COALESCE(note, ''), resolved_by, resolved_at, created_at`
+)
+
+type reconciliationRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewReconciliationRepository untuk membuat instance baru dari ReconciliationRepository
+func NewReconciliationRepository(db *pgxpool.Pool) repository.ReconciliationRepository {
+	return &reconciliationRepository{
+		db: db,
+	}
+}
+
+// CreateRun menyimpan hasil rekonsiliasi beserta daftar selisihnya dalam satu transaksi
+func (r *reconciliationRepository) CreateRun(ctx context.Context, run *entities.ReconciliationRun, issues []entities.ReconciliationIssue) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO reconciliation_runs (` + reconciliationRunColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	if _, err := tx.Exec(ctx, query,
+		run.ID, run.Source, run.PeriodStart, run.PeriodEnd,
+		run.ReportedCount, run.RecordedCount, run.MatchedCount, run.IssueCount,
+		run.ReportedTotal, run.RecordedTotal, run.CreatedAt,
+	); err != nil {
+		return wrapError(err)
+	}
+
+	if len(issues) > 0 {
+		rows := make([][]any, len(issues))
+		for i, issue := range issues {
+			var reference *string
+			if issue.Reference != "" {
+				reference = &issue.Reference
+			}
+			rows[i] = []any{issue.ID, issue.RunID, issue.OrderID, string(issue.Kind), reference, issue.ExpectedAmount, issue.ReportedAmount, issue.Status, issue.CreatedAt}
+		}
+		if _, err := tx.CopyFrom(ctx,
+			pgx.Identifier{"reconciliation_issues"},
+			[]string{"id", "run_id", "order_id", "kind", "reference", "expected_amount", "reported_amount", "status", "created_at"},
+			pgx.CopyFromRows(rows),
+		); err != nil {
+			return wrapError(err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// ListRuns mengambil daftar rekonsiliasi, yang terbaru lebih dulu
+func (r *reconciliationRepository) ListRuns(ctx context.Context, limit, offset int) ([]*entities.ReconciliationRun, int64, error) {
+	var total int64
+	if err := retrying(r.db).QueryRow(ctx, `SELECT COUNT(*) FROM reconciliation_runs`).Scan(&total); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	query := `SELECT ` + reconciliationRunColumns + ` FROM reconciliation_runs ORDER BY created_at DESC LIMIT $1 OFFSET $2`
+	rows, err := retrying(r.db).Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+	defer rows.Close()
+
+	runs := make([]*entities.ReconciliationRun, 0, limit)
+	for rows.Next() {
+		run, err := scanReconciliationRun(rows)
+		if err != nil {
+			return nil, 0, wrapError(err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, wrapError(err)
+	}
+	return runs, total, nil
+}
+
+// LatestRun mengambil rekonsiliasi terakhir, nil bila belum pernah dijalankan
+func (r *reconciliationRepository) LatestRun(ctx context.Context) (*entities.ReconciliationRun, error) {
+	query := `SELECT ` + reconciliationRunColumns + ` FROM reconciliation_runs ORDER BY created_at DESC LIMIT 1`
+
+	run, err := scanReconciliationRun(retrying(r.db).QueryRow(ctx, query))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, wrapError(err)
+	}
+	return run, nil
+}
+
+// ListIssues mengambil antrian selisih berdasarkan status, yang terlama lebih dulu
+func (r *reconciliationRepository) ListIssues(ctx context.Context, status string, limit, offset int) ([]*entities.ReconciliationIssue, int64, error) {
+	where := ` WHERE ($1 = '' OR status = $1)`
+
+	var total int64
+	if err := retrying(r.db).QueryRow(ctx, `SELECT COUNT(*) FROM reconciliation_issues`+where, status).Scan(&total); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	query := `SELECT ` + reconciliationIssueColumns + ` FROM reconciliation_issues` + where + ` ORDER BY created_at, id LIMIT $2 OFFSET $3`
+	rows, err := retrying(r.db).Query(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+	defer rows.Close()
+
+	issues := make([]*entities.ReconciliationIssue, 0, limit)
+	for rows.Next() {
+		issue, err := scanReconciliationIssue(rows)
+		if err != nil {
+			return nil, 0, wrapError(err)
+		}
+		issues = append(issues, issue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, wrapError(err)
+	}
+	return issues, total, nil
+}
+
+// CountOpenIssues menghitung selisih yang belum ditinjau per jenis
+func (r *reconciliationRepository) CountOpenIssues(ctx context.Context) (map[entities.ReconciliationIssueKind]int, error) {
+	rows, err := retrying(r.db).Query(ctx, `SELECT kind, COUNT(*) FROM reconciliation_issues WHERE status = $1 GROUP BY kind`, entities.IssueStatusOpen)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	counts := make(map[entities.ReconciliationIssueKind]int)
+	for rows.Next() {
+		var kind string
+		var count int
+		if err := rows.Scan(&kind, &count); err != nil {
+			return nil, wrapError(err)
+		}
+		counts[entities.ReconciliationIssueKind(kind)] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return counts, nil
+}
+
+// ResolveIssue menandai selisih yang masih terbuka sebagai sudah ditinjau
+func (r *reconciliationRepository) ResolveIssue(ctx context.Context, id, resolvedBy uuid.UUID, note string) (*entities.ReconciliationIssue, error) {
+	query := `
+		UPDATE reconciliation_issues
+		SET status = $1, note = NULLIF($2, ''), resolved_by = $3, resolved_at = NOW()
+		WHERE id = $4 AND status = $5
+		RETURNING ` + reconciliationIssueColumns
+
+	issue, err := scanReconciliationIssue(retrying(r.db).QueryRow(ctx, query, entities.IssueStatusResolved, note, resolvedBy, id, entities.IssueStatusOpen))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrReconciliationIssueNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return issue, nil
+}
+
+// scanReconciliationRun scans a row selected with reconciliationRunColumns
+func scanReconciliationRun(row pgx.Row) (*entities.ReconciliationRun, error) {
+	var run entities.ReconciliationRun
+	if err := row.Scan(
+		&run.ID,
+		&run.Source,
+		&run.PeriodStart,
+		&run.PeriodEnd,
+		&run.ReportedCount,
+		&run.RecordedCount,
+		&run.MatchedCount,
+		&run.IssueCount,
+		&run.ReportedTotal,
+		&run.RecordedTotal,
+		&run.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// scanReconciliationIssue scans a row selected with reconciliationIssueColumns
+func scanReconciliationIssue(row pgx.Row) (*entities.ReconciliationIssue, error) {
+	var issue entities.ReconciliationIssue
+	var kind string
+	if err := row.Scan(
+		&issue.ID,
+		&issue.RunID,
+		&issue.OrderID,
+		&kind,
+		&issue.Reference,
+		&issue.ExpectedAmount,
+		&issue.ReportedAmount,
+		&issue.Status,
+		&issue.Note,
+		&issue.ResolvedBy,
+		&issue.ResolvedAt,
+		&issue.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	issue.Kind = entities.ReconciliationIssueKind(kind)
+	return &issue, nil
+}
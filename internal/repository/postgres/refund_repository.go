@@ -0,0 +1,274 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type refundRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewRefundRepository untuk membuat instance baru dari RefundRepository
+func NewRefundRepository(db *pgxpool.Pool) repository.RefundRepository {
+	return &refundRepository{
+		db: db,
+	}
+}
+
+// Create mencatat refund pending setelah memeriksa sisa jumlah dan item order yang belum direfund
+func (r *refundRepository) Create(ctx context.Context, refund *entities.Refund) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// the order row serializes refunds of the same order
+	var status entities.OrderStatus
+	var total int64
+	err = tx.QueryRow(ctx, `SELECT status, total_amount FROM orders WHERE id = $1 FOR UPDATE`, refund.OrderID).Scan(&status, &total)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.ErrOrderNotFound
+		}
+		return wrapError(err)
+	}
+	if !status.CanTransitionTo(entities.OrderStatusRefunded) {
+		return apperror.ErrOrderNotRefundable
+	}
+
+	var refunded int64
+	err = tx.QueryRow(ctx,
+		`SELECT COALESCE(SUM(amount), 0) FROM refunds WHERE order_id = $1 AND status IN ($2, $3)`,
+		refund.OrderID, entities.RefundStatusPending, entities.RefundStatusSucceeded,
+	).Scan(&refunded)
+	if err != nil {
+		return wrapError(err)
+	}
+	if refunded+refund.Amount > total {
+		return apperror.ErrRefundExceedsOrder
+	}
+
+	for i := range refund.Items {
+		item := &refund.Items[i]
+		var remaining int
+		err := tx.QueryRow(ctx, `
+			SELECT oi.product_id, oi.quantity - COALESCE((
+				SELECT SUM(ri.quantity) FROM refund_items ri JOIN refunds rf ON rf.id = ri.refund_id
+				WHERE ri.order_item_id = oi.id AND rf.status IN ($3, $4)
+			), 0)
+			FROM order_items oi WHERE oi.id = $1 AND oi.order_id = $2`,
+			item.OrderItemID, refund.OrderID, entities.RefundStatusPending, entities.RefundStatusSucceeded,
+		).Scan(&item.ProductID, &remaining)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return apperror.ErrOrderItemNotFound
+			}
+			return wrapError(err)
+		}
+		if item.Quantity > remaining {
+			return apperror.ErrRefundExceedsOrder
+		}
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO refunds (id, order_id, payment_id, provider, status, amount, reason, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		RETURNING created_at, updated_at`,
+		refund.ID, refund.OrderID, refund.PaymentID, refund.Provider, refund.Status, refund.Amount, refund.Reason, refund.CreatedBy,
+	).Scan(&refund.CreatedAt, &refund.UpdatedAt)
+	if err != nil {
+		return wrapError(err)
+	}
+	for _, item := range refund.Items {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO refund_items (refund_id, order_item_id, product_id, quantity) VALUES ($1, $2, $3, $4)`,
+			refund.ID, item.OrderItemID, item.ProductID, item.Quantity,
+		)
+		if err != nil {
+			return wrapError(err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// Complete menandai refund berhasil, mengembalikan stok item dan memindahkan order yang
+// sudah direfund penuh ke refunded
+func (r *refundRepository) Complete(ctx context.Context, refund *entities.Refund, note string) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx,
+		`UPDATE refunds SET status = $1, provider_ref = $2, updated_at = NOW() WHERE id = $3 AND status = $4 RETURNING updated_at`,
+		entities.RefundStatusSucceeded, refund.ProviderRef, refund.ID, entities.RefundStatusPending,
+	).Scan(&refund.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, wrapError(fmt.Errorf("refund %s is not pending", refund.ID))
+		}
+		return false, wrapError(err)
+	}
+	refund.Status = entities.RefundStatusSucceeded
+
+	for _, item := range refund.Items {
+		_, err := tx.Exec(ctx, `UPDATE products SET stock = stock + $1, version = version + 1, updated_at = NOW() WHERE id = $2`, item.Quantity, item.ProductID)
+		if err != nil {
+			return false, wrapError(err)
+		}
+	}
+
+	var status entities.OrderStatus
+	var total int64
+	if err := tx.QueryRow(ctx, `SELECT status, total_amount FROM orders WHERE id = $1 FOR UPDATE`, refund.OrderID).Scan(&status, &total); err != nil {
+		return false, wrapError(err)
+	}
+	var refunded int64
+	err = tx.QueryRow(ctx,
+		`SELECT COALESCE(SUM(amount), 0) FROM refunds WHERE order_id = $1 AND status = $2`,
+		refund.OrderID, entities.RefundStatusSucceeded,
+	).Scan(&refunded)
+	if err != nil {
+		return false, wrapError(err)
+	}
+
+	orderRefunded := false
+	if refunded >= total && status.CanTransitionTo(entities.OrderStatusRefunded) {
+		if _, err := tx.Exec(ctx, `UPDATE orders SET status = $1, updated_at = NOW() WHERE id = $2`, entities.OrderStatusRefunded, refund.OrderID); err != nil {
+			return false, wrapError(err)
+		}
+		if err := insertStatusEvent(ctx, tx, refund.OrderID, entities.OrderStatusRefunded, note); err != nil {
+			return false, err
+		}
+		orderRefunded = true
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, wrapError(err)
+	}
+	return orderRefunded, nil
+}
+
+// Fail menandai refund pending gagal
+func (r *refundRepository) Fail(ctx context.Context, refund *entities.Refund) error {
+	err := retrying(r.db).QueryRow(ctx,
+		`UPDATE refunds SET status = $1, failure_reason = $2, updated_at = NOW() WHERE id = $3 AND status = $4 RETURNING updated_at`,
+		entities.RefundStatusFailed, refund.FailureReason, refund.ID, entities.RefundStatusPending,
+	).Scan(&refund.UpdatedAt)
+	if err != nil {
+		return wrapError(err)
+	}
+	refund.Status = entities.RefundStatusFailed
+	return nil
+}
+
+// RefundedQuantities mengambil jumlah per item order yang sudah dikembalikan ke stok oleh
+// refund yang berhasil
+func (r *refundRepository) RefundedQuantities(ctx context.Context, orderID uuid.UUID) (map[uuid.UUID]int, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT ri.order_item_id, SUM(ri.quantity)::int
+		FROM refund_items ri JOIN refunds rf ON rf.id = ri.refund_id
+		WHERE rf.order_id = $1 AND rf.status = $2
+		GROUP BY ri.order_item_id`, orderID, entities.RefundStatusSucceeded)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	quantities := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var itemID uuid.UUID
+		var quantity int
+		if err := rows.Scan(&itemID, &quantity); err != nil {
+			return nil, wrapError(err)
+		}
+		quantities[itemID] = quantity
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return quantities, nil
+}
+
+// ListByOrderID mengambil refund sebuah order beserta itemnya
+func (r *refundRepository) ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]*entities.Refund, error) {
+	rows, err := retrying(r.db).Query(ctx, `
+		SELECT id, order_id, payment_id, provider, provider_ref, status, amount, reason, failure_reason, created_by, created_at, updated_at
+		FROM refunds WHERE order_id = $1 ORDER BY created_at, id`, orderID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	refunds := make([]*entities.Refund, 0)
+	byID := make(map[uuid.UUID]*entities.Refund)
+	for rows.Next() {
+		var refund entities.Refund
+		if err := rows.Scan(
+			&refund.ID,
+			&refund.OrderID,
+			&refund.PaymentID,
+			&refund.Provider,
+			&refund.ProviderRef,
+			&refund.Status,
+			&refund.Amount,
+			&refund.Reason,
+			&refund.FailureReason,
+			&refund.CreatedBy,
+			&refund.CreatedAt,
+			&refund.UpdatedAt,
+		); err != nil {
+			return nil, wrapError(err)
+		}
+		refund.Items = []entities.RefundItem{}
+		refunds = append(refunds, &refund)
+		byID[refund.ID] = &refund
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	if len(refunds) == 0 {
+		return refunds, nil
+	}
+
+	ids := make([]uuid.UUID, len(refunds))
+	for i, refund := range refunds {
+		ids[i] = refund.ID
+	}
+	itemRows, err := retrying(r.db).Query(ctx,
+		`SELECT refund_id, order_item_id, product_id, quantity FROM refund_items WHERE refund_id = ANY($1) ORDER BY order_item_id`, ids)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer itemRows.Close()
+	for itemRows.Next() {
+		var refundID uuid.UUID
+		var item entities.RefundItem
+		if err := itemRows.Scan(&refundID, &item.OrderItemID, &item.ProductID, &item.Quantity); err != nil {
+			return nil, wrapError(err)
+		}
+		if refund, ok := byID[refundID]; ok {
+			refund.Items = append(refund.Items, item)
+		}
+	}
+	if err := itemRows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return refunds, nil
+}
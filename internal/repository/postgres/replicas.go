@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+	"postgresDB/internal/domain/repository"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Replicas spreads reads over the pools of the read replicas, round robin
+type Replicas struct {
+	pools []*pgxpool.Pool
+	next  atomic.Uint64
+}
+
+// NewReplicas untuk membuat instance baru dari Replicas. Tanpa pool, semua reads ke primary
+func NewReplicas(pools ...*pgxpool.Pool) *Replicas {
+	return &Replicas{
+		pools: pools,
+	}
+}
+
+// pick returns the next replica pool, nil when there is none
+func (rs *Replicas) pick() *pgxpool.Pool {
+	if rs == nil || len(rs.pools) == 0 {
+		return nil
+	}
+	return rs.pools[(rs.next.Add(1)-1)%uint64(len(rs.pools))]
+}
+
+// readConn returns the connection a read runs on: the transaction of ctx, a replica when
+// ctx allows stale reads, and the primary otherwise
+func readConn(ctx context.Context, db *pgxpool.Pool, replicas *Replicas) dbConn {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); !ok && repository.StaleReadsAllowed(ctx) {
+		if replica := replicas.pick(); replica != nil {
+			return retrying(replica)
+		}
+	}
+	return conn(ctx, db)
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"postgresDB/pkg/retry"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// retryPolicy bounds the retries of statements and transactions failing with a
+// transient error
+var retryPolicy = retry.Policy{
+	Attempts:  3,
+	BaseDelay: 20 * time.Millisecond,
+	MaxDelay:  500 * time.Millisecond,
+}
+
+// withRetry runs fn, and again when it fails with a transient error. In the transaction
+// of ctx fn runs once: the failure aborted the transaction, WithinTx runs all of it again
+func withRetry(ctx context.Context, fn func() error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn()
+	}
+	return retry.Do(ctx, "postgres", retryPolicy, isRetryable, fn)
+}
+
+// retrying runs the statements of db again when they fail with a transient error. Only
+// use it outside of a transaction, where a failed statement was rolled back on its own.
+// A query is retried until its rows are returned, errors while reading them are not.
+// CopyFrom isn't retried, its rows can only be read once
+func retrying(db dbConn) dbConn {
+	return retryingConn{db}
+}
+
+type retryingConn struct {
+	dbConn
+}
+
+func (c retryingConn) Exec(ctx context.Context, sql string, args ...any) (tag pgconn.CommandTag, err error) {
+	err = withRetry(ctx, func() error {
+		tag, err = c.dbConn.Exec(ctx, sql, args...)
+		return err
+	})
+	return tag, err
+}
+
+func (c retryingConn) Query(ctx context.Context, sql string, args ...any) (rows pgx.Rows, err error) {
+	err = withRetry(ctx, func() error {
+		rows, err = c.dbConn.Query(ctx, sql, args...)
+		return err
+	})
+	return rows, err
+}
+
+func (c retryingConn) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return retryingRow{ctx: ctx, db: c.dbConn, sql: sql, args: args}
+}
+
+// retryingRow runs its query when it's scanned, so a failed scan can run it again
+type retryingRow struct {
+	ctx  context.Context
+	db   dbConn
+	sql  string
+	args []any
+}
+
+func (r retryingRow) Scan(dest ...any) error {
+	return withRetry(r.ctx, func() error {
+		return r.db.QueryRow(r.ctx, r.sql, r.args...).Scan(dest...)
+	})
+}
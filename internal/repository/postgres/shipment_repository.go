@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type shipmentRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewShipmentRepository untuk membuat instance baru dari ShipmentRepository
+func NewShipmentRepository(db *pgxpool.Pool) repository.ShipmentRepository {
+	return &shipmentRepository{
+		db: db,
+	}
+}
+
+// Create mencatat pengiriman dan menyalin carrier serta nomor resinya ke pesanan
+func (r *shipmentRepository) Create(ctx context.Context, shipment *entities.Shipment) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO shipments (id, order_id, carrier, tracking_number, shipped_at, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err = tx.Exec(ctx, query, shipment.ID, shipment.OrderID, shipment.Carrier, shipment.TrackingNumber,
+		shipment.ShippedAt, shipment.CreatedBy, shipment.CreatedAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return apperror.ErrOrderNotFound
+		}
+		return wrapError(err)
+	}
+	// the order shows the latest shipment, a late record of an earlier parcel leaves it
+	_, err = tx.Exec(ctx, `
+		UPDATE orders SET carrier = $1, tracking_number = $2, updated_at = NOW()
+		WHERE id = $3 AND NOT EXISTS (SELECT 1 FROM shipments WHERE order_id = $3 AND shipped_at > $4)`,
+		shipment.Carrier, shipment.TrackingNumber, shipment.OrderID, shipment.ShippedAt)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// ListByOrderID mengambil pengiriman sebuah pesanan, yang terlama lebih dulu
+func (r *shipmentRepository) ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]entities.Shipment, error) {
+	return listShipments(ctx, conn(ctx, r.db), orderID)
+}
+
+// listShipments returns the shipments of an order, the order repository loads them along
+// with the items
+func listShipments(ctx context.Context, db dbConn, orderID uuid.UUID) ([]entities.Shipment, error) {
+	query := `
+		SELECT id, order_id, carrier, tracking_number, shipped_at, created_by, created_at
+		FROM shipments WHERE order_id = $1 ORDER BY shipped_at, created_at
+	`
+	rows, err := db.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	shipments := make([]entities.Shipment, 0)
+	for rows.Next() {
+		var s entities.Shipment
+		if err := rows.Scan(&s.ID, &s.OrderID, &s.Carrier, &s.TrackingNumber, &s.ShippedAt, &s.CreatedBy, &s.CreatedAt); err != nil {
+			return nil, wrapError(err)
+		}
+		shipments = append(shipments, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return shipments, nil
+}
@@ -0,0 +1,19 @@
+package postgres
+
+import "postgresDB/internal/domain/repository"
+
+// notDeleted is the condition of rows that aren't soft deleted, the default scope of a
+// table with a deleted_at column
+const notDeleted = "deleted_at IS NULL"
+
+// deletedCondition returns the condition selecting the rows of scope by the deleted_at
+// column, "" when scope selects every row
+func deletedCondition(scope repository.DeletedScope) string {
+	switch scope {
+	case repository.DeletedExclude:
+		return notDeleted
+	case repository.DeletedOnly:
+		return "deleted_at IS NOT NULL"
+	}
+	return ""
+}
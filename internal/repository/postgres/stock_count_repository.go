@@ -0,0 +1,290 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const stockCountColumns = `id, warehouse_id, category_id, status, note, created_by, submitted_by, submitted_at, reviewed_by, reviewed_at, review_note, created_at, updated_at`
+
+type stockCountRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewStockCountRepository untuk membuat instance baru dari StockCountRepository
+func NewStockCountRepository(db *pgxpool.Pool) repository.StockCountRepository {
+	return &stockCountRepository{
+		db: db,
+	}
+}
+
+// Create membuka sesi stock opname dan mencatat stok sistem saat ini sebagai stok yang diharapkan
+func (r *stockCountRepository) Create(ctx context.Context, count *entities.StockCount) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO stock_counts (id, warehouse_id, category_id, status, note, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+	err = tx.QueryRow(ctx, query,
+		count.ID, count.WarehouseID, count.CategoryID, string(count.Status), count.Note, count.CreatedBy,
+	).Scan(&count.CreatedAt, &count.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return apperror.ErrStockCountInProgress
+		}
+		if isForeignKeyViolation(err) {
+			return apperror.ErrWarehouseNotFound
+		}
+		return wrapError(err)
+	}
+
+	snapshot := `
+		INSERT INTO stock_count_items (count_id, product_id, expected_quantity)
+		SELECT $1, ws.product_id, ws.quantity
+		FROM warehouse_stock ws
+		JOIN products p ON p.id = ws.product_id
+		WHERE ws.warehouse_id = $2 AND p.deleted_at IS NULL AND ($3::uuid IS NULL OR p.category_id = $3)
+	`
+	if _, err := tx.Exec(ctx, snapshot, count.ID, count.WarehouseID, count.CategoryID); err != nil {
+		return wrapError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// GetByID mengambil sesi stock opname beserta itemnya
+func (r *stockCountRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.StockCount, error) {
+	count, err := scanStockCount(retrying(r.db).QueryRow(ctx, `SELECT `+stockCountColumns+` FROM stock_counts WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrStockCountNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	query := `
+		SELECT i.product_id, p.name, i.expected_quantity, i.counted_quantity, i.counted_by, i.counted_at
+		FROM stock_count_items i
+		JOIN products p ON p.id = i.product_id
+		WHERE i.count_id = $1
+		ORDER BY p.name, i.product_id
+	`
+	rows, err := retrying(r.db).Query(ctx, query, id)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	count.Items = make([]entities.StockCountItem, 0)
+	for rows.Next() {
+		var item entities.StockCountItem
+		if err := rows.Scan(&item.ProductID, &item.ProductName, &item.Expected, &item.Counted, &item.CountedBy, &item.CountedAt); err != nil {
+			return nil, wrapError(err)
+		}
+		count.Items = append(count.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return count, nil
+}
+
+// List mengambil sesi stock opname dengan status tertentu, terbaru lebih dulu
+func (r *stockCountRepository) List(ctx context.Context, status entities.StockCountStatus, limit, offset int) ([]*entities.StockCount, int64, error) {
+	where := ` WHERE ($1 = '' OR status = $1)`
+
+	var total int64
+	if err := retrying(r.db).QueryRow(ctx, `SELECT COUNT(*) FROM stock_counts`+where, string(status)).Scan(&total); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	query := `SELECT ` + stockCountColumns + ` FROM stock_counts` + where + ` ORDER BY created_at DESC, id LIMIT $2 OFFSET $3`
+	rows, err := retrying(r.db).Query(ctx, query, string(status), limit, offset)
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+	defer rows.Close()
+
+	counts := make([]*entities.StockCount, 0, limit)
+	for rows.Next() {
+		count, err := scanStockCount(rows)
+		if err != nil {
+			return nil, 0, wrapError(err)
+		}
+		counts = append(counts, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, wrapError(err)
+	}
+	return counts, total, nil
+}
+
+// RecordCounts menyimpan jumlah hasil hitung untuk sesi yang masih berjalan
+func (r *stockCountRepository) RecordCounts(ctx context.Context, id, userID uuid.UUID, items []entities.StockCountItem) error {
+	return r.withCount(ctx, id, []entities.StockCountStatus{entities.StockCountCounting}, func(tx pgx.Tx, count *entities.StockCount) error {
+		query := `
+			INSERT INTO stock_count_items (count_id, product_id, expected_quantity, counted_quantity, counted_by, counted_at)
+			VALUES ($1, $2, COALESCE((SELECT quantity FROM warehouse_stock WHERE warehouse_id = $3 AND product_id = $2), 0), $4, $5, NOW())
+			ON CONFLICT (count_id, product_id) DO UPDATE
+			SET counted_quantity = EXCLUDED.counted_quantity, counted_by = EXCLUDED.counted_by, counted_at = EXCLUDED.counted_at
+		`
+		for _, item := range items {
+			if _, err := tx.Exec(ctx, query, id, item.ProductID, count.WarehouseID, item.Counted, userID); err != nil {
+				if isForeignKeyViolation(err) {
+					return apperror.ErrProductNotFound
+				}
+				return err
+			}
+		}
+		_, err := tx.Exec(ctx, `UPDATE stock_counts SET updated_at = NOW() WHERE id = $1`, id)
+		return err
+	})
+}
+
+// Submit mengajukan sesi yang semua produknya sudah dihitung untuk disetujui
+func (r *stockCountRepository) Submit(ctx context.Context, id, userID uuid.UUID) error {
+	return r.withCount(ctx, id, []entities.StockCountStatus{entities.StockCountCounting}, func(tx pgx.Tx, _ *entities.StockCount) error {
+		var uncounted bool
+		if err := tx.QueryRow(ctx,
+			`SELECT EXISTS (SELECT 1 FROM stock_count_items WHERE count_id = $1 AND counted_quantity IS NULL)`, id,
+		).Scan(&uncounted); err != nil {
+			return err
+		}
+		if uncounted {
+			return apperror.ErrStockCountIncomplete
+		}
+
+		_, err := tx.Exec(ctx,
+			`UPDATE stock_counts SET status = $1, submitted_by = $2, submitted_at = NOW(), updated_at = NOW() WHERE id = $3`,
+			string(entities.StockCountSubmitted), userID, id,
+		)
+		return err
+	})
+}
+
+// Approve membukukan selisih hasil hitung ke ledger stok
+func (r *stockCountRepository) Approve(ctx context.Context, id, userID uuid.UUID, note string) error {
+	return r.withCount(ctx, id, []entities.StockCountStatus{entities.StockCountSubmitted}, func(tx pgx.Tx, count *entities.StockCount) error {
+		rows, err := tx.Query(ctx,
+			`SELECT product_id, counted_quantity - expected_quantity FROM stock_count_items WHERE count_id = $1 AND counted_quantity <> expected_quantity`, id,
+		)
+		if err != nil {
+			return err
+		}
+		var entries []entities.StockLedgerEntry
+		for rows.Next() {
+			entry := entities.StockLedgerEntry{
+				WarehouseID: count.WarehouseID,
+				Reason:      entities.LedgerStockCount,
+				ReferenceID: &count.ID,
+				Note:        note,
+				CreatedBy:   &userID,
+			}
+			if err := rows.Scan(&entry.ProductID, &entry.Quantity); err != nil {
+				rows.Close()
+				return err
+			}
+			entries = append(entries, entry)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if _, err := postStockLedger(ctx, tx, entries); err != nil {
+			return err
+		}
+		return r.review(ctx, tx, id, entities.StockCountApproved, userID, note)
+	})
+}
+
+// Reject membatalkan sesi tanpa mengubah stok
+func (r *stockCountRepository) Reject(ctx context.Context, id, userID uuid.UUID, note string) error {
+	statuses := []entities.StockCountStatus{entities.StockCountCounting, entities.StockCountSubmitted}
+	return r.withCount(ctx, id, statuses, func(tx pgx.Tx, _ *entities.StockCount) error {
+		return r.review(ctx, tx, id, entities.StockCountRejected, userID, note)
+	})
+}
+
+// review closes a session with the reviewer's decision
+func (r *stockCountRepository) review(ctx context.Context, tx pgx.Tx, id uuid.UUID, status entities.StockCountStatus, userID uuid.UUID, note string) error {
+	_, err := tx.Exec(ctx,
+		`UPDATE stock_counts SET status = $1, reviewed_by = $2, reviewed_at = NOW(), review_note = $3, updated_at = NOW() WHERE id = $4`,
+		string(status), userID, note, id,
+	)
+	return err
+}
+
+// withCount runs apply in a transaction holding the lock of a session in one of statuses
+func (r *stockCountRepository) withCount(ctx context.Context, id uuid.UUID, statuses []entities.StockCountStatus, apply func(pgx.Tx, *entities.StockCount) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	count, err := scanStockCount(tx.QueryRow(ctx, `SELECT `+stockCountColumns+` FROM stock_counts WHERE id = $1 FOR UPDATE`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.ErrStockCountNotFound
+		}
+		return wrapError(err)
+	}
+	if !slices.Contains(statuses, count.Status) {
+		return apperror.ErrStockCountStatus
+	}
+
+	if err := apply(tx, count); err != nil {
+		if apperror.IsAppError(err) {
+			return err
+		}
+		return wrapError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// scanStockCount scans a row selected with stockCountColumns
+func scanStockCount(row pgx.Row) (*entities.StockCount, error) {
+	var count entities.StockCount
+	var status string
+	if err := row.Scan(
+		&count.ID,
+		&count.WarehouseID,
+		&count.CategoryID,
+		&status,
+		&count.Note,
+		&count.CreatedBy,
+		&count.SubmittedBy,
+		&count.SubmittedAt,
+		&count.ReviewedBy,
+		&count.ReviewedAt,
+		&count.ReviewNote,
+		&count.CreatedAt,
+		&count.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	count.Status = entities.StockCountStatus(status)
+	return &count, nil
+}
@@ -0,0 +1,307 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// reservedQuantity is the quantity of a product settled from an order's reservations
+type reservedQuantity struct {
+	productID uuid.UUID
+	quantity  int
+}
+
+type stockReservationRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewStockReservationRepository untuk membuat instance baru dari StockReservationRepository
+func NewStockReservationRepository(db *pgxpool.Pool) repository.StockReservationRepository {
+	return &stockReservationRepository{
+		db: db,
+	}
+}
+
+// Reserve menahan stok untuk semua reservasi dalam satu transaksi. Produk dikunci
+// berurutan berdasarkan ID agar order yang berjalan bersamaan tidak saling deadlock
+func (r *stockReservationRepository) Reserve(ctx context.Context, reservations []entities.StockReservation) ([]*entities.StockLevel, error) {
+	sorted := slices.Clone(reservations)
+	slices.SortStableFunc(sorted, func(a, b entities.StockReservation) int {
+		return bytes.Compare(a.ProductID[:], b.ProductID[:])
+	})
+
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE products SET reserved_stock = reserved_stock + $1, version = version + 1
+		WHERE id = $2 AND deleted_at IS NULL AND stock - reserved_stock >= $1
+		RETURNING stock - reserved_stock + $1, stock - reserved_stock, reorder_threshold
+	`
+	levels := make([]*entities.StockLevel, 0, len(sorted))
+	rows := make([][]any, len(sorted))
+	for i, reservation := range sorted {
+		level := entities.StockLevel{ProductID: reservation.ProductID}
+		err := tx.QueryRow(ctx, query, reservation.Quantity, reservation.ProductID).Scan(&level.Previous, &level.Current, &level.ReorderThreshold)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, apperror.ErrInsufficientStock
+			}
+			return nil, wrapError(err)
+		}
+		levels = append(levels, &level)
+		rows[i] = []any{reservation.ID, reservation.OrderID, reservation.ProductID, reservation.Quantity, string(reservation.Status), reservation.ExpiresAt, reservation.CreatedAt, reservation.CreatedAt}
+	}
+
+	if _, err := tx.CopyFrom(ctx,
+		pgx.Identifier{"stock_reservations"},
+		[]string{"id", "order_id", "product_id", "quantity", "status", "expires_at", "created_at", "updated_at"},
+		pgx.CopyFromRows(rows),
+	); err != nil {
+		return nil, wrapError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, wrapError(err)
+	}
+	return levels, nil
+}
+
+// Convert memotong stok sebesar reservasi aktif sebuah order
+func (r *stockReservationRepository) Convert(ctx context.Context, orderID uuid.UUID) ([]*entities.StockLevel, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	quantities, _, err := settleReservations(ctx, tx, []uuid.UUID{orderID}, entities.ReservationConverted)
+	if err != nil {
+		return nil, err
+	}
+	levels, err := deductReserved(ctx, tx, quantities)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, wrapError(err)
+	}
+	return levels, nil
+}
+
+// Release mengembalikan reservasi aktif sebuah order tanpa mengubah stok
+func (r *stockReservationRepository) Release(ctx context.Context, orderID uuid.UUID) (int, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	quantities, released, err := settleReservations(ctx, tx, []uuid.UUID{orderID}, entities.ReservationReleased)
+	if err != nil {
+		return 0, err
+	}
+	if err := releaseReserved(ctx, tx, quantities); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, wrapError(err)
+	}
+	return released, nil
+}
+
+// ReleaseExpired mengembalikan hingga limit reservasi kedaluwarsa milik order yang masih
+// pending (atau yang ordernya tidak pernah tersimpan) dan membatalkan order tersebut.
+// Baris yang sedang dikunci instance lain dilewati
+func (r *stockReservationRepository) ReleaseExpired(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		SELECT r.order_id
+		FROM stock_reservations r
+		LEFT JOIN orders o ON o.id = r.order_id
+		WHERE r.status = $1 AND r.expires_at <= $2 AND (o.id IS NULL OR o.status = $3)
+		ORDER BY r.expires_at
+		LIMIT $4
+		FOR UPDATE OF r SKIP LOCKED
+	`
+	rows, err := tx.Query(ctx, query, entities.ReservationActive, now, entities.OrderStatusPending, limit)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	orderIDs, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	slices.SortFunc(orderIDs, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+	orderIDs = slices.Compact(orderIDs)
+	if len(orderIDs) == 0 {
+		return nil, nil
+	}
+
+	cancelled, err := cancelPendingOrders(ctx, tx, orderIDs, entities.ReservationExpired, "Reservasi stok kedaluwarsa, order dibatalkan")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, wrapError(err)
+	}
+	return cancelled, nil
+}
+
+// CancelPendingWithProduct membatalkan order pending yang berisi produk dan melepas reservasinya
+func (r *stockReservationRepository) CancelPendingWithProduct(ctx context.Context, productID uuid.UUID) ([]uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		SELECT id FROM orders
+		WHERE status = $2 AND id IN (SELECT order_id FROM order_items WHERE product_id = $1)
+		ORDER BY id
+		FOR UPDATE
+	`
+	rows, err := tx.Query(ctx, query, productID, entities.OrderStatusPending)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	orderIDs, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if len(orderIDs) == 0 {
+		return nil, nil
+	}
+
+	cancelled, err := cancelPendingOrders(ctx, tx, orderIDs, entities.ReservationReleased, "Produk dihapus, order dibatalkan")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, wrapError(err)
+	}
+	return cancelled, nil
+}
+
+// cancelPendingOrders settles the active reservations of the orders with status, gives
+// their stock back and cancels the orders that are still pending, returning their IDs
+func cancelPendingOrders(ctx context.Context, tx pgx.Tx, orderIDs []uuid.UUID, status entities.ReservationStatus, note string) ([]uuid.UUID, error) {
+	quantities, _, err := settleReservations(ctx, tx, orderIDs, status)
+	if err != nil {
+		return nil, err
+	}
+	if err := releaseReserved(ctx, tx, quantities); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx,
+		`UPDATE orders SET status = $1, updated_at = NOW() WHERE id = ANY($2) AND status = $3 RETURNING id`,
+		entities.OrderStatusCancelled, orderIDs, entities.OrderStatusPending,
+	)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	cancelled, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	for _, id := range cancelled {
+		if err := insertStatusEvent(ctx, tx, id, entities.OrderStatusCancelled, note); err != nil {
+			return nil, err
+		}
+	}
+	return cancelled, nil
+}
+
+// settleReservations moves the active reservations of the orders to status and returns
+// their quantity per product, ordered by product so locks are taken consistently, and
+// the number of reservations settled
+func settleReservations(ctx context.Context, tx pgx.Tx, orderIDs []uuid.UUID, status entities.ReservationStatus) ([]reservedQuantity, int, error) {
+	query := `
+		WITH settled AS (
+			UPDATE stock_reservations SET status = $1, updated_at = NOW()
+			WHERE order_id = ANY($2) AND status = $3
+			RETURNING product_id, quantity
+		)
+		SELECT product_id, SUM(quantity)::int, COUNT(*)::int FROM settled GROUP BY product_id ORDER BY product_id
+	`
+	rows, err := tx.Query(ctx, query, status, orderIDs, entities.ReservationActive)
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+	defer rows.Close()
+
+	var quantities []reservedQuantity
+	settled := 0
+	for rows.Next() {
+		var q reservedQuantity
+		var count int
+		if err := rows.Scan(&q.productID, &q.quantity, &count); err != nil {
+			return nil, 0, wrapError(err)
+		}
+		quantities = append(quantities, q)
+		settled += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, wrapError(err)
+	}
+	return quantities, settled, nil
+}
+
+// releaseReserved gives reserved quantities back to the available stock
+func releaseReserved(ctx context.Context, tx pgx.Tx, quantities []reservedQuantity) error {
+	for _, q := range quantities {
+		if _, err := tx.Exec(ctx,
+			`UPDATE products SET reserved_stock = GREATEST(reserved_stock - $1, 0), version = version + 1 WHERE id = $2`,
+			q.quantity, q.productID,
+		); err != nil {
+			return wrapError(err)
+		}
+	}
+	return nil
+}
+
+// deductReserved takes reserved quantities out of the stock. Stock an admin lowered below
+// the reserved quantity in the meantime bottoms out at zero
+func deductReserved(ctx context.Context, tx pgx.Tx, quantities []reservedQuantity) ([]*entities.StockLevel, error) {
+	query := `
+		WITH prev AS (SELECT id, stock FROM products WHERE id = $2 FOR UPDATE)
+		UPDATE products p
+		SET stock = GREATEST(p.stock - $1, 0), reserved_stock = GREATEST(p.reserved_stock - $1, 0), version = p.version + 1, updated_at = NOW()
+		FROM prev
+		WHERE p.id = prev.id
+		RETURNING prev.stock, p.stock, p.reorder_threshold
+	`
+	levels := make([]*entities.StockLevel, 0, len(quantities))
+	for _, q := range quantities {
+		level := entities.StockLevel{ProductID: q.productID}
+		if err := tx.QueryRow(ctx, query, q.quantity, q.productID).Scan(&level.Previous, &level.Current, &level.ReorderThreshold); err != nil {
+			return nil, wrapError(err)
+		}
+		levels = append(levels, &level)
+	}
+	return levels, nil
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"postgresDB/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+func TestStockReservationRepositoryConvertAfterStockLowered(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	products := NewProductRepository(pool, nil)
+	repo := NewStockReservationRepository(pool)
+
+	product := &entities.Product{ID: uuid.New(), Name: "convert reservation test", Price: 1000, Stock: 4}
+	if err := products.Create(ctx, product); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DELETE FROM products WHERE id = $1`, product.ID)
+	})
+
+	now := time.Now()
+	paid, pending := uuid.New(), uuid.New()
+	for _, orderID := range []uuid.UUID{paid, pending} {
+		_, err := repo.Reserve(ctx, []entities.StockReservation{{
+			ID:        uuid.New(),
+			OrderID:   orderID,
+			ProductID: product.ID,
+			Quantity:  2,
+			Status:    entities.ReservationActive,
+			ExpiresAt: now.Add(time.Hour),
+			CreatedAt: now,
+		}})
+		if err != nil {
+			t.Fatalf("Reserve: %v", err)
+		}
+	}
+
+	// an admin counted one unit less than booked
+	if _, err := pool.Exec(ctx, `UPDATE products SET stock = 3 WHERE id = $1`, product.ID); err != nil {
+		t.Fatalf("lowering the stock: %v", err)
+	}
+
+	levels, err := repo.Convert(ctx, paid)
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	if len(levels) != 1 || levels[0].Previous != 3 || levels[0].Current != 1 {
+		t.Fatalf("levels = %+v, want one level from 3 to 1", levels)
+	}
+
+	current, err := products.GetCurrent(ctx, product.ID)
+	if err != nil {
+		t.Fatalf("GetCurrent: %v", err)
+	}
+	if current.ReservedStock != 2 {
+		t.Errorf("reserved stock = %d, want the 2 of the pending order", current.ReservedStock)
+	}
+
+	// the pending order's reservation exceeds what's left, its conversion bottoms out at zero
+	// like a payment or capture would
+	if _, err := repo.Convert(ctx, pending); err != nil {
+		t.Fatalf("Convert of the pending order: %v", err)
+	}
+	if current, err = products.GetCurrent(ctx, product.ID); err != nil {
+		t.Fatalf("GetCurrent: %v", err)
+	}
+	if current.Stock != 0 || current.ReservedStock != 0 {
+		t.Errorf("stock = %d, reserved = %d, want both 0", current.Stock, current.ReservedStock)
+	}
+}
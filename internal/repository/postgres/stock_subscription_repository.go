@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type stockSubscriptionRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewStockSubscriptionRepository creates a new StockSubscriptionRepository instance
+func NewStockSubscriptionRepository(db *pgxpool.Pool) repository.StockSubscriptionRepository {
+	return &stockSubscriptionRepository{
+		db: db,
+	}
+}
+
+// Subscribe menyimpan langganan stok; langganan yang sudah ada dibiarkan
+func (r *stockSubscriptionRepository) Subscribe(ctx context.Context, userID, productID uuid.UUID) (bool, error) {
+	query := `
+		INSERT INTO stock_subscriptions (user_id, product_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, product_id) DO NOTHING
+	`
+	res, err := retrying(r.db).Exec(ctx, query, userID, productID)
+	if err != nil {
+		return false, wrapError(err)
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+// Unsubscribe menghapus langganan stok seorang user
+func (r *stockSubscriptionRepository) Unsubscribe(ctx context.Context, userID, productID uuid.UUID) error {
+	res, err := retrying(r.db).Exec(ctx, `DELETE FROM stock_subscriptions WHERE user_id = $1 AND product_id = $2`, userID, productID)
+	if err != nil {
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrStockSubscriptionNotFound
+	}
+	return nil
+}
+
+// ListByUser mengambil langganan stok seorang user beserta produknya, terbaru lebih dulu
+func (r *stockSubscriptionRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.StockSubscription, int64, error) {
+	var total int64
+	if err := retrying(r.db).QueryRow(ctx, `SELECT COUNT(*) FROM stock_subscriptions WHERE user_id = $1`, userID).Scan(&total); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	query := `
+		SELECT s.user_id, s.product_id, p.name, p.stock, s.created_at
+		FROM stock_subscriptions s
+		JOIN products p ON p.id = s.product_id
+		WHERE s.user_id = $1
+		ORDER BY s.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := retrying(r.db).Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*entities.StockSubscription, 0, limit)
+	for rows.Next() {
+		var s entities.StockSubscription
+		if err := rows.Scan(&s.UserID, &s.ProductID, &s.ProductName, &s.Stock, &s.CreatedAt); err != nil {
+			return nil, 0, wrapError(err)
+		}
+		subscriptions = append(subscriptions, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, wrapError(err)
+	}
+	return subscriptions, total, nil
+}
+
+// ListSubscribers mengambil user aktif yang berlangganan stok produk, langganan terlama lebih dulu
+func (r *stockSubscriptionRepository) ListSubscribers(ctx context.Context, productID uuid.UUID, limit int) ([]entities.StockSubscriber, error) {
+	query := `
+		SELECT u.id, u.username, u.email
+		FROM stock_subscriptions s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.product_id = $1 AND u.is_active
+		ORDER BY s.created_at
+		LIMIT $2
+	`
+	rows, err := retrying(r.db).Query(ctx, query, productID, limit)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	subscribers := make([]entities.StockSubscriber, 0, limit)
+	for rows.Next() {
+		var s entities.StockSubscriber
+		if err := rows.Scan(&s.UserID, &s.Username, &s.Email); err != nil {
+			return nil, wrapError(err)
+		}
+		subscribers = append(subscribers, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return subscribers, nil
+}
+
+// Remove menghapus langganan beberapa user pada sebuah produk
+func (r *stockSubscriptionRepository) Remove(ctx context.Context, productID uuid.UUID, userIDs []uuid.UUID) error {
+	_, err := retrying(r.db).Exec(ctx, `DELETE FROM stock_subscriptions WHERE product_id = $1 AND user_id = ANY($2)`, productID, userIDs)
+	if err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// ProductsWithSubscribers mengambil produk di antara ids yang memiliki pelanggan stok
+func (r *stockSubscriptionRepository) ProductsWithSubscribers(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := retrying(r.db).Query(ctx, `SELECT DISTINCT product_id FROM stock_subscriptions WHERE product_id = ANY($1)`, ids)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	var products []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, wrapError(err)
+		}
+		products = append(products, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return products, nil
+}
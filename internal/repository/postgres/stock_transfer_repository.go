@@ -0,0 +1,288 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const stockTransferColumns = `id, source_warehouse_id, destination_warehouse_id, status, note, created_by, shipped_by, shipped_at, received_by, received_at, created_at, updated_at`
+
+type stockTransferRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewStockTransferRepository untuk membuat instance baru dari StockTransferRepository
+func NewStockTransferRepository(db *pgxpool.Pool) repository.StockTransferRepository {
+	return &stockTransferRepository{
+		db: db,
+	}
+}
+
+// Create menyimpan transfer draft beserta itemnya dalam satu transaksi
+func (r *stockTransferRepository) Create(ctx context.Context, transfer *entities.StockTransfer) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO stock_transfers (id, source_warehouse_id, destination_warehouse_id, status, note, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+	err = tx.QueryRow(ctx, query,
+		transfer.ID, transfer.SourceWarehouseID, transfer.DestinationWarehouseID, string(transfer.Status), transfer.Note, transfer.CreatedBy,
+	).Scan(&transfer.CreatedAt, &transfer.UpdatedAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return apperror.ErrWarehouseNotFound
+		}
+		return wrapError(err)
+	}
+
+	rows := make([][]any, len(transfer.Items))
+	for i, item := range transfer.Items {
+		rows[i] = []any{transfer.ID, item.ProductID, item.Quantity}
+	}
+	if _, err := tx.CopyFrom(ctx,
+		pgx.Identifier{"stock_transfer_items"},
+		[]string{"transfer_id", "product_id", "quantity"},
+		pgx.CopyFromRows(rows),
+	); err != nil {
+		if isForeignKeyViolation(err) {
+			return apperror.ErrProductNotFound
+		}
+		return wrapError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// GetByID mengambil transfer beserta itemnya
+func (r *stockTransferRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.StockTransfer, error) {
+	transfer, err := scanStockTransfer(retrying(r.db).QueryRow(ctx, `SELECT `+stockTransferColumns+` FROM stock_transfers WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrStockTransferNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	items, err := r.listItems(ctx, []uuid.UUID{id})
+	if err != nil {
+		return nil, err
+	}
+	transfer.Items = items[id]
+	return transfer, nil
+}
+
+// List mengambil transfer dengan salah satu status, terlama lebih dulu
+func (r *stockTransferRepository) List(ctx context.Context, statuses []entities.TransferStatus, limit, offset int) ([]*entities.StockTransfer, int64, error) {
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+
+	var total int64
+	if err := retrying(r.db).QueryRow(ctx, `SELECT COUNT(*) FROM stock_transfers WHERE status = ANY($1)`, names).Scan(&total); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	query := `SELECT ` + stockTransferColumns + ` FROM stock_transfers WHERE status = ANY($1) ORDER BY created_at, id LIMIT $2 OFFSET $3`
+	rows, err := retrying(r.db).Query(ctx, query, names, limit, offset)
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+	defer rows.Close()
+
+	transfers := make([]*entities.StockTransfer, 0, limit)
+	ids := make([]uuid.UUID, 0, limit)
+	for rows.Next() {
+		transfer, err := scanStockTransfer(rows)
+		if err != nil {
+			return nil, 0, wrapError(err)
+		}
+		transfers = append(transfers, transfer)
+		ids = append(ids, transfer.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	items, err := r.listItems(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, transfer := range transfers {
+		transfer.Items = items[transfer.ID]
+	}
+	return transfers, total, nil
+}
+
+// Ship mengeluarkan item transfer draft dari gudang asal melalui ledger
+func (r *stockTransferRepository) Ship(ctx context.Context, id, userID uuid.UUID) (*entities.StockTransfer, error) {
+	return r.advance(ctx, id, entities.TransferDraft, entities.TransferInTransit, func(tx pgx.Tx, transfer *entities.StockTransfer) error {
+		if _, err := postStockLedger(ctx, tx, transferMovements(transfer, transfer.SourceWarehouseID, -1, entities.LedgerTransferOut, userID)); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `UPDATE stock_transfers SET shipped_by = $1, shipped_at = NOW() WHERE id = $2`, userID, id)
+		return err
+	})
+}
+
+// Receive memasukkan item transfer yang sedang dikirim ke gudang tujuan melalui ledger
+func (r *stockTransferRepository) Receive(ctx context.Context, id, userID uuid.UUID) (*entities.StockTransfer, error) {
+	return r.advance(ctx, id, entities.TransferInTransit, entities.TransferReceived, func(tx pgx.Tx, transfer *entities.StockTransfer) error {
+		if _, err := postStockLedger(ctx, tx, transferMovements(transfer, transfer.DestinationWarehouseID, 1, entities.LedgerTransferIn, userID)); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `UPDATE stock_transfers SET received_by = $1, received_at = NOW() WHERE id = $2`, userID, id)
+		return err
+	})
+}
+
+// Cancel membatalkan transfer draft
+func (r *stockTransferRepository) Cancel(ctx context.Context, id uuid.UUID) (*entities.StockTransfer, error) {
+	return r.advance(ctx, id, entities.TransferDraft, entities.TransferCancelled, func(pgx.Tx, *entities.StockTransfer) error {
+		return nil
+	})
+}
+
+// advance moves a transfer from one status to the next within a transaction, the
+// transfer row is locked so a document is shipped or received only once
+func (r *stockTransferRepository) advance(ctx context.Context, id uuid.UUID, from, to entities.TransferStatus, apply func(pgx.Tx, *entities.StockTransfer) error) (*entities.StockTransfer, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	transfer, err := scanStockTransfer(tx.QueryRow(ctx, `SELECT `+stockTransferColumns+` FROM stock_transfers WHERE id = $1 FOR UPDATE`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrStockTransferNotFound
+		}
+		return nil, wrapError(err)
+	}
+	if transfer.Status != from {
+		return nil, apperror.ErrStockTransferStatus
+	}
+
+	rows, err := tx.Query(ctx, `SELECT product_id, quantity FROM stock_transfer_items WHERE transfer_id = $1`, id)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	for rows.Next() {
+		var item entities.StockTransferItem
+		if err := rows.Scan(&item.ProductID, &item.Quantity); err != nil {
+			rows.Close()
+			return nil, wrapError(err)
+		}
+		transfer.Items = append(transfer.Items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	if err := apply(tx, transfer); err != nil {
+		if apperror.IsAppError(err) {
+			return nil, err
+		}
+		return nil, wrapError(err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE stock_transfers SET status = $1, updated_at = NOW() WHERE id = $2`, string(to), id); err != nil {
+		return nil, wrapError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, wrapError(err)
+	}
+	return r.GetByID(ctx, id)
+}
+
+// listItems retrieves the items of several transfers with their product names
+func (r *stockTransferRepository) listItems(ctx context.Context, transferIDs []uuid.UUID) (map[uuid.UUID][]entities.StockTransferItem, error) {
+	items := make(map[uuid.UUID][]entities.StockTransferItem, len(transferIDs))
+	if len(transferIDs) == 0 {
+		return items, nil
+	}
+
+	query := `
+		SELECT i.transfer_id, i.product_id, p.name, i.quantity
+		FROM stock_transfer_items i
+		JOIN products p ON p.id = i.product_id
+		WHERE i.transfer_id = ANY($1)
+		ORDER BY i.transfer_id, p.name
+	`
+	rows, err := retrying(r.db).Query(ctx, query, transferIDs)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transferID uuid.UUID
+		var item entities.StockTransferItem
+		if err := rows.Scan(&transferID, &item.ProductID, &item.ProductName, &item.Quantity); err != nil {
+			return nil, wrapError(err)
+		}
+		items[transferID] = append(items[transferID], item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return items, nil
+}
+
+// transferMovements returns the ledger movements of a transfer's items in a warehouse,
+// sign is -1 for stock leaving and 1 for stock arriving
+func transferMovements(transfer *entities.StockTransfer, warehouseID uuid.UUID, sign int, reason entities.LedgerReason, userID uuid.UUID) []entities.StockLedgerEntry {
+	entries := make([]entities.StockLedgerEntry, len(transfer.Items))
+	for i, item := range transfer.Items {
+		entries[i] = entities.StockLedgerEntry{
+			WarehouseID: warehouseID,
+			ProductID:   item.ProductID,
+			Quantity:    sign * item.Quantity,
+			Reason:      reason,
+			ReferenceID: &transfer.ID,
+			CreatedBy:   &userID,
+		}
+	}
+	return entries
+}
+
+// scanStockTransfer scans a row selected with stockTransferColumns
+func scanStockTransfer(row pgx.Row) (*entities.StockTransfer, error) {
+	var transfer entities.StockTransfer
+	var status string
+	if err := row.Scan(
+		&transfer.ID,
+		&transfer.SourceWarehouseID,
+		&transfer.DestinationWarehouseID,
+		&status,
+		&transfer.Note,
+		&transfer.CreatedBy,
+		&transfer.ShippedBy,
+		&transfer.ShippedAt,
+		&transfer.ReceivedBy,
+		&transfer.ReceivedAt,
+		&transfer.CreatedAt,
+		&transfer.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	transfer.Status = entities.TransferStatus(status)
+	return &transfer, nil
+}
@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type tagRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewTagRepository creates a new TagRepository instance
+func NewTagRepository(db *pgxpool.Pool) repository.TagRepository {
+	return &tagRepository{
+		db: db,
+	}
+}
+
+// Create inserts a new tag
+func (r *tagRepository) Create(ctx context.Context, tag *entities.Tag) error {
+	query := `
+		INSERT INTO tags (id, name, slug, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+	`
+	_, err := retrying(r.db).Exec(ctx, query, tag.ID, tag.Name, tag.Slug)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return apperror.ErrTagExists
+		}
+		return wrapError(err)
+	}
+	return nil
+}
+
+// GetByID retrieves a tag by its ID
+func (r *tagRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Tag, error) {
+	query := `SELECT id, name, slug, created_at, updated_at FROM tags WHERE id = $1`
+
+	var t entities.Tag
+	err := retrying(r.db).QueryRow(ctx, query, id).Scan(&t.ID, &t.Name, &t.Slug, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrTagNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return &t, nil
+}
+
+// GetBySlugs retrieves the tags with the given slugs
+func (r *tagRepository) GetBySlugs(ctx context.Context, slugs []string) ([]*entities.Tag, error) {
+	query := `SELECT id, name, slug, created_at, updated_at FROM tags WHERE slug = ANY($1) ORDER BY name`
+	return r.list(ctx, query, slugs)
+}
+
+// List retrieves all tags ordered by name
+func (r *tagRepository) List(ctx context.Context) ([]*entities.Tag, error) {
+	query := `SELECT id, name, slug, created_at, updated_at FROM tags ORDER BY name`
+	return r.list(ctx, query)
+}
+
+// list scans tag rows
+func (r *tagRepository) list(ctx context.Context, query string, args ...interface{}) ([]*entities.Tag, error) {
+	rows, err := retrying(r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	tags := make([]*entities.Tag, 0)
+	for rows.Next() {
+		var t entities.Tag
+		if err := rows.Scan(&t.ID, &t.Name, &t.Slug, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, wrapError(err)
+		}
+		tags = append(tags, &t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return tags, nil
+}
+
+// Update updates an existing tag
+func (r *tagRepository) Update(ctx context.Context, tag *entities.Tag) error {
+	query := `UPDATE tags SET name = $1, slug = $2, updated_at = NOW() WHERE id = $3`
+	res, err := retrying(r.db).Exec(ctx, query, tag.Name, tag.Slug, tag.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return apperror.ErrTagExists
+		}
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrTagNotFound
+	}
+	return nil
+}
+
+// Delete removes a tag; product links are removed by the foreign key cascade
+func (r *tagRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := retrying(r.db).Exec(ctx, `DELETE FROM tags WHERE id = $1`, id)
+	if err != nil {
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrTagNotFound
+	}
+	return nil
+}
+
+// SetProductTags replaces all tags of a product
+func (r *tagRepository) SetProductTags(ctx context.Context, productID uuid.UUID, tagIDs []uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM product_tags WHERE product_id = $1`, productID); err != nil {
+		return wrapError(err)
+	}
+
+	if len(tagIDs) > 0 {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO product_tags (product_id, tag_id) SELECT $1, UNNEST($2::uuid[]) ON CONFLICT DO NOTHING`,
+			productID, tagIDs,
+		)
+		if err != nil {
+			if isForeignKeyViolation(err) {
+				return apperror.ErrTagNotFound
+			}
+			return wrapError(err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// ListByProductIDs retrieves the tags of several products in one query
+func (r *tagRepository) ListByProductIDs(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]entities.Tag, error) {
+	result := make(map[uuid.UUID][]entities.Tag, len(productIDs))
+	if len(productIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT pt.product_id, t.id, t.name, t.slug, t.created_at, t.updated_at
+		FROM product_tags pt
+		JOIN tags t ON t.id = pt.tag_id
+		WHERE pt.product_id = ANY($1)
+		ORDER BY pt.product_id, t.name
+	`
+	rows, err := retrying(r.db).Query(ctx, query, productIDs)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID uuid.UUID
+		var t entities.Tag
+		if err := rows.Scan(&productID, &t.ID, &t.Name, &t.Slug, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, wrapError(err)
+		}
+		result[productID] = append(result[productID], t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type trackingTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewTrackingTokenRepository creates a new TrackingTokenRepository instance
+func NewTrackingTokenRepository(db *pgxpool.Pool) repository.TrackingTokenRepository {
+	return &trackingTokenRepository{
+		db: db,
+	}
+}
+
+// Create menyimpan token tracking baru
+func (r *trackingTokenRepository) Create(ctx context.Context, token *entities.TrackingToken) error {
+	query := `INSERT INTO order_tracking_tokens (id, order_id, created_at) VALUES ($1, $2, $3)`
+	if _, err := retrying(r.db).Exec(ctx, query, token.ID, token.OrderID, token.CreatedAt); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// GetByID mengambil token tracking berdasarkan ID
+func (r *trackingTokenRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.TrackingToken, error) {
+	query := `SELECT id, order_id, created_at, revoked_at FROM order_tracking_tokens WHERE id = $1`
+
+	var token entities.TrackingToken
+	err := retrying(r.db).QueryRow(ctx, query, id).Scan(&token.ID, &token.OrderID, &token.CreatedAt, &token.RevokedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrTrackingLinkInvalid
+		}
+		return nil, wrapError(err)
+	}
+	return &token, nil
+}
+
+// RevokeByOrderID mencabut semua token tracking aktif milik order
+func (r *trackingTokenRepository) RevokeByOrderID(ctx context.Context, orderID uuid.UUID) (int64, error) {
+	query := `UPDATE order_tracking_tokens SET revoked_at = NOW() WHERE order_id = $1 AND revoked_at IS NULL`
+
+	res, err := retrying(r.db).Exec(ctx, query, orderID)
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	return res.RowsAffected(), nil
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/pkg/retry"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// txKey is the context key of the transaction started by WithinTx
+type txKey struct{}
+
+// dbConn is what the pool and a transaction have in common
+type dbConn interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+type txManager struct {
+	db *pgxpool.Pool
+}
+
+// NewTxManager untuk membuat instance baru dari TxManager
+func NewTxManager(db *pgxpool.Pool) repository.TxManager {
+	return &txManager{
+		db: db,
+	}
+}
+
+// WithinTx menjalankan fn dalam satu transaksi. Transaksi yang gagal karena serialization
+// failure, deadlock atau koneksi yang putus sebelum query terkirim dijalankan ulang dari awal
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+	return retry.Do(ctx, "postgres", retryPolicy, isRetryable, func() error {
+		return m.run(ctx, fn)
+	})
+}
+
+// WithinTxNoRetry menjalankan fn dalam satu transaksi tanpa menjalankannya ulang, untuk fn
+// yang memanggil hook atau mempublikasikan event di luar database
+func (m *txManager) WithinTxNoRetry(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+	return m.run(ctx, fn)
+}
+
+// run menjalankan fn satu kali dalam transaksi baru
+func (m *txManager) run(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// conn returns the transaction of ctx, or the pool outside of one, where statements
+// failing with a transient error run again. Begin on a transaction starts a savepoint, so
+// a repository that runs its own transaction works either way
+func conn(ctx context.Context, db *pgxpool.Pool) dbConn {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return retrying(db)
+}
@@ -7,6 +7,7 @@ import (
 	"postgresDB/internal/domain/entities"
 	apperror "postgresDB/internal/domain/errors"
 	"postgresDB/internal/domain/repository"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -15,13 +16,16 @@ import (
 
 type userRepository struct {
 	// Add necessary fields here, e.g., database connections
-	db *pgxpool.Pool
+	db       *pgxpool.Pool
+	replicas *Replicas
 }
 
-// NewUserRepository creates a new instance of UserRepository
-func NewUserRepository(db *pgxpool.Pool) repository.UserRepository {
+// NewUserRepository creates a new instance of UserRepository, reads allowed to be stale
+// go to replicas
+func NewUserRepository(db *pgxpool.Pool, replicas *Replicas) repository.UserRepository {
 	return &userRepository{
-		db: db,
+		db:       db,
+		replicas: replicas,
 	}
 }
 
@@ -30,32 +34,50 @@ func (r *userRepository) Create(ctx context.Context, user *entities.User) error
 	query := `
 		INSERT INTO users (id, username, email, password, role, is_active, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, NOW(),NOW())
 		`
-	_, err := r.db.Exec(ctx, query, user.ID, user.Username, user.Email, user.Password, user.Role, user.IsActive)
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, query, user.ID, user.Username, user.Email, user.Password, user.Role, user.IsActive)
 	if err != nil {
 		if isUniqueViolation(err) {
-			return apperror.ErrEmailExists
+			return userConflict(err)
 		}
-		return apperror.WrapInternal(err)
+		return wrapError(err)
+	}
+	if err := insertOutboxEvent(ctx, tx, entities.OutboxAggregateUser, user.ID, entities.OutboxEventUserRegistered, userEvent(user)); err != nil {
+		return err
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	user.Version = 1
 	return nil
 }
 
-// GetByID retrieves a user by their ID
+// GetByID retrieves a user by their ID, unless it's deleted
 func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	return r.GetByIDScoped(ctx, id, repository.DeletedExclude)
+}
 
-	// Implement the logic to get a user by ID from the database
-	query := `SELECT id, username, email, password, role, is_active, created_at, updated_at FROM users WHERE id = $1`
-	row := r.db.QueryRow(ctx, query, id)
+// GetByIDScoped retrieves a user by their ID among the users of scope
+func (r *userRepository) GetByIDScoped(ctx context.Context, id uuid.UUID, scope repository.DeletedScope) (*entities.User, error) {
+	query := `SELECT id, username, email, password, role, is_active, is_guest, created_at, updated_at, version, deleted_at FROM users WHERE id = $1`
+	if cond := deletedCondition(scope); cond != "" {
+		query += " AND " + cond
+	}
 
 	// Scan the result into a User entity
 	var u entities.User
-	err := row.Scan(&u.ID, &u.Username, &u.Email, &u.Password, &u.Role, &u.IsActive, &u.CreatedAt, &u.UpdatedAt)
-	if err != nil {
+	row := readConn(ctx, r.db, r.replicas).QueryRow(ctx, query, id)
+	if err := row.Scan(&u.ID, &u.Username, &u.Email, &u.Password, &u.Role, &u.IsActive, &u.IsGuest, &u.CreatedAt, &u.UpdatedAt, &u.Version, &u.DeletedAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperror.ErrUserNotFound
 		}
-		return nil, apperror.WrapInternal(err)
+		return nil, wrapError(err)
 	}
 	return &u, nil
 }
@@ -64,30 +86,53 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.U
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
 
 	// Implement the logic to get a user by email from the database
-	query := `SELECT id, username, email, password, role, is_active, created_at, updated_at FROM users WHERE email = $1`
-	row := r.db.QueryRow(ctx, query, email)
+	query := `SELECT id, username, email, password, role, is_active, is_guest, created_at, updated_at, version FROM users WHERE LOWER(email) = LOWER($1) AND NOT is_guest AND ` + notDeleted
+	row := readConn(ctx, r.db, r.replicas).QueryRow(ctx, query, email)
 
 	// Scan the result into a User entity
 	var u entities.User
-	err := row.Scan(&u.ID, &u.Username, &u.Email, &u.Password, &u.Role, &u.IsActive, &u.CreatedAt, &u.UpdatedAt)
+	err := row.Scan(&u.ID, &u.Username, &u.Email, &u.Password, &u.Role, &u.IsActive, &u.IsGuest, &u.CreatedAt, &u.UpdatedAt, &u.Version)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperror.ErrUserNotFound
 		}
-		return nil, apperror.WrapInternal(err)
+		return nil, wrapError(err)
 	}
 	return &u, nil
 
 }
 
+// ListActiveByRole retrieves the active users with the given role
+func (r *userRepository) ListActiveByRole(ctx context.Context, role entities.Role) ([]*entities.User, error) {
+	query := `SELECT id, username, email, password, role, is_active, is_guest, created_at, updated_at, version FROM users WHERE role = $1 AND is_active = TRUE AND NOT is_guest AND ` + notDeleted + ` ORDER BY created_at`
+	rows, err := readConn(ctx, r.db, r.replicas).Query(ctx, query, role)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	users := make([]*entities.User, 0)
+	for rows.Next() {
+		var u entities.User
+		if err := rows.Scan(&u.ID, &u.Username, &u.Email, &u.Password, &u.Role, &u.IsActive, &u.IsGuest, &u.CreatedAt, &u.UpdatedAt, &u.Version); err != nil {
+			return nil, wrapError(err)
+		}
+		users = append(users, &u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return users, nil
+}
+
 // GetByUsername retrieves a user by their username
 func (r *userRepository) GetByUsername(ctx context.Context, username string) (*entities.User, error) {
 	// Implement the logic to get a user by username from the database
-	query := `SELECT id, username, email, password, role, is_active, created_at, updated_at FROM users WHERE username = $1`
-	row := r.db.QueryRow(ctx, query, username)
+	query := `SELECT id, username, email, password, role, is_active, is_guest, created_at, updated_at, version FROM users WHERE username = $1 AND NOT is_guest AND ` + notDeleted
+	row := readConn(ctx, r.db, r.replicas).QueryRow(ctx, query, username)
 	// Scan the result into a User entity
 	var u entities.User
-	err := row.Scan(&u.ID, &u.Username, &u.Email, &u.Password, &u.Role, &u.IsActive, &u.CreatedAt, &u.UpdatedAt)
+	err := row.Scan(&u.ID, &u.Username, &u.Email, &u.Password, &u.Role, &u.IsActive, &u.IsGuest, &u.CreatedAt, &u.UpdatedAt, &u.Version)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperror.ErrUserNotFound
@@ -101,16 +146,16 @@ func (r *userRepository) GetByUsername(ctx context.Context, username string) (*e
 // GetByEmailOrUsername retrieves a user by their email or username
 func (r *userRepository) GetByEmailOrUsername(ctx context.Context, loginID string) (*entities.User, error) {
 	query := `
-		SELECT id, username, email, password, role, is_active, created_at, updated_at
-		FROM users 
-		WHERE email = $1 OR username = $1
+		SELECT id, username, email, password, role, is_active, is_guest, created_at, updated_at, version
+		FROM users
+		WHERE (LOWER(email) = LOWER($1) OR username = $1) AND NOT is_guest AND ` + notDeleted + `
 		LIMIT 1
 	`
 
 	var u entities.User
-	err := r.db.QueryRow(ctx, query, loginID).Scan(
+	err := readConn(ctx, r.db, r.replicas).QueryRow(ctx, query, loginID).Scan(
 		&u.ID, &u.Username, &u.Email, &u.Password,
-		&u.Role, &u.IsActive, &u.CreatedAt, &u.UpdatedAt,
+		&u.Role, &u.IsActive, &u.IsGuest, &u.CreatedAt, &u.UpdatedAt, &u.Version,
 	)
 
 	if err != nil {
@@ -125,10 +170,10 @@ func (r *userRepository) GetByEmailOrUsername(ctx context.Context, loginID strin
 // ExistsByEmail
 func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
 	var exists bool
-	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)`
-	err := r.db.QueryRow(ctx, query, email).Scan(&exists)
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE LOWER(email) = LOWER($1) AND NOT is_guest AND ` + notDeleted + `)`
+	err := readConn(ctx, r.db, r.replicas).QueryRow(ctx, query, email).Scan(&exists)
 	if err != nil {
-		return false, apperror.WrapInternal(err)
+		return false, wrapError(err)
 	}
 	return exists, nil
 }
@@ -136,23 +181,58 @@ func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool,
 // ExistsByUsername
 func (r *userRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
 	var exists bool
-	query := `SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)`
-	err := r.db.QueryRow(ctx, query, username).Scan(&exists)
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE username = $1 AND ` + notDeleted + `)`
+	err := readConn(ctx, r.db, r.replicas).QueryRow(ctx, query, username).Scan(&exists)
 	if err != nil {
-		return false, apperror.WrapInternal(err)
+		return false, wrapError(err)
 	}
 	return exists, nil
 }
 
-// UpdateUser updates an existing user in the database
+// UpdateUser updates an existing user in the database as long as its version still matches
+// user.Version, which then holds the new version
 func (r *userRepository) Update(ctx context.Context, user *entities.User) error {
-	query := `UPDATE users SET username = $1, email = $2, role = $3, is_active = $4, updated_at = NOW() WHERE id = $5`
-	res, err := r.db.Exec(ctx, query, user.Username, user.Email, user.Role, user.IsActive, user.ID)
+	query := `UPDATE users SET username = $1, email = $2, role = $3, is_active = $4, version = version + 1, updated_at = NOW() WHERE id = $5 AND version = $6 AND ` + notDeleted + ` RETURNING version`
+	tx, err := conn(ctx, r.db).Begin(ctx)
 	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, query, user.Username, user.Email, user.Role, user.IsActive, user.ID, user.Version).Scan(&user.Version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// the row is either gone or was updated since it was read
+			var exists bool
+			if err := tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1 AND `+notDeleted+`)`, user.ID).Scan(&exists); err != nil {
+				return wrapError(err)
+			}
+			if exists {
+				return apperror.ErrVersionConflict
+			}
+			return apperror.ErrUserNotFound
+		}
 		if isUniqueViolation(err) {
-			return apperror.ErrEmailExists
+			return userConflict(err)
 		}
-		return apperror.WrapInternal(err)
+		return wrapError(err)
+	}
+	if err := insertOutboxEvent(ctx, tx, entities.OutboxAggregateUser, user.ID, entities.OutboxEventUserUpdated, userEvent(user)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// UpdatePassword updates the password hash of a user
+func (r *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, hashedPassword string) error {
+	query := `UPDATE users SET password = $1, updated_at = NOW() WHERE id = $2 AND ` + notDeleted
+	res, err := conn(ctx, r.db).Exec(ctx, query, hashedPassword, id)
+	if err != nil {
+		return wrapError(err)
 	}
 
 	if res.RowsAffected() == 0 {
@@ -161,35 +241,123 @@ func (r *userRepository) Update(ctx context.Context, user *entities.User) error
 	return nil
 }
 
-// DeleteUser removes a user from the database by their ID
+// DeleteUser soft deletes a user by their ID. Its orders and the records it signed stay
 func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM users WHERE id = $1`
-	res, err := r.db.Exec(ctx, query, id)
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE users SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND ` + notDeleted
+	res, err := tx.Exec(ctx, query, id)
 	if err != nil {
-		return apperror.WrapInternal(err)
+		return wrapError(err)
 	}
 	if res.RowsAffected() == 0 {
 		return apperror.ErrUserNotFound
 	}
+	if err := insertOutboxEvent(ctx, tx, entities.OutboxAggregateUser, id, entities.OutboxEventUserDeleted, entities.UserEvent{UserID: id}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return wrapError(err)
+	}
 	return nil
 }
 
-// isUniqueViolation checks if the error is a unique constraint violation
-func isUniqueViolation(err error) bool {
-	if err == nil {
-		return false
+// Restore brings a soft deleted user back. Its username or email may have been taken
+// since, which is reported as a conflict
+func (r *userRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE users SET deleted_at = NULL, updated_at = NOW() WHERE id = $1 AND deleted_at IS NOT NULL`
+	res, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return userConflict(err)
+		}
+		return wrapError(err)
 	}
-	errStr := err.Error()
-	return contains(errStr, "23505") || contains(errStr, "unique")
+
+	if res.RowsAffected() == 0 {
+		return apperror.ErrUserNotFound
+	}
+	return nil
 }
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && searchString(s, substr)
+
+// GetOrCreateGuest mengambil guest dengan email tersebut, atau membuatnya jika belum ada
+func (r *userRepository) GetOrCreateGuest(ctx context.Context, email string) (*entities.User, error) {
+	query := `
+		INSERT INTO users (id, username, email, password, role, is_active, is_guest, created_at, updated_at)
+		VALUES ($1, $2, $3, '', $4, TRUE, TRUE, NOW(), NOW())
+		ON CONFLICT ((LOWER(email))) WHERE is_guest AND deleted_at IS NULL DO UPDATE SET updated_at = NOW()
+		RETURNING id, username, email, password, role, is_active, is_guest, created_at, updated_at, version
+	`
+	id := uuid.New()
+	username := "guest-" + strings.ReplaceAll(id.String(), "-", "")[:16]
+
+	var u entities.User
+	err := conn(ctx, r.db).QueryRow(ctx, query, id, username, email, entities.RoleUser).Scan(
+		&u.ID, &u.Username, &u.Email, &u.Password,
+		&u.Role, &u.IsActive, &u.IsGuest, &u.CreatedAt, &u.UpdatedAt, &u.Version,
+	)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &u, nil
 }
-func searchString(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
+
+// ClaimGuest memindahkan pesanan guest ke user terdaftar lalu menghapus guest tersebut
+func (r *userRepository) ClaimGuest(ctx context.Context, guestID, userID uuid.UUID) (int64, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// the guest is locked first, so concurrent claims move the orders once
+	var locked int
+	err = tx.QueryRow(ctx, `SELECT 1 FROM users WHERE id = $1 AND is_guest AND `+notDeleted+` FOR UPDATE`, guestID).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, apperror.ErrUserNotFound
 		}
+		return 0, wrapError(err)
+	}
+	// orders are moved before the guest is removed, removing it would delete them
+	res, err := tx.Exec(ctx, `UPDATE orders SET customer_id = $1, updated_at = NOW() WHERE customer_id = $2`, userID, guestID)
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM users WHERE id = $1`, guestID); err != nil {
+		return 0, wrapError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, wrapError(err)
+	}
+	return res.RowsAffected(), nil
+}
+
+// userEvent is the outbox data of a registered or updated user, without the password
+func userEvent(user *entities.User) entities.UserEvent {
+	return entities.UserEvent{
+		UserID:   user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     user.Role,
+		IsActive: &user.IsActive,
+	}
+}
+
+// userConflict maps a unique violation on users to the conflicting field
+func userConflict(err error) error {
+	switch uniqueConstraint(err) {
+	case "users_username_key":
+		return apperror.ErrUsernameExists
+	case "users_email_lower_key", "users_guest_email_lower_key":
+		return apperror.ErrEmailExists
+	default:
+		return apperror.ErrUserAlreadyExists
 	}
-	return false
 }
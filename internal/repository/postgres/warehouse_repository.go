@@ -0,0 +1,296 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const stockLedgerColumns = `id, warehouse_id, product_id, quantity, balance, reason, reference_id, note, created_by, created_at`
+
+type warehouseRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewWarehouseRepository untuk membuat instance baru dari WarehouseRepository
+func NewWarehouseRepository(db *pgxpool.Pool) repository.WarehouseRepository {
+	return &warehouseRepository{
+		db: db,
+	}
+}
+
+// Create menyimpan gudang baru
+func (r *warehouseRepository) Create(ctx context.Context, warehouse *entities.Warehouse) error {
+	query := `
+		INSERT INTO warehouses (id, code, name, address, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+	err := retrying(r.db).QueryRow(ctx, query, warehouse.ID, warehouse.Code, warehouse.Name, warehouse.Address).Scan(&warehouse.CreatedAt, &warehouse.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return apperror.ErrWarehouseCodeExists
+		}
+		return wrapError(err)
+	}
+	return nil
+}
+
+// GetByID mengambil gudang berdasarkan ID
+func (r *warehouseRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Warehouse, error) {
+	query := `SELECT id, code, name, address, created_at, updated_at FROM warehouses WHERE id = $1`
+
+	var w entities.Warehouse
+	err := retrying(r.db).QueryRow(ctx, query, id).Scan(&w.ID, &w.Code, &w.Name, &w.Address, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrWarehouseNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return &w, nil
+}
+
+// List mengambil semua gudang berdasarkan kode
+func (r *warehouseRepository) List(ctx context.Context) ([]*entities.Warehouse, error) {
+	rows, err := retrying(r.db).Query(ctx, `SELECT id, code, name, address, created_at, updated_at FROM warehouses ORDER BY code`)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	warehouses := make([]*entities.Warehouse, 0)
+	for rows.Next() {
+		var w entities.Warehouse
+		if err := rows.Scan(&w.ID, &w.Code, &w.Name, &w.Address, &w.CreatedAt, &w.UpdatedAt); err != nil {
+			return nil, wrapError(err)
+		}
+		warehouses = append(warehouses, &w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return warehouses, nil
+}
+
+// ListStock mengambil stok produk di sebuah gudang berdasarkan nama produk
+func (r *warehouseRepository) ListStock(ctx context.Context, warehouseID uuid.UUID, limit, offset int) ([]*entities.WarehouseStock, int64, error) {
+	var total int64
+	if err := retrying(r.db).QueryRow(ctx, `SELECT COUNT(*) FROM warehouse_stock WHERE warehouse_id = $1`, warehouseID).Scan(&total); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	query := `
+		SELECT ws.warehouse_id, ws.product_id, p.name, COALESCE(p.sku, ''), ws.quantity, ws.updated_at
+		FROM warehouse_stock ws
+		JOIN products p ON p.id = ws.product_id
+		WHERE ws.warehouse_id = $1
+		ORDER BY p.name, ws.product_id
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := retrying(r.db).Query(ctx, query, warehouseID, limit, offset)
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+	defer rows.Close()
+
+	stock := make([]*entities.WarehouseStock, 0, limit)
+	for rows.Next() {
+		var s entities.WarehouseStock
+		if err := rows.Scan(&s.WarehouseID, &s.ProductID, &s.ProductName, &s.SKU, &s.Quantity, &s.UpdatedAt); err != nil {
+			return nil, 0, wrapError(err)
+		}
+		stock = append(stock, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, wrapError(err)
+	}
+	return stock, total, nil
+}
+
+// GetStock mengambil stok beberapa produk di sebuah gudang dalam satu query
+func (r *warehouseRepository) GetStock(ctx context.Context, warehouseID uuid.UUID, productIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	stock := make(map[uuid.UUID]int, len(productIDs))
+	if len(productIDs) == 0 {
+		return stock, nil
+	}
+
+	rows, err := retrying(r.db).Query(ctx,
+		`SELECT product_id, quantity FROM warehouse_stock WHERE warehouse_id = $1 AND product_id = ANY($2)`,
+		warehouseID, productIDs,
+	)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID uuid.UUID
+		var quantity int
+		if err := rows.Scan(&productID, &quantity); err != nil {
+			return nil, wrapError(err)
+		}
+		stock[productID] = quantity
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return stock, nil
+}
+
+// Post membukukan mutasi stok ke ledger dalam satu transaksi
+func (r *warehouseRepository) Post(ctx context.Context, entries []entities.StockLedgerEntry) ([]*entities.StockLedgerEntry, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	posted, err := postStockLedger(ctx, tx, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, wrapError(err)
+	}
+	return posted, nil
+}
+
+// ListLedger mengambil entri ledger sesuai filter, terbaru lebih dulu
+func (r *warehouseRepository) ListLedger(ctx context.Context, filter entities.StockLedgerFilter, limit, offset int) ([]*entities.StockLedgerEntry, int64, error) {
+	where := ` WHERE TRUE`
+	args := []any{}
+	if filter.WarehouseID != nil {
+		args = append(args, *filter.WarehouseID)
+		where += fmt.Sprintf(" AND warehouse_id = $%d", len(args))
+	}
+	if filter.ProductID != nil {
+		args = append(args, *filter.ProductID)
+		where += fmt.Sprintf(" AND product_id = $%d", len(args))
+	}
+	if filter.ReferenceID != nil {
+		args = append(args, *filter.ReferenceID)
+		where += fmt.Sprintf(" AND reference_id = $%d", len(args))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		where += fmt.Sprintf(" AND created_at >= $%d", len(args))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		where += fmt.Sprintf(" AND created_at < $%d", len(args))
+	}
+
+	var total int64
+	if err := retrying(r.db).QueryRow(ctx, `SELECT COUNT(*) FROM stock_ledger`+where, args...).Scan(&total); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	query := `SELECT ` + stockLedgerColumns + ` FROM stock_ledger` + where +
+		fmt.Sprintf(` ORDER BY created_at DESC, id LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	rows, err := retrying(r.db).Query(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+	defer rows.Close()
+
+	entries := make([]*entities.StockLedgerEntry, 0, limit)
+	for rows.Next() {
+		entry, err := scanStockLedgerEntry(rows)
+		if err != nil {
+			return nil, 0, wrapError(err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, wrapError(err)
+	}
+	return entries, total, nil
+}
+
+// postStockLedger applies the movements to the warehouse stock within tx and records them
+// in the ledger. Rows are locked in warehouse and product order so concurrent posts
+// don't deadlock. A movement that would take the stock below zero fails with
+// ErrInsufficientWarehouseStock
+func postStockLedger(ctx context.Context, tx pgx.Tx, entries []entities.StockLedgerEntry) ([]*entities.StockLedgerEntry, error) {
+	sorted := slices.Clone(entries)
+	slices.SortStableFunc(sorted, func(a, b entities.StockLedgerEntry) int {
+		if c := bytes.Compare(a.WarehouseID[:], b.WarehouseID[:]); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.ProductID[:], b.ProductID[:])
+	})
+
+	take := `
+		UPDATE warehouse_stock SET quantity = quantity + $3, updated_at = NOW()
+		WHERE warehouse_id = $1 AND product_id = $2 AND quantity + $3 >= 0
+		RETURNING quantity
+	`
+	put := `
+		INSERT INTO warehouse_stock (warehouse_id, product_id, quantity, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (warehouse_id, product_id) DO UPDATE SET quantity = warehouse_stock.quantity + EXCLUDED.quantity, updated_at = NOW()
+		RETURNING quantity
+	`
+	record := `
+		INSERT INTO stock_ledger (warehouse_id, product_id, quantity, balance, reason, reference_id, note, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		RETURNING id, created_at
+	`
+
+	posted := make([]*entities.StockLedgerEntry, 0, len(sorted))
+	for _, entry := range sorted {
+		query := put
+		if entry.Quantity < 0 {
+			query = take
+		}
+		if err := tx.QueryRow(ctx, query, entry.WarehouseID, entry.ProductID, entry.Quantity).Scan(&entry.Balance); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, apperror.ErrInsufficientWarehouseStock
+			}
+			return nil, wrapError(err)
+		}
+
+		err := tx.QueryRow(ctx, record,
+			entry.WarehouseID, entry.ProductID, entry.Quantity, entry.Balance, string(entry.Reason),
+			entry.ReferenceID, entry.Note, entry.CreatedBy,
+		).Scan(&entry.ID, &entry.CreatedAt)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		posted = append(posted, &entry)
+	}
+	return posted, nil
+}
+
+// scanStockLedgerEntry scans a row selected with stockLedgerColumns
+func scanStockLedgerEntry(row pgx.Row) (*entities.StockLedgerEntry, error) {
+	var entry entities.StockLedgerEntry
+	var reason string
+	if err := row.Scan(
+		&entry.ID,
+		&entry.WarehouseID,
+		&entry.ProductID,
+		&entry.Quantity,
+		&entry.Balance,
+		&reason,
+		&entry.ReferenceID,
+		&entry.Note,
+		&entry.CreatedBy,
+		&entry.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	entry.Reason = entities.LedgerReason(reason)
+	return &entry, nil
+}
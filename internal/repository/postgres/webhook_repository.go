@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// webhookEndpointColumns is the column list scanned by scanWebhookEndpoint
+const webhookEndpointColumns = `id, url, description, secret, events, active, created_by, created_at, updated_at`
+
+type webhookRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewWebhookRepository untuk membuat instance baru dari WebhookRepository
+func NewWebhookRepository(db *pgxpool.Pool) repository.WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+// Create menyimpan endpoint webhook baru
+func (r *webhookRepository) Create(ctx context.Context, endpoint *entities.WebhookEndpoint) error {
+	query := `
+		INSERT INTO webhook_endpoints (id, url, description, secret, events, active, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := retrying(r.db).Exec(ctx, query, endpoint.ID, endpoint.URL, endpoint.Description, endpoint.Secret, endpoint.Events,
+		endpoint.Active, endpoint.CreatedBy, endpoint.CreatedAt, endpoint.UpdatedAt)
+	if err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// GetByID mengambil endpoint webhook berdasarkan ID
+func (r *webhookRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.WebhookEndpoint, error) {
+	endpoint, err := scanWebhookEndpoint(retrying(r.db).QueryRow(ctx, `SELECT `+webhookEndpointColumns+` FROM webhook_endpoints WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrWebhookNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return endpoint, nil
+}
+
+// List mengambil daftar endpoint webhook terbaru lebih dulu
+func (r *webhookRepository) List(ctx context.Context, limit, offset int) ([]*entities.WebhookEndpoint, int64, error) {
+	var total int64
+	if err := retrying(r.db).QueryRow(ctx, `SELECT COUNT(*) FROM webhook_endpoints`).Scan(&total); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	endpoints, err := r.query(ctx, `SELECT `+webhookEndpointColumns+` FROM webhook_endpoints ORDER BY created_at DESC LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return endpoints, total, nil
+}
+
+// ListSubscribed mengambil endpoint aktif yang berlangganan sebuah event
+func (r *webhookRepository) ListSubscribed(ctx context.Context, event string) ([]*entities.WebhookEndpoint, error) {
+	return r.query(ctx, `SELECT `+webhookEndpointColumns+` FROM webhook_endpoints WHERE active AND $1 = ANY(events) ORDER BY created_at`, event)
+}
+
+func (r *webhookRepository) query(ctx context.Context, query string, args ...any) ([]*entities.WebhookEndpoint, error) {
+	rows, err := retrying(r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	endpoints := make([]*entities.WebhookEndpoint, 0)
+	for rows.Next() {
+		endpoint, err := scanWebhookEndpoint(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return endpoints, nil
+}
+
+// Update menyimpan URL, deskripsi, event dan status aktif endpoint webhook
+func (r *webhookRepository) Update(ctx context.Context, endpoint *entities.WebhookEndpoint) error {
+	res, err := retrying(r.db).Exec(ctx,
+		`UPDATE webhook_endpoints SET url = $1, description = $2, events = $3, active = $4, updated_at = $5 WHERE id = $6`,
+		endpoint.URL, endpoint.Description, endpoint.Events, endpoint.Active, endpoint.UpdatedAt, endpoint.ID,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// Delete menghapus endpoint webhook beserta log pengirimannya
+func (r *webhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := retrying(r.db).Exec(ctx, `DELETE FROM webhook_endpoints WHERE id = $1`, id)
+	if err != nil {
+		return wrapError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return apperror.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// RecordDelivery mencatat satu percobaan pengiriman webhook beserta nomor percobaannya
+func (r *webhookRepository) RecordDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	err := retrying(r.db).QueryRow(ctx, `
+		INSERT INTO webhook_deliveries (id, endpoint_id, event_id, event_type, attempt, payload, status_code, response_body, error, duration_ms, succeeded, created_at)
+		VALUES ($1, $2, $3, $4, (SELECT COUNT(*) + 1 FROM webhook_deliveries WHERE endpoint_id = $2 AND event_id = $3), $5, $6, $7, $8, $9, $10, $11)
+		RETURNING attempt`,
+		delivery.ID, delivery.EndpointID, delivery.EventID, delivery.EventType, delivery.Payload, delivery.StatusCode,
+		delivery.ResponseBody, delivery.Error, delivery.DurationMs, delivery.Succeeded, delivery.CreatedAt,
+	).Scan(&delivery.Attempt)
+	if err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// ListDeliveries mengambil log pengiriman sebuah endpoint, terbaru lebih dulu
+func (r *webhookRepository) ListDeliveries(ctx context.Context, endpointID uuid.UUID, limit, offset int) ([]*entities.WebhookDelivery, int64, error) {
+	var total int64
+	if err := retrying(r.db).QueryRow(ctx, `SELECT COUNT(*) FROM webhook_deliveries WHERE endpoint_id = $1`, endpointID).Scan(&total); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	rows, err := retrying(r.db).Query(ctx, `
+		SELECT id, endpoint_id, event_id, event_type, attempt, payload, status_code, response_body, error, duration_ms, succeeded, created_at
+		FROM webhook_deliveries WHERE endpoint_id = $1 ORDER BY created_at DESC, id LIMIT $2 OFFSET $3`,
+		endpointID, limit, offset)
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*entities.WebhookDelivery, 0, limit)
+	for rows.Next() {
+		var d entities.WebhookDelivery
+		if err := rows.Scan(&d.ID, &d.EndpointID, &d.EventID, &d.EventType, &d.Attempt, &d.Payload, &d.StatusCode,
+			&d.ResponseBody, &d.Error, &d.DurationMs, &d.Succeeded, &d.CreatedAt); err != nil {
+			return nil, 0, wrapError(err)
+		}
+		deliveries = append(deliveries, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, wrapError(err)
+	}
+	return deliveries, total, nil
+}
+
+// scanWebhookEndpoint scans a single endpoint row selected with webhookEndpointColumns
+func scanWebhookEndpoint(row pgx.Row) (*entities.WebhookEndpoint, error) {
+	var e entities.WebhookEndpoint
+	err := row.Scan(&e.ID, &e.URL, &e.Description, &e.Secret, &e.Events, &e.Active, &e.CreatedBy, &e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
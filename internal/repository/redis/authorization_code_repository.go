@@ -0,0 +1,52 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/repository"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const authorizationCodePrefix = "oauth:code:"
+
+// authorizationCodeRepository implements repository.AuthorizationCodeRepository
+type authorizationCodeRepository struct {
+	client *redis.Client
+}
+
+// NewAuthorizationCodeRepository creates a new authorization code repository
+func NewAuthorizationCodeRepository(client *redis.Client) repository.AuthorizationCodeRepository {
+	return &authorizationCodeRepository{client: client}
+}
+
+// Save stores the grant as JSON, expiring with the code
+func (r *authorizationCodeRepository) Save(ctx context.Context, codeHash string, code *entities.AuthorizationCode, ttl time.Duration) error {
+	data, err := json.Marshal(code)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(ctx, authorizationCodePrefix+codeHash, data, ttl).Err()
+}
+
+// Consume reads and deletes the grant with GETDEL, two concurrent redemptions of the
+// same code can't both get it
+func (r *authorizationCodeRepository) Consume(ctx context.Context, codeHash string) (*entities.AuthorizationCode, error) {
+	data, err := r.client.GetDel(ctx, authorizationCodePrefix+codeHash).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var code entities.AuthorizationCode
+	if err := json.Unmarshal(data, &code); err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
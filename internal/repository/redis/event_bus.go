@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"context"
+
+	"postgresDB/internal/repository"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const eventChannelPrefix = "events:"
+
+type eventBus struct {
+	client *redis.Client
+}
+
+// NewEventBus creates a new EventBus on Redis pub/sub
+func NewEventBus(client *redis.Client) repository.EventBus {
+	return &eventBus{client: client}
+}
+
+func (b *eventBus) Publish(ctx context.Context, topic string, payload []byte) error {
+	return b.client.Publish(ctx, eventChannelPrefix+topic, payload).Err()
+}
+
+// Subscribe waits for Redis to confirm the subscription, so events published after it
+// returns are delivered. go-redis resubscribes by itself when the connection drops
+func (b *eventBus) Subscribe(ctx context.Context, topic string) (<-chan []byte, error) {
+	pubsub := b.client.Subscribe(ctx, eventChannelPrefix+topic)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	events := make(chan []byte)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				select {
+				case events <- []byte(msg.Payload):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/repository"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const idempotencyPrefix = "idempotency:"
+
+// idempotencyRepository implements repository.IdempotencyRepository
+type idempotencyRepository struct {
+	client *redis.Client
+}
+
+// NewIdempotencyRepository creates a new idempotency key repository
+func NewIdempotencyRepository(client *redis.Client) repository.IdempotencyRepository {
+	return &idempotencyRepository{client: client}
+}
+
+// Claim stores a running request with SET NX, or reads the request holding the key
+func (r *idempotencyRepository) Claim(ctx context.Context, key, fingerprint string, ttl time.Duration) (*entities.IdempotentRequest, bool, error) {
+	data, err := json.Marshal(entities.IdempotentRequest{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, false, err
+	}
+	claimed, err := r.client.SetNX(ctx, idempotencyPrefix+key, data, ttl).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if claimed {
+		return nil, true, nil
+	}
+
+	stored, err := r.client.Get(ctx, idempotencyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// released or expired since SET NX, claim it again
+		return r.Claim(ctx, key, fingerprint, ttl)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var request entities.IdempotentRequest
+	if err := json.Unmarshal(stored, &request); err != nil {
+		return nil, false, err
+	}
+	return &request, false, nil
+}
+
+// Complete overwrites the running request with its response
+func (r *idempotencyRepository) Complete(ctx context.Context, key string, request *entities.IdempotentRequest, ttl time.Duration) error {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(ctx, idempotencyPrefix+key, data, ttl).Err()
+}
+
+// Release deletes the key
+func (r *idempotencyRepository) Release(ctx context.Context, key string) error {
+	return r.client.Del(ctx, idempotencyPrefix+key).Err()
+}
@@ -0,0 +1,368 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	jobPrefix        = "jobs:job:"
+	jobReadyPrefix   = "jobs:ready:"
+	jobRunningPrefix = "jobs:running:"
+	jobFailedPrefix  = "jobs:failed:"
+	jobStatsPrefix   = "jobs:stats:"
+	jobQueuesKey     = "jobs:queues"
+
+	// jobStatsTTL keeps per-minute stats long enough for the widest stats window
+	jobStatsTTL = 25 * time.Hour
+	// maxJobStatsWindow is the widest window stats can be asked for
+	maxJobStatsWindow = 24 * time.Hour
+)
+
+// dequeueScript moves the first due job from the ready to the running set and marks it running
+var dequeueScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, 1)
+if #ids == 0 then
+	return false
+end
+redis.call('ZREM', KEYS[1], ids[1])
+redis.call('ZADD', KEYS[2], ARGV[1], ids[1])
+redis.call('HSET', ARGV[2] .. ids[1], 'status', 'running', 'started_at', ARGV[1], 'updated_at', ARGV[1])
+return ids[1]
+`)
+
+// requeueStaleScript moves running jobs claimed before the cutoff back to the ready set
+var requeueStaleScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+for _, id in ipairs(ids) do
+	redis.call('ZREM', KEYS[1], id)
+	redis.call('ZADD', KEYS[2], ARGV[2], id)
+	redis.call('HSET', ARGV[3] .. id, 'status', 'queued', 'run_at', ARGV[2], 'updated_at', ARGV[2])
+end
+return #ids
+`)
+
+// retryScript moves a failed job back to the ready set. Returns 0 when the job doesn't
+// exist, 1 when it isn't failed and 2 on success
+var retryScript = redis.NewScript(`
+local key = ARGV[1] .. ARGV[2]
+local job = redis.call('HMGET', key, 'status', 'queue')
+if not job[1] then
+	return 0
+end
+if job[1] ~= 'failed' then
+	return 1
+end
+redis.call('ZREM', ARGV[3] .. job[2], ARGV[2])
+redis.call('ZADD', ARGV[4] .. job[2], ARGV[5], ARGV[2])
+redis.call('HSET', key, 'status', 'queued', 'attempts', 0, 'run_at', ARGV[5], 'updated_at', ARGV[5], 'finished_at', '')
+redis.call('PERSIST', key)
+return 2
+`)
+
+// cancelScript drops a queued or failed job. Returns 0 when the job doesn't exist, 1
+// when it's in another state and 2 on success
+var cancelScript = redis.NewScript(`
+local key = ARGV[1] .. ARGV[2]
+local job = redis.call('HMGET', key, 'status', 'queue')
+if not job[1] then
+	return 0
+end
+if job[1] == 'queued' then
+	redis.call('ZREM', ARGV[3] .. job[2], ARGV[2])
+elseif job[1] == 'failed' then
+	redis.call('ZREM', ARGV[4] .. job[2], ARGV[2])
+else
+	return 1
+end
+redis.call('HSET', key, 'status', 'cancelled', 'updated_at', ARGV[5], 'finished_at', ARGV[5])
+redis.call('PEXPIRE', key, ARGV[6])
+return 2
+`)
+
+// jobRepository implements repository.JobRepository
+type jobRepository struct {
+	client    *redis.Client
+	retention time.Duration
+}
+
+// NewJobRepository creates a new job repository. Finished jobs are kept for retention,
+// failed jobs are kept until an admin retries or cancels them
+func NewJobRepository(client *redis.Client, retention time.Duration) repository.JobRepository {
+	return &jobRepository{
+		client:    client,
+		retention: retention,
+	}
+}
+
+// Enqueue stores the job hash and adds it to the ready set of its queue
+func (r *jobRepository) Enqueue(ctx context.Context, job *entities.Job) error {
+	pipe := r.client.TxPipeline()
+	pipe.HSet(ctx, jobPrefix+job.ID.String(), map[string]any{
+		"id":           job.ID.String(),
+		"queue":        job.Queue,
+		"type":         job.Type,
+		"payload":      string(job.Payload),
+		"status":       string(job.Status),
+		"attempts":     job.Attempts,
+		"max_attempts": job.MaxAttempts,
+		"last_error":   job.LastError,
+		"run_at":       job.RunAt.UnixMilli(),
+		"created_at":   job.CreatedAt.UnixMilli(),
+		"updated_at":   job.UpdatedAt.UnixMilli(),
+	})
+	pipe.ZAdd(ctx, jobReadyPrefix+job.Queue, redis.Z{Score: float64(job.RunAt.UnixMilli()), Member: job.ID.String()})
+	pipe.SAdd(ctx, jobQueuesKey, job.Queue)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Dequeue claims the first due job of queue
+func (r *jobRepository) Dequeue(ctx context.Context, queue string, now time.Time) (*entities.Job, error) {
+	id, err := dequeueScript.Run(ctx, r.client,
+		[]string{jobReadyPrefix + queue, jobRunningPrefix + queue},
+		now.UnixMilli(), jobPrefix,
+	).Text()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.load(ctx, id)
+}
+
+// Get returns a job by ID
+func (r *jobRepository) Get(ctx context.Context, id uuid.UUID) (*entities.Job, error) {
+	return r.load(ctx, id.String())
+}
+
+// Complete marks the job succeeded and expires it after the retention
+func (r *jobRepository) Complete(ctx context.Context, job *entities.Job, wait, run time.Duration) error {
+	now := time.Now()
+	key := jobPrefix + job.ID.String()
+
+	pipe := r.client.TxPipeline()
+	pipe.ZRem(ctx, jobRunningPrefix+job.Queue, job.ID.String())
+	pipe.HSet(ctx, key, "status", string(entities.JobStatusSucceeded), "attempts", job.Attempts,
+		"last_error", job.LastError, "updated_at", now.UnixMilli(), "finished_at", now.UnixMilli())
+	pipe.PExpire(ctx, key, r.retention)
+	r.recordStats(ctx, pipe, job.Queue, now, "succeeded", wait, run)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Reschedule puts the job back in the ready set at job.RunAt
+func (r *jobRepository) Reschedule(ctx context.Context, job *entities.Job, wait, run time.Duration) error {
+	now := time.Now()
+
+	pipe := r.client.TxPipeline()
+	pipe.ZRem(ctx, jobRunningPrefix+job.Queue, job.ID.String())
+	pipe.HSet(ctx, jobPrefix+job.ID.String(), "status", string(entities.JobStatusQueued), "attempts", job.Attempts,
+		"last_error", job.LastError, "run_at", job.RunAt.UnixMilli(), "updated_at", now.UnixMilli())
+	pipe.ZAdd(ctx, jobReadyPrefix+job.Queue, redis.Z{Score: float64(job.RunAt.UnixMilli()), Member: job.ID.String()})
+	r.recordStats(ctx, pipe, job.Queue, now, "errored", wait, run)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Fail moves the job to the failed set of its queue
+func (r *jobRepository) Fail(ctx context.Context, job *entities.Job, wait, run time.Duration) error {
+	now := time.Now()
+
+	pipe := r.client.TxPipeline()
+	pipe.ZRem(ctx, jobRunningPrefix+job.Queue, job.ID.String())
+	pipe.HSet(ctx, jobPrefix+job.ID.String(), "status", string(entities.JobStatusFailed), "attempts", job.Attempts,
+		"last_error", job.LastError, "updated_at", now.UnixMilli(), "finished_at", now.UnixMilli())
+	pipe.ZAdd(ctx, jobFailedPrefix+job.Queue, redis.Z{Score: float64(now.UnixMilli()), Member: job.ID.String()})
+	r.recordStats(ctx, pipe, job.Queue, now, "errored", wait, run)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// RequeueStale puts jobs that have been running since before cutoff back in the ready set
+func (r *jobRepository) RequeueStale(ctx context.Context, queue string, cutoff time.Time) (int, error) {
+	return requeueStaleScript.Run(ctx, r.client,
+		[]string{jobRunningPrefix + queue, jobReadyPrefix + queue},
+		cutoff.UnixMilli(), time.Now().UnixMilli(), jobPrefix,
+	).Int()
+}
+
+// ListFailed returns the failed jobs of queue, most recently failed first
+func (r *jobRepository) ListFailed(ctx context.Context, queue string, limit, offset int) ([]*entities.Job, int64, error) {
+	key := jobFailedPrefix + queue
+	total, err := r.client.ZCard(ctx, key).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	ids, err := r.client.ZRevRange(ctx, key, int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	jobs := make([]*entities.Job, 0, len(ids))
+	for _, id := range ids {
+		job, err := r.load(ctx, id)
+		if errors.Is(err, apperror.ErrJobNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, total, nil
+}
+
+// Retry moves a failed job back to its queue, due right away
+func (r *jobRepository) Retry(ctx context.Context, id uuid.UUID) (*entities.Job, error) {
+	res, err := retryScript.Run(ctx, r.client, nil,
+		jobPrefix, id.String(), jobFailedPrefix, jobReadyPrefix, time.Now().UnixMilli(),
+	).Int()
+	if err != nil {
+		return nil, err
+	}
+	if err := scriptResult(res); err != nil {
+		return nil, err
+	}
+	return r.load(ctx, id.String())
+}
+
+// Cancel drops a queued or failed job and expires it after the retention
+func (r *jobRepository) Cancel(ctx context.Context, id uuid.UUID) (*entities.Job, error) {
+	res, err := cancelScript.Run(ctx, r.client, nil,
+		jobPrefix, id.String(), jobReadyPrefix, jobFailedPrefix, time.Now().UnixMilli(), r.retention.Milliseconds(),
+	).Int()
+	if err != nil {
+		return nil, err
+	}
+	if err := scriptResult(res); err != nil {
+		return nil, err
+	}
+	return r.load(ctx, id.String())
+}
+
+// Queues returns the known queue names
+func (r *jobRepository) Queues(ctx context.Context) ([]string, error) {
+	return r.client.SMembers(ctx, jobQueuesKey).Result()
+}
+
+// Stats returns the backlog of queue and sums its per-minute counters over the window
+func (r *jobRepository) Stats(ctx context.Context, queue string, window time.Duration) (*entities.QueueStats, error) {
+	window = min(max(window, time.Minute), maxJobStatsWindow)
+	now := time.Now()
+	nowScore := strconv.FormatInt(now.UnixMilli(), 10)
+
+	pipe := r.client.Pipeline()
+	ready := pipe.ZCount(ctx, jobReadyPrefix+queue, "-inf", nowScore)
+	scheduled := pipe.ZCount(ctx, jobReadyPrefix+queue, "("+nowScore, "+inf")
+	running := pipe.ZCard(ctx, jobRunningPrefix+queue)
+	failed := pipe.ZCard(ctx, jobFailedPrefix+queue)
+	minutes := int(window / time.Minute)
+	buckets := make([]*redis.MapStringStringCmd, minutes)
+	for i := range buckets {
+		buckets[i] = pipe.HGetAll(ctx, jobStatsKey(queue, now.Add(-time.Duration(i)*time.Minute)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	stats := &entities.QueueStats{
+		Queue:     queue,
+		Ready:     ready.Val(),
+		Scheduled: scheduled.Val(),
+		Running:   running.Val(),
+		Failed:    failed.Val(),
+		Window:    window,
+	}
+	for _, bucket := range buckets {
+		values := bucket.Val()
+		stats.Succeeded += parseInt64(values["succeeded"])
+		stats.Errored += parseInt64(values["errored"])
+		stats.TotalWait += time.Duration(parseInt64(values["wait_ms"])) * time.Millisecond
+		stats.TotalRun += time.Duration(parseInt64(values["run_ms"])) * time.Millisecond
+	}
+	return stats, nil
+}
+
+// recordStats adds a finished attempt to the per-minute counters of queue
+func (r *jobRepository) recordStats(ctx context.Context, pipe redis.Pipeliner, queue string, now time.Time, outcome string, wait, run time.Duration) {
+	key := jobStatsKey(queue, now)
+	pipe.HIncrBy(ctx, key, outcome, 1)
+	pipe.HIncrBy(ctx, key, "wait_ms", wait.Milliseconds())
+	pipe.HIncrBy(ctx, key, "run_ms", run.Milliseconds())
+	pipe.Expire(ctx, key, jobStatsTTL)
+}
+
+// load reads a job hash
+func (r *jobRepository) load(ctx context.Context, id string) (*entities.Job, error) {
+	values, err := r.client.HGetAll(ctx, jobPrefix+id).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, apperror.ErrJobNotFound
+	}
+
+	jobID, err := uuid.Parse(values["id"])
+	if err != nil {
+		return nil, fmt.Errorf("corrupt job %s: %w", id, err)
+	}
+	job := &entities.Job{
+		ID:          jobID,
+		Queue:       values["queue"],
+		Type:        values["type"],
+		Payload:     json.RawMessage(values["payload"]),
+		Status:      entities.JobStatus(values["status"]),
+		Attempts:    int(parseInt64(values["attempts"])),
+		MaxAttempts: int(parseInt64(values["max_attempts"])),
+		LastError:   values["last_error"],
+		RunAt:       time.UnixMilli(parseInt64(values["run_at"])),
+		CreatedAt:   time.UnixMilli(parseInt64(values["created_at"])),
+		UpdatedAt:   time.UnixMilli(parseInt64(values["updated_at"])),
+		StartedAt:   parseMilli(values["started_at"]),
+		FinishedAt:  parseMilli(values["finished_at"]),
+	}
+	return job, nil
+}
+
+// scriptResult maps the result code of retryScript and cancelScript to an error
+func scriptResult(res int) error {
+	switch res {
+	case 0:
+		return apperror.ErrJobNotFound
+	case 1:
+		return apperror.ErrJobStateConflict
+	}
+	return nil
+}
+
+// jobStatsKey is the per-minute stats hash of queue
+func jobStatsKey(queue string, at time.Time) string {
+	return fmt.Sprintf("%s%s:%d", jobStatsPrefix, queue, at.Unix()/60)
+}
+
+func parseInt64(s string) int64 {
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
+
+// parseMilli parses an optional unix millisecond timestamp
+func parseMilli(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	t := time.UnixMilli(parseInt64(s))
+	return &t
+}
@@ -0,0 +1,27 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"postgresDB/internal/repository"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const lockPrefix = "lock:"
+
+// lockRepository implements repository.LockRepository
+type lockRepository struct {
+	client *redis.Client
+}
+
+// NewLockRepository creates a new lock repository
+func NewLockRepository(client *redis.Client) repository.LockRepository {
+	return &lockRepository{client: client}
+}
+
+// TryLock stores the owner with SET NX, the lock is released when the key expires
+func (r *lockRepository) TryLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, lockPrefix+name, owner, ttl).Result()
+}
@@ -0,0 +1,27 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"postgresDB/internal/repository"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const noncePrefix = "replay:nonce:"
+
+// nonceRepository implements repository.NonceRepository
+type nonceRepository struct {
+	client *redis.Client
+}
+
+// NewNonceRepository creates a new nonce repository
+func NewNonceRepository(client *redis.Client) repository.NonceRepository {
+	return &nonceRepository{client: client}
+}
+
+// Use stores the nonce with SET NX, only the first request with a nonce gets true
+func (r *nonceRepository) Use(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, noncePrefix+key, "1", ttl).Result()
+}
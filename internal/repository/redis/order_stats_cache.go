@@ -0,0 +1,65 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	orderStatsCachePrefix = "orders:stats:"
+	// orderStatsCacheFormat is part of every entry key and is raised when the cached
+	// statistics change shape
+	orderStatsCacheFormat = "f1"
+)
+
+// orderStatsCache decorates an OrderRepository with a Redis cache of the customer
+// statistics. Entries aren't invalidated by order writes, they live until the TTL runs
+// out; Redis errors fall through to the wrapped repository
+type orderStatsCache struct {
+	repository.OrderRepository
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewOrderStatsCache wraps an order repository with a Redis cache of CustomerStats
+func NewOrderStatsCache(next repository.OrderRepository, client *redis.Client, ttl time.Duration) repository.OrderRepository {
+	return &orderStatsCache{OrderRepository: next, client: client, ttl: ttl}
+}
+
+// CustomerStats returns the cached statistics, reading them through on a miss
+func (c *orderStatsCache) CustomerStats(ctx context.Context, customerID uuid.UUID, categories int) (*entities.CustomerOrderStats, error) {
+	key := fmt.Sprintf("%s%s:%s:%d", orderStatsCachePrefix, orderStatsCacheFormat, customerID, categories)
+
+	cached, err := c.client.Get(ctx, key).Bytes()
+	if err == nil {
+		var stats entities.CustomerOrderStats
+		if json.Unmarshal(cached, &stats) == nil {
+			return &stats, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		logger.WarnContext(ctx, "order stats cache unavailable", "error", err)
+	}
+
+	stats, err := c.OrderRepository.CustomerStats(ctx, customerID, categories)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return stats, nil
+	}
+	if err := c.client.Set(ctx, key, data, c.ttl).Err(); err != nil {
+		logger.WarnContext(ctx, "order stats cache write failed", "error", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,337 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/pkg/logger"
+	"postgresDB/pkg/pagination"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	productCachePrefix = "products:"
+	// productCacheVersionKey is bumped on every product write; cached entries carry the
+	// version they were read at, so a bump invalidates them all at once
+	productCacheVersionKey = productCachePrefix + "version"
+	// productCacheFormat is part of every entry key and is raised when the cached
+	// entities change shape, so entries written by older releases are never read
+	productCacheFormat = "f3"
+)
+
+// productCache decorates a ProductRepository with a short lived Redis cache for the
+// public catalog reads. Redis errors never fail a request, the read falls through to
+// the wrapped repository instead. Writes made outside ProductRepository, like category
+// renames, show up once the TTL runs out
+type productCache struct {
+	next   repository.ProductRepository
+	client *redis.Client
+	ttl    time.Duration
+	// countTTL is how long estimated counts are kept, across product writes
+	countTTL time.Duration
+}
+
+// cachedProductList is the cached result of a product list query
+type cachedProductList struct {
+	Products []*entities.Product
+	Total    int64
+}
+
+// NewProductCache wraps a product repository with a Redis cache of GetByID, GetByIDs, List, ListPage, ListAfter
+// and ListRelated, and of EstimateCount for countTTL
+func NewProductCache(next repository.ProductRepository, client *redis.Client, ttl, countTTL time.Duration) repository.ProductRepository {
+	return &productCache{next: next, client: client, ttl: ttl, countTTL: countTTL}
+}
+
+// GetByID returns the cached product, reading it through on a miss
+func (c *productCache) GetByID(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
+	var product *entities.Product
+	err := c.readThrough(ctx, "id:"+id.String(), &product, func() error {
+		var err error
+		product, err = c.next.GetByID(ctx, id)
+		return err
+	})
+	return product, err
+}
+
+// GetCurrent isn't cached, it reads the latest version for an update
+func (c *productCache) GetCurrent(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
+	return c.next.GetCurrent(ctx, id)
+}
+
+// GetByIDs returns the cached batch, reading it through on a miss
+func (c *productCache) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Product, error) {
+	var products []*entities.Product
+	err := c.readThrough(ctx, "ids:"+hashKey(ids), &products, func() error {
+		var err error
+		products, err = c.next.GetByIDs(ctx, ids)
+		return err
+	})
+	return products, err
+}
+
+// List returns the cached product page, reading it through on a miss
+func (c *productCache) List(ctx context.Context, limit, offset int, filter repository.ProductFilter) ([]*entities.Product, int64, error) {
+	var result cachedProductList
+	key := "list:" + hashKey(limit, offset, filter)
+	err := c.readThrough(ctx, key, &result, func() error {
+		var err error
+		result.Products, result.Total, err = c.next.List(ctx, limit, offset, filter)
+		return err
+	})
+	return result.Products, result.Total, err
+}
+
+// ListPage returns the cached product page without a total, reading it through on a miss
+func (c *productCache) ListPage(ctx context.Context, limit, offset int, filter repository.ProductFilter) ([]*entities.Product, error) {
+	var products []*entities.Product
+	key := "page:" + hashKey(limit, offset, filter)
+	err := c.readThrough(ctx, key, &products, func() error {
+		var err error
+		products, err = c.next.ListPage(ctx, limit, offset, filter)
+		return err
+	})
+	return products, err
+}
+
+// EstimateCount returns the cached count for the filter, which unlike the other entries
+// outlives product writes until countTTL runs out. A cached count is never reported exact
+func (c *productCache) EstimateCount(ctx context.Context, filter repository.ProductFilter) (int64, bool, error) {
+	key := fmt.Sprintf("%s%s:count:%s", productCachePrefix, productCacheFormat, hashKey(filter))
+	if count, err := c.client.Get(ctx, key).Int64(); err == nil {
+		return count, false, nil
+	} else if !errors.Is(err, redis.Nil) {
+		logger.WarnContext(ctx, "product cache unavailable", "error", err)
+		return c.next.EstimateCount(ctx, filter)
+	}
+
+	count, exact, err := c.next.EstimateCount(ctx, filter)
+	if err != nil {
+		return 0, false, err
+	}
+	if err := c.client.Set(ctx, key, count, c.countTTL).Err(); err != nil {
+		logger.WarnContext(ctx, "product cache write failed", "error", err)
+	}
+	return count, exact, nil
+}
+
+// ListAfter returns the cached keyset page, reading it through on a miss
+func (c *productCache) ListAfter(ctx context.Context, limit int, after *pagination.Cursor, filter repository.ProductFilter) ([]*entities.Product, error) {
+	var products []*entities.Product
+	key := "after:" + hashKey(limit, after, filter)
+	err := c.readThrough(ctx, key, &products, func() error {
+		var err error
+		products, err = c.next.ListAfter(ctx, limit, after, filter)
+		return err
+	})
+	return products, err
+}
+
+// GetByIDsForUpdate isn't cached, the locked rows must be read from the database
+func (c *productCache) GetByIDsForUpdate(ctx context.Context, ids []uuid.UUID) ([]*entities.Product, error) {
+	return c.next.GetByIDsForUpdate(ctx, ids)
+}
+
+// FindAll isn't cached, it serves admin jobs that need current data
+func (c *productCache) FindAll(ctx context.Context, filter repository.ProductFilter) ([]*entities.Product, error) {
+	return c.next.FindAll(ctx, filter)
+}
+
+// ListRelated returns the cached related products, reading them through on a miss
+func (c *productCache) ListRelated(ctx context.Context, id uuid.UUID, limit int) ([]*entities.Product, error) {
+	var products []*entities.Product
+	key := "related:" + hashKey(id, limit)
+	err := c.readThrough(ctx, key, &products, func() error {
+		var err error
+		products, err = c.next.ListRelated(ctx, id, limit)
+		return err
+	})
+	return products, err
+}
+
+// RefreshRelated invalidates the cache, the ranking of related products may have changed
+func (c *productCache) RefreshRelated(ctx context.Context) error {
+	return c.invalidateAfter(ctx, c.next.RefreshRelated(ctx))
+}
+
+// References isn't cached, deleting a product needs the current references
+func (c *productCache) References(ctx context.Context, id uuid.UUID) (*entities.ProductReferences, error) {
+	return c.next.References(ctx, id)
+}
+
+func (c *productCache) Create(ctx context.Context, product *entities.Product) error {
+	return c.invalidateAfter(ctx, c.next.Create(ctx, product))
+}
+
+func (c *productCache) Update(ctx context.Context, product *entities.Product) error {
+	return c.invalidateAfter(ctx, c.next.Update(ctx, product))
+}
+
+func (c *productCache) Delete(ctx context.Context, id uuid.UUID) error {
+	return c.invalidateAfter(ctx, c.next.Delete(ctx, id))
+}
+
+func (c *productCache) Restore(ctx context.Context, id uuid.UUID) error {
+	return c.invalidateAfter(ctx, c.next.Restore(ctx, id))
+}
+
+func (c *productCache) PurgeArchived(ctx context.Context, before time.Time, limit int) (int, []string, error) {
+	purged, keys, err := c.next.PurgeArchived(ctx, before, limit)
+	return purged, keys, c.invalidateAfter(ctx, err)
+}
+
+func (c *productCache) UpdateStock(ctx context.Context, id uuid.UUID, delta int) (*entities.StockLevel, error) {
+	level, err := c.next.UpdateStock(ctx, id, delta)
+	return level, c.invalidateAfter(ctx, err)
+}
+
+func (c *productCache) DecrementStock(ctx context.Context, id uuid.UUID, qty int) (int, error) {
+	stock, err := c.next.DecrementStock(ctx, id, qty)
+	return stock, c.invalidateAfter(ctx, err)
+}
+
+// Statistics aren't cached, they are admin reads that should reflect the latest sales
+
+func (c *productCache) InventorySummary(ctx context.Context) (*entities.InventorySummary, error) {
+	return c.next.InventorySummary(ctx)
+}
+
+func (c *productCache) CategoryStats(ctx context.Context) ([]entities.CategoryStats, error) {
+	return c.next.CategoryStats(ctx)
+}
+
+func (c *productCache) TopSellers(ctx context.Context, from, to time.Time, limit int) ([]entities.ProductSales, error) {
+	return c.next.TopSellers(ctx, from, to, limit)
+}
+
+func (c *productCache) WeeklySales(ctx context.Context, ids []uuid.UUID, from time.Time, weeks int) (map[uuid.UUID][]int64, error) {
+	return c.next.WeeklySales(ctx, ids, from, weeks)
+}
+
+// ListLowStock isn't cached, admins expect it to reflect the latest sales
+func (c *productCache) ListLowStock(ctx context.Context, limit, offset int) ([]*entities.Product, int64, error) {
+	return c.next.ListLowStock(ctx, limit, offset)
+}
+
+func (c *productCache) UpdatePrices(ctx context.Context, changes []entities.PriceChange) error {
+	return c.invalidateAfter(ctx, c.next.UpdatePrices(ctx, changes))
+}
+
+func (c *productCache) UpsertBySKU(ctx context.Context, products []*entities.Product, batchSize int) ([]bool, error) {
+	created, err := c.next.UpsertBySKU(ctx, products, batchSize)
+	return created, c.invalidateAfter(ctx, err)
+}
+
+func (c *productCache) RestoreFromSnapshot(ctx context.Context, items []entities.SnapshotItem, restorePrice, restoreStock bool) (int, error) {
+	restored, err := c.next.RestoreFromSnapshot(ctx, items, restorePrice, restoreStock)
+	return restored, c.invalidateAfter(ctx, err)
+}
+
+// readThrough decodes the cached value of key into dest, or calls load and caches
+// dest when it isn't cached. Errors of load are returned as is and never cached
+func (c *productCache) readThrough(ctx context.Context, key string, dest any, load func() error) error {
+	version, err := c.client.Get(ctx, productCacheVersionKey).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		logger.WarnContext(ctx, "product cache unavailable", "error", err)
+		return load()
+	}
+	key = fmt.Sprintf("%s%s:v%s:%s", productCachePrefix, productCacheFormat, version, key)
+
+	cached, err := c.client.Get(ctx, key).Bytes()
+	if err == nil && json.Unmarshal(cached, dest) == nil {
+		return nil
+	}
+
+	if err := load(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(dest)
+	if err != nil {
+		return nil
+	}
+	if err := c.client.Set(ctx, key, data, c.ttl).Err(); err != nil {
+		logger.WarnContext(ctx, "product cache write failed", "error", err)
+	}
+	return nil
+}
+
+// invalidateAfter bumps the cache version once a write succeeded
+func (c *productCache) invalidateAfter(ctx context.Context, err error) error {
+	return invalidateProductCache(ctx, c.client, err)
+}
+
+// reservationCache decorates a StockReservationRepository to invalidate the product cache,
+// reservations change the stock of cached products
+type reservationCache struct {
+	repository.StockReservationRepository
+	client *redis.Client
+}
+
+// NewReservationCache wraps a reservation repository so its writes invalidate the product cache
+func NewReservationCache(next repository.StockReservationRepository, client *redis.Client) repository.StockReservationRepository {
+	return &reservationCache{StockReservationRepository: next, client: client}
+}
+
+func (c *reservationCache) Reserve(ctx context.Context, reservations []entities.StockReservation) ([]*entities.StockLevel, error) {
+	levels, err := c.StockReservationRepository.Reserve(ctx, reservations)
+	return levels, invalidateProductCache(ctx, c.client, err)
+}
+
+func (c *reservationCache) Convert(ctx context.Context, orderID uuid.UUID) ([]*entities.StockLevel, error) {
+	levels, err := c.StockReservationRepository.Convert(ctx, orderID)
+	return levels, invalidateProductCache(ctx, c.client, err)
+}
+
+func (c *reservationCache) Release(ctx context.Context, orderID uuid.UUID) (int, error) {
+	released, err := c.StockReservationRepository.Release(ctx, orderID)
+	if released == 0 {
+		return released, err
+	}
+	return released, invalidateProductCache(ctx, c.client, err)
+}
+
+func (c *reservationCache) ReleaseExpired(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	cancelled, err := c.StockReservationRepository.ReleaseExpired(ctx, now, limit)
+	if len(cancelled) == 0 {
+		return cancelled, err
+	}
+	return cancelled, invalidateProductCache(ctx, c.client, err)
+}
+
+func (c *reservationCache) CancelPendingWithProduct(ctx context.Context, productID uuid.UUID) ([]uuid.UUID, error) {
+	cancelled, err := c.StockReservationRepository.CancelPendingWithProduct(ctx, productID)
+	if len(cancelled) == 0 {
+		return cancelled, err
+	}
+	return cancelled, invalidateProductCache(ctx, c.client, err)
+}
+
+// invalidateProductCache bumps the cache version once a write succeeded; when Redis is
+// down stale entries live until their TTL runs out
+func invalidateProductCache(ctx context.Context, client *redis.Client, err error) error {
+	if err != nil {
+		return err
+	}
+	if err := client.Incr(ctx, productCacheVersionKey).Err(); err != nil {
+		logger.WarnContext(ctx, "product cache invalidation failed", "error", err)
+	}
+	return nil
+}
+
+// hashKey builds a compact cache key from query parameters; encoding/json sorts map
+// keys, so equal filters always give the same key
+func hashKey(parts ...any) string {
+	data, _ := json.Marshal(parts)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
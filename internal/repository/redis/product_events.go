@@ -0,0 +1,117 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/repository"
+	localrepo "postgresDB/internal/repository"
+	"postgresDB/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// productStockEvents decorates a ProductRepository and publishes a StockEvent for every
+// write that sets a product's stock. Publishing never fails the write, a dashboard that
+// misses an event catches up from the snapshot it gets when it reconnects
+type productStockEvents struct {
+	repository.ProductRepository
+	bus localrepo.EventBus
+}
+
+// NewProductStockEvents wraps a product repository so stock writes are published on bus
+func NewProductStockEvents(next repository.ProductRepository, bus localrepo.EventBus) repository.ProductRepository {
+	return &productStockEvents{ProductRepository: next, bus: bus}
+}
+
+func (p *productStockEvents) Update(ctx context.Context, product *entities.Product) error {
+	if err := p.ProductRepository.Update(ctx, product); err != nil {
+		return err
+	}
+	p.publish(ctx, entities.StockEvent{ProductID: product.ID, Stock: product.Stock})
+	return nil
+}
+
+func (p *productStockEvents) UpdateStock(ctx context.Context, id uuid.UUID, delta int) (*entities.StockLevel, error) {
+	level, err := p.ProductRepository.UpdateStock(ctx, id, delta)
+	if err != nil {
+		return nil, err
+	}
+	previous := level.Previous
+	p.publish(ctx, entities.StockEvent{ProductID: level.ProductID, Stock: level.Current, Previous: &previous})
+	return level, nil
+}
+
+func (p *productStockEvents) DecrementStock(ctx context.Context, id uuid.UUID, qty int) (int, error) {
+	stock, err := p.ProductRepository.DecrementStock(ctx, id, qty)
+	if err != nil {
+		return 0, err
+	}
+	previous := stock + qty
+	p.publish(ctx, entities.StockEvent{ProductID: id, Stock: stock, Previous: &previous})
+	return stock, nil
+}
+
+func (p *productStockEvents) UpsertBySKU(ctx context.Context, products []*entities.Product, batchSize int) ([]bool, error) {
+	created, err := p.ProductRepository.UpsertBySKU(ctx, products, batchSize)
+	if err != nil {
+		return created, err
+	}
+	for _, product := range products {
+		p.publish(ctx, entities.StockEvent{ProductID: product.ID, Stock: product.Stock})
+	}
+	return created, nil
+}
+
+func (p *productStockEvents) RestoreFromSnapshot(ctx context.Context, items []entities.SnapshotItem, restorePrice, restoreStock bool) (int, error) {
+	restored, err := p.ProductRepository.RestoreFromSnapshot(ctx, items, restorePrice, restoreStock)
+	if err != nil || !restoreStock {
+		return restored, err
+	}
+	for _, item := range items {
+		p.publish(ctx, entities.StockEvent{ProductID: item.ProductID, Stock: item.Stock})
+	}
+	return restored, nil
+}
+
+func (p *productStockEvents) publish(ctx context.Context, event entities.StockEvent) {
+	publishStockEvent(ctx, p.bus, event)
+}
+
+// reservationStockEvents decorates a StockReservationRepository and publishes the stock
+// deducted when a paid order's reservations are converted
+type reservationStockEvents struct {
+	repository.StockReservationRepository
+	bus localrepo.EventBus
+}
+
+// NewReservationStockEvents wraps a reservation repository so conversions are published on bus
+func NewReservationStockEvents(next repository.StockReservationRepository, bus localrepo.EventBus) repository.StockReservationRepository {
+	return &reservationStockEvents{StockReservationRepository: next, bus: bus}
+}
+
+func (r *reservationStockEvents) Convert(ctx context.Context, orderID uuid.UUID) ([]*entities.StockLevel, error) {
+	levels, err := r.StockReservationRepository.Convert(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, level := range levels {
+		previous := level.Previous
+		publishStockEvent(ctx, r.bus, entities.StockEvent{ProductID: level.ProductID, Stock: level.Current, Previous: &previous})
+	}
+	return levels, nil
+}
+
+// publishStockEvent publishes a stock event, logging rather than returning failures
+func publishStockEvent(ctx context.Context, bus localrepo.EventBus, event entities.StockEvent) {
+	event.OccurredAt = time.Now()
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	if err := bus.Publish(ctx, entities.StockEventTopic, payload); err != nil {
+		logger.WarnContext(ctx, "publishing stock event failed", "product_id", event.ProductID, "error", err)
+	}
+}
@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"postgresDB/internal/repository"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	rateLimitPrefix   = "ratelimit:"
+	tokenBucketPrefix = "ratelimit:bucket:"
+)
+
+// tokenBucketScript takes a token from the bucket in KEYS[1], refilled at ARGV[1] tokens
+// per millisecond up to ARGV[2] tokens. Time is read from Redis so every instance agrees
+// on it. It returns whether a token was taken, the tokens left and the milliseconds until
+// the bucket is full, or until the next token when none was taken
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) * 1000 + math.floor(tonumber(clock[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+local full = math.ceil((burst - tokens) / rate)
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], full + 1000)
+
+local wait = full
+if allowed == 0 then
+	wait = math.ceil((1 - tokens) / rate)
+end
+return {allowed, math.floor(tokens), wait}
+`)
+
+// rateLimitRepository implements repository.RateLimitRepository
+type rateLimitRepository struct {
+	client *redis.Client
+}
+
+// NewRateLimitRepository creates a new rate limit repository
+func NewRateLimitRepository(client *redis.Client) repository.RateLimitRepository {
+	return &rateLimitRepository{client: client}
+}
+
+// Allow increments the counter of the current window, starting the window on the first hit
+func (r *rateLimitRepository) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	key = rateLimitPrefix + key
+
+	pipe := r.client.TxPipeline()
+	count := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, window)
+	ttl := pipe.PTTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, 0, err
+	}
+
+	return count.Val() <= int64(limit), ttl.Val(), nil
+}
+
+// Take refills and takes from the bucket in one script, so concurrent requests of a client
+// never take the same token
+func (r *rateLimitRepository) Take(ctx context.Context, key string, rate float64, burst int) (bool, int, time.Duration, error) {
+	res, err := tokenBucketScript.Run(ctx, r.client, []string{tokenBucketPrefix + key}, rate/1000, burst).Int64Slice()
+	if err != nil {
+		return false, 0, 0, err
+	}
+	return res[0] == 1, int(res[1]), time.Duration(res[2]) * time.Millisecond, nil
+}
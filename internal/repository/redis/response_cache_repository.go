@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/repository"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const responseCachePrefix = "responses:"
+
+// responseCacheRepository implements repository.ResponseCacheRepository
+type responseCacheRepository struct {
+	client *redis.Client
+}
+
+// NewResponseCacheRepository creates a new response cache repository
+func NewResponseCacheRepository(client *redis.Client) repository.ResponseCacheRepository {
+	return &responseCacheRepository{client: client}
+}
+
+// Version reads the version counter of scope, "0" before its first invalidation
+func (r *responseCacheRepository) Version(ctx context.Context, scope string) (string, error) {
+	version, err := r.client.Get(ctx, responseCachePrefix+scope+":version").Result()
+	if errors.Is(err, redis.Nil) {
+		return "0", nil
+	}
+	return version, err
+}
+
+func (r *responseCacheRepository) Get(ctx context.Context, key string) (*entities.CachedResponse, error) {
+	data, err := r.client.Get(ctx, responseCachePrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var response entities.CachedResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (r *responseCacheRepository) Set(ctx context.Context, key string, response *entities.CachedResponse, ttl time.Duration) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(ctx, responseCachePrefix+key, data, ttl).Err()
+}
+
+// Invalidate increments the version counter; entries of older versions are never read
+// again and expire with their TTL
+func (r *responseCacheRepository) Invalidate(ctx context.Context, scope string) error {
+	return r.client.Incr(ctx, responseCachePrefix+scope+":version").Err()
+}
@@ -0,0 +1,41 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"postgresDB/pkg/retry"
+	"syscall"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// retryPolicy bounds the retries of reads failing with a transient error. go-redis retries
+// some network errors itself, these are the ones still failing
+var retryPolicy = retry.Policy{
+	Attempts:  3,
+	BaseDelay: 10 * time.Millisecond,
+	MaxDelay:  200 * time.Millisecond,
+}
+
+// isRetryable reports whether a command may succeed when sent again: it timed out,
+// waited too long for a connection, or the connection was closed or reset. A command
+// stopped by its own context is not retried
+func isRetryable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, redis.ErrPoolTimeout) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF)
+}
+
+// withRetry runs fn, and again when it fails with a transient error. Only reads, and
+// writes that may be applied twice, are run with it
+func withRetry(ctx context.Context, fn func() error) error {
+	return retry.Do(ctx, "redis", retryPolicy, isRetryable, fn)
+}
@@ -2,10 +2,15 @@ package redis
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"postgresDB/internal/domain/entities"
 	"postgresDB/internal/repository"
 
 	"github.com/google/uuid"
@@ -16,8 +21,44 @@ const (
 	blacklistPrefix    = "jwt:blacklist:"
 	tokenFamilyPrefix  = "jwt:family:"
 	userSessionsPrefix = "jwt:sessions:"
+	// revokedBeforePrefix holds, per scope, the unix time before which tokens are revoked
+	revokedBeforePrefix = "jwt:revoked_before:"
+	// sessionInfoPrefix holds the device and timestamps of a login session, per family
+	sessionInfoPrefix = "jwt:session_info:"
+	// evictedSessionsPrefix holds the latest sessions of a user ended by the session quota
+	evictedSessionsPrefix = "jwt:evicted_sessions:"
+
+	// maxEvictedSessions is the number of evicted sessions kept per user
+	maxEvictedSessions = 10
+
+	// authStateBatch is the number of keys read or written per round trip by export and import
+	authStateBatch = 500
 )
 
+// raiseCutoffScript sets every key in KEYS to the cutoff ARGV[1] with TTL ARGV[2] ms,
+// unless it already holds a later cutoff, and returns how many keys were raised
+var raiseCutoffScript = redis.NewScript(`
+local raised = 0
+for _, key in ipairs(KEYS) do
+	local current = tonumber(redis.call('GET', key) or '0')
+	if tonumber(ARGV[1]) > current then
+		redis.call('SET', key, ARGV[1], 'PX', ARGV[2])
+		raised = raised + 1
+	end
+end
+return raised
+`)
+
+// touchSessionScript records the last use of the session details in KEYS[1] at ARGV[1]
+// and extends them to ARGV[2] ms, without creating details for families that have none
+var touchSessionScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	redis.call('HSET', KEYS[1], 'last_used_at', ARGV[1])
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
 // tokenRepository implements repository.TokenRepository
 type tokenRepository struct {
 	client *redis.Client
@@ -40,7 +81,11 @@ func (r *tokenRepository) BlacklistToken(ctx context.Context, jti string, ttl ti
 // IsTokenBlacklisted checks if a token JTI is blacklisted
 func (r *tokenRepository) IsTokenBlacklisted(ctx context.Context, jti string) (bool, error) {
 	key := blacklistPrefix + jti
-	result, err := r.client.Exists(ctx, key).Result()
+	var result int64
+	err := withRetry(ctx, func() (err error) {
+		result, err = r.client.Exists(ctx, key).Result()
+		return err
+	})
 	if err != nil {
 		return false, err
 	}
@@ -56,18 +101,186 @@ func (r *tokenRepository) SetTokenFamily(ctx context.Context, userID uuid.UUID,
 // GetTokenFamily gets the current JTI for a token family
 func (r *tokenRepository) GetTokenFamily(ctx context.Context, userID uuid.UUID, family string) (string, error) {
 	key := fmt.Sprintf("%s%s:%s", tokenFamilyPrefix, userID.String(), family)
-	result, err := r.client.Get(ctx, key).Result()
+	var result string
+	err := withRetry(ctx, func() (err error) {
+		result, err = r.client.Get(ctx, key).Result()
+		return err
+	})
 	if errors.Is(err, redis.Nil) {
 		return "", nil
 	}
 	return result, err
 }
 
-// TrackUserSession tracks a user's session (token family)
+// TrackUserSession tracks a user's session (token family), dropping expired ones
 func (r *tokenRepository) TrackUserSession(ctx context.Context, userID uuid.UUID, family string, ttl time.Duration) error {
 	key := userSessionsPrefix + userID.String()
-	score := float64(time.Now().Add(ttl).Unix())
-	return r.client.ZAdd(ctx, key, redis.Z{Score: score, Member: family}).Err()
+	now := time.Now()
+	score := float64(now.Add(ttl).Unix())
+
+	pipe := r.client.Pipeline()
+	pipe.ZAdd(ctx, key, redis.Z{Score: score, Member: family})
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.Unix(), 10))
+	touchSessionScript.Eval(ctx, pipe, []string{sessionInfoKey(userID, family)}, now.UnixMilli(), ttl.Milliseconds())
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// SaveSession stores the session details as a hash next to the token family
+func (r *tokenRepository) SaveSession(ctx context.Context, userID uuid.UUID, session *entities.Session, ttl time.Duration) error {
+	key := sessionInfoKey(userID, session.Family)
+	pipe := r.client.TxPipeline()
+	pipe.HSet(ctx, key, map[string]any{
+		"device":       session.Device,
+		"ip":           session.IP,
+		"created_at":   session.CreatedAt.UnixMilli(),
+		"last_used_at": session.LastUsedAt.UnixMilli(),
+	})
+	pipe.PExpire(ctx, key, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// ListUserSessions reads the unexpired families of the user with their session details
+func (r *tokenRepository) ListUserSessions(ctx context.Context, userID uuid.UUID) ([]*entities.Session, error) {
+	families, err := r.client.ZRangeByScoreWithScores(ctx, userSessionsPrefix+userID.String(), &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(time.Now().Unix(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*entities.Session, 0, len(families))
+	if len(families) == 0 {
+		return sessions, nil
+	}
+	pipe := r.client.Pipeline()
+	infos := make([]*redis.MapStringStringCmd, len(families))
+	for i, z := range families {
+		infos[i] = pipe.HGetAll(ctx, sessionInfoKey(userID, fmt.Sprint(z.Member)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	for i, z := range families {
+		values := infos[i].Val()
+		if len(values) == 0 {
+			continue
+		}
+		sessions = append(sessions, &entities.Session{
+			Family:     fmt.Sprint(z.Member),
+			Device:     values["device"],
+			IP:         values["ip"],
+			CreatedAt:  time.UnixMilli(parseInt64(values["created_at"])),
+			LastUsedAt: time.UnixMilli(parseInt64(values["last_used_at"])),
+			ExpiresAt:  time.Unix(int64(z.Score), 0),
+		})
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+// EvictUserSession deletes the family, its session details and its entry in the
+// sessions set, and pushes the session to the capped list of evicted sessions
+func (r *tokenRepository) EvictUserSession(ctx context.Context, userID uuid.UUID, session *entities.Session, ttl time.Duration) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	evictedKey := evictedSessionsPrefix + userID.String()
+	pipe := r.client.TxPipeline()
+	pipe.ZRem(ctx, userSessionsPrefix+userID.String(), session.Family)
+	pipe.Del(ctx, fmt.Sprintf("%s%s:%s", tokenFamilyPrefix, userID.String(), session.Family), sessionInfoKey(userID, session.Family))
+	pipe.LPush(ctx, evictedKey, data)
+	pipe.LTrim(ctx, evictedKey, 0, maxEvictedSessions-1)
+	pipe.PExpire(ctx, evictedKey, ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// RemoveUserSession deletes the family, its session details and its entry in the sessions set
+func (r *tokenRepository) RemoveUserSession(ctx context.Context, userID uuid.UUID, family string) error {
+	pipe := r.client.TxPipeline()
+	pipe.ZRem(ctx, userSessionsPrefix+userID.String(), family)
+	pipe.Del(ctx, fmt.Sprintf("%s%s:%s", tokenFamilyPrefix, userID.String(), family), sessionInfoKey(userID, family))
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// ListEvictedSessions reads the evicted sessions list of the user
+func (r *tokenRepository) ListEvictedSessions(ctx context.Context, userID uuid.UUID) ([]*entities.Session, error) {
+	values, err := r.client.LRange(ctx, evictedSessionsPrefix+userID.String(), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*entities.Session, 0, len(values))
+	for _, value := range values {
+		var session entities.Session
+		if err := json.Unmarshal([]byte(value), &session); err != nil {
+			continue
+		}
+		sessions = append(sessions, &session)
+	}
+	return sessions, nil
+}
+
+// RevokeIssuedBefore stores the cutoff of every scope, keeping later cutoffs
+func (r *tokenRepository) RevokeIssuedBefore(ctx context.Context, scopes []string, before time.Time, ttl time.Duration) (int, error) {
+	if len(scopes) == 0 || ttl <= 0 {
+		return 0, nil
+	}
+	keys := make([]string, len(scopes))
+	for i, scope := range scopes {
+		keys[i] = revokedBeforePrefix + scope
+	}
+	return raiseCutoffScript.Run(ctx, r.client, keys, before.Unix(), ttl.Milliseconds()).Int()
+}
+
+// IsTokenRevoked checks the blacklist and the cutoffs of scopes in one round trip
+func (r *tokenRepository) IsTokenRevoked(ctx context.Context, jti string, issuedAt time.Time, scopes ...string) (bool, error) {
+	keys := make([]string, len(scopes))
+	for i, scope := range scopes {
+		keys[i] = revokedBeforePrefix + scope
+	}
+
+	var blacklisted *redis.IntCmd
+	var cutoffs *redis.SliceCmd
+	err := withRetry(ctx, func() error {
+		pipe := r.client.Pipeline()
+		blacklisted = pipe.Exists(ctx, blacklistPrefix+jti)
+		if len(keys) > 0 {
+			cutoffs = pipe.MGet(ctx, keys...)
+		}
+		_, err := pipe.Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if blacklisted.Val() > 0 {
+		return true, nil
+	}
+	if cutoffs == nil {
+		return false, nil
+	}
+	for _, v := range cutoffs.Val() {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		cutoff, err := strconv.ParseInt(s, 10, 64)
+		if err == nil && issuedAt.Unix() < cutoff {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // RevokeAllUserSessions revokes all sessions for a user
@@ -79,10 +292,10 @@ func (r *tokenRepository) RevokeAllUserSessions(ctx context.Context, userID uuid
 		return err
 	}
 
-	// Delete all family tokens
+	// Delete all family tokens and their session details
 	for _, family := range families {
 		familyKey := fmt.Sprintf("%s%s:%s", tokenFamilyPrefix, userID.String(), family)
-		r.client.Del(ctx, familyKey)
+		r.client.Del(ctx, familyKey, sessionInfoKey(userID, family))
 	}
 
 	// Clear the sessions set
@@ -90,3 +303,251 @@ func (r *tokenRepository) RevokeAllUserSessions(ctx context.Context, userID uuid
 
 	return nil
 }
+
+// ExportState reads every blacklist, token family and session key
+func (r *tokenRepository) ExportState(ctx context.Context) ([]entities.AuthStateEntry, error) {
+	now := time.Now()
+	var entries []entities.AuthStateEntry
+
+	err := r.scanKeys(ctx, blacklistPrefix, func(keys []string) error {
+		pipe := r.client.Pipeline()
+		ttls := make([]*redis.DurationCmd, len(keys))
+		for i, key := range keys {
+			ttls[i] = pipe.PTTL(ctx, key)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+		for i, key := range keys {
+			expiresAt, ok := keyExpiry(now, ttls[i].Val())
+			if !ok {
+				continue
+			}
+			entries = append(entries, entities.AuthStateEntry{
+				Kind:      entities.AuthStateBlacklist,
+				ID:        strings.TrimPrefix(key, blacklistPrefix),
+				ExpiresAt: expiresAt,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.scanKeys(ctx, tokenFamilyPrefix, func(keys []string) error {
+		pipe := r.client.Pipeline()
+		values := make([]*redis.StringCmd, len(keys))
+		ttls := make([]*redis.DurationCmd, len(keys))
+		for i, key := range keys {
+			values[i] = pipe.Get(ctx, key)
+			ttls[i] = pipe.PTTL(ctx, key)
+		}
+		if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		for i, key := range keys {
+			expiresAt, ok := keyExpiry(now, ttls[i].Val())
+			if !ok || values[i].Err() != nil {
+				continue
+			}
+			entries = append(entries, entities.AuthStateEntry{
+				Kind:      entities.AuthStateFamily,
+				ID:        strings.TrimPrefix(key, tokenFamilyPrefix),
+				Value:     values[i].Val(),
+				ExpiresAt: expiresAt,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.scanKeys(ctx, revokedBeforePrefix, func(keys []string) error {
+		pipe := r.client.Pipeline()
+		values := make([]*redis.StringCmd, len(keys))
+		ttls := make([]*redis.DurationCmd, len(keys))
+		for i, key := range keys {
+			values[i] = pipe.Get(ctx, key)
+			ttls[i] = pipe.PTTL(ctx, key)
+		}
+		if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		for i, key := range keys {
+			expiresAt, ok := keyExpiry(now, ttls[i].Val())
+			if !ok || values[i].Err() != nil {
+				continue
+			}
+			entries = append(entries, entities.AuthStateEntry{
+				Kind:      entities.AuthStateRevokedBefore,
+				ID:        strings.TrimPrefix(key, revokedBeforePrefix),
+				Value:     values[i].Val(),
+				ExpiresAt: expiresAt,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.scanKeys(ctx, userSessionsPrefix, func(keys []string) error {
+		pipe := r.client.Pipeline()
+		members := make([]*redis.ZSliceCmd, len(keys))
+		for i, key := range keys {
+			members[i] = pipe.ZRangeWithScores(ctx, key, 0, -1)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+		for i, key := range keys {
+			if len(members[i].Val()) == 0 {
+				continue
+			}
+			sessions := make(map[string]int64, len(members[i].Val()))
+			for _, z := range members[i].Val() {
+				sessions[fmt.Sprint(z.Member)] = int64(z.Score)
+			}
+			entries = append(entries, entities.AuthStateEntry{
+				Kind:     entities.AuthStateSessions,
+				ID:       strings.TrimPrefix(key, userSessionsPrefix),
+				Sessions: sessions,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ImportState writes back the entries with their remaining TTL. Blacklist entries are
+// always written, token families only when missing, and session expiries and revocation
+// cutoffs only raised
+func (r *tokenRepository) ImportState(ctx context.Context, entries []entities.AuthStateEntry) (int, int, error) {
+	restored, skipped := 0, 0
+
+	for start := 0; start < len(entries); start += authStateBatch {
+		batch := entries[start:min(start+authStateBatch, len(entries))]
+		now := time.Now()
+
+		pipe := r.client.Pipeline()
+		var setNX []*redis.BoolCmd
+		for _, entry := range batch {
+			var ttl time.Duration
+			if entry.ExpiresAt != nil {
+				if ttl = entry.ExpiresAt.Sub(now); ttl <= 0 {
+					skipped++
+					continue
+				}
+			}
+
+			switch entry.Kind {
+			case entities.AuthStateBlacklist:
+				pipe.Set(ctx, blacklistPrefix+entry.ID, "1", ttl)
+				restored++
+			case entities.AuthStateFamily:
+				setNX = append(setNX, pipe.SetNX(ctx, tokenFamilyPrefix+entry.ID, entry.Value, ttl))
+			case entities.AuthStateRevokedBefore:
+				if ttl <= 0 {
+					skipped++
+					continue
+				}
+				raiseCutoffScript.Eval(ctx, pipe, []string{revokedBeforePrefix + entry.ID}, entry.Value, ttl.Milliseconds())
+				restored++
+			case entities.AuthStateSessions:
+				members := make([]redis.Z, 0, len(entry.Sessions))
+				for family, expiresAt := range entry.Sessions {
+					if expiresAt > now.Unix() {
+						members = append(members, redis.Z{Score: float64(expiresAt), Member: family})
+					}
+				}
+				if len(members) == 0 {
+					skipped++
+					continue
+				}
+				pipe.ZAddGT(ctx, userSessionsPrefix+entry.ID, members...)
+				restored++
+			default:
+				skipped++
+			}
+		}
+
+		if _, err := pipe.Exec(ctx); err != nil {
+			return restored, skipped, err
+		}
+		for _, cmd := range setNX {
+			if cmd.Val() {
+				restored++
+			} else {
+				skipped++
+			}
+		}
+	}
+
+	return restored, skipped, nil
+}
+
+// PruneSessions removes the expired members of every session set. A user's set is only
+// pruned when they log in, so the sets of users who stopped logging in keep growing
+func (r *tokenRepository) PruneSessions(ctx context.Context, now time.Time) (int64, error) {
+	var pruned int64
+	maxScore := strconv.FormatInt(now.Unix(), 10)
+	err := r.scanKeys(ctx, userSessionsPrefix, func(keys []string) error {
+		pipe := r.client.Pipeline()
+		removed := make([]*redis.IntCmd, len(keys))
+		for i, key := range keys {
+			removed[i] = pipe.ZRemRangeByScore(ctx, key, "-inf", maxScore)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+		for _, cmd := range removed {
+			pruned += cmd.Val()
+		}
+		return nil
+	})
+	return pruned, err
+}
+
+// sessionInfoKey is the key of the session details of a token family
+func sessionInfoKey(userID uuid.UUID, family string) string {
+	return fmt.Sprintf("%s%s:%s", sessionInfoPrefix, userID.String(), family)
+}
+
+// scanKeys calls fn with the keys starting with prefix, a batch at a time
+func (r *tokenRepository) scanKeys(ctx context.Context, prefix string, fn func(keys []string) error) error {
+	var cursor uint64
+	for {
+		keys, next, err := r.client.Scan(ctx, cursor, prefix+"*", authStateBatch).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// keyExpiry converts a PTTL reply to an expiry time; ok is false when the key is gone
+func keyExpiry(now time.Time, ttl time.Duration) (expiresAt *time.Time, ok bool) {
+	switch {
+	case ttl == -2:
+		return nil, false
+	case ttl < 0:
+		return nil, true
+	default:
+		t := now.Add(ttl)
+		return &t, true
+	}
+}
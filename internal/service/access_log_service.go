@@ -0,0 +1,421 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/internal/infrastruktur/storage"
+	"postgresDB/pkg/logger"
+	"postgresDB/pkg/metrics"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// JobTypeAccessLogExport writes the file of an access log export
+	JobTypeAccessLogExport = "access_log_export"
+
+	// maxAccessLogExportRange is the longest time range of a single export
+	maxAccessLogExportRange = 93 * 24 * time.Hour
+	// accessLogCleanupInterval is how often expired access logs and exports are removed
+	accessLogCleanupInterval = time.Hour
+)
+
+var accessLogsDropped = metrics.NewCounter("access_logs_dropped_total",
+	"Number of access logs dropped because the write buffer was full")
+
+// accessLogExportJob is the payload of a JobTypeAccessLogExport job
+type accessLogExportJob struct {
+	ExportID uuid.UUID `json:"export_id"`
+}
+
+// AccessLogConfig configures access log storage and exports
+type AccessLogConfig struct {
+	// BufferSize is the number of access logs waiting to be written; requests beyond it
+	// aren't logged rather than slowed down
+	BufferSize int
+	// BatchSize is the most access logs written at once
+	BatchSize int
+	// FlushInterval is the longest an access log waits in the buffer
+	FlushInterval time.Duration
+	// Retention is how long access logs are kept, 0 keeps them forever
+	Retention time.Duration
+	// ExportRetention is how long export files are kept, 0 keeps them forever
+	ExportRetention time.Duration
+}
+
+type accessLogService struct {
+	accessLogRepo repository.AccessLogRepository
+	jobs          service.JobQueue
+	files         storage.Storage
+	cfg           AccessLogConfig
+	entries       chan entities.AccessLog
+}
+
+// NewAccessLogService creates a new AccessLogService instance. Export files are written
+// to files, which must not be publicly served
+func NewAccessLogService(accessLogRepo repository.AccessLogRepository, jobs service.JobQueue, files storage.Storage, cfg AccessLogConfig) service.AccessLogService {
+	return &accessLogService{
+		accessLogRepo: accessLogRepo,
+		jobs:          jobs,
+		files:         files,
+		cfg:           cfg,
+		entries:       make(chan entities.AccessLog, max(cfg.BufferSize, 1)),
+	}
+}
+
+// Record queues the access log, dropping it when the buffer is full
+func (s *accessLogService) Record(log entities.AccessLog) {
+	select {
+	case s.entries <- log:
+	default:
+		accessLogsDropped.Inc()
+	}
+}
+
+// Run writes the queued access logs every FlushInterval or once a batch is full, and
+// removes expired logs and exports. The buffer is flushed when ctx is done
+func (s *accessLogService) Run(ctx context.Context) {
+	flush := time.NewTicker(s.cfg.FlushInterval)
+	defer flush.Stop()
+	cleanup := time.NewTicker(accessLogCleanupInterval)
+	defer cleanup.Stop()
+
+	batchSize := max(s.cfg.BatchSize, 1)
+	batch := make([]entities.AccessLog, 0, batchSize)
+	write := func(ctx context.Context) {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.accessLogRepo.InsertBatch(ctx, batch); err != nil {
+			logger.ErrorContext(ctx, "writing access logs failed", "count", len(batch), "error", err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			storeCtx := context.WithoutCancel(ctx)
+			for {
+				select {
+				case log := <-s.entries:
+					batch = append(batch, log)
+					if len(batch) >= batchSize {
+						write(storeCtx)
+					}
+				default:
+					write(storeCtx)
+					return
+				}
+			}
+		case log := <-s.entries:
+			batch = append(batch, log)
+			if len(batch) >= batchSize {
+				write(ctx)
+			}
+		case <-flush.C:
+			write(ctx)
+		case <-cleanup.C:
+			s.removeExpired(ctx)
+		}
+	}
+}
+
+// removeExpired deletes access logs and exports past their retention
+func (s *accessLogService) removeExpired(ctx context.Context) {
+	if s.cfg.Retention > 0 {
+		removed, err := s.accessLogRepo.DeleteBefore(ctx, time.Now().Add(-s.cfg.Retention))
+		if err != nil {
+			logger.ErrorContext(ctx, "removing old access logs failed", "error", err)
+		} else if removed > 0 {
+			logger.InfoContext(ctx, "old access logs removed", "count", removed)
+		}
+	}
+
+	if s.cfg.ExportRetention > 0 {
+		exports, err := s.accessLogRepo.DeleteExportsBefore(ctx, time.Now().Add(-s.cfg.ExportRetention))
+		if err != nil {
+			logger.ErrorContext(ctx, "removing old access log exports failed", "error", err)
+			return
+		}
+		for _, e := range exports {
+			if e.StorageKey == "" {
+				continue
+			}
+			if err := s.files.Delete(ctx, e.StorageKey); err != nil {
+				logger.ErrorContext(ctx, "removing access log export file failed", "export_id", e.ID, "error", err)
+			}
+		}
+	}
+}
+
+// RequestExport stores the export and queues the job writing its file. Users can only
+// export their own requests
+func (s *accessLogService) RequestExport(ctx context.Context, requester uuid.UUID, role entities.Role, req *dto.CreateAccessLogExportRequest) (*dto.AccessLogExportResponse, error) {
+	if req.To.Sub(req.From) > maxAccessLogExportRange {
+		return nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "to", Message: "rentang waktu export maksimal %d hari", Args: []any{int(maxAccessLogExportRange.Hours() / 24)}},
+		})
+	}
+
+	userID := req.UserID
+	if role != entities.RoleAdmin {
+		if userID != nil && *userID != requester {
+			return nil, apperror.ErrForbidden
+		}
+		userID = &requester
+	}
+
+	format := req.Format
+	if format == "" {
+		format = "csv"
+	}
+
+	export := &entities.AccessLogExport{
+		ID:          uuid.New(),
+		RequestedBy: requester,
+		ClientID:    req.ClientID,
+		UserID:      userID,
+		From:        req.From,
+		To:          req.To,
+		Format:      format,
+		Status:      entities.AccessLogExportPending,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.accessLogRepo.CreateExport(ctx, export); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.jobs.Enqueue(ctx, "", JobTypeAccessLogExport, accessLogExportJob{ExportID: export.ID}); err != nil {
+		export.Status = entities.AccessLogExportFailed
+		export.Error = "export tidak dapat dijadwalkan"
+		if err := s.accessLogRepo.UpdateExport(context.WithoutCancel(ctx), export); err != nil {
+			logger.ErrorContext(ctx, "marking access log export failed", "export_id", export.ID, "error", err)
+		}
+		return nil, err
+	}
+
+	response := dto.ToAccessLogExportResponse(export)
+	return &response, nil
+}
+
+// GetExport retrieves an export of the requester, or any export for admins
+func (s *accessLogService) GetExport(ctx context.Context, id, requester uuid.UUID, role entities.Role) (*dto.AccessLogExportResponse, error) {
+	export, err := s.visibleExport(ctx, id, requester, role)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToAccessLogExportResponse(export)
+	return &response, nil
+}
+
+// ListExports retrieves the exports of the requester, or all exports for admins
+func (s *accessLogService) ListExports(ctx context.Context, requester uuid.UUID, role entities.Role, page, limit int) ([]dto.AccessLogExportResponse, *dto.PaginationMeta, error) {
+	if page <= 1 {
+		page = 1
+	}
+	if limit <= 1 {
+		limit = 10
+	}
+
+	var requestedBy *uuid.UUID
+	if role != entities.RoleAdmin {
+		requestedBy = &requester
+	}
+
+	exports, total, err := s.accessLogRepo.ListExports(ctx, requestedBy, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.PaginationMeta{
+		Total:      total,
+		Limit:      limit,
+		Page:       page,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}
+	return dto.ToAccessLogExportResponseList(exports), pagination, nil
+}
+
+// OpenExport returns the file of a completed export
+func (s *accessLogService) OpenExport(ctx context.Context, id, requester uuid.UUID, role entities.Role) (*dto.AccessLogExportFile, error) {
+	export, err := s.visibleExport(ctx, id, requester, role)
+	if err != nil {
+		return nil, err
+	}
+	if export.Status != entities.AccessLogExportCompleted {
+		return nil, apperror.ErrAccessLogExportNotReady
+	}
+
+	body, err := s.files.Open(ctx, export.StorageKey)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, apperror.ErrAccessLogExportNotFound
+		}
+		return nil, apperror.WrapInternal(err)
+	}
+
+	contentType := "text/csv"
+	if export.Format == "ndjson" {
+		contentType = "application/x-ndjson"
+	}
+	return &dto.AccessLogExportFile{
+		Name:        fmt.Sprintf("access-logs-%s.%s", export.From.UTC().Format("20060102"), export.Format),
+		ContentType: contentType,
+		Size:        export.Size,
+		Body:        body,
+	}, nil
+}
+
+// HandleExport is the JobHandler of JobTypeAccessLogExport. A failed attempt marks the
+// export failed, a retry runs it again from the start
+func (s *accessLogService) HandleExport(ctx context.Context, payload json.RawMessage) error {
+	var job accessLogExportJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return fmt.Errorf("decoding access log export: %w", err)
+	}
+
+	export, err := s.accessLogRepo.GetExport(ctx, job.ExportID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrAccessLogExportNotFound) {
+			// removed by retention before the job ran
+			return nil
+		}
+		return err
+	}
+	if export.Status == entities.AccessLogExportCompleted {
+		return nil
+	}
+
+	export.Status = entities.AccessLogExportRunning
+	export.Error = ""
+	if err := s.accessLogRepo.UpdateExport(ctx, export); err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("access-logs/%s.%s", export.ID, export.Format)
+	rows, size, err := s.writeExportFile(ctx, key, export)
+	if err != nil {
+		export.Status = entities.AccessLogExportFailed
+		export.Error = "export gagal dibuat"
+		if err := s.accessLogRepo.UpdateExport(context.WithoutCancel(ctx), export); err != nil {
+			logger.ErrorContext(ctx, "marking access log export failed", "export_id", export.ID, "error", err)
+		}
+		return err
+	}
+
+	now := time.Now()
+	export.Status = entities.AccessLogExportCompleted
+	export.StorageKey = key
+	export.RowCount = rows
+	export.Size = size
+	export.CompletedAt = &now
+	return s.accessLogRepo.UpdateExport(ctx, export)
+}
+
+// writeExportFile streams the matching access logs into the file stored under key and
+// returns the number of rows and bytes written
+func (s *accessLogService) writeExportFile(ctx context.Context, key string, export *entities.AccessLogExport) (int64, int64, error) {
+	filter := repository.AccessLogFilter{
+		ClientID: export.ClientID,
+		UserID:   export.UserID,
+		From:     export.From,
+		To:       export.To,
+	}
+
+	pr, pw := io.Pipe()
+	out := &countingWriter{w: pw}
+	var rows int64
+	done := make(chan error, 1)
+	go func() {
+		err := writeAccessLogs(ctx, s.accessLogRepo, filter, export.Format, out, &rows)
+		pw.CloseWithError(err)
+		done <- err
+	}()
+
+	_, saveErr := s.files.Save(ctx, key, pr, "")
+	// unblock the writer when saving stopped early
+	pr.CloseWithError(io.ErrClosedPipe)
+	writeErr := <-done
+	if saveErr != nil {
+		return 0, 0, saveErr
+	}
+	if writeErr != nil {
+		return 0, 0, writeErr
+	}
+	return rows, out.n, nil
+}
+
+// writeAccessLogs encodes the access logs matching filter to w as CSV or NDJSON
+func writeAccessLogs(ctx context.Context, repo repository.AccessLogRepository, filter repository.AccessLogFilter, format string, w io.Writer, rows *int64) error {
+	if format == "ndjson" {
+		enc := json.NewEncoder(w)
+		return repo.Each(ctx, filter, func(l *entities.AccessLog) error {
+			*rows++
+			return enc.Encode(dto.ToAccessLogRecord(l))
+		})
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"at", "method", "path", "status", "duration_ms", "size", "ip", "client_id", "user_id", "user_agent"}); err != nil {
+		return err
+	}
+	err := repo.Each(ctx, filter, func(l *entities.AccessLog) error {
+		*rows++
+		r := dto.ToAccessLogRecord(l)
+		return cw.Write([]string{
+			r.At,
+			r.Method,
+			r.Path,
+			strconv.Itoa(r.Status),
+			strconv.FormatFloat(r.DurationMs, 'f', 3, 64),
+			strconv.FormatInt(r.Size, 10),
+			r.IP,
+			r.ClientID,
+			r.UserID,
+			r.UserAgent,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// visibleExport loads an export, hiding those of other users from non-admins
+func (s *accessLogService) visibleExport(ctx context.Context, id, requester uuid.UUID, role entities.Role) (*entities.AccessLogExport, error) {
+	export, err := s.accessLogRepo.GetExport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if role != entities.RoleAdmin && export.RequestedBy != requester {
+		return nil, apperror.ErrAccessLogExportNotFound
+	}
+	return export, nil
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// defaultCountry is the country of addresses added without one
+const defaultCountry = "ID"
+
+type addressService struct {
+	addressRepo repository.AddressRepository
+}
+
+// NewAddressService creates a new AddressService instance
+func NewAddressService(addressRepo repository.AddressRepository) service.AddressService {
+	return &addressService{
+		addressRepo: addressRepo,
+	}
+}
+
+func (s *addressService) Create(ctx context.Context, userID uuid.UUID, req *dto.CreateAddressRequest) (*dto.AddressResponse, error) {
+	existing, err := s.addressRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	address := &entities.Address{
+		ID:            uuid.New(),
+		UserID:        userID,
+		Label:         strings.TrimSpace(req.Label),
+		RecipientName: strings.TrimSpace(req.RecipientName),
+		Phone:         strings.TrimSpace(req.Phone),
+		Street:        strings.TrimSpace(req.Street),
+		City:          strings.TrimSpace(req.City),
+		Province:      strings.TrimSpace(req.Province),
+		PostalCode:    strings.TrimSpace(req.PostalCode),
+		Country:       strings.ToUpper(req.Country),
+		IsDefault:     req.IsDefault || len(existing) == 0,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if address.Country == "" {
+		address.Country = defaultCountry
+	}
+
+	if err := s.addressRepo.Create(ctx, address); err != nil {
+		return nil, err
+	}
+	response := dto.ToAddressResponse(address)
+	return &response, nil
+}
+
+func (s *addressService) List(ctx context.Context, userID uuid.UUID) ([]dto.AddressResponse, error) {
+	addresses, err := s.addressRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToAddressResponseList(addresses), nil
+}
+
+func (s *addressService) Update(ctx context.Context, id, userID uuid.UUID, req *dto.UpdateAddressRequest) (*dto.AddressResponse, error) {
+	address, err := s.get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Label != nil {
+		address.Label = strings.TrimSpace(*req.Label)
+	}
+	if req.RecipientName != nil {
+		address.RecipientName = strings.TrimSpace(*req.RecipientName)
+	}
+	if req.Phone != nil {
+		address.Phone = strings.TrimSpace(*req.Phone)
+	}
+	if req.Street != nil {
+		address.Street = strings.TrimSpace(*req.Street)
+	}
+	if req.City != nil {
+		address.City = strings.TrimSpace(*req.City)
+	}
+	if req.Province != nil {
+		address.Province = strings.TrimSpace(*req.Province)
+	}
+	if req.PostalCode != nil {
+		address.PostalCode = strings.TrimSpace(*req.PostalCode)
+	}
+	if req.Country != nil {
+		address.Country = strings.ToUpper(*req.Country)
+	}
+	// the default is moved by making another address the default
+	if req.IsDefault != nil && *req.IsDefault {
+		address.IsDefault = true
+	}
+	address.UpdatedAt = time.Now()
+
+	if err := s.addressRepo.Update(ctx, address); err != nil {
+		return nil, err
+	}
+	response := dto.ToAddressResponse(address)
+	return &response, nil
+}
+
+// Delete removes an address; placed orders keep their copy of it
+func (s *addressService) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	if _, err := s.get(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.addressRepo.Delete(ctx, id)
+}
+
+// get returns an address of the user, addresses of others are reported as not found
+func (s *addressService) get(ctx context.Context, id, userID uuid.UUID) (*entities.Address, error) {
+	address, err := s.addressRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if address.UserID != userID {
+		return nil, apperror.ErrAddressNotFound
+	}
+	return address, nil
+}
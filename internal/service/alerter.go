@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"postgresDB/internal/domain/service"
+	"postgresDB/internal/infrastruktur/alert"
+	"postgresDB/pkg/logger"
+)
+
+// JobTypeAlert delivers an operational alert to one alert sink
+const JobTypeAlert = "alert"
+
+// alertDelivery is the payload of a JobTypeAlert job
+type alertDelivery struct {
+	Sink  string      `json:"sink"`
+	Alert alert.Alert `json:"alert"`
+}
+
+// Alerter fires operational alerts. Every alert is logged right away and delivered to
+// each sink by its own job, so a failing sink is retried without repeating the others
+type Alerter struct {
+	jobs  service.JobQueue
+	sinks map[string]alert.Sink
+}
+
+// NewAlerter creates an Alerter delivering to sinks; without sinks alerts are only logged
+func NewAlerter(jobs service.JobQueue, sinks ...alert.Sink) *Alerter {
+	a := &Alerter{
+		jobs:  jobs,
+		sinks: make(map[string]alert.Sink, len(sinks)),
+	}
+	for _, sink := range sinks {
+		a.sinks[sink.Name()] = sink
+	}
+	return a
+}
+
+// Fire logs the alert and queues its delivery to every sink
+func (a *Alerter) Fire(ctx context.Context, al alert.Alert) {
+	args := []any{"name", al.Name, "status", al.Status, "severity", al.Severity, "summary", al.Summary}
+	if al.Status == alert.StatusResolved {
+		logger.InfoContext(ctx, "alert", args...)
+	} else {
+		logger.WarnContext(ctx, "alert", args...)
+	}
+
+	for name := range a.sinks {
+		if _, err := a.jobs.Enqueue(ctx, DefaultJobQueue, JobTypeAlert, alertDelivery{Sink: name, Alert: al}); err != nil {
+			logger.ErrorContext(ctx, "enqueueing alert failed", "name", al.Name, "sink", name, "error", err)
+		}
+	}
+}
+
+// Handle is the JobHandler of JobTypeAlert
+func (a *Alerter) Handle(ctx context.Context, payload json.RawMessage) error {
+	var delivery alertDelivery
+	if err := json.Unmarshal(payload, &delivery); err != nil {
+		return fmt.Errorf("decoding alert: %w", err)
+	}
+	sink, ok := a.sinks[delivery.Sink]
+	if !ok {
+		// the sink was removed from the configuration since the alert was fired
+		logger.WarnContext(ctx, "alert dropped, sink not configured", "name", delivery.Alert.Name, "sink", delivery.Sink)
+		return nil
+	}
+	return sink.Send(ctx, delivery.Alert)
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/logger"
+	"postgresDB/pkg/utils"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxAPIClientsPerUser limits how many clients a user can register
+	maxAPIClientsPerUser = 20
+	// defaultAPIClientUsageRange is the usage window when no range is given
+	defaultAPIClientUsageRange = 30 * 24 * time.Hour
+	apiClientIDPrefix          = "ci_"
+	apiClientSecretPrefix      = "cs_"
+)
+
+type apiClientService struct {
+	clientRepo    repository.APIClientRepository
+	accessLogRepo repository.AccessLogRepository
+}
+
+// NewAPIClientService creates a new APIClientService instance
+func NewAPIClientService(clientRepo repository.APIClientRepository, accessLogRepo repository.AccessLogRepository) service.APIClientService {
+	return &apiClientService{
+		clientRepo:    clientRepo,
+		accessLogRepo: accessLogRepo,
+	}
+}
+
+// Create registers a client for the owner and issues its secret
+func (s *apiClientService) Create(ctx context.Context, ownerID uuid.UUID, req *dto.CreateAPIClientRequest) (*dto.APIClientResponse, error) {
+	if err := validateAPIClient(req.RedirectURIs, req.Scopes); err != nil {
+		return nil, err
+	}
+
+	count, err := s.clientRepo.CountByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxAPIClientsPerUser {
+		return nil, apperror.ErrAPIClientLimit
+	}
+
+	clientID, err := utils.RandomToken(apiClientIDPrefix, 12)
+	if err != nil {
+		return nil, apperror.WrapInternal(err)
+	}
+	secret, err := utils.RandomToken(apiClientSecretPrefix, 32)
+	if err != nil {
+		return nil, apperror.WrapInternal(err)
+	}
+
+	now := time.Now()
+	client := &entities.APIClient{
+		ID:              uuid.New(),
+		OwnerID:         ownerID,
+		ClientID:        clientID,
+		Name:            req.Name,
+		SecretHash:      utils.HashToken(secret),
+		RedirectURIs:    req.RedirectURIs,
+		Scopes:          uniqueScopes(req.Scopes),
+		SecretRotatedAt: now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.clientRepo.Create(ctx, client); err != nil {
+		return nil, err
+	}
+
+	logger.InfoContext(ctx, "API client registered", "client_id", client.ClientID, "owner_id", ownerID)
+	response := dto.ToAPIClientResponse(client)
+	response.ClientSecret = secret
+	return &response, nil
+}
+
+// Get retrieves a client of the requester, or any client for admins
+func (s *apiClientService) Get(ctx context.Context, id, requester uuid.UUID, role entities.Role) (*dto.APIClientResponse, error) {
+	client, err := s.visibleClient(ctx, id, requester, role)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToAPIClientResponse(client)
+	return &response, nil
+}
+
+// List retrieves the clients of the requester, or all clients for admins
+func (s *apiClientService) List(ctx context.Context, requester uuid.UUID, role entities.Role, page, limit int) ([]dto.APIClientResponse, *dto.PaginationMeta, error) {
+	if page <= 1 {
+		page = 1
+	}
+	if limit <= 1 {
+		limit = 10
+	}
+
+	var ownerID *uuid.UUID
+	if role != entities.RoleAdmin {
+		ownerID = &requester
+	}
+
+	clients, total, err := s.clientRepo.List(ctx, ownerID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.PaginationMeta{
+		Total:      total,
+		Limit:      limit,
+		Page:       page,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}
+	return dto.ToAPIClientResponseList(clients), pagination, nil
+}
+
+// Update changes the name, redirect URIs or scopes of a client
+func (s *apiClientService) Update(ctx context.Context, id, requester uuid.UUID, role entities.Role, req *dto.UpdateAPIClientRequest) (*dto.APIClientResponse, error) {
+	client, err := s.visibleClient(ctx, id, requester, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		client.Name = *req.Name
+	}
+	if req.RedirectURIs != nil {
+		client.RedirectURIs = req.RedirectURIs
+	}
+	if req.Scopes != nil {
+		client.Scopes = uniqueScopes(req.Scopes)
+	}
+	if err := validateAPIClient(client.RedirectURIs, client.Scopes); err != nil {
+		return nil, err
+	}
+
+	client.UpdatedAt = time.Now()
+	if err := s.clientRepo.Update(ctx, client); err != nil {
+		return nil, err
+	}
+
+	response := dto.ToAPIClientResponse(client)
+	return &response, nil
+}
+
+// RotateSecret issues a new secret for a client
+func (s *apiClientService) RotateSecret(ctx context.Context, id, requester uuid.UUID, role entities.Role) (*dto.APIClientResponse, error) {
+	client, err := s.visibleClient(ctx, id, requester, role)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := utils.RandomToken(apiClientSecretPrefix, 32)
+	if err != nil {
+		return nil, apperror.WrapInternal(err)
+	}
+	client.SecretHash = utils.HashToken(secret)
+	client.SecretRotatedAt = time.Now()
+	client.UpdatedAt = client.SecretRotatedAt
+	if err := s.clientRepo.UpdateSecret(ctx, client); err != nil {
+		return nil, err
+	}
+
+	logger.InfoContext(ctx, "API client secret rotated", "client_id", client.ClientID, "rotated_by", requester)
+	response := dto.ToAPIClientResponse(client)
+	response.ClientSecret = secret
+	return &response, nil
+}
+
+// Delete removes a client, its past traffic stays in the access logs
+func (s *apiClientService) Delete(ctx context.Context, id, requester uuid.UUID, role entities.Role) error {
+	client, err := s.visibleClient(ctx, id, requester, role)
+	if err != nil {
+		return err
+	}
+	return s.clientRepo.Delete(ctx, client.ID)
+}
+
+// Usage reports the daily traffic of a client, the last 30 days by default
+func (s *apiClientService) Usage(ctx context.Context, id, requester uuid.UUID, role entities.Role, from, to time.Time) (*dto.APIClientUsageResponse, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultAPIClientUsageRange)
+	}
+	if !to.After(from) {
+		return nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "to", Message: "to harus setelah from"},
+		})
+	}
+	if to.Sub(from) > maxAccessLogExportRange {
+		return nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "to", Message: "rentang waktu maksimal %d hari", Args: []any{int(maxAccessLogExportRange.Hours() / 24)}},
+		})
+	}
+
+	client, err := s.visibleClient(ctx, id, requester, role)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := s.accessLogRepo.DailyUsage(ctx, client.ClientID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToAPIClientUsageResponse(client.ClientID, from, to, usage)
+	return &response, nil
+}
+
+// visibleClient loads a client, hiding those of other users from non-admins
+func (s *apiClientService) visibleClient(ctx context.Context, id, requester uuid.UUID, role entities.Role) (*entities.APIClient, error) {
+	client, err := s.clientRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if role != entities.RoleAdmin && client.OwnerID != requester {
+		return nil, apperror.ErrAPIClientNotFound
+	}
+	return client, nil
+}
+
+// validateAPIClient checks the redirect URIs and scopes of a client. Redirect URIs must
+// be absolute https URLs without fragment, plain http is only allowed for loopback hosts
+func validateAPIClient(redirectURIs, scopes []string) error {
+	var details []apperror.ValidationError
+	for i, raw := range redirectURIs {
+		if !validRedirectURI(raw) {
+			details = append(details, apperror.ValidationError{
+				Field:   fmt.Sprintf("redirect_uris[%d]", i),
+				Message: "redirect URI harus berupa URL https absolut tanpa fragment, http hanya untuk localhost",
+			})
+		}
+	}
+	for i, scope := range scopes {
+		if !entities.IsValidScope(scope) {
+			details = append(details, apperror.ValidationError{
+				Field:   fmt.Sprintf("scopes[%d]", i),
+				Message: "scope %s tidak dikenal",
+				Args:    []any{scope},
+			})
+		}
+	}
+	if len(details) > 0 {
+		return apperror.NewValidationError(details)
+	}
+	return nil
+}
+
+func validRedirectURI(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || !u.IsAbs() || u.Host == "" || u.Fragment != "" || u.User != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "https":
+		return true
+	case "http":
+		host := u.Hostname()
+		return host == "localhost" || host == "127.0.0.1" || host == "::1"
+	default:
+		return false
+	}
+}
+
+// uniqueScopes returns the scopes sorted without duplicates
+func uniqueScopes(scopes []string) []string {
+	unique := slices.Clone(scopes)
+	slices.Sort(unique)
+	return slices.Compact(unique)
+}
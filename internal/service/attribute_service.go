@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	localrepo "postgresDB/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type attributeService struct {
+	attributeRepo repository.AttributeRepository
+	categoryRepo  repository.CategoryRepository
+	responseCache localrepo.ResponseCacheRepository
+}
+
+// NewAttributeService creates a new AttributeService instance
+func NewAttributeService(attributeRepo repository.AttributeRepository, categoryRepo repository.CategoryRepository, responseCache localrepo.ResponseCacheRepository) service.AttributeService {
+	return &attributeService{
+		attributeRepo: attributeRepo,
+		categoryRepo:  categoryRepo,
+		responseCache: responseCache,
+	}
+}
+
+// Create defines a new attribute for a category
+func (s *attributeService) Create(ctx context.Context, categoryID uuid.UUID, req *dto.CreateAttributeRequest) (*dto.AttributeResponse, error) {
+	// Category must exist
+	if _, err := s.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		return nil, err
+	}
+
+	def := &entities.AttributeDefinition{
+		ID:         uuid.New(),
+		CategoryID: categoryID,
+		Code:       req.Code,
+		Name:       req.Name,
+		Type:       entities.AttributeType(req.Type),
+		Unit:       req.Unit,
+		Filterable: req.Filterable,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+
+	if err := s.attributeRepo.CreateDefinition(ctx, def); err != nil {
+		return nil, err
+	}
+	invalidateCatalog(ctx, s.responseCache)
+
+	response := dto.ToAttributeResponse(def)
+	return &response, nil
+}
+
+// ListByCategory retrieves the attribute definitions of a category
+func (s *attributeService) ListByCategory(ctx context.Context, categoryID uuid.UUID) ([]dto.AttributeResponse, error) {
+	if _, err := s.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		return nil, err
+	}
+
+	defs, err := s.attributeRepo.ListDefinitionsByCategory(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToAttributeResponseList(defs), nil
+}
+
+// Update updates an attribute definition
+func (s *attributeService) Update(ctx context.Context, id uuid.UUID, req *dto.UpdateAttributeRequest) (*dto.AttributeResponse, error) {
+	def, err := s.attributeRepo.GetDefinitionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Code != nil {
+		def.Code = *req.Code
+	}
+	if req.Name != nil {
+		def.Name = *req.Name
+	}
+	if req.Unit != nil {
+		def.Unit = *req.Unit
+	}
+	if req.Filterable != nil {
+		def.Filterable = *req.Filterable
+	}
+
+	if err := s.attributeRepo.UpdateDefinition(ctx, def); err != nil {
+		return nil, err
+	}
+	invalidateCatalog(ctx, s.responseCache)
+
+	response := dto.ToAttributeResponse(def)
+	return &response, nil
+}
+
+// Delete deletes an attribute definition along with its product values
+func (s *attributeService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.attributeRepo.DeleteDefinition(ctx, id); err != nil {
+		return err
+	}
+	invalidateCatalog(ctx, s.responseCache)
+	return nil
+}
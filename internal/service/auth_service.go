@@ -11,6 +11,7 @@ import (
 	"postgresDB/internal/domain/repository"
 	"postgresDB/internal/domain/service"
 	"postgresDB/pkg/jwt"
+	"postgresDB/pkg/logger"
 	"postgresDB/pkg/utils"
 
 	"github.com/google/uuid"
@@ -20,25 +21,30 @@ import (
 type authService struct {
 	userRepo   repository.UserRepository
 	jwtService *jwt.JWTService
+	hasher     utils.PasswordHasher
 }
 
 // NewAuthService creates a new AuthService instance
-func NewAuthService(userRepo repository.UserRepository, jwtService *jwt.JWTService) service.AuthService {
+func NewAuthService(userRepo repository.UserRepository, jwtService *jwt.JWTService, hasher utils.PasswordHasher) service.AuthService {
 	return &authService{
 		userRepo:   userRepo,
 		jwtService: jwtService,
+		hasher:     hasher,
 	}
 }
 
 // Register handles user registration
 func (s *authService) Register(ctx context.Context, req dto.RegisterRequest) (*dto.RegisterResponse, error) {
+	// Emails are compared case-insensitively
+	email := utils.NormalizeEmail(req.Email)
+
 	// Check if email or username already exists
-	exists, err := s.userRepo.ExistsByEmail(ctx, req.Email)
+	exists, err := s.userRepo.ExistsByEmail(ctx, email)
 	if err != nil {
 		return nil, err
 	}
 	if exists {
-		return nil, apperror.ErrUserAlreadyExists
+		return nil, apperror.ErrEmailExists
 	}
 
 	exists, err = s.userRepo.ExistsByUsername(ctx, req.Username)
@@ -46,11 +52,11 @@ func (s *authService) Register(ctx context.Context, req dto.RegisterRequest) (*d
 		return nil, err
 	}
 	if exists {
-		return nil, apperror.ErrUserAlreadyExists
+		return nil, apperror.ErrUsernameExists
 	}
 
 	// Hash password
-	hashedPassword, err := utils.HashPassword(req.Password)
+	hashedPassword, err := s.hasher.Hash(req.Password)
 	if err != nil {
 		return nil, apperror.WrapInternal(err)
 	}
@@ -63,7 +69,7 @@ func (s *authService) Register(ctx context.Context, req dto.RegisterRequest) (*d
 	newUser := &entities.User{
 		ID:        uuid.New(),
 		Username:  req.Username,
-		Email:     req.Email,
+		Email:     email,
 		Password:  hashedPassword,
 		Role:      role,
 		IsActive:  true,
@@ -75,6 +81,7 @@ func (s *authService) Register(ctx context.Context, req dto.RegisterRequest) (*d
 	if err := s.userRepo.Create(ctx, newUser); err != nil {
 		return nil, err
 	}
+	registrations.Inc()
 
 	// Response
 	return &dto.RegisterResponse{
@@ -84,11 +91,12 @@ func (s *authService) Register(ctx context.Context, req dto.RegisterRequest) (*d
 }
 
 // Login handles user authentication
-func (s *authService) Login(ctx context.Context, req dto.LoginRequest) (*dto.AuthResponse, error) {
+func (s *authService) Login(ctx context.Context, req dto.LoginRequest, device, ip string) (*dto.AuthResponse, error) {
 	// Find user by email or username
 	userEntity, err := s.userRepo.GetByEmailOrUsername(ctx, req.LoginID)
 	if err != nil {
 		if errors.Is(err, apperror.ErrUserNotFound) {
+			logins.With(loginInvalidCredentials).Inc()
 			return nil, apperror.ErrInvalidCredentials
 		}
 		return nil, err
@@ -96,25 +104,38 @@ func (s *authService) Login(ctx context.Context, req dto.LoginRequest) (*dto.Aut
 
 	// Check if user is active
 	if !userEntity.IsActive {
+		logins.With(loginInactive).Inc()
 		return nil, apperror.ErrUserInactive
 	}
 
 	// Verify password
-	if err := utils.CheckPassword(req.Password, userEntity.Password); err != nil {
+	if err := s.hasher.Verify(req.Password, userEntity.Password); err != nil {
+		logins.With(loginInvalidCredentials).Inc()
 		return nil, apperror.ErrInvalidCredentials
 	}
 
-	// Generate tokens
-	tokenPair, err := s.jwtService.GenerateTokenPair(ctx, userEntity.ID, userEntity.Role)
+	// Transparently upgrade legacy, bcrypt or outdated argon2 hashes to the configured algorithm
+	if s.hasher.NeedsRehash(userEntity.Password) {
+		s.rehashPassword(ctx, userEntity, req.Password)
+	}
+
+	// Generate tokens, within the session limit
+	tokenPair, err := s.jwtService.StartSession(ctx, userEntity.ID, userEntity.Role, device, ip)
 	if err != nil {
+		if errors.Is(err, jwt.ErrSessionLimitReached) {
+			logins.With(loginSessionLimit).Inc()
+			return nil, apperror.ErrSessionLimitReached
+		}
 		return nil, apperror.WrapInternal(err)
 	}
+	logins.With(loginSucceeded).Inc()
 
 	// Create response
 	return &dto.AuthResponse{
 		User:         dto.ToUserResponse(userEntity),
 		AccessToken:  tokenPair.AccessToken,
 		RefreshToken: tokenPair.RefreshToken,
+		SigningKey:   s.jwtService.RequestSigningKey(tokenPair.TokenFamily),
 	}, nil
 }
 
@@ -147,6 +168,7 @@ func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (*d
 		User:         dto.ToUserResponse(userEntity),
 		AccessToken:  tokenPair.AccessToken,
 		RefreshToken: tokenPair.RefreshToken,
+		SigningKey:   s.jwtService.RequestSigningKey(tokenPair.TokenFamily),
 	}, nil
 }
 
@@ -158,6 +180,87 @@ func (s *authService) RevokeAllSessions(ctx context.Context, userID uuid.UUID) e
 	return nil
 }
 
+// PruneSessions drops expired sessions left in the session sets of users who didn't log in since
+func (s *authService) PruneSessions(ctx context.Context) error {
+	pruned, err := s.jwtService.PruneExpiredSessions(ctx)
+	if err != nil {
+		return apperror.WrapInternal(err)
+	}
+	if pruned > 0 {
+		logger.InfoContext(ctx, "expired sessions pruned", "count", pruned)
+	}
+	return nil
+}
+
+// ListSessions returns the active sessions of a user and those evicted by the session limit
+func (s *authService) ListSessions(ctx context.Context, userID uuid.UUID) (*dto.SessionListResponse, error) {
+	active, evicted, err := s.jwtService.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, apperror.WrapInternal(err)
+	}
+	return &dto.SessionListResponse{
+		MaxSessions: s.jwtService.MaxSessions(),
+		Sessions:    dto.ToSessionResponseList(active),
+		Evicted:     dto.ToSessionResponseList(evicted),
+	}, nil
+}
+
+// RevokeTokens revokes in bulk every token issued before req.IssuedBefore, for all users,
+// a role or a list of users. Tokens are rejected by comparing their issued-at with the
+// revocation cutoff of their scopes, so no token has to be looked up
+func (s *authService) RevokeTokens(ctx context.Context, adminID uuid.UUID, req dto.RevokeTokensRequest) (*dto.RevokeTokensResponse, error) {
+	now := time.Now()
+	issuedBefore := now
+	if req.IssuedBefore != nil {
+		issuedBefore = *req.IssuedBefore
+	}
+
+	var details []apperror.ValidationError
+	if issuedBefore.After(now) {
+		details = append(details, apperror.ValidationError{Field: "issued_before", Message: "issued_before tidak boleh di masa depan"})
+	}
+	if req.Role != "" && len(req.UserIDs) > 0 {
+		details = append(details, apperror.ValidationError{Field: "role", Message: "role dan user_ids tidak boleh diisi bersamaan"})
+	}
+	if req.Role == "" && len(req.UserIDs) == 0 && req.IssuedBefore == nil {
+		details = append(details, apperror.ValidationError{Field: "issued_before", Message: "issued_before wajib diisi untuk mencabut semua token"})
+	}
+	if len(details) > 0 {
+		return nil, apperror.NewValidationError(details)
+	}
+
+	res := &dto.RevokeTokensResponse{IssuedBefore: issuedBefore}
+	var scopes []string
+	switch {
+	case req.Role != "":
+		res.Scope = "role"
+		res.Role = req.Role
+		scopes = []string{jwt.RoleScope(entities.Role(req.Role))}
+	case len(req.UserIDs) > 0:
+		seen := make(map[uuid.UUID]struct{}, len(req.UserIDs))
+		for _, id := range req.UserIDs {
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			scopes = append(scopes, jwt.UserScope(id))
+		}
+		res.Scope = "users"
+		res.Users = len(scopes)
+	default:
+		res.Scope = "all"
+		scopes = []string{jwt.ScopeAll}
+	}
+
+	if _, err := s.jwtService.RevokeIssuedBefore(ctx, scopes, issuedBefore); err != nil {
+		return nil, apperror.WrapInternal(err)
+	}
+
+	logger.WarnContext(ctx, "Tokens revoked in bulk", "admin_id", adminID.String(), "scope", res.Scope,
+		"role", res.Role, "users", res.Users, "issued_before", issuedBefore)
+	return res, nil
+}
+
 // Logout handles user logout by blacklisting the access token and refresh token
 func (s *authService) Logout(ctx context.Context, accessJTI string, accessExp time.Time, refreshToken string) error {
 	accessTTL := time.Until(accessExp)
@@ -176,7 +279,29 @@ func (s *authService) Logout(ctx context.Context, accessJTI string, accessExp ti
 		if err := s.jwtService.BlacklistToken(ctx, refreshClaims.ID, refreshTTL); err != nil {
 			return apperror.WrapInternal(err)
 		}
+
+		// the session no longer counts against the session limit
+		if refreshClaims.TokenFamily != "" {
+			if err := s.jwtService.EndSession(ctx, refreshClaims.UserID, refreshClaims.TokenFamily); err != nil {
+				return apperror.WrapInternal(err)
+			}
+		}
 	}
 
 	return nil
 }
+
+// rehashPassword re-hashes the password with the current algorithm. Failures are
+// only logged since the user has already been authenticated.
+func (s *authService) rehashPassword(ctx context.Context, user *entities.User, password string) {
+	hashedPassword, err := s.hasher.Hash(password)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to rehash password", "user_id", user.ID.String(), "error", err.Error())
+		return
+	}
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		logger.ErrorContext(ctx, "Failed to save rehashed password", "user_id", user.ID.String(), "error", err.Error())
+		return
+	}
+	user.Password = hashedPassword
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/internal/infrastruktur/alert"
+	"postgresDB/internal/infrastruktur/storage"
+	localrepo "postgresDB/internal/repository"
+	"postgresDB/pkg/logger"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// authStateBackupVersion is the version of the backup file format
+	authStateBackupVersion = 1
+	authStateLostAlertName = "auth_state_lost"
+)
+
+// AuthStateBackupConfig configures scheduled auth state backups
+type AuthStateBackupConfig struct {
+	// Interval is how often a backup is taken, 0 disables scheduled backups
+	Interval time.Duration
+	// Retention is how long backup files are kept, 0 keeps them forever
+	Retention time.Duration
+}
+
+type authStateBackupService struct {
+	tokenRepo  localrepo.TokenRepository
+	backupRepo repository.AuthStateBackupRepository
+	files      storage.Storage
+	alerter    *Alerter
+	cfg        AuthStateBackupConfig
+	// lost is set while Redis holds no auth state although the last backup had some
+	lost bool
+}
+
+// NewAuthStateBackupService creates a new AuthStateBackupService instance. Backup files
+// hold token ids and must be written to private storage
+func NewAuthStateBackupService(tokenRepo localrepo.TokenRepository, backupRepo repository.AuthStateBackupRepository, files storage.Storage, alerter *Alerter, cfg AuthStateBackupConfig) service.AuthStateBackupService {
+	return &authStateBackupService{
+		tokenRepo:  tokenRepo,
+		backupRepo: backupRepo,
+		files:      files,
+		alerter:    alerter,
+		cfg:        cfg,
+	}
+}
+
+// Backup writes the current auth state to storage. Failed backups are recorded too
+func (s *authStateBackupService) Backup(ctx context.Context, trigger string, requestedBy *uuid.UUID) (*dto.AuthStateBackupResponse, error) {
+	entries, err := s.tokenRepo.ExportState(ctx)
+	if err != nil {
+		return nil, s.recordFailure(ctx, trigger, requestedBy, err)
+	}
+	return s.save(ctx, trigger, requestedBy, entries)
+}
+
+// save writes entries as a gzipped JSON file and records the backup
+func (s *authStateBackupService) save(ctx context.Context, trigger string, requestedBy *uuid.UUID, entries []entities.AuthStateEntry) (*dto.AuthStateBackupResponse, error) {
+	backup := &entities.AuthStateBackup{
+		ID:          uuid.New(),
+		Trigger:     trigger,
+		RequestedBy: requestedBy,
+		Status:      entities.AuthStateBackupCompleted,
+		CreatedAt:   time.Now(),
+	}
+	for _, entry := range entries {
+		switch entry.Kind {
+		case entities.AuthStateBlacklist:
+			backup.Blacklisted++
+		case entities.AuthStateFamily:
+			backup.Families++
+		case entities.AuthStateSessions:
+			backup.Sessions++
+		}
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	file := dto.AuthStateBackupFile{Version: authStateBackupVersion, CreatedAt: backup.CreatedAt, Entries: entries}
+	if err := json.NewEncoder(zw).Encode(file); err != nil {
+		return nil, s.recordFailure(ctx, trigger, requestedBy, err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, s.recordFailure(ctx, trigger, requestedBy, err)
+	}
+	backup.Size = int64(buf.Len())
+
+	key := fmt.Sprintf("auth-state/%s/%s.json.gz", backup.CreatedAt.UTC().Format("2006/01"), backup.ID)
+	if _, err := s.files.Save(ctx, key, &buf, "application/gzip"); err != nil {
+		return nil, s.recordFailure(ctx, trigger, requestedBy, err)
+	}
+	backup.StorageKey = key
+
+	if err := s.backupRepo.CreateBackup(ctx, backup); err != nil {
+		return nil, err
+	}
+
+	logger.InfoContext(ctx, "auth state backed up", "backup_id", backup.ID, "trigger", trigger,
+		"blacklisted", backup.Blacklisted, "families", backup.Families, "sessions", backup.Sessions, "size", backup.Size)
+	response := dto.ToAuthStateBackupResponse(backup)
+	return &response, nil
+}
+
+// recordFailure records a failed backup and returns cause as an internal error
+func (s *authStateBackupService) recordFailure(ctx context.Context, trigger string, requestedBy *uuid.UUID, cause error) error {
+	logger.ErrorContext(ctx, "auth state backup failed", "trigger", trigger, "error", cause)
+	backup := &entities.AuthStateBackup{
+		ID:          uuid.New(),
+		Trigger:     trigger,
+		RequestedBy: requestedBy,
+		Status:      entities.AuthStateBackupFailed,
+		Error:       cause.Error(),
+		CreatedAt:   time.Now(),
+	}
+	if err := s.backupRepo.CreateBackup(ctx, backup); err != nil {
+		logger.ErrorContext(ctx, "recording failed auth state backup failed", "error", err)
+	}
+	return apperror.WrapInternal(cause)
+}
+
+// ListBackups retrieves backups newest first
+func (s *authStateBackupService) ListBackups(ctx context.Context, page, limit int) ([]dto.AuthStateBackupResponse, *dto.PaginationMeta, error) {
+	if page <= 1 {
+		page = 1
+	}
+	if limit <= 1 {
+		limit = 10
+	}
+
+	backups, total, err := s.backupRepo.ListBackups(ctx, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.PaginationMeta{
+		Total:      total,
+		Limit:      limit,
+		Page:       page,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}
+	return dto.ToAuthStateBackupResponseList(backups), pagination, nil
+}
+
+// Restore writes the entries of a completed backup back to Redis and records the restore
+func (s *authStateBackupService) Restore(ctx context.Context, id, restoredBy uuid.UUID) (*dto.AuthStateRestoreResponse, error) {
+	backup, err := s.backupRepo.GetBackup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if backup.Status != entities.AuthStateBackupCompleted || backup.StorageKey == "" {
+		return nil, apperror.ErrAuthStateBackupUnavailable
+	}
+
+	file, err := s.readBackup(ctx, backup.StorageKey)
+	if err != nil {
+		return nil, apperror.WrapInternal(err)
+	}
+
+	restored, skipped, err := s.tokenRepo.ImportState(ctx, file.Entries)
+	if err != nil {
+		return nil, apperror.WrapInternal(err)
+	}
+
+	restore := &entities.AuthStateRestore{
+		ID:         uuid.New(),
+		BackupID:   backup.ID,
+		RestoredBy: &restoredBy,
+		Restored:   restored,
+		Skipped:    skipped,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.backupRepo.CreateRestore(ctx, restore); err != nil {
+		return nil, err
+	}
+
+	logger.WarnContext(ctx, "auth state restored from backup", "backup_id", backup.ID, "restored_by", restoredBy,
+		"restored", restored, "skipped", skipped)
+	response := dto.ToAuthStateRestoreResponse(restore)
+	return &response, nil
+}
+
+// readBackup reads and decodes a backup file
+func (s *authStateBackupService) readBackup(ctx context.Context, key string) (*dto.AuthStateBackupFile, error) {
+	rc, err := s.files.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	zr, err := gzip.NewReader(rc)
+	if err != nil {
+		return nil, fmt.Errorf("reading auth state backup: %w", err)
+	}
+	defer zr.Close()
+
+	var file dto.AuthStateBackupFile
+	if err := json.NewDecoder(zr).Decode(&file); err != nil {
+		return nil, fmt.Errorf("decoding auth state backup: %w", err)
+	}
+	if file.Version != authStateBackupVersion {
+		return nil, fmt.Errorf("unsupported auth state backup version %d", file.Version)
+	}
+	return &file, nil
+}
+
+// ListRestores retrieves restores newest first
+func (s *authStateBackupService) ListRestores(ctx context.Context, page, limit int) ([]dto.AuthStateRestoreResponse, *dto.PaginationMeta, error) {
+	if page <= 1 {
+		page = 1
+	}
+	if limit <= 1 {
+		limit = 10
+	}
+
+	restores, total, err := s.backupRepo.ListRestores(ctx, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.PaginationMeta{
+		Total:      total,
+		Limit:      limit,
+		Page:       page,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}
+	return dto.ToAuthStateRestoreResponseList(restores), pagination, nil
+}
+
+// RunSchedule takes a backup on every tick and expires old backups until ctx is done.
+// When Redis is found empty while the last backup wasn't, no backup is taken, so the
+// last good one stays the latest, and an alert fires until the state is back
+func (s *authStateBackupService) RunSchedule(ctx context.Context) {
+	if s.cfg.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.scheduledBackup(ctx)
+		s.expireBackups(ctx)
+	}
+}
+
+// scheduledBackup takes a scheduled backup unless the auth state looks lost
+func (s *authStateBackupService) scheduledBackup(ctx context.Context) {
+	entries, err := s.tokenRepo.ExportState(ctx)
+	if err != nil {
+		s.recordFailure(ctx, entities.AuthStateTriggerScheduled, nil, err)
+		return
+	}
+
+	if len(entries) == 0 {
+		latest, err := s.backupRepo.LatestBackup(ctx)
+		if err != nil {
+			logger.ErrorContext(ctx, "reading latest auth state backup failed", "error", err)
+			return
+		}
+		if latest != nil && latest.Blacklisted+latest.Families+latest.Sessions > 0 {
+			if !s.lost {
+				s.lost = true
+				s.alerter.Fire(ctx, alert.Alert{
+					Name:     authStateLostAlertName,
+					Status:   alert.StatusFiring,
+					Severity: alert.SeverityCritical,
+					Summary:  "Redis holds no sessions or token blacklist, restore the latest auth state backup",
+					Details:  map[string]any{"latest_backup_id": latest.ID.String(), "latest_backup_at": latest.CreatedAt},
+					At:       time.Now(),
+				})
+			}
+			return
+		}
+	}
+
+	if s.lost {
+		s.lost = false
+		s.alerter.Fire(ctx, alert.Alert{
+			Name:     authStateLostAlertName,
+			Status:   alert.StatusResolved,
+			Severity: alert.SeverityCritical,
+			Summary:  "Redis holds sessions or token blacklist entries again",
+			At:       time.Now(),
+		})
+	}
+
+	s.save(ctx, entities.AuthStateTriggerScheduled, nil, entries)
+}
+
+// expireBackups removes the files of backups past retention, their records are kept
+func (s *authStateBackupService) expireBackups(ctx context.Context) {
+	if s.cfg.Retention <= 0 {
+		return
+	}
+
+	keys, err := s.backupRepo.ExpireBackupsBefore(ctx, time.Now().Add(-s.cfg.Retention))
+	if err != nil {
+		logger.ErrorContext(ctx, "expiring old auth state backups failed", "error", err)
+		return
+	}
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if err := s.files.Delete(ctx, key); err != nil {
+			logger.ErrorContext(ctx, "removing auth state backup file failed", "key", key, "error", err)
+		}
+	}
+	if len(keys) > 0 {
+		logger.InfoContext(ctx, "old auth state backups expired", "count", len(keys))
+	}
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/internal/infrastruktur/mail"
+	"postgresDB/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// JobTypeBackInStock emails the subscribers of a product that is back in stock
+	JobTypeBackInStock = "back_in_stock"
+
+	backInStockTemplateKey = "back-in-stock"
+	// backInStockBatch is the number of subscribers emailed before their subscriptions
+	// are cleared
+	backInStockBatch = 200
+)
+
+// backInStock is the payload of a JobTypeBackInStock job
+type backInStock struct {
+	ProductID uuid.UUID `json:"product_id"`
+}
+
+// BackInStockNotifier emails the users subscribed to a product once it is back in stock
+type BackInStockNotifier struct {
+	productRepo      repository.ProductRepository
+	subscriptionRepo repository.StockSubscriptionRepository
+	templates        service.EmailTemplateService
+	consents         service.ConsentChecker
+	mailer           mail.Mailer
+}
+
+// NewBackInStockNotifier creates a BackInStockNotifier. mailer may be nil, subscriptions
+// are then kept until email is configured
+func NewBackInStockNotifier(productRepo repository.ProductRepository, subscriptionRepo repository.StockSubscriptionRepository, templates service.EmailTemplateService, consents service.ConsentChecker, mailer mail.Mailer) *BackInStockNotifier {
+	return &BackInStockNotifier{
+		productRepo:      productRepo,
+		subscriptionRepo: subscriptionRepo,
+		templates:        templates,
+		consents:         consents,
+		mailer:           mailer,
+	}
+}
+
+// Handle is the JobHandler of JobTypeBackInStock. Subscriptions are cleared as soon as
+// their user has been emailed, so a retry only emails the users that were missed
+func (n *BackInStockNotifier) Handle(ctx context.Context, payload json.RawMessage) error {
+	var job backInStock
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return fmt.Errorf("decoding back in stock job: %w", err)
+	}
+	if n.mailer == nil {
+		logger.WarnContext(ctx, "back in stock notification skipped, no email configured", "product_id", job.ProductID)
+		return nil
+	}
+
+	product, err := n.productRepo.GetByID(ctx, job.ProductID)
+	if errors.Is(err, apperror.ErrProductNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// sold out again before the job ran, the subscribers keep waiting
+	if product.Stock <= 0 {
+		return nil
+	}
+
+	for {
+		subscribers, err := n.subscriptionRepo.ListSubscribers(ctx, product.ID, backInStockBatch)
+		if err != nil {
+			return err
+		}
+
+		notified := make([]uuid.UUID, 0, len(subscribers))
+		var errs []error
+		withdrawn := 0
+		for _, subscriber := range subscribers {
+			// back-in-stock emails are marketing, users who withdrew consent since
+			// subscribing are dropped without an email
+			granted, err := n.consents.HasConsent(ctx, subscriber.UserID, entities.ConsentMarketingEmail)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("checking consent of %s: %w", subscriber.UserID, err))
+				continue
+			}
+			if !granted {
+				notified = append(notified, subscriber.UserID)
+				withdrawn++
+				continue
+			}
+			if err := n.email(ctx, product, subscriber); err != nil {
+				errs = append(errs, fmt.Errorf("mailing %s: %w", subscriber.Email, err))
+				continue
+			}
+			notified = append(notified, subscriber.UserID)
+		}
+		if len(notified) > 0 {
+			if err := n.subscriptionRepo.Remove(ctx, product.ID, notified); err != nil {
+				return err
+			}
+			logger.InfoContext(ctx, "back in stock notifications sent", "product_id", product.ID, "users", len(notified)-withdrawn, "without_consent", withdrawn)
+		}
+		if len(errs) > 0 {
+			return errors.Join(errs...)
+		}
+		if len(subscribers) < backInStockBatch {
+			return nil
+		}
+	}
+}
+
+// email sends the back-in-stock template to a subscriber
+func (n *BackInStockNotifier) email(ctx context.Context, product *entities.Product, subscriber entities.StockSubscriber) error {
+	rendered, err := n.templates.Render(ctx, backInStockTemplateKey, "", map[string]any{
+		"Name":        subscriber.Username,
+		"ProductID":   product.ID.String(),
+		"ProductName": product.Name,
+		"Stock":       product.Stock,
+	})
+	if err != nil {
+		return err
+	}
+	return n.mailer.Send(ctx, mail.Message{
+		To:      subscriber.Email,
+		Subject: rendered.Subject,
+		HTML:    rendered.HTML,
+		Text:    rendered.Text,
+	})
+}
+
+// backInStockHook decorates a ProductRepository and enqueues a JobTypeBackInStock job
+// when a stock write leaves a product with subscribers in stock. The write has already
+// succeeded, so a failed enqueue is only logged
+type backInStockHook struct {
+	repository.ProductRepository
+	subscriptionRepo repository.StockSubscriptionRepository
+	jobs             service.JobService
+}
+
+// NewBackInStockHook wraps a product repository so restocks notify the subscribers
+func NewBackInStockHook(next repository.ProductRepository, subscriptionRepo repository.StockSubscriptionRepository, jobs service.JobService) repository.ProductRepository {
+	return &backInStockHook{ProductRepository: next, subscriptionRepo: subscriptionRepo, jobs: jobs}
+}
+
+func (h *backInStockHook) UpdateStock(ctx context.Context, id uuid.UUID, delta int) (*entities.StockLevel, error) {
+	level, err := h.ProductRepository.UpdateStock(ctx, id, delta)
+	if err != nil {
+		return nil, err
+	}
+	if level.Previous <= 0 && level.Current > 0 {
+		h.restocked(ctx, level.ProductID)
+	}
+	return level, nil
+}
+
+func (h *backInStockHook) Update(ctx context.Context, product *entities.Product) error {
+	if err := h.ProductRepository.Update(ctx, product); err != nil {
+		return err
+	}
+	if product.Stock > 0 {
+		h.restocked(ctx, product.ID)
+	}
+	return nil
+}
+
+func (h *backInStockHook) UpsertBySKU(ctx context.Context, products []*entities.Product, batchSize int) ([]bool, error) {
+	created, err := h.ProductRepository.UpsertBySKU(ctx, products, batchSize)
+	if err != nil {
+		return created, err
+	}
+	ids := make([]uuid.UUID, 0, len(products))
+	for _, product := range products {
+		if product.Stock > 0 {
+			ids = append(ids, product.ID)
+		}
+	}
+	h.restocked(ctx, ids...)
+	return created, nil
+}
+
+func (h *backInStockHook) RestoreFromSnapshot(ctx context.Context, items []entities.SnapshotItem, restorePrice, restoreStock bool) (int, error) {
+	restored, err := h.ProductRepository.RestoreFromSnapshot(ctx, items, restorePrice, restoreStock)
+	if err != nil || !restoreStock {
+		return restored, err
+	}
+	ids := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		if item.Stock > 0 {
+			ids = append(ids, item.ProductID)
+		}
+	}
+	h.restocked(ctx, ids...)
+	return restored, nil
+}
+
+// restocked enqueues a notification for the products in stock that have subscribers;
+// subscriptions only exist while a product is out of stock, so any left were waiting
+func (h *backInStockHook) restocked(ctx context.Context, ids ...uuid.UUID) {
+	if len(ids) == 0 {
+		return
+	}
+	waiting, err := h.subscriptionRepo.ProductsWithSubscribers(ctx, ids)
+	if err != nil {
+		logger.ErrorContext(ctx, "checking stock subscriptions failed", "products", len(ids), "error", err)
+		return
+	}
+	for _, id := range waiting {
+		if _, err := h.jobs.Enqueue(ctx, DefaultJobQueue, JobTypeBackInStock, backInStock{ProductID: id}); err != nil {
+			logger.ErrorContext(ctx, "enqueueing back in stock notification failed", "product_id", id, "error", err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"net/http"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/pkg/metrics"
+)
+
+// Business KPI metrics for dashboards and conversion alerts. Labels only ever take
+// values from small fixed sets (order statuses, outcomes), never IDs or user input
+var (
+	ordersCreated  = metrics.NewCounter("business_orders_created_total", "Number of orders placed")
+	ordersRejected = metrics.NewCounterVec("business_orders_rejected_total",
+		"Number of order attempts rejected before being placed", "reason")
+	orderValue = metrics.NewHistogram("business_order_value", "Total amount of placed orders",
+		[]float64{50_000, 100_000, 250_000, 500_000, 1_000_000, 2_500_000, 5_000_000, 10_000_000})
+	orderStatusChanges = metrics.NewCounterVec("business_order_status_changes_total",
+		"Number of orders moved to a status", "status")
+	paymentCaptures = metrics.NewCounterVec("business_payment_captures_total",
+		"Number of payment capture attempts by outcome (captured, declined, expired, pending)", "outcome")
+	stockouts        = metrics.NewCounter("business_stockouts_total", "Number of times an order sold out a product")
+	registrations    = metrics.NewCounter("business_registrations_total", "Number of users registered")
+	checkoutAttempts = metrics.NewCounterVec("business_checkout_attempts_total",
+		"Number of checkout attempts by stage (order, payment) and outcome (succeeded, failed)", "stage", "outcome")
+	checkoutFailureRate = metrics.NewGauge("business_checkout_failure_rate",
+		"Checkout failure rate over the anomaly detection window")
+	ordersExpired = metrics.NewCounter("business_orders_expired_total",
+		"Number of unpaid orders cancelled because their stock reservation expired")
+	refundOutcomes = metrics.NewCounterVec("business_refunds_total",
+		"Number of refunds by outcome (succeeded, failed)", "outcome")
+	logins = metrics.NewCounterVec("business_logins_total",
+		"Number of login attempts by outcome (succeeded, invalid_credentials, inactive, session_limit)", "outcome")
+)
+
+// Outcomes of business_logins_total
+const (
+	loginSucceeded          = "succeeded"
+	loginInvalidCredentials = "invalid_credentials"
+	loginInactive           = "inactive"
+	loginSessionLimit       = "session_limit"
+)
+
+// Reasons of business_orders_rejected_total
+const (
+	orderRejectedOutOfStock      = "insufficient_stock"
+	orderRejectedProductNotFound = "product_not_found"
+	orderRejectedHook            = "hook"
+)
+
+// Labels of business_checkout_attempts_total
+const (
+	checkoutStageOrder   = "order"
+	checkoutStagePayment = "payment"
+	checkoutSucceeded    = "succeeded"
+	checkoutFailed       = "failed"
+)
+
+// recordCheckout counts a checkout attempt of stage. Errors the customer can fix, like an
+// out of stock item, aren't counted, only those the shop has to
+func recordCheckout(stage string, err error) {
+	if err == nil {
+		checkoutAttempts.With(stage, checkoutSucceeded).Inc()
+		return
+	}
+	if appErr, ok := apperror.AsAppError(err); ok && appErr.HTTPStatus < http.StatusInternalServerError {
+		return
+	}
+	checkoutAttempts.With(stage, checkoutFailed).Inc()
+}
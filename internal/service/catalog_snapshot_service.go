@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/logger"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type catalogSnapshotService struct {
+	snapshotRepo repository.CatalogSnapshotRepository
+	productRepo  repository.ProductRepository
+	// dailyAt is the time of day, as an offset from midnight, of the scheduled snapshot
+	dailyAt   time.Duration
+	retention time.Duration
+}
+
+// NewCatalogSnapshotService creates a new CatalogSnapshotService instance. Snapshots older
+// than retention are removed after each scheduled run; zero keeps them forever
+func NewCatalogSnapshotService(snapshotRepo repository.CatalogSnapshotRepository, productRepo repository.ProductRepository, dailyAt, retention time.Duration) service.CatalogSnapshotService {
+	return &catalogSnapshotService{
+		snapshotRepo: snapshotRepo,
+		productRepo:  productRepo,
+		dailyAt:      dailyAt,
+		retention:    retention,
+	}
+}
+
+// Capture takes a snapshot of the current catalog prices and stock
+func (s *catalogSnapshotService) Capture(ctx context.Context, trigger string) (*dto.CatalogSnapshotResponse, error) {
+	items, err := s.currentItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &entities.CatalogSnapshot{
+		ID:           uuid.New(),
+		Trigger:      trigger,
+		ProductCount: len(items),
+		Items:        items,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.snapshotRepo.Create(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	response := dto.ToCatalogSnapshotResponse(snapshot)
+	return &response, nil
+}
+
+// List retrieves snapshots newest first
+func (s *catalogSnapshotService) List(ctx context.Context, page, limit int) ([]dto.CatalogSnapshotResponse, *dto.PaginationMeta, error) {
+	if page <= 1 {
+		page = 1
+	}
+	if limit <= 1 {
+		limit = 10
+	}
+
+	snapshots, total, err := s.snapshotRepo.List(ctx, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.PaginationMeta{
+		Total:      total,
+		Limit:      limit,
+		Page:       page,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}
+	return dto.ToCatalogSnapshotResponseList(snapshots), pagination, nil
+}
+
+// Diff compares two snapshots, or a snapshot against the current catalog
+func (s *catalogSnapshotService) Diff(ctx context.Context, from uuid.UUID, to *uuid.UUID) (*dto.CatalogDiffResponse, error) {
+	fromSnapshot, err := s.snapshotRepo.GetByID(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.CatalogDiffResponse{From: from.String()}
+	var toItems []entities.SnapshotItem
+	if to != nil {
+		toSnapshot, err := s.snapshotRepo.GetByID(ctx, *to)
+		if err != nil {
+			return nil, err
+		}
+		toItems = toSnapshot.Items
+		response.To = to.String()
+	} else if toItems, err = s.currentItems(ctx); err != nil {
+		return nil, err
+	}
+
+	diffs := entities.DiffSnapshotItems(fromSnapshot.Items, toItems)
+	response.Total = len(diffs)
+	response.Changes = dto.ToSnapshotDiffResponseList(diffs)
+	return response, nil
+}
+
+// Rollback restores the selected products to their snapshot values. A snapshot of the
+// catalog is taken first so the rollback itself can be undone
+func (s *catalogSnapshotService) Rollback(ctx context.Context, id uuid.UUID, req *dto.SnapshotRollbackRequest) (*dto.SnapshotRollbackResponse, error) {
+	snapshot, err := s.snapshotRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	restorePrice := len(req.Fields) == 0 || slices.Contains(req.Fields, "price")
+	restoreStock := len(req.Fields) == 0 || slices.Contains(req.Fields, "stock")
+
+	selected := make(map[uuid.UUID]bool, len(req.ProductIDs))
+	for _, id := range req.ProductIDs {
+		selected[id] = true
+	}
+	items := make([]entities.SnapshotItem, 0, len(req.ProductIDs))
+	for _, item := range snapshot.Items {
+		if selected[item.ProductID] {
+			items = append(items, item)
+		}
+	}
+
+	backup, err := s.Capture(ctx, entities.SnapshotTriggerPreRollback)
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := s.productRepo.RestoreFromSnapshot(ctx, items, restorePrice, restoreStock)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.InfoContext(ctx, "catalog snapshot rolled back", "snapshot_id", id, "restored", restored, "backup_snapshot_id", backup.ID)
+	return &dto.SnapshotRollbackResponse{Restored: restored, BackupSnapshotID: backup.ID}, nil
+}
+
+// RunSchedule takes a snapshot every day at the configured time until ctx is done
+func (s *catalogSnapshotService) RunSchedule(ctx context.Context) {
+	for {
+		now := time.Now()
+		timer := time.NewTimer(nextDailyRun(now, s.dailyAt).Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		snapshot, err := s.Capture(ctx, entities.SnapshotTriggerScheduled)
+		if err != nil {
+			logger.ErrorContext(ctx, "scheduled catalog snapshot failed", "error", err)
+			continue
+		}
+		logger.InfoContext(ctx, "catalog snapshot taken", "snapshot_id", snapshot.ID, "products", snapshot.ProductCount)
+
+		if s.retention > 0 {
+			removed, err := s.snapshotRepo.DeleteOlderThan(ctx, time.Now().Add(-s.retention))
+			if err != nil {
+				logger.ErrorContext(ctx, "removing old catalog snapshots failed", "error", err)
+			} else if removed > 0 {
+				logger.InfoContext(ctx, "old catalog snapshots removed", "count", removed)
+			}
+		}
+	}
+}
+
+// currentItems reads the current catalog as snapshot items
+func (s *catalogSnapshotService) currentItems(ctx context.Context) ([]entities.SnapshotItem, error) {
+	products, err := s.productRepo.FindAll(ctx, repository.ProductFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]entities.SnapshotItem, len(products))
+	for i, p := range products {
+		items[i] = entities.SnapshotItem{ProductID: p.ID, Name: p.Name, Price: p.Price, Stock: p.Stock}
+	}
+	return items, nil
+}
+
+// nextDailyRun returns the next time after now that is at the given offset from midnight
+func nextDailyRun(now time.Time, at time.Duration) time.Time {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	next := midnight.Add(at)
+	if !next.After(now) {
+		next = midnight.AddDate(0, 0, 1).Add(at)
+	}
+	return next
+}
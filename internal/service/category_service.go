@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	localrepo "postgresDB/internal/repository"
+	"postgresDB/pkg/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type categoryService struct {
+	categoryRepo  repository.CategoryRepository
+	responseCache localrepo.ResponseCacheRepository
+}
+
+// NewCategoryService creates a new CategoryService instance
+func NewCategoryService(categoryRepo repository.CategoryRepository, responseCache localrepo.ResponseCacheRepository) service.CategoryService {
+	return &categoryService{
+		categoryRepo:  categoryRepo,
+		responseCache: responseCache,
+	}
+}
+
+// Create creates a new category
+func (s *categoryService) Create(ctx context.Context, req *dto.CreateCategoryRequest) (*dto.CategoryResponse, error) {
+	slug := req.Slug
+	if slug == "" {
+		slug = utils.Slugify(req.Name)
+	}
+	if slug == "" {
+		return nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "slug", Message: "slug tidak dapat dibuat dari nama kategori"},
+		})
+	}
+
+	// Parent must exist
+	if req.ParentID != nil {
+		if _, err := s.categoryRepo.GetByID(ctx, *req.ParentID); err != nil {
+			if errors.Is(err, apperror.ErrCategoryNotFound) {
+				return nil, apperror.ErrInvalidCategoryParent
+			}
+			return nil, err
+		}
+	}
+
+	category := &entities.Category{
+		ID:          uuid.New(),
+		ParentID:    req.ParentID,
+		Name:        req.Name,
+		Slug:        slug,
+		Description: req.Description,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := s.categoryRepo.Create(ctx, category); err != nil {
+		return nil, err
+	}
+	invalidateCatalog(ctx, s.responseCache)
+
+	response := dto.ToCategoryResponse(category)
+	return &response, nil
+}
+
+// GetByID retrieves a category by its ID
+func (s *categoryService) GetByID(ctx context.Context, id uuid.UUID) (*dto.CategoryResponse, error) {
+	category, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToCategoryResponse(category)
+	return &response, nil
+}
+
+// List retrieves all categories as a flat list
+func (s *categoryService) List(ctx context.Context) ([]dto.CategoryResponse, error) {
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToCategoryResponseList(categories), nil
+}
+
+// Tree retrieves all categories as a nested tree for storefront navigation
+func (s *categoryService) Tree(ctx context.Context) ([]dto.CategoryTreeResponse, error) {
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToCategoryTree(categories), nil
+}
+
+// Update updates an existing category
+func (s *categoryService) Update(ctx context.Context, id uuid.UUID, req *dto.UpdateCategoryRequest) (*dto.CategoryResponse, error) {
+	category, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		category.Name = *req.Name
+	}
+	if req.Slug != nil {
+		category.Slug = *req.Slug
+	}
+	if req.Description != nil {
+		category.Description = *req.Description
+	}
+	if req.RemoveParent {
+		category.ParentID = nil
+	} else if req.ParentID != nil {
+		if err := s.checkParent(ctx, id, *req.ParentID); err != nil {
+			return nil, err
+		}
+		category.ParentID = req.ParentID
+	}
+
+	if err := s.categoryRepo.Update(ctx, category); err != nil {
+		return nil, err
+	}
+	invalidateCatalog(ctx, s.responseCache)
+
+	response := dto.ToCategoryResponse(category)
+	return &response, nil
+}
+
+// Delete deletes a category by its ID
+func (s *categoryService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.categoryRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	invalidateCatalog(ctx, s.responseCache)
+	return nil
+}
+
+// checkParent ensures the new parent exists and is not the category itself or one of its descendants
+func (s *categoryService) checkParent(ctx context.Context, id, parentID uuid.UUID) error {
+	if id == parentID {
+		return apperror.ErrInvalidCategoryParent
+	}
+
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	parents := make(map[uuid.UUID]*uuid.UUID, len(categories))
+	for _, c := range categories {
+		parents[c.ID] = c.ParentID
+	}
+
+	if _, ok := parents[parentID]; !ok {
+		return apperror.ErrInvalidCategoryParent
+	}
+
+	// Walk up from the new parent; reaching the category means a cycle
+	for current := &parentID; current != nil; current = parents[*current] {
+		if *current == id {
+			return apperror.ErrInvalidCategoryParent
+		}
+	}
+	return nil
+}
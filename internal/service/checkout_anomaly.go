@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"postgresDB/internal/infrastruktur/alert"
+	"time"
+)
+
+const checkoutAlertName = "checkout_failure_rate"
+
+// CheckoutAnomalyConfig controls when a rise in checkout failures is alerted
+type CheckoutAnomalyConfig struct {
+	// Interval is how often the failure rate is evaluated
+	Interval time.Duration
+	// Window is the recent period whose failure rate is checked
+	Window time.Duration
+	// Baseline is the period before Window the failure rate is compared with
+	Baseline time.Duration
+	// Sensitivity is how many times the baseline failure rate the window must reach
+	Sensitivity float64
+	// MinAttempts is the fewest attempts in the window, and in the baseline to use it,
+	// so a handful of failures at night doesn't page anyone
+	MinAttempts int
+	// MinFailureRate is the lowest failure rate alerted, whatever the baseline
+	MinFailureRate float64
+	// Cooldown is the least time between two alerts
+	Cooldown time.Duration
+}
+
+// checkoutSample is a reading of the checkout counters
+type checkoutSample struct {
+	at       time.Time
+	attempts float64
+	failures float64
+}
+
+// CheckoutAnomalyDetector compares the checkout failure rate of a rolling window against
+// the rate of the period before it and alerts when failures spike, e.g. when the payment
+// gateway goes down. It reads the checkout counters of this instance only
+type CheckoutAnomalyDetector struct {
+	cfg     CheckoutAnomalyConfig
+	alerter *Alerter
+
+	samples   []checkoutSample
+	firing    bool
+	lastFired time.Time
+}
+
+// NewCheckoutAnomalyDetector creates a CheckoutAnomalyDetector alerting through alerter
+func NewCheckoutAnomalyDetector(cfg CheckoutAnomalyConfig, alerter *Alerter) *CheckoutAnomalyDetector {
+	return &CheckoutAnomalyDetector{
+		cfg:     cfg,
+		alerter: alerter,
+	}
+}
+
+// Run evaluates the failure rate on every tick until ctx is done
+func (d *CheckoutAnomalyDetector) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			d.evaluate(ctx, now)
+		}
+	}
+}
+
+// evaluate records a sample and fires or resolves the alert
+func (d *CheckoutAnomalyDetector) evaluate(ctx context.Context, now time.Time) {
+	d.record(now)
+
+	latest := d.samples[len(d.samples)-1]
+	windowStart := d.sampleAt(now.Add(-d.cfg.Window))
+	baselineStart := d.sampleAt(now.Add(-d.cfg.Window - d.cfg.Baseline))
+
+	attempts := latest.attempts - windowStart.attempts
+	rate := failureRate(latest.failures-windowStart.failures, attempts)
+	baselineAttempts := windowStart.attempts - baselineStart.attempts
+	baselineRate := failureRate(windowStart.failures-baselineStart.failures, baselineAttempts)
+	checkoutFailureRate.Set(rate)
+
+	anomalous := attempts >= float64(d.cfg.MinAttempts) && rate >= d.cfg.MinFailureRate &&
+		(baselineAttempts < float64(d.cfg.MinAttempts) || rate >= baselineRate*d.cfg.Sensitivity)
+
+	details := map[string]any{
+		"failure_rate":  rate,
+		"baseline_rate": baselineRate,
+		"attempts":      int(attempts),
+		"window":        d.cfg.Window.String(),
+	}
+	switch {
+	case anomalous && !d.firing && now.Sub(d.lastFired) >= d.cfg.Cooldown:
+		d.firing = true
+		d.lastFired = now
+		d.alerter.Fire(ctx, alert.Alert{
+			Name:     checkoutAlertName,
+			Status:   alert.StatusFiring,
+			Severity: alert.SeverityCritical,
+			Summary: fmt.Sprintf("Checkout failure rate is %.0f%% over the last %s, against %.0f%% before",
+				rate*100, d.cfg.Window, baselineRate*100),
+			Details: details,
+			At:      now,
+		})
+	case !anomalous && d.firing:
+		d.firing = false
+		d.alerter.Fire(ctx, alert.Alert{
+			Name:     checkoutAlertName,
+			Status:   alert.StatusResolved,
+			Severity: alert.SeverityCritical,
+			Summary:  fmt.Sprintf("Checkout failure rate is back to %.0f%% over the last %s", rate*100, d.cfg.Window),
+			Details:  details,
+			At:       now,
+		})
+	}
+}
+
+// record appends a reading of the counters and drops the samples older than needed
+func (d *CheckoutAnomalyDetector) record(now time.Time) {
+	var sample checkoutSample
+	sample.at = now
+	for _, stage := range []string{checkoutStageOrder, checkoutStagePayment} {
+		succeeded := checkoutAttempts.With(stage, checkoutSucceeded).Value()
+		failed := checkoutAttempts.With(stage, checkoutFailed).Value()
+		sample.attempts += succeeded + failed
+		sample.failures += failed
+	}
+	d.samples = append(d.samples, sample)
+
+	// keep the last sample before the horizon, it's where the baseline starts
+	horizon := now.Add(-d.cfg.Window - d.cfg.Baseline)
+	keep := 0
+	for keep+1 < len(d.samples) && !d.samples[keep+1].at.After(horizon) {
+		keep++
+	}
+	d.samples = d.samples[keep:]
+}
+
+// sampleAt returns the latest sample taken at or before t, or the oldest one while the
+// detector hasn't run that long
+func (d *CheckoutAnomalyDetector) sampleAt(t time.Time) checkoutSample {
+	sample := d.samples[0]
+	for _, s := range d.samples {
+		if s.at.After(t) {
+			break
+		}
+		sample = s
+	}
+	return sample
+}
+
+func failureRate(failures, attempts float64) float64 {
+	if attempts <= 0 {
+		return 0
+	}
+	return failures / attempts
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+// maxConsentUserAgent is the longest User-Agent kept on a consent record
+const maxConsentUserAgent = 255
+
+type consentService struct {
+	consentRepo repository.ConsentRepository
+	userRepo    repository.UserRepository
+}
+
+// NewConsentService creates a new ConsentService instance
+func NewConsentService(consentRepo repository.ConsentRepository, userRepo repository.UserRepository) service.ConsentService {
+	return &consentService{
+		consentRepo: consentRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// HasConsent returns the consent the user chose for purpose, or its default
+func (s *consentService) HasConsent(ctx context.Context, userID uuid.UUID, purpose entities.ConsentPurpose) (bool, error) {
+	consent, err := s.consentRepo.Get(ctx, userID, purpose)
+	if errors.Is(err, apperror.ErrConsentNotFound) {
+		return purpose.Default(), nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return consent.Granted, nil
+}
+
+// List returns the consents of the user, filling in the defaults of purposes never chosen
+func (s *consentService) List(ctx context.Context, userID uuid.UUID) ([]dto.ConsentResponse, error) {
+	chosen, err := s.consentRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byPurpose := make(map[entities.ConsentPurpose]*entities.Consent, len(chosen))
+	for _, c := range chosen {
+		byPurpose[c.Purpose] = c
+	}
+
+	consents := make([]*entities.Consent, len(entities.ConsentPurposes))
+	for i, purpose := range entities.ConsentPurposes {
+		if c, ok := byPurpose[purpose]; ok {
+			consents[i] = c
+			continue
+		}
+		consents[i] = &entities.Consent{UserID: userID, Purpose: purpose, Granted: purpose.Default()}
+	}
+	return dto.ToConsentResponseList(consents), nil
+}
+
+// Update records every purpose set in the request, unchanged choices included, since a
+// repeated choice is a new confirmation for compliance
+func (s *consentService) Update(ctx context.Context, userID uuid.UUID, req *dto.UpdateConsentsRequest, ip, userAgent string) ([]dto.ConsentResponse, error) {
+	choices := req.Choices()
+	if len(choices) == 0 {
+		return nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "consents", Message: "minimal satu persetujuan harus diisi"},
+		})
+	}
+	if len(userAgent) > maxConsentUserAgent {
+		userAgent = userAgent[:maxConsentUserAgent]
+	}
+
+	records := make([]*entities.ConsentRecord, 0, len(choices))
+	for _, purpose := range entities.ConsentPurposes {
+		granted, ok := choices[purpose]
+		if !ok {
+			continue
+		}
+		records = append(records, &entities.ConsentRecord{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Purpose:   purpose,
+			Granted:   granted,
+			IP:        ip,
+			UserAgent: userAgent,
+		})
+	}
+	if err := s.consentRepo.Record(ctx, records); err != nil {
+		return nil, err
+	}
+	return s.List(ctx, userID)
+}
+
+// History retrieves the consent records of a user, newest first
+func (s *consentService) History(ctx context.Context, userID uuid.UUID, page, limit int) ([]dto.ConsentRecordResponse, *dto.PaginationMeta, error) {
+	if page <= 1 {
+		page = 1
+	}
+	if limit <= 1 {
+		limit = 10
+	}
+
+	// the records of a deleted user stay readable
+	if _, err := s.userRepo.GetByIDScoped(ctx, userID, repository.DeletedInclude); err != nil {
+		return nil, nil, err
+	}
+	records, total, err := s.consentRepo.ListRecords(ctx, userID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.PaginationMeta{
+		Total:      total,
+		Limit:      limit,
+		Page:       page,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}
+	return dto.ToConsentRecordResponseList(records), pagination, nil
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	htmltemplate "html/template"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/internal/infrastruktur/mail"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// testEmailPrefix marks test sends so they aren't mistaken for real notifications
+const testEmailPrefix = "[TEST] "
+
+type emailTemplateService struct {
+	templateRepo  repository.EmailTemplateRepository
+	mailer        mail.Mailer
+	defaultLocale string
+}
+
+// NewEmailTemplateService creates a new EmailTemplateService instance. mailer may be nil
+// while no SMTP server is configured, test sends are then refused
+func NewEmailTemplateService(templateRepo repository.EmailTemplateRepository, mailer mail.Mailer, defaultLocale string) service.EmailTemplateService {
+	return &emailTemplateService{
+		templateRepo:  templateRepo,
+		mailer:        mailer,
+		defaultLocale: defaultLocale,
+	}
+}
+
+// Create stores a new draft version of the template
+func (s *emailTemplateService) Create(ctx context.Context, adminID uuid.UUID, req *dto.CreateEmailTemplateRequest) (*dto.EmailTemplateResponse, error) {
+	if _, details := parseEmailTemplate(req.Subject, req.HTMLBody, req.TextBody); len(details) > 0 {
+		return nil, apperror.NewValidationError(details)
+	}
+
+	now := time.Now()
+	template := &entities.EmailTemplate{
+		ID:        uuid.New(),
+		Key:       req.Key,
+		Locale:    req.Locale,
+		Subject:   req.Subject,
+		HTMLBody:  req.HTMLBody,
+		TextBody:  req.TextBody,
+		Status:    entities.EmailTemplateDraft,
+		CreatedBy: &adminID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return nil, err
+	}
+
+	response := dto.ToEmailTemplateResponse(template)
+	return &response, nil
+}
+
+// Get retrieves a template version by ID
+func (s *emailTemplateService) Get(ctx context.Context, id uuid.UUID) (*dto.EmailTemplateResponse, error) {
+	template, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToEmailTemplateResponse(template)
+	return &response, nil
+}
+
+// List retrieves template versions, optionally filtered by key and locale
+func (s *emailTemplateService) List(ctx context.Context, key, locale string, page, limit int) ([]dto.EmailTemplateResponse, *dto.PaginationMeta, error) {
+	if page <= 1 {
+		page = 1
+	}
+	if limit <= 1 {
+		limit = 10
+	}
+
+	templates, total, err := s.templateRepo.List(ctx, key, locale, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.PaginationMeta{
+		Total:      total,
+		Limit:      limit,
+		Page:       page,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}
+	return dto.ToEmailTemplateResponseList(templates), pagination, nil
+}
+
+// Publish activates a template version
+func (s *emailTemplateService) Publish(ctx context.Context, id uuid.UUID) (*dto.EmailTemplateResponse, error) {
+	template, err := s.templateRepo.Publish(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToEmailTemplateResponse(template)
+	return &response, nil
+}
+
+// Preview renders a template version with the given sample data
+func (s *emailTemplateService) Preview(ctx context.Context, id uuid.UUID, req *dto.PreviewEmailTemplateRequest) (*dto.RenderedEmailResponse, error) {
+	template, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return renderForReview(template, req.Data)
+}
+
+// SendTest renders a template version and mails it to the test address
+func (s *emailTemplateService) SendTest(ctx context.Context, id uuid.UUID, req *dto.SendTestEmailRequest) error {
+	if s.mailer == nil {
+		return apperror.ErrMailUnavailable
+	}
+
+	template, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	rendered, err := renderForReview(template, req.Data)
+	if err != nil {
+		return err
+	}
+
+	if err := s.mailer.Send(ctx, mail.Message{
+		To:      req.To,
+		Subject: testEmailPrefix + rendered.Subject,
+		HTML:    rendered.HTML,
+		Text:    rendered.Text,
+	}); err != nil {
+		return apperror.ErrServiceUnavailable.WithError(err)
+	}
+	return nil
+}
+
+// Render renders the active version of key for the closest available locale
+func (s *emailTemplateService) Render(ctx context.Context, key, locale string, data any) (*dto.RenderedEmailResponse, error) {
+	template, err := s.templateRepo.GetActive(ctx, key, s.localeChain(locale))
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, details := parseEmailTemplate(template.Subject, template.HTMLBody, template.TextBody)
+	if len(details) > 0 {
+		return nil, apperror.WrapInternal(apperror.NewValidationError(details))
+	}
+	rendered, err := parsed.execute(data)
+	if err != nil {
+		return nil, apperror.WrapInternal(err)
+	}
+	rendered.TemplateID = template.ID.String()
+	rendered.Locale = template.Locale
+	rendered.Version = template.Version
+	return rendered, nil
+}
+
+// localeChain lists the locales to try for locale, e.g. en-US, en and the default locale
+func (s *emailTemplateService) localeChain(locale string) []string {
+	chain := make([]string, 0, 3)
+	if locale != "" {
+		chain = append(chain, locale)
+		if base, _, ok := strings.Cut(locale, "-"); ok {
+			chain = append(chain, base)
+		}
+	}
+	return append(chain, s.defaultLocale)
+}
+
+// renderForReview renders a template for an admin, render errors are reported as validation errors
+func renderForReview(template *entities.EmailTemplate, data map[string]any) (*dto.RenderedEmailResponse, error) {
+	parsed, details := parseEmailTemplate(template.Subject, template.HTMLBody, template.TextBody)
+	if len(details) > 0 {
+		return nil, apperror.NewValidationError(details)
+	}
+	rendered, err := parsed.execute(data)
+	if err != nil {
+		return nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "data", Message: "Template gagal dirender: %s", Args: []any{err.Error()}},
+		})
+	}
+	rendered.TemplateID = template.ID.String()
+	rendered.Locale = template.Locale
+	rendered.Version = template.Version
+	return rendered, nil
+}
+
+// parsedEmailTemplate holds the compiled parts of an email template
+type parsedEmailTemplate struct {
+	subject *texttemplate.Template
+	html    *htmltemplate.Template
+	text    *texttemplate.Template
+}
+
+// parseEmailTemplate compiles the template parts. Missing variables fail the render
+// instead of silently printing "<no value>"
+func parseEmailTemplate(subject, htmlBody, textBody string) (*parsedEmailTemplate, []apperror.ValidationError) {
+	var parsed parsedEmailTemplate
+	var details []apperror.ValidationError
+	var err error
+
+	if parsed.subject, err = texttemplate.New("subject").Option("missingkey=error").Parse(subject); err != nil {
+		details = append(details, apperror.ValidationError{Field: "subject", Message: err.Error()})
+	}
+	if parsed.html, err = htmltemplate.New("html_body").Option("missingkey=error").Parse(htmlBody); err != nil {
+		details = append(details, apperror.ValidationError{Field: "html_body", Message: err.Error()})
+	}
+	if parsed.text, err = texttemplate.New("text_body").Option("missingkey=error").Parse(textBody); err != nil {
+		details = append(details, apperror.ValidationError{Field: "text_body", Message: err.Error()})
+	}
+	return &parsed, details
+}
+
+// execute renders all parts with data
+func (p *parsedEmailTemplate) execute(data any) (*dto.RenderedEmailResponse, error) {
+	var subject, html, text bytes.Buffer
+	if err := p.subject.Execute(&subject, data); err != nil {
+		return nil, err
+	}
+	if err := p.html.Execute(&html, data); err != nil {
+		return nil, err
+	}
+	if err := p.text.Execute(&text, data); err != nil {
+		return nil, err
+	}
+	return &dto.RenderedEmailResponse{
+		Subject: strings.TrimSpace(subject.String()),
+		HTML:    html.String(),
+		Text:    text.String(),
+	}, nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/logger"
+	"postgresDB/pkg/metrics"
+	"sync"
+	"time"
+)
+
+// maxPendingFieldUsage caps the distinct client, route and field combinations counted
+// between flushes, so clients sending arbitrary keys can't grow memory without bound
+const maxPendingFieldUsage = 10000
+
+var fieldUsageDropped = metrics.NewCounter("field_usage_dropped_total",
+	"Number of field uses not counted because too many distinct fields were pending")
+
+// fieldUsageKey identifies a counted field usage
+type fieldUsageKey struct {
+	clientID  string
+	route     string
+	direction entities.FieldDirection
+	field     string
+}
+
+type fieldUsageService struct {
+	fieldUsageRepo repository.FieldUsageRepository
+	flushInterval  time.Duration
+
+	mu      sync.Mutex
+	pending map[fieldUsageKey]*entities.FieldUsage
+}
+
+// NewFieldUsageService creates a new FieldUsageService instance, recorded usage is stored
+// every flushInterval
+func NewFieldUsageService(fieldUsageRepo repository.FieldUsageRepository, flushInterval time.Duration) service.FieldUsageService {
+	return &fieldUsageService{
+		fieldUsageRepo: fieldUsageRepo,
+		flushInterval:  flushInterval,
+		pending:        make(map[fieldUsageKey]*entities.FieldUsage),
+	}
+}
+
+// Record counts the fields in memory until the next flush
+func (s *fieldUsageService) Record(clientID, route string, direction entities.FieldDirection, fields []string) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, field := range fields {
+		key := fieldUsageKey{clientID: clientID, route: route, direction: direction, field: field}
+		usage, ok := s.pending[key]
+		if !ok {
+			if len(s.pending) >= maxPendingFieldUsage {
+				fieldUsageDropped.Inc()
+				continue
+			}
+			usage = &entities.FieldUsage{Route: route, Direction: direction, Field: field, ClientID: clientID, FirstSeen: now}
+			s.pending[key] = usage
+		}
+		usage.Count++
+		usage.LastSeen = now
+	}
+}
+
+// Run stores the counted usage every flush interval, and once more when ctx is done
+func (s *fieldUsageService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.flush(context.WithoutCancel(ctx))
+			return
+		case <-ticker.C:
+			s.flush(ctx)
+		}
+	}
+}
+
+// flush stores the pending usage; it's dropped when storing fails, the counts are samples
+func (s *fieldUsageService) flush(ctx context.Context) {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[fieldUsageKey]*entities.FieldUsage, len(pending))
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+	usage := make([]entities.FieldUsage, 0, len(pending))
+	for _, u := range pending {
+		usage = append(usage, *u)
+	}
+	if err := s.fieldUsageRepo.Add(ctx, usage); err != nil {
+		logger.ErrorContext(ctx, "writing field usage failed", "count", len(usage), "error", err)
+	}
+}
+
+// Report returns the stored usage per field
+func (s *fieldUsageService) Report(ctx context.Context, filter entities.FieldUsageFilter) ([]dto.FieldUsageResponse, error) {
+	usage, err := s.fieldUsageRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToFieldUsageResponseList(usage), nil
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	localrepo "postgresDB/internal/repository"
+	"postgresDB/pkg/logger"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxWatchedProducts caps the products a single stream can watch
+	maxWatchedProducts = 100
+	// watcherBuffer is how many events a slow client may lag behind before it's dropped
+	watcherBuffer = 64
+)
+
+// stockWatcher is a client of the inventory stream
+type stockWatcher struct {
+	products map[uuid.UUID]struct{}
+	events   chan dto.StockEventResponse
+}
+
+type inventoryStreamService struct {
+	bus         localrepo.EventBus
+	productRepo repository.ProductRepository
+
+	mu       sync.Mutex
+	watchers map[*stockWatcher]struct{}
+}
+
+// NewInventoryStreamService creates a new InventoryStreamService instance. A single bus
+// subscription per instance is fanned out to all of its watchers
+func NewInventoryStreamService(bus localrepo.EventBus, productRepo repository.ProductRepository) service.InventoryStreamService {
+	return &inventoryStreamService{
+		bus:         bus,
+		productRepo: productRepo,
+		watchers:    make(map[*stockWatcher]struct{}),
+	}
+}
+
+// Run keeps the bus subscription open, resubscribing with backoff while Redis is down
+func (s *inventoryStreamService) Run(ctx context.Context) {
+	for attempts := 0; ; {
+		events, err := s.bus.Subscribe(ctx, entities.StockEventTopic)
+		if err == nil {
+			attempts = 0
+			for payload := range events {
+				var event entities.StockEvent
+				if err := json.Unmarshal(payload, &event); err != nil {
+					logger.WarnContext(ctx, "malformed stock event", "error", err)
+					continue
+				}
+				s.broadcast(&event)
+			}
+		} else {
+			logger.ErrorContext(ctx, "subscribing to stock events failed", "error", err)
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+		attempts++
+		timer := time.NewTimer(retryBackoff(time.Second, 30*time.Second, attempts))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// Watch registers the watcher before loading the snapshot, so a change made while the
+// snapshot is read is sent as an event rather than lost
+func (s *inventoryStreamService) Watch(ctx context.Context, productIDs []uuid.UUID) ([]dto.StockLevelResponse, <-chan dto.StockEventResponse, error) {
+	if len(productIDs) == 0 || len(productIDs) > maxWatchedProducts {
+		return nil, nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "product_id", Message: "product_id wajib diisi, maksimal %d produk", Args: []any{maxWatchedProducts}},
+		})
+	}
+
+	watcher := &stockWatcher{
+		products: make(map[uuid.UUID]struct{}, len(productIDs)),
+		events:   make(chan dto.StockEventResponse, watcherBuffer),
+	}
+	for _, id := range productIDs {
+		watcher.products[id] = struct{}{}
+	}
+
+	s.mu.Lock()
+	s.watchers[watcher] = struct{}{}
+	s.mu.Unlock()
+
+	products, err := s.productRepo.FindAll(ctx, repository.ProductFilter{IDs: productIDs})
+	if err != nil {
+		s.remove(watcher)
+		return nil, nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		s.remove(watcher)
+	}()
+
+	levels := make([]dto.StockLevelResponse, len(products))
+	for i, product := range products {
+		levels[i] = dto.ToStockLevelResponse(product)
+	}
+	return levels, watcher.events, nil
+}
+
+// broadcast sends the event to the watchers of its product. A watcher whose buffer is
+// full is dropped instead of blocking everyone else
+func (s *inventoryStreamService) broadcast(event *entities.StockEvent) {
+	response := dto.ToStockEventResponse(event)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for watcher := range s.watchers {
+		if _, ok := watcher.products[event.ProductID]; !ok {
+			continue
+		}
+		select {
+		case watcher.events <- response:
+		default:
+			delete(s.watchers, watcher)
+			close(watcher.events)
+		}
+	}
+}
+
+// remove unregisters the watcher and closes its channel, unless broadcast already did
+func (s *inventoryStreamService) remove(watcher *stockWatcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.watchers[watcher]; ok {
+		delete(s.watchers, watcher)
+		close(watcher.events)
+	}
+}
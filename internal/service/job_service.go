@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/service"
+	"postgresDB/internal/repository"
+	"postgresDB/pkg/logger"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultJobQueue is the queue used when a job is enqueued without one
+const DefaultJobQueue = "default"
+
+// JobWorkerConfig configures the background job workers
+type JobWorkerConfig struct {
+	// Queues are the queues the workers take jobs from, in priority order
+	Queues []string
+	// Workers is the number of jobs handled concurrently
+	Workers int
+	// PollInterval is the wait after finding every queue empty
+	PollInterval time.Duration
+	// MaxAttempts is the number of attempts before a job is moved to the failed list
+	MaxAttempts int
+	// BaseBackoff is the wait after the first failed attempt, doubled after every attempt
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Timeout bounds a single run of a handler
+	Timeout time.Duration
+	// StaleAfter is how long a job may stay running before it's considered abandoned
+	// by a dead worker and queued again; it must be well above Timeout
+	StaleAfter time.Duration
+}
+
+type jobService struct {
+	jobRepo repository.JobRepository
+	cfg     JobWorkerConfig
+
+	mu       sync.RWMutex
+	handlers map[string]service.JobHandler
+}
+
+// NewJobService creates a new JobService instance
+func NewJobService(jobRepo repository.JobRepository, cfg JobWorkerConfig) service.JobService {
+	return &jobService{
+		jobRepo:  jobRepo,
+		cfg:      cfg,
+		handlers: make(map[string]service.JobHandler),
+	}
+}
+
+// Enqueue stores a job that is due right away
+func (s *jobService) Enqueue(ctx context.Context, queue, jobType string, payload any) (uuid.UUID, error) {
+	if queue == "" {
+		queue = DefaultJobQueue
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return uuid.Nil, apperror.WrapInternal(fmt.Errorf("encoding %s job payload: %w", jobType, err))
+	}
+
+	now := time.Now()
+	job := &entities.Job{
+		ID:          uuid.New(),
+		Queue:       queue,
+		Type:        jobType,
+		Payload:     data,
+		Status:      entities.JobStatusQueued,
+		MaxAttempts: s.cfg.MaxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.jobRepo.Enqueue(ctx, job); err != nil {
+		return uuid.Nil, wrapJobError(err)
+	}
+	return job.ID, nil
+}
+
+// Register sets the handler of a job type
+func (s *jobService) Register(jobType string, handler service.JobHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[jobType] = handler
+}
+
+// Run starts the workers and the stale job recovery, and returns once ctx is done and
+// every in-flight job has finished
+func (s *jobService) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range max(s.cfg.Workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(s.cfg.StaleAfter / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case <-ticker.C:
+		}
+
+		for _, queue := range s.cfg.Queues {
+			n, err := s.jobRepo.RequeueStale(ctx, queue, time.Now().Add(-s.cfg.StaleAfter))
+			if err != nil {
+				logger.ErrorContext(ctx, "requeueing stale jobs failed", "queue", queue, "error", err)
+				continue
+			}
+			if n > 0 {
+				logger.WarnContext(ctx, "requeued stale jobs", "queue", queue, "count", n)
+			}
+		}
+	}
+}
+
+// work takes jobs from the queues in priority order, sleeping when they're all empty
+func (s *jobService) work(ctx context.Context) {
+	for ctx.Err() == nil {
+		found := false
+		for _, queue := range s.cfg.Queues {
+			job, err := s.jobRepo.Dequeue(ctx, queue, time.Now())
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.ErrorContext(ctx, "dequeueing job failed", "queue", queue, "error", err)
+				}
+				continue
+			}
+			if job == nil {
+				continue
+			}
+			found = true
+			s.process(ctx, job)
+			break
+		}
+
+		if !found {
+			select {
+			case <-ctx.Done():
+			case <-time.After(s.cfg.PollInterval):
+			}
+		}
+	}
+}
+
+// process runs one attempt of job and stores the outcome. The attempt isn't cut short
+// by ctx, so shutting down lets in-flight jobs finish within Timeout
+func (s *jobService) process(ctx context.Context, job *entities.Job) {
+	started := time.Now()
+	wait := max(started.Sub(job.RunAt), 0)
+
+	s.mu.RLock()
+	handler, ok := s.handlers[job.Type]
+	s.mu.RUnlock()
+
+	var err error
+	if ok {
+		runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.cfg.Timeout)
+		err = runJobHandler(runCtx, handler, job.Payload)
+		cancel()
+	} else {
+		err = fmt.Errorf("no handler registered for job type %q", job.Type)
+	}
+	run := time.Since(started)
+
+	storeCtx := context.WithoutCancel(ctx)
+	job.Attempts++
+	if err == nil {
+		job.LastError = ""
+		if err := s.jobRepo.Complete(storeCtx, job, wait, run); err != nil {
+			logger.ErrorContext(ctx, "completing job failed", "job_id", job.ID, "error", err)
+		}
+		return
+	}
+
+	job.LastError = err.Error()
+	if !ok || job.Attempts >= job.MaxAttempts {
+		logger.ErrorContext(ctx, "job failed", "job_id", job.ID, "type", job.Type, "attempts", job.Attempts, "error", err)
+		if err := s.jobRepo.Fail(storeCtx, job, wait, run); err != nil {
+			logger.ErrorContext(ctx, "failing job failed", "job_id", job.ID, "error", err)
+		}
+		return
+	}
+
+	job.RunAt = time.Now().Add(retryBackoff(s.cfg.BaseBackoff, s.cfg.MaxBackoff, job.Attempts))
+	logger.WarnContext(ctx, "job attempt failed, retrying", "job_id", job.ID, "type", job.Type, "attempts", job.Attempts, "run_at", job.RunAt, "error", err)
+	if err := s.jobRepo.Reschedule(storeCtx, job, wait, run); err != nil {
+		logger.ErrorContext(ctx, "rescheduling job failed", "job_id", job.ID, "error", err)
+	}
+}
+
+// Get retrieves a job by ID
+func (s *jobService) Get(ctx context.Context, id uuid.UUID) (*dto.JobResponse, error) {
+	job, err := s.jobRepo.Get(ctx, id)
+	if err != nil {
+		return nil, wrapJobError(err)
+	}
+
+	response := dto.ToJobResponse(job)
+	return &response, nil
+}
+
+// ListFailed retrieves the failed jobs of a queue
+func (s *jobService) ListFailed(ctx context.Context, queue string, page, limit int) ([]dto.JobResponse, *dto.PaginationMeta, error) {
+	if queue == "" {
+		queue = DefaultJobQueue
+	}
+	if page <= 1 {
+		page = 1
+	}
+	if limit <= 1 {
+		limit = 10
+	}
+
+	jobs, total, err := s.jobRepo.ListFailed(ctx, queue, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, wrapJobError(err)
+	}
+
+	pagination := &dto.PaginationMeta{
+		Total:      total,
+		Limit:      limit,
+		Page:       page,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}
+	return dto.ToJobResponseList(jobs), pagination, nil
+}
+
+// Retry puts a failed job back in its queue
+func (s *jobService) Retry(ctx context.Context, id uuid.UUID) (*dto.JobResponse, error) {
+	job, err := s.jobRepo.Retry(ctx, id)
+	if err != nil {
+		return nil, wrapJobError(err)
+	}
+
+	response := dto.ToJobResponse(job)
+	return &response, nil
+}
+
+// Cancel drops a queued or failed job
+func (s *jobService) Cancel(ctx context.Context, id uuid.UUID) (*dto.JobResponse, error) {
+	job, err := s.jobRepo.Cancel(ctx, id)
+	if err != nil {
+		return nil, wrapJobError(err)
+	}
+
+	response := dto.ToJobResponse(job)
+	return &response, nil
+}
+
+// Stats returns the stats of every known queue, sorted by name
+func (s *jobService) Stats(ctx context.Context, window time.Duration) ([]dto.QueueStatsResponse, error) {
+	queues, err := s.jobRepo.Queues(ctx)
+	if err != nil {
+		return nil, wrapJobError(err)
+	}
+	for _, queue := range s.cfg.Queues {
+		if !slices.Contains(queues, queue) {
+			queues = append(queues, queue)
+		}
+	}
+	sort.Strings(queues)
+
+	responses := make([]dto.QueueStatsResponse, 0, len(queues))
+	for _, queue := range queues {
+		stats, err := s.jobRepo.Stats(ctx, queue, window)
+		if err != nil {
+			return nil, wrapJobError(err)
+		}
+		responses = append(responses, dto.ToQueueStatsResponse(stats))
+	}
+	return responses, nil
+}
+
+// runJobHandler calls handler, turning a panic into an error so one bad job can't kill a worker
+func runJobHandler(ctx context.Context, handler service.JobHandler, payload json.RawMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job handler panicked: %v", r)
+		}
+	}()
+	return handler(ctx, payload)
+}
+
+// wrapJobError passes application errors through and wraps Redis errors as internal errors
+func wrapJobError(err error) error {
+	if apperror.IsAppError(err) {
+		return err
+	}
+	return apperror.WrapInternal(err)
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/internal/infrastruktur/mail"
+	"postgresDB/pkg/logger"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// JobTypeLowStockAlert notifies admins that a product reached its reorder threshold
+	JobTypeLowStockAlert = "low_stock_alert"
+
+	lowStockTemplateKey = "low-stock-alert"
+	lowStockEvent       = "product.low_stock"
+)
+
+// lowStockAlert is the payload of a JobTypeLowStockAlert job
+type lowStockAlert struct {
+	ProductID        uuid.UUID `json:"product_id"`
+	Stock            int       `json:"stock"`
+	ReorderThreshold int       `json:"reorder_threshold"`
+}
+
+// LowStockNotifier delivers low-stock alerts to the admins by email and to a webhook
+type LowStockNotifier struct {
+	productRepo repository.ProductRepository
+	userRepo    repository.UserRepository
+	templates   service.EmailTemplateService
+	mailer      mail.Mailer
+	webhookURL  string
+	client      *http.Client
+}
+
+// NewLowStockNotifier creates a LowStockNotifier. mailer may be nil and webhookURL empty
+// to skip that channel
+func NewLowStockNotifier(productRepo repository.ProductRepository, userRepo repository.UserRepository, templates service.EmailTemplateService, mailer mail.Mailer, webhookURL string) *LowStockNotifier {
+	return &LowStockNotifier{
+		productRepo: productRepo,
+		userRepo:    userRepo,
+		templates:   templates,
+		mailer:      mailer,
+		webhookURL:  webhookURL,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Handle is the JobHandler of JobTypeLowStockAlert. A failed channel retries the whole
+// job, so a retry may send an email or webhook call twice
+func (n *LowStockNotifier) Handle(ctx context.Context, payload json.RawMessage) error {
+	var alert lowStockAlert
+	if err := json.Unmarshal(payload, &alert); err != nil {
+		return fmt.Errorf("decoding low stock alert: %w", err)
+	}
+	if n.mailer == nil && n.webhookURL == "" {
+		logger.WarnContext(ctx, "low stock alert dropped, no email or webhook configured", "product_id", alert.ProductID)
+		return nil
+	}
+
+	product, err := n.productRepo.GetByID(ctx, alert.ProductID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	if n.mailer != nil {
+		errs = append(errs, n.email(ctx, product, alert))
+	}
+	if n.webhookURL != "" {
+		errs = append(errs, n.webhook(ctx, product, alert))
+	}
+	return errors.Join(errs...)
+}
+
+// email sends the low-stock-alert template to every active admin
+func (n *LowStockNotifier) email(ctx context.Context, product *entities.Product, alert lowStockAlert) error {
+	admins, err := n.userRepo.ListActiveByRole(ctx, entities.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	rendered, err := n.templates.Render(ctx, lowStockTemplateKey, "", map[string]any{
+		"ProductID":        product.ID.String(),
+		"ProductName":      product.Name,
+		"SKU":              product.SKU,
+		"Stock":            alert.Stock,
+		"ReorderThreshold": alert.ReorderThreshold,
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, admin := range admins {
+		if err := n.mailer.Send(ctx, mail.Message{
+			To:      admin.Email,
+			Subject: rendered.Subject,
+			HTML:    rendered.HTML,
+			Text:    rendered.Text,
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("mailing %s: %w", admin.Email, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// webhook posts the alert as JSON, any non-2xx response is an error
+func (n *LowStockNotifier) webhook(ctx context.Context, product *entities.Product, alert lowStockAlert) error {
+	body, err := json.Marshal(map[string]any{
+		"event":             lowStockEvent,
+		"product_id":        product.ID.String(),
+		"sku":               product.SKU,
+		"name":              product.Name,
+		"stock":             alert.Stock,
+		"reorder_threshold": alert.ReorderThreshold,
+		"occurred_at":       time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("low stock webhook: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("low stock webhook: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/internal/notification"
+	"postgresDB/pkg/logger"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// JobTypeNotification sends one notification to a user on one channel
+const JobTypeNotification = "notification"
+
+// notificationTemplates are the email templates the notification kinds are rendered
+// from, on every channel
+var notificationTemplates = map[entities.NotificationKind]string{
+	entities.NotificationOrderConfirmation: "order-confirmation",
+	entities.NotificationOrderStatus:       "order-status-changed",
+	entities.NotificationPasswordReset:     "password-reset",
+}
+
+// notificationDelivery is the payload of a JobTypeNotification job
+type notificationDelivery struct {
+	UserID  uuid.UUID                 `json:"user_id"`
+	Kind    entities.NotificationKind `json:"kind"`
+	Channel string                    `json:"channel"`
+	Data    map[string]any            `json:"data"`
+}
+
+type notificationService struct {
+	preferenceRepo repository.NotificationPreferenceRepository
+	userRepo       repository.UserRepository
+	templates      service.EmailTemplateService
+	jobs           service.JobQueue
+	channels       map[string]notification.Channel
+	// names are the channel names in the order they were given
+	names []string
+}
+
+// NewNotificationService creates a new NotificationService sending on channels; without
+// channels notifications are dropped
+func NewNotificationService(preferenceRepo repository.NotificationPreferenceRepository, userRepo repository.UserRepository, templates service.EmailTemplateService, jobs service.JobQueue, channels ...notification.Channel) service.NotificationService {
+	s := &notificationService{
+		preferenceRepo: preferenceRepo,
+		userRepo:       userRepo,
+		templates:      templates,
+		jobs:           jobs,
+		channels:       make(map[string]notification.Channel, len(channels)),
+	}
+	for _, channel := range channels {
+		s.channels[channel.Name()] = channel
+		s.names = append(s.names, channel.Name())
+	}
+	return s
+}
+
+// Notify queues the notification on every channel, each by its own job so a failing
+// channel is retried without repeating the others. Preferences are checked on delivery
+func (s *notificationService) Notify(ctx context.Context, userID uuid.UUID, kind entities.NotificationKind, data map[string]any) error {
+	if !kind.IsValid() {
+		return fmt.Errorf("unknown notification kind %q", kind)
+	}
+	for _, name := range s.names {
+		delivery := notificationDelivery{UserID: userID, Kind: kind, Channel: name, Data: data}
+		if _, err := s.jobs.Enqueue(ctx, DefaultJobQueue, JobTypeNotification, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Deliver is the JobHandler of JobTypeNotification. It renders the template of the kind
+// and sends it, unless the user turned the notification off or has no template
+func (s *notificationService) Deliver(ctx context.Context, payload json.RawMessage) error {
+	var delivery notificationDelivery
+	if err := json.Unmarshal(payload, &delivery); err != nil {
+		return fmt.Errorf("decoding notification: %w", err)
+	}
+	channel, ok := s.channels[delivery.Channel]
+	if !ok {
+		// the channel was removed from the configuration since the notification was queued
+		logger.WarnContext(ctx, "notification dropped, channel not configured", "kind", delivery.Kind, "channel", delivery.Channel)
+		return nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, delivery.UserID)
+	if errors.Is(err, apperror.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	enabled, err := s.enabled(ctx, user.ID, delivery.Kind, delivery.Channel)
+	if err != nil || !enabled {
+		return err
+	}
+
+	// guests have a generated username, templates greet them without a name
+	recipient := notification.Recipient{UserID: user.ID, Name: user.Username, Email: user.Email}
+	if user.IsGuest {
+		recipient.Name = ""
+	}
+	data := maps.Clone(delivery.Data)
+	if data == nil {
+		data = make(map[string]any)
+	}
+	data["Name"] = recipient.Name
+
+	rendered, err := s.templates.Render(ctx, notificationTemplates[delivery.Kind], "", data)
+	if errors.Is(err, apperror.ErrEmailTemplateNotFound) {
+		logger.WarnContext(ctx, "notification skipped, no template published", "kind", delivery.Kind, "template", notificationTemplates[delivery.Kind])
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	err = channel.Send(ctx, recipient, notification.Message{
+		Kind:    string(delivery.Kind),
+		Subject: rendered.Subject,
+		HTML:    rendered.HTML,
+		Text:    rendered.Text,
+		Data:    data,
+	})
+	if errors.Is(err, notification.ErrUnreachable) {
+		return nil
+	}
+	return err
+}
+
+// enabled reports whether the user receives kind on channel, on unless turned off
+func (s *notificationService) enabled(ctx context.Context, userID uuid.UUID, kind entities.NotificationKind, channel string) (bool, error) {
+	if kind.Required() {
+		return true, nil
+	}
+	preference, err := s.preferenceRepo.Get(ctx, userID, kind, channel)
+	if errors.Is(err, apperror.ErrNotificationPreferenceNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return preference.Enabled, nil
+}
+
+// Preferences returns the preferences of the user on the configured channels, filling in
+// the defaults of kinds never chosen
+func (s *notificationService) Preferences(ctx context.Context, userID uuid.UUID) ([]dto.NotificationPreferenceResponse, error) {
+	chosen, err := s.preferenceRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	type key struct {
+		kind    entities.NotificationKind
+		channel string
+	}
+	byKey := make(map[key]*entities.NotificationPreference, len(chosen))
+	for _, p := range chosen {
+		byKey[key{p.Kind, p.Channel}] = p
+	}
+
+	preferences := make([]*entities.NotificationPreference, 0, len(entities.NotificationKinds)*len(s.names))
+	for _, kind := range entities.NotificationKinds {
+		for _, channel := range s.names {
+			if p, ok := byKey[key{kind, channel}]; ok {
+				preferences = append(preferences, p)
+				continue
+			}
+			preferences = append(preferences, &entities.NotificationPreference{UserID: userID, Kind: kind, Channel: channel, Enabled: true})
+		}
+	}
+	return dto.ToNotificationPreferenceResponseList(preferences), nil
+}
+
+// UpdatePreferences saves the preferences of the request; required notifications can't
+// be turned off
+func (s *notificationService) UpdatePreferences(ctx context.Context, userID uuid.UUID, req *dto.UpdateNotificationPreferencesRequest) ([]dto.NotificationPreferenceResponse, error) {
+	var details []apperror.ValidationError
+	preferences := make([]*entities.NotificationPreference, 0, len(req.Preferences))
+	for i, p := range req.Preferences {
+		kind := entities.NotificationKind(p.Kind)
+		switch {
+		case !kind.IsValid():
+			details = append(details, apperror.ValidationError{
+				Field:   fmt.Sprintf("preferences[%d].kind", i),
+				Message: "jenis notifikasi tidak dikenal",
+			})
+		case !slices.Contains(s.names, p.Channel):
+			details = append(details, apperror.ValidationError{
+				Field:   fmt.Sprintf("preferences[%d].channel", i),
+				Message: "channel notifikasi tidak tersedia",
+			})
+		case kind.Required() && !*p.Enabled:
+			details = append(details, apperror.ValidationError{
+				Field:   fmt.Sprintf("preferences[%d].enabled", i),
+				Message: "notifikasi ini tidak dapat dimatikan",
+			})
+		default:
+			preferences = append(preferences, &entities.NotificationPreference{
+				UserID:  userID,
+				Kind:    kind,
+				Channel: p.Channel,
+				Enabled: *p.Enabled,
+			})
+		}
+	}
+	if len(details) > 0 {
+		return nil, apperror.NewValidationError(details)
+	}
+
+	if err := s.preferenceRepo.Save(ctx, preferences); err != nil {
+		return nil, err
+	}
+	return s.Preferences(ctx, userID)
+}
@@ -0,0 +1,396 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	localrepo "postgresDB/internal/repository"
+	"postgresDB/pkg/jwt"
+	"postgresDB/pkg/logger"
+	"postgresDB/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// OAuth grant types accepted by the token endpoint
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+)
+
+const (
+	authorizationCodePrefix = "ac_"
+	// pkceChallengeLength is the length of a base64url encoded SHA-256 code challenge
+	pkceChallengeLength = 43
+	// pkceVerifierMin and pkceVerifierMax bound the code verifier length (RFC 7636)
+	pkceVerifierMin = 43
+	pkceVerifierMax = 128
+)
+
+// OAuthConfig configures the OAuth provider
+type OAuthConfig struct {
+	// IssuerURL is the public base URL of the API and the issuer of ID tokens
+	IssuerURL string
+	// AuthorizationURL is the consent page advertised as authorization endpoint, the
+	// authorize API endpoint is used when empty
+	AuthorizationURL string
+	// CodeTTL is how long an authorization code can be redeemed
+	CodeTTL time.Duration
+}
+
+type oauthService struct {
+	clientRepo repository.APIClientRepository
+	userRepo   repository.UserRepository
+	codeRepo   localrepo.AuthorizationCodeRepository
+	jwtService *jwt.JWTService
+	cfg        OAuthConfig
+}
+
+// NewOAuthService creates a new OAuthService instance
+func NewOAuthService(clientRepo repository.APIClientRepository, userRepo repository.UserRepository, codeRepo localrepo.AuthorizationCodeRepository, jwtService *jwt.JWTService, cfg OAuthConfig) service.OAuthService {
+	return &oauthService{
+		clientRepo: clientRepo,
+		userRepo:   userRepo,
+		codeRepo:   codeRepo,
+		jwtService: jwtService,
+		cfg:        cfg,
+	}
+}
+
+// Consent validates the request without issuing a code
+func (s *oauthService) Consent(ctx context.Context, req *dto.AuthorizeRequest) (*dto.AuthorizeConsentResponse, error) {
+	client, scopes, err := s.resolveAuthorization(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AuthorizeConsentResponse{
+		ClientID:    client.ClientID,
+		ClientName:  client.Name,
+		RedirectURI: req.RedirectURI,
+		Scopes:      scopes,
+	}, nil
+}
+
+// Authorize issues a single use authorization code bound to the client, the redirect
+// URI and the PKCE challenge, and returns the redirect URI carrying it
+func (s *oauthService) Authorize(ctx context.Context, userID uuid.UUID, req *dto.AuthorizeRequest) (*dto.AuthorizeResponse, error) {
+	client, scopes, err := s.resolveAuthorization(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := utils.RandomToken(authorizationCodePrefix, 32)
+	if err != nil {
+		return nil, apperror.WrapInternal(err)
+	}
+	grant := &entities.AuthorizationCode{
+		ClientID:      client.ClientID,
+		UserID:        userID,
+		RedirectURI:   req.RedirectURI,
+		Scopes:        scopes,
+		CodeChallenge: req.CodeChallenge,
+		Nonce:         req.Nonce,
+		CreatedAt:     time.Now(),
+	}
+	if err := s.codeRepo.Save(ctx, utils.HashToken(code), grant, s.cfg.CodeTTL); err != nil {
+		return nil, apperror.WrapInternal(err)
+	}
+
+	// the redirect URI was matched against the registered ones, so it parses
+	redirect, err := url.Parse(req.RedirectURI)
+	if err != nil {
+		return nil, apperror.WrapInternal(err)
+	}
+	query := redirect.Query()
+	query.Set("code", code)
+	if req.State != "" {
+		query.Set("state", req.State)
+	}
+	redirect.RawQuery = query.Encode()
+
+	logger.InfoContext(ctx, "OAuth authorization code issued", "client_id", client.ClientID, "user_id", userID.String())
+	return &dto.AuthorizeResponse{
+		RedirectTo: redirect.String(),
+		ExpiresIn:  int64(s.cfg.CodeTTL.Seconds()),
+	}, nil
+}
+
+// Token authenticates the client and redeems the grant
+func (s *oauthService) Token(ctx context.Context, req *dto.OAuthTokenRequest) (*dto.OAuthTokenResponse, error) {
+	if req.GrantType == "" {
+		return nil, apperror.NewOAuthError(apperror.OAuthInvalidRequest, "grant_type wajib diisi")
+	}
+	if req.GrantType != GrantTypeAuthorizationCode && req.GrantType != GrantTypeRefreshToken {
+		return nil, apperror.NewOAuthError(apperror.OAuthUnsupportedGrantType, "grant_type "+req.GrantType+" tidak didukung")
+	}
+
+	client, err := s.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GrantType == GrantTypeAuthorizationCode {
+		return s.exchangeCode(ctx, client, req)
+	}
+	return s.refresh(ctx, client, req)
+}
+
+// UserInfo returns the claims of the user, an access token without the openid scope
+// is refused
+func (s *oauthService) UserInfo(ctx context.Context, accessToken string) (*dto.UserInfoResponse, error) {
+	claims, err := s.jwtService.ValidateClientAccessToken(ctx, accessToken)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenStoreUnavailable) {
+			return nil, apperror.ErrServiceUnavailable
+		}
+		return nil, apperror.ErrInvalidToken
+	}
+
+	scopes := strings.Fields(claims.Scope)
+	if !slices.Contains(scopes, entities.ScopeOpenID) {
+		return nil, apperror.ErrForbidden
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrUserNotFound) {
+			return nil, apperror.ErrInvalidToken
+		}
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, apperror.ErrInvalidToken
+	}
+
+	res := &dto.UserInfoResponse{Subject: user.ID.String()}
+	if slices.Contains(scopes, entities.ScopeProfile) {
+		res.PreferredUsername = user.Username
+	}
+	if slices.Contains(scopes, entities.ScopeEmail) {
+		res.Email = user.Email
+	}
+	return res, nil
+}
+
+// Discovery returns the OpenID Connect discovery document
+func (s *oauthService) Discovery() dto.OpenIDConfiguration {
+	authorizationEndpoint := s.cfg.AuthorizationURL
+	if authorizationEndpoint == "" {
+		authorizationEndpoint = s.cfg.IssuerURL + "/api/v1/oauth/authorize"
+	}
+
+	return dto.OpenIDConfiguration{
+		Issuer:                            s.cfg.IssuerURL,
+		AuthorizationEndpoint:             authorizationEndpoint,
+		TokenEndpoint:                     s.cfg.IssuerURL + "/api/v1/oauth/token",
+		UserInfoEndpoint:                  s.cfg.IssuerURL + "/api/v1/oauth/userinfo",
+		JWKSURI:                           s.cfg.IssuerURL + "/.well-known/jwks.json",
+		ScopesSupported:                   entities.APIClientScopes(),
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{GrantTypeAuthorizationCode, GrantTypeRefreshToken},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{"RS256"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "nonce", "azp", "preferred_username", "email"},
+	}
+}
+
+// JWKS returns the keys ID tokens are signed with
+func (s *oauthService) JWKS() dto.JWKSet {
+	key := s.jwtService.PublicJWK()
+	return dto.JWKSet{Keys: []dto.JWK{{
+		KeyType:   key.KeyType,
+		Use:       key.Use,
+		Algorithm: key.Algorithm,
+		KeyID:     key.KeyID,
+		Modulus:   key.Modulus,
+		Exponent:  key.Exponent,
+	}}}
+}
+
+// resolveAuthorization loads the client of an authorization request and checks the
+// redirect URI, the scopes and the code challenge against it
+func (s *oauthService) resolveAuthorization(ctx context.Context, req *dto.AuthorizeRequest) (*entities.APIClient, []string, error) {
+	client, err := s.clientRepo.GetByClientID(ctx, req.ClientID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrAPIClientNotFound) {
+			return nil, nil, apperror.NewValidationError([]apperror.ValidationError{
+				{Field: "client_id", Message: "client_id tidak dikenal"},
+			})
+		}
+		return nil, nil, err
+	}
+
+	var details []apperror.ValidationError
+	if !slices.Contains(client.RedirectURIs, req.RedirectURI) {
+		details = append(details, apperror.ValidationError{Field: "redirect_uri", Message: "redirect_uri tidak terdaftar untuk client ini"})
+	}
+	scopes := uniqueScopes(strings.Fields(req.Scope))
+	if len(scopes) == 0 {
+		details = append(details, apperror.ValidationError{Field: "scope", Message: "scope wajib diisi"})
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(client.Scopes, scope) {
+			details = append(details, apperror.ValidationError{Field: "scope", Message: "scope %s tidak diizinkan untuk client ini", Args: []any{scope}})
+		}
+	}
+	if _, err := base64.RawURLEncoding.DecodeString(req.CodeChallenge); err != nil || len(req.CodeChallenge) != pkceChallengeLength {
+		details = append(details, apperror.ValidationError{Field: "code_challenge", Message: "code_challenge harus berupa SHA-256 base64url tanpa padding"})
+	}
+	if len(details) > 0 {
+		return nil, nil, apperror.NewValidationError(details)
+	}
+	return client, scopes, nil
+}
+
+// authenticateClient checks the client credentials, an unknown client and a wrong
+// secret are not told apart
+func (s *oauthService) authenticateClient(ctx context.Context, clientID, secret string) (*entities.APIClient, error) {
+	if clientID == "" || secret == "" {
+		return nil, apperror.NewOAuthError(apperror.OAuthInvalidClient, "autentikasi client gagal")
+	}
+
+	client, err := s.clientRepo.GetByClientID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrAPIClientNotFound) {
+			return nil, apperror.NewOAuthError(apperror.OAuthInvalidClient, "autentikasi client gagal")
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(utils.HashToken(secret)), []byte(client.SecretHash)) != 1 {
+		return nil, apperror.NewOAuthError(apperror.OAuthInvalidClient, "autentikasi client gagal")
+	}
+	return client, nil
+}
+
+// exchangeCode redeems an authorization code. The code is consumed before it is
+// checked, so a code sent with a wrong verifier can't be retried
+func (s *oauthService) exchangeCode(ctx context.Context, client *entities.APIClient, req *dto.OAuthTokenRequest) (*dto.OAuthTokenResponse, error) {
+	if req.Code == "" || req.RedirectURI == "" || req.CodeVerifier == "" {
+		return nil, apperror.NewOAuthError(apperror.OAuthInvalidRequest, "code, redirect_uri dan code_verifier wajib diisi")
+	}
+
+	grant, err := s.codeRepo.Consume(ctx, utils.HashToken(req.Code))
+	if err != nil {
+		return nil, err
+	}
+	if grant == nil || grant.ClientID != client.ClientID || grant.RedirectURI != req.RedirectURI {
+		return nil, apperror.NewOAuthError(apperror.OAuthInvalidGrant, "authorization code tidak valid atau kedaluwarsa")
+	}
+	if !verifyCodeChallenge(req.CodeVerifier, grant.CodeChallenge) {
+		return nil, apperror.NewOAuthError(apperror.OAuthInvalidGrant, "code_verifier tidak cocok")
+	}
+
+	user, err := s.activeUser(ctx, grant.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	pair, err := s.jwtService.GenerateClientTokens(ctx, user.ID, user.Role, client.ClientID, grant.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.InfoContext(ctx, "OAuth tokens issued", "client_id", client.ClientID, "user_id", user.ID.String())
+	return s.tokenResponse(client, user, pair, grant.Nonce)
+}
+
+// refresh rotates a refresh token of the client, the scopes stay those of the grant
+func (s *oauthService) refresh(ctx context.Context, client *entities.APIClient, req *dto.OAuthTokenRequest) (*dto.OAuthTokenResponse, error) {
+	if req.RefreshToken == "" {
+		return nil, apperror.NewOAuthError(apperror.OAuthInvalidRequest, "refresh_token wajib diisi")
+	}
+
+	pair, err := s.jwtService.RefreshClientTokens(ctx, req.RefreshToken, client.ClientID)
+	if err != nil {
+		oauthErr := apperror.NewOAuthError(apperror.OAuthInvalidGrant, "refresh token tidak valid")
+		oauthErr.Err = err
+		return nil, oauthErr
+	}
+
+	claims, err := s.jwtService.ValidateToken(pair.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.activeUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tokenResponse(client, user, pair, "")
+}
+
+// activeUser loads the user a grant was issued for, a deleted or inactive user
+// invalidates the grant
+func (s *oauthService) activeUser(ctx context.Context, userID uuid.UUID) (*entities.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrUserNotFound) {
+			return nil, apperror.NewOAuthError(apperror.OAuthInvalidGrant, "user tidak ditemukan")
+		}
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, apperror.NewOAuthError(apperror.OAuthInvalidGrant, "user tidak aktif")
+	}
+	return user, nil
+}
+
+// tokenResponse builds the token response, with an ID token when openid was granted
+func (s *oauthService) tokenResponse(client *entities.APIClient, user *entities.User, pair *jwt.TokenPair, nonce string) (*dto.OAuthTokenResponse, error) {
+	res := &dto.OAuthTokenResponse{
+		AccessToken:  pair.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.jwtService.GetAccessTokenTTL().Seconds()),
+		RefreshToken: pair.RefreshToken,
+		Scope:        strings.Join(pair.Scopes, " "),
+	}
+	if !slices.Contains(pair.Scopes, entities.ScopeOpenID) {
+		return res, nil
+	}
+
+	claims := jwt.IDTokenClaims{
+		Nonce:           nonce,
+		AuthorizedParty: client.ClientID,
+	}
+	claims.Issuer = s.cfg.IssuerURL
+	claims.Subject = user.ID.String()
+	claims.Audience = []string{client.ClientID}
+	if slices.Contains(pair.Scopes, entities.ScopeProfile) {
+		claims.PreferredUsername = user.Username
+	}
+	if slices.Contains(pair.Scopes, entities.ScopeEmail) {
+		claims.Email = user.Email
+	}
+
+	idToken, err := s.jwtService.GenerateIDToken(claims)
+	if err != nil {
+		return nil, err
+	}
+	res.IDToken = idToken
+	return res, nil
+}
+
+// verifyCodeChallenge checks a PKCE code verifier against its S256 challenge
+func verifyCodeChallenge(verifier, challenge string) bool {
+	if len(verifier) < pkceVerifierMin || len(verifier) > pkceVerifierMax {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type orderHistoryService struct {
+	orderRepo   repository.OrderRepository
+	paymentRepo repository.PaymentRepository
+	refundRepo  repository.RefundRepository
+}
+
+// NewOrderHistoryService creates a new OrderHistoryService instance
+func NewOrderHistoryService(orderRepo repository.OrderRepository, paymentRepo repository.PaymentRepository, refundRepo repository.RefundRepository) service.OrderHistoryService {
+	return &orderHistoryService{
+		orderRepo:   orderRepo,
+		paymentRepo: paymentRepo,
+		refundRepo:  refundRepo,
+	}
+}
+
+// AsOf replays the status history up to at. Payments and refunds leave pending once, at
+// their updated_at, so their status at the time follows from their timestamps. Items
+// don't change after the order is placed
+func (s *orderHistoryService) AsOf(ctx context.Context, orderID uuid.UUID, at time.Time) (*dto.OrderAsOfResponse, error) {
+	order, err := s.orderRepo.GetByIDWithItems(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if at.Before(order.CreatedAt) {
+		return nil, apperror.ErrOrderNotYetPlaced
+	}
+
+	history, err := s.orderRepo.GetStatusHistory(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	// every order is placed pending
+	status := entities.OrderStatusPending
+	events := make([]dto.OrderStatusEventResponse, 0, len(history))
+	for _, e := range history {
+		if e.CreatedAt.After(at) {
+			break
+		}
+		status = e.Status
+		events = append(events, dto.OrderStatusEventResponse{Status: e.Status.String(), Note: e.Note, At: e.CreatedAt.Format(time.RFC3339)})
+	}
+
+	response := dto.OrderAsOfResponse{
+		OrderID:       order.ID.String(),
+		AsOf:          at.Format(time.RFC3339),
+		CustomerID:    order.CustomerID.String(),
+		Status:        status.String(),
+		TotalAmount:   order.TotalAmount,
+		Items:         dto.ToOrderResponse(order).Items,
+		History:       events,
+		Refunds:       []dto.RefundResponse{},
+		PlacedAt:      order.CreatedAt.Format(time.RFC3339),
+		CurrentStatus: order.Status.String(),
+	}
+
+	payments, err := s.paymentRepo.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range payments {
+		if p.CreatedAt.After(at) {
+			break
+		}
+		payment := *p
+		payment.ClientSecret = ""
+		if payment.UpdatedAt.After(at) {
+			payment.Status = entities.PaymentStatusPending
+			payment.FailureReason = ""
+			payment.UpdatedAt = payment.CreatedAt
+		}
+		paymentResponse := dto.ToPaymentResponse(&payment)
+		response.Payment = &paymentResponse
+	}
+
+	refunds, err := s.refundRepo.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range refunds {
+		if r.CreatedAt.After(at) {
+			break
+		}
+		refund := *r
+		if refund.UpdatedAt.After(at) {
+			refund.Status = entities.RefundStatusPending
+			refund.ProviderRef = ""
+			refund.FailureReason = ""
+			refund.UpdatedAt = refund.CreatedAt
+		}
+		if refund.Status == entities.RefundStatusSucceeded {
+			response.RefundedAmount += refund.Amount
+		}
+		response.Refunds = append(response.Refunds, dto.ToRefundResponse(&refund))
+	}
+
+	return &response, nil
+}
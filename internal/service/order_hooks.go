@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/logger"
+	"postgresDB/pkg/metrics"
+
+	"github.com/google/uuid"
+)
+
+// Stages of order_hook_errors_total
+const (
+	hookStageBeforeCreate       = "before_create"
+	hookStageAfterCreate        = "after_create"
+	hookStageBeforePayment      = "before_payment"
+	hookStageAfterPayment       = "after_payment"
+	hookStageBeforeStatusChange = "before_status_change"
+	hookStageAfterStatusChange  = "after_status_change"
+)
+
+var orderHookErrors = metrics.NewCounterVec("order_hook_errors_total",
+	"Number of order hooks that returned an error, by stage", "stage")
+
+// OrderHooks holds the order lifecycle hooks of a deployment, run in the order they were
+// registered. A nil *OrderHooks runs none
+type OrderHooks struct {
+	beforeCreate       []service.BeforeOrderCreateHook
+	afterCreate        []service.AfterOrderCreateHook
+	beforePayment      []service.BeforeOrderPaymentHook
+	afterPayment       []service.AfterOrderPaymentHook
+	beforeStatusChange []service.BeforeOrderStatusChangeHook
+	afterStatusChange  []service.AfterOrderStatusChangeHook
+}
+
+// NewOrderHooks registers every hook under each of the order hook interfaces it
+// implements. A hook implementing none of them is refused, it would never run
+func NewOrderHooks(hooks ...any) (*OrderHooks, error) {
+	h := &OrderHooks{}
+	for _, hook := range hooks {
+		registered := false
+		if hook, ok := hook.(service.BeforeOrderCreateHook); ok {
+			h.beforeCreate = append(h.beforeCreate, hook)
+			registered = true
+		}
+		if hook, ok := hook.(service.AfterOrderCreateHook); ok {
+			h.afterCreate = append(h.afterCreate, hook)
+			registered = true
+		}
+		if hook, ok := hook.(service.BeforeOrderPaymentHook); ok {
+			h.beforePayment = append(h.beforePayment, hook)
+			registered = true
+		}
+		if hook, ok := hook.(service.AfterOrderPaymentHook); ok {
+			h.afterPayment = append(h.afterPayment, hook)
+			registered = true
+		}
+		if hook, ok := hook.(service.BeforeOrderStatusChangeHook); ok {
+			h.beforeStatusChange = append(h.beforeStatusChange, hook)
+			registered = true
+		}
+		if hook, ok := hook.(service.AfterOrderStatusChangeHook); ok {
+			h.afterStatusChange = append(h.afterStatusChange, hook)
+			registered = true
+		}
+		if !registered {
+			return nil, fmt.Errorf("order hook %T implements no order hook interface", hook)
+		}
+	}
+	return h, nil
+}
+
+// BeforeCreate runs the before create hooks until one refuses the order
+func (h *OrderHooks) BeforeCreate(ctx context.Context, order *entities.Order) error {
+	if h == nil {
+		return nil
+	}
+	for _, hook := range h.beforeCreate {
+		if err := hook.BeforeOrderCreate(ctx, order); err != nil {
+			hookRefused(hookStageBeforeCreate, hook, order.ID, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// AfterCreate runs the after create hooks, logging their errors
+func (h *OrderHooks) AfterCreate(ctx context.Context, order *entities.Order) {
+	if h == nil {
+		return
+	}
+	for _, hook := range h.afterCreate {
+		if err := hook.AfterOrderCreate(ctx, order); err != nil {
+			hookFailed(hookStageAfterCreate, hook, order.ID, err)
+		}
+	}
+}
+
+// BeforePayment runs the before payment hooks until one refuses the payment
+func (h *OrderHooks) BeforePayment(ctx context.Context, order *entities.Order, payment *entities.Payment) error {
+	if h == nil {
+		return nil
+	}
+	for _, hook := range h.beforePayment {
+		if err := hook.BeforeOrderPayment(ctx, order, payment); err != nil {
+			hookRefused(hookStageBeforePayment, hook, order.ID, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// AfterPayment runs the after payment hooks, logging their errors
+func (h *OrderHooks) AfterPayment(ctx context.Context, payment *entities.Payment) {
+	if h == nil {
+		return
+	}
+	for _, hook := range h.afterPayment {
+		if err := hook.AfterOrderPayment(ctx, payment); err != nil {
+			hookFailed(hookStageAfterPayment, hook, payment.OrderID, err)
+		}
+	}
+}
+
+// BeforeStatusChange runs the before status change hooks until one refuses the change
+func (h *OrderHooks) BeforeStatusChange(ctx context.Context, order *entities.Order, status entities.OrderStatus) error {
+	if h == nil {
+		return nil
+	}
+	for _, hook := range h.beforeStatusChange {
+		if err := hook.BeforeOrderStatusChange(ctx, order, status); err != nil {
+			hookRefused(hookStageBeforeStatusChange, hook, order.ID, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// AfterStatusChange runs the after status change hooks, logging their errors
+func (h *OrderHooks) AfterStatusChange(ctx context.Context, order *entities.Order, from entities.OrderStatus) {
+	if h == nil {
+		return
+	}
+	for _, hook := range h.afterStatusChange {
+		if err := hook.AfterOrderStatusChange(ctx, order, from); err != nil {
+			hookFailed(hookStageAfterStatusChange, hook, order.ID, err)
+		}
+	}
+}
+
+// AfterStatusChangeOf loads an order moved by a repository and runs the after status
+// change hooks for it. The order is only loaded when there are hooks to run
+func (h *OrderHooks) AfterStatusChangeOf(ctx context.Context, orderRepo repository.OrderRepository, id uuid.UUID, from entities.OrderStatus) {
+	if h == nil || len(h.afterStatusChange) == 0 {
+		return
+	}
+	order, err := orderRepo.GetByIDWithItems(ctx, id)
+	if err != nil {
+		logger.ErrorContext(ctx, "loading order for order hooks failed", "order_id", id, "error", err)
+		orderHookErrors.With(hookStageAfterStatusChange).Inc()
+		return
+	}
+	h.AfterStatusChange(ctx, order, from)
+}
+
+// hookRefused logs a before hook that refused an order change
+func hookRefused(stage string, hook any, orderID uuid.UUID, err error) {
+	logger.Info("order hook refused change", "stage", stage, "hook", fmt.Sprintf("%T", hook), "order_id", orderID, "error", err)
+	orderHookErrors.With(stage).Inc()
+}
+
+// hookFailed logs an after hook that failed, the change it followed stands
+func hookFailed(stage string, hook any, orderID uuid.UUID, err error) {
+	logger.Error("order hook failed", "stage", stage, "hook", fmt.Sprintf("%T", hook), "order_id", orderID, "error", err)
+	orderHookErrors.With(stage).Inc()
+}
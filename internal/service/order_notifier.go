@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderNotifier is the order hook notifying customers: an order confirmation when they
+// place an order, and a status update when it reaches one of the chosen statuses, with
+// the order's shipments so a shipped notification carries the tracking numbers
+type OrderNotifier struct {
+	notifier    service.Notifier
+	productRepo repository.ProductRepository
+	statuses    map[entities.OrderStatus]bool
+}
+
+// NewOrderNotifier creates an OrderNotifier sending status updates on the given statuses,
+// none turns them off
+func NewOrderNotifier(notifier service.Notifier, productRepo repository.ProductRepository, statuses []string) (*OrderNotifier, error) {
+	n := &OrderNotifier{
+		notifier:    notifier,
+		productRepo: productRepo,
+		statuses:    make(map[entities.OrderStatus]bool, len(statuses)),
+	}
+	for _, status := range statuses {
+		s := entities.OrderStatus(status)
+		if !s.IsValid() {
+			return nil, fmt.Errorf("unknown order status %q", status)
+		}
+		n.statuses[s] = true
+	}
+	return n, nil
+}
+
+// AfterOrderCreate queues the order confirmation, listing the items with their product names
+func (n *OrderNotifier) AfterOrderCreate(ctx context.Context, order *entities.Order) error {
+	ids := make([]uuid.UUID, len(order.Items))
+	for i, item := range order.Items {
+		ids[i] = item.ProductID
+	}
+	products, err := n.productRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	names := make(map[uuid.UUID]string, len(products))
+	for _, product := range products {
+		names[product.ID] = product.Name
+	}
+
+	items := make([]map[string]any, len(order.Items))
+	for i, item := range order.Items {
+		items[i] = map[string]any{
+			"ProductID": item.ProductID.String(),
+			"Name":      names[item.ProductID],
+			"Quantity":  item.Quantity,
+			"UnitPrice": entities.FormatAmount(item.UnitPrice),
+			"Subtotal":  entities.FormatAmount(item.SubTotal),
+		}
+	}
+	return n.notifier.Notify(ctx, order.CustomerID, entities.NotificationOrderConfirmation, map[string]any{
+		"OrderID":      order.ID.String(),
+		"Items":        items,
+		"Subtotal":     entities.FormatAmount(order.Subtotal),
+		"TaxAmount":    entities.FormatAmount(order.TaxAmount),
+		"ShippingCost": entities.FormatAmount(order.ShippingCost),
+		"TotalAmount":  entities.FormatAmount(order.TotalAmount),
+	})
+}
+
+// AfterOrderStatusChange queues the status update of a chosen status
+func (n *OrderNotifier) AfterOrderStatusChange(ctx context.Context, order *entities.Order, from entities.OrderStatus) error {
+	if !n.statuses[order.Status] {
+		return nil
+	}
+	shipments := make([]map[string]any, len(order.Shipments))
+	for i, shipment := range order.Shipments {
+		shipments[i] = map[string]any{
+			"Carrier":        shipment.Carrier,
+			"TrackingNumber": shipment.TrackingNumber,
+			"ShippedAt":      shipment.ShippedAt.Format(time.RFC3339),
+		}
+	}
+	return n.notifier.Notify(ctx, order.CustomerID, entities.NotificationOrderStatus, map[string]any{
+		"OrderID":        order.ID.String(),
+		"Status":         order.Status.String(),
+		"PreviousStatus": from.String(),
+		"TotalAmount":    entities.FormatAmount(order.TotalAmount),
+		"Carrier":        order.Carrier,
+		"TrackingNumber": order.TrackingNumber,
+		"Shipments":      shipments,
+	})
+}
@@ -1,82 +1,358 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"postgresDB/internal/domain/dto"
 	"postgresDB/internal/domain/entities"
 	apperror "postgresDB/internal/domain/errors"
 	"postgresDB/internal/domain/repository"
 	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/logger"
+	"postgresDB/pkg/pagination"
+	"postgresDB/pkg/utils"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const (
+	// reservationSweepBatch caps the expired reservations released per query
+	reservationSweepBatch = 100
+	// favoriteCategories is how many favorite categories the customer statistics list
+	favoriteCategories = 3
+)
+
+// ReservationConfig controls how long orders hold their stock before being paid
+type ReservationConfig struct {
+	// TTL is how long a pending order holds its stock
+	TTL time.Duration
+}
+
+// OrderLimits bounds the size of an order
+type OrderLimits struct {
+	// MaxItems is the most distinct products in an order
+	MaxItems int
+	// MaxItemQuantity is the most units of one product in an order
+	MaxItemQuantity int
+}
+
 type orderService struct {
-	orderRepo   repository.OrderRepository
-	productRepo repository.ProductRepository
+	orderRepo       repository.OrderRepository
+	productRepo     repository.ProductRepository
+	userRepo        repository.UserRepository
+	reservationRepo repository.StockReservationRepository
+	refundRepo      repository.RefundRepository
+	addressRepo     repository.AddressRepository
+	shipmentRepo    repository.ShipmentRepository
+	txManager       repository.TxManager
+	jobs            service.JobQueue
+	shipping        service.ShippingService
+	tax             service.TaxService
+	hooks           *OrderHooks
+	reservations    ReservationConfig
+	limits          OrderLimits
 }
 
-func NewOrderService(orderRepo repository.OrderRepository, productRepo repository.ProductRepository) service.OrderService {
+func NewOrderService(orderRepo repository.OrderRepository, productRepo repository.ProductRepository, userRepo repository.UserRepository, reservationRepo repository.StockReservationRepository, refundRepo repository.RefundRepository, addressRepo repository.AddressRepository, shipmentRepo repository.ShipmentRepository, txManager repository.TxManager, jobs service.JobQueue, shipping service.ShippingService, tax service.TaxService, hooks *OrderHooks, reservations ReservationConfig, limits OrderLimits) service.OrderService {
 	return &orderService{
-		orderRepo:   orderRepo,
-		productRepo: productRepo,
+		orderRepo:       orderRepo,
+		productRepo:     productRepo,
+		userRepo:        userRepo,
+		reservationRepo: reservationRepo,
+		refundRepo:      refundRepo,
+		addressRepo:     addressRepo,
+		shipmentRepo:    shipmentRepo,
+		txManager:       txManager,
+		jobs:            jobs,
+		shipping:        shipping,
+		tax:             tax,
+		hooks:           hooks,
+		reservations:    reservations,
+		limits:          limits,
 	}
 }
 
 func (s *orderService) Create(ctx context.Context, customerID uuid.UUID, req dto.CreateOrderRequest) (*dto.OrderResponse, error) {
+	response, err := s.create(ctx, customerID, req, nil)
+	recordCheckout(checkoutStageOrder, err)
+	return response, err
+}
+
+// CreateAsGuest places an order for the guest customer with the email, created on their
+// first order, and emails the guest a tracking link
+func (s *orderService) CreateAsGuest(ctx context.Context, req dto.GuestOrderRequest) (*dto.OrderResponse, error) {
+	guest, err := s.userRepo.GetOrCreateGuest(ctx, utils.NormalizeEmail(req.Email))
+	if err != nil {
+		recordCheckout(checkoutStageOrder, err)
+		return nil, err
+	}
+	response, err := s.create(ctx, guest.ID, dto.CreateOrderRequest{
+		Items:          req.Items,
+		ShippingMethod: req.ShippingMethod,
+	}, guestAddress(req.ShippingAddress))
+	recordCheckout(checkoutStageOrder, err)
+	if err != nil {
+		return nil, err
+	}
+
+	// the order is placed, a failed enqueue is only logged
+	if _, err := s.jobs.Enqueue(ctx, DefaultJobQueue, JobTypeGuestOrderLink, guestOrderLink{OrderID: response.ID}); err != nil {
+		logger.ErrorContext(ctx, "enqueueing guest order link failed", "order_id", response.ID, "error", err)
+	}
+	return response, nil
+}
+
+// create places the order, Create and CreateAsGuest wrap it to count the checkout attempt.
+// It ships to address, or to the customer's address book when nil
+func (s *orderService) create(ctx context.Context, customerID uuid.UUID, req dto.CreateOrderRequest, address *entities.ShippingAddress) (*dto.OrderResponse, error) {
 	// Create order entity
 	order := &entities.Order{
 		ID:          uuid.New(),
 		CustomerID:  customerID,
 		Status:      entities.OrderStatusPending,
 		TotalAmount: 0,
+		TaxLines:    []entities.TaxLine{},
 		Items:       []entities.OrderItem{},
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
-	// Validate and create order items
-	for _, itemReq := range req.Items {
-		// Check product existence and stock
-		product, err := s.productRepo.GetByID(ctx, itemReq.ProductID)
-		if err != nil {
+	items, err := s.consolidateItems(req.Items)
+	if err != nil {
+		return nil, err
+	}
+	productIDs := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		productIDs[i] = item.ProductID
+	}
+	if address == nil {
+		if address, err = s.shippingAddress(ctx, customerID, req.AddressID); err != nil {
 			return nil, err
 		}
+	}
+	order.ShippingAddress = address
+	// items are taxed at the rate of the country they ship to
+	region := ""
+	if address != nil {
+		region = address.Country
+	}
 
-		if product.Stock < itemReq.Quantity {
-			return nil, apperror.ErrInsufficientStock
+	// Check the stock, reserve it and save the order in one transaction; the products are
+	// locked so concurrent orders for them wait for this one. The deployment hooks run in
+	// it, so it isn't run again after a transient failure
+	var stockLevels []*entities.StockLevel
+	err = s.txManager.WithinTxNoRetry(ctx, func(ctx context.Context) error {
+		locked, err := s.productRepo.GetByIDsForUpdate(ctx, productIDs)
+		if err != nil {
+			return err
 		}
-		// Create order item
-		orderItem := entities.OrderItem{
-			ID:        uuid.New(),
-			OrderID:   order.ID,
-			ProductID: itemReq.ProductID,
-			Quantity:  itemReq.Quantity,
-			UnitPrice: product.Price,
-			SubTotal:  product.Price * float64(itemReq.Quantity),
-			CreatedAt: time.Now(),
+		products := make(map[uuid.UUID]*entities.Product, len(locked))
+		for _, product := range locked {
+			products[product.ID] = product
 		}
 
-		// Append order item to order
-		order.Items = append(order.Items, orderItem)
-		order.TotalAmount += orderItem.SubTotal
-		// Update product stock
-		product.Stock -= itemReq.Quantity
-		if err := s.productRepo.UpdateStock(ctx, product.ID, product.Stock); err != nil {
-			return nil, err
+		// Validate and create order items
+		reservations := make([]entities.StockReservation, 0, len(items))
+		weight := 0
+		for _, itemReq := range items {
+			// Check product existence and stock
+			product, ok := products[itemReq.ProductID]
+			if !ok {
+				ordersRejected.With(orderRejectedProductNotFound).Inc()
+				return apperror.ErrProductNotFound
+			}
+			if product.Stock-product.ReservedStock < itemReq.Quantity {
+				ordersRejected.With(orderRejectedOutOfStock).Inc()
+				return apperror.ErrInsufficientStock
+			}
+			// Create order item
+			orderItem := entities.OrderItem{
+				ID:        uuid.New(),
+				OrderID:   order.ID,
+				ProductID: itemReq.ProductID,
+				Quantity:  itemReq.Quantity,
+				UnitPrice: product.Price,
+				SubTotal:  product.Price * int64(itemReq.Quantity),
+				CreatedAt: time.Now(),
+			}
+			if rate := s.tax.Rate(region, product.Category); rate != nil {
+				orderItem.TaxAmount = rate.Tax(orderItem.SubTotal)
+				order.TaxLines = addTaxLine(order.TaxLines, rate, orderItem.SubTotal, orderItem.TaxAmount)
+			}
+
+			// Append order item to order
+			order.Items = append(order.Items, orderItem)
+			order.Subtotal += orderItem.SubTotal
+			order.TaxAmount += orderItem.TaxAmount
+			order.TotalAmount += orderItem.SubTotal + orderItem.TaxAmount
+			weight += product.WeightGrams * itemReq.Quantity
+			// Hold the stock until the order is paid
+			reservations = append(reservations, entities.StockReservation{
+				ID:        uuid.New(),
+				OrderID:   order.ID,
+				ProductID: itemReq.ProductID,
+				Quantity:  itemReq.Quantity,
+				Status:    entities.ReservationActive,
+				ExpiresAt: order.CreatedAt.Add(s.reservations.TTL),
+				CreatedAt: order.CreatedAt,
+			})
 		}
 
-	}
-	// Save order to repository
+		// Shipping is priced on the weight of the whole order
+		if len(s.shipping.Methods()) > 0 || req.ShippingMethod != "" {
+			method, cost, err := s.shipping.Quote(req.ShippingMethod, weight)
+			if err != nil {
+				return err
+			}
+			order.ShippingMethod = method.Code
+			order.ShippingCost = cost
+			order.TotalAmount += cost
+		}
 
-	if err := s.orderRepo.Create(ctx, order); err != nil {
+		// deployment hooks see the complete order and may still refuse it
+		if err := s.hooks.BeforeCreate(ctx, order); err != nil {
+			ordersRejected.With(orderRejectedHook).Inc()
+			return err
+		}
+
+		stockLevels, err = s.reservationRepo.Reserve(ctx, reservations)
+		if err != nil {
+			return err
+		}
+		// Save order to repository
+		return s.orderRepo.Create(ctx, order)
+	})
+	if err != nil {
 		return nil, err
 	}
+	ordersCreated.Inc()
+	orderValue.Observe(float64(order.TotalAmount) / entities.MinorUnits)
+	for _, level := range stockLevels {
+		if level.Previous > 0 && level.Current <= 0 {
+			stockouts.Inc()
+		}
+	}
+	s.alertLowStock(ctx, stockLevels)
+	s.hooks.AfterCreate(ctx, order)
 
 	response := dto.ToOrderResponse(order)
 	return &response, nil
 }
 
+// consolidateItems merges the items listing the same product, keeping the position of
+// the first, and checks them against the order limits. Errors point at the item index
+// of the request
+func (s *orderService) consolidateItems(requested []dto.OrderItemRequest) ([]dto.OrderItemRequest, error) {
+	var details []apperror.ValidationError
+	items := make([]dto.OrderItemRequest, 0, len(requested))
+	// positions holds the request index of every merged item
+	positions := make([]int, 0, len(requested))
+	merged := make(map[uuid.UUID]int, len(requested))
+	for i, item := range requested {
+		if item.Quantity > s.limits.MaxItemQuantity {
+			details = append(details, apperror.ValidationError{
+				Field:   fmt.Sprintf("items[%d].quantity", i),
+				Message: "quantity maksimal %d per produk",
+				Args:    []any{s.limits.MaxItemQuantity},
+			})
+		}
+		if j, ok := merged[item.ProductID]; ok {
+			items[j].Quantity += item.Quantity
+			continue
+		}
+		merged[item.ProductID] = len(items)
+		items = append(items, item)
+		positions = append(positions, i)
+	}
+
+	if len(details) == 0 {
+		// duplicates can add up past the limit although every item is within it
+		for j, item := range items {
+			if item.Quantity > s.limits.MaxItemQuantity {
+				details = append(details, apperror.ValidationError{
+					Field:   fmt.Sprintf("items[%d].quantity", positions[j]),
+					Message: "produk ini ada di beberapa item dengan total quantity %d, maksimal %d per produk",
+					Args:    []any{item.Quantity, s.limits.MaxItemQuantity},
+				})
+			}
+		}
+	}
+	if len(items) > s.limits.MaxItems {
+		details = append(details, apperror.ValidationError{
+			Field:   "items",
+			Message: "pesanan maksimal berisi %d produk berbeda",
+			Args:    []any{s.limits.MaxItems},
+		})
+	}
+	if len(details) > 0 {
+		return nil, apperror.NewValidationError(details)
+	}
+	return items, nil
+}
+
+// addTaxLine adds the tax of an item to the line of its rate, lines keep the order in
+// which their rate was first applied
+func addTaxLine(lines []entities.TaxLine, rate *entities.TaxRate, taxable, amount int64) []entities.TaxLine {
+	name := rate.Name()
+	for i := range lines {
+		if lines[i].Name == name {
+			lines[i].TaxableAmount += taxable
+			lines[i].Amount += amount
+			return lines
+		}
+	}
+	return append(lines, entities.TaxLine{Name: name, Rate: rate.Rate, TaxableAmount: taxable, Amount: amount})
+}
+
+// shippingAddress copies the address the order ships to, the chosen address book entry
+// or the customer's default address. An address is required once shipping is set up
+func (s *orderService) shippingAddress(ctx context.Context, customerID uuid.UUID, addressID *uuid.UUID) (*entities.ShippingAddress, error) {
+	if addressID != nil {
+		address, err := s.addressRepo.GetByID(ctx, *addressID)
+		if err != nil {
+			return nil, err
+		}
+		if address.UserID != customerID {
+			return nil, apperror.ErrAddressNotFound
+		}
+		return address.Snapshot(), nil
+	}
+
+	address, err := s.addressRepo.GetDefault(ctx, customerID)
+	if errors.Is(err, apperror.ErrAddressNotFound) {
+		if len(s.shipping.Methods()) > 0 {
+			return nil, apperror.ErrShippingAddressRequired
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return address.Snapshot(), nil
+}
+
+// guestAddress copies the address sent with a guest order
+func guestAddress(req *dto.ShippingAddressRequest) *entities.ShippingAddress {
+	address := &entities.ShippingAddress{
+		RecipientName: strings.TrimSpace(req.RecipientName),
+		Phone:         strings.TrimSpace(req.Phone),
+		Street:        strings.TrimSpace(req.Street),
+		City:          strings.TrimSpace(req.City),
+		Province:      strings.TrimSpace(req.Province),
+		PostalCode:    strings.TrimSpace(req.PostalCode),
+		Country:       strings.ToUpper(req.Country),
+	}
+	if address.Country == "" {
+		address.Country = defaultCountry
+	}
+	return address
+}
+
 func (s *orderService) GetByID(ctx context.Context, id uuid.UUID, requesterID uuid.UUID, requesterRole entities.Role) (*dto.OrderResponse, error) {
 	// Get order by ID
 	order, err := s.orderRepo.GetByIDWithItems(ctx, id)
@@ -91,7 +367,29 @@ func (s *orderService) GetByID(ctx context.Context, id uuid.UUID, requesterID uu
 	return &response, nil
 }
 
+// CustomerStats aggregates the paid orders of a customer; customers only see their own
+func (s *orderService) CustomerStats(ctx context.Context, customerID, requesterID uuid.UUID, requesterRole entities.Role) (*dto.CustomerOrderStatsResponse, error) {
+	if requesterRole != entities.RoleAdmin && customerID != requesterID {
+		return nil, apperror.ErrForbidden
+	}
+	if _, err := s.userRepo.GetByID(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.orderRepo.CustomerStats(ctx, customerID, favoriteCategories)
+	if err != nil {
+		return nil, err
+	}
+	response := dto.ToCustomerOrderStatsResponse(customerID, stats)
+	return &response, nil
+}
+
 func (s *orderService) ListAll(ctx context.Context, UserID uuid.UUID, requesterRole entities.Role, req dto.OrderListRequest) ([]dto.OrderResponse, *dto.PaginationMeta, error) {
+	filter, err := orderFilter(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// set default pagination values
 	page := req.Page
 	if page <= 1 {
@@ -106,27 +404,18 @@ func (s *orderService) ListAll(ctx context.Context, UserID uuid.UUID, requesterR
 	// Fetch orders based on role
 	var orders []*entities.Order
 	var total int64
-	var err error
 
 	// Admin can see all orders, users can see their own orders
 	if requesterRole == entities.RoleAdmin {
-		orders, total, err = s.orderRepo.ListAll(ctx, limit, offset, req.Status)
+		orders, total, err = s.orderRepo.ListAll(ctx, limit, offset, filter)
 	} else {
-		orders, total, err = s.orderRepo.GetByCustomerID(ctx, UserID, limit, offset, req.Status)
+		filter.CustomerEmail = ""
+		orders, total, err = s.orderRepo.GetByCustomerID(ctx, UserID, limit, offset, filter)
 	}
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Populate items for each order
-	// for _, order := range orders {
-	// 	items, err := s.orderRepo.GetOrderItemsByOrderID(ctx, order.ID)
-	// 	if err != nil {
-	// 		return nil, nil, err
-	// 	}
-	// 	order.Items = items
-	// }
-
 	responseList := dto.ToOrderResponseList(orders)
 	pagination := &dto.PaginationMeta{
 		Total:      total,
@@ -137,18 +426,137 @@ func (s *orderService) ListAll(ctx context.Context, UserID uuid.UUID, requesterR
 	return responseList, pagination, nil
 }
 
-func (s *orderService) UpdateStatus(ctx context.Context, id uuid.UUID, requesterID uuid.UUID, requesterRole entities.Role, req *dto.UpdateOrderRequest) (*dto.OrderResponse, error) {
-	// Only admin can update order status
+// ListByCursor retrieves a page of orders using keyset pagination; admins see all orders,
+// users only their own
+func (s *orderService) ListByCursor(ctx context.Context, userID uuid.UUID, requesterRole entities.Role, req dto.OrderListRequest) ([]dto.OrderResponse, *dto.CursorMeta, error) {
+	filter, err := orderFilter(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	limit := req.Limit
+	if limit <= 1 {
+		limit = 10
+	}
+
+	after, err := decodeCursor(req.Cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	if requesterRole != entities.RoleAdmin {
-		return nil, apperror.ErrForbidden
+		filter.CustomerID, filter.CustomerEmail = &userID, ""
 	}
 
-	// Get existing order
-	order, err := s.orderRepo.GetByID(ctx, id)
+	// one extra row tells whether another page exists
+	orders, err := s.orderRepo.ListAfter(ctx, limit+1, after, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta := &dto.CursorMeta{Limit: limit}
+	if len(orders) > limit {
+		orders = orders[:limit]
+		last := orders[limit-1]
+		meta.HasMore = true
+		meta.NextCursor = pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	return dto.ToOrderResponseList(orders), meta, nil
+}
+
+// orderFilter checks the ranges of an order listing and converts it to a repository filter
+func orderFilter(req dto.OrderListRequest) (repository.OrderFilter, error) {
+	var details []apperror.ValidationError
+	if !req.From.IsZero() && !req.To.IsZero() && !req.To.After(req.From) {
+		details = append(details, apperror.ValidationError{Field: "to", Message: "to harus setelah from"})
+	}
+	if req.MinTotal != nil && req.MaxTotal != nil && *req.MaxTotal < *req.MinTotal {
+		details = append(details, apperror.ValidationError{Field: "max_total", Message: "max_total tidak boleh kurang dari min_total"})
+	}
+	if len(details) > 0 {
+		return repository.OrderFilter{}, apperror.NewValidationError(details)
+	}
+
+	return repository.OrderFilter{
+		Statuses:      req.Status,
+		CustomerID:    req.CustomerID,
+		CustomerEmail: strings.TrimSpace(req.CustomerEmail),
+		From:          req.From,
+		To:            req.To,
+		MinTotal:      req.MinTotal,
+		MaxTotal:      req.MaxTotal,
+		WithItems:     req.IncludeItems,
+	}, nil
+}
+
+// PackingSlips builds the packing slips of the given orders. Lines are sorted by product
+// name so pickers can walk through them in a stable order
+func (s *orderService) PackingSlips(ctx context.Context, ids []uuid.UUID) ([]dto.PackingSlip, error) {
+	orders, err := s.orderRepo.GetByIDsWithItems(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) != len(ids) {
+		return nil, apperror.ErrOrderNotFound
+	}
+
+	// Load every product on the slips at once
+	productIDs := make([]uuid.UUID, 0)
+	for _, o := range orders {
+		for _, item := range o.Items {
+			productIDs = append(productIDs, item.ProductID)
+		}
+	}
+	// archived products still show up on slips of orders placed before archiving
+	products, err := s.productRepo.FindAll(ctx, repository.ProductFilter{IDs: productIDs, Archived: repository.ArchivedInclude})
 	if err != nil {
 		return nil, err
 	}
+	productByID := make(map[uuid.UUID]*entities.Product, len(products))
+	for _, p := range products {
+		productByID[p.ID] = p
+	}
 
+	customers := make(map[uuid.UUID]*entities.User)
+	slips := make([]dto.PackingSlip, 0, len(orders))
+	for _, o := range orders {
+		customer, ok := customers[o.CustomerID]
+		if !ok {
+			// orders of deleted customers are still shipped
+			if customer, err = s.userRepo.GetByIDScoped(ctx, o.CustomerID, repository.DeletedInclude); err != nil {
+				return nil, err
+			}
+			customers[o.CustomerID] = customer
+		}
+
+		slip := dto.PackingSlip{
+			OrderID:        o.ID.String(),
+			OrderDate:      o.CreatedAt.Format("2006-01-02 15:04"),
+			Status:         o.Status.String(),
+			CustomerName:   customer.Username,
+			CustomerEmail:  customer.Email,
+			ShipTo:         dto.ToShippingAddressResponse(o.ShippingAddress),
+			ShippingMethod: o.ShippingMethod,
+			Items:          make([]dto.PackingSlipItem, 0, len(o.Items)),
+		}
+		for _, item := range o.Items {
+			line := dto.PackingSlipItem{ProductID: item.ProductID.String(), Quantity: item.Quantity}
+			if p, ok := productByID[item.ProductID]; ok {
+				line.Name = p.Name
+				line.Category = p.Category
+			}
+			slip.Items = append(slip.Items, line)
+			slip.TotalQuantity += item.Quantity
+		}
+		sort.SliceStable(slip.Items, func(i, j int) bool { return slip.Items[i].Name < slip.Items[j].Name })
+		slips = append(slips, slip)
+	}
+	return slips, nil
+}
+
+// UpdateStatus moves an order along its lifecycle. Admins make any valid change;
+// customers may only cancel their unpaid orders and confirm delivery of their own
+func (s *orderService) UpdateStatus(ctx context.Context, id uuid.UUID, requesterID uuid.UUID, requesterRole entities.Role, req *dto.UpdateOrderRequest) (*dto.OrderResponse, error) {
 	// parse and validate new status
 	newStatus := entities.OrderStatus(req.Status)
 	if !newStatus.IsValid() {
@@ -157,36 +565,220 @@ func (s *orderService) UpdateStatus(ctx context.Context, id uuid.UUID, requester
 		})
 	}
 
-	// check if transition is valid
-	if !order.Status.CanTransitionTo(newStatus) {
-		return nil, apperror.ErrInvalidStatusTransition
-	}
+	// The status, the stock and the shipment change in one transaction. The order is locked
+	// first, so a concurrent change waits for this one and is checked against the status it
+	// left behind: a cancelled order is never restocked twice. The hooks run in it, so it
+	// isn't run again after a transient failure
+	var order *entities.Order
+	err := s.txManager.WithinTxNoRetry(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.orderRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if requesterRole != entities.RoleAdmin {
+			if order.CustomerID != requesterID {
+				return apperror.ErrForbidden
+			}
+			// shipment details are recorded by admins
+			if req.Carrier != nil || req.TrackingNumber != nil {
+				return apperror.ErrForbidden
+			}
+		}
 
-	// if canceling, restock products
-	if newStatus == entities.OrderStatusCancelled {
-		for _, item := range order.Items {
-			product, err := s.productRepo.GetByID(ctx, item.ProductID)
-			if err != nil {
-				return nil, err
+		// shipment details record a shipment, completed from the order's latest one
+		var shipment *entities.Shipment
+		if req.Carrier != nil || req.TrackingNumber != nil {
+			now := time.Now()
+			shipment = &entities.Shipment{
+				ID:             uuid.New(),
+				OrderID:        id,
+				Carrier:        order.Carrier,
+				TrackingNumber: order.TrackingNumber,
+				ShippedAt:      now,
+				CreatedBy:      &requesterID,
+				CreatedAt:      now,
 			}
-			product.Stock += item.Quantity
-			if err := s.productRepo.UpdateStock(ctx, product.ID, product.Stock); err != nil {
-				return nil, err
+			if req.Carrier != nil {
+				shipment.Carrier = strings.TrimSpace(*req.Carrier)
+			}
+			if req.TrackingNumber != nil {
+				shipment.TrackingNumber = strings.TrimSpace(*req.TrackingNumber)
+			}
+			if shipment.Carrier == "" || shipment.TrackingNumber == "" {
+				return apperror.NewValidationError([]apperror.ValidationError{
+					{Field: "tracking_number", Message: "carrier dan tracking_number wajib diisi untuk mencatat pengiriman"},
+				})
 			}
 		}
-	}
 
-	// update order status
-	if err := s.orderRepo.UpdateStatus(ctx, id, newStatus); err != nil {
+		// check if transition is valid; orders waiting for payment capture are moved on by
+		// the capture retries only
+		if order.Status == entities.OrderStatusPendingCapture || !order.Status.CanTransitionTo(newStatus) {
+			return apperror.ErrInvalidStatusTransition
+		}
+		if !order.Status.CanTransitionAs(newStatus, requesterRole) {
+			return apperror.ErrForbidden
+		}
+		if err := s.hooks.BeforeStatusChange(ctx, order, newStatus); err != nil {
+			return err
+		}
+
+		switch newStatus {
+		case entities.OrderStatusPaid:
+			// a paid order keeps its stock for good, taken out like a payment or capture does
+			if _, err := s.reservationRepo.Convert(ctx, id); err != nil {
+				return err
+			}
+		case entities.OrderStatusCancelled:
+			if err := s.restock(ctx, order); err != nil {
+				return err
+			}
+		case entities.OrderStatusRefunded:
+			// goods that never left the warehouse go back into stock; returned parcels are
+			// booked by a stock adjustment once they're checked
+			if order.Status == entities.OrderStatusPaid || order.Status == entities.OrderStatusProcessing {
+				if err := s.restock(ctx, order); err != nil {
+					return err
+				}
+			}
+		}
+
+		// update order status
+		if err := s.orderRepo.UpdateStatus(ctx, id, newStatus); err != nil {
+			return err
+		}
+		if shipment != nil {
+			return s.shipmentRepo.Create(ctx, shipment)
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
+	orderStatusChanges.With(string(newStatus)).Inc()
 
 	// get updated order with items
 	updatedOrder, err := s.orderRepo.GetByIDWithItems(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	s.hooks.AfterStatusChange(ctx, updatedOrder, order.Status)
 
 	response := dto.ToOrderResponse(updatedOrder)
 	return &response, nil
 }
+
+// AddShipment records a shipment of an order that is paid and not delivered yet. Several
+// parcels may be recorded; recording one doesn't change the status
+func (s *orderService) AddShipment(ctx context.Context, orderID, adminID uuid.UUID, req dto.CreateShipmentRequest) (*dto.ShipmentResponse, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	switch order.Status {
+	case entities.OrderStatusPaid, entities.OrderStatusProcessing, entities.OrderStatusShipped:
+	default:
+		return nil, apperror.ErrShipmentNotAllowed
+	}
+
+	now := time.Now()
+	shippedAt := now
+	if req.ShippedAt != nil {
+		if req.ShippedAt.After(now) {
+			return nil, apperror.NewValidationError([]apperror.ValidationError{
+				{Field: "shipped_at", Message: "shipped_at tidak boleh di masa depan"},
+			})
+		}
+		if req.ShippedAt.Before(order.CreatedAt) {
+			return nil, apperror.NewValidationError([]apperror.ValidationError{
+				{Field: "shipped_at", Message: "shipped_at tidak boleh sebelum pesanan dibuat"},
+			})
+		}
+		shippedAt = *req.ShippedAt
+	}
+
+	shipment := &entities.Shipment{
+		ID:             uuid.New(),
+		OrderID:        orderID,
+		Carrier:        strings.TrimSpace(req.Carrier),
+		TrackingNumber: strings.TrimSpace(req.TrackingNumber),
+		ShippedAt:      shippedAt,
+		CreatedBy:      &adminID,
+		CreatedAt:      now,
+	}
+	if err := s.shipmentRepo.Create(ctx, shipment); err != nil {
+		return nil, err
+	}
+	logger.InfoContext(ctx, "shipment recorded", "order_id", orderID, "shipment_id", shipment.ID, "carrier", shipment.Carrier, "admin_id", adminID)
+
+	response := dto.ToShipmentResponse(shipment)
+	return &response, nil
+}
+
+// restock returns the stock of an order that won't be fulfilled, within the transaction
+// changing its status. A pending order only held its stock; a paid order, or one placed
+// before stock reservations, had the stock of its items deducted, less the items succeeded
+// refunds put back
+func (s *orderService) restock(ctx context.Context, order *entities.Order) error {
+	released, err := s.reservationRepo.Release(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	if released > 0 {
+		return nil
+	}
+	// succeeded refunds put their items back already
+	refunded, err := s.refundRepo.RefundedQuantities(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	// products are locked in id order, like placing an order does, so the two can't deadlock
+	items := slices.Clone(order.Items)
+	slices.SortFunc(items, func(a, b entities.OrderItem) int { return bytes.Compare(a.ProductID[:], b.ProductID[:]) })
+	for _, item := range items {
+		quantity := item.Quantity - refunded[item.ID]
+		if quantity <= 0 {
+			continue
+		}
+		if _, err := s.productRepo.UpdateStock(ctx, item.ProductID, quantity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SweepExpiredReservations releases expired stock reservations and cancels their unpaid
+// orders, a batch at a time until none are left
+func (s *orderService) SweepExpiredReservations(ctx context.Context) error {
+	for ctx.Err() == nil {
+		cancelled, err := s.reservationRepo.ReleaseExpired(ctx, time.Now(), reservationSweepBatch)
+		if err != nil {
+			return err
+		}
+		if len(cancelled) == 0 {
+			return nil
+		}
+		orderStatusChanges.With(string(entities.OrderStatusCancelled)).Add(float64(len(cancelled)))
+		ordersExpired.Add(float64(len(cancelled)))
+		logger.InfoContext(ctx, "unpaid orders cancelled, stock reservations expired", "orders", len(cancelled))
+		for _, id := range cancelled {
+			s.hooks.AfterStatusChangeOf(ctx, s.orderRepo, id, entities.OrderStatusPending)
+		}
+	}
+	return ctx.Err()
+}
+
+// alertLowStock enqueues an alert for every product the order took down to its reorder
+// threshold. The order is already placed, so a failed enqueue is only logged
+func (s *orderService) alertLowStock(ctx context.Context, levels []*entities.StockLevel) {
+	for _, level := range levels {
+		if !level.DroppedToReorderLevel() {
+			continue
+		}
+		alert := lowStockAlert{ProductID: level.ProductID, Stock: level.Current, ReorderThreshold: *level.ReorderThreshold}
+		if _, err := s.jobs.Enqueue(ctx, DefaultJobQueue, JobTypeLowStockAlert, alert); err != nil {
+			logger.ErrorContext(ctx, "enqueueing low stock alert failed", "product_id", level.ProductID, "error", err)
+		}
+	}
+}
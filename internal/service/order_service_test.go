@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+// The fakes embed their repository interface and implement only what UpdateStatus calls,
+// any other call panics
+
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTxManager) WithinTxNoRetry(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeOrderRepo struct {
+	repository.OrderRepository
+	order *entities.Order
+}
+
+func (r *fakeOrderRepo) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entities.Order, error) {
+	order := *r.order
+	return &order, nil
+}
+
+func (r *fakeOrderRepo) GetByIDWithItems(ctx context.Context, id uuid.UUID) (*entities.Order, error) {
+	order := *r.order
+	return &order, nil
+}
+
+func (r *fakeOrderRepo) UpdateStatus(ctx context.Context, id uuid.UUID, status entities.OrderStatus) error {
+	r.order.Status = status
+	return nil
+}
+
+type fakeReservationRepo struct {
+	repository.StockReservationRepository
+}
+
+func (fakeReservationRepo) Release(ctx context.Context, orderID uuid.UUID) (int, error) {
+	return 0, nil
+}
+
+type fakeRefundRepo struct {
+	repository.RefundRepository
+	refunded map[uuid.UUID]int
+}
+
+func (r fakeRefundRepo) RefundedQuantities(ctx context.Context, orderID uuid.UUID) (map[uuid.UUID]int, error) {
+	return r.refunded, nil
+}
+
+type fakeProductRepo struct {
+	repository.ProductRepository
+	stock map[uuid.UUID]int
+}
+
+func (r *fakeProductRepo) UpdateStock(ctx context.Context, id uuid.UUID, delta int) (*entities.StockLevel, error) {
+	previous := r.stock[id]
+	r.stock[id] += delta
+	return &entities.StockLevel{ProductID: id, Previous: previous, Current: r.stock[id]}, nil
+}
+
+func TestUpdateStatusRefundedAfterPartialRefund(t *testing.T) {
+	shirt, mug := uuid.New(), uuid.New()
+	order := &entities.Order{
+		ID:     uuid.New(),
+		Status: entities.OrderStatusPaid,
+		Items: []entities.OrderItem{
+			{ID: uuid.New(), ProductID: shirt, Quantity: 3},
+			{ID: uuid.New(), ProductID: mug, Quantity: 2},
+		},
+	}
+	// a succeeded refund already put 1 shirt and both mugs back into stock
+	refunded := map[uuid.UUID]int{order.Items[0].ID: 1, order.Items[1].ID: 2}
+	products := &fakeProductRepo{stock: map[uuid.UUID]int{shirt: 10, mug: 10}}
+
+	s := &orderService{
+		orderRepo:       &fakeOrderRepo{order: order},
+		productRepo:     products,
+		reservationRepo: fakeReservationRepo{},
+		refundRepo:      fakeRefundRepo{refunded: refunded},
+		txManager:       fakeTxManager{},
+	}
+	if _, err := s.UpdateStatus(context.Background(), order.ID, uuid.New(), entities.RoleAdmin, &dto.UpdateOrderRequest{Status: string(entities.OrderStatusRefunded)}); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	if got := products.stock[shirt]; got != 12 {
+		t.Errorf("shirt stock = %d, want 12: only the 2 shirts not refunded yet go back", got)
+	}
+	if got := products.stock[mug]; got != 10 {
+		t.Errorf("mug stock = %d, want 10: the refund put the mugs back already", got)
+	}
+}
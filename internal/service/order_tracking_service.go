@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/internal/infrastruktur/mail"
+	"postgresDB/pkg/logger"
+	"postgresDB/pkg/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// JobTypeGuestOrderLink emails the tracking link of a guest order to the guest
+	JobTypeGuestOrderLink = "guest_order_link"
+
+	guestOrderTemplateKey = "guest-order"
+)
+
+// guestOrderLink is the payload of a JobTypeGuestOrderLink job
+type guestOrderLink struct {
+	OrderID uuid.UUID `json:"order_id"`
+}
+
+type orderTrackingService struct {
+	orderRepo    repository.OrderRepository
+	trackingRepo repository.TrackingTokenRepository
+	userRepo     repository.UserRepository
+	templates    service.EmailTemplateService
+	mailer       mail.Mailer
+	secret       []byte
+	baseURL      string
+}
+
+// NewOrderTrackingService creates a new OrderTrackingService instance. Links are signed
+// with secret and built as baseURL + "/" + token. mailer may be nil, guests then get no
+// tracking link
+func NewOrderTrackingService(orderRepo repository.OrderRepository, trackingRepo repository.TrackingTokenRepository, userRepo repository.UserRepository, templates service.EmailTemplateService, mailer mail.Mailer, secret []byte, baseURL string) service.OrderTrackingService {
+	return &orderTrackingService{
+		orderRepo:    orderRepo,
+		trackingRepo: trackingRepo,
+		userRepo:     userRepo,
+		templates:    templates,
+		mailer:       mailer,
+		secret:       secret,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// CreateLink creates a new public tracking link for an order owned by the requester
+func (s *orderTrackingService) CreateLink(ctx context.Context, orderID, requesterID uuid.UUID, requesterRole entities.Role) (*dto.TrackingLinkResponse, error) {
+	if err := s.checkOwner(ctx, orderID, requesterID, requesterRole); err != nil {
+		return nil, err
+	}
+
+	return s.newLink(ctx, orderID)
+}
+
+// newLink creates a tracking link for an order
+func (s *orderTrackingService) newLink(ctx context.Context, orderID uuid.UUID) (*dto.TrackingLinkResponse, error) {
+	token := &entities.TrackingToken{
+		ID:        uuid.New(),
+		OrderID:   orderID,
+		CreatedAt: time.Now(),
+	}
+	if err := s.trackingRepo.Create(ctx, token); err != nil {
+		return nil, err
+	}
+
+	signed := utils.SignToken(s.secret, token.ID[:])
+	return &dto.TrackingLinkResponse{URL: s.baseURL + "/" + signed, Token: signed}, nil
+}
+
+// RevokeLinks revokes every tracking link of an order owned by the requester
+func (s *orderTrackingService) RevokeLinks(ctx context.Context, orderID, requesterID uuid.UUID, requesterRole entities.Role) error {
+	if err := s.checkOwner(ctx, orderID, requesterID, requesterRole); err != nil {
+		return err
+	}
+
+	_, err := s.trackingRepo.RevokeByOrderID(ctx, orderID)
+	return err
+}
+
+// Track returns the public tracking view for a signed, unrevoked token
+func (s *orderTrackingService) Track(ctx context.Context, token string) (*dto.OrderTrackingResponse, error) {
+	trackingToken, err := s.verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.orderRepo.GetByIDWithItems(ctx, trackingToken.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.orderRepo.GetStatusHistory(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToOrderTrackingResponse(order, history)
+	return &response, nil
+}
+
+// TrackOrder returns the tracking view of an order for its customer or an admin
+func (s *orderTrackingService) TrackOrder(ctx context.Context, orderID, requesterID uuid.UUID, requesterRole entities.Role) (*dto.OrderTrackingResponse, error) {
+	order, err := s.orderRepo.GetByIDWithItems(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if requesterRole != entities.RoleAdmin && order.CustomerID != requesterID {
+		return nil, apperror.ErrForbidden
+	}
+	history, err := s.orderRepo.GetStatusHistory(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToOrderTrackingResponse(order, history)
+	return &response, nil
+}
+
+// SendGuestLink is the JobHandler of JobTypeGuestOrderLink, emailing the guest who
+// placed an order a tracking link with the guest-order template
+func (s *orderTrackingService) SendGuestLink(ctx context.Context, payload json.RawMessage) error {
+	var job guestOrderLink
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return fmt.Errorf("decoding guest order link job: %w", err)
+	}
+	if s.mailer == nil {
+		logger.WarnContext(ctx, "guest order link not sent, no email configured", "order_id", job.OrderID)
+		return nil
+	}
+
+	order, err := s.orderRepo.GetByIDWithItems(ctx, job.OrderID)
+	if errors.Is(err, apperror.ErrOrderNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	guest, err := s.userRepo.GetByID(ctx, order.CustomerID)
+	if errors.Is(err, apperror.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// claimed before the job ran, the customer sees the order in their account
+	if !guest.IsGuest {
+		return nil
+	}
+
+	link, err := s.newLink(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	rendered, err := s.templates.Render(ctx, guestOrderTemplateKey, "", map[string]any{
+		"Email":       guest.Email,
+		"OrderID":     order.ID.String(),
+		"ItemCount":   len(order.Items),
+		"TotalAmount": entities.FormatAmount(order.TotalAmount),
+		"TrackingURL": link.URL,
+		"Token":       link.Token,
+	})
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, mail.Message{
+		To:      guest.Email,
+		Subject: rendered.Subject,
+		HTML:    rendered.HTML,
+		Text:    rendered.Text,
+	})
+}
+
+// ClaimGuestOrders moves every order of the guest behind a tracking link to the user. The
+// link was emailed to the guest, so holding it and an account with the same email shows
+// the orders are the user's
+func (s *orderTrackingService) ClaimGuestOrders(ctx context.Context, userID uuid.UUID, token string) (*dto.ClaimGuestOrdersResponse, error) {
+	trackingToken, err := s.verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.orderRepo.GetByID(ctx, trackingToken.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.CustomerID == userID {
+		return &dto.ClaimGuestOrdersResponse{Claimed: 0}, nil
+	}
+
+	guest, err := s.userRepo.GetByID(ctx, order.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	if !guest.IsGuest {
+		return nil, apperror.ErrGuestOrderNotClaimable
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(user.Email, guest.Email) {
+		return nil, apperror.ErrGuestEmailMismatch
+	}
+
+	claimed, err := s.userRepo.ClaimGuest(ctx, guest.ID, userID)
+	if errors.Is(err, apperror.ErrUserNotFound) {
+		return nil, apperror.ErrGuestOrderNotClaimable
+	}
+	if err != nil {
+		return nil, err
+	}
+	logger.InfoContext(ctx, "guest orders claimed", "user_id", userID, "guest_id", guest.ID, "orders", claimed)
+	return &dto.ClaimGuestOrdersResponse{Claimed: claimed}, nil
+}
+
+// verify returns the tracking token behind a signed link, unless it was revoked
+func (s *orderTrackingService) verify(ctx context.Context, token string) (*entities.TrackingToken, error) {
+	payload, err := utils.VerifySignedToken(s.secret, token)
+	if err != nil {
+		return nil, apperror.ErrTrackingLinkInvalid
+	}
+	tokenID, err := uuid.FromBytes(payload)
+	if err != nil {
+		return nil, apperror.ErrTrackingLinkInvalid
+	}
+
+	trackingToken, err := s.trackingRepo.GetByID(ctx, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	if trackingToken.RevokedAt != nil {
+		return nil, apperror.ErrTrackingLinkInvalid
+	}
+	return trackingToken, nil
+}
+
+// checkOwner allows admins and the customer who placed the order
+func (s *orderTrackingService) checkOwner(ctx context.Context, orderID, requesterID uuid.UUID, requesterRole entities.Role) error {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if requesterRole != entities.RoleAdmin && order.CustomerID != requesterID {
+		return apperror.ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/logger"
+	"postgresDB/pkg/metrics"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// outboxCleanupInterval is how often published events past the retention are removed
+const outboxCleanupInterval = time.Hour
+
+var (
+	outboxPublished = metrics.NewCounterVec("outbox_events_published_total",
+		"Number of outbox events published to the message broker", "type")
+	outboxPublishErrors = metrics.NewCounter("outbox_publish_errors_total",
+		"Number of failed attempts to publish an outbox event")
+	outboxPending = metrics.NewGauge("outbox_pending_events",
+		"Number of outbox events waiting to be published")
+	outboxLag = metrics.NewGauge("outbox_lag_seconds",
+		"Age of the oldest outbox event waiting to be published")
+)
+
+// OutboxConfig controls how the outbox is relayed to the message broker
+type OutboxConfig struct {
+	// PollInterval is how often new events are looked for
+	PollInterval time.Duration
+	// BatchSize is the most events published in one transaction
+	BatchSize      int
+	PublishTimeout time.Duration
+	// BaseBackoff is the wait after the first failed attempt to publish an event, doubled
+	// after every attempt up to MaxBackoff
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Retention is how long published events are kept, 0 keeps them forever
+	Retention time.Duration
+}
+
+type outboxRelay struct {
+	outboxRepo repository.OutboxRepository
+	txManager  repository.TxManager
+	publisher  service.EventPublisher
+	cfg        OutboxConfig
+
+	mu              sync.Mutex
+	lastPublishedAt time.Time
+	lastError       string
+	// retryAt holds publishing back after a failure, the failed event goes first again
+	retryAt     time.Time
+	lastCleanup time.Time
+}
+
+// NewOutboxRelay creates a new OutboxRelay publishing the outbox through publisher. Only
+// one instance publishes at a time, so the events keep their order
+func NewOutboxRelay(outboxRepo repository.OutboxRepository, txManager repository.TxManager, publisher service.EventPublisher, cfg OutboxConfig) service.OutboxRelay {
+	return &outboxRelay{
+		outboxRepo: outboxRepo,
+		txManager:  txManager,
+		publisher:  publisher,
+		cfg:        cfg,
+	}
+}
+
+// Status returns the backlog of the outbox; the last publish and error are this instance's
+func (s *outboxRelay) Status(ctx context.Context) (*dto.OutboxStatusResponse, error) {
+	pending, oldest, err := s.outboxRepo.Backlog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := &dto.OutboxStatusResponse{
+		Broker:    s.publisher.Name(),
+		Pending:   pending,
+		LastError: s.lastError,
+	}
+	if oldest != nil {
+		status.OldestPendingAt = oldest.Format(time.RFC3339)
+	}
+	if !s.lastPublishedAt.IsZero() {
+		status.LastPublishedAt = s.lastPublishedAt.Format(time.RFC3339)
+	}
+	return status, nil
+}
+
+// Run publishes new events on every tick until ctx is done
+func (s *outboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.relay(ctx)
+		s.maintain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay publishes batches until the outbox is caught up or publishing fails
+func (s *outboxRelay) relay(ctx context.Context) {
+	for ctx.Err() == nil {
+		s.mu.Lock()
+		waiting := time.Now().Before(s.retryAt)
+		s.mu.Unlock()
+		if waiting {
+			return
+		}
+
+		full, err := s.publishBatch(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.ErrorContext(ctx, "relaying outbox failed", "error", err)
+			}
+			return
+		}
+		if !full {
+			return
+		}
+	}
+}
+
+// publishBatch publishes the next batch in order, stopping at the first event the broker
+// refuses. It reports whether the batch was full, so more events may be waiting
+func (s *outboxRelay) publishBatch(ctx context.Context) (bool, error) {
+	full := false
+	// published events can't be taken back, so a failed batch isn't run again here; its
+	// events stay pending and the next batch publishes them once more
+	err := s.txManager.WithinTxNoRetry(ctx, func(ctx context.Context) error {
+		events, err := s.outboxRepo.ClaimPending(ctx, s.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		published := make([]uuid.UUID, 0, len(events))
+		for _, event := range events {
+			publishCtx, cancel := context.WithTimeout(ctx, s.cfg.PublishTimeout)
+			err := s.publisher.Publish(publishCtx, event)
+			cancel()
+			if err != nil {
+				outboxPublishErrors.Inc()
+				wait := retryBackoff(s.cfg.BaseBackoff, s.cfg.MaxBackoff, event.Attempts+1)
+				logger.WarnContext(ctx, "publishing outbox event failed", "event_id", event.ID, "type", event.EventType,
+					"broker", s.publisher.Name(), "attempt", event.Attempts+1, "retry_in", wait, "error", err)
+				s.mu.Lock()
+				s.lastError = err.Error()
+				s.retryAt = time.Now().Add(wait)
+				s.mu.Unlock()
+				if err := s.outboxRepo.MarkFailed(ctx, event.ID, err.Error()); err != nil {
+					return err
+				}
+				break
+			}
+			outboxPublished.With(event.EventType).Inc()
+			published = append(published, event.ID)
+		}
+
+		if len(published) == 0 {
+			return nil
+		}
+		now := time.Now()
+		if err := s.outboxRepo.MarkPublished(ctx, published, now); err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.lastPublishedAt = now
+		if len(published) == len(events) {
+			s.lastError = ""
+		}
+		s.mu.Unlock()
+		full = len(published) == s.cfg.BatchSize
+		return nil
+	})
+	return full, err
+}
+
+// maintain updates the backlog metrics and removes published events past the retention
+func (s *outboxRelay) maintain(ctx context.Context) {
+	pending, oldest, err := s.outboxRepo.Backlog(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.ErrorContext(ctx, "reading outbox backlog failed", "error", err)
+		}
+		return
+	}
+	outboxPending.Set(float64(pending))
+	lag := 0.0
+	if oldest != nil {
+		lag = time.Since(*oldest).Seconds()
+	}
+	outboxLag.Set(lag)
+
+	if s.cfg.Retention <= 0 || time.Since(s.lastCleanup) < outboxCleanupInterval {
+		return
+	}
+	s.lastCleanup = time.Now()
+	deleted, err := s.outboxRepo.DeletePublishedBefore(ctx, time.Now().Add(-s.cfg.Retention))
+	if err != nil {
+		logger.ErrorContext(ctx, "removing published outbox events failed", "error", err)
+		return
+	}
+	if deleted > 0 {
+		logger.InfoContext(ctx, "published outbox events removed", "count", deleted)
+	}
+}
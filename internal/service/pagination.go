@@ -0,0 +1,20 @@
+package service
+
+import (
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/pkg/pagination"
+)
+
+// decodeCursor decodes an optional cursor query value, empty meaning the first page
+func decodeCursor(cursor *string) (*pagination.Cursor, error) {
+	if cursor == nil || *cursor == "" {
+		return nil, nil
+	}
+	c, err := pagination.Decode(*cursor)
+	if err != nil {
+		return nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "cursor", Message: "cursor tidak valid"},
+		})
+	}
+	return &c, nil
+}
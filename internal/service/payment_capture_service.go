@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/logger"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CaptureRetryConfig controls how often and how long payment captures are retried
+type CaptureRetryConfig struct {
+	// Interval is how often due captures are looked up
+	Interval time.Duration
+	// BaseBackoff is the wait after the first failed attempt, doubled after every attempt
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// MaxAttempts is the number of attempts before the capture fails for good
+	MaxAttempts int
+	// BatchSize caps the attempts per tick so a backlog doesn't flood the provider
+	BatchSize int
+	// AttemptTimeout bounds a single call to the provider
+	AttemptTimeout time.Duration
+}
+
+type paymentCaptureService struct {
+	captureRepo repository.PaymentCaptureRepository
+	orderRepo   repository.OrderRepository
+	gateway     service.PaymentGateway
+	hooks       *OrderHooks
+	cfg         CaptureRetryConfig
+}
+
+// NewPaymentCaptureService creates a new PaymentCaptureService instance. gateway may be
+// nil while no payment provider is configured, captures are then refused
+func NewPaymentCaptureService(captureRepo repository.PaymentCaptureRepository, orderRepo repository.OrderRepository, gateway service.PaymentGateway, hooks *OrderHooks, cfg CaptureRetryConfig) service.PaymentCaptureService {
+	return &paymentCaptureService{
+		captureRepo: captureRepo,
+		orderRepo:   orderRepo,
+		gateway:     gateway,
+		hooks:       hooks,
+		cfg:         cfg,
+	}
+}
+
+// Start moves a pending order to pending_capture and schedules the first attempt right away
+func (s *paymentCaptureService) Start(ctx context.Context, orderID uuid.UUID) (*dto.PaymentCaptureResponse, error) {
+	if s.gateway == nil {
+		return nil, apperror.ErrPaymentUnavailable
+	}
+
+	if _, err := s.orderRepo.GetByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	capture := &entities.PaymentCapture{
+		OrderID:       orderID,
+		Status:        entities.CaptureStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := s.captureRepo.Start(ctx, capture, "Menunggu konfirmasi pembayaran"); err != nil {
+		return nil, err
+	}
+	s.hooks.AfterStatusChangeOf(ctx, s.orderRepo, orderID, entities.OrderStatusPending)
+
+	response := dto.ToPaymentCaptureResponse(capture)
+	return &response, nil
+}
+
+// Get returns the capture state of an order
+func (s *paymentCaptureService) Get(ctx context.Context, orderID uuid.UUID) (*dto.PaymentCaptureResponse, error) {
+	capture, err := s.captureRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToPaymentCaptureResponse(capture)
+	return &response, nil
+}
+
+// RunRetries attempts due captures on every tick until ctx is done
+func (s *paymentCaptureService) RunRetries(ctx context.Context) {
+	if s.gateway == nil {
+		return
+	}
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		captures, err := s.captureRepo.ListDue(ctx, time.Now(), s.cfg.BatchSize)
+		if err != nil {
+			logger.ErrorContext(ctx, "listing due payment captures failed", "error", err)
+			continue
+		}
+		for _, capture := range captures {
+			if ctx.Err() != nil {
+				return
+			}
+			if err := s.attempt(ctx, capture); err != nil {
+				logger.ErrorContext(ctx, "payment capture attempt failed", "order_id", capture.OrderID, "error", err)
+			}
+		}
+	}
+}
+
+// attempt runs one capture attempt and moves the capture to its next state
+func (s *paymentCaptureService) attempt(ctx context.Context, capture *entities.PaymentCapture) error {
+	order, err := s.orderRepo.GetByIDWithItems(ctx, capture.OrderID)
+	if err != nil {
+		return err
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, s.cfg.AttemptTimeout)
+	result, captureErr := s.gateway.Capture(attemptCtx, order)
+	cancel()
+
+	capture.Attempts++
+	capture.LastError = ""
+	if captureErr != nil {
+		capture.LastError = captureErr.Error()
+		result = entities.CaptureResultPending
+	}
+
+	switch {
+	case result == entities.CaptureResultCaptured:
+		recordCheckout(checkoutStagePayment, nil)
+	case captureErr != nil || result == entities.CaptureResultDeclined:
+		checkoutAttempts.With(checkoutStagePayment, checkoutFailed).Inc()
+	}
+
+	switch result {
+	case entities.CaptureResultCaptured:
+		capture.Status = entities.CaptureStatusCaptured
+		return s.finish(ctx, capture, "captured", entities.OrderStatusPaid, "Pembayaran berhasil dikonfirmasi")
+	case entities.CaptureResultDeclined:
+		capture.Status = entities.CaptureStatusFailed
+		if capture.LastError == "" {
+			capture.LastError = "payment declined by provider"
+		}
+		return s.finish(ctx, capture, "declined", entities.OrderStatusCancelled, "Pembayaran ditolak, order dibatalkan")
+	}
+
+	if capture.Attempts >= s.cfg.MaxAttempts {
+		capture.Status = entities.CaptureStatusFailed
+		if capture.LastError == "" {
+			capture.LastError = "payment still pending after the last attempt"
+		}
+		return s.finish(ctx, capture, "expired", entities.OrderStatusCancelled,
+			fmt.Sprintf("Pembayaran tidak terkonfirmasi setelah %d percobaan, order dibatalkan", capture.Attempts))
+	}
+
+	capture.NextAttemptAt = time.Now().Add(s.backoff(capture.Attempts))
+	if err := s.captureRepo.Reschedule(ctx, capture,
+		fmt.Sprintf("Pembayaran belum terkonfirmasi (percobaan %d), dicoba lagi %s", capture.Attempts, capture.NextAttemptAt.Format(time.RFC3339))); err != nil {
+		return err
+	}
+	paymentCaptures.With("pending").Inc()
+	return nil
+}
+
+// finish records the final capture state and the order status it leads to
+func (s *paymentCaptureService) finish(ctx context.Context, capture *entities.PaymentCapture, outcome string, status entities.OrderStatus, note string) error {
+	if err := s.captureRepo.Finish(ctx, capture, status, note); err != nil {
+		return err
+	}
+	paymentCaptures.With(outcome).Inc()
+	orderStatusChanges.With(string(status)).Inc()
+	s.hooks.AfterStatusChangeOf(ctx, s.orderRepo, capture.OrderID, entities.OrderStatusPendingCapture)
+	return nil
+}
+
+// backoff returns the wait after the given number of attempts
+func (s *paymentCaptureService) backoff(attempts int) time.Duration {
+	return retryBackoff(s.cfg.BaseBackoff, s.cfg.MaxBackoff, attempts)
+}
+
+// retryBackoff returns the wait after the given number of attempts: exponential from base,
+// capped at maxWait, with up to 20% jitter so retries started together spread out
+func retryBackoff(base, maxWait time.Duration, attempts int) time.Duration {
+	wait := base
+	for i := 1; i < attempts && wait < maxWait; i++ {
+		wait *= 2
+	}
+	wait = min(wait, maxWait)
+	return wait + time.Duration(rand.Int64N(int64(wait)/5+1))
+}
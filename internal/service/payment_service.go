@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/logger"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type paymentService struct {
+	paymentRepo repository.PaymentRepository
+	orderRepo   repository.OrderRepository
+	provider    service.PaymentProvider
+	hooks       *OrderHooks
+	currency    string
+}
+
+// NewPaymentService creates a new PaymentService instance. provider may be nil while no
+// payment provider is configured, payments are then refused
+func NewPaymentService(paymentRepo repository.PaymentRepository, orderRepo repository.OrderRepository, provider service.PaymentProvider, hooks *OrderHooks, currency string) service.PaymentService {
+	return &paymentService{
+		paymentRepo: paymentRepo,
+		orderRepo:   orderRepo,
+		provider:    provider,
+		hooks:       hooks,
+		currency:    currency,
+	}
+}
+
+// CreateIntent creates a payment at the provider for the total of a pending order
+func (s *paymentService) CreateIntent(ctx context.Context, orderID uuid.UUID, requesterID uuid.UUID, requesterRole entities.Role) (*dto.PaymentResponse, error) {
+	if s.provider == nil {
+		return nil, apperror.ErrPaymentUnavailable
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	// customers can only pay their own orders
+	if requesterRole == entities.RoleUser && order.CustomerID != requesterID {
+		return nil, apperror.ErrForbidden
+	}
+	if order.Status != entities.OrderStatusPending {
+		return nil, apperror.ErrOrderNotPayable
+	}
+
+	// a retried checkout gets the payment it already started
+	latest, err := s.paymentRepo.GetLatestByOrderID(ctx, orderID)
+	if err != nil && !errors.Is(err, apperror.ErrPaymentNotFound) {
+		return nil, err
+	}
+	if latest != nil && latest.Status == entities.PaymentStatusPending && latest.Amount == order.TotalAmount {
+		response := dto.ToPaymentResponse(latest)
+		return &response, nil
+	}
+
+	now := time.Now()
+	payment := &entities.Payment{
+		ID:        uuid.New(),
+		OrderID:   orderID,
+		Provider:  s.provider.Name(),
+		Status:    entities.PaymentStatusPending,
+		Amount:    order.TotalAmount,
+		Currency:  s.currency,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.hooks.BeforePayment(ctx, order, payment); err != nil {
+		return nil, err
+	}
+	intent, err := s.provider.CreateIntent(ctx, payment)
+	if err != nil {
+		logger.ErrorContext(ctx, "creating payment intent failed", "order_id", orderID, "provider", payment.Provider, "error", err)
+		checkoutAttempts.With(checkoutStagePayment, checkoutFailed).Inc()
+		return nil, apperror.ErrPaymentProviderFailed
+	}
+	payment.ProviderRef = intent.ProviderRef
+	payment.ClientSecret = intent.ClientSecret
+
+	if err := s.paymentRepo.Create(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	response := dto.ToPaymentResponse(payment)
+	return &response, nil
+}
+
+// GetByOrder returns the latest payment of an order
+func (s *paymentService) GetByOrder(ctx context.Context, orderID uuid.UUID) (*dto.PaymentResponse, error) {
+	payment, err := s.paymentRepo.GetLatestByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToPaymentResponse(payment)
+	return &response, nil
+}
+
+// HandleWebhook verifies a provider notification and applies it to its payment
+func (s *paymentService) HandleWebhook(ctx context.Context, payload []byte, header http.Header) error {
+	if s.provider == nil {
+		return apperror.ErrPaymentUnavailable
+	}
+
+	event, err := s.provider.ParseWebhook(payload, header)
+	if err != nil {
+		logger.WarnContext(ctx, "rejected payment webhook", "provider", s.provider.Name(), "error", err)
+		return apperror.ErrWebhookSignatureInvalid
+	}
+	if event == nil {
+		return nil
+	}
+
+	payment, orderPaid, err := s.paymentRepo.ApplyEvent(ctx, s.provider.Name(), event, "Pembayaran diterima dari "+s.provider.Name())
+	if err != nil {
+		// payments created outside this shop are acknowledged so the provider stops retrying
+		if errors.Is(err, apperror.ErrPaymentNotFound) {
+			logger.WarnContext(ctx, "payment webhook for unknown payment", "provider", s.provider.Name(), "provider_ref", event.ProviderRef, "event_id", event.ID)
+			return nil
+		}
+		if errors.Is(err, apperror.ErrPaymentAmountMismatch) {
+			logger.ErrorContext(ctx, "payment webhook amount mismatch", "provider", s.provider.Name(), "provider_ref", event.ProviderRef, "amount", event.Amount)
+		}
+		return err
+	}
+	if payment == nil {
+		return nil
+	}
+
+	switch event.Status {
+	case entities.PaymentStatusSucceeded:
+		recordCheckout(checkoutStagePayment, nil)
+	case entities.PaymentStatusFailed:
+		checkoutAttempts.With(checkoutStagePayment, checkoutFailed).Inc()
+	}
+	if orderPaid {
+		orderStatusChanges.With(string(entities.OrderStatusPaid)).Inc()
+		logger.InfoContext(ctx, "order paid", "order_id", payment.OrderID, "payment_id", payment.ID, "provider", payment.Provider)
+	}
+	s.hooks.AfterPayment(ctx, payment)
+	if orderPaid {
+		s.hooks.AfterStatusChangeOf(ctx, s.orderRepo, payment.OrderID, entities.OrderStatusPending)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"math"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// forecastHistoryWeeks is the sales history a forecast looks at, a year so the same
+	// weeks of last year can weight it
+	forecastHistoryWeeks  = 52
+	defaultForecastWeeks  = 4
+	maxForecastWeeks      = 26
+	defaultForecastWindow = 4
+	maxForecastWindow     = 26
+	// minSeasonalFactor and maxSeasonalFactor bound how much last year's sales can move a
+	// forecast away from the moving average, a single unusual week shouldn't dominate it
+	minSeasonalFactor = 0.5
+	maxSeasonalFactor = 2.0
+)
+
+// Forecast estimates the demand of a product over the coming weeks. The weekly demand is
+// the moving average of the latest complete weeks; when the product has been sold for a
+// year, each week is weighted by how that week sold last year compared to the year average
+func (s *productService) Forecast(ctx context.Context, req dto.DemandForecastRequest) (*dto.DemandForecastResponse, error) {
+	if req.Weeks < 1 {
+		req.Weeks = defaultForecastWeeks
+	}
+	if req.Window < 1 {
+		req.Window = defaultForecastWindow
+	}
+	var details []apperror.ValidationError
+	if req.Weeks > maxForecastWeeks {
+		details = append(details, apperror.ValidationError{Field: "weeks", Message: "weeks maksimal 26"})
+	}
+	if req.Window > maxForecastWindow {
+		details = append(details, apperror.ValidationError{Field: "window", Message: "window maksimal 26"})
+	}
+	if len(details) > 0 {
+		return nil, apperror.NewValidationError(details)
+	}
+
+	product, err := s.productRepo.GetByID(ctx, req.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	forecasts, err := s.forecastDemand(ctx, []*entities.Product{product}, req.Weeks, req.Window)
+	if err != nil {
+		return nil, err
+	}
+	forecast := forecasts[product.ID]
+
+	response := dto.ToDemandForecastResponse(product, forecast, suggestReorder(product, forecast))
+	return &response, nil
+}
+
+// forecastDemand forecasts the demand of products over weeks, with one sales query
+func (s *productService) forecastDemand(ctx context.Context, products []*entities.Product, weeks, window int) (map[uuid.UUID]*entities.DemandForecast, error) {
+	currentWeek := weekStart(time.Now())
+	historyStart := currentWeek.AddDate(0, 0, -7*forecastHistoryWeeks)
+
+	ids := make([]uuid.UUID, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+	sales, err := s.productRepo.WeeklySales(ctx, ids, historyStart, forecastHistoryWeeks)
+	if err != nil {
+		return nil, err
+	}
+
+	forecasts := make(map[uuid.UUID]*entities.DemandForecast, len(products))
+	for _, p := range products {
+		forecast := &entities.DemandForecast{
+			ProductID:    p.ID,
+			HistoryStart: historyStart,
+			History:      sales[p.ID],
+			Window:       window,
+			Weekly:       make([]float64, weeks),
+		}
+
+		var recent int64
+		for _, q := range forecast.History[len(forecast.History)-window:] {
+			recent += q
+		}
+		forecast.Baseline = float64(recent) / float64(window)
+
+		// The history starts a year before the current week, so week i of the forecast
+		// sold History[i] last year
+		var year int64
+		for _, q := range forecast.History {
+			year += q
+		}
+		yearAverage := float64(year) / float64(len(forecast.History))
+		forecast.Seasonal = p.CreatedAt.Before(historyStart) && yearAverage > 0
+
+		for i := range forecast.Weekly {
+			demand := forecast.Baseline
+			if forecast.Seasonal {
+				factor := float64(forecast.History[i]) / yearAverage
+				demand *= min(max(factor, minSeasonalFactor), maxSeasonalFactor)
+			}
+			forecast.Weekly[i] = demand
+			forecast.Total += demand
+		}
+		forecasts[p.ID] = forecast
+	}
+	return forecasts, nil
+}
+
+// suggestReorder returns the quantity to order so the available stock covers the forecast
+// and still reaches the reorder threshold afterwards
+func suggestReorder(product *entities.Product, forecast *entities.DemandForecast) int {
+	target := int(math.Ceil(forecast.Total))
+	if product.ReorderThreshold != nil {
+		target += *product.ReorderThreshold
+	}
+	return max(target-max(product.Stock-product.ReservedStock, 0), 0)
+}
+
+// weekStart returns the start of the week of t, Monday 00:00 UTC
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/internal/infrastruktur/storage"
+	localrepo "postgresDB/internal/repository"
+	"postgresDB/pkg/logger"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// allowedImageTypes maps accepted image content types to file extensions
+var allowedImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
+type productImageService struct {
+	productRepo   repository.ProductRepository
+	imageRepo     repository.ProductImageRepository
+	storage       storage.Storage
+	responseCache localrepo.ResponseCacheRepository
+	maxUploadSize int64
+}
+
+// NewProductImageService creates a new ProductImageService instance
+func NewProductImageService(productRepo repository.ProductRepository, imageRepo repository.ProductImageRepository, storage storage.Storage, responseCache localrepo.ResponseCacheRepository, maxUploadSize int64) service.ProductImageService {
+	return &productImageService{
+		productRepo:   productRepo,
+		imageRepo:     imageRepo,
+		storage:       storage,
+		responseCache: responseCache,
+		maxUploadSize: maxUploadSize,
+	}
+}
+
+// Upload stores an image and appends it to the product gallery
+func (s *productImageService) Upload(ctx context.Context, productID uuid.UUID, req dto.UploadProductImageRequest) (*dto.ProductImageResponse, error) {
+	if req.Size > s.maxUploadSize {
+		return nil, apperror.ErrFileTooLarge
+	}
+
+	// Ensure product exists before storing anything
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	// Detect content type from the file content, not the client supplied header
+	reader := bufio.NewReaderSize(req.File, 512)
+	head, err := reader.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, apperror.ErrInvalidImage.WithError(err)
+	}
+	contentType := http.DetectContentType(head)
+	ext, ok := allowedImageTypes[contentType]
+	if !ok {
+		return nil, apperror.ErrInvalidImage
+	}
+
+	image := &entities.ProductImage{
+		ID:          uuid.New(),
+		ProductID:   productID,
+		ContentType: contentType,
+		Size:        req.Size,
+		IsPrimary:   req.IsPrimary,
+		CreatedAt:   time.Now(),
+	}
+	image.StorageKey = "products/" + productID.String() + "/" + image.ID.String() + ext
+
+	url, err := s.storage.Save(ctx, image.StorageKey, io.LimitReader(reader, s.maxUploadSize), contentType)
+	if err != nil {
+		return nil, apperror.WrapInternal(err)
+	}
+	image.URL = url
+
+	if err := s.imageRepo.Create(ctx, image); err != nil {
+		// Don't leave orphaned files behind
+		if delErr := s.storage.Delete(ctx, image.StorageKey); delErr != nil {
+			logger.ErrorContext(ctx, "Failed to delete orphaned image", "key", image.StorageKey, "error", delErr.Error())
+		}
+		return nil, err
+	}
+	invalidateCatalog(ctx, s.responseCache)
+
+	response := dto.ToProductImageResponse(image)
+	return &response, nil
+}
+
+// List retrieves the gallery of a product
+func (s *productImageService) List(ctx context.Context, productID uuid.UUID) ([]dto.ProductImageResponse, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	images, err := s.imageRepo.ListByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToProductImageResponseList(images), nil
+}
+
+// SetPrimary marks an image as the primary image of the product
+func (s *productImageService) SetPrimary(ctx context.Context, productID, imageID uuid.UUID) ([]dto.ProductImageResponse, error) {
+	if err := s.imageRepo.SetPrimary(ctx, productID, imageID); err != nil {
+		return nil, err
+	}
+	invalidateCatalog(ctx, s.responseCache)
+	return s.List(ctx, productID)
+}
+
+// Reorder changes the order of the product gallery
+func (s *productImageService) Reorder(ctx context.Context, productID uuid.UUID, req dto.ReorderProductImagesRequest) ([]dto.ProductImageResponse, error) {
+	if err := s.imageRepo.Reorder(ctx, productID, req.ImageIDs); err != nil {
+		return nil, err
+	}
+	invalidateCatalog(ctx, s.responseCache)
+	return s.List(ctx, productID)
+}
+
+// Delete removes an image from the product gallery and the storage backend
+func (s *productImageService) Delete(ctx context.Context, productID, imageID uuid.UUID) error {
+	image, err := s.imageRepo.GetByID(ctx, imageID)
+	if err != nil {
+		return err
+	}
+	if image.ProductID != productID {
+		return apperror.ErrProductImageNotFound
+	}
+
+	if err := s.imageRepo.Delete(ctx, imageID); err != nil {
+		return err
+	}
+	invalidateCatalog(ctx, s.responseCache)
+
+	if err := s.storage.Delete(ctx, image.StorageKey); err != nil {
+		logger.ErrorContext(ctx, "Failed to delete image file", "key", image.StorageKey, "error", err.Error())
+	}
+	return nil
+}
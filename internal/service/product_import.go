@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/pkg/validator"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxImportRows caps the number of rows of a single import file
+	maxImportRows = 5000
+	// importBatchSize is the number of rows sent to the database per round trip
+	importBatchSize = 500
+)
+
+// Import row statuses
+const (
+	importStatusCreated = "created"
+	importStatusUpdated = "updated"
+	importStatusFailed  = "failed"
+)
+
+// importRow is a parsed import row with the errors found while parsing it
+type importRow struct {
+	row    dto.ProductImportRow
+	errors []apperror.ValidationError
+}
+
+// Import validates every row of a CSV or JSON file and upserts the valid ones by SKU in
+// a single transaction. Invalid rows are reported and skipped, they don't fail the import
+func (s *productService) Import(ctx context.Context, req dto.ImportProductsRequest) (*dto.ProductImportReport, error) {
+	var rows []importRow
+	var err error
+	switch req.Format {
+	case "csv":
+		rows, err = parseImportCSV(req.File)
+	case "json":
+		rows, err = parseImportJSON(req.File)
+	default:
+		return nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "format", Message: "format harus csv atau json"},
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "file", Message: "file tidak berisi produk"},
+		})
+	}
+
+	report := &dto.ProductImportReport{
+		Total: len(rows),
+		Rows:  make([]dto.ProductImportRowResult, len(rows)),
+	}
+
+	products := make([]*entities.Product, 0, len(rows))
+	positions := make([]int, 0, len(rows))
+	seen := make(map[string]int, len(rows))
+	categories := make(map[string]*entities.Product)
+
+	for i := range rows {
+		r := &rows[i]
+		r.row.SKU = strings.TrimSpace(r.row.SKU)
+		result := &report.Rows[i]
+		result.Row = i + 1
+		result.SKU = r.row.SKU
+
+		if len(r.errors) == 0 {
+			if err := validator.ValidateStruct(&r.row); err != nil {
+				appErr, ok := apperror.AsAppError(err)
+				if !ok || len(appErr.Details) == 0 {
+					return nil, err
+				}
+				r.errors = appErr.Details
+			}
+		}
+		if len(r.errors) == 0 && r.row.SKU != "" {
+			if first, ok := seen[r.row.SKU]; ok {
+				r.errors = append(r.errors, apperror.ValidationError{
+					Field:   "sku",
+					Message: "SKU sudah dipakai pada baris %d",
+					Args:    []any{first},
+				})
+			} else {
+				seen[r.row.SKU] = result.Row
+			}
+		}
+
+		var product *entities.Product
+		if len(r.errors) == 0 {
+			product = &entities.Product{
+				ID:          uuid.New(),
+				SKU:         r.row.SKU,
+				Name:        r.row.Name,
+				Description: r.row.Description,
+				Price:       r.row.Price,
+				Stock:       r.row.Stock,
+			}
+			if err := s.resolveImportCategory(ctx, product, r.row, categories); err != nil {
+				if !errors.Is(err, apperror.ErrCategoryNotFound) {
+					return nil, err
+				}
+				r.errors = append(r.errors, apperror.ValidationError{Field: "category_id", Message: "kategori tidak ditemukan"})
+			}
+		}
+
+		if len(r.errors) > 0 {
+			result.Status = importStatusFailed
+			result.Errors = r.errors
+			report.Failed++
+			continue
+		}
+		products = append(products, product)
+		positions = append(positions, i)
+	}
+
+	if len(products) == 0 {
+		return report, nil
+	}
+
+	created, err := s.productRepo.UpsertBySKU(ctx, products, importBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	for j, p := range products {
+		result := &report.Rows[positions[j]]
+		result.ProductID = p.ID.String()
+		if created[j] {
+			result.Status = importStatusCreated
+			report.Created++
+		} else {
+			result.Status = importStatusUpdated
+			report.Updated++
+		}
+	}
+
+	return report, nil
+}
+
+// resolveImportCategory links an imported product to its category, caching lookups since
+// an import file usually repeats the same few categories
+func (s *productService) resolveImportCategory(ctx context.Context, product *entities.Product, row dto.ProductImportRow, cache map[string]*entities.Product) error {
+	key := "name:" + row.Category
+	if row.CategoryID != nil {
+		key = "id:" + row.CategoryID.String()
+	}
+
+	resolved, ok := cache[key]
+	if !ok {
+		resolved = &entities.Product{}
+		if err := s.resolveCategory(ctx, resolved, row.CategoryID, row.Category); err != nil {
+			return err
+		}
+		cache[key] = resolved
+	}
+
+	product.Category = resolved.Category
+	product.CategoryID = resolved.CategoryID
+	return nil
+}
+
+// parseImportJSON parses a JSON array of products; a row that doesn't decode is
+// reported on its own instead of rejecting the file
+func parseImportJSON(r io.Reader) ([]importRow, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "file", Message: "file JSON harus berupa array produk"},
+		})
+	}
+	if len(raw) > maxImportRows {
+		return nil, tooManyImportRows()
+	}
+
+	rows := make([]importRow, len(raw))
+	for i, item := range raw {
+		if err := json.Unmarshal(item, &rows[i].row); err != nil {
+			rows[i].errors = []apperror.ValidationError{{Field: "row", Message: "format produk tidak valid"}}
+		}
+	}
+	return rows, nil
+}
+
+// parseImportCSV parses a CSV file whose header names the columns, see dto.ProductImportRow
+func parseImportCSV(r io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "file", Message: "header CSV tidak ditemukan"},
+		})
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"sku", "name", "price", "stock"} {
+		if _, ok := columns[required]; !ok {
+			return nil, apperror.NewValidationError([]apperror.ValidationError{
+				{Field: "file", Message: "kolom %s wajib ada pada header CSV", Args: []any{required}},
+			})
+		}
+	}
+
+	rows := make([]importRow, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if len(rows) >= maxImportRows {
+			return nil, tooManyImportRows()
+		}
+
+		var row importRow
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, apperror.WrapInternal(err)
+			}
+			row.errors = []apperror.ValidationError{{Field: "row", Message: "baris CSV tidak valid"}}
+			rows = append(rows, row)
+			continue
+		}
+
+		get := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row.row = dto.ProductImportRow{
+			SKU:         get("sku"),
+			Name:        get("name"),
+			Description: get("description"),
+			Category:    get("category"),
+		}
+		if row.row.Price, err = entities.ParseAmount(get("price")); err != nil {
+			row.errors = append(row.errors, apperror.ValidationError{Field: "price", Message: "price harus berupa angka dengan maksimal 2 desimal"})
+		}
+		if row.row.Stock, err = strconv.Atoi(get("stock")); err != nil {
+			row.errors = append(row.errors, apperror.ValidationError{Field: "stock", Message: "stock harus berupa bilangan bulat"})
+		}
+		if v := get("category_id"); v != "" {
+			id, err := uuid.Parse(v)
+			if err != nil {
+				row.errors = append(row.errors, apperror.ValidationError{Field: "category_id", Message: "category_id tidak valid"})
+			} else {
+				row.row.CategoryID = &id
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// tooManyImportRows reports an import file above maxImportRows
+func tooManyImportRows() error {
+	return apperror.NewValidationError([]apperror.ValidationError{
+		{Field: "file", Message: "maksimal %d produk per import", Args: []any{maxImportRows}},
+	})
+}
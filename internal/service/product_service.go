@@ -2,24 +2,61 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
 	"postgresDB/internal/domain/dto"
 	"postgresDB/internal/domain/entities"
 	apperror "postgresDB/internal/domain/errors"
 	"postgresDB/internal/domain/repository"
 	"postgresDB/internal/domain/service"
+	"postgresDB/internal/infrastruktur/storage"
+	localrepo "postgresDB/internal/repository"
+	"postgresDB/pkg/logger"
+	"postgresDB/pkg/pagination"
+	"postgresDB/pkg/utils"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const (
+	defaultRelatedLimit = 10
+	maxRelatedLimit     = 50
+	defaultTopSellers   = 10
+	maxTopSellers       = 50
+	// defaultStatsRange is the top sellers period when none is given
+	defaultStatsRange = 30 * 24 * time.Hour
+	// archivePurgeBatch is the most archived products deleted per statement
+	archivePurgeBatch = 100
+)
+
 type productService struct {
-	productRepo repository.ProductRepository
+	productRepo   repository.ProductRepository
+	categoryRepo  repository.CategoryRepository
+	imageRepo     repository.ProductImageRepository
+	attributeRepo repository.AttributeRepository
+	tagRepo       repository.TagRepository
+	reservations  repository.StockReservationRepository
+	// responseCache holds the rendered catalog responses, invalidated after every write
+	responseCache localrepo.ResponseCacheRepository
+	storage       storage.Storage
 }
 
 // NewProductService creates a new ProductService instance
-func NewProductService(productRepo repository.ProductRepository) service.ProductService {
+func NewProductService(productRepo repository.ProductRepository, categoryRepo repository.CategoryRepository, imageRepo repository.ProductImageRepository, attributeRepo repository.AttributeRepository, tagRepo repository.TagRepository, reservations repository.StockReservationRepository, responseCache localrepo.ResponseCacheRepository, storage storage.Storage) service.ProductService {
 	return &productService{
-		productRepo: productRepo,
+		productRepo:   productRepo,
+		categoryRepo:  categoryRepo,
+		imageRepo:     imageRepo,
+		attributeRepo: attributeRepo,
+		tagRepo:       tagRepo,
+		reservations:  reservations,
+		responseCache: responseCache,
+		storage:       storage,
 	}
 }
 
@@ -27,14 +64,33 @@ func NewProductService(productRepo repository.ProductRepository) service.Product
 func (s *productService) Create(ctx context.Context, req *dto.CreateProductRequest) (*dto.ProductResponse, error) {
 	// Create product entity
 	product := &entities.Product{
-		ID:          uuid.New(),
-		Name:        req.Name,
-		Description: req.Description,
-		Price:       req.Price,
-		Stock:       req.Stock,
-		Category:    req.Category,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		ID:               uuid.New(),
+		SKU:              strings.TrimSpace(req.SKU),
+		Name:             req.Name,
+		Description:      req.Description,
+		Price:            req.Price,
+		Stock:            req.Stock,
+		Category:         req.Category,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+		ReorderThreshold: req.ReorderThreshold,
+		WeightGrams:      req.WeightGrams,
+	}
+
+	// Link product to its category
+	if err := s.resolveCategory(ctx, product, req.CategoryID, req.Category); err != nil {
+		return nil, err
+	}
+
+	// Validate attribute values against the category definitions
+	attributes, err := s.buildAttributes(ctx, product, req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := s.resolveTags(ctx, req.Tags)
+	if err != nil {
+		return nil, err
 	}
 
 	// Save product to repository
@@ -42,6 +98,21 @@ func (s *productService) Create(ctx context.Context, req *dto.CreateProductReque
 		return nil, err
 	}
 
+	if len(attributes) > 0 {
+		if err := s.attributeRepo.SetProductValues(ctx, product.ID, attributes); err != nil {
+			return nil, err
+		}
+	}
+	product.Attributes = attributes
+
+	if len(tags) > 0 {
+		if err := s.tagRepo.SetProductTags(ctx, product.ID, tagIDs(tags)); err != nil {
+			return nil, err
+		}
+	}
+	product.Tags = tags
+	invalidateCatalog(ctx, s.responseCache)
+
 	// Return response DTO
 	response := dto.ToProductResponse(product)
 	return &response, nil
@@ -54,10 +125,55 @@ func (s *productService) GetByID(ctx context.Context, id uuid.UUID) (*dto.Produc
 		return nil, err
 	}
 
+	if err := s.attachDetails(ctx, product); err != nil {
+		return nil, err
+	}
+
 	response := dto.ToProductResponse(product)
 	return &response, nil
 }
 
+// GetByIDs retrieves the products in the order of ids, duplicates are returned once
+func (s *productService) GetByIDs(ctx context.Context, ids []uuid.UUID) (*dto.BatchProductResponse, error) {
+	unique := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+
+	products, err := s.productRepo.GetByIDs(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachDetails(ctx, products...); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*entities.Product, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+
+	res := &dto.BatchProductResponse{
+		Products:   make([]dto.ProductResponse, 0, len(products)),
+		MissingIDs: []string{},
+	}
+	for _, id := range unique {
+		product, ok := byID[id]
+		if !ok {
+			res.MissingIDs = append(res.MissingIDs, id.String())
+			continue
+		}
+		res.Products = append(res.Products, dto.ToProductResponse(product))
+	}
+	return res, nil
+}
+
 // List retrieves a list of products with pagination and optional filtering
 func (s *productService) List(ctx context.Context, req dto.ProductListRequest) ([]dto.ProductResponse, *dto.PaginationMeta, error) {
 	// set default pagination values
@@ -73,26 +189,101 @@ func (s *productService) List(ctx context.Context, req dto.ProductListRequest) (
 
 	offset := (page - 1) * limit
 
-	products, total, err := s.productRepo.List(ctx, limit, offset, req.Search, req.Category)
+	filter, err := listFilter(req)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	var products []*entities.Product
+	var total int64
+	exact := true
+	if req.ExactCount {
+		products, total, err = s.productRepo.List(ctx, limit, offset, filter)
+	} else {
+		products, err = s.productRepo.ListPage(ctx, limit, offset, filter)
+		if err == nil {
+			total, exact, err = s.productRepo.EstimateCount(ctx, filter)
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if seen := int64(offset + len(products)); !exact {
+		// a short page is the last one and gives the exact total, a full page at least
+		// bounds it from below
+		if len(products) < limit && (len(products) > 0 || offset == 0) {
+			total, exact = seen, true
+		} else {
+			total = max(total, seen)
+		}
+	}
+
+	if err := s.attachDetails(ctx, products...); err != nil {
+		return nil, nil, err
+	}
+
 	responseList := dto.ToProductResponseList(products)
 	pagination := &dto.PaginationMeta{
-		Total:      total,
-		Limit:      limit,
-		Page:       page,
-		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+		Total:          total,
+		Limit:          limit,
+		Page:           page,
+		TotalPages:     int((total + int64(limit) - 1) / int64(limit)),
+		TotalEstimated: !exact,
 	}
 
 	return responseList, pagination, nil
 }
 
+// ListByCursor retrieves a page of products using keyset pagination, which stays fast and
+// stable on large catalogs where offsets skip or repeat rows as products are added
+func (s *productService) ListByCursor(ctx context.Context, req dto.ProductListRequest) ([]dto.ProductResponse, *dto.CursorMeta, error) {
+	if len(req.Sort) > 0 {
+		return nil, nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "sort", Message: "sort tidak bisa dipakai bersama cursor"},
+		})
+	}
+
+	limit := req.Limit
+	if limit <= 1 {
+		limit = 10
+	}
+
+	after, err := decodeCursor(req.Cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter, err := listFilter(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// one extra row tells whether another page exists
+	products, err := s.productRepo.ListAfter(ctx, limit+1, after, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta := &dto.CursorMeta{Limit: limit}
+	if len(products) > limit {
+		products = products[:limit]
+		last := products[limit-1]
+		meta.HasMore = true
+		meta.NextCursor = pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	if err := s.attachDetails(ctx, products...); err != nil {
+		return nil, nil, err
+	}
+
+	return dto.ToProductResponseList(products), meta, nil
+}
+
 // Update updates an existing product
 func (s *productService) Update(ctx context.Context, id uuid.UUID, req *dto.UpdateProductRequest, UserID entities.Role) (*dto.ProductResponse, error) {
-	// get existing product
-	product, err := s.productRepo.GetByID(ctx, id)
+	// get existing product; the cached copy may lag behind stock changes of orders, the
+	// update starts from the stored one
+	product, err := s.productRepo.GetCurrent(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -102,7 +293,15 @@ func (s *productService) Update(ctx context.Context, id uuid.UUID, req *dto.Upda
 		return nil, apperror.ErrUnauthorized
 	}
 
+	// the update only applies to the version the caller read, without one it applies to the latest
+	if req.Version != nil {
+		product.Version = *req.Version
+	}
+
 	// Update fields if provided
+	if req.SKU != nil {
+		product.SKU = strings.TrimSpace(*req.SKU)
+	}
 	if req.Name != nil {
 		product.Name = *req.Name
 	}
@@ -115,8 +314,42 @@ func (s *productService) Update(ctx context.Context, id uuid.UUID, req *dto.Upda
 	if req.Stock != nil {
 		product.Stock = *req.Stock
 	}
-	if req.Category != nil {
-		product.Category = *req.Category
+	if req.ReorderThreshold != nil {
+		product.ReorderThreshold = req.ReorderThreshold
+	}
+	if req.WeightGrams != nil {
+		product.WeightGrams = *req.WeightGrams
+	}
+	categoryChanged := false
+	if req.CategoryID != nil || req.Category != nil {
+		previous := product.CategoryID
+		var category string
+		if req.Category != nil {
+			category = *req.Category
+		}
+		if err := s.resolveCategory(ctx, product, req.CategoryID, category); err != nil {
+			return nil, err
+		}
+		categoryChanged = !sameCategory(previous, product.CategoryID)
+	}
+
+	// Values of the old category no longer apply, so a category change clears them
+	// unless new values are sent along
+	var attributes []entities.ProductAttribute
+	replaceAttributes := req.Attributes != nil || categoryChanged
+	if req.Attributes != nil {
+		attributes, err = s.buildAttributes(ctx, product, req.Attributes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var tags []entities.Tag
+	if req.Tags != nil {
+		tags, err = s.resolveTags(ctx, req.Tags)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Save updated product
@@ -124,25 +357,591 @@ func (s *productService) Update(ctx context.Context, id uuid.UUID, req *dto.Upda
 		return nil, err
 	}
 
+	if replaceAttributes {
+		if err := s.attributeRepo.SetProductValues(ctx, product.ID, attributes); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Tags != nil {
+		if err := s.tagRepo.SetProductTags(ctx, product.ID, tagIDs(tags)); err != nil {
+			return nil, err
+		}
+	}
+
+	invalidateCatalog(ctx, s.responseCache)
+
+	if err := s.attachDetails(ctx, product); err != nil {
+		return nil, err
+	}
+
 	// Return response DTO
 	response := dto.ToProductResponse(product)
 	return &response, nil
 }
 
-// Delete deletes a product by its ID
-func (s *productService) Delete(ctx context.Context, id uuid.UUID, userRole entities.Role) error {
+// Delete archives a product by its ID; it stays referenced by existing orders. The block
+// policy refuses when open orders or reservations depend on it, cleanup cancels its unpaid
+// orders first and archive leaves them to complete
+func (s *productService) Delete(ctx context.Context, id uuid.UUID, userRole entities.Role, policy entities.ProductDeletePolicy) (*dto.ProductDeleteReport, error) {
 	// Get existing product
 	_, err := s.productRepo.GetByID(ctx, id)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Check authorization: only admin can delete
 	if userRole != entities.RoleAdmin {
-		return apperror.ErrUnauthorized
+		return nil, apperror.ErrUnauthorized
+	}
+
+	refs, err := s.productRepo.References(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.ProductDeleteReport{
+		ProductID:       id.String(),
+		Policy:          string(policy),
+		References:      dto.ToProductReferencesResponse(refs),
+		CancelledOrders: []string{},
+	}
+
+	switch policy {
+	case entities.ProductDeleteBlock:
+		if refs.Blocking() {
+			return nil, referencedError(refs)
+		}
+	case entities.ProductDeleteCleanup:
+		cancelled, err := s.reservations.CancelPendingWithProduct(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if len(cancelled) > 0 {
+			orderStatusChanges.With(string(entities.OrderStatusCancelled)).Add(float64(len(cancelled)))
+			logger.InfoContext(ctx, "unpaid orders cancelled, product deleted", "product_id", id, "orders", len(cancelled))
+		}
+		for _, orderID := range cancelled {
+			report.CancelledOrders = append(report.CancelledOrders, orderID.String())
+		}
 	}
 
-	// Delete product
+	if err := s.productRepo.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+	invalidateCatalog(ctx, s.responseCache)
+	report.Archived = true
+	return report, nil
+}
 
-	return s.productRepo.Delete(ctx, id)
+// References lists what still refers to the product
+func (s *productService) References(ctx context.Context, id uuid.UUID) (*dto.ProductReferencesResponse, error) {
+	if _, err := s.productRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	refs, err := s.productRepo.References(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	response := dto.ToProductReferencesResponse(refs)
+	return &response, nil
+}
+
+// referencedError reports the open orders and reservations blocking a delete
+func referencedError(refs *entities.ProductReferences) error {
+	var details []apperror.ValidationError
+	for _, status := range entities.OpenOrderStatuses {
+		if n := refs.OpenOrders[status]; n > 0 {
+			details = append(details, apperror.ValidationError{Field: "open_orders." + string(status), Message: "%d order %s berisi produk ini", Args: []any{n, status}})
+		}
+	}
+	if refs.ActiveReservations > 0 {
+		details = append(details, apperror.ValidationError{Field: "active_reservations", Message: "%d reservasi aktif menahan %d stok", Args: []any{refs.ActiveReservations, refs.ReservedQuantity}})
+	}
+
+	err := *apperror.ErrProductReferenced
+	err.Details = details
+	return &err
+}
+
+// ListRelated returns up to limit products related to the product id
+func (s *productService) ListRelated(ctx context.Context, id uuid.UUID, limit int) ([]dto.ProductResponse, error) {
+	if _, err := s.productRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if limit < 1 {
+		limit = defaultRelatedLimit
+	}
+	limit = min(limit, maxRelatedLimit)
+
+	products, err := s.productRepo.ListRelated(ctx, id, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachDetails(ctx, products...); err != nil {
+		return nil, err
+	}
+	return dto.ToProductResponseList(products), nil
+}
+
+// RefreshRelated recomputes the co-purchase counts of related products
+func (s *productService) RefreshRelated(ctx context.Context) error {
+	start := time.Now()
+	if err := s.productRepo.RefreshRelated(ctx); err != nil {
+		return err
+	}
+	invalidateCatalog(ctx, s.responseCache)
+	logger.InfoContext(ctx, "related products refreshed", "duration", time.Since(start))
+	return nil
+}
+
+// PurgeArchived permanently deletes the products archived for longer than retention that
+// no order refers to, along with their image files
+func (s *productService) PurgeArchived(ctx context.Context, retention time.Duration) error {
+	before := time.Now().Add(-retention)
+	total := 0
+	for ctx.Err() == nil {
+		purged, keys, err := s.productRepo.PurgeArchived(ctx, before, archivePurgeBatch)
+		if err != nil {
+			return err
+		}
+		total += purged
+		for _, key := range keys {
+			if err := s.storage.Delete(ctx, key); err != nil {
+				logger.ErrorContext(ctx, "Failed to delete image file", "key", key, "error", err.Error())
+			}
+		}
+		if purged < archivePurgeBatch {
+			break
+		}
+	}
+	if total > 0 {
+		logger.InfoContext(ctx, "archived products purged", "count", total)
+	}
+	return ctx.Err()
+}
+
+// ListArchived retrieves archived products, most recently created first
+func (s *productService) ListArchived(ctx context.Context, page, limit int) ([]dto.ProductResponse, *dto.PaginationMeta, error) {
+	if page <= 1 {
+		page = 1
+	}
+	if limit <= 1 {
+		limit = 10
+	}
+
+	products, total, err := s.productRepo.List(ctx, limit, (page-1)*limit, repository.ProductFilter{Archived: repository.ArchivedOnly})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.attachDetails(ctx, products...); err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.PaginationMeta{
+		Total:      total,
+		Limit:      limit,
+		Page:       page,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}
+	return dto.ToProductResponseList(products), pagination, nil
+}
+
+// Stats aggregates the active products and the top sellers over [from, to), the last
+// 30 days by default
+func (s *productService) Stats(ctx context.Context, req dto.ProductStatsRequest) (*dto.ProductStatsResponse, error) {
+	if req.To.IsZero() {
+		req.To = time.Now()
+	}
+	if req.From.IsZero() {
+		req.From = req.To.Add(-defaultStatsRange)
+	}
+	if !req.To.After(req.From) {
+		return nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "to", Message: "to harus setelah from"},
+		})
+	}
+	if req.TopLimit < 1 {
+		req.TopLimit = defaultTopSellers
+	}
+	req.TopLimit = min(req.TopLimit, maxTopSellers)
+
+	summary, err := s.productRepo.InventorySummary(ctx)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.productRepo.CategoryStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sales, err := s.productRepo.TopSellers(ctx, req.From, req.To, req.TopLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToProductStatsResponse(summary, categories, sales, req.From, req.To)
+	return &response, nil
+}
+
+// ListLowStock retrieves the products at or below their reorder threshold, the furthest
+// below first, with the quantity to reorder for the forecast demand of the coming weeks
+func (s *productService) ListLowStock(ctx context.Context, page, limit int) ([]dto.ProductResponse, *dto.PaginationMeta, error) {
+	if page <= 1 {
+		page = 1
+	}
+	if limit <= 1 {
+		limit = 10
+	}
+
+	products, total, err := s.productRepo.ListLowStock(ctx, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.attachDetails(ctx, products...); err != nil {
+		return nil, nil, err
+	}
+	forecasts, err := s.forecastDemand(ctx, products, defaultForecastWeeks, defaultForecastWindow)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	responses := dto.ToProductResponseList(products)
+	for i, p := range products {
+		suggested := suggestReorder(p, forecasts[p.ID])
+		responses[i].SuggestedReorderQuantity = &suggested
+	}
+
+	pagination := &dto.PaginationMeta{
+		Total:      total,
+		Limit:      limit,
+		Page:       page,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}
+	return responses, pagination, nil
+}
+
+// Restore brings an archived product back into listings and new orders
+func (s *productService) Restore(ctx context.Context, id uuid.UUID) (*dto.ProductResponse, error) {
+	if err := s.productRepo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	invalidateCatalog(ctx, s.responseCache)
+	return s.GetByID(ctx, id)
+}
+
+// BulkUpdatePrices applies price rules to the matching products in a single transaction,
+// or only computes the changes when preview is requested
+func (s *productService) BulkUpdatePrices(ctx context.Context, req *dto.BulkPriceUpdateRequest) (*dto.BulkPriceUpdateResponse, error) {
+	changes := make(map[uuid.UUID]*entities.PriceChange)
+	order := make([]uuid.UUID, 0)
+
+	for i, ruleReq := range req.Rules {
+		filter, err := s.priceRuleFilter(ctx, ruleReq)
+		if err != nil {
+			return nil, err
+		}
+		products, err := s.productRepo.FindAll(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		rule, err := priceRule(ruleReq)
+		if err != nil {
+			return nil, apperror.NewValidationError([]apperror.ValidationError{
+				{Field: fmt.Sprintf("rules[%d].value", i), Message: err.Error()},
+			})
+		}
+		for _, p := range products {
+			change, ok := changes[p.ID]
+			if !ok {
+				change = &entities.PriceChange{ProductID: p.ID, Name: p.Name, OldPrice: p.Price, NewPrice: p.Price}
+				changes[p.ID] = change
+				order = append(order, p.ID)
+			}
+			change.NewPrice = rule.Apply(change.NewPrice)
+			if change.NewPrice < 0 {
+				return nil, apperror.NewValidationError([]apperror.ValidationError{
+					{Field: fmt.Sprintf("rules[%d]", i), Message: "harga produk %s menjadi negatif", Args: []any{p.Name}},
+				})
+			}
+		}
+	}
+
+	// Products whose price ends up unchanged are left out
+	result := make([]entities.PriceChange, 0, len(order))
+	for _, id := range order {
+		if c := changes[id]; c.NewPrice != c.OldPrice {
+			result = append(result, *c)
+		}
+	}
+
+	if !req.Preview && len(result) > 0 {
+		if err := s.productRepo.UpdatePrices(ctx, result); err != nil {
+			return nil, err
+		}
+		invalidateCatalog(ctx, s.responseCache)
+	}
+
+	response := dto.ToBulkPriceUpdateResponse(result, req.Preview)
+	return &response, nil
+}
+
+// priceRule converts a rule request, the value of amount and set rules must be a whole
+// number of minor units
+func priceRule(req dto.PriceRuleRequest) (entities.PriceRule, error) {
+	rule := entities.PriceRule{
+		Adjustment: entities.PriceAdjustment(req.Adjustment),
+		RoundTo:    req.RoundTo,
+		Ending:     req.Ending,
+	}
+	if rule.Adjustment == entities.PriceAdjustmentPercent {
+		rule.Percent = req.Value
+		return rule, nil
+	}
+	if req.Value != math.Trunc(req.Value) || math.Abs(req.Value) > math.MaxInt64/2 {
+		return rule, errors.New("value harus berupa bilangan bulat dalam satuan terkecil mata uang")
+	}
+	rule.Amount = int64(req.Value)
+	return rule, nil
+}
+
+// priceRuleFilter builds the product filter selecting the products of a price rule
+func (s *productService) priceRuleFilter(ctx context.Context, rule dto.PriceRuleRequest) (repository.ProductFilter, error) {
+	filter := repository.ProductFilter{IDs: rule.ProductIDs}
+
+	switch {
+	case rule.CategoryID != nil:
+		if _, err := s.categoryRepo.GetByID(ctx, *rule.CategoryID); err != nil {
+			return filter, err
+		}
+		filter.CategoryIDs = []uuid.UUID{*rule.CategoryID}
+	case rule.Category != "":
+		// the category column holds the slug for linked products and the raw text for
+		// older free-text ones, so match on both
+		filter.Categories = []string{rule.Category}
+		if c, err := s.categoryRepo.GetBySlug(ctx, utils.Slugify(rule.Category)); err == nil && c.Slug != rule.Category {
+			filter.Categories = append(filter.Categories, c.Slug)
+		} else if err != nil && !errors.Is(err, apperror.ErrCategoryNotFound) {
+			return filter, err
+		}
+	}
+	return filter, nil
+}
+
+// invalidateCatalog drops the cached catalog responses after a write to products or what
+// they show: images, categories, attributes and tags. A failure is only logged, the
+// responses expire with RESPONSE_CACHE_TTL
+func invalidateCatalog(ctx context.Context, responseCache localrepo.ResponseCacheRepository) {
+	if err := responseCache.Invalidate(ctx, entities.CacheScopeCatalog); err != nil {
+		logger.WarnContext(ctx, "response cache invalidation failed", "error", err)
+	}
+}
+
+// resolveCategory links the product to a category by ID, or by slug for clients
+// still sending the free-text category; unknown free-text categories are kept as is
+func (s *productService) resolveCategory(ctx context.Context, product *entities.Product, categoryID *uuid.UUID, category string) error {
+	if categoryID != nil {
+		c, err := s.categoryRepo.GetByID(ctx, *categoryID)
+		if err != nil {
+			return err
+		}
+		product.CategoryID = &c.ID
+		product.Category = c.Slug
+		return nil
+	}
+
+	c, err := s.categoryRepo.GetBySlug(ctx, utils.Slugify(category))
+	if err != nil {
+		if errors.Is(err, apperror.ErrCategoryNotFound) {
+			product.CategoryID = nil
+			product.Category = category
+			return nil
+		}
+		return err
+	}
+	product.CategoryID = &c.ID
+	product.Category = c.Slug
+	return nil
+}
+
+// attachDetails loads the image galleries, attribute values and tags of the products,
+// one query each regardless of the number of products
+func (s *productService) attachDetails(ctx context.Context, products ...*entities.Product) error {
+	ids := make([]uuid.UUID, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+
+	images, err := s.imageRepo.ListByProductIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	attributes, err := s.attributeRepo.ListValuesByProductIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	tags, err := s.tagRepo.ListByProductIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, p := range products {
+		p.Images = images[p.ID]
+		p.Attributes = attributes[p.ID]
+		p.Tags = tags[p.ID]
+	}
+	return nil
+}
+
+// resolveTags looks up the tags by slug; every slug must name an existing tag
+func (s *productService) resolveTags(ctx context.Context, slugs []string) ([]entities.Tag, error) {
+	if len(slugs) == 0 {
+		return nil, nil
+	}
+
+	found, err := s.tagRepo.GetBySlugs(ctx, slugs)
+	if err != nil {
+		return nil, err
+	}
+	bySlug := make(map[string]*entities.Tag, len(found))
+	for _, t := range found {
+		bySlug[t.Slug] = t
+	}
+
+	var details []apperror.ValidationError
+	tags := make([]entities.Tag, 0, len(found))
+	for i, slug := range slugs {
+		t, ok := bySlug[slug]
+		if !ok {
+			details = append(details, apperror.ValidationError{Field: fmt.Sprintf("tags[%d]", i), Message: "tag %s tidak ditemukan", Args: []any{slug}})
+			continue
+		}
+		if !slices.ContainsFunc(tags, func(existing entities.Tag) bool { return existing.ID == t.ID }) {
+			tags = append(tags, *t)
+		}
+	}
+	if len(details) > 0 {
+		return nil, apperror.NewValidationError(details)
+	}
+	return tags, nil
+}
+
+// tagIDs returns the IDs of the tags
+func tagIDs(tags []entities.Tag) []uuid.UUID {
+	ids := make([]uuid.UUID, len(tags))
+	for i, t := range tags {
+		ids[i] = t.ID
+	}
+	return ids
+}
+
+// buildAttributes validates raw attribute values against the definitions of the
+// product's category and returns them in canonical form
+func (s *productService) buildAttributes(ctx context.Context, product *entities.Product, values map[string]string) ([]entities.ProductAttribute, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	if product.CategoryID == nil {
+		return nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "attributes", Message: "atribut hanya dapat diisi untuk produk dengan kategori terdaftar"},
+		})
+	}
+
+	defs, err := s.attributeRepo.ListDefinitionsByCategory(ctx, *product.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	byCode := make(map[string]*entities.AttributeDefinition, len(defs))
+	for _, d := range defs {
+		byCode[d.Code] = d
+	}
+
+	codes := make([]string, 0, len(values))
+	for code := range values {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	var details []apperror.ValidationError
+	attributes := make([]entities.ProductAttribute, 0, len(values))
+	for _, code := range codes {
+		field := "attributes." + code
+		def, ok := byCode[code]
+		if !ok {
+			details = append(details, apperror.ValidationError{Field: field, Message: "atribut tidak tersedia untuk kategori ini"})
+			continue
+		}
+		value, ok := def.Type.Normalize(values[code])
+		if !ok {
+			details = append(details, apperror.ValidationError{Field: field, Message: "nilai harus bertipe %s", Args: []any{string(def.Type)}})
+			continue
+		}
+		attributes = append(attributes, entities.ProductAttribute{
+			ProductID:   product.ID,
+			AttributeID: def.ID,
+			Code:        def.Code,
+			Name:        def.Name,
+			Type:        def.Type,
+			Unit:        def.Unit,
+			Value:       value,
+		})
+	}
+	if len(details) > 0 {
+		return nil, apperror.NewValidationError(details)
+	}
+	return attributes, nil
+}
+
+// attributeFilterValues expands each filter value to its canonical forms; the
+// attribute type isn't known here, so every type that accepts the value is tried
+func attributeFilterValues(filters map[string]string) map[string][]string {
+	if len(filters) == 0 {
+		return nil
+	}
+
+	types := []entities.AttributeType{entities.AttributeTypeString, entities.AttributeTypeNumber, entities.AttributeTypeBoolean}
+	result := make(map[string][]string, len(filters))
+	for code, raw := range filters {
+		var candidates []string
+		for _, t := range types {
+			value, ok := t.Normalize(raw)
+			if ok && !slices.Contains(candidates, value) {
+				candidates = append(candidates, value)
+			}
+		}
+		result[code] = candidates
+	}
+	return result
+}
+
+// listFilter validates the list request and converts it to a repository filter
+func listFilter(req dto.ProductListRequest) (repository.ProductFilter, error) {
+	if req.MinPrice != nil && req.MaxPrice != nil && *req.MaxPrice < *req.MinPrice {
+		return repository.ProductFilter{}, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "max_price", Message: "max_price harus lebih besar atau sama dengan min_price"},
+		})
+	}
+
+	return repository.ProductFilter{
+		Search:     req.Search,
+		Categories: req.Categories,
+		MinPrice:   req.MinPrice,
+		MaxPrice:   req.MaxPrice,
+		InStock:    req.InStock,
+		Attributes: attributeFilterValues(req.Attributes),
+		Tags:       req.Tags,
+		Sort:       req.Sort,
+	}, nil
+}
+
+// sameCategory reports whether two optional category IDs point to the same category
+func sameCategory(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
 }
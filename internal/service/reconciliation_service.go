@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/logger"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxSettlementLines caps the number of lines of an uploaded settlement report
+const maxSettlementLines = 100000
+
+type reconciliationService struct {
+	reconciliationRepo repository.ReconciliationRepository
+	captureRepo        repository.PaymentCaptureRepository
+	source             service.SettlementSource
+	dailyAt            time.Duration
+}
+
+// NewReconciliationService creates a new ReconciliationService instance. source may be nil
+// while no provider API is configured, reports are then uploaded by hand
+func NewReconciliationService(reconciliationRepo repository.ReconciliationRepository, captureRepo repository.PaymentCaptureRepository, source service.SettlementSource, dailyAt time.Duration) service.ReconciliationService {
+	return &reconciliationService{
+		reconciliationRepo: reconciliationRepo,
+		captureRepo:        captureRepo,
+		source:             source,
+		dailyAt:            dailyAt,
+	}
+}
+
+// ImportReport parses an uploaded CSV settlement report and reconciles it
+func (s *reconciliationService) ImportReport(ctx context.Context, req dto.UploadSettlementReportRequest) (*dto.ReconciliationRunResponse, error) {
+	if !req.PeriodEnd.After(req.PeriodStart) {
+		return nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "period_end", Message: "period_end harus setelah period_start"},
+		})
+	}
+
+	lines, err := parseSettlementCSV(req.File)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.reconcile(ctx, &entities.SettlementReport{
+		Source:      entities.SettlementSourceUpload,
+		PeriodStart: req.PeriodStart,
+		PeriodEnd:   req.PeriodEnd,
+		Lines:       lines,
+	})
+}
+
+// ListRuns retrieves reconciliation runs, most recent first
+func (s *reconciliationService) ListRuns(ctx context.Context, page, limit int) ([]dto.ReconciliationRunResponse, *dto.PaginationMeta, error) {
+	if page <= 1 {
+		page = 1
+	}
+	if limit <= 1 {
+		limit = 10
+	}
+
+	runs, total, err := s.reconciliationRepo.ListRuns(ctx, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.PaginationMeta{
+		Total:      total,
+		Limit:      limit,
+		Page:       page,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}
+	return dto.ToReconciliationRunResponseList(runs), pagination, nil
+}
+
+// ListIssues retrieves the review queue, oldest first
+func (s *reconciliationService) ListIssues(ctx context.Context, status string, page, limit int) ([]dto.ReconciliationIssueResponse, *dto.PaginationMeta, error) {
+	if status != "" && status != entities.IssueStatusOpen && status != entities.IssueStatusResolved {
+		return nil, nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "status", Message: "status harus open atau resolved"},
+		})
+	}
+	if page <= 1 {
+		page = 1
+	}
+	if limit <= 1 {
+		limit = 10
+	}
+
+	issues, total, err := s.reconciliationRepo.ListIssues(ctx, status, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.PaginationMeta{
+		Total:      total,
+		Limit:      limit,
+		Page:       page,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}
+	return dto.ToReconciliationIssueResponseList(issues), pagination, nil
+}
+
+// ResolveIssue closes a review queue entry with a note on how it was settled
+func (s *reconciliationService) ResolveIssue(ctx context.Context, id, adminID uuid.UUID, req *dto.ResolveReconciliationIssueRequest) (*dto.ReconciliationIssueResponse, error) {
+	issue, err := s.reconciliationRepo.ResolveIssue(ctx, id, adminID, strings.TrimSpace(req.Note))
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToReconciliationIssueResponse(issue)
+	return &response, nil
+}
+
+// Summary returns the latest run and the open issues per kind
+func (s *reconciliationService) Summary(ctx context.Context) (*dto.ReconciliationSummaryResponse, error) {
+	latest, err := s.reconciliationRepo.LatestRun(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.reconciliationRepo.CountOpenIssues(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &dto.ReconciliationSummaryResponse{OpenIssuesByKind: make(map[string]int, len(counts))}
+	if latest != nil {
+		run := dto.ToReconciliationRunResponse(latest)
+		summary.LatestRun = &run
+	}
+	for kind, count := range counts {
+		summary.OpenIssuesByKind[string(kind)] = count
+		summary.OpenIssues += count
+	}
+	return summary, nil
+}
+
+// RunSchedule reconciles the previous day every day at the configured time until ctx is done
+func (s *reconciliationService) RunSchedule(ctx context.Context) {
+	if s.source == nil {
+		return
+	}
+
+	for {
+		now := time.Now()
+		timer := time.NewTimer(nextDailyRun(now, s.dailyAt).Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		today := time.Now()
+		end := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+		report, err := s.source.FetchReport(ctx, end.AddDate(0, 0, -1), end)
+		if err != nil {
+			logger.ErrorContext(ctx, "fetching settlement report failed", "error", err)
+			continue
+		}
+		report.Source = entities.SettlementSourceAPI
+
+		run, err := s.reconcile(ctx, report)
+		if err != nil {
+			logger.ErrorContext(ctx, "scheduled reconciliation failed", "error", err)
+			continue
+		}
+		logger.InfoContext(ctx, "payments reconciled", "run_id", run.ID, "matched", run.MatchedCount, "issues", run.IssueCount)
+	}
+}
+
+// reconcile matches a report against the captured payments and saves the run and its issues
+func (s *reconciliationService) reconcile(ctx context.Context, report *entities.SettlementReport) (*dto.ReconciliationRunResponse, error) {
+	orderIDs := make([]uuid.UUID, len(report.Lines))
+	for i, line := range report.Lines {
+		orderIDs[i] = line.OrderID
+	}
+
+	payments, err := s.captureRepo.ListCaptured(ctx, report.PeriodStart, report.PeriodEnd, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	run, issues := entities.Reconcile(report, payments)
+	run.ID = uuid.New()
+	run.CreatedAt = time.Now()
+	for i := range issues {
+		issues[i].ID = uuid.New()
+		issues[i].RunID = run.ID
+		issues[i].CreatedAt = run.CreatedAt
+	}
+
+	if err := s.reconciliationRepo.CreateRun(ctx, run, issues); err != nil {
+		return nil, err
+	}
+
+	response := dto.ToReconciliationRunResponse(run)
+	return &response, nil
+}
+
+// parseSettlementCSV parses a settlement report with the header order_id,amount,settled_at,reference.
+// Reports come from the provider, so any malformed line rejects the whole file
+func parseSettlementCSV(r io.Reader) ([]entities.SettlementLine, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "file", Message: "header CSV tidak ditemukan"},
+		})
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"order_id", "amount"} {
+		if _, ok := columns[required]; !ok {
+			return nil, apperror.NewValidationError([]apperror.ValidationError{
+				{Field: "file", Message: "kolom %s wajib ada pada header CSV", Args: []any{required}},
+			})
+		}
+	}
+
+	lines := make([]entities.SettlementLine, 0)
+	var details []apperror.ValidationError
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if len(lines) >= maxSettlementLines {
+			return nil, apperror.NewValidationError([]apperror.ValidationError{
+				{Field: "file", Message: "maksimal %d baris per laporan", Args: []any{maxSettlementLines}},
+			})
+		}
+		field := fmt.Sprintf("rows[%d]", row)
+		if err != nil {
+			details = append(details, apperror.ValidationError{Field: field, Message: "baris CSV tidak valid"})
+			continue
+		}
+
+		get := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		var line entities.SettlementLine
+		if line.OrderID, err = uuid.Parse(get("order_id")); err != nil {
+			details = append(details, apperror.ValidationError{Field: field + ".order_id", Message: "order_id tidak valid"})
+		}
+		if line.Amount, err = entities.ParseAmount(get("amount")); err != nil {
+			details = append(details, apperror.ValidationError{Field: field + ".amount", Message: "amount harus berupa angka dengan maksimal 2 desimal"})
+		}
+		if v := get("settled_at"); v != "" {
+			if line.SettledAt, err = time.Parse(time.RFC3339, v); err != nil {
+				details = append(details, apperror.ValidationError{Field: field + ".settled_at", Message: "settled_at harus berformat RFC3339"})
+			}
+		}
+		line.Reference = get("reference")
+		lines = append(lines, line)
+
+		// a report full of errors is reported with its first few
+		if len(details) >= 20 {
+			break
+		}
+	}
+	if len(details) > 0 {
+		return nil, apperror.NewValidationError(details)
+	}
+	return lines, nil
+}
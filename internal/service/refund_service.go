@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+type refundService struct {
+	refundRepo  repository.RefundRepository
+	orderRepo   repository.OrderRepository
+	paymentRepo repository.PaymentRepository
+	provider    service.PaymentProvider
+	hooks       *OrderHooks
+}
+
+// NewRefundService creates a new RefundService instance. provider may be nil while no
+// payment provider is configured, only orders paid outside a provider can be refunded then
+func NewRefundService(refundRepo repository.RefundRepository, orderRepo repository.OrderRepository, paymentRepo repository.PaymentRepository, provider service.PaymentProvider, hooks *OrderHooks) service.RefundService {
+	return &refundService{
+		refundRepo:  refundRepo,
+		orderRepo:   orderRepo,
+		paymentRepo: paymentRepo,
+		provider:    provider,
+		hooks:       hooks,
+	}
+}
+
+// Refund records a pending refund, returns the money at the payment provider and then
+// completes the refund, restocking its items. A refund the provider refuses is kept as
+// failed so it can be retried
+func (s *refundService) Refund(ctx context.Context, orderID uuid.UUID, adminID uuid.UUID, req *dto.RefundOrderRequest) (*dto.RefundResponse, error) {
+	order, err := s.orderRepo.GetByIDWithItems(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.Status.CanTransitionTo(entities.OrderStatusRefunded) {
+		return nil, apperror.ErrOrderNotRefundable
+	}
+
+	refund := &entities.Refund{
+		ID:        uuid.New(),
+		OrderID:   orderID,
+		Status:    entities.RefundStatusPending,
+		Reason:    req.Reason,
+		CreatedBy: &adminID,
+		Items:     []entities.RefundItem{},
+	}
+	if len(req.Items) == 0 && req.Amount == nil {
+		if err := s.fillRemaining(ctx, order, refund); err != nil {
+			return nil, err
+		}
+	} else if err := fillRequested(order, req, refund); err != nil {
+		return nil, err
+	}
+
+	// orders paid through the provider are refunded there, others by hand
+	payment, err := s.paymentRepo.GetLatestByOrderID(ctx, orderID)
+	if err != nil && !errors.Is(err, apperror.ErrPaymentNotFound) {
+		return nil, err
+	}
+	refund.Provider = entities.RefundProviderManual
+	if payment != nil && payment.Status == entities.PaymentStatusSucceeded {
+		if s.provider == nil || s.provider.Name() != payment.Provider {
+			return nil, apperror.ErrPaymentUnavailable
+		}
+		refund.PaymentID = &payment.ID
+		refund.Provider = payment.Provider
+	}
+
+	if err := s.refundRepo.Create(ctx, refund); err != nil {
+		return nil, err
+	}
+
+	if refund.PaymentID != nil {
+		providerRef, err := s.provider.Refund(ctx, payment, refund)
+		if err != nil {
+			logger.ErrorContext(ctx, "refund at payment provider failed", "order_id", orderID, "refund_id", refund.ID, "provider", refund.Provider, "error", err)
+			refundOutcomes.With("failed").Inc()
+			refund.FailureReason = err.Error()
+			if err := s.refundRepo.Fail(ctx, refund); err != nil {
+				logger.ErrorContext(ctx, "marking refund failed failed", "refund_id", refund.ID, "error", err)
+			}
+			return nil, apperror.ErrPaymentProviderFailed
+		}
+		refund.ProviderRef = providerRef
+	}
+
+	orderRefunded, err := s.refundRepo.Complete(ctx, refund, refundNote(refund))
+	if err != nil {
+		// the money is returned already, the pending refund has to be completed by hand
+		logger.ErrorContext(ctx, "completing refund failed", "order_id", orderID, "refund_id", refund.ID, "provider_ref", refund.ProviderRef, "error", err)
+		return nil, err
+	}
+	refundOutcomes.With("succeeded").Inc()
+
+	response := dto.ToRefundResponse(refund)
+	response.OrderStatus = order.Status.String()
+	if orderRefunded {
+		orderStatusChanges.With(string(entities.OrderStatusRefunded)).Inc()
+		response.OrderStatus = entities.OrderStatusRefunded.String()
+		s.hooks.AfterStatusChangeOf(ctx, s.orderRepo, orderID, order.Status)
+	}
+	return &response, nil
+}
+
+// List returns the refunds of an order
+func (s *refundService) List(ctx context.Context, orderID uuid.UUID) ([]dto.RefundResponse, error) {
+	if _, err := s.orderRepo.GetByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	refunds, err := s.refundRepo.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToRefundResponseList(refunds), nil
+}
+
+// fillRemaining sets a full refund: the amount not refunded yet and, for orders that
+// never left the warehouse, the items not returned yet. Returned parcels of shipped
+// orders are refunded with their items
+func (s *refundService) fillRemaining(ctx context.Context, order *entities.Order, refund *entities.Refund) error {
+	previous, err := s.refundRepo.ListByOrderID(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	refunded := make(map[uuid.UUID]int)
+	refund.Amount = order.TotalAmount
+	for _, p := range previous {
+		if p.Status == entities.RefundStatusFailed {
+			continue
+		}
+		refund.Amount -= p.Amount
+		for _, item := range p.Items {
+			refunded[item.OrderItemID] += item.Quantity
+		}
+	}
+	if refund.Amount <= 0 {
+		return apperror.ErrRefundExceedsOrder
+	}
+
+	if order.Status == entities.OrderStatusPaid || order.Status == entities.OrderStatusProcessing {
+		for _, item := range order.Items {
+			if remaining := item.Quantity - refunded[item.ID]; remaining > 0 {
+				refund.Items = append(refund.Items, entities.RefundItem{OrderItemID: item.ID, ProductID: item.ProductID, Quantity: remaining})
+			}
+		}
+	}
+	return nil
+}
+
+// fillRequested sets a partial refund of the requested items, at their price and their
+// share of the item's tax unless an amount is given
+func fillRequested(order *entities.Order, req *dto.RefundOrderRequest, refund *entities.Refund) error {
+	items := make(map[uuid.UUID]entities.OrderItem, len(order.Items))
+	for _, item := range order.Items {
+		items[item.ID] = item
+	}
+
+	var value int64
+	for _, r := range req.Items {
+		item, ok := items[r.OrderItemID]
+		if !ok {
+			return apperror.ErrOrderItemNotFound
+		}
+		value += item.UnitPrice*int64(r.Quantity) + item.TaxAmount*int64(r.Quantity)/int64(item.Quantity)
+
+		merged := false
+		for i := range refund.Items {
+			if refund.Items[i].OrderItemID == item.ID {
+				refund.Items[i].Quantity += r.Quantity
+				merged = true
+			}
+		}
+		if !merged {
+			refund.Items = append(refund.Items, entities.RefundItem{OrderItemID: item.ID, ProductID: item.ProductID, Quantity: r.Quantity})
+		}
+	}
+
+	refund.Amount = value
+	if req.Amount != nil {
+		refund.Amount = *req.Amount
+	}
+	if refund.Amount <= 0 {
+		return apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "Amount", Message: "jumlah refund harus lebih dari 0"},
+		})
+	}
+	return nil
+}
+
+// refundNote is the status history note of the refund that refunds the order in full
+func refundNote(refund *entities.Refund) string {
+	if refund.Reason != "" {
+		return "Order direfund: " + refund.Reason
+	}
+	return "Order direfund"
+}
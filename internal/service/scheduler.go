@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"postgresDB/internal/domain/service"
+	"postgresDB/internal/repository"
+	"postgresDB/pkg/logger"
+	"postgresDB/pkg/metrics"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	scheduledRuns = metrics.NewCounterVec("scheduled_task_runs_total",
+		"Number of scheduled task runs by result: ok, error or skipped when another instance ran it", "task", "result")
+	scheduledDuration = metrics.NewGaugeVec("scheduled_task_duration_seconds",
+		"Duration of the last run of a scheduled task", "task")
+)
+
+type scheduledTask struct {
+	name     string
+	interval time.Duration
+	run      service.ScheduledTask
+}
+
+type scheduler struct {
+	locks repository.LockRepository
+	// owner identifies this instance in the locks it holds
+	owner string
+
+	mu    sync.Mutex
+	tasks []scheduledTask
+}
+
+// NewScheduler creates a new Scheduler coordinating the instances through locks
+func NewScheduler(locks repository.LockRepository) service.Scheduler {
+	host, _ := os.Hostname()
+	return &scheduler{
+		locks: locks,
+		owner: fmt.Sprintf("%s/%d/%s", host, os.Getpid(), uuid.NewString()[:8]),
+	}
+}
+
+// Register adds a task, a non-positive interval disables it
+func (s *scheduler) Register(name string, interval time.Duration, task service.ScheduledTask) {
+	if interval <= 0 {
+		logger.Info("scheduled task disabled", "task", name)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks = append(s.tasks, scheduledTask{name: name, interval: interval, run: task})
+}
+
+// Run ticks every task on its own interval until ctx is done, then waits for the
+// running tasks to return
+func (s *scheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	tasks := s.tasks
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, task := range tasks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, task)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *scheduler) loop(ctx context.Context, task scheduledTask) {
+	ticker := time.NewTicker(task.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s.runOnce(ctx, task)
+	}
+}
+
+// runOnce runs the task unless another instance did within the interval. The lock is
+// never released, it expires a little before the next tick so the instance ticking
+// first claims the next run. The run is bounded by the lock, so runs never overlap
+func (s *scheduler) runOnce(ctx context.Context, task scheduledTask) {
+	lease := task.interval - task.interval/10
+	locked, err := s.locks.TryLock(ctx, "scheduler:"+task.name, s.owner, lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.ErrorContext(ctx, "locking scheduled task failed", "task", task.name, "error", err)
+			scheduledRuns.With(task.name, "error").Inc()
+		}
+		return
+	}
+	if !locked {
+		scheduledRuns.With(task.name, "skipped").Inc()
+		return
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, lease)
+	defer cancel()
+	start := time.Now()
+	err = task.run(runCtx)
+	scheduledDuration.With(task.name).Set(time.Since(start).Seconds())
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.ErrorContext(ctx, "scheduled task failed", "task", task.name, "duration", time.Since(start), "error", err)
+		}
+		scheduledRuns.With(task.name, "error").Inc()
+		return
+	}
+	scheduledRuns.With(task.name, "ok").Inc()
+}
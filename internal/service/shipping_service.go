@@ -0,0 +1,127 @@
+package service
+
+import (
+	"fmt"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/service"
+	"strings"
+)
+
+// gramsPerKg rounds parcel weights up to started kilograms
+const gramsPerKg = 1000
+
+// flatShipping charges the same cost for every order
+type flatShipping struct {
+	cost int64
+}
+
+func (c flatShipping) Cost(int) int64 {
+	return c.cost
+}
+
+// weightShipping charges a base cost plus a cost for every started kilogram
+type weightShipping struct {
+	base  int64
+	perKg int64
+}
+
+func (c weightShipping) Cost(weightGrams int) int64 {
+	kg := (max(weightGrams, 0) + gramsPerKg - 1) / gramsPerKg
+	return c.base + c.perKg*int64(kg)
+}
+
+type shippingService struct {
+	methods     []entities.ShippingMethod
+	calculators map[string]service.ShippingCalculator
+}
+
+// NewShippingService creates the shipping methods from their configuration, entries of
+// "<code>=flat:<cost>" or "<code>=weight:<base cost>:<cost per kg>" with costs in units.
+// No entries leave shipping off
+func NewShippingService(entries []string) (service.ShippingService, error) {
+	s := &shippingService{
+		methods:     make([]entities.ShippingMethod, 0, len(entries)),
+		calculators: make(map[string]service.ShippingCalculator, len(entries)),
+	}
+	for _, entry := range entries {
+		method, err := parseShippingMethod(entry)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := s.calculators[method.Code]; ok {
+			return nil, fmt.Errorf("shipping method %q is configured twice", method.Code)
+		}
+
+		var calculator service.ShippingCalculator = flatShipping{cost: method.BaseCost}
+		if method.Calculator == entities.ShippingCalculatorWeight {
+			calculator = weightShipping{base: method.BaseCost, perKg: method.CostPerKg}
+		}
+		s.methods = append(s.methods, method)
+		s.calculators[method.Code] = calculator
+	}
+	return s, nil
+}
+
+func (s *shippingService) Methods() []dto.ShippingMethodResponse {
+	methods := make([]dto.ShippingMethodResponse, len(s.methods))
+	for i, m := range s.methods {
+		methods[i] = dto.ShippingMethodResponse{
+			Code:       m.Code,
+			Calculator: m.Calculator,
+			BaseCost:   m.BaseCost,
+			CostPerKg:  m.CostPerKg,
+		}
+	}
+	return methods
+}
+
+func (s *shippingService) Quote(code string, weightGrams int) (*entities.ShippingMethod, int64, error) {
+	if len(s.methods) == 0 {
+		return nil, 0, apperror.ErrShippingMethodNotFound
+	}
+	method := s.methods[0]
+	if code != "" {
+		found := false
+		for _, m := range s.methods {
+			if m.Code == code {
+				method, found = m, true
+				break
+			}
+		}
+		if !found {
+			return nil, 0, apperror.ErrShippingMethodNotFound
+		}
+	}
+	return &method, s.calculators[method.Code].Cost(weightGrams), nil
+}
+
+// parseShippingMethod parses a single shipping method entry
+func parseShippingMethod(entry string) (entities.ShippingMethod, error) {
+	code, spec, ok := strings.Cut(strings.TrimSpace(entry), "=")
+	code = strings.TrimSpace(code)
+	if !ok || code == "" {
+		return entities.ShippingMethod{}, fmt.Errorf("shipping method %q: want <code>=<calculator>:<costs>", entry)
+	}
+	parts := strings.Split(strings.TrimSpace(spec), ":")
+	costs := make([]int64, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		cost, err := entities.ParseAmount(part)
+		if err != nil || cost < 0 {
+			return entities.ShippingMethod{}, fmt.Errorf("shipping method %q: invalid cost %q", entry, part)
+		}
+		costs = append(costs, cost)
+	}
+
+	method := entities.ShippingMethod{Code: code, Calculator: parts[0]}
+	switch {
+	case method.Calculator == entities.ShippingCalculatorFlat && len(costs) == 1:
+		method.BaseCost = costs[0]
+	case method.Calculator == entities.ShippingCalculatorWeight && len(costs) == 2:
+		method.BaseCost, method.CostPerKg = costs[0], costs[1]
+	default:
+		return entities.ShippingMethod{}, fmt.Errorf("shipping method %q: want flat:<cost> or weight:<base cost>:<cost per kg>", entry)
+	}
+	return method, nil
+}
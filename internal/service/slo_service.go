@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/service"
+	"postgresDB/internal/infrastruktur/alert"
+	"postgresDB/pkg/metrics"
+	"sync"
+	"time"
+)
+
+// Alert levels of an SLO, from its burn rates
+const (
+	sloAlertFastBurn = "fast_burn"
+	sloAlertSlowBurn = "slow_burn"
+)
+
+// sloShortWindowRatio is how much shorter the short window confirming a burn rate is than
+// its long window, so an alert resolves soon after the burn stops
+const sloShortWindowRatio = 12
+
+var (
+	sloObjective = metrics.NewGaugeVec("slo_objective",
+		"Share of good events promised by a service level objective", "slo")
+	sloIndicator = metrics.NewGaugeVec("slo_sli",
+		"Share of good events of a service level objective over its error budget period", "slo")
+	sloBudgetRemaining = metrics.NewGaugeVec("slo_error_budget_remaining",
+		"Share of the error budget left in the period, negative once spent", "slo")
+	sloBurnRate = metrics.NewGaugeVec("slo_burn_rate",
+		"How many times faster than sustainable the error budget burned over a window", "slo", "window")
+)
+
+// SLI reads the good and total events of a service level indicator, counted since the start
+type SLI func() (good, total float64)
+
+// SLO is a service level objective: the share of good events promised over the period
+type SLO struct {
+	// Name identifies the objective in metrics, alerts and reports, e.g. checkout_success
+	Name        string
+	Description string
+	// Objective is the promised share of good events, e.g. 0.99
+	Objective float64
+	SLI       SLI
+}
+
+// SLOConfig controls the error budget period of the objectives and when their burn rate
+// is alerted. A burn rate alerts when both its window and a twelfth of it burn that fast
+type SLOConfig struct {
+	// Interval is how often the objectives are evaluated
+	Interval time.Duration
+	// Period is the error budget period
+	Period time.Duration
+	// FastBurnRate over FastBurnWindow raises a critical alert, e.g. 14.4 over 1h spends
+	// 2% of a 30 day budget
+	FastBurnRate   float64
+	FastBurnWindow time.Duration
+	// SlowBurnRate over SlowBurnWindow raises a warning, e.g. 6 over 6h spends 5% of a 30
+	// day budget
+	SlowBurnRate   float64
+	SlowBurnWindow time.Duration
+	// MinEvents is the fewest events in a window for its burn rate to alert, so a single
+	// failure right after a restart doesn't page anyone
+	MinEvents int
+}
+
+// sloSample is a reading of an indicator
+type sloSample struct {
+	at    time.Time
+	good  float64
+	total float64
+}
+
+// sloState is the evaluation state of one objective
+type sloState struct {
+	slo     SLO
+	samples []sloSample
+	alert   string
+	report  dto.SLOResponse
+}
+
+type sloService struct {
+	cfg     SLOConfig
+	alerter *Alerter
+
+	mu   sync.RWMutex
+	slos []*sloState
+}
+
+// NewSLOService creates a new SLOService evaluating slos and alerting through alerter.
+// The indicators count since the server started, so the period starts with it too
+func NewSLOService(cfg SLOConfig, slos []SLO, alerter *Alerter) service.SLOService {
+	s := &sloService{
+		cfg:     cfg,
+		alerter: alerter,
+		slos:    make([]*sloState, 0, len(slos)),
+	}
+	for _, slo := range slos {
+		sloObjective.With(slo.Name).Set(slo.Objective)
+		s.slos = append(s.slos, &sloState{slo: slo})
+	}
+	s.evaluate(context.Background(), time.Now())
+	return s
+}
+
+// CheckoutSLI reads the successful checkout attempts, order placement and payment, and
+// all attempts. Errors the customer can fix aren't attempts, see recordCheckout
+func CheckoutSLI() (good, total float64) {
+	for _, stage := range []string{checkoutStageOrder, checkoutStagePayment} {
+		succeeded := checkoutAttempts.With(stage, checkoutSucceeded).Value()
+		good += succeeded
+		total += succeeded + checkoutAttempts.With(stage, checkoutFailed).Value()
+	}
+	return good, total
+}
+
+// Report returns the state of every objective as of the last evaluation
+func (s *sloService) Report() []dto.SLOResponse {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	reports := make([]dto.SLOResponse, 0, len(s.slos))
+	for _, state := range s.slos {
+		reports = append(reports, state.report)
+	}
+	return reports
+}
+
+// Run evaluates the objectives on every tick until ctx is done
+func (s *sloService) Run(ctx context.Context) {
+	if len(s.slos) == 0 {
+		return
+	}
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.evaluate(ctx, now)
+		}
+	}
+}
+
+// evaluate samples every indicator, updates the reports and metrics and fires or
+// resolves the burn rate alerts
+func (s *sloService) evaluate(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, state := range s.slos {
+		s.evaluateSLO(ctx, state, now)
+	}
+}
+
+func (s *sloService) evaluateSLO(ctx context.Context, state *sloState, now time.Time) {
+	slo := state.slo
+	state.record(now, s.horizon(now))
+	latest := state.samples[len(state.samples)-1]
+	budget := 1 - slo.Objective
+
+	start := state.sampleAt(now.Add(-s.cfg.Period))
+	good, total := latest.good-start.good, latest.total-start.total
+	sli := 1.0
+	if total > 0 {
+		sli = good / total
+	}
+	remaining := 1.0
+	if budget > 0 {
+		remaining = 1 - (1-sli)/budget
+	}
+	sloIndicator.With(slo.Name).Set(sli)
+	sloBudgetRemaining.With(slo.Name).Set(remaining)
+
+	windows := []struct {
+		window    time.Duration
+		threshold float64
+	}{
+		{s.cfg.FastBurnWindow, s.cfg.FastBurnRate},
+		{s.cfg.FastBurnWindow / sloShortWindowRatio, s.cfg.FastBurnRate},
+		{s.cfg.SlowBurnWindow, s.cfg.SlowBurnRate},
+		{s.cfg.SlowBurnWindow / sloShortWindowRatio, s.cfg.SlowBurnRate},
+	}
+	burnRates := make([]dto.SLOBurnRateResponse, 0, len(windows))
+	burning := make([]bool, len(windows))
+	for i, w := range windows {
+		from := state.sampleAt(now.Add(-w.window))
+		events := latest.total - from.total
+		rate := 0.0
+		if events > 0 && budget > 0 {
+			rate = (events - (latest.good - from.good)) / events / budget
+		}
+		// the long windows need enough events, the short ones only confirm them
+		burning[i] = rate >= w.threshold && (i%2 == 1 || events >= float64(s.cfg.MinEvents))
+		sloBurnRate.With(slo.Name, w.window.String()).Set(rate)
+		burnRates = append(burnRates, dto.SLOBurnRateResponse{Window: w.window.String(), Rate: rate, Threshold: w.threshold})
+	}
+
+	level, burn := "", burnRates[0]
+	switch {
+	case burning[0] && burning[1]:
+		level = sloAlertFastBurn
+	case burning[2] && burning[3]:
+		level, burn = sloAlertSlowBurn, burnRates[2]
+	}
+	s.alert(ctx, state, level, burn, remaining, now)
+
+	state.report = dto.SLOResponse{
+		Name:                 slo.Name,
+		Description:          slo.Description,
+		Objective:            slo.Objective,
+		Period:               min(s.cfg.Period, now.Sub(state.samples[0].at)).Round(time.Second).String(),
+		GoodEvents:           int64(good),
+		TotalEvents:          int64(total),
+		SLI:                  sli,
+		ErrorBudgetRemaining: remaining,
+		BurnRates:            burnRates,
+		Alert:                state.alert,
+	}
+}
+
+// alert fires when the objective starts burning its budget, or burns it faster than
+// before, and resolves once it no longer does
+func (s *sloService) alert(ctx context.Context, state *sloState, level string, burn dto.SLOBurnRateResponse, remaining float64, now time.Time) {
+	if level == state.alert || (level == sloAlertSlowBurn && state.alert == sloAlertFastBurn) {
+		// a fast burn slowing down stays alerted until the burn stops
+		return
+	}
+	previous := state.alert
+	state.alert = level
+
+	slo := state.slo
+	al := alert.Alert{
+		Name:   "slo_" + slo.Name + "_burn_rate",
+		Status: alert.StatusFiring,
+		Details: map[string]any{
+			"slo":                    slo.Name,
+			"objective":              slo.Objective,
+			"error_budget_remaining": remaining,
+		},
+		At: now,
+	}
+	switch level {
+	case sloAlertFastBurn, sloAlertSlowBurn:
+		al.Severity = alert.SeverityWarning
+		if level == sloAlertFastBurn {
+			al.Severity = alert.SeverityCritical
+		}
+		al.Summary = fmt.Sprintf("%s burned its error budget %.1f times faster than sustainable over the last %s, %.0f%% of the budget left",
+			slo.Name, burn.Rate, burn.Window, remaining*100)
+		al.Details["burn_rate"] = burn.Rate
+		al.Details["window"] = burn.Window
+	default:
+		al.Status = alert.StatusResolved
+		al.Severity = alert.SeverityWarning
+		if previous == sloAlertFastBurn {
+			al.Severity = alert.SeverityCritical
+		}
+		al.Summary = fmt.Sprintf("%s no longer burns its error budget too fast, %.0f%% of the budget left", slo.Name, remaining*100)
+	}
+	s.alerter.Fire(ctx, al)
+}
+
+// horizon is the oldest time a sample is needed for
+func (s *sloService) horizon(now time.Time) time.Time {
+	return now.Add(-max(s.cfg.Period, s.cfg.FastBurnWindow, s.cfg.SlowBurnWindow))
+}
+
+// record appends a reading of the indicator and drops the samples older than needed
+func (st *sloState) record(now, horizon time.Time) {
+	good, total := st.slo.SLI()
+	st.samples = append(st.samples, sloSample{at: now, good: good, total: total})
+
+	// keep the last sample before the horizon, it's where the longest window starts
+	keep := 0
+	for keep+1 < len(st.samples) && !st.samples[keep+1].at.After(horizon) {
+		keep++
+	}
+	st.samples = st.samples[keep:]
+}
+
+// sampleAt returns the latest sample taken at or before t, or the oldest one while the
+// server hasn't run that long
+func (st *sloState) sampleAt(t time.Time) sloSample {
+	sample := st.samples[0]
+	for _, s := range st.samples {
+		if s.at.After(t) {
+			break
+		}
+		sample = s
+	}
+	return sample
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/logger"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type stockCountService struct {
+	countRepo     repository.StockCountRepository
+	warehouseRepo repository.WarehouseRepository
+	categoryRepo  repository.CategoryRepository
+	productRepo   repository.ProductRepository
+}
+
+// NewStockCountService creates a new StockCountService instance
+func NewStockCountService(countRepo repository.StockCountRepository, warehouseRepo repository.WarehouseRepository, categoryRepo repository.CategoryRepository, productRepo repository.ProductRepository) service.StockCountService {
+	return &stockCountService{
+		countRepo:     countRepo,
+		warehouseRepo: warehouseRepo,
+		categoryRepo:  categoryRepo,
+		productRepo:   productRepo,
+	}
+}
+
+// Create opens a count session. The products stocked in the warehouse, or in the
+// category, are expected at their current stock
+func (s *stockCountService) Create(ctx context.Context, adminID uuid.UUID, req *dto.CreateStockCountRequest) (*dto.StockCountResponse, error) {
+	if _, err := s.warehouseRepo.GetByID(ctx, req.WarehouseID); err != nil {
+		return nil, err
+	}
+	if req.CategoryID != nil {
+		if _, err := s.categoryRepo.GetByID(ctx, *req.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	count := &entities.StockCount{
+		ID:          uuid.New(),
+		WarehouseID: req.WarehouseID,
+		CategoryID:  req.CategoryID,
+		Status:      entities.StockCountCounting,
+		Note:        strings.TrimSpace(req.Note),
+		CreatedBy:   &adminID,
+	}
+	if err := s.countRepo.Create(ctx, count); err != nil {
+		return nil, err
+	}
+
+	return s.Get(ctx, count.ID)
+}
+
+// Get returns a count session with its items and variances
+func (s *stockCountService) Get(ctx context.Context, id uuid.UUID) (*dto.StockCountResponse, error) {
+	count, err := s.countRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToStockCountResponse(count)
+	return &response, nil
+}
+
+// List returns the count sessions with status, all of them for ""
+func (s *stockCountService) List(ctx context.Context, status string, page, limit int) ([]dto.StockCountResponse, *dto.PaginationMeta, error) {
+	if status != "" && !entities.StockCountStatus(status).IsValid() {
+		return nil, nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "status", Message: "status harus counting, submitted, approved atau rejected"},
+		})
+	}
+	if page <= 1 {
+		page = 1
+	}
+	if limit <= 1 {
+		limit = 10
+	}
+
+	counts, total, err := s.countRepo.List(ctx, entities.StockCountStatus(status), limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.PaginationMeta{
+		Total:      total,
+		Limit:      limit,
+		Page:       page,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}
+	return dto.ToStockCountResponseList(counts), pagination, nil
+}
+
+// RecordCounts saves counted quantities. Products found in the warehouse that weren't
+// expected can be counted too, as long as they belong to the counted category
+func (s *stockCountService) RecordCounts(ctx context.Context, id, adminID uuid.UUID, req *dto.RecordStockCountRequest) (*dto.StockCountResponse, error) {
+	count, err := s.countRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if count.Status != entities.StockCountCounting {
+		return nil, apperror.ErrStockCountStatus
+	}
+
+	productIDs := make([]uuid.UUID, len(req.Items))
+	for i, item := range req.Items {
+		productIDs[i] = item.ProductID
+	}
+	products, err := checkStockProducts(ctx, s.productRepo, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	if count.CategoryID != nil {
+		var details []apperror.ValidationError
+		for i, id := range productIDs {
+			if p := products[id]; p.CategoryID == nil || *p.CategoryID != *count.CategoryID {
+				details = append(details, apperror.ValidationError{Field: fmt.Sprintf("items[%d].product_id", i), Message: "produk bukan bagian dari kategori yang dihitung"})
+			}
+		}
+		if len(details) > 0 {
+			return nil, apperror.NewValidationError(details)
+		}
+	}
+
+	items := make([]entities.StockCountItem, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = entities.StockCountItem{ProductID: item.ProductID, Counted: item.CountedQuantity}
+	}
+	if err := s.countRepo.RecordCounts(ctx, id, adminID, items); err != nil {
+		return nil, err
+	}
+
+	return s.Get(ctx, id)
+}
+
+// Submit sends a session for approval once every product has been counted
+func (s *stockCountService) Submit(ctx context.Context, id, adminID uuid.UUID) (*dto.StockCountResponse, error) {
+	if err := s.countRepo.Submit(ctx, id, adminID); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, id)
+}
+
+// Approve posts the variances of a submitted session to the stock ledger. Variances are
+// relative to the stock when the session was opened, so movements posted since then
+// are kept
+func (s *stockCountService) Approve(ctx context.Context, id, adminID uuid.UUID, req *dto.ReviewStockCountRequest) (*dto.StockCountResponse, error) {
+	if err := s.countRepo.Approve(ctx, id, adminID, strings.TrimSpace(req.Note)); err != nil {
+		return nil, err
+	}
+	logger.InfoContext(ctx, "stock count approved", "count_id", id, "admin_id", adminID)
+
+	return s.Get(ctx, id)
+}
+
+// Reject drops a session without changing the stock
+func (s *stockCountService) Reject(ctx context.Context, id, adminID uuid.UUID, req *dto.ReviewStockCountRequest) (*dto.StockCountResponse, error) {
+	if err := s.countRepo.Reject(ctx, id, adminID, strings.TrimSpace(req.Note)); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, id)
+}
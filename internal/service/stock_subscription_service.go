@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type stockSubscriptionService struct {
+	subscriptionRepo repository.StockSubscriptionRepository
+	productRepo      repository.ProductRepository
+	consents         service.ConsentChecker
+}
+
+// NewStockSubscriptionService creates a new StockSubscriptionService instance
+func NewStockSubscriptionService(subscriptionRepo repository.StockSubscriptionRepository, productRepo repository.ProductRepository, consents service.ConsentChecker) service.StockSubscriptionService {
+	return &stockSubscriptionService{
+		subscriptionRepo: subscriptionRepo,
+		productRepo:      productRepo,
+		consents:         consents,
+	}
+}
+
+// Subscribe subscribes the user to an out-of-stock product, subscribing twice is a no-op.
+// The notification is a marketing email, so the user has to consent to those
+func (s *stockSubscriptionService) Subscribe(ctx context.Context, userID, productID uuid.UUID) (*dto.StockSubscriptionResponse, error) {
+	granted, err := s.consents.HasConsent(ctx, userID, entities.ConsentMarketingEmail)
+	if err != nil {
+		return nil, err
+	}
+	if !granted {
+		return nil, apperror.ErrConsentRequired
+	}
+
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.Stock > 0 {
+		return nil, apperror.ErrProductInStock
+	}
+
+	if _, err := s.subscriptionRepo.Subscribe(ctx, userID, productID); err != nil {
+		return nil, err
+	}
+
+	response := dto.ToStockSubscriptionResponse(&entities.StockSubscription{
+		UserID:      userID,
+		ProductID:   product.ID,
+		ProductName: product.Name,
+		Stock:       product.Stock,
+		CreatedAt:   time.Now(),
+	})
+	return &response, nil
+}
+
+// Unsubscribe removes the subscription of the user to a product
+func (s *stockSubscriptionService) Unsubscribe(ctx context.Context, userID, productID uuid.UUID) error {
+	return s.subscriptionRepo.Unsubscribe(ctx, userID, productID)
+}
+
+// List retrieves the subscriptions of the user, newest first
+func (s *stockSubscriptionService) List(ctx context.Context, userID uuid.UUID, page, limit int) ([]dto.StockSubscriptionResponse, *dto.PaginationMeta, error) {
+	if page <= 1 {
+		page = 1
+	}
+	if limit <= 1 {
+		limit = 10
+	}
+
+	subscriptions, total, err := s.subscriptionRepo.ListByUser(ctx, userID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.PaginationMeta{
+		Total:      total,
+		Limit:      limit,
+		Page:       page,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}
+	return dto.ToStockSubscriptionResponseList(subscriptions), pagination, nil
+}
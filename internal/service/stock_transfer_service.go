@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/logger"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type stockTransferService struct {
+	transferRepo  repository.StockTransferRepository
+	warehouseRepo repository.WarehouseRepository
+	productRepo   repository.ProductRepository
+}
+
+// NewStockTransferService creates a new StockTransferService instance
+func NewStockTransferService(transferRepo repository.StockTransferRepository, warehouseRepo repository.WarehouseRepository, productRepo repository.ProductRepository) service.StockTransferService {
+	return &stockTransferService{
+		transferRepo:  transferRepo,
+		warehouseRepo: warehouseRepo,
+		productRepo:   productRepo,
+	}
+}
+
+// Create creates a draft transfer. The source warehouse must hold the quantities now;
+// they are checked again, and taken out, when the transfer is shipped
+func (s *stockTransferService) Create(ctx context.Context, adminID uuid.UUID, req *dto.CreateStockTransferRequest) (*dto.StockTransferResponse, error) {
+	if req.SourceWarehouseID == req.DestinationWarehouseID {
+		return nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "destination_warehouse_id", Message: "gudang tujuan harus berbeda dari gudang asal"},
+		})
+	}
+	if _, err := s.warehouseRepo.GetByID(ctx, req.SourceWarehouseID); err != nil {
+		return nil, err
+	}
+	if _, err := s.warehouseRepo.GetByID(ctx, req.DestinationWarehouseID); err != nil {
+		return nil, err
+	}
+
+	productIDs := make([]uuid.UUID, len(req.Items))
+	for i, item := range req.Items {
+		productIDs[i] = item.ProductID
+	}
+	if _, err := checkStockProducts(ctx, s.productRepo, productIDs); err != nil {
+		return nil, err
+	}
+
+	stock, err := s.warehouseRepo.GetStock(ctx, req.SourceWarehouseID, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	var details []apperror.ValidationError
+	for i, item := range req.Items {
+		if available := stock[item.ProductID]; item.Quantity > available {
+			details = append(details, apperror.ValidationError{
+				Field:   fmt.Sprintf("items[%d].quantity", i),
+				Message: "stok di gudang asal hanya %d",
+				Args:    []any{available},
+			})
+		}
+	}
+	if len(details) > 0 {
+		return nil, apperror.NewValidationError(details)
+	}
+
+	transfer := &entities.StockTransfer{
+		ID:                     uuid.New(),
+		SourceWarehouseID:      req.SourceWarehouseID,
+		DestinationWarehouseID: req.DestinationWarehouseID,
+		Status:                 entities.TransferDraft,
+		Note:                   strings.TrimSpace(req.Note),
+		CreatedBy:              &adminID,
+		Items:                  make([]entities.StockTransferItem, len(req.Items)),
+	}
+	for i, item := range req.Items {
+		transfer.Items[i] = entities.StockTransferItem{ProductID: item.ProductID, Quantity: item.Quantity}
+	}
+	if err := s.transferRepo.Create(ctx, transfer); err != nil {
+		return nil, err
+	}
+
+	return s.Get(ctx, transfer.ID)
+}
+
+// Get returns a transfer with its items
+func (s *stockTransferService) Get(ctx context.Context, id uuid.UUID) (*dto.StockTransferResponse, error) {
+	transfer, err := s.transferRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToStockTransferResponse(transfer)
+	return &response, nil
+}
+
+// List returns the transfers with status, oldest first so the longest waiting are handled first
+func (s *stockTransferService) List(ctx context.Context, status string, page, limit int) ([]dto.StockTransferResponse, *dto.PaginationMeta, error) {
+	var statuses []entities.TransferStatus
+	switch status {
+	case "open":
+		statuses = entities.OpenTransferStatuses
+	case "":
+		statuses = []entities.TransferStatus{entities.TransferDraft, entities.TransferInTransit, entities.TransferReceived, entities.TransferCancelled}
+	default:
+		if !entities.TransferStatus(status).IsValid() {
+			return nil, nil, apperror.NewValidationError([]apperror.ValidationError{
+				{Field: "status", Message: "status harus open, draft, in_transit, received, cancelled atau all"},
+			})
+		}
+		statuses = []entities.TransferStatus{entities.TransferStatus(status)}
+	}
+	if page <= 1 {
+		page = 1
+	}
+	if limit <= 1 {
+		limit = 10
+	}
+
+	transfers, total, err := s.transferRepo.List(ctx, statuses, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.PaginationMeta{
+		Total:      total,
+		Limit:      limit,
+		Page:       page,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}
+	return dto.ToStockTransferResponseList(transfers), pagination, nil
+}
+
+// Ship takes the items out of the source warehouse, the transfer is then in transit
+func (s *stockTransferService) Ship(ctx context.Context, id, adminID uuid.UUID) (*dto.StockTransferResponse, error) {
+	transfer, err := s.transferRepo.Ship(ctx, id, adminID)
+	if err != nil {
+		return nil, err
+	}
+	logger.InfoContext(ctx, "stock transfer shipped", "transfer_id", id, "admin_id", adminID)
+
+	response := dto.ToStockTransferResponse(transfer)
+	return &response, nil
+}
+
+// Receive books the items into the destination warehouse
+func (s *stockTransferService) Receive(ctx context.Context, id, adminID uuid.UUID) (*dto.StockTransferResponse, error) {
+	transfer, err := s.transferRepo.Receive(ctx, id, adminID)
+	if err != nil {
+		return nil, err
+	}
+	logger.InfoContext(ctx, "stock transfer received", "transfer_id", id, "admin_id", adminID)
+
+	response := dto.ToStockTransferResponse(transfer)
+	return &response, nil
+}
+
+// Cancel drops a transfer that hasn't been shipped
+func (s *stockTransferService) Cancel(ctx context.Context, id uuid.UUID) (*dto.StockTransferResponse, error) {
+	transfer, err := s.transferRepo.Cancel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToStockTransferResponse(transfer)
+	return &response, nil
+}
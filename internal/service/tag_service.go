@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	localrepo "postgresDB/internal/repository"
+	"postgresDB/pkg/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type tagService struct {
+	tagRepo       repository.TagRepository
+	responseCache localrepo.ResponseCacheRepository
+}
+
+// NewTagService creates a new TagService instance
+func NewTagService(tagRepo repository.TagRepository, responseCache localrepo.ResponseCacheRepository) service.TagService {
+	return &tagService{
+		tagRepo:       tagRepo,
+		responseCache: responseCache,
+	}
+}
+
+// Create creates a new tag
+func (s *tagService) Create(ctx context.Context, req *dto.CreateTagRequest) (*dto.TagResponse, error) {
+	slug := req.Slug
+	if slug == "" {
+		slug = utils.Slugify(req.Name)
+	}
+	if slug == "" {
+		return nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "slug", Message: "slug tidak dapat dibuat dari nama tag"},
+		})
+	}
+
+	tag := &entities.Tag{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Slug:      slug,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := s.tagRepo.Create(ctx, tag); err != nil {
+		return nil, err
+	}
+	invalidateCatalog(ctx, s.responseCache)
+
+	response := dto.ToTagResponse(tag)
+	return &response, nil
+}
+
+// List retrieves all tags
+func (s *tagService) List(ctx context.Context) ([]dto.TagResponse, error) {
+	tags, err := s.tagRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToTagResponseList(tags), nil
+}
+
+// Update updates an existing tag; products keep the tag under its new slug
+func (s *tagService) Update(ctx context.Context, id uuid.UUID, req *dto.UpdateTagRequest) (*dto.TagResponse, error) {
+	tag, err := s.tagRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		tag.Name = *req.Name
+	}
+	if req.Slug != nil {
+		tag.Slug = *req.Slug
+	}
+
+	if err := s.tagRepo.Update(ctx, tag); err != nil {
+		return nil, err
+	}
+	invalidateCatalog(ctx, s.responseCache)
+
+	response := dto.ToTagResponse(tag)
+	return &response, nil
+}
+
+// Delete deletes a tag and removes it from all products
+func (s *tagService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.tagRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	invalidateCatalog(ctx, s.responseCache)
+	return nil
+}
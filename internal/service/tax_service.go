@@ -0,0 +1,73 @@
+package service
+
+import (
+	"fmt"
+	"postgresDB/internal/domain/entities"
+	"postgresDB/internal/domain/service"
+	"strings"
+)
+
+// taxAny matches any region or category in a tax rate entry
+const taxAny = "*"
+
+type taxService struct {
+	// rates are keyed by region and lowercased category, empty for any
+	rates map[[2]string]*entities.TaxRate
+}
+
+// NewTaxService creates the tax rates from their configuration, entries of
+// "<region>[/<category>]=<percent>" where region is a country code and either may be *,
+// e.g. "*=11", "SG=9" or "ID/books=0". No entries leave orders untaxed
+func NewTaxService(entries []string) (service.TaxService, error) {
+	s := &taxService{rates: make(map[[2]string]*entities.TaxRate, len(entries))}
+	for _, entry := range entries {
+		rate, err := parseTaxRate(entry)
+		if err != nil {
+			return nil, err
+		}
+		key := [2]string{rate.Region, strings.ToLower(rate.Category)}
+		if _, ok := s.rates[key]; ok {
+			return nil, fmt.Errorf("tax rate %q is configured twice", rate.Name())
+		}
+		s.rates[key] = rate
+	}
+	return s, nil
+}
+
+func (s *taxService) Rate(region, category string) *entities.TaxRate {
+	region, category = strings.ToUpper(region), strings.ToLower(category)
+	// an empty region or category only finds the rates for any
+	for _, key := range [][2]string{{region, category}, {"", category}, {region, ""}, {"", ""}} {
+		if rate, ok := s.rates[key]; ok {
+			return rate
+		}
+	}
+	return nil
+}
+
+// parseTaxRate parses a single tax rate entry
+func parseTaxRate(entry string) (*entities.TaxRate, error) {
+	scope, percent, ok := strings.Cut(strings.TrimSpace(entry), "=")
+	scope = strings.TrimSpace(scope)
+	if !ok || scope == "" {
+		return nil, fmt.Errorf("tax rate %q: want <region>[/<category>]=<percent>", entry)
+	}
+	region, category, _ := strings.Cut(scope, "/")
+	region, category = strings.ToUpper(strings.TrimSpace(region)), strings.TrimSpace(category)
+	if region == taxAny {
+		region = ""
+	}
+	if category == taxAny {
+		category = ""
+	}
+	if region != "" && len(region) != 2 {
+		return nil, fmt.Errorf("tax rate %q: region must be a two letter country code or *", entry)
+	}
+
+	// percentages have at most two decimals, so in hundredths they are basis points
+	rate, err := entities.ParseAmount(percent)
+	if err != nil || rate < 0 || rate > entities.BasisPoints {
+		return nil, fmt.Errorf("tax rate %q: invalid percentage %q", entry, percent)
+	}
+	return &entities.TaxRate{Region: region, Category: category, Rate: int(rate)}, nil
+}
@@ -17,12 +17,14 @@ import (
 // UserServiceImpl implements the UserService interface
 type userService struct {
 	userRepo repository.UserRepository
+	hasher   utils.PasswordHasher
 }
 
 // NewUserService creates a new UserService instance
-func NewUserService(userRepo repository.UserRepository) service.UserService {
+func NewUserService(userRepo repository.UserRepository, hasher utils.PasswordHasher) service.UserService {
 	return &userService{
 		userRepo: userRepo,
+		hasher:   hasher,
 	}
 }
 
@@ -33,7 +35,12 @@ func (s *userService) GetUser(ctx context.Context, id uuid.UUID, requesterID uui
 		return nil, apperror.ErrUnauthorized
 	}
 
-	user, err := s.userRepo.GetByID(ctx, id)
+	// admins also see deleted users
+	scope := repository.DeletedExclude
+	if requesterRole == entities.RoleAdmin {
+		scope = repository.DeletedInclude
+	}
+	user, err := s.userRepo.GetByIDScoped(ctx, id, scope)
 	if err != nil {
 		return nil, err
 	}
@@ -53,6 +60,10 @@ func (s *userService) Update(ctx context.Context, id uuid.UUID, requesterID uuid
 	if err != nil {
 		return nil, apperror.ErrUserNotFound
 	}
+	// the update only applies to the version the caller read, without one it applies to the latest
+	if req.Version != nil {
+		existingUser.Version = *req.Version
+	}
 
 	// only admin can update role and active status
 	// if req.Role != nil {
@@ -69,15 +80,18 @@ func (s *userService) Update(ctx context.Context, id uuid.UUID, requesterID uuid
 	}
 
 	// Check for uniqueness if email is being updated
-	if req.Email != nil && *req.Email != existingUser.Email {
-		exists, err := s.userRepo.ExistsByEmail(ctx, *req.Email)
-		if err != nil {
-			return nil, err
+	if req.Email != nil {
+		email := utils.NormalizeEmail(*req.Email)
+		if email != utils.NormalizeEmail(existingUser.Email) {
+			exists, err := s.userRepo.ExistsByEmail(ctx, email)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				return nil, apperror.ErrEmailExists
+			}
 		}
-		if exists {
-			return nil, apperror.ErrUserAlreadyExists
-		}
-		existingUser.Email = *req.Email
+		existingUser.Email = email
 	}
 
 	// Check for uniqueness if username is being updated
@@ -87,7 +101,7 @@ func (s *userService) Update(ctx context.Context, id uuid.UUID, requesterID uuid
 			return nil, err
 		}
 		if exists {
-			return nil, apperror.ErrUserAlreadyExists
+			return nil, apperror.ErrUsernameExists
 		}
 		existingUser.Username = *req.Username
 	}
@@ -122,12 +136,12 @@ func (s *userService) ChangePassword(ctx context.Context, id uuid.UUID, requeste
 	}
 
 	// Verify old password
-	if err := utils.CheckPassword(req.OldPassword, userEntity.Password); err != nil {
+	if err := s.hasher.Verify(req.OldPassword, userEntity.Password); err != nil {
 		return apperror.ErrPasswordMismatch
 	}
 
 	// Hash new password
-	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	hashedPassword, err := s.hasher.Hash(req.NewPassword)
 	if err != nil {
 		return err
 	}
@@ -135,12 +149,8 @@ func (s *userService) ChangePassword(ctx context.Context, id uuid.UUID, requeste
 		return apperror.ErrPasswordTooWeak
 	}
 
-	// Update password
-	userEntity.Password = hashedPassword
-	userEntity.UpdatedAt = time.Now()
-
-	// Save changes
-	return s.userRepo.Update(ctx, userEntity)
+	// Save new password
+	return s.userRepo.UpdatePassword(ctx, userEntity.ID, hashedPassword)
 }
 
 // Delete deactivates a user account
@@ -158,3 +168,16 @@ func (s *userService) Delete(ctx context.Context, id uuid.UUID, requesterID uuid
 	// Save changes
 	return s.userRepo.Delete(ctx, id)
 }
+
+// Restore brings a deleted user account back
+func (s *userService) Restore(ctx context.Context, id uuid.UUID) (*dto.UserResponse, error) {
+	if err := s.userRepo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	response := dto.ToUserResponse(user)
+	return &response, nil
+}
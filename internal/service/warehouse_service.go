@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type warehouseService struct {
+	warehouseRepo repository.WarehouseRepository
+	productRepo   repository.ProductRepository
+}
+
+// NewWarehouseService creates a new WarehouseService instance
+func NewWarehouseService(warehouseRepo repository.WarehouseRepository, productRepo repository.ProductRepository) service.WarehouseService {
+	return &warehouseService{
+		warehouseRepo: warehouseRepo,
+		productRepo:   productRepo,
+	}
+}
+
+// Create creates a new warehouse
+func (s *warehouseService) Create(ctx context.Context, req *dto.CreateWarehouseRequest) (*dto.WarehouseResponse, error) {
+	warehouse := &entities.Warehouse{
+		ID:      uuid.New(),
+		Code:    req.Code,
+		Name:    strings.TrimSpace(req.Name),
+		Address: strings.TrimSpace(req.Address),
+	}
+	if err := s.warehouseRepo.Create(ctx, warehouse); err != nil {
+		return nil, err
+	}
+
+	response := dto.ToWarehouseResponse(warehouse)
+	return &response, nil
+}
+
+// List returns all warehouses
+func (s *warehouseService) List(ctx context.Context) ([]dto.WarehouseResponse, error) {
+	warehouses, err := s.warehouseRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToWarehouseResponseList(warehouses), nil
+}
+
+// ListStock returns the stock of a warehouse per product
+func (s *warehouseService) ListStock(ctx context.Context, id uuid.UUID, page, limit int) ([]dto.WarehouseStockResponse, *dto.PaginationMeta, error) {
+	if _, err := s.warehouseRepo.GetByID(ctx, id); err != nil {
+		return nil, nil, err
+	}
+	if page <= 1 {
+		page = 1
+	}
+	if limit <= 1 {
+		limit = 10
+	}
+
+	stock, total, err := s.warehouseRepo.ListStock(ctx, id, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.PaginationMeta{
+		Total:      total,
+		Limit:      limit,
+		Page:       page,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}
+	return dto.ToWarehouseStockResponseList(stock), pagination, nil
+}
+
+// Adjust posts manual stock corrections of a warehouse to the stock ledger. Either all
+// items are applied or, when one would leave a negative stock, none
+func (s *warehouseService) Adjust(ctx context.Context, id, adminID uuid.UUID, req *dto.StockAdjustmentRequest) ([]dto.StockLedgerEntryResponse, error) {
+	if _, err := s.warehouseRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	productIDs := make([]uuid.UUID, len(req.Items))
+	for i, item := range req.Items {
+		productIDs[i] = item.ProductID
+	}
+	if _, err := checkStockProducts(ctx, s.productRepo, productIDs); err != nil {
+		return nil, err
+	}
+
+	note := strings.TrimSpace(req.Note)
+	entries := make([]entities.StockLedgerEntry, len(req.Items))
+	for i, item := range req.Items {
+		entries[i] = entities.StockLedgerEntry{
+			WarehouseID: id,
+			ProductID:   item.ProductID,
+			Quantity:    item.Quantity,
+			Reason:      entities.LedgerAdjustment,
+			Note:        note,
+			CreatedBy:   &adminID,
+		}
+	}
+
+	posted, err := s.warehouseRepo.Post(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToStockLedgerEntryResponseList(posted), nil
+}
+
+// ListLedger returns the stock ledger entries matching the filter, newest first
+func (s *warehouseService) ListLedger(ctx context.Context, req dto.StockLedgerListRequest) ([]dto.StockLedgerEntryResponse, *dto.PaginationMeta, error) {
+	if !req.From.IsZero() && !req.To.IsZero() && !req.To.After(req.From) {
+		return nil, nil, apperror.NewValidationError([]apperror.ValidationError{
+			{Field: "to", Message: "to harus setelah from"},
+		})
+	}
+	page := req.Page
+	if page <= 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit <= 1 {
+		limit = 10
+	}
+
+	filter := entities.StockLedgerFilter{
+		WarehouseID: req.WarehouseID,
+		ProductID:   req.ProductID,
+		ReferenceID: req.ReferenceID,
+		From:        req.From,
+		To:          req.To,
+	}
+
+	entries, total, err := s.warehouseRepo.ListLedger(ctx, filter, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.PaginationMeta{
+		Total:      total,
+		Limit:      limit,
+		Page:       page,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}
+	return dto.ToStockLedgerEntryResponseList(entries), pagination, nil
+}
+
+// checkStockProducts validates the products of a stock document: each may appear once
+// and must be an active product. The products are returned by ID
+func checkStockProducts(ctx context.Context, productRepo repository.ProductRepository, productIDs []uuid.UUID) (map[uuid.UUID]*entities.Product, error) {
+	var details []apperror.ValidationError
+	seen := make(map[uuid.UUID]struct{}, len(productIDs))
+	for i, id := range productIDs {
+		if _, ok := seen[id]; ok {
+			details = append(details, apperror.ValidationError{Field: fmt.Sprintf("items[%d].product_id", i), Message: "produk sudah ada di item lain"})
+		}
+		seen[id] = struct{}{}
+	}
+	if len(details) > 0 {
+		return nil, apperror.NewValidationError(details)
+	}
+
+	products, err := productRepo.GetByIDs(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[uuid.UUID]*entities.Product, len(products))
+	for _, p := range products {
+		found[p.ID] = p
+	}
+	for i, id := range productIDs {
+		if _, ok := found[id]; !ok {
+			details = append(details, apperror.ValidationError{Field: fmt.Sprintf("items[%d].product_id", i), Message: "produk tidak ditemukan"})
+		}
+	}
+	if len(details) > 0 {
+		return nil, apperror.NewValidationError(details)
+	}
+	return found, nil
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"postgresDB/internal/domain/dto"
+	"postgresDB/internal/domain/entities"
+	apperror "postgresDB/internal/domain/errors"
+	"postgresDB/internal/domain/repository"
+	"postgresDB/internal/domain/service"
+	"postgresDB/pkg/logger"
+	"postgresDB/pkg/utils"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// JobTypeWebhook delivers an order event to one webhook endpoint
+	JobTypeWebhook = "webhook"
+
+	webhookSecretPrefix = "whsec_"
+	// webhookResponseLimit caps the response body kept in the delivery log
+	webhookResponseLimit = 1024
+)
+
+// webhookDelivery is the payload of a JobTypeWebhook job. The body is encoded once so
+// every attempt sends the same event
+type webhookDelivery struct {
+	EndpointID uuid.UUID       `json:"endpoint_id"`
+	EventID    uuid.UUID       `json:"event_id"`
+	EventType  string          `json:"event_type"`
+	Body       json.RawMessage `json:"body"`
+}
+
+type webhookService struct {
+	webhookRepo repository.WebhookRepository
+	jobs        service.JobQueue
+	client      *http.Client
+}
+
+// NewWebhookService creates a new WebhookService instance, deliveries are queued on jobs
+func NewWebhookService(webhookRepo repository.WebhookRepository, jobs service.JobQueue) service.WebhookService {
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		jobs:        jobs,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Create registers an endpoint and issues its signing secret
+func (s *webhookService) Create(ctx context.Context, adminID uuid.UUID, req *dto.CreateWebhookRequest) (*dto.WebhookResponse, error) {
+	events := uniqueEvents(req.Events)
+	if err := validateWebhook(req.URL, events); err != nil {
+		return nil, err
+	}
+
+	secret, err := utils.RandomToken(webhookSecretPrefix, 32)
+	if err != nil {
+		return nil, apperror.WrapInternal(err)
+	}
+
+	now := time.Now()
+	endpoint := &entities.WebhookEndpoint{
+		ID:          uuid.New(),
+		URL:         req.URL,
+		Description: req.Description,
+		Secret:      secret,
+		Events:      events,
+		Active:      true,
+		CreatedBy:   &adminID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.webhookRepo.Create(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	logger.InfoContext(ctx, "webhook endpoint registered", "webhook_id", endpoint.ID, "url", endpoint.URL, "created_by", adminID)
+	response := dto.ToWebhookResponse(endpoint)
+	response.Secret = secret
+	return &response, nil
+}
+
+// List retrieves the registered endpoints
+func (s *webhookService) List(ctx context.Context, page, limit int) ([]dto.WebhookResponse, *dto.PaginationMeta, error) {
+	if page <= 1 {
+		page = 1
+	}
+	if limit <= 1 {
+		limit = 10
+	}
+
+	endpoints, total, err := s.webhookRepo.List(ctx, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.PaginationMeta{
+		Total:      total,
+		Limit:      limit,
+		Page:       page,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}
+	return dto.ToWebhookResponseList(endpoints), pagination, nil
+}
+
+// Update changes the URL, description or events of an endpoint, or pauses it
+func (s *webhookService) Update(ctx context.Context, id uuid.UUID, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error) {
+	endpoint, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		endpoint.URL = *req.URL
+	}
+	if req.Description != nil {
+		endpoint.Description = *req.Description
+	}
+	if req.Events != nil {
+		endpoint.Events = uniqueEvents(req.Events)
+	}
+	if req.Active != nil {
+		endpoint.Active = *req.Active
+	}
+	if err := validateWebhook(endpoint.URL, endpoint.Events); err != nil {
+		return nil, err
+	}
+
+	endpoint.UpdatedAt = time.Now()
+	if err := s.webhookRepo.Update(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	response := dto.ToWebhookResponse(endpoint)
+	return &response, nil
+}
+
+// Delete removes an endpoint, its queued deliveries are dropped
+func (s *webhookService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.webhookRepo.Delete(ctx, id)
+}
+
+// Deliveries retrieves the delivery log of an endpoint
+func (s *webhookService) Deliveries(ctx context.Context, id uuid.UUID, page, limit int) ([]dto.WebhookDeliveryResponse, *dto.PaginationMeta, error) {
+	if page <= 1 {
+		page = 1
+	}
+	if limit <= 1 {
+		limit = 10
+	}
+
+	if _, err := s.webhookRepo.GetByID(ctx, id); err != nil {
+		return nil, nil, err
+	}
+	deliveries, total, err := s.webhookRepo.ListDeliveries(ctx, id, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.PaginationMeta{
+		Total:      total,
+		Limit:      limit,
+		Page:       page,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}
+	return dto.ToWebhookDeliveryResponseList(deliveries), pagination, nil
+}
+
+// AfterOrderCreate queues an order.created event
+func (s *webhookService) AfterOrderCreate(ctx context.Context, order *entities.Order) error {
+	return s.publish(ctx, entities.WebhookEventOrderCreated, dto.WebhookOrderData{Order: dto.ToOrderResponse(order)})
+}
+
+// AfterOrderStatusChange queues an order.status_changed event
+func (s *webhookService) AfterOrderStatusChange(ctx context.Context, order *entities.Order, from entities.OrderStatus) error {
+	return s.publish(ctx, entities.WebhookEventOrderStatusChanged, dto.WebhookOrderData{
+		Order:          dto.ToOrderResponse(order),
+		PreviousStatus: from.String(),
+	})
+}
+
+// publish queues a delivery of the event to every endpoint subscribed to it
+func (s *webhookService) publish(ctx context.Context, eventType string, data dto.WebhookOrderData) error {
+	endpoints, err := s.webhookRepo.ListSubscribed(ctx, eventType)
+	if err != nil {
+		return err
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	eventID := uuid.New()
+	event := dto.WebhookEvent{
+		ID:        eventID.String(),
+		Type:      eventType,
+		CreatedAt: time.Now().Format(time.RFC3339),
+		Data:      data,
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encoding webhook event: %w", err)
+	}
+
+	var errs []error
+	for _, endpoint := range endpoints {
+		delivery := webhookDelivery{EndpointID: endpoint.ID, EventID: eventID, EventType: eventType, Body: body}
+		if _, err := s.jobs.Enqueue(ctx, DefaultJobQueue, JobTypeWebhook, delivery); err != nil {
+			errs = append(errs, fmt.Errorf("enqueueing delivery to webhook %s: %w", endpoint.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Deliver posts an event to its endpoint and logs the attempt. Anything but a 2xx answer
+// fails the job, so the job queue retries it with backoff
+func (s *webhookService) Deliver(ctx context.Context, payload json.RawMessage) error {
+	var delivery webhookDelivery
+	if err := json.Unmarshal(payload, &delivery); err != nil {
+		return fmt.Errorf("decoding webhook delivery: %w", err)
+	}
+	endpoint, err := s.webhookRepo.GetByID(ctx, delivery.EndpointID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrWebhookNotFound) {
+			// the endpoint was deleted since the event was queued
+			return nil
+		}
+		return err
+	}
+	if !endpoint.Active {
+		logger.InfoContext(ctx, "webhook delivery dropped, endpoint paused", "webhook_id", endpoint.ID, "event_id", delivery.EventID)
+		return nil
+	}
+
+	log := &entities.WebhookDelivery{
+		ID:         uuid.New(),
+		EndpointID: endpoint.ID,
+		EventID:    delivery.EventID,
+		EventType:  delivery.EventType,
+		Payload:    delivery.Body,
+		CreatedAt:  time.Now(),
+	}
+	sendErr := s.send(ctx, endpoint, &delivery, log)
+	log.DurationMs = int(time.Since(log.CreatedAt).Milliseconds())
+	log.Succeeded = sendErr == nil
+	if sendErr != nil {
+		log.Error = sendErr.Error()
+	}
+	if err := s.webhookRepo.RecordDelivery(ctx, log); err != nil {
+		logger.ErrorContext(ctx, "recording webhook delivery failed", "webhook_id", endpoint.ID, "event_id", delivery.EventID, "error", err)
+	}
+	return sendErr
+}
+
+// send posts the signed event and keeps the answer in log
+func (s *webhookService) send(ctx context.Context, endpoint *entities.WebhookEndpoint, delivery *webhookDelivery, log *entities.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-ID", delivery.EventID.String())
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Signature", signWebhook(endpoint.Secret, time.Now(), delivery.Body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	log.StatusCode = &resp.StatusCode
+	log.ResponseBody = string(body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook endpoint answered %s", resp.Status)
+	}
+	return nil
+}
+
+// signWebhook returns the X-Webhook-Signature header: t=<unix time>,v1=<hex HMAC-SHA256
+// of "<unix time>.<body>" keyed with the endpoint secret>
+func signWebhook(secret string, at time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// uniqueEvents drops repeated event types, keeping their first position
+func uniqueEvents(events []string) []string {
+	unique := make([]string, 0, len(events))
+	for _, event := range events {
+		if !slices.Contains(unique, event) {
+			unique = append(unique, event)
+		}
+	}
+	return unique
+}
+
+func validateWebhook(rawURL string, events []string) error {
+	var details []apperror.ValidationError
+	if u, err := url.Parse(rawURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || u.User != nil {
+		details = append(details, apperror.ValidationError{
+			Field:   "url",
+			Message: "url harus berupa URL http atau https absolut",
+		})
+	}
+	for i, event := range events {
+		if !entities.IsValidWebhookEvent(event) {
+			details = append(details, apperror.ValidationError{
+				Field:   fmt.Sprintf("events[%d]", i),
+				Message: "event %s tidak dikenal",
+				Args:    []any{event},
+			})
+		}
+	}
+	if len(details) > 0 {
+		return apperror.NewValidationError(details)
+	}
+	return nil
+}
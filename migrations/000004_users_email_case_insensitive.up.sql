@@ -0,0 +1,6 @@
+-- Normalize existing emails to lowercase
+UPDATE users SET email = LOWER(email) WHERE email <> LOWER(email);
+
+-- Replace case-sensitive unique constraint with a case-insensitive unique index
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower_key ON users (LOWER(email));
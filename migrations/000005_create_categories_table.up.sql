@@ -0,0 +1,33 @@
+-- Create categories table
+CREATE TABLE IF NOT EXISTS categories (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    parent_id UUID REFERENCES categories(id) ON DELETE RESTRICT,
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (parent_id IS NULL OR parent_id <> id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id);
+
+-- Link products to categories
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category_id UUID REFERENCES categories(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_products_category_id ON products (category_id);
+
+-- Backfill categories from the existing free-text column
+INSERT INTO categories (name, slug)
+SELECT DISTINCT ON (slug) category, slug
+FROM (
+    SELECT category, TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(category), '[^a-z0-9]+', '-', 'g')) AS slug
+    FROM products
+    WHERE category IS NOT NULL AND category <> ''
+) c
+WHERE slug <> ''
+ON CONFLICT (slug) DO NOTHING;
+
+UPDATE products p
+SET category_id = c.id, category = c.slug
+FROM categories c
+WHERE c.slug = TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(p.category), '[^a-z0-9]+', '-', 'g'));
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_products_in_stock_price;
+DROP INDEX IF EXISTS idx_products_category_price;
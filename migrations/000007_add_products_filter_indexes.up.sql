@@ -0,0 +1,5 @@
+-- Support category + price range filtering
+CREATE INDEX IF NOT EXISTS idx_products_category_price ON products (category, price);
+
+-- Support in_stock=true with price range filtering
+CREATE INDEX IF NOT EXISTS idx_products_in_stock_price ON products (price) WHERE stock > 0;
@@ -0,0 +1,24 @@
+-- Attribute definitions per category
+CREATE TABLE IF NOT EXISTS attribute_definitions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    code VARCHAR(100) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('string', 'number', 'boolean')),
+    unit VARCHAR(20),
+    filterable BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (category_id, code)
+);
+
+-- Attribute values per product
+CREATE TABLE IF NOT EXISTS product_attribute_values (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    attribute_id UUID NOT NULL REFERENCES attribute_definitions(id) ON DELETE CASCADE,
+    value TEXT NOT NULL,
+    PRIMARY KEY (product_id, attribute_id)
+);
+
+-- Support ?attr.<code>=<value> filtering
+CREATE INDEX IF NOT EXISTS idx_product_attribute_values_lookup ON product_attribute_values (attribute_id, value);
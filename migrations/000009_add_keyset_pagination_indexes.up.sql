@@ -0,0 +1,4 @@
+-- Keyset pagination orders by (created_at, id) descending
+CREATE INDEX IF NOT EXISTS idx_products_created_at_id ON products (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_orders_created_at_id ON orders (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_orders_customer_created_at_id ON orders (customer_id, created_at DESC, id DESC);
@@ -0,0 +1,10 @@
+-- Catalog snapshots store gzip compressed JSON of product prices and stock
+CREATE TABLE IF NOT EXISTS catalog_snapshots (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trigger VARCHAR(20) NOT NULL,
+    product_count INTEGER NOT NULL DEFAULT 0,
+    data BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_snapshots_created_at ON catalog_snapshots (created_at DESC);
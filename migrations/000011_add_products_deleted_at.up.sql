@@ -0,0 +1,4 @@
+-- Products are archived instead of deleted so order history keeps its references
+ALTER TABLE products ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_products_archived ON products (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS order_tracking_tokens;
+DROP TABLE IF EXISTS order_status_history;
+ALTER TABLE orders DROP COLUMN IF EXISTS tracking_number;
+ALTER TABLE orders DROP COLUMN IF EXISTS carrier;
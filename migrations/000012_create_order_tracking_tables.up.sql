@@ -0,0 +1,27 @@
+-- Shipment details of an order
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS carrier VARCHAR(50);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tracking_number VARCHAR(100);
+
+-- Create order_status_history table
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    status order_status NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_status_history_order_id ON order_status_history (order_id, created_at);
+
+-- Existing orders start their history with the current status
+INSERT INTO order_status_history (order_id, status, created_at)
+SELECT id, status, COALESCE(updated_at, created_at, NOW()) FROM orders;
+
+-- Create order_tracking_tokens table, tokens are revoked rather than deleted
+CREATE TABLE IF NOT EXISTS order_tracking_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_tracking_tokens_order_id ON order_tracking_tokens (order_id) WHERE revoked_at IS NULL;
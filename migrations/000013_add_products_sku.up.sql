@@ -0,0 +1,4 @@
+-- SKU identifies a product for bulk imports; NULLs are allowed for existing products
+ALTER TABLE products ADD COLUMN IF NOT EXISTS sku VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_sku ON products (sku);
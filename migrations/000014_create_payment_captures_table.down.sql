@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_payment_captures_due;
+DROP TABLE IF EXISTS payment_captures;
+ALTER TABLE order_status_history DROP COLUMN IF EXISTS note;
+
+-- Enum values can't be dropped, orders still waiting for capture go back to pending
+UPDATE orders SET status = 'pending' WHERE status = 'pending_capture';
@@ -0,0 +1,18 @@
+-- Orders wait in pending_capture while an asynchronous payment provider settles
+ALTER TYPE order_status ADD VALUE IF NOT EXISTS 'pending_capture' AFTER 'pending';
+
+-- Status history entries can carry a short note, e.g. about a capture retry
+ALTER TABLE order_status_history ADD COLUMN IF NOT EXISTS note TEXT;
+
+-- Create payment_captures table, one capture per order
+CREATE TABLE IF NOT EXISTS payment_captures (
+    order_id UUID PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'captured', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_captures_due ON payment_captures (next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,35 @@
+-- Create reconciliation_runs table, one row per checked settlement report
+CREATE TABLE IF NOT EXISTS reconciliation_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source VARCHAR(20) NOT NULL,
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    reported_count INTEGER NOT NULL DEFAULT 0,
+    recorded_count INTEGER NOT NULL DEFAULT 0,
+    matched_count INTEGER NOT NULL DEFAULT 0,
+    issue_count INTEGER NOT NULL DEFAULT 0,
+    reported_total DECIMAL(14,2) NOT NULL DEFAULT 0,
+    recorded_total DECIMAL(14,2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reconciliation_runs_created_at ON reconciliation_runs (created_at DESC);
+
+-- Create reconciliation_issues table, the admin review queue
+CREATE TABLE IF NOT EXISTS reconciliation_issues (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    run_id UUID NOT NULL REFERENCES reconciliation_runs(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL,
+    kind VARCHAR(30) NOT NULL,
+    reference VARCHAR(100),
+    expected_amount DECIMAL(12,2),
+    reported_amount DECIMAL(12,2),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved')),
+    note TEXT,
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reconciliation_issues_status ON reconciliation_issues (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_reconciliation_issues_run_id ON reconciliation_issues (run_id);
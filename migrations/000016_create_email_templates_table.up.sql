@@ -0,0 +1,33 @@
+-- Create email_templates table; every edit is a new version and at most one
+-- version per key and locale is active
+CREATE TABLE IF NOT EXISTS email_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    key VARCHAR(100) NOT NULL,
+    locale VARCHAR(35) NOT NULL,
+    version INTEGER NOT NULL,
+    subject TEXT NOT NULL,
+    html_body TEXT NOT NULL,
+    text_body TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'active', 'archived')),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    published_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (key, locale, version)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_templates_active ON email_templates (key, locale) WHERE status = 'active';
+
+-- Seed the order confirmation template
+INSERT INTO email_templates (key, locale, version, subject, html_body, text_body, status, published_at) VALUES
+('order-confirmation', 'id', 1,
+ 'Pesanan {{.OrderID}} diterima',
+ '<p>Halo {{.Name}},</p><p>Terima kasih, pesanan <strong>{{.OrderID}}</strong> sebesar {{.Total}} sudah kami terima.</p>',
+ 'Halo {{.Name}}, terima kasih, pesanan {{.OrderID}} sebesar {{.Total}} sudah kami terima.',
+ 'active', NOW()),
+('order-confirmation', 'en', 1,
+ 'Order {{.OrderID}} received',
+ '<p>Hi {{.Name}},</p><p>Thank you, we have received your order <strong>{{.OrderID}}</strong> of {{.Total}}.</p>',
+ 'Hi {{.Name}}, thank you, we have received your order {{.OrderID}} of {{.Total}}.',
+ 'active', NOW())
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+DELETE FROM email_templates WHERE key = 'low-stock-alert';
+DROP INDEX IF EXISTS idx_products_low_stock;
+ALTER TABLE products DROP COLUMN IF EXISTS reorder_threshold;
@@ -0,0 +1,20 @@
+-- Products at or below their reorder threshold are reported as low stock;
+-- NULL disables low-stock alerts for the product
+ALTER TABLE products ADD COLUMN IF NOT EXISTS reorder_threshold INTEGER CHECK (reorder_threshold >= 0);
+
+CREATE INDEX IF NOT EXISTS idx_products_low_stock ON products (stock)
+    WHERE reorder_threshold IS NOT NULL AND stock <= reorder_threshold AND deleted_at IS NULL;
+
+-- Seed the admin low-stock alert template
+INSERT INTO email_templates (key, locale, version, subject, html_body, text_body, status, published_at) VALUES
+('low-stock-alert', 'id', 1,
+ 'Stok menipis: {{.ProductName}}',
+ '<p>Stok <strong>{{.ProductName}}</strong>{{if .SKU}} ({{.SKU}}){{end}} tinggal {{.Stock}}, pada atau di bawah ambang reorder {{.ReorderThreshold}}.</p>',
+ 'Stok {{.ProductName}}{{if .SKU}} ({{.SKU}}){{end}} tinggal {{.Stock}}, pada atau di bawah ambang reorder {{.ReorderThreshold}}.',
+ 'active', NOW()),
+('low-stock-alert', 'en', 1,
+ 'Low stock: {{.ProductName}}',
+ '<p><strong>{{.ProductName}}</strong>{{if .SKU}} ({{.SKU}}){{end}} is down to {{.Stock}}, at or below its reorder threshold of {{.ReorderThreshold}}.</p>',
+ '{{.ProductName}}{{if .SKU}} ({{.SKU}}){{end}} is down to {{.Stock}}, at or below its reorder threshold of {{.ReorderThreshold}}.',
+ 'active', NOW())
+ON CONFLICT DO NOTHING;
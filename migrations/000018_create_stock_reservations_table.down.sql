@@ -0,0 +1,7 @@
+-- Without reservations pending orders hold their stock as a deduction
+UPDATE products SET stock = GREATEST(stock - reserved_stock, 0) WHERE reserved_stock > 0;
+
+DROP INDEX IF EXISTS idx_stock_reservations_expiry;
+DROP INDEX IF EXISTS idx_stock_reservations_order_id;
+DROP TABLE IF EXISTS stock_reservations;
+ALTER TABLE products DROP COLUMN IF EXISTS reserved_stock;
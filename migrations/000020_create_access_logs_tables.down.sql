@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS access_log_exports;
+DROP TABLE IF EXISTS access_logs;
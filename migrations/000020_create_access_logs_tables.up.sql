@@ -0,0 +1,39 @@
+-- Create access_logs table, one row per API request. user_id has no foreign key so
+-- the audit trail outlives deleted users
+CREATE TABLE IF NOT EXISTS access_logs (
+    id BIGSERIAL PRIMARY KEY,
+    at TIMESTAMP WITH TIME ZONE NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    path TEXT NOT NULL,
+    status INTEGER NOT NULL,
+    duration_ms DOUBLE PRECISION NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    ip VARCHAR(64),
+    client_id VARCHAR(100),
+    user_id UUID,
+    user_agent TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_access_logs_at ON access_logs (at);
+CREATE INDEX IF NOT EXISTS idx_access_logs_client_id_at ON access_logs (client_id, at) WHERE client_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_access_logs_user_id_at ON access_logs (user_id, at) WHERE user_id IS NOT NULL;
+
+-- Create access_log_exports table
+CREATE TABLE IF NOT EXISTS access_log_exports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    requested_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    client_id VARCHAR(100),
+    user_id UUID,
+    from_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    to_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'ndjson')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    row_count BIGINT NOT NULL DEFAULT 0,
+    size BIGINT NOT NULL DEFAULT 0,
+    storage_key TEXT,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_access_log_exports_requested_by ON access_log_exports (requested_by, created_at DESC);
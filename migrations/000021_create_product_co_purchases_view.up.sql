@@ -0,0 +1,12 @@
+-- How often two products were bought in the same order, in both directions. Cancelled
+-- orders don't count. Refreshed by a scheduled job, so it lags behind new orders
+CREATE MATERIALIZED VIEW IF NOT EXISTS product_co_purchases AS
+SELECT a.product_id, b.product_id AS related_id, COUNT(DISTINCT a.order_id) AS order_count
+FROM order_items a
+JOIN order_items b ON b.order_id = a.order_id AND b.product_id <> a.product_id
+JOIN orders o ON o.id = a.order_id
+WHERE o.status <> 'cancelled'
+GROUP BY a.product_id, b.product_id;
+
+-- A unique index is required by REFRESH MATERIALIZED VIEW CONCURRENTLY
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_co_purchases_pair ON product_co_purchases (product_id, related_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS auth_state_restores;
+DROP TABLE IF EXISTS auth_state_backups;
@@ -0,0 +1,29 @@
+-- Create auth_state_backups table, the audit trail of Redis session and blacklist backups.
+-- Rows outlive their file: past retention the file is removed and the row marked expired
+CREATE TABLE IF NOT EXISTS auth_state_backups (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trigger VARCHAR(20) NOT NULL CHECK (trigger IN ('manual', 'scheduled')),
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('completed', 'failed', 'expired')),
+    blacklisted INTEGER NOT NULL DEFAULT 0,
+    families INTEGER NOT NULL DEFAULT 0,
+    sessions INTEGER NOT NULL DEFAULT 0,
+    size BIGINT NOT NULL DEFAULT 0,
+    storage_key TEXT,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_state_backups_created_at ON auth_state_backups (created_at DESC);
+
+-- Create auth_state_restores table, every restore of a backup into Redis
+CREATE TABLE IF NOT EXISTS auth_state_restores (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    backup_id UUID NOT NULL REFERENCES auth_state_backups(id) ON DELETE CASCADE,
+    restored_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    restored INTEGER NOT NULL DEFAULT 0,
+    skipped INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_state_restores_created_at ON auth_state_restores (created_at DESC);
@@ -0,0 +1,15 @@
+ALTER TABLE reconciliation_issues
+    ALTER COLUMN expected_amount TYPE DECIMAL(12,2) USING expected_amount / 100.0,
+    ALTER COLUMN reported_amount TYPE DECIMAL(12,2) USING reported_amount / 100.0;
+
+ALTER TABLE reconciliation_runs
+    ALTER COLUMN reported_total TYPE DECIMAL(14,2) USING reported_total / 100.0,
+    ALTER COLUMN recorded_total TYPE DECIMAL(14,2) USING recorded_total / 100.0;
+
+ALTER TABLE order_items
+    ALTER COLUMN unit_price TYPE DECIMAL(12,2) USING unit_price / 100.0,
+    ALTER COLUMN subtotal TYPE DECIMAL(12,2) USING subtotal / 100.0;
+
+ALTER TABLE orders ALTER COLUMN total_amount TYPE DECIMAL(12,2) USING total_amount / 100.0;
+
+ALTER TABLE products ALTER COLUMN price TYPE DECIMAL(12,2) USING price / 100.0;
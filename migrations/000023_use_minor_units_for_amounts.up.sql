@@ -0,0 +1,17 @@
+-- Amounts are stored as whole minor units (cents) instead of decimals, so the application
+-- can sum and compare them without floating point drift
+ALTER TABLE products ALTER COLUMN price TYPE BIGINT USING ROUND(price * 100)::BIGINT;
+
+ALTER TABLE orders ALTER COLUMN total_amount TYPE BIGINT USING ROUND(total_amount * 100)::BIGINT;
+
+ALTER TABLE order_items
+    ALTER COLUMN unit_price TYPE BIGINT USING ROUND(unit_price * 100)::BIGINT,
+    ALTER COLUMN subtotal TYPE BIGINT USING ROUND(subtotal * 100)::BIGINT;
+
+ALTER TABLE reconciliation_runs
+    ALTER COLUMN reported_total TYPE BIGINT USING ROUND(reported_total * 100)::BIGINT,
+    ALTER COLUMN recorded_total TYPE BIGINT USING ROUND(recorded_total * 100)::BIGINT;
+
+ALTER TABLE reconciliation_issues
+    ALTER COLUMN expected_amount TYPE BIGINT USING ROUND(expected_amount * 100)::BIGINT,
+    ALTER COLUMN reported_amount TYPE BIGINT USING ROUND(reported_amount * 100)::BIGINT;
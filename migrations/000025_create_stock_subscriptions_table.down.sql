@@ -0,0 +1,2 @@
+DELETE FROM email_templates WHERE key = 'back-in-stock';
+DROP TABLE IF EXISTS stock_subscriptions;
@@ -0,0 +1,24 @@
+-- Users waiting for an out-of-stock product; a subscription is removed once the user
+-- has been emailed that the product is back in stock
+CREATE TABLE IF NOT EXISTS stock_subscriptions (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_subscriptions_product_id ON stock_subscriptions (product_id, created_at);
+
+-- Seed the back-in-stock notification template
+INSERT INTO email_templates (key, locale, version, subject, html_body, text_body, status, published_at) VALUES
+('back-in-stock', 'id', 1,
+ '{{.ProductName}} tersedia kembali',
+ '<p>Halo {{.Name}},</p><p><strong>{{.ProductName}}</strong> yang kamu tunggu sudah tersedia kembali, stok saat ini {{.Stock}}.</p>',
+ 'Halo {{.Name}}, {{.ProductName}} yang kamu tunggu sudah tersedia kembali, stok saat ini {{.Stock}}.',
+ 'active', NOW()),
+('back-in-stock', 'en', 1,
+ '{{.ProductName}} is back in stock',
+ '<p>Hi {{.Name}},</p><p><strong>{{.ProductName}}</strong> you were waiting for is back in stock, {{.Stock}} available now.</p>',
+ 'Hi {{.Name}}, {{.ProductName}} you were waiting for is back in stock, {{.Stock}} available now.',
+ 'active', NOW())
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,62 @@
+-- Create warehouses table
+CREATE TABLE IF NOT EXISTS warehouses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(50) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- On-hand stock per warehouse, only changed by posting to the stock ledger
+CREATE TABLE IF NOT EXISTS warehouse_stock (
+    warehouse_id UUID NOT NULL REFERENCES warehouses(id),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (warehouse_id, product_id)
+);
+
+-- Every warehouse stock movement; balance is the warehouse stock after the movement
+CREATE TABLE IF NOT EXISTS stock_ledger (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    warehouse_id UUID NOT NULL REFERENCES warehouses(id),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity <> 0),
+    balance INTEGER NOT NULL CHECK (balance >= 0),
+    reason VARCHAR(30) NOT NULL CHECK (reason IN ('adjustment', 'transfer_out', 'transfer_in')),
+    reference_id UUID,
+    note TEXT NOT NULL DEFAULT '',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_ledger_warehouse_product ON stock_ledger (warehouse_id, product_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_stock_ledger_reference_id ON stock_ledger (reference_id) WHERE reference_id IS NOT NULL;
+
+-- Create stock_transfers table. Shipped quantities leave the source warehouse and are
+-- in transit until the transfer is received at the destination
+CREATE TABLE IF NOT EXISTS stock_transfers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_warehouse_id UUID NOT NULL REFERENCES warehouses(id),
+    destination_warehouse_id UUID NOT NULL REFERENCES warehouses(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'in_transit', 'received', 'cancelled')),
+    note TEXT NOT NULL DEFAULT '',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    shipped_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    shipped_at TIMESTAMP WITH TIME ZONE,
+    received_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    received_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (source_warehouse_id <> destination_warehouse_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_transfers_open ON stock_transfers (created_at) WHERE status IN ('draft', 'in_transit');
+
+CREATE TABLE IF NOT EXISTS stock_transfer_items (
+    transfer_id UUID NOT NULL REFERENCES stock_transfers(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (transfer_id, product_id)
+);
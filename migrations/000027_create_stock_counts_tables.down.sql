@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS stock_count_items;
+DROP TABLE IF EXISTS stock_counts;
+
+DELETE FROM stock_ledger WHERE reason = 'stock_count';
+ALTER TABLE stock_ledger DROP CONSTRAINT IF EXISTS stock_ledger_reason_check;
+ALTER TABLE stock_ledger ADD CONSTRAINT stock_ledger_reason_check
+    CHECK (reason IN ('adjustment', 'transfer_out', 'transfer_in'));
@@ -0,0 +1,36 @@
+-- Stock count adjustments are posted to the ledger with their own reason
+ALTER TABLE stock_ledger DROP CONSTRAINT IF EXISTS stock_ledger_reason_check;
+ALTER TABLE stock_ledger ADD CONSTRAINT stock_ledger_reason_check
+    CHECK (reason IN ('adjustment', 'transfer_out', 'transfer_in', 'stock_count'));
+
+-- Create stock_counts table. A count session covers a warehouse, optionally only the
+-- products of one category; expected quantities are the system stock when it was opened
+CREATE TABLE IF NOT EXISTS stock_counts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    warehouse_id UUID NOT NULL REFERENCES warehouses(id),
+    category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'counting' CHECK (status IN ('counting', 'submitted', 'approved', 'rejected')),
+    note TEXT NOT NULL DEFAULT '',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    submitted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    submitted_at TIMESTAMP WITH TIME ZONE,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    review_note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- One count in progress per warehouse, so adjustments of two counts can't overlap
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stock_counts_active_warehouse ON stock_counts (warehouse_id) WHERE status IN ('counting', 'submitted');
+CREATE INDEX IF NOT EXISTS idx_stock_counts_created_at ON stock_counts (created_at DESC);
+
+CREATE TABLE IF NOT EXISTS stock_count_items (
+    count_id UUID NOT NULL REFERENCES stock_counts(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    expected_quantity INTEGER NOT NULL CHECK (expected_quantity >= 0),
+    counted_quantity INTEGER CHECK (counted_quantity >= 0),
+    counted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    counted_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (count_id, product_id)
+);
@@ -0,0 +1,15 @@
+-- Create field_usage table, sampled counts of the fields each client sends in request
+-- bodies ('request') or reports reading from responses ('response'). client_id is ''
+-- for requests without X-Client-ID
+CREATE TABLE IF NOT EXISTS field_usage (
+    route TEXT NOT NULL,
+    direction VARCHAR(10) NOT NULL CHECK (direction IN ('request', 'response')),
+    field TEXT NOT NULL,
+    client_id VARCHAR(100) NOT NULL DEFAULT '',
+    count BIGINT NOT NULL DEFAULT 0,
+    first_seen TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_seen TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (route, direction, field, client_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_field_usage_client_id ON field_usage (client_id);
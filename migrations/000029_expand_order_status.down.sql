@@ -0,0 +1,7 @@
+-- Enum values can't be dropped, orders in the new statuses go back to the closest old one
+UPDATE orders SET status = 'paid' WHERE status = 'processing';
+UPDATE orders SET status = 'completed' WHERE status = 'delivered';
+UPDATE orders SET status = 'cancelled' WHERE status = 'refunded';
+UPDATE order_status_history SET status = 'paid' WHERE status = 'processing';
+UPDATE order_status_history SET status = 'completed' WHERE status = 'delivered';
+UPDATE order_status_history SET status = 'cancelled' WHERE status = 'refunded';
@@ -0,0 +1,5 @@
+-- Extend the order lifecycle: paid orders are processed before shipping, shipped orders
+-- are delivered, and paid orders can be refunded
+ALTER TYPE order_status ADD VALUE IF NOT EXISTS 'processing' AFTER 'paid';
+ALTER TYPE order_status ADD VALUE IF NOT EXISTS 'delivered' AFTER 'shipped';
+ALTER TYPE order_status ADD VALUE IF NOT EXISTS 'refunded' AFTER 'cancelled';
@@ -0,0 +1,27 @@
+-- Create payments table, the payment intents created at a payment provider for orders.
+-- An order gets a new payment when the previous one failed
+CREATE TABLE IF NOT EXISTS payments (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    provider VARCHAR(32) NOT NULL,
+    provider_ref VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed', 'cancelled')),
+    amount BIGINT NOT NULL CHECK (amount >= 0),
+    currency VARCHAR(3) NOT NULL,
+    client_secret TEXT NOT NULL DEFAULT '',
+    failure_reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (provider, provider_ref)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payments_order_id ON payments (order_id, created_at DESC);
+
+-- Create payment_events table, the provider webhook events already applied so a
+-- redelivered event is ignored
+CREATE TABLE IF NOT EXISTS payment_events (
+    provider VARCHAR(32) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    received_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (provider, event_id)
+);
@@ -0,0 +1,8 @@
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS shipping_cost,
+    DROP COLUMN IF EXISTS shipping_method,
+    DROP COLUMN IF EXISTS shipping_address;
+
+ALTER TABLE products DROP COLUMN IF EXISTS weight_grams;
+
+DROP TABLE IF EXISTS addresses;
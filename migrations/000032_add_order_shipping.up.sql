@@ -0,0 +1,30 @@
+-- Create addresses table, the address book of users orders are shipped to
+CREATE TABLE IF NOT EXISTS addresses (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    label VARCHAR(50) NOT NULL DEFAULT '',
+    recipient_name VARCHAR(100) NOT NULL,
+    phone VARCHAR(30) NOT NULL,
+    street VARCHAR(255) NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    province VARCHAR(100) NOT NULL DEFAULT '',
+    postal_code VARCHAR(20) NOT NULL,
+    country CHAR(2) NOT NULL DEFAULT 'ID',
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_addresses_user_id ON addresses (user_id, created_at);
+-- a user has at most one default address
+CREATE UNIQUE INDEX IF NOT EXISTS idx_addresses_user_default ON addresses (user_id) WHERE is_default;
+
+-- Shipping weight of one unit of a product
+ALTER TABLE products ADD COLUMN IF NOT EXISTS weight_grams INTEGER NOT NULL DEFAULT 0 CHECK (weight_grams >= 0);
+
+-- Orders keep a copy of the address they ship to, the shipping method and its cost,
+-- which is part of total_amount
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS shipping_address JSONB,
+    ADD COLUMN IF NOT EXISTS shipping_method VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS shipping_cost BIGINT NOT NULL DEFAULT 0 CHECK (shipping_cost >= 0);
@@ -0,0 +1,14 @@
+DROP MATERIALIZED VIEW IF EXISTS product_co_purchases;
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS product_co_purchases AS
+SELECT a.product_id, b.product_id AS related_id, COUNT(DISTINCT a.order_id) AS order_count
+FROM order_items a
+JOIN order_items b ON b.order_id = a.order_id AND b.product_id <> a.product_id
+JOIN orders o ON o.id = a.order_id
+WHERE o.status <> 'cancelled'
+GROUP BY a.product_id, b.product_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_co_purchases_pair ON product_co_purchases (product_id, related_id);
+
+DROP TABLE IF EXISTS consent_records;
+DROP TABLE IF EXISTS user_consents;
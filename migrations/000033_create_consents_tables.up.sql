@@ -0,0 +1,38 @@
+-- Current consent of a user per purpose; purposes without a row use their default
+CREATE TABLE IF NOT EXISTS user_consents (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(30) NOT NULL,
+    granted BOOLEAN NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, purpose)
+);
+
+-- Every consent choice a user made, with where it was made from, kept for compliance
+CREATE TABLE IF NOT EXISTS consent_records (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(30) NOT NULL,
+    granted BOOLEAN NOT NULL,
+    ip VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_consent_records_user_id ON consent_records (user_id, created_at DESC);
+
+-- Customers who opted out of analytics are left out of the co-purchase statistics
+DROP MATERIALIZED VIEW IF EXISTS product_co_purchases;
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS product_co_purchases AS
+SELECT a.product_id, b.product_id AS related_id, COUNT(DISTINCT a.order_id) AS order_count
+FROM order_items a
+JOIN order_items b ON b.order_id = a.order_id AND b.product_id <> a.product_id
+JOIN orders o ON o.id = a.order_id
+WHERE o.status <> 'cancelled'
+  AND NOT EXISTS (
+      SELECT 1 FROM user_consents c
+      WHERE c.user_id = o.customer_id AND c.purpose = 'analytics' AND NOT c.granted
+  )
+GROUP BY a.product_id, b.product_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_co_purchases_pair ON product_co_purchases (product_id, related_id);
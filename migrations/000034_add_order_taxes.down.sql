@@ -0,0 +1,6 @@
+ALTER TABLE order_items DROP COLUMN IF EXISTS tax_amount;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS tax_lines,
+    DROP COLUMN IF EXISTS tax_amount,
+    DROP COLUMN IF EXISTS subtotal;
@@ -0,0 +1,12 @@
+-- Orders keep their subtotal, the tax charged on it and a line per tax rate applied;
+-- total_amount is subtotal + tax_amount + shipping_cost
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS subtotal BIGINT NOT NULL DEFAULT 0 CHECK (subtotal >= 0),
+    ADD COLUMN IF NOT EXISTS tax_amount BIGINT NOT NULL DEFAULT 0 CHECK (tax_amount >= 0),
+    ADD COLUMN IF NOT EXISTS tax_lines JSONB NOT NULL DEFAULT '[]';
+
+-- Orders placed before taxes were charged
+UPDATE orders SET subtotal = total_amount - shipping_cost WHERE subtotal = 0;
+
+-- Tax charged on each item, so partial refunds return its share
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_amount BIGINT NOT NULL DEFAULT 0 CHECK (tax_amount >= 0);
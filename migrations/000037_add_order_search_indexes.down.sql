@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_email_lower_pattern;
+DROP INDEX IF EXISTS idx_orders_total_amount;
+DROP INDEX IF EXISTS idx_orders_status_created_at;
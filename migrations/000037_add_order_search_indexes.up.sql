@@ -0,0 +1,4 @@
+-- Admin order search: status with a date range, total ranges and customer email prefixes
+CREATE INDEX IF NOT EXISTS idx_orders_status_created_at ON orders (status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_orders_total_amount ON orders (total_amount);
+CREATE INDEX IF NOT EXISTS idx_users_email_lower_pattern ON users (LOWER(email) text_pattern_ops);
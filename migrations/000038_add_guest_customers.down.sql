@@ -0,0 +1,8 @@
+-- Guests and their unclaimed orders are removed, their emails may clash with accounts
+DELETE FROM users WHERE is_guest;
+
+DROP INDEX IF EXISTS users_guest_email_lower_key;
+DROP INDEX IF EXISTS users_email_lower_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower_key ON users (LOWER(email));
+
+ALTER TABLE users DROP COLUMN IF EXISTS is_guest;
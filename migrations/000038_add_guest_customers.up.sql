@@ -0,0 +1,7 @@
+-- Guests are customers who ordered without an account. An email is unique among the
+-- registered users and among the guests, so it can be registered after ordering as guest
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_guest BOOLEAN NOT NULL DEFAULT FALSE;
+
+DROP INDEX IF EXISTS users_email_lower_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower_key ON users (LOWER(email)) WHERE NOT is_guest;
+CREATE UNIQUE INDEX IF NOT EXISTS users_guest_email_lower_key ON users (LOWER(email)) WHERE is_guest;
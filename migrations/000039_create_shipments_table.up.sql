@@ -0,0 +1,22 @@
+-- Parcels of an order handed to a carrier, an order may ship in several. The carrier and
+-- tracking number of orders stay as the latest shipment's
+CREATE TABLE IF NOT EXISTS shipments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    carrier VARCHAR(50) NOT NULL,
+    tracking_number VARCHAR(100) NOT NULL,
+    shipped_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipments_order_id ON shipments (order_id, shipped_at);
+
+-- Orders shipped before shipments were recorded keep their tracking number, shipped when
+-- they first became shipped
+INSERT INTO shipments (order_id, carrier, tracking_number, shipped_at)
+SELECT o.id, COALESCE(o.carrier, ''), o.tracking_number,
+       COALESCE((SELECT MIN(h.created_at) FROM order_status_history h WHERE h.order_id = o.id AND h.status = 'shipped'),
+                o.updated_at, o.created_at, NOW())
+FROM orders o
+WHERE COALESCE(o.tracking_number, '') <> '';
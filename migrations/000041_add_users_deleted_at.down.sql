@@ -0,0 +1,14 @@
+-- Deleted users are removed, their usernames and emails may clash with live users
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_users_deleted;
+
+DROP INDEX IF EXISTS users_guest_email_lower_key;
+DROP INDEX IF EXISTS users_email_lower_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower_key ON users (LOWER(email)) WHERE NOT is_guest;
+CREATE UNIQUE INDEX IF NOT EXISTS users_guest_email_lower_key ON users (LOWER(email)) WHERE is_guest;
+
+DROP INDEX IF EXISTS users_username_key;
+ALTER TABLE users ADD CONSTRAINT users_username_key UNIQUE (username);
+
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
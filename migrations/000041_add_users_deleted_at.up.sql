@@ -0,0 +1,13 @@
+-- Users are soft deleted so their orders and the records they signed stay. Usernames and
+-- emails are unique among the users that aren't deleted, a deleted user's can be taken
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_username_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_key ON users (username) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS users_email_lower_key;
+DROP INDEX IF EXISTS users_guest_email_lower_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower_key ON users (LOWER(email)) WHERE NOT is_guest AND deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS users_guest_email_lower_key ON users (LOWER(email)) WHERE is_guest AND deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_deleted ON users (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_products_active_category;
+DROP INDEX IF EXISTS idx_products_active_created;
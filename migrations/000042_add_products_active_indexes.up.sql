@@ -0,0 +1,4 @@
+-- Listings exclude archived products by default, index the live ones for the default
+-- order and the category filter
+CREATE INDEX IF NOT EXISTS idx_products_active_created ON products (created_at DESC, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_products_active_category ON products (category_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,4 @@
+-- Every update raises the version, an update carrying an older one is refused so
+-- concurrent edits don't overwrite each other
+ALTER TABLE products ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,283 @@
+package i18n
+
+// english translates the messages of the API to English
+var english = map[string]string{
+	// errors
+	"Validasi gagal":                                 "Validation failed",
+	"Autentikasi diperlukan":                         "Authentication required",
+	"Akses ditolak":                                  "Access denied",
+	"Akses dari alamat IP ini tidak diizinkan":       "Access from this IP address is not allowed",
+	"Terjadi kesalahan internal":                     "An internal error occurred",
+	"Endpoint tidak ditemukan":                       "Endpoint not found",
+	"%s tidak ditemukan":                             "%s not found",
+	"Method tidak diizinkan untuk endpoint ini":      "Method not allowed for this endpoint",
+	"Content-Type harus application/json":            "Content-Type must be application/json",
+	"Body request terlalu besar":                     "Request body too large",
+	"Permintaan melebihi batas waktu, coba lagi":     "Request timed out, try again",
+	"Query database melebihi batas waktu, coba lagi": "Database query timed out, try again",
+	"Permintaan dibatalkan":                          "Request canceled",
+	"Terlalu banyak permintaan, coba lagi nanti":     "Too many requests, try again later",
+	"Server sedang sibuk, coba lagi sebentar lagi":   "Server is busy, try again shortly",
+	"Layanan sedang tidak tersedia, coba lagi nanti": "Service unavailable, try again later",
+
+	// auth and users
+	"Email atau password salah":                               "Invalid email or password",
+	"Token tidak valid atau sudah kadaluarsa":                 "Invalid or expired token",
+	"Token sudah dicabut":                                     "Token has been revoked",
+	"Refresh token sudah digunakan, semua sesi telah dicabut": "Refresh token already used, all sessions have been revoked",
+	"Jumlah sesi aktif sudah mencapai batas, keluar dari perangkat lain terlebih dahulu": "Active session limit reached, sign out from another device first",
+	"User tidak ditemukan":                                 "User not found",
+	"User sudah terdaftar":                                 "User already registered",
+	"Email sudah terdaftar":                                "Email already registered",
+	"Username sudah digunakan":                             "Username already taken",
+	"User tidak aktif":                                     "User is inactive",
+	"Password lama tidak sesuai":                           "Old password does not match",
+	"Password terlalu lemah":                               "Password too weak",
+	"Role tidak ditemukan":                                 "Role not found",
+	"ID user tidak valid":                                  "Invalid user ID",
+	"Backup auth state tidak ditemukan":                    "Auth state backup not found",
+	"Backup auth state tidak dapat dipulihkan":             "Auth state backup cannot be restored",
+	"issued_before tidak boleh di masa depan":              "issued_before cannot be in the future",
+	"issued_before wajib diisi untuk mencabut semua token": "issued_before is required to revoke all tokens",
+	"role dan user_ids tidak boleh diisi bersamaan":        "role and user_ids cannot both be set",
+
+	// request signing and idempotency
+	"Header Idempotency-Key harus 1 sampai 255 karakter":                              "Idempotency-Key header must be 1 to 255 characters",
+	"Idempotency-Key sudah dipakai untuk request yang berbeda":                        "Idempotency-Key already used for a different request",
+	"Request dengan Idempotency-Key ini masih diproses":                               "A request with this Idempotency-Key is still being processed",
+	"Header X-Request-Timestamp, X-Request-Nonce dan X-Request-Signature wajib diisi": "X-Request-Timestamp, X-Request-Nonce and X-Request-Signature headers are required",
+	"Request dengan nonce ini sudah pernah diterima":                                  "A request with this nonce was already received",
+	"Tanda tangan request tidak valid atau kedaluwarsa":                               "Request signature invalid or expired",
+
+	// request bodies and parameters
+	"Body request wajib diisi":                        "Request body is required",
+	"Body request tidak dapat dibaca":                 "Request body could not be read",
+	"Body request terlalu besar atau tidak terbaca":   "Request body too large or unreadable",
+	"Body request hanya boleh berisi satu nilai JSON": "Request body must contain a single JSON value",
+	"Format JSON tidak valid":                         "Invalid JSON",
+	"JSON tidak lengkap":                              "Incomplete JSON",
+	"Field tidak dikenal":                             "Unknown field",
+	"Harus berupa string":                             "Must be a string",
+	"Harus berupa boolean":                            "Must be a boolean",
+	"Harus berupa bilangan bulat":                     "Must be an integer",
+	"Harus berupa angka":                              "Must be a number",
+	"Harus berupa array":                              "Must be an array",
+	"Harus berupa objek":                              "Must be an object",
+	"Format multipart tidak valid":                    "Invalid multipart form",
+	"ID tidak valid":                                  "Invalid ID",
+	"ID tidak valid: %s":                              "Invalid ID: %s",
+	"ID tidak ditemukan":                              "ID not found",
+	"Parameter from tidak valid":                      "Invalid from parameter",
+	"Parameter to tidak valid":                        "Invalid to parameter",
+	"Parameter ids wajib diisi, maksimal %d order":    "The ids parameter is required, at most %d orders",
+	"from harus berformat RFC3339":                    "from must be in RFC3339 format",
+	"to harus berformat RFC3339":                      "to must be in RFC3339 format",
+	"since harus berformat RFC3339":                   "since must be in RFC3339 format",
+	"to harus setelah from":                           "to must be after from",
+	"window tidak valid":                              "Invalid window",
+	"window maksimal 26":                              "window is at most 26",
+	"weeks maksimal 26":                               "weeks is at most 26",
+	"cursor tidak valid":                              "Invalid cursor",
+	"sort tidak bisa dipakai bersama cursor":          "sort cannot be used together with cursor",
+	"status tidak valid":                              "Invalid status",
+	"rentang waktu maksimal %d hari":                  "The time range is at most %d days",
+	"rentang waktu export maksimal %d hari":           "The export time range is at most %d days",
+
+	// validation
+	"%s wajib diisi":                           "%s is required",
+	"Format email tidak valid":                 "Invalid email format",
+	"%s minimal %s":                            "%s must be at least %s",
+	"%s minimal %s karakter":                   "%s must be at least %s characters",
+	"%s minimal %s item":                       "%s must have at least %s items",
+	"%s maksimal %s":                           "%s must be at most %s",
+	"%s maksimal %s karakter":                  "%s must be at most %s characters",
+	"%s maksimal %s item":                      "%s must have at most %s items",
+	"%s harus lebih besar dari %s":             "%s must be greater than %s",
+	"%s harus lebih besar atau sama dengan %s": "%s must be greater than or equal to %s",
+	"%s harus lebih kecil dari %s":             "%s must be less than %s",
+	"%s harus lebih kecil atau sama dengan %s": "%s must be less than or equal to %s",
+	"%s harus salah satu dari: %s":             "%s must be one of: %s",
+	"Password harus minimal 8 karakter dengan huruf besar, huruf kecil, dan angka": "Password must be at least 8 characters with uppercase and lowercase letters and a number",
+	"Username hanya boleh berisi huruf, angka, dan underscore":                     "Username may only contain letters, numbers and underscores",
+	"%s hanya boleh berisi huruf kecil, angka, dan tanda hubung":                   "%s may only contain lowercase letters, numbers and hyphens",
+	"%s harus berupa kode bahasa BCP 47, misalnya id atau en-US":                   "%s must be a BCP 47 language tag, such as id or en-US",
+	"%s harus berformat UUID yang valid":                                           "%s must be a valid UUID",
+	"%s tidak valid":                                                               "%s is invalid",
+
+	// query parameters
+	"%s harus berformat RFC3339 atau YYYY-MM-DD": "%s must be in RFC3339 or YYYY-MM-DD format",
+	"%s harus berupa true atau false":            "%s must be true or false",
+	"%s harus berupa bilangan bulat":             "%s must be an integer",
+	"%s harus berupa bilangan bulat positif":     "%s must be a positive integer",
+	"%s harus berupa angka":                      "%s must be a number",
+	"%s berisi field kosong":                     "%s contains an empty field",
+	"%s berisi %s lebih dari sekali":             "%s contains %s more than once",
+	"%s hanya bisa berisi %s":                    "%s may only contain %s",
+
+	// catalog
+	"Produk tidak ditemukan":                                               "Product not found",
+	"produk tidak ditemukan":                                               "product not found",
+	"ID produk tidak valid":                                                "Invalid product ID",
+	"product_id tidak valid":                                               "Invalid product_id",
+	"SKU sudah digunakan produk lain":                                      "SKU already used by another product",
+	"SKU sudah dipakai pada baris %d":                                      "SKU already used on row %d",
+	"Harga produk berubah selama pembaruan, silakan coba lagi":             "Product price changed during the update, please try again",
+	"Data sudah diubah oleh permintaan lain, muat ulang lalu coba lagi":    "The data was changed by another request, reload it and try again",
+	"Produk masih dipakai order aktif":                                     "Product is still used by active orders",
+	"%d order %s berisi produk ini":                                        "%d %s orders contain this product",
+	"%d reservasi aktif menahan %d stok":                                   "%d active reservations hold %d in stock",
+	"Policy hapus produk tidak valid, gunakan block, archive atau cleanup": "Invalid product delete policy, use block, archive or cleanup",
+	"Snapshot katalog tidak ditemukan":                                     "Catalog snapshot not found",
+	"ID snapshot tidak valid":                                              "Invalid snapshot ID",
+	"Gambar produk tidak ditemukan":                                        "Product image not found",
+	"ID gambar tidak valid":                                                "Invalid image ID",
+	"File image wajib diisi":                                               "Image file is required",
+	"File gambar tidak valid, gunakan JPEG, PNG, WEBP atau GIF":            "Invalid image file, use JPEG, PNG, WEBP or GIF",
+	"Ukuran file terlalu besar":                                            "File too large",
+	"Kategori tidak ditemukan":                                             "Category not found",
+	"kategori tidak ditemukan":                                             "category not found",
+	"ID kategori tidak valid":                                              "Invalid category ID",
+	"category_id tidak valid":                                              "Invalid category_id",
+	"Slug kategori sudah digunakan":                                        "Category slug already in use",
+	"Kategori masih memiliki sub-kategori":                                 "Category still has subcategories",
+	"Parent kategori tidak valid":                                          "Invalid parent category",
+	"slug tidak dapat dibuat dari nama kategori":                           "A slug cannot be made from the category name",
+	"Atribut tidak ditemukan":                                              "Attribute not found",
+	"ID atribut tidak valid":                                               "Invalid attribute ID",
+	"Kode atribut sudah digunakan pada kategori ini":                       "Attribute code already used in this category",
+	"atribut tidak tersedia untuk kategori ini":                            "Attribute not available for this category",
+	"atribut hanya dapat diisi untuk produk dengan kategori terdaftar":     "Attributes can only be set for products with a registered category",
+	"nilai harus bertipe %s":                                               "Value must be of type %s",
+	"Tag tidak ditemukan":                                                  "Tag not found",
+	"tag %s tidak ditemukan":                                               "tag %s not found",
+	"ID tag tidak valid":                                                   "Invalid tag ID",
+	"Slug tag sudah digunakan":                                             "Tag slug already in use",
+	"slug tidak dapat dibuat dari nama tag":                                "A slug cannot be made from the tag name",
+	"price harus berupa angka dengan maksimal 2 desimal":                   "price must be a number with at most 2 decimals",
+	"stock harus berupa bilangan bulat":                                    "stock must be an integer",
+	"max_price harus lebih besar atau sama dengan min_price":               "max_price must be greater than or equal to min_price",
+	"harga produk %s menjadi negatif":                                      "The price of product %s would become negative",
+	"value harus berupa bilangan bulat dalam satuan terkecil mata uang":    "value must be an integer in the smallest currency unit",
+
+	// product import
+	"File import wajib diisi":             "Import file is required",
+	"format harus csv atau json":          "format must be csv or json",
+	"format produk tidak valid":           "Invalid product format",
+	"baris CSV tidak valid":               "Invalid CSV row",
+	"header CSV tidak ditemukan":          "CSV header not found",
+	"kolom %s wajib ada pada header CSV":  "Column %s is required in the CSV header",
+	"file JSON harus berupa array produk": "The JSON file must be an array of products",
+	"file tidak berisi produk":            "The file contains no products",
+	"maksimal %d produk per import":       "At most %d products per import",
+
+	// inventory
+	"Stok tidak mencukupi":                                                   "Insufficient stock",
+	"Stok gudang tidak mencukupi":                                            "Insufficient warehouse stock",
+	"stok di gudang asal hanya %d":                                           "The source warehouse only has %d in stock",
+	"Gudang tidak ditemukan":                                                 "Warehouse not found",
+	"ID gudang tidak valid":                                                  "Invalid warehouse ID",
+	"Kode gudang sudah digunakan":                                            "Warehouse code already in use",
+	"gudang tujuan harus berbeda dari gudang asal":                           "The destination warehouse must differ from the source warehouse",
+	"produk sudah ada di item lain":                                          "product is already in another item",
+	"Transfer stok tidak ditemukan":                                          "Stock transfer not found",
+	"ID transfer tidak valid":                                                "Invalid transfer ID",
+	"Status transfer stok tidak memungkinkan aksi ini":                       "The stock transfer status does not allow this action",
+	"status harus open, draft, in_transit, received, cancelled atau all":     "status must be open, draft, in_transit, received, cancelled or all",
+	"Stock opname tidak ditemukan":                                           "Stock count not found",
+	"ID stock opname tidak valid":                                            "Invalid stock count ID",
+	"Status stock opname tidak memungkinkan aksi ini":                        "The stock count status does not allow this action",
+	"Masih ada stock opname yang berjalan di gudang ini":                     "A stock count is still running in this warehouse",
+	"Masih ada produk yang belum dihitung":                                   "Some products have not been counted yet",
+	"produk bukan bagian dari kategori yang dihitung":                        "product is not in the counted category",
+	"status harus counting, submitted, approved atau rejected":               "status must be counting, submitted, approved or rejected",
+	"Langganan stok tidak ditemukan":                                         "Stock subscription not found",
+	"Produk masih tersedia, langganan hanya untuk produk yang stoknya habis": "Product is still available, subscriptions are only for out of stock products",
+	"product_id wajib diisi, maksimal %d produk":                             "product_id is required, at most %d products",
+
+	// orders
+	"Order tidak ditemukan":                              "Order not found",
+	"ID order tidak valid":                               "Invalid order ID",
+	"Item order tidak ditemukan":                         "Order item not found",
+	"Transisi status tidak valid":                        "Invalid status transition",
+	"Order belum dibuat pada waktu tersebut":             "The order did not exist yet at that time",
+	"Alamat pengiriman wajib diisi":                      "Shipping address is required",
+	"Alamat tidak ditemukan":                             "Address not found",
+	"ID alamat tidak valid":                              "Invalid address ID",
+	"customer_id harus sama dengan user yang login":      "customer_id must match the signed in user",
+	"Email akun harus sama dengan email pesanan guest":   "The account email must match the guest order email",
+	"Pesanan ini bukan pesanan guest atau sudah diklaim": "This order is not a guest order or was already claimed",
+	"Link tracking tidak valid atau sudah dicabut":       "Tracking link invalid or revoked",
+	"quantity maksimal %d per produk":                    "quantity is at most %d per product",
+	"produk ini ada di beberapa item dengan total quantity %d, maksimal %d per produk": "this product is in several items with a total quantity of %d, at most %d per product",
+	"pesanan maksimal berisi %d produk berbeda":                                        "an order contains at most %d different products",
+	"max_total tidak boleh kurang dari min_total":                                      "max_total cannot be less than min_total",
+	"Metode pengiriman tidak tersedia":                                                 "Shipping method not available",
+	"Pengiriman hanya bisa dicatat untuk pesanan paid, processing atau shipped":        "Shipments can only be recorded for paid, processing or shipped orders",
+	"carrier dan tracking_number wajib diisi untuk mencatat pengiriman":                "carrier and tracking_number are required to record a shipment",
+	"shipped_at tidak boleh di masa depan":                                             "shipped_at cannot be in the future",
+	"shipped_at tidak boleh sebelum pesanan dibuat":                                    "shipped_at cannot be before the order was created",
+
+	// payments and refunds
+	"Pembayaran tidak ditemukan":                                      "Payment not found",
+	"Capture pembayaran tidak ditemukan":                              "Payment capture not found",
+	"Order tidak sedang menunggu pembayaran":                          "Order is not awaiting payment",
+	"Jumlah pembayaran tidak sesuai dengan total order":               "Payment amount does not match the order total",
+	"Penyedia pembayaran belum dikonfigurasi":                         "Payment provider is not configured",
+	"Penyedia pembayaran gagal memproses permintaan, coba lagi nanti": "Payment provider failed to process the request, try again later",
+	"Tanda tangan webhook tidak valid":                                "Invalid webhook signature",
+	"Payload webhook terlalu besar":                                   "Webhook payload too large",
+	"Payload webhook tidak dapat dibaca":                              "Webhook payload could not be read",
+	"Order belum dibayar atau sudah direfund":                         "Order is not paid or already refunded",
+	"Refund melebihi jumlah atau item order yang belum direfund":      "Refund exceeds the amount or items of the order not yet refunded",
+	"jumlah refund harus lebih dari 0":                                "The refund amount must be greater than 0",
+
+	// reconciliation
+	"File laporan wajib diisi":                                 "Report file is required",
+	"period_start tidak valid":                                 "Invalid period_start",
+	"period_end tidak valid":                                   "Invalid period_end",
+	"period_end harus setelah period_start":                    "period_end must be after period_start",
+	"order_id tidak valid":                                     "Invalid order_id",
+	"amount harus berupa angka dengan maksimal 2 desimal":      "amount must be a number with at most 2 decimals",
+	"settled_at harus berformat RFC3339":                       "settled_at must be in RFC3339 format",
+	"maksimal %d baris per laporan":                            "At most %d rows per report",
+	"Selisih rekonsiliasi tidak ditemukan atau sudah ditinjau": "Reconciliation discrepancy not found or already reviewed",
+	"status harus open atau resolved":                          "status must be open or resolved",
+
+	// webhooks, jobs and API clients
+	"Webhook tidak ditemukan":                      "Webhook not found",
+	"ID webhook tidak valid":                       "Invalid webhook ID",
+	"event %s tidak dikenal":                       "Unknown event %s",
+	"url harus berupa URL http atau https absolut": "url must be an absolute http or https URL",
+	"Job tidak ditemukan":                          "Job not found",
+	"ID job tidak valid":                           "Invalid job ID",
+	"Job tidak dapat diubah pada status saat ini":  "The job cannot be changed in its current status",
+	"API client tidak ditemukan":                   "API client not found",
+	"ID API client tidak valid":                    "Invalid API client ID",
+	"Batas jumlah API client tercapai":             "API client limit reached",
+	"scope %s tidak dikenal":                       "Unknown scope %s",
+	"Export access log tidak ditemukan":            "Access log export not found",
+	"Export access log belum selesai":              "Access log export not finished yet",
+	"ID export tidak valid":                        "Invalid export ID",
+
+	// OAuth
+	"client_id tidak dikenal":                       "Unknown client_id",
+	"scope wajib diisi":                             "scope is required",
+	"scope %s tidak diizinkan untuk client ini":     "scope %s is not allowed for this client",
+	"redirect_uri tidak terdaftar untuk client ini": "redirect_uri is not registered for this client",
+	"redirect URI harus berupa URL https absolut tanpa fragment, http hanya untuk localhost": "Redirect URI must be an absolute https URL without a fragment, http only for localhost",
+	"code_challenge harus berupa SHA-256 base64url tanpa padding":                            "code_challenge must be a SHA-256 in base64url without padding",
+
+	// notifications, consents and email templates
+	"Preferensi notifikasi belum pernah dipilih":                                 "Notification preferences were never chosen",
+	"jenis notifikasi tidak dikenal":                                             "Unknown notification type",
+	"channel notifikasi tidak tersedia":                                          "Notification channel not available",
+	"notifikasi ini tidak dapat dimatikan":                                       "This notification cannot be turned off",
+	"Persetujuan belum pernah dipilih":                                           "Consents were never chosen",
+	"minimal satu persetujuan harus diisi":                                       "At least one consent is required",
+	"Persetujuan email pemasaran diperlukan, aktifkan di pengaturan persetujuan": "Marketing email consent required, enable it in the consent settings",
+	"Template email tidak ditemukan":                                             "Email template not found",
+	"ID template tidak valid":                                                    "Invalid template ID",
+	"Template gagal dirender: %s":                                                "Template failed to render: %s",
+	"Pengiriman email belum dikonfigurasi":                                       "Email delivery is not configured",
+	"ID backup tidak valid":                                                      "Invalid backup ID",
+}
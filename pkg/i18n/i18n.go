@@ -0,0 +1,80 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Languages messages are available in
+const (
+	Indonesian = "id"
+	English    = "en"
+
+	// Default is the language messages are written in the code, and the language of
+	// clients accepting none of the supported ones
+	Default = Indonesian
+)
+
+// catalogs translate messages, keyed by their text in the default language. A message
+// missing from a catalog stays in the default language
+var catalogs = map[string]map[string]string{
+	English: english,
+}
+
+// Supported reports whether messages are available in lang
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok || lang == Default
+}
+
+// T translates message to lang and fills in its verbs with args, as fmt.Sprintf does.
+// Without args message is returned as it is, it may contain a literal %
+func T(lang, message string, args ...any) string {
+	if translated, ok := catalogs[lang][message]; ok {
+		message = translated
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Negotiate returns the supported language the Accept-Language header prefers, matching
+// a regional tag such as en-US by its language. Default when the client accepts none
+func Negotiate(acceptLanguage string) string {
+	type option struct {
+		lang string
+		q    float64
+	}
+	var options []option
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		lang, _, _ := strings.Cut(tag, "-")
+		if lang == "*" {
+			lang = Default
+		}
+		if q > 0 && Supported(lang) {
+			options = append(options, option{lang: lang, q: q})
+		}
+	}
+	if len(options) == 0 {
+		return Default
+	}
+	// stable, so the first listed of equal quality wins
+	sort.SliceStable(options, func(i, j int) bool { return options[i].q > options[j].q })
+	return options[0].lang
+}
@@ -3,8 +3,11 @@ package jwt
 
 import (
 	"context"
+	"crypto/hmac"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/hex"
 	"encoding/pem"
 	"errors"
 	"fmt"
@@ -12,6 +15,9 @@ import (
 	"postgresDB/config"
 	"postgresDB/internal/domain/entities"
 	"postgresDB/internal/repository"
+	"postgresDB/pkg/logger"
+	"postgresDB/pkg/metrics"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -24,14 +30,70 @@ const (
 	TokenTypeAccess = "access"
 	// TokenTypeRefresh represents refresh token type
 	TokenTypeRefresh = "refresh"
+	// TokenTypeOAuthAccess represents access tokens issued to an OAuth client, they are
+	// only accepted by the OAuth endpoints
+	TokenTypeOAuthAccess = "oauth_access"
+	// TokenTypeOAuthRefresh represents refresh tokens issued to an OAuth client
+	TokenTypeOAuthRefresh = "oauth_refresh"
 )
 
+// Redis failure policies for access token validation
+const (
+	// PolicyFailClosed rejects access tokens while the token store is unavailable
+	PolicyFailClosed = "fail_closed"
+	// PolicyFailOpen accepts validly signed access tokens without the blacklist check
+	PolicyFailOpen = "fail_open"
+)
+
+// Revocation scopes, a token is checked against the cutoff of each scope it belongs to
+const (
+	// ScopeAll covers every token
+	ScopeAll = "all"
+)
+
+// RoleScope returns the revocation scope of all tokens issued for role
+func RoleScope(role entities.Role) string {
+	return "role:" + string(role)
+}
+
+// UserScope returns the revocation scope of all tokens issued for a user
+func UserScope(userID uuid.UUID) string {
+	return "user:" + userID.String()
+}
+
+// FamilyScope returns the revocation scope of all tokens of a token family
+func FamilyScope(family string) string {
+	return "family:" + family
+}
+
+// ErrTokenStoreUnavailable is returned when the token store (Redis) cannot be reached
+var ErrTokenStoreUnavailable = errors.New("token store unavailable")
+
+// ErrSessionLimitReached is returned at login when the user already has the maximum
+// number of sessions and the session limit policy is reject
+var ErrSessionLimitReached = errors.New("session limit reached")
+
+var (
+	authDegradedAccepted = metrics.NewCounter("auth_degraded_accepted_total", "Access tokens accepted without blacklist check while Redis is unavailable")
+	authDegradedRejected = metrics.NewCounter("auth_degraded_rejected_total", "Access tokens rejected because Redis is unavailable")
+	authSessionsEvicted  = metrics.NewCounter("auth_sessions_evicted_total", "Sessions ended at login because the user reached the session limit")
+	authSessionsRejected = metrics.NewCounter("auth_sessions_rejected_total", "Logins rejected because the user reached the session limit")
+	authValidations      = metrics.NewCounterVec("auth_token_validations_total",
+		"Access token checks by result (valid, expired, invalid, revoked, unavailable)", "result")
+)
+
+// errTokenRevoked is returned for a blacklisted token or one issued before a revocation
+var errTokenRevoked = errors.New("token has been revoked")
+
 // Claims custom
 type Claims struct {
 	UserID      uuid.UUID     `json:"user_id"`
 	Role        entities.Role `json:"role"`
 	TokenType   string        `json:"token_type"`
 	TokenFamily string        `json:"token_family,omitempty"`
+	// ClientID and Scope are set on tokens issued to an OAuth client, Scope is space separated
+	ClientID string `json:"client_id,omitempty"`
+	Scope    string `json:"scope,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -43,17 +105,27 @@ type TokenPair struct {
 	RefreshJTI   string
 	TokenFamily  string
 	ExpiresAt    time.Time
+	// Scopes granted to the OAuth client the tokens were issued to
+	Scopes []string
 }
 
 // JWTManager – immutable & thread-safe
 type JWTService struct {
 	privateKey      *rsa.PrivateKey
 	publicKey       *rsa.PublicKey
+	keyID           string
 	accessTokenTTL  time.Duration
 	refreshTokenTTL time.Duration
 	issuer          string
 	audience        string
 	tokenRepo       repository.TokenRepository
+	failurePolicy   string
+	storeAvailable  func() bool
+	// maxSessions is the number of login sessions a user may have, 0 for no limit
+	maxSessions        int
+	sessionLimitPolicy string
+	// signingSecret derives the request signing keys of sessions
+	signingSecret []byte
 }
 
 func NewService(cfg *config.JWTConfig, tokenRepo repository.TokenRepository) (*JWTService, error) {
@@ -67,35 +139,164 @@ func NewService(cfg *config.JWTConfig, tokenRepo repository.TokenRepository) (*J
 		return nil, fmt.Errorf("failed to load public key: %w", err)
 	}
 
+	keyID, err := publicKeyID(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key id: %w", err)
+	}
+
+	if cfg.MaxSessions > 0 && cfg.SessionLimitPolicy != entities.SessionLimitReject && cfg.SessionLimitPolicy != entities.SessionLimitEvictOldest {
+		return nil, fmt.Errorf("unknown session limit policy %q", cfg.SessionLimitPolicy)
+	}
+
+	// derived from the private key so every instance derives the same session keys
+	signingSecret := sha256.Sum256(append([]byte("request-signing\n"), x509.MarshalPKCS1PrivateKey(privateKey)...))
+
 	return &JWTService{
 		privateKey:      privateKey,
 		publicKey:       publicKey,
+		keyID:           keyID,
 		accessTokenTTL:  cfg.AccessTokenTTL,
 		refreshTokenTTL: cfg.RefreshTokenTTL,
 		issuer:          cfg.Issuer,
 		audience:        cfg.Audience,
 		tokenRepo:       tokenRepo,
+		failurePolicy:   cfg.RedisFailurePolicy,
+
+		maxSessions:        cfg.MaxSessions,
+		sessionLimitPolicy: cfg.SessionLimitPolicy,
+		signingSecret:      signingSecret[:],
 	}, nil
 }
 
+// SetTokenStoreHealth sets the function reporting whether the token store is available,
+// used to apply the Redis failure policy without waiting for a request to time out
+func (s *JWTService) SetTokenStoreHealth(available func() bool) {
+	s.storeAvailable = available
+}
+
 // GenerateTokenPair generates new access and refresh tokens
 func (s *JWTService) GenerateTokenPair(ctx context.Context, userID uuid.UUID, role entities.Role) (*TokenPair, error) {
 	tokenFamily := uuid.New().String()
-	return s.generateTokenPairWithFamily(ctx, userID, role, tokenFamily)
+	return s.generateTokenPairWithFamily(ctx, userID, role, tokenFamily, "", nil)
+}
+
+// StartSession generates the token pair of a new login session and records the device
+// it was started from. A user at the session limit is rejected, or their oldest sessions
+// are evicted, depending on the session limit policy. The limit is soft, logins racing
+// each other may go over it
+func (s *JWTService) StartSession(ctx context.Context, userID uuid.UUID, role entities.Role, device, ip string) (*TokenPair, error) {
+	if s.maxSessions > 0 {
+		if err := s.enforceSessionLimit(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	tokenPair, err := s.GenerateTokenPair(ctx, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &entities.Session{
+		Family:     tokenPair.TokenFamily,
+		Device:     device,
+		IP:         ip,
+		CreatedAt:  now,
+		LastUsedAt: now,
+	}
+	if err := s.tokenRepo.SaveSession(ctx, userID, session, s.refreshTokenTTL); err != nil {
+		return nil, err
+	}
+	return tokenPair, nil
+}
+
+// enforceSessionLimit makes room for one more session of the user
+func (s *JWTService) enforceSessionLimit(ctx context.Context, userID uuid.UUID) error {
+	sessions, err := s.tokenRepo.ListUserSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	excess := len(sessions) - s.maxSessions + 1
+	if excess <= 0 {
+		return nil
+	}
+	if s.sessionLimitPolicy != entities.SessionLimitEvictOldest {
+		authSessionsRejected.Inc()
+		return ErrSessionLimitReached
+	}
+
+	// sessions are listed oldest first
+	for _, session := range sessions[:excess] {
+		now := time.Now()
+		// tokens already handed out for the session stop working right away
+		if _, err := s.RevokeIssuedBefore(ctx, []string{FamilyScope(session.Family)}, now); err != nil {
+			return err
+		}
+		session.EvictedAt = &now
+		if err := s.tokenRepo.EvictUserSession(ctx, userID, session, s.refreshTokenTTL); err != nil {
+			return err
+		}
+		authSessionsEvicted.Inc()
+		logger.InfoContext(ctx, "Session evicted at login", "user_id", userID.String(), "family", session.Family, "device", session.Device)
+	}
+	return nil
+}
+
+// RequestSigningKey returns the key the requests of the session of family are signed with
+// for replay protection. It's handed to the client when tokens are issued and never sent
+// with a request, the server derives it again from the family of the access token
+func (s *JWTService) RequestSigningKey(family string) string {
+	mac := hmac.New(sha256.New, s.signingSecret)
+	mac.Write([]byte(family))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// EndSession removes a login session, e.g. at logout, so it no longer counts against
+// the session limit
+func (s *JWTService) EndSession(ctx context.Context, userID uuid.UUID, family string) error {
+	return s.tokenRepo.RemoveUserSession(ctx, userID, family)
+}
+
+// ListSessions returns the active login sessions of a user, oldest first, and the
+// sessions recently evicted by the session limit
+func (s *JWTService) ListSessions(ctx context.Context, userID uuid.UUID) (active, evicted []*entities.Session, err error) {
+	active, err = s.tokenRepo.ListUserSessions(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	evicted, err = s.tokenRepo.ListEvictedSessions(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return active, evicted, nil
+}
+
+// MaxSessions returns the number of login sessions a user may have, 0 for no limit
+func (s *JWTService) MaxSessions() int {
+	return s.maxSessions
 }
 
 // generate Token
-// generateTokenPairWithFamily generates tokens with a specific family
-func (s *JWTService) generateTokenPairWithFamily(ctx context.Context, userID uuid.UUID, role entities.Role, tokenFamily string) (*TokenPair, error) {
+// generateTokenPairWithFamily generates tokens with a specific family, tokens of an
+// OAuth client are generated when clientID is set
+func (s *JWTService) generateTokenPairWithFamily(ctx context.Context, userID uuid.UUID, role entities.Role, tokenFamily, clientID string, scopes []string) (*TokenPair, error) {
 	now := time.Now()
 	accessJTI := uuid.New().String()
 	refreshJTI := uuid.New().String()
+	accessType, refreshType := TokenTypeAccess, TokenTypeRefresh
+	if clientID != "" {
+		accessType, refreshType = TokenTypeOAuthAccess, TokenTypeOAuthRefresh
+	}
+	scope := strings.Join(scopes, " ")
 
-	// Generate access token
+	// Generate access token, it carries the family so evicting a session revokes it too
 	accessClaims := Claims{
-		UserID:    userID,
-		Role:      role,
-		TokenType: TokenTypeAccess,
+		UserID:      userID,
+		Role:        role,
+		TokenType:   accessType,
+		TokenFamily: tokenFamily,
+		ClientID:    clientID,
+		Scope:       scope,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        accessJTI,
 			Subject:   userID.String(),
@@ -116,8 +317,10 @@ func (s *JWTService) generateTokenPairWithFamily(ctx context.Context, userID uui
 	refreshClaims := Claims{
 		UserID:      userID,
 		Role:        role,
-		TokenType:   TokenTypeRefresh,
+		TokenType:   refreshType,
 		TokenFamily: tokenFamily,
+		ClientID:    clientID,
+		Scope:       scope,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        refreshJTI,
 			Subject:   userID.String(),
@@ -151,32 +354,45 @@ func (s *JWTService) generateTokenPairWithFamily(ctx context.Context, userID uui
 		RefreshJTI:   refreshJTI,
 		TokenFamily:  tokenFamily,
 		ExpiresAt:    now.Add(s.accessTokenTTL),
+		Scopes:       scopes,
 	}, nil
 }
 
-// signToken signs a token with the private key
-func (s *JWTService) signToken(claims Claims) (string, error) {
+// signToken signs a token with the private key, the key id lets OAuth clients pick
+// the key from the JWKS
+func (s *JWTService) signToken(claims jwt.Claims) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = s.keyID
 	return token.SignedString(s.privateKey)
 }
 
 // RefreshTokens validates refresh token and generates new token pair
 func (s *JWTService) RefreshTokens(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	claims, err := s.rotateRefreshToken(ctx, refreshToken, TokenTypeRefresh)
+	if err != nil {
+		return nil, err
+	}
+	return s.generateTokenPairWithFamily(ctx, claims.UserID, claims.Role, claims.TokenFamily, "", nil)
+}
+
+// rotateRefreshToken validates a refresh token of tokenType and blacklists it, so its
+// family can be continued with a new pair. Reusing a rotated token revokes all sessions
+func (s *JWTService) rotateRefreshToken(ctx context.Context, refreshToken, tokenType string) (*Claims, error) {
 	claims, err := s.ValidateToken(refreshToken)
 	if err != nil {
 		return nil, err
 	}
 
-	if claims.TokenType != TokenTypeRefresh {
+	if claims.TokenType != tokenType {
 		return nil, errors.New("invalid token type")
 	}
 
-	isBlacklisted, err := s.tokenRepo.IsTokenBlacklisted(ctx, claims.ID)
+	isRevoked, err := s.isRevoked(ctx, claims)
 	if err != nil {
 		return nil, err
 	}
-	if isBlacklisted {
-		return nil, errors.New("token has been revoked")
+	if isRevoked {
+		return nil, errTokenRevoked
 	}
 
 	// Check for token reuse
@@ -198,7 +414,7 @@ func (s *JWTService) RefreshTokens(ctx context.Context, refreshToken string) (*T
 		return nil, err
 	}
 
-	return s.generateTokenPairWithFamily(ctx, claims.UserID, claims.Role, claims.TokenFamily)
+	return claims, nil
 }
 
 // ValidateToken validates a token and returns its claims
@@ -233,36 +449,105 @@ func (s *JWTService) ValidateToken(tokenString string) (*Claims, error) {
 
 // ValidateAccessToken validates an access token and checks blacklist
 func (s *JWTService) ValidateAccessToken(ctx context.Context, tokenString string) (*Claims, error) {
+	return s.validateAccessToken(ctx, tokenString, TokenTypeAccess)
+}
+
+// validateAccessToken validates an access token of tokenType and checks blacklist,
+// counting the result
+func (s *JWTService) validateAccessToken(ctx context.Context, tokenString, tokenType string) (*Claims, error) {
+	claims, err := s.checkAccessToken(ctx, tokenString, tokenType)
+	switch {
+	case err == nil:
+		authValidations.With("valid").Inc()
+	case errors.Is(err, jwt.ErrTokenExpired):
+		authValidations.With("expired").Inc()
+	case errors.Is(err, errTokenRevoked):
+		authValidations.With("revoked").Inc()
+	case errors.Is(err, ErrTokenStoreUnavailable):
+		authValidations.With("unavailable").Inc()
+	default:
+		authValidations.With("invalid").Inc()
+	}
+	return claims, err
+}
+
+// checkAccessToken validates an access token of tokenType and checks blacklist
+func (s *JWTService) checkAccessToken(ctx context.Context, tokenString, tokenType string) (*Claims, error) {
 	claims, err := s.ValidateToken(tokenString)
 	if err != nil {
 		return nil, err
 	}
 
-	if claims.TokenType != TokenTypeAccess {
+	if claims.TokenType != tokenType {
 		return nil, errors.New("invalid token type")
 	}
 
-	isBlacklisted, err := s.tokenRepo.IsTokenBlacklisted(ctx, claims.ID)
+	if s.storeAvailable != nil && !s.storeAvailable() {
+		return s.validateDegraded(claims, ErrTokenStoreUnavailable)
+	}
+
+	isRevoked, err := s.isRevoked(ctx, claims)
 	if err != nil {
-		return nil, err
+		return s.validateDegraded(claims, fmt.Errorf("%w: %v", ErrTokenStoreUnavailable, err))
 	}
-	if isBlacklisted {
-		return nil, errors.New("token has been revoked")
+	if isRevoked {
+		return nil, errTokenRevoked
 	}
 
 	return claims, nil
 }
 
+// isRevoked checks the blacklist and the revocation cutoffs of the token's scopes
+func (s *JWTService) isRevoked(ctx context.Context, claims *Claims) (bool, error) {
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+	scopes := []string{ScopeAll, RoleScope(claims.Role), UserScope(claims.UserID)}
+	if claims.TokenFamily != "" {
+		scopes = append(scopes, FamilyScope(claims.TokenFamily))
+	}
+	return s.tokenRepo.IsTokenRevoked(ctx, claims.ID, issuedAt, scopes...)
+}
+
+// validateDegraded applies the Redis failure policy when the blacklist cannot be checked
+func (s *JWTService) validateDegraded(claims *Claims, cause error) (*Claims, error) {
+	if s.failurePolicy == PolicyFailOpen {
+		authDegradedAccepted.Inc()
+		logger.Warn("Access token accepted without blacklist check", "jti", claims.ID, "reason", cause.Error())
+		return claims, nil
+	}
+	authDegradedRejected.Inc()
+	return nil, cause
+}
+
 // BlacklistToken adds a token JTI to the blacklist
 func (s *JWTService) BlacklistToken(ctx context.Context, jti string, ttl time.Duration) error {
 	return s.tokenRepo.BlacklistToken(ctx, jti, ttl)
 }
 
+// RevokeIssuedBefore revokes every token of the scopes issued before t. The cutoff is
+// rounded up to the second because issued-at claims are, and is kept until the last
+// token issued before t expires. It returns the number of scopes whose cutoff was raised
+func (s *JWTService) RevokeIssuedBefore(ctx context.Context, scopes []string, t time.Time) (int, error) {
+	cutoff := t.Truncate(time.Second)
+	if cutoff.Before(t) {
+		cutoff = cutoff.Add(time.Second)
+	}
+	ttl := time.Until(cutoff) + max(s.accessTokenTTL, s.refreshTokenTTL)
+	return s.tokenRepo.RevokeIssuedBefore(ctx, scopes, cutoff, ttl)
+}
+
 // RevokeAllUserSessions revokes all sessions for a user
 func (s *JWTService) RevokeAllUserSessions(ctx context.Context, userID uuid.UUID) error {
 	return s.tokenRepo.RevokeAllUserSessions(ctx, userID)
 }
 
+// PruneExpiredSessions drops the expired sessions of every user from the session sets
+func (s *JWTService) PruneExpiredSessions(ctx context.Context) (int64, error) {
+	return s.tokenRepo.PruneSessions(ctx, time.Now())
+}
+
 // GetAccessTokenTTL returns the access token TTL
 func (s *JWTService) GetAccessTokenTTL() time.Duration {
 	return s.accessTokenTTL
@@ -0,0 +1,101 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"postgresDB/internal/domain/entities"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// IDTokenClaims are the claims of an OpenID Connect ID token. The caller sets the
+// issuer, subject and audience, the identity claims are only set for granted scopes
+type IDTokenClaims struct {
+	Nonce             string `json:"nonce,omitempty"`
+	AuthorizedParty   string `json:"azp,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Email             string `json:"email,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// JWK is the public signing key in JSON Web Key format
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+// GenerateClientTokens generates a new access and refresh token pair for an OAuth
+// client acting for the user, in a token family of its own
+func (s *JWTService) GenerateClientTokens(ctx context.Context, userID uuid.UUID, role entities.Role, clientID string, scopes []string) (*TokenPair, error) {
+	if clientID == "" {
+		return nil, errors.New("client id is required")
+	}
+	return s.generateTokenPairWithFamily(ctx, userID, role, uuid.New().String(), clientID, scopes)
+}
+
+// RefreshClientTokens rotates a refresh token issued to the OAuth client, a token of
+// another client is rejected without being rotated
+func (s *JWTService) RefreshClientTokens(ctx context.Context, refreshToken, clientID string) (*TokenPair, error) {
+	claims, err := s.ValidateToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.ClientID != clientID {
+		return nil, errors.New("token was issued to another client")
+	}
+
+	claims, err = s.rotateRefreshToken(ctx, refreshToken, TokenTypeOAuthRefresh)
+	if err != nil {
+		return nil, err
+	}
+	return s.generateTokenPairWithFamily(ctx, claims.UserID, claims.Role, claims.TokenFamily, claims.ClientID, strings.Fields(claims.Scope))
+}
+
+// ValidateClientAccessToken validates an access token issued to an OAuth client and
+// checks blacklist
+func (s *JWTService) ValidateClientAccessToken(ctx context.Context, tokenString string) (*Claims, error) {
+	return s.validateAccessToken(ctx, tokenString, TokenTypeOAuthAccess)
+}
+
+// GenerateIDToken signs an ID token valid as long as an access token
+func (s *JWTService) GenerateIDToken(claims IDTokenClaims) (string, error) {
+	now := time.Now()
+	claims.ID = uuid.New().String()
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(s.accessTokenTTL))
+	return s.signToken(claims)
+}
+
+// PublicJWK returns the public key tokens are signed with
+func (s *JWTService) PublicJWK() JWK {
+	return JWK{
+		KeyType:   "RSA",
+		Use:       "sig",
+		Algorithm: jwt.SigningMethodRS256.Alg(),
+		KeyID:     s.keyID,
+		Modulus:   base64.RawURLEncoding.EncodeToString(s.publicKey.N.Bytes()),
+		Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.publicKey.E)).Bytes()),
+	}
+}
+
+// publicKeyID derives a stable key id from the public key, it changes with the key
+func publicKeyID(key *rsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(sum[:12]), nil
+}
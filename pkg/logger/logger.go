@@ -1,11 +1,15 @@
 package logger
 
 import (
+	"context"
 	"log/slog"
 	"os"
 	"sync"
 )
 
+// requestIDKey is the context key of the request ID
+type requestIDKey struct{}
+
 var (
 	logger *slog.Logger
 	once   sync.Once
@@ -25,7 +29,7 @@ func Init(env string) {
 			handler = slog.NewTextHandler(os.Stdout, opts)
 		}
 
-		logger = slog.New(handler)
+		logger = slog.New(contextHandler{handler})
 		slog.SetDefault(logger)
 	})
 }
@@ -39,6 +43,38 @@ func Get() *slog.Logger {
 	return logger
 }
 
+// WithRequestID returns a copy of ctx carrying the request ID, which is added to every
+// line logged with that context
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, empty when there is none
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// contextHandler adds the request ID of the context a line is logged with
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
 // Info logs an informational message
 func Info(msg string, args ...any) {
 	Get().Info(msg, args...)
@@ -58,3 +94,23 @@ func Debug(msg string, args ...any) {
 func Warn(msg string, args ...any) {
 	Get().Warn(msg, args...)
 }
+
+// InfoContext logs an informational message with the request ID of ctx
+func InfoContext(ctx context.Context, msg string, args ...any) {
+	Get().InfoContext(ctx, msg, args...)
+}
+
+// ErrorContext logs an error message with the request ID of ctx
+func ErrorContext(ctx context.Context, msg string, args ...any) {
+	Get().ErrorContext(ctx, msg, args...)
+}
+
+// DebugContext logs a debug message with the request ID of ctx
+func DebugContext(ctx context.Context, msg string, args ...any) {
+	Get().DebugContext(ctx, msg, args...)
+}
+
+// WarnContext logs a warning message with the request ID of ctx
+func WarnContext(ctx context.Context, msg string, args ...any) {
+	Get().WarnContext(ctx, msg, args...)
+}
@@ -0,0 +1,364 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// MaxSeries caps the label combinations of a CounterVec by default, Limit changes it.
+// Further combinations are counted under the value "other" for every label, so a label
+// fed with unbounded values can't blow up the number of series
+const MaxSeries = 100
+
+// overflowLabel replaces the label values of combinations beyond MaxSeries
+const overflowLabel = "other"
+
+// CounterVec is a counter partitioned by label values. Label values must come from a
+// small fixed set (statuses, outcomes), never from IDs or user input
+type CounterVec struct {
+	labels    []string
+	maxSeries int
+
+	mu       sync.RWMutex
+	counters map[string]*Counter
+	overflow *Counter
+}
+
+// With returns the counter of the label values, given in the order of the labels
+func (v *CounterVec) With(values ...string) *Counter {
+	key := formatLabels(v.labels, values)
+
+	v.mu.RLock()
+	c, ok := v.counters[key]
+	v.mu.RUnlock()
+	if ok {
+		return c
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if c, ok := v.counters[key]; ok {
+		return c
+	}
+	if len(v.counters) >= v.maxSeries {
+		if v.overflow == nil {
+			v.overflow = &Counter{}
+			overflow := make([]string, len(v.labels))
+			for i := range overflow {
+				overflow[i] = overflowLabel
+			}
+			v.counters[formatLabels(v.labels, overflow)] = v.overflow
+		}
+		return v.overflow
+	}
+	c = &Counter{}
+	v.counters[key] = c
+	return c
+}
+
+// Limit sets the most label combinations of the vector, for labels with a larger but
+// still fixed set of values such as route patterns. Call it before the first With
+func (v *CounterVec) Limit(n int) *CounterVec {
+	v.maxSeries = n
+	return v
+}
+
+func (v *CounterVec) samples() []sample {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	samples := make([]sample, 0, len(v.counters))
+	for labels, c := range v.counters {
+		samples = append(samples, sample{labels: labels, value: c.Value()})
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].labels < samples[j].labels })
+	return samples
+}
+
+// GaugeVec is a gauge partitioned by label values, with the same label rules and series
+// cap as CounterVec
+type GaugeVec struct {
+	labels    []string
+	maxSeries int
+
+	mu       sync.RWMutex
+	gauges   map[string]*Gauge
+	overflow *Gauge
+}
+
+// With returns the gauge of the label values, given in the order of the labels
+func (v *GaugeVec) With(values ...string) *Gauge {
+	key := formatLabels(v.labels, values)
+
+	v.mu.RLock()
+	g, ok := v.gauges[key]
+	v.mu.RUnlock()
+	if ok {
+		return g
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if g, ok := v.gauges[key]; ok {
+		return g
+	}
+	if len(v.gauges) >= v.maxSeries {
+		if v.overflow == nil {
+			v.overflow = &Gauge{}
+			overflow := make([]string, len(v.labels))
+			for i := range overflow {
+				overflow[i] = overflowLabel
+			}
+			v.gauges[formatLabels(v.labels, overflow)] = v.overflow
+		}
+		return v.overflow
+	}
+	g = &Gauge{}
+	v.gauges[key] = g
+	return g
+}
+
+// Limit sets the most label combinations of the vector, see CounterVec.Limit
+func (v *GaugeVec) Limit(n int) *GaugeVec {
+	v.maxSeries = n
+	return v
+}
+
+func (v *GaugeVec) samples() []sample {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	samples := make([]sample, 0, len(v.gauges))
+	for labels, g := range v.gauges {
+		samples = append(samples, sample{labels: labels, value: g.Value()})
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].labels < samples[j].labels })
+	return samples
+}
+
+// Histogram counts observations in cumulative buckets
+type Histogram struct {
+	// labels are the rendered label pairs of a HistogramVec member, without braces
+	labels string
+	// upper are the bucket upper bounds in increasing order
+	upper  []float64
+	counts []atomic.Uint64
+	count  atomic.Uint64
+	sum    Gauge
+}
+
+// Observe records a value
+func (h *Histogram) Observe(v float64) {
+	if i := sort.SearchFloat64s(h.upper, v); i < len(h.upper) {
+		h.counts[i].Add(1)
+	}
+	h.sum.Add(v)
+	h.count.Add(1)
+}
+
+// Count returns the number of observations
+func (h *Histogram) Count() float64 {
+	return float64(h.count.Load())
+}
+
+// CountAtOrBelow returns the number of observations of at most the largest bucket bound
+// not above v, so a v between two bounds is rounded down to the lower one
+func (h *Histogram) CountAtOrBelow(v float64) float64 {
+	var count uint64
+	for i, upper := range h.upper {
+		if upper > v {
+			break
+		}
+		count += h.counts[i].Load()
+	}
+	return float64(count)
+}
+
+func (h *Histogram) samples() []sample {
+	samples := make([]sample, 0, len(h.upper)+3)
+	prefix, labels := "", ""
+	if h.labels != "" {
+		prefix, labels = h.labels+",", "{"+h.labels+"}"
+	}
+	var cumulative uint64
+	for i, upper := range h.upper {
+		cumulative += h.counts[i].Load()
+		samples = append(samples, sample{
+			suffix: "_bucket",
+			labels: "{" + prefix + `le="` + strconv.FormatFloat(upper, 'g', -1, 64) + `"}`,
+			value:  float64(cumulative),
+		})
+	}
+	count := float64(h.count.Load())
+	return append(samples,
+		sample{suffix: "_bucket", labels: "{" + prefix + `le="+Inf"}`, value: count},
+		sample{suffix: "_sum", labels: labels, value: h.sum.Value()},
+		sample{suffix: "_count", labels: labels, value: count},
+	)
+}
+
+// HistogramVec is a histogram partitioned by label values, with the same label rules and
+// series cap as CounterVec
+type HistogramVec struct {
+	labels    []string
+	upper     []float64
+	maxSeries int
+
+	mu         sync.RWMutex
+	histograms map[string]*Histogram
+	overflow   *Histogram
+}
+
+// With returns the histogram of the label values, given in the order of the labels
+func (v *HistogramVec) With(values ...string) *Histogram {
+	key := formatLabels(v.labels, values)
+
+	v.mu.RLock()
+	h, ok := v.histograms[key]
+	v.mu.RUnlock()
+	if ok {
+		return h
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if h, ok := v.histograms[key]; ok {
+		return h
+	}
+	if len(v.histograms) >= v.maxSeries {
+		if v.overflow == nil {
+			overflow := make([]string, len(v.labels))
+			for i := range overflow {
+				overflow[i] = overflowLabel
+			}
+			key := formatLabels(v.labels, overflow)
+			v.overflow = v.newHistogram(key)
+			v.histograms[key] = v.overflow
+		}
+		return v.overflow
+	}
+	h = v.newHistogram(key)
+	v.histograms[key] = h
+	return h
+}
+
+// Limit sets the most label combinations of the vector, see CounterVec.Limit
+func (v *HistogramVec) Limit(n int) *HistogramVec {
+	v.maxSeries = n
+	return v
+}
+
+func (v *HistogramVec) newHistogram(key string) *Histogram {
+	return &Histogram{
+		labels: strings.TrimSuffix(strings.TrimPrefix(key, "{"), "}"),
+		upper:  v.upper,
+		counts: make([]atomic.Uint64, len(v.upper)),
+	}
+}
+
+func (v *HistogramVec) samples() []sample {
+	v.mu.RLock()
+	keys := make([]string, 0, len(v.histograms))
+	for key := range v.histograms {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	histograms := make([]*Histogram, len(keys))
+	for i, key := range keys {
+		histograms[i] = v.histograms[key]
+	}
+	v.mu.RUnlock()
+
+	var samples []sample
+	for _, h := range histograms {
+		samples = append(samples, h.samples()...)
+	}
+	return samples
+}
+
+// NewCounterVec creates and registers a labeled counter in the default registry
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return Default.NewCounterVec(name, help, labels...)
+}
+
+// NewGaugeVec creates and registers a labeled gauge in the default registry
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return Default.NewGaugeVec(name, help, labels...)
+}
+
+// NewHistogram creates and registers a histogram in the default registry
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	return Default.NewHistogram(name, help, buckets)
+}
+
+// NewHistogramVec creates and registers a labeled histogram in the default registry
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return Default.NewHistogramVec(name, help, buckets, labels...)
+}
+
+// NewCounterVec creates and registers a labeled counter
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	v := &CounterVec{labels: labels, maxSeries: MaxSeries, counters: make(map[string]*Counter)}
+	r.register(&metric{name: name, help: help, kind: "counter", samples: v.samples})
+	return v
+}
+
+// NewGaugeVec creates and registers a labeled gauge
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	v := &GaugeVec{labels: labels, maxSeries: MaxSeries, gauges: make(map[string]*Gauge)}
+	r.register(&metric{name: name, help: help, kind: "gauge", samples: v.samples})
+	return v
+}
+
+// NewHistogram creates and registers a histogram with the given bucket upper bounds
+func (r *Registry) NewHistogram(name, help string, buckets []float64) *Histogram {
+	upper := bucketBounds(buckets)
+	h := &Histogram{upper: upper, counts: make([]atomic.Uint64, len(upper))}
+	r.register(&metric{name: name, help: help, kind: "histogram", samples: h.samples})
+	return h
+}
+
+// NewHistogramVec creates and registers a labeled histogram with the given bucket upper
+// bounds
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	v := &HistogramVec{labels: labels, upper: bucketBounds(buckets), maxSeries: MaxSeries, histograms: make(map[string]*Histogram)}
+	r.register(&metric{name: name, help: help, kind: "histogram", samples: v.samples})
+	return v
+}
+
+// bucketBounds returns the finite bucket upper bounds in increasing order
+func bucketBounds(buckets []float64) []float64 {
+	upper := make([]float64, 0, len(buckets))
+	for _, b := range buckets {
+		if !math.IsInf(b, 1) {
+			upper = append(upper, b)
+		}
+	}
+	sort.Float64s(upper)
+	return upper
+}
+
+// formatLabels renders label pairs in the exposition format, missing values are empty
+func formatLabels(labels, values []string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, label := range labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		b.WriteString(label)
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(value))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,226 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Counter is a monotonically increasing metric
+type Counter struct {
+	bits atomic.Uint64
+}
+
+// Inc increments the counter by 1
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add increments the counter by v (v must be >= 0)
+func (c *Counter) Add(v float64) {
+	for {
+		old := c.bits.Load()
+		if c.bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+v)) {
+			return
+		}
+	}
+}
+
+// Value returns the current value
+func (c *Counter) Value() float64 {
+	return math.Float64frombits(c.bits.Load())
+}
+
+// Gauge is a metric that can go up and down
+type Gauge struct {
+	bits atomic.Uint64
+}
+
+// Set sets the gauge to v
+func (g *Gauge) Set(v float64) {
+	g.bits.Store(math.Float64bits(v))
+}
+
+// Add adds v to the gauge (v may be negative)
+func (g *Gauge) Add(v float64) {
+	for {
+		old := g.bits.Load()
+		if g.bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+v)) {
+			return
+		}
+	}
+}
+
+// Value returns the current value
+func (g *Gauge) Value() float64 {
+	return math.Float64frombits(g.bits.Load())
+}
+
+// metric is a registered metric with its metadata
+type metric struct {
+	name    string
+	help    string
+	kind    string
+	samples func() []sample
+}
+
+// sample is one exposed value of a metric; suffix and labels are empty for plain
+// counters and gauges
+type sample struct {
+	suffix string
+	labels string
+	value  float64
+}
+
+// single returns the samples of a metric with a single unlabeled value
+func single(value func() float64) func() []sample {
+	return func() []sample {
+		return []sample{{value: value()}}
+	}
+}
+
+// Registry holds registered metrics
+type Registry struct {
+	mu      sync.RWMutex
+	metrics map[string]*metric
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]*metric)}
+}
+
+// Default is the registry used by the package level constructors
+var Default = NewRegistry()
+
+// NewCounter creates and registers a counter in the default registry
+func NewCounter(name, help string) *Counter {
+	return Default.NewCounter(name, help)
+}
+
+// NewGauge creates and registers a gauge in the default registry
+func NewGauge(name, help string) *Gauge {
+	return Default.NewGauge(name, help)
+}
+
+// NewCounterFunc registers a counter in the default registry whose value is read from
+// value at every scrape, for counts kept by a library
+func NewCounterFunc(name, help string, value func() float64) {
+	Default.NewCounterFunc(name, help, value)
+}
+
+// NewGaugeFunc registers a gauge in the default registry whose value is read from value
+// at every scrape
+func NewGaugeFunc(name, help string, value func() float64) {
+	Default.NewGaugeFunc(name, help, value)
+}
+
+// NewCounter creates and registers a counter
+func (r *Registry) NewCounter(name, help string) *Counter {
+	c := &Counter{}
+	r.register(&metric{name: name, help: help, kind: "counter", samples: single(c.Value)})
+	return c
+}
+
+// NewGauge creates and registers a gauge
+func (r *Registry) NewGauge(name, help string) *Gauge {
+	g := &Gauge{}
+	r.register(&metric{name: name, help: help, kind: "gauge", samples: single(g.Value)})
+	return g
+}
+
+// NewCounterFunc registers a counter whose value is read from value at every scrape
+func (r *Registry) NewCounterFunc(name, help string, value func() float64) {
+	r.register(&metric{name: name, help: help, kind: "counter", samples: single(value)})
+}
+
+// NewGaugeFunc registers a gauge whose value is read from value at every scrape
+func (r *Registry) NewGaugeFunc(name, help string, value func() float64) {
+	r.register(&metric{name: name, help: help, kind: "gauge", samples: single(value)})
+}
+
+// register adds a metric, panicking on duplicate names like other metric libraries do
+func (r *Registry) register(m *metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.metrics[m.name]; exists {
+		panic(fmt.Sprintf("metrics: duplicate metric %q", m.name))
+	}
+	r.metrics[m.name] = m
+}
+
+// Snapshot returns the current value of every series, keyed by name and labels
+func (r *Registry) Snapshot() map[string]float64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	snapshot := make(map[string]float64, len(r.metrics))
+	for name, m := range r.metrics {
+		for _, s := range m.samples() {
+			snapshot[name+s.suffix+s.labels] = s.value
+		}
+	}
+	return snapshot
+}
+
+// WriteText writes all metrics in the Prometheus text exposition format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	r.mu.RUnlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		r.mu.RLock()
+		m := r.metrics[name]
+		r.mu.RUnlock()
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind); err != nil {
+			return err
+		}
+		for _, s := range m.samples() {
+			if _, err := fmt.Fprintf(w, "%s%s%s %g\n", m.name, s.suffix, s.labels, s.value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteOpenMetrics writes all metrics in the OpenMetrics text format. Counter families
+// are named without their _total suffix, as OpenMetrics requires
+func (r *Registry) WriteOpenMetrics(w io.Writer) error {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	r.mu.RUnlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		r.mu.RLock()
+		m := r.metrics[name]
+		r.mu.RUnlock()
+		family, suffix := m.name, ""
+		if m.kind == "counter" {
+			family = strings.TrimSuffix(m.name, "_total")
+			suffix = "_total"
+		}
+		if _, err := fmt.Fprintf(w, "# TYPE %s %s\n# HELP %s %s\n", family, m.kind, family, m.help); err != nil {
+			return err
+		}
+		for _, s := range m.samples() {
+			if _, err := fmt.Fprintf(w, "%s%s%s%s %g\n", family, suffix, s.suffix, s.labels, s.value); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := io.WriteString(w, "# EOF\n")
+	return err
+}
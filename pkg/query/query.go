@@ -0,0 +1,247 @@
+package query
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"postgresDB/internal/domain/errors"
+	"postgresDB/pkg/i18n"
+)
+
+// dateLayout is the date-only form accepted for time fields next to RFC 3339
+const dateLayout = "2006-01-02"
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// message is a parse error whose text is shown to the client after the parameter name.
+// format is in the default language of i18n, args fill in its verbs
+type message struct {
+	format string
+	args   []any
+}
+
+func (m message) Error() string {
+	return i18n.T(i18n.Default, m.format, m.args...)
+}
+
+// detail returns the validation error of parameter name failing to parse with err
+func detail(name string, err error) errors.ValidationError {
+	msg, ok := err.(message)
+	if !ok {
+		msg = message{format: "%s", args: []any{err.Error()}}
+	}
+	return errors.ValidationError{Field: name, Message: "%s " + msg.format, Args: append([]any{name}, msg.args...)}
+}
+
+// Bind fills the fields of the struct dst points to from query parameters. A field is
+// bound when it has a query tag naming its parameter:
+//
+//	Page   int         `query:"page" default:"1"`
+//	Status string      `query:"status"`
+//	IDs    []uuid.UUID `query:"id"`
+//	From   time.Time   `query:"from"`
+//	To     time.Time   `query:"to,end"`
+//	Sort   query.Sort  `query:"sort" allowed:"name,price"`
+//
+// Supported types are strings, booleans, integers, floats, time.Time (RFC 3339 or
+// YYYY-MM-DD), types implementing encoding.TextUnmarshaler such as uuid.UUID, slices of
+// those and pointers to them. Slices take comma separated and repeated parameters alike.
+// A pointer is left nil when its parameter is missing, so "not sent" can be told apart
+// from the zero value; a *string is set even for an empty parameter.
+//
+// The end option makes a date-only value point at the start of the next day, so an
+// exclusive upper bound still covers the day given. Missing parameters take the default
+// tag, or keep the field's current value. Every malformed parameter is reported in one
+// validation error; rules such as ranges are left to the validate tags.
+func Bind(values url.Values, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return errors.WrapInternal(fmt.Errorf("query: Bind needs a pointer to a struct, got %T", dst))
+	}
+
+	var details []errors.ValidationError
+	bindStruct(values, v.Elem(), &details)
+	if len(details) > 0 {
+		return errors.NewValidationError(details)
+	}
+	return nil
+}
+
+// bindStruct binds the tagged fields of v, descending into embedded structs
+func bindStruct(values url.Values, v reflect.Value, details *[]errors.ValidationError) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag, ok := field.Tag.Lookup("query")
+		if !ok {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				bindStruct(values, v.Field(i), details)
+			}
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		raw, present := values[name]
+		if !present {
+			def, ok := field.Tag.Lookup("default")
+			if !ok {
+				continue
+			}
+			raw = []string{def}
+		}
+
+		end := options == "end"
+		if err := bindField(v.Field(i), raw, end); err != nil {
+			*details = append(*details, detail(name, err))
+			continue
+		}
+		if allowed, ok := field.Tag.Lookup("allowed"); ok {
+			if sort, ok := v.Field(i).Interface().(Sort); ok {
+				if err := sort.check(strings.Split(allowed, ",")); err != nil {
+					*details = append(*details, detail(name, err))
+				}
+			}
+		}
+	}
+}
+
+// bindField sets a field from the raw values of its parameter
+func bindField(f reflect.Value, raw []string, end bool) error {
+	switch {
+	case f.Kind() == reflect.Pointer:
+		if f.Type().Elem().Kind() != reflect.String && strings.TrimSpace(first(raw)) == "" {
+			return nil
+		}
+		elem := reflect.New(f.Type().Elem())
+		if err := bindField(elem.Elem(), raw, end); err != nil {
+			return err
+		}
+		f.Set(elem)
+		return nil
+
+	case f.Kind() == reflect.Slice && f.Type().Elem().Kind() != reflect.Uint8 && !isScalar(f.Type()):
+		items := split(raw)
+		slice := reflect.MakeSlice(f.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setScalar(slice.Index(i), item, end); err != nil {
+				return err
+			}
+		}
+		f.Set(slice)
+		return nil
+
+	default:
+		return setScalar(f, first(raw), end)
+	}
+}
+
+// setScalar parses one value into f
+func setScalar(f reflect.Value, s string, end bool) error {
+	if f.Type() == timeType {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			f.Set(reflect.Zero(timeType))
+			return nil
+		}
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			f.Set(reflect.ValueOf(t))
+			return nil
+		}
+		t, err := time.Parse(dateLayout, s)
+		if err != nil {
+			return message{format: "harus berformat RFC3339 atau YYYY-MM-DD"}
+		}
+		if end {
+			t = t.AddDate(0, 0, 1)
+		}
+		f.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if f.CanAddr() && f.Addr().Type().Implements(textUnmarshalerType) {
+		if err := f.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+			if msg, ok := err.(message); ok {
+				return msg
+			}
+			return message{format: "tidak valid"}
+		}
+		return nil
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(s)
+	case reflect.Bool:
+		s = strings.TrimSpace(s)
+		if s == "" {
+			f.SetBool(false)
+			return nil
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return message{format: "harus berupa true atau false"}
+		}
+		f.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, f.Type().Bits())
+		if err != nil {
+			return message{format: "harus berupa bilangan bulat"}
+		}
+		f.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.TrimSpace(s), 10, f.Type().Bits())
+		if err != nil {
+			return message{format: "harus berupa bilangan bulat positif"}
+		}
+		f.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(strings.TrimSpace(s), f.Type().Bits())
+		if err != nil {
+			return message{format: "harus berupa angka"}
+		}
+		f.SetFloat(n)
+	default:
+		panic(fmt.Sprintf("query: unsupported field type %s", f.Type()))
+	}
+	return nil
+}
+
+// isScalar reports whether a slice type parses from a single value, such as Sort
+func isScalar(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// first returns the first raw value, a repeated scalar parameter keeps the first one
+func first(raw []string) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	return raw[0]
+}
+
+// split returns the items of comma separated or repeated values, dropping empty ones
+func split(raw []string) []string {
+	items := make([]string, 0, len(raw))
+	for _, r := range raw {
+		for _, item := range strings.Split(r, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
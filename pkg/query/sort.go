@@ -0,0 +1,62 @@
+package query
+
+import (
+	"slices"
+	"strings"
+)
+
+// SortField is one key of a sort expression
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// Sort is a sort expression such as sort=-price,name: comma separated fields, each
+// descending when prefixed with "-". The first field sorts first
+type Sort []SortField
+
+// UnmarshalText parses a sort expression, each field may appear once
+func (s *Sort) UnmarshalText(text []byte) error {
+	var sort Sort
+	for _, item := range strings.Split(string(text), ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		field := SortField{Field: item}
+		if name, ok := strings.CutPrefix(item, "-"); ok {
+			field = SortField{Field: name, Desc: true}
+		}
+		if field.Field == "" {
+			return message{format: "berisi field kosong"}
+		}
+		if slices.ContainsFunc(sort, func(f SortField) bool { return f.Field == field.Field }) {
+			return message{format: "berisi %s lebih dari sekali", args: []any{field.Field}}
+		}
+		sort = append(sort, field)
+	}
+	*s = sort
+	return nil
+}
+
+// String returns the expression in the form it's parsed from
+func (s Sort) String() string {
+	items := make([]string, len(s))
+	for i, f := range s {
+		items[i] = f.Field
+		if f.Desc {
+			items[i] = "-" + f.Field
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+// check rejects fields outside allowed
+func (s Sort) check(allowed []string) error {
+	for _, f := range s {
+		if !slices.Contains(allowed, f.Field) {
+			return message{format: "hanya bisa berisi %s", args: []any{strings.Join(allowed, ", ")}}
+		}
+	}
+	return nil
+}
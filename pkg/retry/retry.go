@@ -0,0 +1,63 @@
+package retry
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"postgresDB/pkg/metrics"
+)
+
+var (
+	retries = metrics.NewCounterVec("retries_total",
+		"Number of operations run again after a transient error", "store")
+	exhausted = metrics.NewCounterVec("retries_exhausted_total",
+		"Number of operations that still failed with a transient error after their last attempt", "store")
+)
+
+// Policy bounds the retries of an operation
+type Policy struct {
+	// Attempts is the most times the operation runs, the first one included
+	Attempts int
+	// BaseDelay is the wait before the first retry, doubled before every next one up to
+	// MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// Do runs fn until it succeeds, fails with an error retryable rejects or has run
+// policy.Attempts times, and returns its last error. Retries are counted per store, the
+// backend fn talks to. Waits are jittered so failed callers don't retry together, and end
+// early with the error of fn when ctx is done
+func Do(ctx context.Context, store string, policy Policy, retryable func(error) bool, fn func() error) error {
+	err := fn()
+	for attempt := 1; err != nil && retryable(err); attempt++ {
+		if attempt >= policy.Attempts {
+			exhausted.With(store).Inc()
+			return err
+		}
+
+		timer := time.NewTimer(Backoff(policy.BaseDelay, policy.MaxDelay, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		retries.With(store).Inc()
+		err = fn()
+	}
+	return err
+}
+
+// Backoff returns the wait before retry attempt, counted from 1: base doubled per
+// attempt, capped at maxWait, with up to a fifth added at random
+func Backoff(base, maxWait time.Duration, attempt int) time.Duration {
+	wait := base
+	for i := 1; i < attempt && wait < maxWait; i++ {
+		wait *= 2
+	}
+	wait = min(wait, maxWait)
+	return wait + time.Duration(rand.Int64N(int64(wait)/5+1))
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// argon2Params holds the parameters encoded in an argon2 PHC string
+type argon2Params struct {
+	variant     string
+	memory      uint32
+	time        uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+// argon2idHasher hashes passwords with Argon2id
+type argon2idHasher struct {
+	memory      uint32
+	time        uint32
+	parallelism uint8
+}
+
+// NewArgon2idHasher creates an Argon2id PasswordHasher; memory is in KiB.
+// Zero values fall back to the OWASP recommended minimums.
+func NewArgon2idHasher(memory, time uint32, parallelism uint8) PasswordHasher {
+	if memory == 0 {
+		memory = 64 * 1024
+	}
+	if time == 0 {
+		time = 3
+	}
+	if parallelism == 0 {
+		parallelism = 2
+	}
+	return &argon2idHasher{
+		memory:      memory,
+		time:        time,
+		parallelism: parallelism,
+	}
+}
+
+// Hash hashes the password and encodes it as a PHC string:
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+func (h *argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.time, h.memory, h.parallelism, argon2KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.memory,
+		h.time,
+		h.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify checks the password against a hash in any supported format
+func (h *argon2idHasher) Verify(password, hashedPassword string) error {
+	return verifyPassword(password, hashedPassword)
+}
+
+// NeedsRehash reports whether the hash is not Argon2id or uses other parameters
+func (h *argon2idHasher) NeedsRehash(hashedPassword string) bool {
+	params, err := parseArgon2Hash(hashedPassword)
+	if err != nil || params.variant != "argon2id" {
+		return true
+	}
+	return params.memory != h.memory ||
+		params.time != h.time ||
+		params.parallelism != h.parallelism ||
+		len(params.key) != argon2KeyLength
+}
+
+// parseArgon2Hash parses an argon2 PHC string
+func parseArgon2Hash(hashedPassword string) (*argon2Params, error) {
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 || (parts[1] != "argon2id" && parts[1] != "argon2i") {
+		return nil, ErrUnsupportedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, fmt.Errorf("unsupported argon2 version: %s", parts[2])
+	}
+
+	p := &argon2Params{variant: parts[1]}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.parallelism); err != nil {
+		return nil, fmt.Errorf("invalid argon2 parameters: %w", err)
+	}
+
+	var err error
+	if p.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, fmt.Errorf("invalid argon2 salt: %w", err)
+	}
+	if p.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return nil, fmt.Errorf("invalid argon2 hash: %w", err)
+	}
+	return p, nil
+}
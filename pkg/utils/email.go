@@ -0,0 +1,9 @@
+package utils
+
+import "strings"
+
+// NormalizeEmail trims surrounding whitespace and lowercases the email so
+// addresses that differ only by case are treated as the same account
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
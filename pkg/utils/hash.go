@@ -6,16 +6,79 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-// HashPassword hashes the given password using bcrypt
-func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+// Supported password hashing algorithms
+const (
+	HashAlgorithmArgon2id = "argon2id"
+	HashAlgorithmBcrypt   = "bcrypt"
+)
+
+// PasswordHasher hashes and verifies passwords
+type PasswordHasher interface {
+	// Hash hashes the password with the configured algorithm
+	Hash(password string) (string, error)
+	// Verify checks the password against a hash in any supported format
+	Verify(password, hashedPassword string) error
+	// NeedsRehash reports whether the hash uses another algorithm or
+	// weaker parameters than configured and should be upgraded
+	NeedsRehash(hashedPassword string) bool
+}
+
+// HasherConfig holds the password hashing parameters
+type HasherConfig struct {
+	Algorithm         string
+	BcryptCost        int
+	Argon2Memory      uint32
+	Argon2Time        uint32
+	Argon2Parallelism uint8
+}
+
+// NewPasswordHasher creates a PasswordHasher for the configured algorithm
+func NewPasswordHasher(cfg HasherConfig) (PasswordHasher, error) {
+	switch cfg.Algorithm {
+	case HashAlgorithmArgon2id, "":
+		return NewArgon2idHasher(cfg.Argon2Memory, cfg.Argon2Time, cfg.Argon2Parallelism), nil
+	case HashAlgorithmBcrypt:
+		return NewBcryptHasher(cfg.BcryptCost)
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm: %s", cfg.Algorithm)
+	}
+}
+
+// bcryptHasher hashes passwords with bcrypt
+type bcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher creates a bcrypt PasswordHasher
+func NewBcryptHasher(cost int) (PasswordHasher, error) {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("invalid bcrypt cost: %d", cost)
+	}
+	return &bcryptHasher{cost: cost}, nil
+}
+
+// Hash hashes the given password using bcrypt
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
 	if err != nil {
 		return "", fmt.Errorf("failed to hash password: %w", err)
 	}
 	return string(bytes), nil
 }
 
-// CheckPassword checks if the provided password matches the hashed password
-func CheckPassword(password, hashedPassword string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+// Verify checks if the provided password matches the hashed password
+func (h *bcryptHasher) Verify(password, hashedPassword string) error {
+	return verifyPassword(password, hashedPassword)
+}
+
+// NeedsRehash reports whether the hash is not bcrypt or uses another cost
+func (h *bcryptHasher) NeedsRehash(hashedPassword string) bool {
+	if !(bcryptVerifier{}).Supports(hashedPassword) {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	return err != nil || cost != h.cost
 }
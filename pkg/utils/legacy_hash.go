@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrPasswordMismatch is returned when the password does not match the hash
+var ErrPasswordMismatch = errors.New("password does not match")
+
+// ErrUnsupportedHash is returned when no verifier recognizes the hash format
+var ErrUnsupportedHash = errors.New("unsupported password hash format")
+
+// PasswordVerifier verifies a password against one hash format
+type PasswordVerifier interface {
+	// Supports reports whether the verifier recognizes the hash format
+	Supports(hashedPassword string) bool
+	// Verify checks the password against the hash
+	Verify(password, hashedPassword string) error
+}
+
+// verifierChain lists the supported hash formats. MD5/SHA1 hashes come from
+// users imported from the old system and are upgraded on their next login.
+var verifierChain = []PasswordVerifier{
+	bcryptVerifier{},
+	argon2Verifier{},
+	digestVerifier{name: "sha1", size: sha1.Size, newHash: sha1.New},
+	digestVerifier{name: "md5", size: md5.Size, newHash: md5.New},
+}
+
+// verifyPassword checks the password with the first verifier supporting the hash format
+func verifyPassword(password, hashedPassword string) error {
+	for _, v := range verifierChain {
+		if v.Supports(hashedPassword) {
+			return v.Verify(password, hashedPassword)
+		}
+	}
+	return ErrUnsupportedHash
+}
+
+// bcryptVerifier verifies bcrypt hashes ($2a$, $2b$, $2y$)
+type bcryptVerifier struct{}
+
+func (bcryptVerifier) Supports(hashedPassword string) bool {
+	return strings.HasPrefix(hashedPassword, "$2a$") ||
+		strings.HasPrefix(hashedPassword, "$2b$") ||
+		strings.HasPrefix(hashedPassword, "$2y$")
+}
+
+func (bcryptVerifier) Verify(password, hashedPassword string) error {
+	if err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password)); err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrPasswordMismatch
+		}
+		return err
+	}
+	return nil
+}
+
+// argon2Verifier verifies argon2i/argon2id hashes in PHC string format:
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+type argon2Verifier struct{}
+
+func (argon2Verifier) Supports(hashedPassword string) bool {
+	return strings.HasPrefix(hashedPassword, "$argon2id$") || strings.HasPrefix(hashedPassword, "$argon2i$")
+}
+
+func (argon2Verifier) Verify(password, hashedPassword string) error {
+	p, err := parseArgon2Hash(hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	var actual []byte
+	if p.variant == "argon2id" {
+		actual = argon2.IDKey([]byte(password), p.salt, p.time, p.memory, p.parallelism, uint32(len(p.key)))
+	} else {
+		actual = argon2.Key([]byte(password), p.salt, p.time, p.memory, p.parallelism, uint32(len(p.key)))
+	}
+
+	if subtle.ConstantTimeCompare(actual, p.key) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
+
+// digestVerifier verifies unsalted hex digests ("<hex>") and salted digests
+// in the form "<name>$<salt>$<hex>" where hex = digest(salt + password)
+type digestVerifier struct {
+	name    string
+	size    int
+	newHash func() hash.Hash
+}
+
+func (v digestVerifier) Supports(hashedPassword string) bool {
+	if strings.HasPrefix(hashedPassword, v.name+"$") {
+		return true
+	}
+	return len(hashedPassword) == hex.EncodedLen(v.size) && isHex(hashedPassword)
+}
+
+func (v digestVerifier) Verify(password, hashedPassword string) error {
+	salt, digest := "", hashedPassword
+	if strings.HasPrefix(hashedPassword, v.name+"$") {
+		parts := strings.SplitN(hashedPassword, "$", 3)
+		if len(parts) != 3 {
+			return ErrUnsupportedHash
+		}
+		salt, digest = parts[1], parts[2]
+	}
+
+	expected, err := hex.DecodeString(strings.ToLower(digest))
+	if err != nil || len(expected) != v.size {
+		return ErrUnsupportedHash
+	}
+
+	h := v.newHash()
+	h.Write([]byte(salt + password))
+	if subtle.ConstantTimeCompare(h.Sum(nil), expected) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
+
+func isHex(s string) bool {
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidSignature is returned when a signed token was tampered with or malformed
+var ErrInvalidSignature = errors.New("invalid token signature")
+
+// SignToken returns payload and its HMAC-SHA256 signature as "<payload>.<signature>",
+// both base64url encoded, so the token is safe to put in a URL
+func SignToken(secret, payload []byte) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(sign(secret, payload))
+}
+
+// VerifySignedToken checks a token produced by SignToken and returns its payload
+func VerifySignedToken(secret []byte, token string) ([]byte, error) {
+	encodedPayload, encodedSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+
+	enc := base64.RawURLEncoding
+	payload, err := enc.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	sig, err := enc.DecodeString(encodedSig)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	if !hmac.Equal(sig, sign(secret, payload)) {
+		return nil, ErrInvalidSignature
+	}
+	return payload, nil
+}
+
+func sign(secret, payload []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Slugify converts a string to a URL friendly slug, e.g. "Men's Shoes" -> "men-s-shoes"
+func Slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+			dash = false
+		case b.Len() > 0 && !dash:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
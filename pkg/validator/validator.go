@@ -36,6 +36,7 @@ func GetValidator() *validator.Validate {
 		_ = validate.RegisterValidation("strongPassword", validatePassword)
 		_ = validate.RegisterValidation("username", validateUsername)
 		_ = validate.RegisterValidation("customEmail", validateCustomEmail)
+		_ = validate.RegisterValidation("slug", validateSlug)
 	})
 	return validate
 }
@@ -53,17 +54,70 @@ func ValidateStruct(data interface{}) error {
 		return errors.WrapInternal(err)
 	}
 
+	root := reflect.TypeOf(data)
 	details := make([]errors.ValidationError, 0, len(validationErrors))
 	for _, e := range validationErrors {
+		message, args := getErrorMessage(e)
 		details = append(details, errors.ValidationError{
-			Field:   e.Field(),
-			Message: getErrorMessage(e),
+			Field:   fieldPath(root, e),
+			Message: message,
+			Args:    args,
 		})
 	}
 
 	return errors.NewValidationError(details)
 }
 
+// fieldPath returns the JSON path of the field that failed, e.g. items[2].quantity, so
+// errors inside slices and nested structs point at the element. Embedded structs are
+// left out as their fields are flattened in JSON
+func fieldPath(root reflect.Type, e validator.FieldError) string {
+	segments := strings.Split(e.StructNamespace(), ".")
+	t := root
+	path := make([]string, 0, len(segments))
+	for _, segment := range segments[1:] {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return e.Field()
+		}
+		name, index, _ := strings.Cut(segment, "[")
+		field, ok := t.FieldByName(name)
+		if !ok {
+			return e.Field()
+		}
+
+		t = field.Type
+		if index != "" {
+			// one level of [i] or [key] per slice, array or map
+			for range strings.Count(index, "[") + 1 {
+				for t.Kind() == reflect.Pointer {
+					t = t.Elem()
+				}
+				if t.Kind() != reflect.Slice && t.Kind() != reflect.Array && t.Kind() != reflect.Map {
+					return e.Field()
+				}
+				t = t.Elem()
+			}
+			index = "[" + index
+		}
+		if field.Anonymous && index == "" {
+			continue
+		}
+
+		jsonName := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if jsonName == "" || jsonName == "-" {
+			jsonName = field.Name
+		}
+		path = append(path, jsonName+index)
+	}
+	if len(path) == 0 {
+		return e.Field()
+	}
+	return strings.Join(path, ".")
+}
+
 // validatePassword validates password strength
 func validatePassword(fl validator.FieldLevel) bool {
 	password := fl.Field().String()
@@ -102,42 +156,65 @@ func validateUsername(fl validator.FieldLevel) bool {
 	return usernameRegex.MatchString(username)
 }
 
+func validateSlug(fl validator.FieldLevel) bool {
+	slugRegex := regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	return slugRegex.MatchString(fl.Field().String())
+}
+
 func validateCustomEmail(fl validator.FieldLevel) bool {
 	email := fl.Field().String()
 	return IsValidEmail(email)
 }
 
-// getErrorMessage returns a user-friendly error message in Indonesian
-func getErrorMessage(e validator.FieldError) string {
+// getErrorMessage returns a user-friendly error message in Indonesian, the default
+// language of i18n, as a format and the args filling it in
+func getErrorMessage(e validator.FieldError) (string, []any) {
 	field := e.Field()
 
 	switch e.Tag() {
 	case "required":
-		return field + " wajib diisi"
+		return "%s wajib diisi", []any{field}
 	case "email", "customEmail":
-		return "Format email tidak valid"
+		return "Format email tidak valid", nil
 	case "min":
-		return field + " minimal " + e.Param() + " karakter"
+		return "%s minimal %s" + unit(e), []any{field, e.Param()}
 	case "max":
-		return field + " maksimal " + e.Param() + " karakter"
+		return "%s maksimal %s" + unit(e), []any{field, e.Param()}
 	case "gt":
-		return field + " harus lebih besar dari " + e.Param()
+		return "%s harus lebih besar dari %s", []any{field, e.Param()}
 	case "gte":
-		return field + " harus lebih besar atau sama dengan " + e.Param()
+		return "%s harus lebih besar atau sama dengan %s", []any{field, e.Param()}
 	case "lt":
-		return field + " harus lebih kecil dari " + e.Param()
+		return "%s harus lebih kecil dari %s", []any{field, e.Param()}
 	case "lte":
-		return field + " harus lebih kecil atau sama dengan " + e.Param()
+		return "%s harus lebih kecil atau sama dengan %s", []any{field, e.Param()}
 	case "oneof":
-		return field + " harus salah satu dari: " + e.Param()
+		return "%s harus salah satu dari: %s", []any{field, e.Param()}
 	case "password", "strongPassword":
-		return "Password harus minimal 8 karakter dengan huruf besar, huruf kecil, dan angka"
+		return "Password harus minimal 8 karakter dengan huruf besar, huruf kecil, dan angka", nil
 	case "username":
-		return "Username hanya boleh berisi huruf, angka, dan underscore"
+		return "Username hanya boleh berisi huruf, angka, dan underscore", nil
+	case "slug":
+		return "%s hanya boleh berisi huruf kecil, angka, dan tanda hubung", []any{field}
+	case "bcp47_language_tag":
+		return "%s harus berupa kode bahasa BCP 47, misalnya id atau en-US", []any{field}
 	case "uuid":
-		return field + " harus berformat UUID yang valid"
+		return "%s harus berformat UUID yang valid", []any{field}
+	default:
+		return "%s tidak valid", []any{field}
+	}
+}
+
+// unit names what min and max count for the kind of field, it's part of the format so
+// catalogs translate it with the rest of the message
+func unit(e validator.FieldError) string {
+	switch e.Kind() {
+	case reflect.String:
+		return " karakter"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " item"
 	default:
-		return field + " tidak valid"
+		return ""
 	}
 }
 